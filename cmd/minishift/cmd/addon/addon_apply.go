@@ -20,11 +20,14 @@ import (
 	"fmt"
 
 	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/provision"
 	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
 	"github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/addon"
+	"github.com/minishift/minishift/pkg/minishift/addon/manager"
 	"github.com/minishift/minishift/pkg/minishift/clusterup"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/minishift/oc"
@@ -34,16 +37,20 @@ import (
 )
 
 var (
+	setVars []string
+
 	addonsApplyCmd = &cobra.Command{
 		Use:   "apply ADDON_NAME ...",
 		Short: "Applies the specified add-ons.",
-		Long:  "Applies the specified add-ons. You can specify one or more add-ons, regardless of whether the add-on is enabled or disabled.",
-		Run:   runApplyAddon,
+		Long: `Applies the specified add-ons. You can specify one or more add-ons, regardless of whether the add-on is enabled or disabled.
+Variables specified with --set take precedence over the add-on environment, the profile configuration and the environment.`,
+		Run: runApplyAddon,
 	}
 )
 
 func init() {
 	addonsApplyCmd.Flags().AddFlag(util.AddOnEnvFlag)
+	addonsApplyCmd.Flags().StringSliceVar(&setVars, "set", []string{}, "Specify key=value pairs overriding add-on interpolation variables for this run.")
 	AddonsCmd.AddCommand(addonsApplyCmd)
 }
 
@@ -72,27 +79,36 @@ func runApplyAddon(cmd *cobra.Command, args []string) {
 
 	util.ExitIfNotRunning(host.Driver, constants.MachineName)
 
-	ip, err := host.Driver.GetIP()
+	var addOns []addon.AddOn
+	for _, addOnName := range args {
+		addOns = append(addOns, addOnManager.Get(addOnName))
+	}
+	ApplyAddOns(addOnManager, host.Driver, addOns, setVars)
+}
+
+// ApplyAddOns applies the add-ons to the running VM. The key=value pairs of setVars override the add-on interpolation
+// variables.
+func ApplyAddOns(addOnManager *manager.AddOnManager, driver drivers.Driver, addOns []addon.AddOn, setVars []string) {
+	ip, err := driver.GetIP()
 	if err != nil {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting the IP address: %s", err.Error()))
 	}
 
-	routingSuffix := determineRoutingSuffix(host.Driver)
-	sshCommander := provision.GenericSSHCommander{Driver: host.Driver}
+	routingSuffix := determineRoutingSuffix(driver)
+	sshCommander := provision.GenericSSHCommander{Driver: driver}
 	sshUser := sshCommander.Driver.GetSSHUsername()
 	ocRunner, err := oc.NewOcRunner(minishiftConfig.InstanceStateConfig.OcPath, constants.KubeConfigPath)
 	if err != nil {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Error applying the add-on: %s", err.Error()))
 	}
 
-	for i := range args {
-		addonName := args[i]
-		addon := addOnManager.Get(addonName)
-		addonContext, err := clusterup.GetExecutionContext(ip, routingSuffix, sshUser, viper.GetStringSlice(configCmd.AddonEnv.Name), ocRunner, sshCommander)
+	for _, addOn := range addOns {
+		addOnEnv := append(viper.GetStringSlice(configCmd.AddonEnv.Name), setVars...)
+		addonContext, err := clusterup.GetExecutionContext(ip, routingSuffix, sshUser, addOnEnv, ocRunner, sshCommander)
 		if err != nil {
 			atexit.ExitWithMessage(1, fmt.Sprint("Error applying the add-on: ", err))
 		}
-		err = addOnManager.ApplyAddOn(addon, addonContext)
+		err = addOnManager.ApplyAddOn(addOn, addonContext)
 		if err != nil {
 			atexit.ExitWithMessage(1, fmt.Sprint("Error applying the add-on: ", err))
 		}
@@ -19,6 +19,7 @@ package addon
 import (
 	"fmt"
 
+	"github.com/minishift/minishift/pkg/minishift/addon/manager"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
@@ -57,6 +58,10 @@ func runDisableAddon(cmd *cobra.Command, args []string) {
 		atexit.ExitWithMessage(0, fmt.Sprintf(addOnAlreadyDisabledMessage, addOnName))
 	}
 
+	disableAddon(addOnManager, addOnName)
+}
+
+func disableAddon(addOnManager *manager.AddOnManager, addOnName string) {
 	addOnConfig, err := addOnManager.Disable(addOnName)
 	if err != nil {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Unable to disable the add-on '%s': %s", addOnName, err.Error()))
@@ -71,3 +71,41 @@ func enableAddon(addOnManager *manager.AddOnManager, addOnName string, priority
 		atexit.ExitWithMessage(1, fmt.Sprintf("Error writing addon config data: %v", err))
 	}
 }
+
+// ApplyPresetAddOns enables the add-ons of a preset which are not enabled yet and disables the add-ons enabled by the
+// previously applied preset which are not part of the preset. Add-ons enabled by the user are kept. The add-ons
+// enabled because of the preset are recorded in the instance config.
+func ApplyPresetAddOns(addOnNames []string) {
+	addOnManager := GetAddOnManager()
+	for _, addOnName := range addOnNames {
+		if !addOnManager.IsInstalled(addOnName) {
+			atexit.ExitWithMessage(1, fmt.Sprintf(noAddOnMessage, addOnName))
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, addOnName := range addOnNames {
+		wanted[addOnName] = true
+	}
+	var presetAddOns []string
+	for _, addOnName := range minishiftConfig.InstanceConfig.PresetAddOns {
+		switch {
+		case wanted[addOnName]:
+			presetAddOns = append(presetAddOns, addOnName)
+		case addOnManager.IsInstalled(addOnName) && addOnManager.Get(addOnName).IsEnabled():
+			disableAddon(addOnManager, addOnName)
+		}
+	}
+	for _, addOnName := range addOnNames {
+		if addOnManager.Get(addOnName).IsEnabled() {
+			continue
+		}
+		enableAddon(addOnManager, addOnName, 0)
+		presetAddOns = append(presetAddOns, addOnName)
+	}
+
+	minishiftConfig.InstanceConfig.PresetAddOns = presetAddOns
+	if err := minishiftConfig.InstanceConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error writing addon config data: %v", err))
+	}
+}
@@ -22,6 +22,10 @@ import (
 	"strings"
 
 	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minishift/addon/repository"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/filehelper"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
 )
@@ -44,8 +48,10 @@ var (
 var addonsInstallCmd = &cobra.Command{
 	Use:   "install [SOURCE]",
 	Short: "Installs the specified add-on.",
-	Long:  "Installs the add-on from the specified file path and verifies the installation.",
-	Run:   runInstallAddon,
+	Long: `Installs the add-on from the specified file path and verifies the installation.
+If SOURCE is not a directory, it is treated as add-on name and resolved from the configured add-on repositories.
+Use 'NAME@REF' to install the add-on from a specific branch, tag or commit of git repositories.`,
+	Run: runInstallAddon,
 }
 
 func init() {
@@ -70,6 +76,9 @@ func runInstallAddon(cmd *cobra.Command, args []string) {
 	}
 
 	source := args[0]
+	if !filehelper.IsDirectory(source) {
+		source = resolveFromRepos(source)
+	}
 
 	addOnName, err := addOnManager.Install(source, force)
 	if err != nil {
@@ -83,3 +92,13 @@ func runInstallAddon(cmd *cobra.Command, args []string) {
 		enableAddon(addOnManager, addOnName, 0)
 	}
 }
+
+// resolveFromRepos returns the directory of the add-on referenced as 'name[@ref]' within the configured add-on repositories.
+func resolveFromRepos(reference string) string {
+	name, ref := repository.SplitNameAndRef(reference)
+	dir, err := repository.Resolve(minishiftConfig.AllInstancesConfig.AddonRepos, state.InstanceDirs.AddonRepos, name, ref)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf(failedPluginInstallation, err.Error()))
+	}
+	return dir
+}
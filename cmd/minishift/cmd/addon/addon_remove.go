@@ -37,13 +37,14 @@ var (
 	addonsRemoveCmd = &cobra.Command{
 		Use:   "remove ADDON_NAME ...",
 		Short: "Removes the specified add-ons.",
-		Long:  "Removes the specified add-ons. You can specify one or more add-ons, regardless of whether the add-on is enabled or disabled.",
+		Long:  "Removes the specified add-ons by running the Remove section of the add-on or its addon.remove file. You can specify one or more add-ons, regardless of whether the add-on is enabled or disabled.",
 		Run:   runRemoveAddon,
 	}
 )
 
 func init() {
 	addonsRemoveCmd.Flags().AddFlag(util.AddOnEnvFlag)
+	addonsRemoveCmd.Flags().StringSliceVar(&setVars, "set", []string{}, "Specify key=value pairs overriding add-on interpolation variables for this run.")
 	AddonsCmd.AddCommand(addonsRemoveCmd)
 }
 
@@ -91,7 +92,8 @@ func runRemoveAddon(cmd *cobra.Command, args []string) {
 	for i := range args {
 		addonName := args[i]
 		addon := addOnManager.Get(addonName)
-		addonContext, err := clusterup.GetExecutionContext(ip, routingSuffix, sshUser, viper.GetStringSlice(configCmd.AddonEnv.Name), ocRunner, sshCommander)
+		addOnEnv := append(viper.GetStringSlice(configCmd.AddonEnv.Name), setVars...)
+		addonContext, err := clusterup.GetExecutionContext(ip, routingSuffix, sshUser, addOnEnv, ocRunner, sshCommander)
 		if err != nil {
 			atexit.ExitWithMessage(1, fmt.Sprint("Error removing the add-on: ", err))
 		}
@@ -0,0 +1,40 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	noAddonReposMessage = "No add-on repositories are configured."
+	unknownAddonRepo    = "There is no add-on repository named '%s'."
+)
+
+var addonsRepoCmd = &cobra.Command{
+	Use:   "repo SUBCOMMAND [flags]",
+	Short: "Manages the repositories add-ons can be installed from.",
+	Long: `Manages the repositories add-ons can be installed from. Repositories are git repositories or
+.tar.gz, .tgz or .zip archives served via HTTP(S). They are shared across all profiles.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+func init() {
+	AddonsCmd.AddCommand(addonsRepoCmd)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+
+	"github.com/minishift/minishift/pkg/minishift/addon/repository"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+const (
+	repoNameFlag = "name"
+	repoRefFlag  = "ref"
+)
+
+var (
+	repoName string
+	repoRef  string
+)
+
+var addonsRepoAddCmd = &cobra.Command{
+	Use:   "add URL",
+	Short: "Adds an add-on repository.",
+	Long:  "Adds the add-on repository with the specified URL. Use the ref flag to pin a git repository to a branch, tag or commit.",
+	Run:   runAddonsRepoAdd,
+}
+
+func init() {
+	addonsRepoAddCmd.Flags().StringVar(&repoName, repoNameFlag, "", "The name of the repository. Defaults to the last element of the URL.")
+	addonsRepoAddCmd.Flags().StringVar(&repoRef, repoRefFlag, "", "The branch, tag or commit of the git repository to use.")
+	addonsRepoCmd.AddCommand(addonsRepoAddCmd)
+}
+
+func runAddonsRepoAdd(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You must specify the URL of the add-on repository.")
+	}
+
+	repo, err := repository.NewRepository(args[0], repoName, repoRef)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	if findAddonRepo(repo.Name) >= 0 {
+		atexit.ExitWithMessage(1, fmt.Sprintf("An add-on repository named '%s' already exists.", repo.Name))
+	}
+
+	minishiftConfig.AllInstancesConfig.AddonRepos = append(minishiftConfig.AllInstancesConfig.AddonRepos, *repo)
+	if err := minishiftConfig.AllInstancesConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error storing add-on repository '%s': %v", repo.Name, err))
+	}
+	fmt.Println(fmt.Sprintf("Add-on repository '%s' added", repo.Name))
+}
+
+// findAddonRepo returns the index of the add-on repository with the specified name or -1 if there is none.
+func findAddonRepo(name string) int {
+	for i, repo := range minishiftConfig.AllInstancesConfig.AddonRepos {
+		if repo.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,52 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/spf13/cobra"
+)
+
+var addonsRepoListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the configured add-on repositories.",
+	Long:  "Lists the configured add-on repositories in the order they are searched by 'minishift addons install'.",
+	Run:   runAddonsRepoList,
+}
+
+func init() {
+	addonsRepoCmd.AddCommand(addonsRepoListCmd)
+}
+
+func runAddonsRepoList(cmd *cobra.Command, args []string) {
+	repos := minishiftConfig.AllInstancesConfig.AddonRepos
+	if len(repos) == 0 {
+		fmt.Println(noAddonReposMessage)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tURL\tREF")
+	for _, repo := range repos {
+		fmt.Fprintln(w, fmt.Sprintf("%s\t%s\t%s", repo.Name, repo.URL, repo.Ref))
+	}
+	w.Flush()
+}
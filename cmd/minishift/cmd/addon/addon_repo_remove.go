@@ -0,0 +1,59 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minishift/minishift/cmd/minishift/state"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var addonsRepoRemoveCmd = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Removes an add-on repository.",
+	Long:  "Removes the specified add-on repository and its cached content. Add-ons already installed from the repository are kept.",
+	Run:   runAddonsRepoRemove,
+}
+
+func init() {
+	addonsRepoCmd.AddCommand(addonsRepoRemoveCmd)
+}
+
+func runAddonsRepoRemove(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You must specify the name of the add-on repository to remove.")
+	}
+
+	name := args[0]
+	i := findAddonRepo(name)
+	if i < 0 {
+		atexit.ExitWithMessage(1, fmt.Sprintf(unknownAddonRepo, name))
+	}
+
+	repos := minishiftConfig.AllInstancesConfig.AddonRepos
+	minishiftConfig.AllInstancesConfig.AddonRepos = append(repos[:i], repos[i+1:]...)
+	if err := minishiftConfig.AllInstancesConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error removing add-on repository '%s': %v", name, err))
+	}
+	os.RemoveAll(filepath.Join(state.InstanceDirs.AddonRepos, name))
+	fmt.Println(fmt.Sprintf("Add-on repository '%s' removed", name))
+}
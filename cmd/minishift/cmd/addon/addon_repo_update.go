@@ -0,0 +1,61 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minishift/addon/repository"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var addonsRepoUpdateCmd = &cobra.Command{
+	Use:   "update [NAME]",
+	Short: "Refreshes the cached content of add-on repositories.",
+	Long:  "Refreshes the cached content of all or only the specified add-on repository at the ref the repository is pinned to.",
+	Run:   runAddonsRepoUpdate,
+}
+
+func init() {
+	addonsRepoCmd.AddCommand(addonsRepoUpdateCmd)
+}
+
+func runAddonsRepoUpdate(cmd *cobra.Command, args []string) {
+	repos := minishiftConfig.AllInstancesConfig.AddonRepos
+	if len(args) == 1 {
+		i := findAddonRepo(args[0])
+		if i < 0 {
+			atexit.ExitWithMessage(1, fmt.Sprintf(unknownAddonRepo, args[0]))
+		}
+		repos = []repository.Repository{repos[i]}
+	}
+
+	if len(repos) == 0 {
+		fmt.Println(noAddonReposMessage)
+		return
+	}
+
+	for _, repo := range repos {
+		if _, err := repo.Fetch(state.InstanceDirs.AddonRepos, "", true); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+		fmt.Println(fmt.Sprintf("Add-on repository '%s' updated", repo.Name))
+	}
+}
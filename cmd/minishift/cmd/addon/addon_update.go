@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/provision"
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/clusterup"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	dryRunFlag = "dry-run"
+)
+
+var (
+	dryRun bool
+
+	addonsUpdateCmd = &cobra.Command{
+		Use:   "update [ADDON_NAME ...]",
+		Short: "Re-applies add-ons which changed since they were applied.",
+		Long: `Re-applies the add-ons whose content changed since they were last applied to the instance and shows what changed.
+If no add-on names are specified, all applied add-ons are checked.`,
+		Run: runUpdateAddon,
+	}
+)
+
+func init() {
+	addonsUpdateCmd.Flags().AddFlag(util.AddOnEnvFlag)
+	addonsUpdateCmd.Flags().StringSliceVar(&setVars, "set", []string{}, "Specify key=value pairs overriding add-on interpolation variables for this run.")
+	addonsUpdateCmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "Only show which add-ons changed and how, without re-applying them.")
+	AddonsCmd.AddCommand(addonsUpdateCmd)
+}
+
+func runUpdateAddon(cmd *cobra.Command, args []string) {
+	addOnManager := GetAddOnManager()
+	for _, addonName := range args {
+		if !addOnManager.IsInstalled(addonName) {
+			atexit.ExitWithMessage(0, fmt.Sprintf(noAddOnMessage, addonName))
+		}
+	}
+
+	changes, err := addOnManager.ChangedAddOns(args...)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprint("Error checking the add-ons for changes: ", err))
+	}
+	if len(changes) == 0 {
+		fmt.Println("All applied add-ons are up to date.")
+		return
+	}
+
+	for _, change := range changes {
+		fmt.Println(fmt.Sprintf("-- Add-on '%s' changed:", change.AddOn.MetaData().Name()))
+		fmt.Print(change.Diff())
+		fmt.Println()
+	}
+
+	if dryRun {
+		return
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	util.ExitIfUndefined(api, constants.MachineName)
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	util.ExitIfNotRunning(host.Driver, constants.MachineName)
+
+	ip, err := host.Driver.GetIP()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting the IP address: %s", err.Error()))
+	}
+
+	routingSuffix := determineRoutingSuffix(host.Driver)
+	sshCommander := provision.GenericSSHCommander{Driver: host.Driver}
+	sshUser := sshCommander.Driver.GetSSHUsername()
+	ocRunner, err := oc.NewOcRunner(minishiftConfig.InstanceStateConfig.OcPath, constants.KubeConfigPath)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error updating the add-on: %s", err.Error()))
+	}
+
+	for _, change := range changes {
+		addOnEnv := append(viper.GetStringSlice(configCmd.AddonEnv.Name), setVars...)
+		addonContext, err := clusterup.GetExecutionContext(ip, routingSuffix, sshUser, addOnEnv, ocRunner, sshCommander)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprint("Error updating the add-on: ", err))
+		}
+		err = addOnManager.ApplyAddOn(change.AddOn, addonContext)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprint("Error updating the add-on: ", err))
+		}
+	}
+}
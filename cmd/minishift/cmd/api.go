@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	localAPI "github.com/minishift/minishift/pkg/minishift/api"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	apiAddress string
+
+	// apiCmd represents the api command
+	apiCmd = &cobra.Command{
+		Use:   "api",
+		Short: "Serves the Minishift API on a local socket.",
+		Long: `Serves the Minishift API on a unix socket, or a named pipe on Windows, so that tools such as IDE plugins can
+start and stop profiles and query their status, console URL and Docker environment without parsing the output of
+the minishift commands. The API is only accessible by the current user. The command runs until interrupted.`,
+		Run: runAPI,
+	}
+)
+
+func init() {
+	apiCmd.Flags().StringVar(&apiAddress, "address", localAPI.DefaultAddress(), "The unix socket or named pipe to serve the API on.")
+	RootCmd.AddCommand(apiCmd)
+}
+
+func runAPI(cmd *cobra.Command, args []string) {
+	listener, err := localAPI.Listen(apiAddress)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error listening on '%s': %v", apiAddress, err))
+	}
+
+	// closing the listener removes the unix socket
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+
+	fmt.Println(fmt.Sprintf("Serving the Minishift API %s on %s. Press Ctrl-C to stop.", localAPI.Version, apiAddress))
+	localAPI.NewServer(newAPIBackend()).Serve(listener)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/state"
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	cmdState "github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	localAPI "github.com/minishift/minishift/pkg/minishift/api"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	openshiftVersion "github.com/minishift/minishift/pkg/minishift/openshift/version"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	minishiftOs "github.com/minishift/minishift/pkg/util/os"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+)
+
+// apiBackend performs the operations of the Minishift API. Other than the commands, the API serves all profiles,
+// hence the backend does not rely on the global state of the current profile.
+type apiBackend struct {
+	// start and stop run one at a time, like they would from a single terminal
+	operationLock sync.Mutex
+}
+
+func newAPIBackend() *apiBackend {
+	return &apiBackend{}
+}
+
+func (b *apiBackend) Profiles() ([]localAPI.Profile, error) {
+	allInstancesConfig, err := minishiftConfig.NewAllInstancesConfig(constants.AllInstanceConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []localAPI.Profile{}
+	for _, name := range profileActions.GetProfileList() {
+		profiles = append(profiles, cmdUtil.GetProfileSummary(name, name == allInstancesConfig.ActiveProfile))
+	}
+	return profiles, nil
+}
+
+func (b *apiBackend) Status(profile string) (*localAPI.Status, error) {
+	api, err := b.machineAPI(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer api.Close()
+
+	hostVm, vmStatus, err := loadHost(api, profile)
+	if err != nil {
+		return nil, err
+	}
+	status := &localAPI.Status{Profile: profile, VMStatus: vmStatus, OpenShiftStatus: "Stopped"}
+	if vmStatus != state.Running.String() {
+		return status, nil
+	}
+
+	if status.IP, err = hostVm.Driver.GetIP(); err != nil {
+		return nil, err
+	}
+	version, err := openshiftVersion.GetOpenshiftVersion(provision.GenericSSHCommander{Driver: hostVm.Driver})
+	if err == nil {
+		status.OpenShiftStatus = "Running"
+		status.OpenShiftVersion = strings.TrimSpace(strings.Split(version, "\n")[0])
+	}
+	return status, nil
+}
+
+func (b *apiBackend) Console(profile string) (*localAPI.Console, error) {
+	api, err := b.machineAPI(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer api.Close()
+
+	hostVm, err := runningHost(api, profile)
+	if err != nil {
+		return nil, err
+	}
+	ip, err := hostVm.Driver.GetIP()
+	if err != nil {
+		return nil, err
+	}
+	return &localAPI.Console{Profile: profile, ConsoleURL: fmt.Sprintf("https://%s:%d/console", publicHostname(profile, ip), constants.APIServerPort)}, nil
+}
+
+func (b *apiBackend) DockerEnv(profile string) (*localAPI.DockerEnv, error) {
+	api, err := b.machineAPI(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer api.Close()
+
+	hostVm, err := runningHost(api, profile)
+	if err != nil {
+		return nil, err
+	}
+	ip, err := hostVm.Driver.GetIP()
+	if err != nil {
+		return nil, err
+	}
+	dockerAPIVersion, err := hostVm.RunSSHCommand("docker version --format '{{.Server.APIVersion}}'")
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{
+		"DOCKER_TLS_VERIFY":  "1",
+		"DOCKER_HOST":        fmt.Sprintf("tcp://%s:2376", ip),
+		"DOCKER_CERT_PATH":   profileDirs(profile).Certs,
+		"DOCKER_API_VERSION": strings.TrimSpace(dockerAPIVersion),
+	}
+	return &localAPI.DockerEnv{Profile: profile, Env: env}, nil
+}
+
+// Describe runs 'minishift describe', since the description depends on the configuration of the profile, which the
+// commands load for the current profile only.
+func (b *apiBackend) Describe(profile string) (*localAPI.Description, error) {
+	if !profileExists(profile) {
+		return nil, localAPI.ErrProfileNotFound
+	}
+
+	executable, err := minishiftOs.CurrentExecutable()
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	describeCmd := exec.Command(executable, "describe", "--output", jsonOutput, "--profile", profile)
+	describeCmd.Stderr = &stderr
+	out, err := describeCmd.Output()
+	if err != nil {
+		// with JSON output the error is reported on stdout
+		report := atexit.ErrorReport{}
+		if json.Unmarshal(out, &report) == nil && report.Message != "" {
+			return nil, &localAPI.OperationError{
+				Message: fmt.Sprintf("Error describing profile '%s': %s", profile, report.Message),
+				Reason:  report.Reason,
+			}
+		}
+		return nil, fmt.Errorf("Error describing profile '%s': %v %s", profile, err, strings.TrimSpace(stderr.String()))
+	}
+
+	description := &localAPI.Description{}
+	if err := json.Unmarshal(out, description); err != nil {
+		return nil, err
+	}
+	return description, nil
+}
+
+func (b *apiBackend) Start(profile string) (*localAPI.Operation, error) {
+	return b.runOperation(profile, "start")
+}
+
+func (b *apiBackend) Stop(profile string) (*localAPI.Operation, error) {
+	return b.runOperation(profile, "stop")
+}
+
+// runOperation runs the command of the operation for the profile, so that the operation behaves exactly as if it was
+// run from the command line, including the persisted configuration of the profile.
+func (b *apiBackend) runOperation(profile string, operation string) (*localAPI.Operation, error) {
+	if !profileExists(profile) {
+		return nil, localAPI.ErrProfileNotFound
+	}
+
+	b.operationLock.Lock()
+	defer b.operationLock.Unlock()
+
+	executable, err := minishiftOs.CurrentExecutable()
+	if err != nil {
+		return nil, err
+	}
+	out, err := exec.Command(executable, operation, "--profile", profile).CombinedOutput()
+	if err != nil {
+		reason := atexit.GenericError
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			reason = atexit.ReasonForExitCode(exitErr.Sys().(syscall.WaitStatus).ExitStatus())
+		}
+		return nil, &localAPI.OperationError{
+			Message: fmt.Sprintf("Error running '%s' for profile '%s': %v %s", operation, profile, err, strings.TrimSpace(string(out))),
+			Reason:  reason,
+		}
+	}
+	return &localAPI.Operation{Profile: profile, Operation: operation, Output: string(out)}, nil
+}
+
+func (b *apiBackend) machineAPI(profile string) (libmachine.API, error) {
+	if !profileExists(profile) {
+		return nil, localAPI.ErrProfileNotFound
+	}
+	dirs := profileDirs(profile)
+	return libmachine.NewClient(dirs.Home, dirs.Certs), nil
+}
+
+func runningHost(api libmachine.API, profile string) (*host.Host, error) {
+	hostVm, vmStatus, err := loadHost(api, profile)
+	if err != nil {
+		return nil, err
+	}
+	if vmStatus != state.Running.String() {
+		return nil, localAPI.ErrVMNotRunning
+	}
+	return hostVm, nil
+}
+
+// loadHost loads the host of the profile and returns it together with the status of its VM. The host is loaded only
+// once, since every load of a host starts a driver plugin.
+func loadHost(api libmachine.API, profile string) (*host.Host, string, error) {
+	exists, err := api.Exists(profile)
+	if err != nil {
+		return nil, "", err
+	}
+	if !exists {
+		return nil, "Does Not Exist", nil
+	}
+
+	hostVm, err := api.Load(profile)
+	if err != nil {
+		return nil, "", err
+	}
+	vmState, err := hostVm.Driver.GetState()
+	if err != nil {
+		return nil, "", err
+	}
+	if vmState.String() == "" {
+		return nil, "Does Not Exist", nil
+	}
+	return hostVm, vmState.String(), nil
+}
+
+func profileDirs(profile string) *cmdState.MinishiftDirs {
+	return cmdState.GetMinishiftDirsStructure(constants.GetProfileHomeDir(profile))
+}
+
+// publicHostname returns the public hostname configured for the profile, or the IP if none is configured. Like for
+// the commands, the configuration of the profile takes precedence over the global configuration.
+func publicHostname(profile string, ip string) string {
+	for _, configFile := range []string{constants.GetProfileConfigFile(profile), constants.GlobalConfigFile} {
+		config, err := minishiftConfig.ReadViperConfig(configFile)
+		if err != nil {
+			continue
+		}
+		if hostname, ok := config[configCmd.PublicHostname.Name].(string); ok && hostname != "" {
+			return hostname
+		}
+	}
+	return ip
+}
+
+func profileExists(profile string) bool {
+	for _, name := range profileActions.GetProfileList() {
+		if name == profile {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/minishift/minishift/cmd/minishift/cmd/addon"
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	minishiftNetwork "github.com/minishift/minishift/pkg/minishift/network"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+const (
+	openShiftHealthAttempts = 60
+	openShiftHealthInterval = 5 * time.Second
+)
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Applies configuration changes to the running instance.",
+	Long: `Reconciles the running instance with the current configuration, without stopping and starting it. The command
+applies the enabled add-ons which were not applied yet or changed since, mounts the host folders if
+hostfolders-automount is enabled, adds the configured nameservers and renders the configuration of the Docker daemon,
+like its environment, the registry mirrors and the insecure registries. Updating the Docker daemon restarts it, which
+stops the OpenShift containers. A running OpenShift cluster is therefore started again, and the command waits until
+its API server is healthy.
+
+Settings which require the VM to be recreated, like the memory, the disk size or the VM driver, are not applied.`,
+	Run: runApply,
+}
+
+func runApply(cmd *cobra.Command, args []string) {
+	cmdUtil.LockProfilesOrExit(constants.ProfileName)
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	cmdUtil.ExitIfUndefined(api, constants.MachineName)
+	hostVm, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	cmdUtil.ExitIfNotRunning(hostVm.Driver, constants.MachineName)
+
+	applyNameservers(hostVm)
+	applyDockerDaemonConfig(hostVm)
+	autoMountHostFolders(hostVm.Driver)
+	applyPendingAddOns(hostVm)
+}
+
+// applyNameservers adds the configured nameservers which are missing in the VM
+func applyNameservers(hostVm *host.Host) {
+	nameservers := getSlice(configCmd.NameServers.Name)
+	if len(nameservers) == 0 {
+		return
+	}
+	fmt.Println("-- Adding nameservers")
+	minishiftNetwork.AddNameserversToInstance(hostVm.Driver, nameservers)
+}
+
+// applyDockerDaemonConfig renders the systemd drop-in of the Docker daemon from the configuration. The Docker daemon
+// is restarted if the drop-in changed.
+func applyDockerDaemonConfig(hostVm *host.Host) {
+	handleProxyConfig()
+	handleRegistryCache()
+
+	// restarting the Docker daemon stops all containers, including the ones of the OpenShift cluster
+	openShiftRunning := IsOpenShiftRunning(hostVm.Driver)
+
+	config := dockerDaemonConfig()
+	restarted, err := docker.ApplyDropIn(hostVm.Driver, &config)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error updating the Docker daemon configuration: %v", err))
+	}
+	if !restarted {
+		return
+	}
+	fmt.Println("-- Updated the Docker daemon configuration and restarted the Docker daemon")
+
+	if openShiftRunning {
+		restartOpenShiftAfterDockerRestart(hostVm)
+	}
+}
+
+// restartOpenShiftAfterDockerRestart starts the origin container again, which brings up the API server and the
+// cluster containers, and waits until the API server is healthy.
+func restartOpenShiftAfterDockerRestart(hostVm *host.Host) {
+	fmt.Println("-- Restarting OpenShift")
+	dockerCommander := docker.NewVmDockerCommander(provision.GenericSSHCommander{Driver: hostVm.Driver})
+	if _, err := dockerCommander.Restart(minishiftConstants.OpenshiftContainerName); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error restarting OpenShift after restarting the Docker daemon: %v. Run 'minishift stop' and 'minishift start' to recover.", err))
+	}
+	if err := openshift.WaitForAPIServer(dockerCommander, openShiftHealthAttempts, openShiftHealthInterval); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("OpenShift did not become healthy after restarting the Docker daemon: %v. Run 'minishift stop' and 'minishift start' to recover.", err))
+	}
+}
+
+// applyPendingAddOns applies the enabled add-ons which were not applied yet or whose content changed since. Add-ons
+// need a running OpenShift cluster, which is not the case if the instance was started with --no-provision.
+func applyPendingAddOns(hostVm *host.Host) {
+	if !IsOpenShiftRunning(hostVm.Driver) {
+		return
+	}
+
+	addOnManager := addon.GetAddOnManager()
+	pending, err := addOnManager.PendingAddOns()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error determining the add-ons to apply: %v", err))
+	}
+	if len(pending) == 0 {
+		return
+	}
+	addon.ApplyAddOns(addOnManager, hostVm.Driver, pending, nil)
+}
+
+func init() {
+	RootCmd.AddCommand(applyCmd)
+}
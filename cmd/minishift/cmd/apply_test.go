@@ -0,0 +1,41 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerDaemonConfig(t *testing.T) {
+	defer viper.Reset()
+	viper.Set(configCmd.DockerEnv.Name, []string{"FOO=bar"})
+	viper.Set(configCmd.InsecureRegistry.Name, []string{"hub.foo.com"})
+	viper.Set(configCmd.RegistryMirror.Name, []string{"https://mirror.foo.com"})
+	viper.Set(configCmd.DockerStorageOpt.Name, []string{"dm.basesize=20G"})
+	viper.Set(configCmd.DockerEngineOpt.Name, []string{"log-level=debug"})
+
+	config := dockerDaemonConfig()
+	assert.Equal(t, []string{"FOO=bar"}, config.Env)
+	assert.Equal(t, []string{"hub.foo.com", defaultInsecureRegistry}, config.InsecureRegistries)
+	assert.Equal(t, []string{"https://mirror.foo.com"}, config.RegistryMirrors)
+	assert.Equal(t, []string{"dm.basesize=20G"}, config.StorageOptions)
+	assert.Equal(t, []string{"log-level=debug"}, config.Options)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minishift/backup"
+	"github.com/minishift/minishift/pkg/minishift/filetransfer"
+	"github.com/spf13/cobra"
+)
+
+var BackupCmd = &cobra.Command{
+	Use:   "backup SUBCOMMAND [flags]",
+	Short: "Creates and restores backups of the cluster data.",
+	Long: `Creates and restores backups of the cluster data, namely the etcd data and the directories of the hostPath
+persistent volumes. Backups are archives on the host, which allow to recover the cluster after the VM got corrupted or
+to move the data to a fresh instance.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// CreateBackup archives the cluster data of the running VM and copies the archive to target on the host.
+func CreateBackup(api libmachine.API, hostVm *host.Host, target string, manifest backup.Manifest) error {
+	archiveInVM := backup.ArchivePathInsideInstance(filepath.Base(target))
+	sshCommander := provision.GenericSSHCommander{Driver: hostVm.Driver}
+	if err := backup.Create(sshCommander, archiveInVM, manifest); err != nil {
+		return err
+	}
+	defer sshCommander.SSHCommand(fmt.Sprintf("sudo rm -f %s", archiveInVM))
+
+	client, err := cluster.NewSSHClient(api)
+	if err != nil {
+		return fmt.Errorf("Cannot establish SSH connection to the VM: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := filetransfer.New(client, filetransfer.Options{}).FromVM(archiveInVM, target); err != nil {
+		return fmt.Errorf("Error copying the backup from the VM: %v", err)
+	}
+	return nil
+}
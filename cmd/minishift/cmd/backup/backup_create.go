@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/backup"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/minishift/minishift/pkg/util/progressdots"
+	"github.com/spf13/cobra"
+)
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create [BACKUP_FILE]",
+	Short: "Creates a backup of the cluster data.",
+	Long: `Creates a backup of the cluster data of the running VM. The containers of the cluster are paused while the data is
+archived. Unless a backup file is specified, the backup is written to the backups directory of the Minishift home
+directory.`,
+	Run: runBackupCreate,
+}
+
+func runBackupCreate(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		atexit.ExitWithMessage(1, "Usage: minishift backup create [BACKUP_FILE]")
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	cmdUtil.ExitIfUndefined(api, constants.MachineName)
+	hostVm, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	cmdUtil.ExitIfNotRunning(hostVm.Driver, constants.MachineName)
+
+	manifest := backup.Manifest{
+		Profile:          constants.ProfileName,
+		OpenShiftVersion: minishiftConfig.InstanceStateConfig.OpenshiftVersion,
+		Created:          time.Now(),
+	}
+	target := filepath.Join(minishiftConstants.GetBackupsDir(), backup.FileName(manifest.Profile, manifest.Created))
+	if len(args) == 1 {
+		target = args[0]
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error creating the backup directory: %v", err))
+	}
+
+	fmt.Print(fmt.Sprintf("-- Creating backup of profile '%s' ", manifest.Profile))
+	progressDots := progressdots.New()
+	progressDots.Start()
+	err = CreateBackup(api, hostVm, target, manifest)
+	progressDots.Stop()
+	if err != nil {
+		fmt.Println(" FAIL")
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	fmt.Println(" OK")
+	fmt.Println(fmt.Sprintf("Backup written to '%s'", target))
+}
+
+func init() {
+	BackupCmd.AddCommand(backupCreateCmd)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/backup"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listAllProfiles bool
+
+	backupListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "Lists the backups of the profile.",
+		Long:  "Lists the backups of the profile in the backups directory of the Minishift home directory, the newest first.",
+		Run:   runBackupList,
+	}
+)
+
+func runBackupList(cmd *cobra.Command, args []string) {
+	profile := constants.ProfileName
+	if listAllProfiles {
+		profile = ""
+	}
+	backups, err := backup.List(minishiftConstants.GetBackupsDir(), profile)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error listing the backups: %v", err))
+	}
+	if len(backups) == 0 {
+		fmt.Println("No backups found.")
+		return
+	}
+
+	display := new(tabwriter.Writer)
+	display.Init(os.Stdout, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(display, "CREATED\tPROFILE\tOPENSHIFT\tTYPE\tSIZE\tFILE")
+	for _, b := range backups {
+		kind := "manual"
+		if b.Manifest.Scheduled {
+			kind = "scheduled"
+		}
+		fmt.Fprintln(display, fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s", b.Manifest.Created.Format("2006-01-02 15:04:05"), b.Manifest.Profile,
+			b.Manifest.OpenShiftVersion, kind, units.HumanSize(float64(b.Size)), b.Path))
+	}
+	display.Flush()
+}
+
+func init() {
+	backupListCmd.Flags().BoolVar(&listAllProfiles, "all", false, "Lists the backups of all profiles.")
+	BackupCmd.AddCommand(backupListCmd)
+}
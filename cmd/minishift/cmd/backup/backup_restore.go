@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/provision"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/backup"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/filetransfer"
+	"github.com/minishift/minishift/pkg/util/filehelper"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/minishift/minishift/pkg/util/progressdots"
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreForce bool
+
+	backupRestoreCmd = &cobra.Command{
+		Use:   "restore [BACKUP_FILE]",
+		Short: "Restores the cluster data from a backup.",
+		Long: `Replaces the cluster data of the running VM with the content of a backup. OpenShift is stopped while the data is
+replaced and started again afterwards. The backup is either a file or the name of a file in the backups directory of the
+Minishift home directory. Without argument, the latest backup of the profile is restored.`,
+		Run: runBackupRestore,
+	}
+)
+
+func runBackupRestore(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		atexit.ExitWithMessage(1, "Usage: minishift backup restore [BACKUP_FILE]")
+	}
+	cmdUtil.ExitIfProtected(constants.ProfileName, restoreForce)
+	archive := resolveBackup(args)
+	manifest, err := backup.ReadManifest(archive)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	directories, err := backup.Verify(archive, manifest)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	openShiftVersion := minishiftConfig.InstanceStateConfig.OpenshiftVersion
+	if manifest.OpenShiftVersion != openShiftVersion && !restoreForce {
+		atexit.ExitWithMessage(1, fmt.Sprintf("The backup was created with OpenShift %s, but the cluster runs OpenShift %s. Use --force to restore it anyway.",
+			manifest.OpenShiftVersion, openShiftVersion))
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	cmdUtil.ExitIfUndefined(api, constants.MachineName)
+	hostVm, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	cmdUtil.ExitIfNotRunning(hostVm.Driver, constants.MachineName)
+
+	client, err := cluster.NewSSHClient(api)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Cannot establish SSH connection to the VM: %v", err))
+	}
+	defer client.Close()
+
+	fmt.Print(fmt.Sprintf("-- Restoring backup '%s' of profile '%s' ", filepath.Base(archive), manifest.Profile))
+	progressDots := progressdots.New()
+	progressDots.Start()
+	archiveInVM := backup.ArchivePathInsideInstance(filepath.Base(archive))
+	sshCommander := provision.GenericSSHCommander{Driver: hostVm.Driver}
+	err = backup.PrepareStaging(sshCommander)
+	if err == nil {
+		_, err = filetransfer.New(client, filetransfer.Options{}).ToVM(archive, archiveInVM)
+	}
+	if err == nil {
+		err = backup.Restore(sshCommander, archiveInVM, directories)
+		sshCommander.SSHCommand(fmt.Sprintf("sudo rm -f %s", archiveInVM))
+	}
+	progressDots.Stop()
+	if err != nil {
+		fmt.Println(" FAIL")
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	fmt.Println(" OK")
+	fmt.Println("OpenShift is starting with the restored data, which can take a few minutes.")
+}
+
+// resolveBackup returns the path of the backup archive specified by args, or the latest backup of the profile
+func resolveBackup(args []string) string {
+	dir := minishiftConstants.GetBackupsDir()
+	if len(args) == 1 {
+		if filehelper.Exists(args[0]) {
+			return args[0]
+		}
+		if inBackupsDir := filepath.Join(dir, args[0]); filehelper.Exists(inBackupsDir) {
+			return inBackupsDir
+		}
+		atexit.ExitWithMessage(1, fmt.Sprintf("Backup '%s' does not exist.", args[0]))
+	}
+
+	backups, err := backup.List(dir, constants.ProfileName)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error listing the backups: %v", err))
+	}
+	if len(backups) == 0 {
+		atexit.ExitWithMessage(1, fmt.Sprintf("There is no backup of profile '%s' in '%s'.", constants.ProfileName, dir))
+	}
+	return backups[0].Path
+}
+
+func init() {
+	backupRestoreCmd.Flags().BoolVarP(&restoreForce, "force", "f", false, "Restores the backup even if it was created with another OpenShift version.")
+	BackupCmd.AddCommand(backupRestoreCmd)
+}
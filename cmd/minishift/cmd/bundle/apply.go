@@ -0,0 +1,132 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/bundle"
+	"github.com/minishift/minishift/pkg/minishift/cache"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker/image"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	bundleApplyCmd = &cobra.Command{
+		Use:   "apply BUNDLE_FILE",
+		Short: "Preloads the content of a bundle into the local cache.",
+		Long: `Preloads the images and the oc binary contained in a bundle into the local cache and adds the images to the
+list of cached images of the profile. If the Minishift VM is running, the images are imported into its Docker daemon as well.`,
+		Run: applyBundle,
+	}
+)
+
+func applyBundle(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You need to specify the bundle file to apply.")
+	}
+	bundlePath := args[0]
+
+	manifest, err := bundle.ReadManifest(bundlePath)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error reading the bundle: %v", err))
+	}
+	// the version is used as directory name in the cache
+	if err := minishiftConfig.IsValidOcVersion("", manifest.OpenShiftVersion); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Invalid OpenShift version in the bundle: %v", err))
+	}
+
+	ocBinary := cache.Oc{OpenShiftVersion: manifest.OpenShiftVersion, MinishiftCacheDir: state.InstanceDirs.Cache}
+	ocTargetDir := ""
+	if manifest.OcOS == runtime.GOOS {
+		ocTargetDir = ocBinary.GetCacheFilepath()
+	} else if manifest.OcOS != "" {
+		fmt.Println(fmt.Sprintf("  WARN: The oc binary of the bundle is built for '%s' and is not applied.", manifest.OcOS))
+	}
+
+	fmt.Print(fmt.Sprintf("Applying bundle '%s' ... ", bundlePath))
+	if err := bundle.Apply(bundlePath, state.InstanceDirs.ImageCache, ocTargetDir); err != nil {
+		fmt.Println("FAIL")
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error applying the bundle: %v", err))
+	}
+	fmt.Println("OK")
+
+	util.RecordCacheReferences(cache.ImageArtifact, manifest.Images...)
+	if ocTargetDir != "" {
+		util.RecordCacheReferences(cache.OcArtifact, ocTargetDir)
+	}
+
+	cacheImages := minishiftConfig.InstanceConfig.CacheImages
+	for _, bundleImage := range manifest.Images {
+		cacheImages = appendUnique(cacheImages, bundleImage)
+	}
+	minishiftConfig.InstanceConfig.CacheImages = cacheImages
+	if err := minishiftConfig.InstanceConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error writing the cache images to config: %v", err))
+	}
+
+	importIntoRunningVM(manifest.Images)
+
+	fmt.Println(fmt.Sprintf("Run 'minishift start --openshift-version %s' to provision the cluster from the bundle.", manifest.OpenShiftVersion))
+}
+
+// importIntoRunningVM imports the images into the Docker daemon if the VM of the profile is running
+func importIntoRunningVM(images []string) {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil || !util.IsHostRunning(host.Driver) {
+		return
+	}
+
+	envMap, err := cluster.GetHostDockerEnv(api)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error determining Docker daemon settings: %v", err))
+	}
+
+	handler, err := image.NewOciImageHandler(host.Driver, envMap)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Cannot create the image handler: %v", err))
+	}
+
+	imageCacheConfig := &image.ImageCacheConfig{
+		HostCacheDir:      state.InstanceDirs.ImageCache,
+		CachedImages:      images,
+		Out:               os.Stdout,
+		ImageMissStrategy: image.Skip,
+		Workers:           viper.GetInt(config.ImageCachingWorkers.Name),
+	}
+	if _, err := handler.ImportImages(imageCacheConfig); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Container image import failed:\n%v", err))
+	}
+}
+
+func init() {
+	BundleCmd.AddCommand(bundleApplyCmd)
+}
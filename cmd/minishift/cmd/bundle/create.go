@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	cmdAddon "github.com/minishift/minishift/cmd/minishift/cmd/addon"
+	"github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/addon/manager"
+	"github.com/minishift/minishift/pkg/minishift/bundle"
+	"github.com/minishift/minishift/pkg/minishift/cache"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker/image"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	stringUtils "github.com/minishift/minishift/pkg/util/strings"
+	"github.com/minishift/minishift/pkg/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	openShiftVersionInterpolation = "#{openshift-version}"
+)
+
+var (
+	openShiftVersion string
+	output           string
+	skipOc           bool
+
+	bundleCreateCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Creates a bundle with all images required by an OpenShift version and the enabled add-ons.",
+		Long: `Creates a bundle with all images required by an OpenShift version and the enabled add-ons.
+The images and the oc binary are fetched into the local cache if needed. Besides the OpenShift images, the bundle
+contains the images listed in the Required-Images meta data of the enabled add-ons and the configured cache images.`,
+		Run: createBundle,
+	}
+)
+
+func createBundle(cmd *cobra.Command, args []string) {
+	if !strings.HasPrefix(openShiftVersion, constants.VersionPrefix) {
+		openShiftVersion = constants.VersionPrefix + openShiftVersion
+	}
+
+	if output == "" {
+		output = fmt.Sprintf("minishift-bundle-%s.tar.gz", openShiftVersion)
+	}
+
+	images := bundleImages(openShiftVersion, cmdAddon.GetAddOnManager())
+
+	handler, err := image.NewLocalOnlyOciImageHandler()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Cannot create the image handler: %v", err))
+	}
+
+	imageCacheConfig := &image.ImageCacheConfig{
+		HostCacheDir:       state.InstanceDirs.ImageCache,
+		CachedImages:       images,
+		Out:                os.Stdout,
+		Workers:            viper.GetInt(config.ImageCachingWorkers.Name),
+		InsecureRegistries: viper.GetStringSlice(config.InsecureRegistry.Name),
+	}
+	fetchedImages, err := handler.FetchImages(imageCacheConfig)
+	util.RecordCacheReferences(cache.ImageArtifact, fetchedImages...)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error fetching the images of the bundle:\n%v", err))
+	}
+
+	manifest := bundle.Manifest{OpenShiftVersion: openShiftVersion, Images: images}
+	ocBinary := ""
+	if !skipOc {
+		oc := cache.Oc{OpenShiftVersion: openShiftVersion, MinishiftCacheDir: state.InstanceDirs.Cache}
+		if err := oc.EnsureIsCached(); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error caching the oc binary: %v", err))
+		}
+		util.RecordCacheReferences(cache.OcArtifact, oc.GetCacheFilepath())
+		ocBinary = filepath.Join(oc.GetCacheFilepath(), constants.OC_BINARY_NAME)
+		manifest.OcOS = runtime.GOOS
+	}
+
+	fmt.Print(fmt.Sprintf("Writing bundle '%s' ... ", output))
+	if err := bundle.Create(output, manifest, state.InstanceDirs.ImageCache, ocBinary); err != nil {
+		fmt.Println("FAIL")
+		os.Remove(output)
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error creating the bundle: %v", err))
+	}
+	fmt.Println("OK")
+}
+
+// bundleImages returns the images required by the specified OpenShift version, the enabled add-ons and the configured cache images
+func bundleImages(openShiftVersion string, addOnManager *manager.AddOnManager) []string {
+	images := image.GetClusterUpImageNames(openShiftVersion)
+	for _, addOn := range addOnManager.List() {
+		if !addOn.IsEnabled() {
+			continue
+		}
+		requiredImages, err := addOn.MetaData().RequiredImages()
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Invalid Required-Images meta data of add-on '%s': %v", addOn.MetaData().Name(), err))
+		}
+		for _, requiredImage := range requiredImages {
+			images = appendUnique(images, strings.Replace(requiredImage, openShiftVersionInterpolation, openShiftVersion, -1))
+		}
+	}
+
+	for _, cacheImage := range minishiftConfig.InstanceConfig.CacheImages {
+		images = appendUnique(images, cacheImage)
+	}
+	return images
+}
+
+func appendUnique(images []string, image string) []string {
+	if stringUtils.Contains(images, image) {
+		return images
+	}
+	return append(images, image)
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVar(&openShiftVersion, "openshift-version", version.GetOpenShiftVersion(), "The OpenShift version to create the bundle for.")
+	bundleCreateCmd.Flags().StringVarP(&output, "output", "o", "", "The file to write the bundle to. Defaults to 'minishift-bundle-<openshift-version>.tar.gz'.")
+	bundleCreateCmd.Flags().BoolVar(&skipOc, "skip-oc", false, "Does not add the oc binary to the bundle.")
+	BundleCmd.AddCommand(bundleCreateCmd)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cleanup"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	pkgUtil "github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var cleanupForceFlag bool
+
+// cleanupCmd represents the cleanup command
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Removes VMs, machine directories and network interfaces left behind by Minishift.",
+	Long: `Detects and removes the leftovers of deleted or broken Minishift VMs: VMs registered with the hypervisor whose
+machine directory in the Minishift home directory is gone, machine directories whose VM no longer exists in the hypervisor,
+as well as VirtualBox host-only interfaces within the Minishift host-only networks and Hyper-V virtual switches no VM
+uses any longer.
+Each removal needs to be confirmed, unless the --force flag is specified.`,
+	Run: runCleanup,
+}
+
+func runCleanup(cmd *cobra.Command, args []string) {
+	// no other Minishift process may create or delete VMs while leftovers are detected
+	cmdUtil.LockProfilesOrExit(profileActions.GetProfileList()...)
+
+	home := constants.GetMinishiftHomeDir()
+	machines, err := cleanup.FindMachines(home)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error reading the machine directories in '%s': %v", home, err))
+	}
+
+	orphans, err := cleanup.Detect(home, machines)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Warning: Not all leftovers could be detected: %v", err))
+	}
+	if len(orphans) == 0 {
+		fmt.Println("Nothing to clean up.")
+		return
+	}
+
+	fmt.Println(fmt.Sprintf("Found %d leftovers:", len(orphans)))
+	for _, orphan := range orphans {
+		fmt.Println(fmt.Sprintf("  %s", orphan))
+	}
+
+	errors := pkgUtil.MultiError{}
+	removed := 0
+	for _, orphan := range orphans {
+		if !cleanupForceFlag && !pkgUtil.AskForConfirmation(fmt.Sprintf("Removing %s.", orphan)) {
+			continue
+		}
+		if err := orphan.Remove(); err != nil {
+			errors.Collect(fmt.Errorf("Error removing %s: %v", orphan, err))
+			continue
+		}
+		removed++
+	}
+	fmt.Println(fmt.Sprintf("Removed %d of %d leftovers.", removed, len(orphans)))
+
+	if err := errors.ToError(); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+}
+
+func init() {
+	cleanupCmd.Flags().BoolVarP(&cleanupForceFlag, "force", "f", false, "Removes the leftovers without asking for confirmation.")
+	RootCmd.AddCommand(cleanupCmd)
+}
@@ -50,6 +50,9 @@ const longDescription = `
 
 	Additionally, you may want to output the completion to a file and source in your .bashrc
 
+	Profile names, add-on names and configuration properties are completed from the local Minishift state,
+	for example for 'minishift profile set', 'minishift addons enable' and 'minishift config set'.
+
 	Note for zsh users: [1] zsh completions are only supported in versions of zsh >= 5.2
 `
 
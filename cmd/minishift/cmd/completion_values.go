@@ -0,0 +1,161 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	cmdAddon "github.com/minishift/minishift/cmd/minishift/cmd/addon"
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/minishift/minishift/pkg/minishift/addon"
+	"github.com/minishift/minishift/pkg/minishift/iso"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+const (
+	completeProfiles       = "profiles"
+	completeAddOns         = "addons"
+	completeEnabledAddOns  = "enabled-addons"
+	completeDisabledAddOns = "disabled-addons"
+	completeConfigKeys     = "config-keys"
+	completeIsoFlavors     = "iso-flavors"
+)
+
+// completionValuesCommand is the name of the hidden command printing the completion values. The name must not collide
+// with the '__complete' command newer cobra versions add for their own completion.
+const completionValuesCommand = "__completion-values"
+
+// bashCompletionFunc is called by the generated bash completion whenever cobra itself has no completions to offer.
+// It completes the arguments of selected commands with values read from the local Minishift state. The profile is
+// looked up in the words of the command line, since cobra records only some of the forms of a flag in flaghash.
+const bashCompletionFunc = `
+__minishift_complete_values()
+{
+    local i profile_flag values
+    for (( i=0; i < ${#words[@]}; i++ )); do
+        case "${words[i]}" in
+            --profile=*)
+                profile_flag="${words[i]}"
+                ;;
+            --profile)
+                profile_flag="--profile=${words[i+1]}"
+                ;;
+        esac
+    done
+    values=$(minishift ` + completionValuesCommand + ` ${profile_flag} "$1" 2>/dev/null)
+    COMPREPLY=( $( compgen -W "${values}" -- "$cur" ) )
+}
+
+__custom_func() {
+    case ${last_command} in
+        minishift_profile_set | minishift_profile_delete | minishift_profile_copy | minishift_profile_export | minishift_profile_clone | minishift_profile_rename)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __minishift_complete_values profiles
+            fi
+            return
+            ;;
+        minishift_addons_enable)
+            __minishift_complete_values disabled-addons
+            return
+            ;;
+        minishift_addons_disable)
+            __minishift_complete_values enabled-addons
+            return
+            ;;
+        minishift_addons_apply | minishift_addons_remove | minishift_addons_uninstall | minishift_addons_update)
+            __minishift_complete_values addons
+            return
+            ;;
+        minishift_iso_set)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __minishift_complete_values iso-flavors
+            fi
+            return
+            ;;
+        minishift_config_set | minishift_config_get | minishift_config_unset)
+            if [[ ${#nouns[@]} -eq 0 ]]; then
+                __minishift_complete_values config-keys
+            fi
+            return
+            ;;
+        *)
+            ;;
+    esac
+}
+`
+
+var completionValuesCmd = &cobra.Command{
+	Use:    completionValuesCommand + " KIND",
+	Short:  "Prints the values used by the shell completion for dynamic arguments.",
+	Long:   "Prints the values used by the shell completion for dynamic arguments, one per line.",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Usage: minishift %s KIND", completionValuesCommand))
+		}
+
+		values, err := completionValues(args[0])
+		if err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+		for _, value := range values {
+			fmt.Println(value)
+		}
+	},
+}
+
+func init() {
+	RootCmd.BashCompletionFunction = bashCompletionFunc
+	RootCmd.AddCommand(completionValuesCmd)
+}
+
+func completionValues(kind string) ([]string, error) {
+	switch kind {
+	case completeProfiles:
+		return profileActions.GetProfileList(), nil
+	case completeAddOns, completeEnabledAddOns, completeDisabledAddOns:
+		return addOnNames(cmdAddon.GetAddOnManager().List(), kind), nil
+	case completeConfigKeys:
+		names := configCmd.SettingNames()
+		sort.Strings(names)
+		return names, nil
+	case completeIsoFlavors:
+		var names []string
+		for _, flavor := range iso.Flavors() {
+			names = append(names, flavor.Name)
+		}
+		return names, nil
+	default:
+		return nil, fmt.Errorf("Unknown completion kind '%s'", kind)
+	}
+}
+
+// addOnNames returns the sorted names of the add-ons, filtered by their status for the enabled or disabled kind.
+func addOnNames(addOns []addon.AddOn, kind string) []string {
+	var names []string
+	for _, addOn := range addOns {
+		if kind == completeEnabledAddOns && !addOn.IsEnabled() || kind == completeDisabledAddOns && addOn.IsEnabled() {
+			continue
+		}
+		names = append(names, addOn.MetaData().Name())
+	}
+	sort.Strings(names)
+	return names
+}
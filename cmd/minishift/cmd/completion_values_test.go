@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/addon"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddOn_Names_Are_Filtered_By_Status(t *testing.T) {
+	addOns := []addon.AddOn{
+		newTestAddOn(t, "xpaas", false),
+		newTestAddOn(t, "admin-user", true),
+		newTestAddOn(t, "anyuid", false),
+	}
+
+	assert.Equal(t, []string{"admin-user", "anyuid", "xpaas"}, addOnNames(addOns, completeAddOns))
+	assert.Equal(t, []string{"admin-user"}, addOnNames(addOns, completeEnabledAddOns))
+	assert.Equal(t, []string{"anyuid", "xpaas"}, addOnNames(addOns, completeDisabledAddOns))
+}
+
+func Test_Config_Keys_Are_Completed(t *testing.T) {
+	keys, err := completionValues(completeConfigKeys)
+
+	assert.NoError(t, err)
+	assert.Contains(t, keys, "memory")
+	assert.Contains(t, keys, "vm-driver")
+}
+
+func Test_Unknown_Completion_Kind_Returns_Error(t *testing.T) {
+	_, err := completionValues("foo")
+
+	assert.EqualError(t, err, "Unknown completion kind 'foo'")
+}
+
+func Test_Bash_Completion_Calls_Custom_Func(t *testing.T) {
+	assert.Contains(t, RootCmd.BashCompletionFunction, "minishift_profile_set")
+	assert.Contains(t, RootCmd.BashCompletionFunction, "minishift __completion-values")
+	assert.NotContains(t, RootCmd.BashCompletionFunction, "minishift __complete ")
+}
+
+func newTestAddOn(t *testing.T, name string, enabled bool) addon.AddOn {
+	meta, err := addon.NewAddOnMeta(map[string]interface{}{"Name": name, "Description": []string{"test"}})
+	assert.NoError(t, err)
+
+	addOn := addon.NewAddOn(meta, nil, nil, nil, "")
+	addOn.SetEnabled(enabled)
+	return addOn
+}
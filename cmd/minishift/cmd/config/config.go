@@ -20,6 +20,10 @@ import (
 	"strings"
 
 	validations "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker/image"
+	"github.com/minishift/minishift/pkg/minishift/network/registrycache"
+	"github.com/minishift/minishift/pkg/minishift/update"
+	"github.com/minishift/minishift/pkg/util/secret"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -37,6 +41,10 @@ type Setting struct {
 	set         func(validations.ViperConfig, string, string) error
 	validations []setFn
 	callbacks   []setFn
+	// defaultValue is the default of a property without corresponding flag
+	defaultValue interface{}
+	// redact returns the representation of a secret value for display. It is nil for properties which are not secret.
+	redact func(string) string
 }
 
 var settingsList []Setting
@@ -44,17 +52,30 @@ var settingsList []Setting
 var (
 	// minishift
 	ISOUrl                = createConfigSetting("iso-url", SetString, []setFn{validations.IsValidISOUrl}, []setFn{RequiresRestartMsg}, true, nil)
+	ISOSigningKey         = createConfigSetting("iso-signing-key", SetString, []setFn{validations.IsValidPath}, nil, true, nil)
+	CloudInit             = createConfigSetting("cloud-init", SetString, []setFn{validations.IsValidCloudInit}, nil, true, nil)
+	DownloadMirrors       = createConfigSetting("download-mirrors", SetSlice, []setFn{validations.IsValidDownloadMirrorSlice}, nil, true, nil)
 	CPUs                  = createConfigSetting("cpus", SetInt, []setFn{validations.IsPositive}, []setFn{RequiresRestartMsg}, true, nil)
 	Memory                = createConfigSetting("memory", SetString, []setFn{validations.IsValidMemorySize}, []setFn{RequiresRestartMsg}, true, nil)
 	DiskSize              = createConfigSetting("disk-size", SetString, []setFn{validations.IsValidDiskSize}, []setFn{RequiresRestartMsg}, true, nil)
 	VmDriver              = createConfigSetting("vm-driver", SetString, []setFn{validations.IsValidDriver}, []setFn{RequiresRestartMsg}, true, nil)
-	OpenshiftVersion      = createConfigSetting("openshift-version", SetString, nil, nil, true, nil)
+	ContainerRuntime      = createConfigSetting("container-runtime", SetString, []setFn{validations.IsValidContainerRuntime}, []setFn{RequiresRestartMsg}, true, nil)
+	KubernetesOnly        = createConfigSetting("kubernetes-only", SetBool, nil, []setFn{RequiresRestartMsg}, true, nil)
+	OpenshiftVersion      = createConfigSetting("openshift-version", SetString, []setFn{validations.IsValidOpenShiftVersion}, nil, true, nil)
+	OcVersion             = createConfigSetting("oc-version", SetString, []setFn{validations.IsValidOcVersion}, nil, true, nil)
 	HostOnlyCIDR          = createConfigSetting("host-only-cidr", SetString, []setFn{validations.IsValidCIDR}, nil, true, nil)
 	DockerEnv             = createConfigSetting("docker-env", SetSlice, nil, nil, true, nil)
-	DockerEngineOpt       = createConfigSetting("docker-opt", SetSlice, nil, nil, true, nil)
-	InsecureRegistry      = createConfigSetting("insecure-registry", SetSlice, nil, nil, true, nil)
-	RegistryMirror        = createConfigSetting("registry-mirror", SetSlice, nil, nil, true, nil)
+	DockerEngineOpt       = createConfigSetting("docker-opt", SetDockerOpt, []setFn{validations.IsValidDockerOpt}, nil, true, nil)
+	DockerVersion         = createConfigSetting("docker-version", SetString, []setFn{validations.IsValidDockerVersion}, nil, true, nil)
+	DockerEnvFile         = createConfigSetting("docker-env-file", SetString, []setFn{validations.IsValidDockerEnvFile}, nil, true, nil)
+	DockerStorageOpt      = createConfigSetting("docker-storage-opt", SetSlice, []setFn{validations.IsValidDockerStorageOptSlice}, nil, true, nil)
+	InsecureRegistry      = createConfigSetting("insecure-registry", SetSlice, []setFn{validations.IsValidDockerFlagValueSlice}, nil, true, nil)
+	RegistryCAs           = createConfigSetting("registry-cas", SetSlice, []setFn{validations.IsValidRegistryCASlice}, nil, true, nil)
+	RegistryMirror        = createConfigSetting("registry-mirror", SetSlice, []setFn{validations.IsValidDockerFlagValueSlice}, nil, true, nil)
+	ImagePolicy           = createConfigSetting("image-policy", SetString, []setFn{validations.IsValidImagePolicy}, nil, true, nil)
+	ImageSignatureConfig  = createConfigSetting("image-signature-config", SetString, []setFn{validations.IsValidImageSignatureConfig}, nil, true, nil)
 	AddonEnv              = createConfigSetting("addon-env", SetSlice, nil, nil, true, nil)
+	Preset                = createConfigSetting("preset", SetString, []setFn{validations.IsValidPreset}, nil, true, nil)
 	RemoteIPAddress       = createConfigSetting("remote-ipaddress", SetString, nil, nil, true, nil)
 	RemoteSSHUser         = createConfigSetting("remote-ssh-user", SetString, nil, nil, true, nil)
 	SSHKeyToConnectRemote = createConfigSetting("remote-ssh-key", SetString, nil, nil, true, nil)
@@ -67,22 +88,51 @@ var (
 	ServerLogLevel    = createConfigSetting("server-loglevel", SetInt, []setFn{validations.IsPositive}, nil, true, nil)
 	ImageName         = createConfigSetting("image", SetString, nil, nil, false, nil)
 	WriteConfig       = createConfigSetting("write-config", SetBool, nil, nil, true, nil)
+	RouterImage       = createConfigSetting("openshift.router-image", SetString, []setFn{validations.IsValidImageReference}, []setFn{RequiresRestartMsg}, true, nil)
+	RegistryImage     = createConfigSetting("openshift.registry-image", SetString, []setFn{validations.IsValidImageReference}, []setFn{RequiresRestartMsg}, true, nil)
+	WebConsoleImage   = createConfigSetting("openshift.web-console-image", SetString, []setFn{validations.IsValidImageReference}, []setFn{RequiresRestartMsg}, true, nil)
+	ExtraTemplates    = createConfigSetting("extra-templates", SetSlice, []setFn{isValidTemplateSourceSlice}, nil, true, nil)
+	APIExtraSANs      = createConfigSetting("api-extra-sans", SetSlice, []setFn{validations.IsValidSANSlice}, []setFn{RequiresRestartMsg}, true, nil)
+	ClusterUpFlags    = createConfigSetting("cluster-up-flags", SetSlice, []setFn{validations.IsValidClusterUpFlags}, []setFn{RequiresRestartMsg}, true, nil)
+
+	// persistent volumes
+	PVCount         = createConfigSetting("pv-count", SetInt, []setFn{validations.IsValidPVCount}, nil, true, nil)
+	PVSize          = createConfigSetting("pv-size", SetString, []setFn{validations.IsValidPVSize}, nil, true, nil)
+	PVReclaimPolicy = createConfigSetting("pv-reclaim-policy", SetString, []setFn{validations.IsValidPVReclaimPolicy}, nil, true, nil)
+
+	// service catalog
+	ServiceCatalog = createConfigSetting("service-catalog", SetBool, nil, nil, true, nil)
+
+	// identity provider
+	IdentityProvider = createConfigSetting("identity-provider", SetString, []setFn{validations.IsValidIdentityProvider}, nil, true, nil)
+	HtpasswdFile     = createConfigSetting("identity-provider.htpasswd-file", SetString, []setFn{validations.IsValidPath}, nil, true, nil)
+	LDAPURL          = createConfigSetting("identity-provider.ldap-url", SetString, []setFn{validations.IsValidLDAPURL}, nil, true, nil)
+	LDAPBindDN       = createConfigSetting("identity-provider.ldap-bind-dn", SetString, nil, nil, true, nil)
+	LDAPBindPassword = createSecretSetting("identity-provider.ldap-bind-password", SetSecret, nil, secret.Redact)
+	LDAPCA           = createConfigSetting("identity-provider.ldap-ca", SetString, []setFn{validations.IsValidPath}, nil, true, nil)
+
+	// audit logging
+	AuditLogging = createConfigSetting("audit-logging", SetBool, nil, []setFn{RequiresRestartMsg}, true, nil)
 
 	// future enabled flags
 	ExtraClusterUpFlags = createConfigSetting("extra-clusterup-flags", SetString, nil, nil, true, nil)
 
 	// Setting proxy
 	NoProxyList = createConfigSetting("no-proxy", SetString, nil, nil, true, nil)
-	HttpProxy   = createConfigSetting("http-proxy", SetString, []setFn{validations.IsValidProxy}, nil, true, nil)
-	HttpsProxy  = createConfigSetting("https-proxy", SetString, []setFn{validations.IsValidProxy}, nil, true, nil)
+	HttpProxy   = createSecretSetting("http-proxy", SetCredentialURL, []setFn{validations.IsValidProxy}, redactCredentialURL)
+	HttpsProxy  = createSecretSetting("https-proxy", SetCredentialURL, []setFn{validations.IsValidProxy}, redactCredentialURL)
 	// when local proxy is set, it will override the assigned proxies
 	LocalProxy          = createConfigSetting("local-proxy", SetBool, nil, nil, true, nil)
 	LocalProxyReencrypt = createConfigSetting("local-proxy-reencrypt", SetBool, nil, nil, true, nil)
-	LocalProxyUpstream  = createConfigSetting("local-proxy-upstream", SetString, []setFn{validations.IsValidProxy}, nil, true, nil)
+	LocalProxyUpstream  = createSecretSetting("local-proxy-upstream", SetCredentialURL, []setFn{validations.IsValidProxy}, redactCredentialURL)
+
+	// Host-side pull-through registry cache used as registry mirror by the Docker daemon
+	RegistryCache         = createConfigSetting("registry-cache", SetBool, nil, []setFn{RequiresRestartMsg}, true, nil)
+	RegistryCacheUpstream = createConfigSetting("registry-cache-upstream", SetString, nil, nil, true, registrycache.DefaultUpstream)
 
 	// Subscription Manager
 	Username         = createConfigSetting("username", SetString, nil, nil, true, nil)
-	Password         = createConfigSetting("password", SetString, nil, nil, true, nil)
+	Password         = createSecretSetting("password", SetSecret, nil, secret.Redact)
 	SkipRegistration = createConfigSetting("skip-registration", SetBool, nil, nil, true, nil)
 
 	// Global flags
@@ -94,15 +144,25 @@ var (
 	HostFoldersAutoMount = createConfigSetting("hostfolders-automount", SetBool, nil, nil, true, nil)
 
 	// Services
-	ServicesSftpPort       = createConfigSetting("hostfolders-sftp-port", SetInt, []setFn{validations.IsValidPort}, nil, true, nil)
-	ServicesLocalProxyPort = createConfigSetting("services-proxy-port", SetInt, []setFn{validations.IsValidPort}, nil, true, nil)
+	ServicesSftpPort          = createConfigSetting("hostfolders-sftp-port", SetInt, []setFn{validations.IsValidPort}, nil, true, nil)
+	ServicesLocalProxyPort    = createConfigSetting("services-proxy-port", SetInt, []setFn{validations.IsValidPort}, nil, true, nil)
+	ServicesRegistryCachePort = createConfigSetting("services-registry-cache-port", SetInt, []setFn{validations.IsValidPort}, []setFn{RequiresRestartMsg}, true, registrycache.DefaultPort)
+
+	// Update
+	UpdateChannel   = createConfigSetting("update-channel", SetString, []setFn{validations.IsValidUpdateChannel}, nil, true, update.StableChannel)
+	SkipUpdateCheck = createConfigSetting("skip-update-check", SetBool, nil, nil, true, false)
 
 	// No Provision
 	NoProvision = createConfigSetting("no-provision", SetBool, nil, nil, true, nil)
 
 	// Image caching
-	ImageCaching = createConfigSetting("image-caching", SetBool, nil, nil, true, true)
-	CacheImages  = createConfigSetting("cache-images", SetSlice, nil, nil, false, nil)
+	ImageCaching        = createConfigSetting("image-caching", SetBool, nil, nil, true, true)
+	CacheImages         = createConfigSetting("cache-images", SetSlice, nil, nil, false, nil)
+	ImageCachingWorkers = createConfigSetting("image-caching-workers", SetInt, []setFn{validations.IsPositive}, nil, true, image.DefaultWorkers)
+	HostImages          = createConfigSetting("host-images", SetSlice, nil, nil, true, nil)
+	HostImagesCli       = createConfigSetting("host-images-cli", SetString, nil, nil, true, nil)
+	AutoCleanCache      = createConfigSetting("auto-clean-cache", SetBool, nil, nil, true, nil)
+	IsolatedCache       = createConfigSetting("isolated-cache", SetBool, nil, nil, true, false)
 
 	// Pre-flight checks (before start)
 	SkipDeprecationCheck      = createConfigSetting("skip-check-deprecation", SetBool, nil, nil, true, nil)
@@ -173,16 +233,24 @@ var (
 	// Systemtray
 	AutoStartTray = createConfigSetting("auto-start-tray", SetBool, []setFn{validations.IsSystemTrayAvailable}, nil, true, true)
 
+	// Host agent
+	DaemonIdleTimeout = createConfigSetting("daemon-idle-timeout", SetString, []setFn{validations.IsPositiveDuration}, nil, true, nil)
+
+	// Scheduled backups taken by the host agent
+	BackupSchedule  = createConfigSetting("backup.schedule", SetString, []setFn{validations.IsPositiveDuration}, nil, true, nil)
+	BackupRetention = createConfigSetting("backup.retention", SetInt, []setFn{validations.IsPositive}, nil, true, nil)
+
 	// Static-IP
 	StaticIPAutoSet = createConfigSetting("static-ip", SetBool, nil, nil, true, true)
 )
 
 func createConfigSetting(name string, set func(validations.ViperConfig, string, string) error, validations []setFn, callbacks []setFn, isApply bool, defaultVal interface{}) *Setting {
 	flag := Setting{
-		Name:        name,
-		set:         set,
-		validations: validations,
-		callbacks:   callbacks,
+		Name:         name,
+		set:          set,
+		validations:  validations,
+		callbacks:    callbacks,
+		defaultValue: defaultVal,
 	}
 	if isApply {
 		settingsList = append(settingsList, flag)
@@ -205,9 +273,25 @@ Configurable properties (enter as SUBCOMMAND): ` + "\n\n" + configurableFields()
 			cmd.Help()
 		},
 	}
-	global bool
+	global       bool
+	force        bool
+	showOrigin   bool
+	revealSecret bool
+
+	// destructiveSettings are the properties whose change only takes effect after the instance is deleted and
+	// created again. Changing them for a protected profile requires --force.
+	destructiveSettings = []string{ISOUrl.Name, CPUs.Name, Memory.Name, DiskSize.Name, VmDriver.Name, ContainerRuntime.Name, KubernetesOnly.Name}
 )
 
+// SettingNames returns the names of all configurable properties.
+func SettingNames() []string {
+	var names []string
+	for _, s := range settingsList {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
 func configurableFields() string {
 	var fields []string
 	for _, s := range settingsList {
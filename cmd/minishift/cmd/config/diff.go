@@ -0,0 +1,143 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var configDiffOutput string
+
+// ConfigDelta is a configuration property whose effective value differs from its default.
+type ConfigDelta struct {
+	Value   interface{} `json:"value" yaml:"value"`
+	Default interface{} `json:"default" yaml:"default"`
+	// Origin is the layer the value originates from, e.g. 'env' or 'profile'
+	Origin string `json:"origin" yaml:"origin"`
+}
+
+var configDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Display the configuration properties whose values differ from the defaults.",
+	Long: `Display the configuration properties whose effective values differ from the defaults, together with the default
+and the origin of each value. The output only contains the deltas to the defaults, so that it can be included in support requests.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		deltas, err := configDiff()
+		if err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+
+		if configDiffOutput != "" {
+			err = printStructured(deltas, configDiffOutput, os.Stdout)
+		} else {
+			printConfigDiff(deltas, os.Stdout)
+		}
+		if err != nil {
+			atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+		}
+	},
+}
+
+func init() {
+	configDiffCmd.Flags().StringVarP(&configDiffOutput, "output", "o", "", "Prints the differences in the specified format. Supported formats: yaml, json")
+	ConfigCmd.AddCommand(configDiffCmd)
+}
+
+// configDiff returns the configurable properties whose effective values differ from their defaults
+func configDiff() (map[string]ConfigDelta, error) {
+	deltas := make(map[string]ConfigDelta)
+	for _, s := range settingsList {
+		if _, excluded := excludedConfigKeys[s.Name]; excluded {
+			continue
+		}
+
+		value, err := EffectiveValue(nil, s.Name)
+		if err != nil {
+			return nil, err
+		}
+		if value.Origin == OriginDefault {
+			continue
+		}
+
+		defaultValue := settingDefault(s)
+		if normalizeValue(value.Value) == normalizeValue(defaultValue) {
+			continue
+		}
+		deltas[s.Name] = ConfigDelta{Value: RedactedValue(s.Name, value.Value), Default: defaultValue, Origin: value.Origin}
+	}
+	return deltas, nil
+}
+
+// settingDefault returns the default value of the corresponding flag or, if there is no such flag, the default of
+// the setting itself
+func settingDefault(s Setting) interface{} {
+	if value, ok := FlagDefault(s.Name); ok {
+		return value
+	}
+	if s.defaultValue != nil {
+		return s.defaultValue
+	}
+	return ""
+}
+
+// normalizeValue returns the string representation of a value, so that values read from the JSON configuration
+// files can be compared with the string defaults of flags. Slices are represented as comma-separated list.
+func normalizeValue(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		var elements []string
+		for _, element := range v {
+			elements = append(elements, fmt.Sprintf("%v", element))
+		}
+		return strings.Join(elements, ",")
+	case []string:
+		return strings.Join(v, ",")
+	case nil:
+		return ""
+	}
+
+	s := fmt.Sprintf("%v", value)
+	// string slice flags have a default like '[]'
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		return strings.Replace(strings.Trim(s, "[]"), " ", ",", -1)
+	}
+	return s
+}
+
+func printConfigDiff(deltas map[string]ConfigDelta, writer io.Writer) {
+	var names []string
+	for name := range deltas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		delta := deltas[name]
+		defaultValue := normalizeValue(delta.Default)
+		if defaultValue == "" {
+			defaultValue = "<none>"
+		}
+		fmt.Fprintln(writer, fmt.Sprintf("- %-35s: %s (%s, default: %s)", name, normalizeValue(delta.Value), delta.Origin, defaultValue))
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDiffOnlyContainsNonDefaultValues(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-config-diff-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	origConfigFile, origGlobalConfigFile := constants.ConfigFile, constants.GlobalConfigFile
+	constants.ConfigFile = filepath.Join(testDir, "config.json")
+	constants.GlobalConfigFile = filepath.Join(testDir, "global.json")
+	defer func() {
+		constants.ConfigFile, constants.GlobalConfigFile = origConfigFile, origGlobalConfigFile
+	}()
+
+	defaults := map[string]string{"cpus": "2", "memory": "4GB", "insecure-registry": "[]"}
+	origFlagDefault := FlagDefault
+	FlagDefault = func(name string) (string, bool) {
+		value, ok := defaults[name]
+		return value, ok
+	}
+	defer func() { FlagDefault = origFlagDefault }()
+
+	assert.NoError(t, ioutil.WriteFile(constants.GlobalConfigFile, []byte(`{"memory": "8GB", "insecure-registry": ["hub.foo.com"]}`), 0644))
+	assert.NoError(t, ioutil.WriteFile(constants.ConfigFile, []byte(`{"cpus": 2}`), 0644))
+	defer setEnv(map[string]string{"MINISHIFT_VM_DRIVER": "virtualbox"})()
+
+	deltas, err := configDiff()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]ConfigDelta{
+		"memory":            {Value: "8GB", Default: "4GB", Origin: OriginGlobal},
+		"insecure-registry": {Value: []interface{}{"hub.foo.com"}, Default: "[]", Origin: OriginGlobal},
+		"vm-driver":         {Value: "virtualbox", Default: "", Origin: OriginEnv},
+	}, deltas, "The unchanged cpus value should not be part of the diff")
+
+	var out bytes.Buffer
+	printConfigDiff(deltas, &out)
+	assert.Equal(t, "- insecure-registry                  : hub.foo.com (global, default: <none>)\n"+
+		"- memory                             : 8GB (global, default: 4GB)\n"+
+		"- vm-driver                          : virtualbox (env, default: <none>)\n", out.String())
+}
@@ -0,0 +1,73 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	validations "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/spf13/viper"
+)
+
+// EnvOverride returns the value of the MINISHIFT_<KEY> environment variable overriding the configuration property
+// with the specified name, e.g. MINISHIFT_VM_DRIVER for vm-driver.
+func EnvOverride(name string) (string, bool) {
+	return os.LookupEnv(envVariableName(name))
+}
+
+// ApplyEnvOverrides sets every configuration property for which a MINISHIFT_<KEY> environment variable is set.
+// The values are validated and converted like the values passed to 'minishift config set', so that slices are
+// comma-separated and booleans accept 'on' and 'off'. Since viper consults changed flags before explicitly set
+// values, flags passed on the command line still take precedence over the environment. Invalid values are ignored,
+// so that a single variable does not break every command, and are returned as errors.
+func ApplyEnvOverrides() []error {
+	var invalid []error
+	for _, s := range settingsList {
+		value, ok := EnvOverride(s.Name)
+		if !ok {
+			continue
+		}
+
+		if err := applyValue(s, value); err != nil {
+			invalid = append(invalid, fmt.Errorf("Ignoring invalid value '%s' of %s: %v", value, envVariableName(s.Name), err))
+			// viper reads the environment on its own, it must not see the invalid value either
+			os.Unsetenv(envVariableName(s.Name))
+		}
+	}
+	return invalid
+}
+
+// applyValue validates and converts the value like 'minishift config set' before setting it in viper
+func applyValue(s Setting, value string) error {
+	if err := run(s.Name, value, s.validations); err != nil {
+		return err
+	}
+
+	// secret values are only encrypted when written to a configuration file
+	if s.redact != nil {
+		viper.Set(s.Name, value)
+		return nil
+	}
+
+	converted := validations.ViperConfig{}
+	if err := s.set(converted, s.Name, value); err != nil {
+		return err
+	}
+	viper.Set(s.Name, converted[s.Name])
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	defer viper.Reset()
+	defer setEnv(map[string]string{
+		"MINISHIFT_CPUS":              "4",
+		"MINISHIFT_INSECURE_REGISTRY": "hub.foo.com, hub.bar.com",
+		"MINISHIFT_SKIP_REGISTRATION": "on",
+	})()
+
+	viper.Set(CPUs.Name, 2)
+	assert.Empty(t, ApplyEnvOverrides())
+
+	assert.Equal(t, 4, viper.GetInt(CPUs.Name))
+	assert.Equal(t, []string{"hub.foo.com", "hub.bar.com"}, viper.GetStringSlice(InsecureRegistry.Name))
+	assert.True(t, viper.GetBool(SkipRegistration.Name))
+}
+
+func TestFlagsTakePrecedenceOverEnvOverrides(t *testing.T) {
+	defer viper.Reset()
+	defer setEnv(map[string]string{"MINISHIFT_CPUS": "4"})()
+
+	flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+	flags.Int(CPUs.Name, 2, "")
+	viper.BindPFlags(flags)
+	assert.NoError(t, flags.Parse([]string{"--cpus", "8"}))
+
+	assert.Empty(t, ApplyEnvOverrides())
+	assert.Equal(t, 8, viper.GetInt(CPUs.Name))
+}
+
+func TestInvalidEnvOverride(t *testing.T) {
+	defer viper.Reset()
+	defer setEnv(map[string]string{"MINISHIFT_CPUS": "many"})()
+
+	defer setEnv(map[string]string{"MINISHIFT_MEMORY": "8GB"})()
+
+	viper.Set(CPUs.Name, 2)
+	errs := ApplyEnvOverrides()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "MINISHIFT_CPUS")
+	assert.Equal(t, 2, viper.GetInt(CPUs.Name), "An invalid value should be ignored")
+	assert.Equal(t, "8GB", viper.GetString(Memory.Name), "Valid values should still be applied")
+	_, isSet := os.LookupEnv("MINISHIFT_CPUS")
+	assert.False(t, isSet)
+}
+
+// setEnv sets the environment variables and returns a function unsetting them again
+func setEnv(env map[string]string) func() {
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+	return func() {
+		for name := range env {
+			os.Unsetenv(name)
+		}
+	}
+}
@@ -23,9 +23,13 @@ import (
 	"github.com/minishift/minishift/pkg/minikube/constants"
 	"github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/minishift/minishift/pkg/util/secret"
 	"github.com/spf13/cobra"
 )
 
+// unsetValue is printed for properties which are not set
+const unsetValue = "<nil>"
+
 var configGetCmd = &cobra.Command{
 	Use:   "get PROPERTY_NAME",
 	Short: "Gets the value of a configuration property from the Minishift configuration file.",
@@ -35,10 +39,33 @@ var configGetCmd = &cobra.Command{
 			atexit.ExitWithMessage(1, "usage: minishift config get PROPERTY_NAME")
 		}
 
+		if showOrigin {
+			value, err := getWithOrigin(cmd, args[0])
+			if err != nil {
+				atexit.ExitWithMessage(1, err.Error())
+			}
+			if !revealSecret {
+				value = RedactedValue(args[0], value).(*ConfigValue)
+			} else if value.Value, err = reveal(value.Value); err != nil {
+				atexit.ExitWithMessage(1, err.Error())
+			}
+			fmt.Fprintln(os.Stdout, value)
+			return
+		}
+
 		val, err := get(args[0])
 		if err != nil {
 			fmt.Fprintln(os.Stdout, err)
 		}
+		if revealSecret {
+			var revealed interface{}
+			if revealed, err = reveal(val); err != nil {
+				atexit.ExitWithMessage(1, err.Error())
+			}
+			val = revealed.(string)
+		} else if val != unsetValue {
+			val = fmt.Sprintf("%v", RedactedValue(args[0], val))
+		}
 		if val != "" {
 			fmt.Fprintln(os.Stdout, val)
 		}
@@ -48,6 +75,16 @@ var configGetCmd = &cobra.Command{
 func init() {
 	ConfigCmd.AddCommand(configGetCmd)
 	configGetCmd.Flags().BoolVar(&global, "global", false, "Get the value of a configuration property in the global configuration file.")
+	configGetCmd.Flags().BoolVar(&revealSecret, "reveal", false, "Show the decrypted value of a secret property instead of redacting it.")
+	configGetCmd.Flags().BoolVar(&showOrigin, "show-origin", false, "Show the effective value of the property and whether it originates from a flag, an environment variable, the profile or the global configuration file or the default.")
+}
+
+// reveal decrypts encrypted values
+func reveal(value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok {
+		return secret.Decrypt(s)
+	}
+	return value, nil
 }
 
 func get(name string) (string, error) {
@@ -61,3 +98,20 @@ func get(name string) (string, error) {
 	}
 	return fmt.Sprintf("%v", m[name]), nil
 }
+
+// getWithOrigin returns the effective value of the property. With --global, only the global configuration is considered.
+func getWithOrigin(cmd *cobra.Command, name string) (*ConfigValue, error) {
+	if !global {
+		return EffectiveValue(cmd.Flags(), name)
+	}
+
+	m, err := config.ReadViperConfig(constants.GlobalConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := m[name]; ok {
+		return &ConfigValue{Value: value, Origin: OriginGlobal, Source: constants.GlobalConfigFile}, nil
+	}
+	value, _ := FlagDefault(name)
+	return &ConfigValue{Value: value, Origin: OriginDefault}, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/spf13/pflag"
+)
+
+// The layers a configuration value can originate from, in order of precedence
+const (
+	OriginFlag    = "flag"
+	OriginEnv     = "env"
+	OriginProfile = "profile"
+	OriginGlobal  = "global"
+	OriginDefault = "default"
+)
+
+// FlagDefault returns the default value of the command line flag corresponding to a configuration property.
+// It is set by the root command, which knows the flags of all commands.
+var FlagDefault = func(name string) (string, bool) {
+	return "", false
+}
+
+// ConfigValue is the effective value of a configuration property together with the layer it originates from.
+type ConfigValue struct {
+	Value  interface{} `json:"value" yaml:"value"`
+	Origin string      `json:"origin" yaml:"origin"`
+	// Source is the flag, environment variable or configuration file the value is read from
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// Location describes where the value is read from, e.g. 'env:MINISHIFT_MEMORY'
+func (v ConfigValue) Location() string {
+	if v.Source == "" {
+		return v.Origin
+	}
+	return fmt.Sprintf("%s:%s", v.Origin, v.Source)
+}
+
+func (v ConfigValue) String() string {
+	return fmt.Sprintf("%s\t%v", v.Location(), v.Value)
+}
+
+// EffectiveValue resolves the value of the configuration property with the specified name. Flags set on the command
+// line take precedence over environment variables, which take precedence over the profile configuration, which in
+// turn takes precedence over the global configuration. If the property is not set in any layer, the default value
+// of the corresponding flag is returned.
+func EffectiveValue(flags *pflag.FlagSet, name string) (*ConfigValue, error) {
+	if flags != nil {
+		if flag := flags.Lookup(name); flag != nil && flag.Changed {
+			return &ConfigValue{Value: flag.Value.String(), Origin: OriginFlag, Source: "--" + name}, nil
+		}
+	}
+
+	envName := envVariableName(name)
+	if value, ok := os.LookupEnv(envName); ok {
+		return &ConfigValue{Value: value, Origin: OriginEnv, Source: envName}, nil
+	}
+
+	layers := []struct {
+		origin string
+		file   string
+	}{
+		{OriginProfile, constants.ConfigFile},
+		{OriginGlobal, constants.GlobalConfigFile},
+	}
+	for _, layer := range layers {
+		cfg, err := config.ReadViperConfig(layer.file)
+		if err != nil {
+			return nil, err
+		}
+		if value, ok := cfg[name]; ok {
+			return &ConfigValue{Value: value, Origin: layer.origin, Source: layer.file}, nil
+		}
+	}
+
+	if value, ok := FlagDefault(name); ok {
+		return &ConfigValue{Value: value, Origin: OriginDefault}, nil
+	}
+	return &ConfigValue{Value: "", Origin: OriginDefault}, nil
+}
+
+// envVariableName returns the environment variable which sets the property, e.g. MINISHIFT_VM_DRIVER for vm-driver
+func envVariableName(name string) string {
+	return constants.MiniShiftEnvPrefix + "_" + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
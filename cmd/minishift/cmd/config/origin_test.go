@@ -0,0 +1,76 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffectiveValueHonoursPrecedence(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-config-origin-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	origConfigFile, origGlobalConfigFile := constants.ConfigFile, constants.GlobalConfigFile
+	constants.ConfigFile = filepath.Join(testDir, "config.json")
+	constants.GlobalConfigFile = filepath.Join(testDir, "global.json")
+	defer func() {
+		constants.ConfigFile, constants.GlobalConfigFile = origConfigFile, origGlobalConfigFile
+	}()
+
+	origFlagDefault := FlagDefault
+	FlagDefault = func(name string) (string, bool) { return "2GB", name == "memory" }
+	defer func() { FlagDefault = origFlagDefault }()
+
+	flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+	flags.String("memory", "2GB", "")
+
+	assertOrigin(t, flags, OriginDefault, "", "2GB")
+
+	assert.NoError(t, ioutil.WriteFile(constants.GlobalConfigFile, []byte(`{"memory": "8GB"}`), 0644))
+	assertOrigin(t, flags, OriginGlobal, constants.GlobalConfigFile, "8GB")
+
+	assert.NoError(t, ioutil.WriteFile(constants.ConfigFile, []byte(`{"memory": "4GB"}`), 0644))
+	assertOrigin(t, flags, OriginProfile, constants.ConfigFile, "4GB")
+
+	os.Setenv("MINISHIFT_MEMORY", "6GB")
+	defer os.Unsetenv("MINISHIFT_MEMORY")
+	assertOrigin(t, flags, OriginEnv, "MINISHIFT_MEMORY", "6GB")
+
+	assert.NoError(t, flags.Parse([]string{"--memory", "10GB"}))
+	assertOrigin(t, flags, OriginFlag, "--memory", "10GB")
+}
+
+func TestConfigValueLocation(t *testing.T) {
+	assert.Equal(t, "env:MINISHIFT_VM_DRIVER\tkvm", ConfigValue{Value: "kvm", Origin: OriginEnv, Source: envVariableName("vm-driver")}.String())
+	assert.Equal(t, "default\t", ConfigValue{Value: "", Origin: OriginDefault}.String())
+}
+
+func assertOrigin(t *testing.T, flags *pflag.FlagSet, origin string, source string, value interface{}) {
+	actual, err := EffectiveValue(flags, "memory")
+	assert.NoError(t, err)
+	assert.Equal(t, origin, actual.Origin)
+	assert.Equal(t, source, actual.Source)
+	assert.Equal(t, value, actual.Value)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/minishift/minishift/pkg/minishift/preset"
+	"github.com/spf13/pflag"
+)
+
+// ValidatePreset returns an error if the preset contains an unknown configuration property or an invalid value.
+func ValidatePreset(p *preset.Preset) error {
+	values := p.Values()
+	for _, name := range sortedNames(values) {
+		s, err := findSetting(name)
+		if err != nil {
+			return fmt.Errorf("Invalid preset '%s': %v", p.Name, err)
+		}
+		if err := run(name, values[name], s.validations); err != nil {
+			return fmt.Errorf("Invalid value '%s' of %s in preset '%s': %v", values[name], name, p.Name, err)
+		}
+	}
+	return nil
+}
+
+// ApplyPreset sets the configuration properties of the preset, overriding the persistent configuration. Properties
+// set by a flag on the command line or by a MINISHIFT_<KEY> environment variable keep their value.
+func ApplyPreset(p *preset.Preset, flags *pflag.FlagSet) error {
+	if err := ValidatePreset(p); err != nil {
+		return err
+	}
+
+	values := p.Values()
+	for _, name := range sortedNames(values) {
+		if flag := flags.Lookup(name); flag != nil && flag.Changed {
+			continue
+		}
+		if _, ok := EnvOverride(name); ok {
+			continue
+		}
+		s, _ := findSetting(name)
+		if err := applyValue(s, values[name]); err != nil {
+			return fmt.Errorf("Invalid value '%s' of %s in preset '%s': %v", values[name], name, p.Name, err)
+		}
+	}
+	return nil
+}
+
+func sortedNames(values map[string]string) []string {
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
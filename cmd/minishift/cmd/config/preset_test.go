@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/preset"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPreset(t *testing.T) {
+	defer viper.Reset()
+	defer setEnv(map[string]string{"MINISHIFT_DISK_SIZE": "30GB"})()
+
+	flags := pflag.NewFlagSet("start", pflag.ContinueOnError)
+	flags.Int(CPUs.Name, 2, "")
+	flags.String(Memory.Name, "4GB", "")
+	viper.BindPFlags(flags)
+	assert.NoError(t, flags.Parse([]string{"--cpus", "8"}))
+	assert.Empty(t, ApplyEnvOverrides())
+
+	p, err := preset.Parse("test", []byte("config:\n  cpus: 4\n  memory: 6GB\n  disk-size: 50GB\n  insecure-registry: [hub.foo.com, hub.bar.com]\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, ApplyPreset(p, flags))
+
+	assert.Equal(t, 8, viper.GetInt(CPUs.Name), "Flags take precedence over the preset")
+	assert.Equal(t, "30GB", viper.GetString(DiskSize.Name), "Environment variables take precedence over the preset")
+	assert.Equal(t, "6GB", viper.GetString(Memory.Name))
+	assert.Equal(t, []string{"hub.foo.com", "hub.bar.com"}, viper.GetStringSlice(InsecureRegistry.Name))
+}
+
+func TestBuiltinPresetsAreValid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minishift-test-presets-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(dir)
+
+	presets, err := preset.List(dir)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, presets)
+	for _, p := range presets {
+		assert.NoError(t, ValidatePreset(p), p.Name)
+	}
+}
+
+func TestInvalidPreset(t *testing.T) {
+	p, _ := preset.Parse("test", []byte("config:\n  no-such-property: true\n"))
+	assert.EqualError(t, ValidatePreset(p), "Invalid preset 'test': Cannot find property name 'no-such-property'")
+
+	p, _ = preset.Parse("test", []byte("config:\n  cpus: many\n"))
+	err := ValidatePreset(p)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid value 'many' of cpus in preset 'test'")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resetForce bool
+
+	configResetCmd = &cobra.Command{
+		Use:   "reset",
+		Short: "Clears all configuration properties of the profile or the global configuration.",
+		Long: `Clears all configuration properties of the active profile, or of the profile selected by --profile, so that the
+defaults apply again. With --global, the global configuration is cleared instead. The value can be overwritten at
+runtime by flags or environment variables.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			confFile := constants.ConfigFile
+			scope := fmt.Sprintf("profile '%s'", constants.ProfileName)
+			if global {
+				confFile = constants.GlobalConfigFile
+				scope = "global configuration"
+			}
+			if !global && !resetForce && profile.IsProtected(constants.ProfileName) {
+				atexit.ExitWithMessage(1, fmt.Sprintf("Profile '%s' is protected. Use --force to reset its configuration.", constants.ProfileName))
+			}
+			if !resetForce && !util.AskForConfirmation(fmt.Sprintf("Resetting all configuration properties of the %s.", scope)) {
+				atexit.Exit(0)
+			}
+
+			names, err := reset(confFile)
+			if err != nil {
+				atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+			}
+			fmt.Println(fmt.Sprintf("Reset %d configuration properties of the %s", len(names), scope))
+		},
+	}
+)
+
+func init() {
+	ConfigCmd.AddCommand(configResetCmd)
+	configResetCmd.Flags().BoolVar(&global, "global", false, "Clears the global configuration file instead of the configuration of the profile.")
+	configResetCmd.Flags().BoolVarP(&resetForce, "force", "f", false, "Clears the configuration without asking for confirmation.")
+}
+
+// reset removes all configuration properties from the configuration file and returns their names. Entries which are
+// not configuration properties, for example those Minishift maintains itself, are kept.
+func reset(confFile string) ([]string, error) {
+	m, err := config.ReadViperConfig(confFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, name := range SettingNames() {
+		if _, ok := m[name]; ok {
+			delete(m, name)
+			names = append(names, name)
+		}
+	}
+	return names, config.WriteViperConfig(confFile, m)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	validations "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/secret"
+	"github.com/spf13/viper"
+)
+
+const redactedPassword = "*****"
+
+// createSecretSetting creates a setting whose values are encrypted by the set function and redacted for display
+func createSecretSetting(name string, set func(validations.ViperConfig, string, string) error, validations []setFn, redact func(string) string) *Setting {
+	s := createConfigSetting(name, set, validations, nil, true, nil)
+	s.redact = redact
+	settingsList[len(settingsList)-1].redact = redact
+	return s
+}
+
+// SetSecret encrypts the value before storing it.
+func SetSecret(m validations.ViperConfig, name string, val string) error {
+	if val == "" {
+		m[name] = val
+		return nil
+	}
+
+	encrypted, err := secret.Encrypt(val)
+	if err != nil {
+		return fmt.Errorf("Error encrypting the value of '%s': %v", name, err)
+	}
+	m[name] = encrypted
+	return nil
+}
+
+// SetCredentialURL stores the URL, encrypting it if it contains a password.
+func SetCredentialURL(m validations.ViperConfig, name string, val string) error {
+	if u, err := url.Parse(val); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return SetSecret(m, name, val)
+		}
+	}
+	return SetString(m, name, val)
+}
+
+// redactCredentialURL masks the password of a URL. Encrypted URLs are redacted as a whole.
+func redactCredentialURL(value string) string {
+	if secret.IsEncrypted(value) {
+		return secret.Redact(value)
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, hasPassword := u.User.Password(); !hasPassword {
+		return value
+	}
+	u.User = url.UserPassword(u.User.Username(), redactedPassword)
+	// keep the mask readable instead of percent-encoding it
+	unescaped, err := url.PathUnescape(u.String())
+	if err != nil {
+		return u.String()
+	}
+	return unescaped
+}
+
+// RedactedValue returns the value of the property as it may be displayed, for example by 'config view'. Values of
+// secret properties are replaced, so that the output can be shared, for example as part of a support request.
+func RedactedValue(name string, value interface{}) interface{} {
+	s, err := findSetting(name)
+	if err != nil || s.redact == nil {
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		return s.redact(v)
+	case *ConfigValue:
+		redacted := *v
+		redacted.Value = RedactedValue(name, v.Value)
+		return &redacted
+	}
+	return value
+}
+
+// IsVisibleToAddOns returns true if the property may be used by add-ons for interpolation, which is the case for all
+// configuration properties except for secrets.
+func IsVisibleToAddOns(name string) bool {
+	s, err := findSetting(name)
+	return err == nil && s.redact == nil
+}
+
+// redactConfig returns a copy of the configuration with the values of all secret properties redacted
+func redactConfig(cfg validations.ViperConfig) validations.ViperConfig {
+	redacted := make(validations.ViperConfig)
+	for k, v := range cfg {
+		redacted[k] = RedactedValue(k, v)
+	}
+	return redacted
+}
+
+// DecryptSecrets decrypts the values of the given secret properties read from the configuration files, so that the
+// command using them sees the plain values. Values set via flags or environment variables are never encrypted.
+// Only the commands which use a secret decrypt it, so that a value which cannot be decrypted does not prevent
+// other commands, for example 'config unset', from running.
+func DecryptSecrets(settings ...*Setting) error {
+	for _, s := range settings {
+		value, ok := viper.Get(s.Name).(string)
+		if !ok || !secret.IsEncrypted(value) {
+			continue
+		}
+
+		decrypted, err := secret.Decrypt(value)
+		if err != nil {
+			viper.Set(s.Name, "")
+			return fmt.Errorf("Error decrypting the value of '%s': %v", s.Name, err)
+		}
+		viper.Set(s.Name, decrypted)
+	}
+	return nil
+}
+
+// EncryptPlaintextSecrets encrypts the values of secret properties which were stored in plain text in the given
+// configuration files, for example by a Minishift version which did not encrypt them yet. It is run by 'config set'.
+func EncryptPlaintextSecrets(configFiles ...string) error {
+	for _, configFile := range configFiles {
+		if info, err := os.Stat(configFile); err != nil || info.Size() == 0 {
+			continue
+		}
+
+		cfg, err := validations.ReadViperConfig(configFile)
+		if err != nil {
+			return err
+		}
+		changed := false
+		for _, s := range settingsList {
+			value, ok := cfg[s.Name].(string)
+			if s.redact == nil || !ok || value == "" || secret.IsEncrypted(value) {
+				continue
+			}
+			if err := s.set(cfg, s.Name, value); err != nil {
+				return err
+			}
+			changed = changed || cfg[s.Name] != value
+		}
+		if !changed {
+			continue
+		}
+		if err := validations.WriteViperConfig(configFile, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
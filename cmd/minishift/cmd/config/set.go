@@ -17,6 +17,8 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
+
 	"github.com/minishift/minishift/pkg/minikube/constants"
 	"github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
@@ -32,9 +34,15 @@ These values can be overwritten by flags or environment variables at runtime.`,
 		if len(args) != 2 {
 			atexit.ExitWithMessage(1, "usage: minishift config set PROPERTY_NAME PROPERTY_VALUE")
 		}
+		if err := checkProtection(args[0]); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
 		err := Set(args[0], args[1], true)
 		if err != nil {
-			atexit.ExitWithMessage(1, err.Error())
+			atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+		}
+		if err := EncryptPlaintextSecrets(constants.GlobalConfigFile, constants.ConfigFile); err != nil {
+			fmt.Println(fmt.Sprintf("   WARN: Error encrypting the secrets stored in plain text: %v", err))
 		}
 	},
 }
@@ -42,6 +50,7 @@ These values can be overwritten by flags or environment variables at runtime.`,
 func init() {
 	ConfigCmd.AddCommand(configSetCmd)
 	configSetCmd.Flags().BoolVar(&global, "global", false, "Sets the value of a configuration property in the global configuration file.")
+	configSetCmd.Flags().BoolVarP(&force, "force", "f", false, "Sets the value also if the profile is protected.")
 }
 
 func Set(name string, value string, runCallback bool) error {
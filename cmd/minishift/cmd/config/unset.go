@@ -19,22 +19,28 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/minishift/minishift/pkg/minikube/constants"
 	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
 )
 
 var configUnsetCmd = &cobra.Command{
-	Use:   "unset PROPERTY_NAME",
-	Short: "Clears the value of a configuration property in the Minishift configuration file.",
-	Long:  "Clears the value of a configuration property in the Minishift configuration file. The value can be overwritten at runtime by flags or environment variables",
+	Use:   "unset PROPERTY_NAME [PROPERTY_NAME ...]",
+	Short: "Clears the value of one or more configuration properties in the Minishift configuration file.",
+	Long: `Clears the value of one or more configuration properties in the Minishift configuration file. The value can be overwritten at runtime by flags or environment variables.
+A single Docker daemon option is removed with docker-opt=<flag>, for example docker-opt=log-driver or docker-opt=log-opt=max-size.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) != 1 {
-			atexit.ExitWithMessage(1, "usage: minishift config unset PROPERTY_NAME")
+		if len(args) == 0 {
+			atexit.ExitWithMessage(1, "usage: minishift config unset PROPERTY_NAME [PROPERTY_NAME ...]")
 		}
-		err := unset(args[0])
+		if err := checkProtection(args...); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+		err := unset(args...)
 		if err != nil {
 			fmt.Fprintln(os.Stdout, err)
 		}
@@ -44,12 +50,19 @@ var configUnsetCmd = &cobra.Command{
 func init() {
 	ConfigCmd.AddCommand(configUnsetCmd)
 	configUnsetCmd.Flags().BoolVar(&global, "global", false, "Unset the value of a configuration property in the global configuration file.")
+	configUnsetCmd.Flags().BoolVarP(&force, "force", "f", false, "Unset the value also if the profile is protected.")
 }
 
-func unset(name string) error {
-	_, err := findSetting(name)
-	if err != nil {
-		return err
+// unset clears the properties in the configuration file. All names are validated before the file is changed.
+// A name in the form docker-opt=<key> only removes the Docker daemon options matching the key.
+func unset(names ...string) error {
+	for _, name := range names {
+		if _, ok := dockerOptKey(name); ok {
+			continue
+		}
+		if _, err := findSetting(name); err != nil {
+			return err
+		}
 	}
 	confFile := constants.ConfigFile
 	if global {
@@ -59,10 +72,40 @@ func unset(name string) error {
 	if err != nil {
 		return err
 	}
-	if m[name] == nil {
-		return fmt.Errorf("Property name '%s' is not set", name)
+
+	errors := util.MultiError{}
+	// the messages are only printed once the configuration file is written
+	var messages []string
+	for _, name := range names {
+		if key, ok := dockerOptKey(name); ok {
+			if err := unsetDockerOpt(m, DockerEngineOpt.Name, key); err != nil {
+				errors.Collect(err)
+				continue
+			}
+			messages = append(messages, fmt.Sprintf("Docker option '%s' successfully unset", key))
+			continue
+		}
+		if m[name] == nil {
+			errors.Collect(fmt.Errorf("Property name '%s' is not set", name))
+			continue
+		}
+		delete(m, name)
+		messages = append(messages, fmt.Sprintf("Property name '%s' successfully unset", name))
+	}
+	if err := config.WriteViperConfig(confFile, m); err != nil {
+		return err
+	}
+	for _, message := range messages {
+		fmt.Println(message)
+	}
+	return errors.ToError()
+}
+
+// dockerOptKey returns the key of the Docker daemon options to remove if the name is in the form docker-opt=<key>
+func dockerOptKey(name string) (string, bool) {
+	prefix := DockerEngineOpt.Name + "="
+	if !strings.HasPrefix(name, prefix) || name == prefix {
+		return "", false
 	}
-	delete(m, name)
-	fmt.Printf("Property name '%s' successfully unset\n", name)
-	return config.WriteViperConfig(confFile, m)
+	return strings.TrimPrefix(name, prefix), true
 }
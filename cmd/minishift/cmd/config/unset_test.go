@@ -0,0 +1,98 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsetMultipleProperties(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-config-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+	constants.ConfigFile = filepath.Join(testDir, "config.json")
+
+	persistValue(t, "cpus", "4")
+	persistValue(t, "memory", "6GB")
+	persistValue(t, "disk-size", "30GB")
+
+	assert.EqualError(t, unset("cpus", "no-such-property"), "Cannot find property name 'no-such-property'")
+	verifyStoredValue(t, "cpus", "4")
+
+	assert.EqualError(t, unset("cpus", "vm-driver", "memory"), "Property name 'vm-driver' is not set")
+	verifyValueUnset(t, "cpus")
+	verifyValueUnset(t, "memory")
+	verifyStoredValue(t, "disk-size", "30GB")
+}
+
+func TestUnsetSingleDockerOption(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-config-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+	constants.ConfigFile = filepath.Join(testDir, "config.json")
+
+	persistValue(t, "docker-opt", "log-driver=json-file")
+	persistValue(t, "docker-opt", "log-opt=max-size=10m")
+
+	assert.EqualError(t, unset("docker-opt=live-restore"), "Docker option 'live-restore' is not set")
+	assert.NoError(t, unset("docker-opt=log-driver"))
+	verifyStoredValue(t, "docker-opt", "[log-opt=max-size=10m]")
+
+	assert.NoError(t, unset("docker-opt=log-opt"))
+	verifyValueUnset(t, "docker-opt")
+}
+
+func TestReset(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-config-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+	constants.ConfigFile = filepath.Join(testDir, "config.json")
+
+	assert.NoError(t, config.WriteViperConfig(constants.ConfigFile, config.ViperConfig{"cpus": 4, "memory": "6GB", "internal": true}))
+
+	names, err := reset(constants.ConfigFile)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"cpus", "memory"}, names)
+
+	m, err := config.ReadViperConfig(constants.ConfigFile)
+	assert.NoError(t, err)
+	assert.Equal(t, config.ViperConfig{"internal": true}, m, "Entries which are not configuration properties are kept")
+}
+
+func TestDestructiveChangesOfProtectedProfileRequireForce(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-config-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+	defer func(c *config.GlobalConfigType) { config.AllInstancesConfig = c }(config.AllInstancesConfig)
+	config.AllInstancesConfig, err = config.NewAllInstancesConfig(filepath.Join(testDir, "allinstances.json"))
+	assert.NoError(t, err)
+	config.AllInstancesConfig.ProtectedProfiles = []string{constants.ProfileName}
+
+	assert.NoError(t, checkProtection("addon-env"))
+	assert.Error(t, checkProtection("addon-env", "vm-driver"))
+
+	force = true
+	defer func() { force = false }()
+	assert.NoError(t, checkProtection("vm-driver"))
+}
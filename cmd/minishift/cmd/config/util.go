@@ -26,7 +26,12 @@ import (
 
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minikube/constants"
 	viperConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/minishift/profile"
+	stringUtils "github.com/minishift/minishift/pkg/util/strings"
 )
 
 // Runs all the validation or callback functions and collects errors
@@ -45,6 +50,30 @@ func run(name string, value string, fns []setFn) error {
 	}
 }
 
+// checkProtection returns an error if the profile is protected and one of the properties is destructive, unless the
+// change is forced or affects the global configuration.
+func checkProtection(names ...string) error {
+	if global || force || !profile.IsProtected(constants.ProfileName) {
+		return nil
+	}
+	for _, name := range names {
+		if stringUtils.Contains(destructiveSettings, name) {
+			return fmt.Errorf("Profile '%s' is protected. Use --force to change '%s' or 'minishift profile unlock %s' to remove the protection.", constants.ProfileName, name, constants.ProfileName)
+		}
+	}
+	return nil
+}
+
+// isValidTemplateSourceSlice checks that each entry is either an HTTP(S) URL or an existing file or directory
+func isValidTemplateSourceSlice(_ string, entries string) error {
+	for _, entry := range strings.Split(entries, ",") {
+		if err := openshift.ValidateTemplateSource(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func findSetting(name string) (Setting, error) {
 	for _, s := range settingsList {
 		if name == s.Name {
@@ -70,7 +99,14 @@ func SetInt(m viperConfig.ViperConfig, name string, val string) error {
 	return nil
 }
 
+// SetBool accepts the values understood by strconv.ParseBool as well as 'on' and 'off'.
 func SetBool(m viperConfig.ViperConfig, name string, val string) error {
+	switch strings.ToLower(val) {
+	case "on":
+		val = "true"
+	case "off":
+		val = "false"
+	}
 	b, err := strconv.ParseBool(val)
 	if err != nil {
 		return err
@@ -90,6 +126,44 @@ func SetSlice(m viperConfig.ViperConfig, name string, val string) error {
 	return nil
 }
 
+// SetDockerOpt merges the given Docker daemon option into the configured ones, so that 'config set docker-opt'
+// can be repeated to add options and to replace the value of an already configured option
+func SetDockerOpt(m viperConfig.ViperConfig, name string, val string) error {
+	var added []string
+	if val = strings.TrimSpace(val); val != "" {
+		added = append(added, val)
+	}
+	m[name] = docker.MergeOptions(toStringSlice(m[name]), added)
+	return nil
+}
+
+// unsetDockerOpt removes the Docker daemon options matching the key from the configured ones
+func unsetDockerOpt(m viperConfig.ViperConfig, name string, key string) error {
+	remaining, removed := docker.RemoveOptions(toStringSlice(m[name]), key)
+	if !removed {
+		return fmt.Errorf("Docker option '%s' is not set", key)
+	}
+	if len(remaining) == 0 {
+		delete(m, name)
+	} else {
+		m[name] = remaining
+	}
+	return nil
+}
+
+func toStringSlice(value interface{}) []string {
+	var slice []string
+	switch current := value.(type) {
+	case []string:
+		slice = current
+	case []interface{}:
+		for _, v := range current {
+			slice = append(slice, fmt.Sprint(v))
+		}
+	}
+	return slice
+}
+
 func RequiresRestartMsg(name string, value string) error {
 	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
 	defer api.Close()
@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"os"
 	"strings"
 	"testing"
 
@@ -70,6 +71,16 @@ func TestSetBool(t *testing.T) {
 	assert.IsType(t, *new(bool), minikubeConfig["show-libmachine-logs"])
 }
 
+func TestSetBoolOnOff(t *testing.T) {
+	err := SetBool(minikubeConfig, "audit-logging", "on")
+	assert.NoError(t, err, "Error setting boolean value in config")
+	assert.Equal(t, true, minikubeConfig["audit-logging"])
+
+	err = SetBool(minikubeConfig, "audit-logging", "Off")
+	assert.NoError(t, err, "Error setting boolean value in config")
+	assert.Equal(t, false, minikubeConfig["audit-logging"])
+}
+
 func TestSetSlice(t *testing.T) {
 	expectedSlice := []string{"172.0.0.1/16", "mycustom.registry.com/3030"}
 	err := SetSlice(minikubeConfig, "insecure-registry", strings.Join(expectedSlice, ","))
@@ -78,3 +89,31 @@ func TestSetSlice(t *testing.T) {
 	assert.IsType(t, *new([]string), minikubeConfig["insecure-registry"])
 	assert.Equal(t, expectedSlice, val)
 }
+
+func TestSetDockerOpt(t *testing.T) {
+	minikubeConfig["docker-opt"] = []interface{}{"log-driver=journald", "default-ulimit=nofile=1024:2048"}
+
+	err := SetDockerOpt(minikubeConfig, "docker-opt", "log-driver=json-file")
+	assert.NoError(t, err, "Error setting docker-opt")
+	err = SetDockerOpt(minikubeConfig, "docker-opt", "default-ulimit=nproc=512")
+	assert.NoError(t, err, "Error setting docker-opt")
+	assert.Equal(t, []string{"log-driver=json-file", "default-ulimit=nofile=1024:2048", "default-ulimit=nproc=512"}, minikubeConfig["docker-opt"])
+
+	err = SetDockerOpt(minikubeConfig, "docker-opt", "default-ulimit=nofile=4096:8192")
+	assert.NoError(t, err, "Error setting docker-opt")
+	assert.Equal(t, []string{"log-driver=json-file", "default-ulimit=nofile=4096:8192", "default-ulimit=nproc=512"}, minikubeConfig["docker-opt"])
+
+	err = SetDockerOpt(minikubeConfig, "docker-opt", "log-opt=labels=a,b")
+	assert.NoError(t, err, "Error setting docker-opt")
+	assert.Contains(t, minikubeConfig["docker-opt"], "log-opt=labels=a,b")
+
+	assert.NoError(t, unsetDockerOpt(minikubeConfig, "docker-opt", "default-ulimit=nproc"))
+	assert.Equal(t, []string{"log-driver=json-file", "default-ulimit=nofile=4096:8192", "log-opt=labels=a,b"}, minikubeConfig["docker-opt"])
+	assert.Error(t, unsetDockerOpt(minikubeConfig, "docker-opt", "live-restore"))
+}
+
+func TestIsValidTemplateSourceSlice(t *testing.T) {
+	assert.NoError(t, isValidTemplateSourceSlice("extra-templates", "https://example.com/templates/jenkins.json"))
+	assert.NoError(t, isValidTemplateSourceSlice("extra-templates", os.TempDir()))
+	assert.Error(t, isValidTemplateSourceSlice("extra-templates", "https://example.com/templates/jenkins.json,/does/not/exist"))
+}
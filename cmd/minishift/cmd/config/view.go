@@ -18,6 +18,7 @@ package config
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -28,18 +29,29 @@ import (
 	"github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 )
 
 const (
 	DefaultConfigViewFormat = "- {{.ConfigKey | printf \"%-35s\"}}: {{.ConfigValue}}"
+	// DefaultConfigViewOriginFormat is the default format used with --show-origin
+	DefaultConfigViewOriginFormat = "- {{.ConfigKey | printf \"%-35s\"}}: {{.ConfigValue}} ({{.Origin}})"
+
+	jsonOutput = "json"
+	yamlOutput = "yaml"
 )
 
-var configViewFormat string
+var (
+	configViewFormat string
+	configViewOutput string
+)
 var excludedConfigKeys = make(map[string]interface{})
 
 type ConfigViewTemplate struct {
 	ConfigKey   string
 	ConfigValue interface{}
+	// Origin is the location the value originates from. It is only set with --show-origin.
+	Origin string
 }
 
 var configViewCmd = &cobra.Command{
@@ -47,14 +59,33 @@ var configViewCmd = &cobra.Command{
 	Short: "Display the properties and values of the Minishift configuration file.",
 	Long:  "Display the properties and values of the Minishift configuration file. You can set the output format from one of the available Go templates.",
 	Run: func(cmd *cobra.Command, args []string) {
-		confFile := constants.ConfigFile
-		if global {
-			confFile = constants.GlobalConfigFile
+		var cfg config.ViperConfig
+		var err error
+		if showOrigin && !global {
+			cfg, err = effectiveConfig(cmd)
+		} else {
+			confFile := constants.ConfigFile
+			if global {
+				confFile = constants.GlobalConfigFile
+			}
+			cfg, err = config.ReadViperConfig(confFile)
 		}
-		cfg, err := config.ReadViperConfig(confFile)
 		if err != nil {
 			atexit.ExitWithMessage(1, err.Error())
 		}
+		cfg = redactConfig(cfg)
+		if configViewOutput != "" {
+			if cmd.Flags().Changed("format") {
+				atexit.ExitWithReason(atexit.ConfigurationError, "The flags '--format' and '--output' cannot be used together.")
+			}
+			if err = printStructured(viewableConfig(cfg), configViewOutput, os.Stdout); err != nil {
+				atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+			}
+			return
+		}
+		if showOrigin && !cmd.Flags().Changed("format") {
+			configViewFormat = DefaultConfigViewOriginFormat
+		}
 		template := determineTemplate(configViewFormat)
 		if err = configView(cfg, template, os.Stdout); err != nil {
 			atexit.ExitWithMessage(1, err.Error())
@@ -70,7 +101,56 @@ func init() {
 		`Go template format to apply to the configuration file. For more information about Go templates, see: https://golang.org/pkg/text/template/
 		For the list of configurable variables for the template, see the struct values section of ConfigViewTemplate at: https://godoc.org/github.com/minishift/minishift/cmd/minishift/cmd/config#ConfigViewTemplate`)
 	ConfigCmd.AddCommand(configViewCmd)
+	configViewCmd.Flags().StringVarP(&configViewOutput, "output", "o", "", "Prints the configuration in the specified format instead of using a template. Supported formats: yaml, json")
 	configViewCmd.Flags().BoolVar(&global, "global", false, "View the global configuration properties and values")
+	configViewCmd.Flags().BoolVar(&showOrigin, "show-origin", false, "View the effective values of all properties set by an environment variable, the profile or the global configuration file together with their origin.")
+}
+
+// effectiveConfig returns the effective values of the configurable properties which are set in any configuration layer
+func effectiveConfig(cmd *cobra.Command) (config.ViperConfig, error) {
+	cfg := make(config.ViperConfig)
+	for _, name := range SettingNames() {
+		value, err := EffectiveValue(cmd.Flags(), name)
+		if err != nil {
+			return nil, err
+		}
+		if value.Origin != OriginDefault {
+			cfg[name] = value
+		}
+	}
+	return cfg, nil
+}
+
+// viewableConfig returns the configuration without the excluded keys
+func viewableConfig(cfg config.ViperConfig) config.ViperConfig {
+	viewable := make(config.ViperConfig)
+	for k, v := range cfg {
+		if _, excluded := excludedConfigKeys[k]; !excluded {
+			viewable[k] = v
+		}
+	}
+	return viewable
+}
+
+// printStructured prints the data as YAML or JSON document
+func printStructured(data interface{}, format string, writer io.Writer) error {
+	var out []byte
+	var err error
+	switch format {
+	case yamlOutput:
+		out, err = yaml.Marshal(data)
+	case jsonOutput:
+		out, err = json.MarshalIndent(data, "", "  ")
+		out = append(out, '\n')
+	default:
+		return fmt.Errorf("Unsupported output format '%s'. Supported formats are '%s' and '%s'.", format, yamlOutput, jsonOutput)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(out)
+	return err
 }
 
 func determineTemplate(tempFormat string) (tmpl *template.Template) {
@@ -88,7 +168,13 @@ func configView(cfg config.ViperConfig, tmpl *template.Template, writer io.Write
 		if excluded {
 			continue
 		}
-		viewTmplt := ConfigViewTemplate{k, v}
+		viewTmplt := ConfigViewTemplate{ConfigKey: k, ConfigValue: v}
+		if value, ok := v.(*ConfigValue); ok {
+			viewTmplt.ConfigValue = value.Value
+			viewTmplt.Origin = value.Location()
+		} else if showOrigin {
+			viewTmplt.Origin = OriginGlobal + ":" + constants.GlobalConfigFile
+		}
 		var buffer bytes.Buffer
 		if err := tmpl.Execute(&buffer, viewTmplt); err != nil {
 			return err
@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/minishift/minishift/cmd/testing/cli"
@@ -77,3 +78,17 @@ func TestConfigView(t *testing.T) {
 		assert.Equal(t, tt.expectedString, tee.StdoutBuffer.String())
 	}
 }
+
+func TestPrintStructured(t *testing.T) {
+	cfg := map[string]interface{}{"cpus": 4, "vm-driver": "kvm"}
+
+	var out bytes.Buffer
+	assert.NoError(t, printStructured(cfg, "yaml", &out))
+	assert.Equal(t, "cpus: 4\nvm-driver: kvm\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, printStructured(cfg, "json", &out))
+	assert.Equal(t, "{\n  \"cpus\": 4,\n  \"vm-driver\": \"kvm\"\n}\n", out.String())
+
+	assert.Error(t, printStructured(cfg, "xml", &out))
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/docker/machine/libmachine"
 	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
@@ -34,11 +38,43 @@ var (
 	consoleURLMode    bool
 	machineReadAble   bool
 	requestOauthToken bool
+	consoleOutput     string
+	consoleProject    string
+	consoleView       string
 	machineDetails    = `HOST=%s
 PORT=%d
 CONSOLE_URL=%s`
+
+	// consoleViews maps the views which can be deep-linked via --view to their path below the project page
+	consoleViews = map[string]string{
+		"overview":    "overview",
+		"builds":      "browse/builds",
+		"pipelines":   "browse/pipelines",
+		"deployments": "browse/deployments",
+		"images":      "browse/images",
+		"pods":        "browse/pods",
+		"services":    "browse/services",
+		"routes":      "browse/routes",
+		"storage":     "browse/storage",
+		"config-maps": "browse/config-maps",
+		"secrets":     "browse/secrets",
+		"events":      "browse/events",
+		"monitoring":  "monitoring",
+	}
 )
 
+const jsonOutput = "json"
+
+// consoleDetails is the JSON representation of the console URL printed with '--output json'
+type consoleDetails struct {
+	Host       string `json:"host"`
+	Port       int    `json:"port"`
+	ConsoleURL string `json:"consoleURL"`
+	URL        string `json:"url"`
+	Project    string `json:"project,omitempty"`
+	View       string `json:"view,omitempty"`
+}
+
 // consoleCmd represents the console command
 var consoleCmd = &cobra.Command{
 	Use:     "console",
@@ -46,23 +82,92 @@ var consoleCmd = &cobra.Command{
 	Short:   "Opens or displays the OpenShift Web Console URL.",
 	Long:    `Opens the OpenShift Web Console URL in the default browser or displays it to the console.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if consoleOutput != "" && consoleOutput != jsonOutput {
+			atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("Unsupported output format '%s'. Only '%s' is supported.", consoleOutput, jsonOutput))
+		}
+		if consoleOutput == jsonOutput {
+			atexit.ReportErrorsAsJSON()
+		}
+
 		api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
 		defer api.Close()
 
-		if consoleURLMode {
-			fmt.Fprintln(os.Stdout, getHostUrl(api))
-		} else if machineReadAble {
-			displayConsoleInMachineReadable(getHostIp(api), getHostUrl(api))
-		} else if requestOauthToken {
+		if requestOauthToken {
 			fmt.Fprintln(os.Stdout, "Opening requested token URI in the default browser...")
 			browser.OpenURL(getTokenRequestUrl(api))
+			return
+		}
+
+		consoleURL := getHostUrl(api)
+		link, err := consoleLink(consoleURL, consoleProject, consoleView)
+		if err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+
+		if consoleOutput == jsonOutput {
+			displayConsoleInJson(getHostIp(api), consoleURL, link, consoleProject, consoleView)
+		} else if consoleURLMode {
+			fmt.Fprintln(os.Stdout, link)
+		} else if machineReadAble {
+			displayConsoleInMachineReadable(getHostIp(api), link)
 		} else {
 			fmt.Fprintln(os.Stdout, "Opening the OpenShift Web console in the default browser...")
-			browser.OpenURL(getHostUrl(api))
+			browser.OpenURL(link)
 		}
 	},
 }
 
+// consoleLink returns the URL of the console page for the specified project and view. Without project the URL of
+// the console itself is returned.
+func consoleLink(consoleURL string, project string, view string) (string, error) {
+	if project == "" {
+		if view != "" {
+			return "", fmt.Errorf("The --view flag requires a project to be specified using --project")
+		}
+		return consoleURL, nil
+	}
+
+	if view == "" {
+		view = "overview"
+	}
+	path, ok := consoleViews[view]
+	if !ok {
+		return "", fmt.Errorf("Unknown console view '%s'. Valid views are: %s", view, strings.Join(validConsoleViews(), ", "))
+	}
+
+	return fmt.Sprintf("%s/project/%s/%s", strings.TrimSuffix(consoleURL, "/"), url.PathEscape(project), path), nil
+}
+
+func validConsoleViews() []string {
+	var views []string
+	for view := range consoleViews {
+		views = append(views, view)
+	}
+	sort.Strings(views)
+	return views
+}
+
+func displayConsoleInJson(hostIP string, consoleURL string, link string, project string, view string) {
+	if project != "" && view == "" {
+		view = "overview"
+	}
+
+	details := consoleDetails{
+		Host:       hostIP,
+		Port:       constants.APIServerPort,
+		ConsoleURL: consoleURL,
+		URL:        link,
+		Project:    project,
+		View:       view,
+	}
+
+	out, err := json.MarshalIndent(details, "", "  ")
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error creating JSON output: %v", err))
+	}
+	fmt.Fprintln(os.Stdout, string(out))
+}
+
 func displayConsoleInMachineReadable(hostIP string, url string) {
 	machineDetails = fmt.Sprintf(machineDetails, hostIP, constants.APIServerPort, url)
 	fmt.Fprintln(os.Stdout, machineDetails)
@@ -100,5 +205,8 @@ func init() {
 	consoleCmd.Flags().BoolVar(&consoleURLMode, "url", false, "Prints the OpenShift Web Console URL to the console.")
 	consoleCmd.Flags().BoolVar(&machineReadAble, "machine-readable", false, "Prints OpenShift's IP, port and Web Console URL in Machine readable format")
 	consoleCmd.Flags().BoolVar(&requestOauthToken, "request-oauth-token", false, "Open token request to default web browser")
+	consoleCmd.Flags().StringVarP(&consoleOutput, "output", "o", "", "Prints the OpenShift Web Console details in the specified format. Supported format: json")
+	consoleCmd.Flags().StringVar(&consoleProject, "project", "", "Opens or displays the Web Console page of the specified project.")
+	consoleCmd.Flags().StringVar(&consoleView, "view", "", fmt.Sprintf("Opens or displays the specified view of the project. Valid views are: %s", strings.Join(validConsoleViews(), ", ")))
 	RootCmd.AddCommand(consoleCmd)
 }
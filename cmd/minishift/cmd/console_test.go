@@ -38,3 +38,50 @@ CONSOLE_URL=https://192.168.99.103:8443
 	actualStdout := tee.StdoutBuffer.String()
 	assert.Equal(t, expectedStdout, actualStdout)
 }
+
+func TestDisplayConsoleInJson(t *testing.T) {
+	tee := cli.CreateTee(t, true)
+
+	expectedStdout := `{
+  "host": "192.168.1.1",
+  "port": 8443,
+  "consoleURL": "https://192.168.1.1:8443/console",
+  "url": "https://192.168.1.1:8443/console/project/myproject/overview",
+  "project": "myproject",
+  "view": "overview"
+}
+`
+
+	displayConsoleInJson("192.168.1.1", "https://192.168.1.1:8443/console", "https://192.168.1.1:8443/console/project/myproject/overview", "myproject", "")
+	tee.Close()
+
+	assert.Equal(t, expectedStdout, tee.StdoutBuffer.String())
+}
+
+func TestConsoleLink(t *testing.T) {
+	consoleURL := "https://192.168.1.1:8443/console"
+
+	var testCases = []struct {
+		project     string
+		view        string
+		expectedURL string
+		expectedErr bool
+	}{
+		{"", "", consoleURL, false},
+		{"myproject", "", consoleURL + "/project/myproject/overview", false},
+		{"myproject", "builds", consoleURL + "/project/myproject/browse/builds", false},
+		{"myproject", "monitoring", consoleURL + "/project/myproject/monitoring", false},
+		{"myproject", "foo", "", true},
+		{"", "builds", "", true},
+	}
+
+	for _, testCase := range testCases {
+		link, err := consoleLink(consoleURL, testCase.project, testCase.view)
+		if testCase.expectedErr {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expectedURL, link)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/filetransfer"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cpRecursive bool
+	cpQuiet     bool
+)
+
+// cpCmd represents the cp command
+var cpCmd = &cobra.Command{
+	Use:   "cp SOURCE DESTINATION",
+	Short: "Copies files and directories between the host and the Minishift VM.",
+	Long: `Copies files and directories between the host and the Minishift VM. Paths on the VM are prefixed with the name of the VM, which is the name of the profile, for example 'minishift:/home/docker/'.
+The checksum of each copied file is verified and the permissions of the files are kept. If the destination is an existing directory, the source is copied into it.`,
+	Example: `  minishift cp ./registries.conf minishift:/home/docker/
+  minishift cp -r ./manifests minishift:/home/docker/manifests
+  minishift cp -r minishift:/var/lib/minishift/base/master .`,
+	Run: runCp,
+}
+
+func runCp(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		atexit.ExitWithMessage(1, "Usage: minishift cp SOURCE DESTINATION")
+	}
+
+	source, sourceIsRemote := parseVMPath(args[0], constants.MachineName)
+	destination, destinationIsRemote := parseVMPath(args[1], constants.MachineName)
+	if sourceIsRemote == destinationIsRemote {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Exactly one of source and destination needs to be a path on the VM, prefixed with '%s:'", constants.MachineName))
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	client, err := cluster.NewSSHClient(api)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Cannot establish SSH connection to the VM: %v", err))
+	}
+	defer client.Close()
+
+	options := filetransfer.Options{Recursive: cpRecursive}
+	if !cpQuiet {
+		options.Progress = true
+		options.Out = os.Stdout
+	}
+	transfer := filetransfer.New(client, options)
+
+	var count int
+	if sourceIsRemote {
+		count, err = transfer.FromVM(source, destination)
+	} else {
+		count, err = transfer.ToVM(source, destination)
+	}
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error copying '%s' to '%s': %v", args[0], args[1], err))
+	}
+
+	if !cpQuiet {
+		fmt.Println(fmt.Sprintf("%d file(s) copied", count))
+	}
+}
+
+func init() {
+	cpCmd.Flags().BoolVarP(&cpRecursive, "recursive", "r", false, "Copies directories recursively.")
+	cpCmd.Flags().BoolVarP(&cpQuiet, "quiet", "q", false, "Does not print the copied files and the progress.")
+	RootCmd.AddCommand(cpCmd)
+}
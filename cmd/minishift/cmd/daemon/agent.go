@@ -0,0 +1,194 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"fmt"
+	goos "os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/agent"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/util/os"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	installAgentService   bool
+	uninstallAgentService bool
+
+	daemonStartCmd = &cobra.Command{
+		Use:   "start",
+		Short: "Starts the Minishift agent.",
+		Long: `Starts the Minishift agent of the profile in the background. The agent reconciles changes of the IP of the VM,
+stops the VM after it was idle for the time configured by 'daemon-idle-timeout', restarts terminated tunnels, keeps
+the DNS server running and takes the backups scheduled by 'backup.schedule'.`,
+		Run: startAgent,
+	}
+
+	daemonStopCmd = &cobra.Command{
+		Use:   "stop",
+		Short: "Stops the Minishift agent.",
+		Long:  `Stops the Minishift agent of the profile.`,
+		Run:   stopAgent,
+	}
+
+	daemonStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Displays the status of the Minishift agent.",
+		Long:  `Displays whether the Minishift agent of the profile is running and the outcome of the last run of its tasks.`,
+		Run:   agentStatus,
+	}
+
+	daemonRunCmd = &cobra.Command{
+		Use:    "run",
+		Short:  "Runs the Minishift agent in the foreground.",
+		Long:   `Runs the Minishift agent of the profile in the foreground. Used by 'daemon start' and the service manager of the host.`,
+		Run:    runAgent,
+		Hidden: true,
+	}
+)
+
+func init() {
+	daemonStartCmd.Flags().BoolVar(&installAgentService, "install", false, fmt.Sprintf("Registers the agent with %s, so that it is started at login.", agent.ServiceManager))
+	daemonStopCmd.Flags().BoolVar(&uninstallAgentService, "uninstall", false, fmt.Sprintf("Removes the registration of the agent with %s.", agent.ServiceManager))
+	DaemonCmd.AddCommand(daemonStartCmd)
+	DaemonCmd.AddCommand(daemonStopCmd)
+	DaemonCmd.AddCommand(daemonStatusCmd)
+	DaemonCmd.AddCommand(daemonRunCmd)
+}
+
+func startAgent(cmd *cobra.Command, args []string) {
+	if installAgentService {
+		if agent.IsRunning() && !agent.IsServiceInstalled() {
+			// the service manager takes over, it starts the agent right away
+			if err := agent.Stop(); err != nil {
+				atexit.ExitWithMessage(1, fmt.Sprintf("Error stopping the Minishift agent: %v", err))
+			}
+		}
+
+		executable, err := os.CurrentExecutable()
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error determining the Minishift executable: %v", err))
+		}
+		if err := agent.InstallService(executable); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error registering the Minishift agent with %s: %v", agent.ServiceManager, err))
+		}
+		fmt.Println(fmt.Sprintf("The Minishift agent of profile '%s' is registered with %s.", constants.ProfileName, agent.ServiceManager))
+		return
+	}
+
+	if err := agent.EnsureDaemonRunning(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error starting the Minishift agent: %v", err))
+	}
+	fmt.Println(fmt.Sprintf("The Minishift agent of profile '%s' is running with pid %d.", constants.ProfileName, minishiftConfig.InstanceStateConfig.AgentPID))
+}
+
+func stopAgent(cmd *cobra.Command, args []string) {
+	if agent.IsServiceInstalled() {
+		if !uninstallAgentService {
+			atexit.ExitWithMessage(1, fmt.Sprintf("The Minishift agent is registered with %s, which restarts it. Use '--uninstall' to stop it.", agent.ServiceManager))
+		}
+		if err := agent.UninstallService(); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error removing the registration of the Minishift agent: %v", err))
+		}
+	}
+
+	if err := agent.Stop(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error stopping the Minishift agent: %v", err))
+	}
+	fmt.Println(fmt.Sprintf("The Minishift agent of profile '%s' is stopped.", constants.ProfileName))
+}
+
+func agentStatus(cmd *cobra.Command, args []string) {
+	if !agent.IsRunning() {
+		fmt.Println("Agent:   Stopped")
+	} else {
+		fmt.Println(fmt.Sprintf("Agent:   Running (pid %d)", minishiftConfig.InstanceStateConfig.AgentPID))
+	}
+	if agent.IsServiceInstalled() {
+		fmt.Println(fmt.Sprintf("Service: Registered with %s", agent.ServiceManager))
+	} else {
+		fmt.Println("Service: Not registered")
+	}
+
+	status, err := agent.ReadStatus(minishiftConstants.GetAgentStatusPath())
+	if err != nil || !agent.IsRunning() || status.PID != minishiftConfig.InstanceStateConfig.AgentPID {
+		return
+	}
+	fmt.Println()
+	fmt.Print(formatTaskStatus(status.Tasks, time.Now()))
+}
+
+func formatTaskStatus(tasks []agent.TaskStatus, now time.Time) string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("%-12s %-12s %s", "TASK", "LAST RUN", "RESULT"))
+	for _, task := range tasks {
+		lastRun, result := "-", "-"
+		if !task.LastRun.IsZero() {
+			lastRun = fmt.Sprintf("%s ago", now.Sub(task.LastRun).Round(time.Second))
+			result = "OK"
+		}
+		if task.Error != "" {
+			result = task.Error
+		}
+		lines = append(lines, fmt.Sprintf("%-12s %-12s %s", task.Name, lastRun, result))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func runAgent(cmd *cobra.Command, args []string) {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	// the agent might have been started by the service manager rather than 'daemon start'
+	if err := recordAgentPID(goos.Getpid()); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error recording the pid of the Minishift agent: %v", err))
+	}
+	defer recordAgentPID(0)
+
+	stop := make(chan struct{})
+	signals := make(chan goos.Signal, 1)
+	signal.Notify(signals, goos.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+
+	agent.New(minishiftConstants.GetAgentStatusPath(), agentTasks(api)...).Run(stop)
+}
+
+func recordAgentPID(pid int) error {
+	stateConfig, err := minishiftConfig.NewInstanceStateConfig(minishiftConfig.InstanceStateConfig.FilePath)
+	if err != nil {
+		return err
+	}
+	stateConfig.AgentPID = pid
+	if err := stateConfig.Write(); err != nil {
+		return err
+	}
+	minishiftConfig.InstanceStateConfig = stateConfig
+	return nil
+}
@@ -0,0 +1,326 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"fmt"
+	goos "os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/provision"
+	cmdBackup "github.com/minishift/minishift/cmd/minishift/cmd/backup"
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/agent"
+	"github.com/minishift/minishift/pkg/minishift/backup"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder"
+	minishiftNetwork "github.com/minishift/minishift/pkg/minishift/network"
+	"github.com/minishift/minishift/pkg/minishift/network/dns"
+	"github.com/minishift/minishift/pkg/minishift/network/dockerforward"
+	"github.com/minishift/minishift/pkg/minishift/network/ipwatch"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/util/logging"
+	"github.com/minishift/minishift/pkg/util/os"
+)
+
+var logger = logging.For("daemon")
+
+const (
+	idleCheckInterval   = time.Minute
+	tunnelCheckInterval = 30 * time.Second
+	dnsCheckInterval    = 30 * time.Second
+	backupCheckInterval = 5 * time.Minute
+)
+
+// agentTasks returns the tasks of the agent. Other commands change the instance state while the agent is running,
+// hence each task starts from the current state on disk.
+func agentTasks(api libmachine.API) []agent.Task {
+	idleTracker := agent.NewIdleTracker(0, time.Now())
+
+	return []agent.Task{
+		{
+			Name:     "ip-watch",
+			Interval: ipwatch.DefaultInterval,
+			Run: withCurrentState(func() error {
+				// a separately started IP watcher takes care already
+				if ipwatch.IsRunning() {
+					return nil
+				}
+				// the watcher starts from the last known IP of the current state, which other commands update
+				_, err := util.NewIPWatcher(api).Check()
+				return err
+			}),
+		},
+		{
+			Name:     "idle-stop",
+			Interval: idleCheckInterval,
+			Run: withCurrentState(func() error {
+				return stopIfIdle(api, idleTracker)
+			}),
+		},
+		{
+			Name:     "tunnels",
+			Interval: tunnelCheckInterval,
+			Run: withCurrentState(func() error {
+				return restoreTunnels(api)
+			}),
+		},
+		{
+			Name:     "dns",
+			Interval: dnsCheckInterval,
+			Run: withCurrentState(func() error {
+				return ensureDNSRunning(api)
+			}),
+		},
+		{
+			Name:     "backup",
+			Interval: backupCheckInterval,
+			Run: withCurrentState(func() error {
+				return backupIfDue(api)
+			}),
+		},
+	}
+}
+
+func withCurrentState(task func() error) func() error {
+	return func() error {
+		stateConfig, err := minishiftConfig.NewInstanceStateConfig(minishiftConfig.InstanceStateConfig.FilePath)
+		if err != nil {
+			return err
+		}
+		minishiftConfig.InstanceStateConfig = stateConfig
+
+		instanceConfig, err := minishiftConfig.NewInstanceConfig(minishiftConfig.InstanceConfig.FilePath)
+		if err != nil {
+			return err
+		}
+		minishiftConfig.InstanceConfig = instanceConfig
+
+		allInstancesConfig, err := minishiftConfig.NewAllInstancesConfig(minishiftConfig.AllInstancesConfig.FilePath)
+		if err != nil {
+			return err
+		}
+		minishiftConfig.AllInstancesConfig = allInstancesConfig
+
+		return task()
+	}
+}
+
+// loadRunningHost returns the VM of the profile, or nil if the VM is not running.
+func loadRunningHost(api libmachine.API) (*host.Host, error) {
+	hostVm, err := api.Load(constants.MachineName)
+	if err != nil {
+		return nil, err
+	}
+	if !util.IsHostRunning(hostVm.Driver) {
+		return nil, nil
+	}
+	return hostVm, nil
+}
+
+// stopIfIdle stops the VM once no connections from the host to the API server, the Docker daemon or the router were
+// seen for the configured idle timeout.
+func stopIfIdle(api libmachine.API, tracker *agent.IdleTracker) error {
+	now := time.Now()
+	timeout, err := idleTimeout()
+	if err != nil || timeout == 0 {
+		tracker.Reset(now)
+		return err
+	}
+	tracker.SetTimeout(timeout)
+
+	hostVm, err := loadRunningHost(api)
+	if err != nil || hostVm == nil {
+		tracker.Reset(now)
+		return err
+	}
+
+	hostIP, err := minishiftNetwork.DetermineHostIP(hostVm.Driver)
+	if err != nil || hostIP == "" {
+		return err
+	}
+	out, err := drivers.RunSSHCommandFromDriver(hostVm.Driver, agent.ConnectionsCommand())
+	if err != nil {
+		return err
+	}
+
+	active := agent.CountHostConnections(out, hostIP, agent.ActivityPorts) > 0
+	if !tracker.Observe(active, now) {
+		return nil
+	}
+
+	logger.Infof("Stopping the VM after it was idle for %s", tracker.IdleFor(now).Round(time.Second))
+	tracker.Reset(now)
+	return stopVM()
+}
+
+// stopVM stops the VM the same way 'minishift stop' does.
+func stopVM() error {
+	executable, err := os.CurrentExecutable()
+	if err != nil {
+		return err
+	}
+	out, err := exec.Command(executable, "stop", "--profile", constants.ProfileName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Error stopping the VM: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// idleTimeout returns the configured idle timeout or 0 if the VM is not to be stopped when idle. The configuration is
+// read on each check, so that changes apply without restarting the agent.
+func idleTimeout() (time.Duration, error) {
+	value, err := configuredValue(configCmd.DaemonIdleTimeout.Name)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	return time.ParseDuration(value)
+}
+
+// configuredValue returns the value of the setting from the instance configuration or, if not set there, from the
+// global configuration. It returns an empty string if the setting is not set at all.
+func configuredValue(name string) (string, error) {
+	value := ""
+	for _, configFile := range []string{constants.GlobalConfigFile, constants.ConfigFile} {
+		cfg, err := minishiftConfig.ReadViperConfig(configFile)
+		if err != nil {
+			return "", err
+		}
+		if v, ok := cfg[name]; ok && v != nil && fmt.Sprint(v) != "" {
+			value = fmt.Sprint(v)
+		}
+	}
+	return value, nil
+}
+
+// backupIfDue takes a backup of the cluster data once the interval configured by 'backup.schedule' passed since the
+// last scheduled backup. Afterwards, scheduled backups beyond the number configured by 'backup.retention' are removed.
+func backupIfDue(api libmachine.API) error {
+	schedule, err := configuredValue(configCmd.BackupSchedule.Name)
+	if err != nil || schedule == "" {
+		return err
+	}
+	interval, err := time.ParseDuration(schedule)
+	if err != nil {
+		return err
+	}
+
+	dir := minishiftConstants.GetBackupsDir()
+	backups, err := backup.List(dir, constants.ProfileName)
+	if err != nil {
+		return err
+	}
+	if !backup.Due(backup.Scheduled(backups), interval, time.Now()) {
+		return nil
+	}
+
+	hostVm, err := loadRunningHost(api)
+	if err != nil || hostVm == nil {
+		return err
+	}
+	if !openshift.IsRunning(docker.NewVmDockerCommander(provision.GenericSSHCommander{Driver: hostVm.Driver})) {
+		return nil
+	}
+
+	manifest := backup.Manifest{
+		Profile:          constants.ProfileName,
+		OpenShiftVersion: minishiftConfig.InstanceStateConfig.OpenshiftVersion,
+		Created:          time.Now(),
+		Scheduled:        true,
+	}
+	target := filepath.Join(dir, backup.FileName(manifest.Profile, manifest.Created))
+	if err := goos.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	logger.Infof("Creating scheduled backup %s", target)
+	if err := cmdBackup.CreateBackup(api, hostVm, target, manifest); err != nil {
+		goos.Remove(target)
+		return err
+	}
+
+	retention, err := configuredValue(configCmd.BackupRetention.Name)
+	if err != nil || retention == "" {
+		return err
+	}
+	keep, err := strconv.Atoi(retention)
+	if err != nil {
+		return err
+	}
+	backups, err = backup.List(dir, constants.ProfileName)
+	if err != nil {
+		return err
+	}
+	removed, err := backup.Prune(backups, keep)
+	for _, path := range removed {
+		logger.Infof("Removed scheduled backup %s", path)
+	}
+	return err
+}
+
+// restoreTunnels restarts the sftp tunnel of the sshfs host folders and the forward to the Docker daemon, in case
+// they terminated.
+func restoreTunnels(api libmachine.API) error {
+	hostVm, err := loadRunningHost(api)
+	if err != nil || hostVm == nil {
+		return err
+	}
+
+	manager, err := hostfolder.NewManager(minishiftConfig.InstanceConfig, minishiftConfig.AllInstancesConfig)
+	if err != nil {
+		return err
+	}
+	if restarted, err := manager.EnsureTunnelRunning(hostVm.Driver); err != nil {
+		return err
+	} else if restarted {
+		logger.Infof("Restarted the sftp tunnel of the host folders")
+	}
+
+	if restarted, err := dockerforward.Restore(); err != nil {
+		return err
+	} else if restarted {
+		logger.Infof("Restarted forwarding %s to the Docker daemon", minishiftConfig.InstanceStateConfig.DockerForwardAddress)
+	}
+	return nil
+}
+
+// ensureDNSRunning starts the DNS server of the VM again, if it was started with 'minishift dns start' and is not
+// running anymore, for example after the VM was restarted.
+func ensureDNSRunning(api libmachine.API) error {
+	if !minishiftConfig.InstanceStateConfig.DNSEnabled {
+		return nil
+	}
+
+	hostVm, err := loadRunningHost(api)
+	if err != nil || hostVm == nil || dns.Status(hostVm.Driver) {
+		return err
+	}
+
+	logger.Infof("Starting the DNS server of the VM")
+	_, err = dns.Start(hostVm.Driver)
+	return err
+}
@@ -21,11 +21,12 @@ import (
 )
 
 var DaemonCmd = &cobra.Command{
-	Use:   "daemon",
-	Short: "Starts a minishift service daemon.",
-	Long:  `Starts a minishift service daemon. This command is for internal use of Minishift source code`,
+	Use:   "daemon SUBCOMMAND [flags]",
+	Short: "Manages the Minishift agent.",
+	Long: `Manages the Minishift agent, a lightweight host process which watches the IP of the VM, stops the VM when idle,
+maintains tunnels to the VM and keeps the DNS server running. The hidden subcommands run the service daemons and are
+for internal use of Minishift source code.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
-	Hidden: true,
 }
@@ -0,0 +1,75 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/network/dockerforward"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dockerForwardBindIP string
+	dockerForwardPort   int
+
+	daemonDockerForwardCmd = &cobra.Command{
+		Use:    "docker-forward",
+		Short:  "Forwards an address of the host to the Docker daemon of the VM.",
+		Long:   `Forwards an address of the host to the Docker daemon of the VM, so that the daemon can be used from other machines.`,
+		Run:    runDockerForward,
+		Hidden: true,
+	}
+)
+
+func init() {
+	daemonDockerForwardCmd.Flags().StringVar(&dockerForwardBindIP, "bind-ip", "", "The IP of the host to listen on.")
+	daemonDockerForwardCmd.Flags().IntVarP(&dockerForwardPort, "port", "p", dockerforward.DefaultPort, "The port of the host to listen on.")
+	DaemonCmd.AddCommand(daemonDockerForwardCmd)
+}
+
+func runDockerForward(cmd *cobra.Command, args []string) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(dockerForwardBindIP, fmt.Sprintf("%d", dockerForwardPort)))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Println(fmt.Sprintf("Forwarding %s to the Docker daemon of the VM", listener.Addr()))
+	log.Fatal(dockerforward.Serve(listener, vmDockerAddress))
+}
+
+// vmDockerAddress looks up the IP of the VM for every connection, since the IP can change when the VM is restarted.
+func vmDockerAddress() (string, error) {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		return "", err
+	}
+
+	ip, err := host.Driver.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return dockerforward.DockerAddress(ip), nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/minishift/minishift/pkg/minishift/filesync"
+	"github.com/spf13/cobra"
+)
+
+const (
+	syncPullInterval   = 5 * time.Second
+	syncReconnectDelay = 10 * time.Second
+)
+
+var (
+	syncSource    string
+	syncTarget    string
+	syncPullPaths []string
+	syncExcludes  []string
+
+	daemonHostFolderSyncCmd = &cobra.Command{
+		Use:    "hostfolder-sync",
+		Short:  "Keeps a sync based host folder in sync with the VM.",
+		Long:   `Keeps a sync based host folder in sync with the VM. Changes on the host are pushed into the VM, changes of the pull paths in the VM are pulled back to the host.`,
+		Run:    runHostFolderSync,
+		Hidden: true,
+	}
+)
+
+func init() {
+	daemonHostFolderSyncCmd.Flags().StringVar(&syncSource, "source", "", "The directory on the host.")
+	daemonHostFolderSyncCmd.Flags().StringVar(&syncTarget, "target", "", "The directory in the VM.")
+	daemonHostFolderSyncCmd.Flags().StringSliceVar(&syncPullPaths, "pull", nil, "The paths pulled back from the VM.")
+	daemonHostFolderSyncCmd.Flags().StringSliceVar(&syncExcludes, "exclude", nil, "The patterns of files which are not synchronized.")
+	DaemonCmd.AddCommand(daemonHostFolderSyncCmd)
+}
+
+// runHostFolderSync synchronizes until the VM is deleted. If the connection to the VM is lost, for example because
+// the VM was restarted, the process reconnects and synchronizes all changes made in the meantime.
+func runHostFolderSync(cmd *cobra.Command, args []string) {
+	options := filesync.Options{PullPaths: syncPullPaths, Excludes: syncExcludes}
+	for {
+		if err := syncHostFolder(options); err != nil {
+			logger.Errorf("Error synchronizing '%s': %v", syncSource, err)
+		}
+		time.Sleep(syncReconnectDelay)
+	}
+}
+
+func syncHostFolder(options filesync.Options) error {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		logger.Fatalf("failed to load the VM: %v", err)
+	}
+
+	client, err := sshutil.NewSSHClient(host.Driver)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	syncer := filesync.New(client, syncSource, syncTarget, options)
+	if _, err := syncer.Push(); err != nil {
+		return err
+	}
+	if _, err := syncer.Pull(); err != nil {
+		return err
+	}
+	return syncer.Watch(syncPullInterval, make(chan struct{}))
+}
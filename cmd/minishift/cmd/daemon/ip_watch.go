@@ -0,0 +1,51 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minishift/network/ipwatch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ipWatchInterval time.Duration
+
+	daemonIPWatchCmd = &cobra.Command{
+		Use:    "ip-watch",
+		Short:  "Reconciles changes of the IP of the VM.",
+		Long:   `Watches the IP of the VM and updates the kubeconfig, the certificates and the DNS configuration when the IP changes.`,
+		Run:    runIPWatch,
+		Hidden: true,
+	}
+)
+
+func init() {
+	daemonIPWatchCmd.Flags().DurationVar(&ipWatchInterval, "interval", ipwatch.DefaultInterval, "The interval in which the IP is checked.")
+	DaemonCmd.AddCommand(daemonIPWatchCmd)
+}
+
+func runIPWatch(cmd *cobra.Command, args []string) {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	util.NewIPWatcher(api).Watch(ipWatchInterval, make(chan struct{}))
+}
@@ -19,6 +19,7 @@ package daemon
 import (
 	"github.com/minishift/minishift/cmd/minishift/cmd/config"
 	"github.com/minishift/minishift/pkg/minishift/network/proxy"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -50,6 +51,9 @@ func init() {
 }
 
 func runProxy(cmd *cobra.Command, args []string) {
+	if err := config.DecryptSecrets(config.LocalProxyUpstream); err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
 	proxyPort := viper.GetInt(config.ServicesLocalProxyPort.Name)
 	if proxyPort == 0 {
 		proxyPort = proxyServerPortFromFlag
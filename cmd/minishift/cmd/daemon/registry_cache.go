@@ -0,0 +1,53 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minishift/network/registrycache"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	registryCachePortFromFlag    int
+	registryCacheHostIPsFromFlag []string
+
+	daemonRegistryCacheCmd = &cobra.Command{
+		Use:    "registry-cache",
+		Short:  "Starts a pull-through registry cache on host",
+		Long:   `Starts a pull-through registry cache on host`,
+		Run:    runRegistryCache,
+		Hidden: true,
+	}
+)
+
+func init() {
+	daemonRegistryCacheCmd.Flags().IntVarP(&registryCachePortFromFlag, "port", "p", registrycache.DefaultPort, "The server port.")
+	daemonRegistryCacheCmd.Flags().StringSliceVar(&registryCacheHostIPsFromFlag, "host-ip", nil, "The host addresses on the instance networks to listen on in addition to localhost.")
+	DaemonCmd.AddCommand(daemonRegistryCacheCmd)
+}
+
+func runRegistryCache(cmd *cobra.Command, args []string) {
+	port := registryCachePortFromFlag
+	if !cmd.Flags().Changed("port") {
+		port = viper.GetInt(config.ServicesRegistryCachePort.Name)
+	}
+
+	registrycache.StartRegistryCache(port, registryCacheHostIPsFromFlag, state.InstanceDirs.RegistryCache, viper.GetString(config.RegistryCacheUpstream.Name))
+}
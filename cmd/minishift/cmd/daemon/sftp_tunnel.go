@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sftpTunnelPort int
+
+	daemonSftpTunnelCmd = &cobra.Command{
+		Use:    "sftp-tunnel",
+		Short:  "Serves sftp to the VM over a reverse SSH tunnel for sshfs based host folders.",
+		Long:   `Serves sftp to the VM over a reverse SSH tunnel for sshfs based host folders. The sftp port is only opened on the loopback interface of the VM, no port is opened on the host.`,
+		Run:    runSftpTunnel,
+		Hidden: true,
+	}
+)
+
+func init() {
+	daemonSftpTunnelCmd.Flags().IntVarP(&sftpTunnelPort, sftpdServerPortFlag, "p", 2022, "The port to listen on within the VM.")
+	DaemonCmd.AddCommand(daemonSftpTunnelCmd)
+}
+
+func runSftpTunnel(cmd *cobra.Command, args []string) {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		logger.Fatalf("failed to load the VM: %v", err)
+	}
+
+	client, err := sshutil.NewSSHClient(host.Driver)
+	if err != nil {
+		logger.Fatalf("failed to connect to the VM: %v", err)
+	}
+	defer client.Close()
+
+	// the VM connects to the forwarded port, which is served by the SSH server of this process
+	listener, err := client.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", sftpTunnelPort))
+	if err != nil {
+		logger.Fatalf("failed to listen for connections within the VM: %v", err)
+	}
+	logger.Infof("listening on %v within the VM", listener.Addr())
+
+	serveConnections(listener, serverConfig())
+}
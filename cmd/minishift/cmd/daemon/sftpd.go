@@ -18,7 +18,6 @@ package daemon
 
 import (
 	"fmt"
-	"github.com/golang/glog"
 	"github.com/minishift/minishift/cmd/minishift/cmd/config"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
 	"github.com/minishift/minishift/pkg/util/filehelper"
@@ -69,9 +68,9 @@ func runSftp(cmd *cobra.Command, args []string) {
 	// Once a ServerConfig has been configured, connections can be accepted.
 	listener, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
 	if err != nil {
-		glog.Fatal("failed to listen for connection", err)
+		logger.Fatalf("failed to listen for connection: %v", err)
 	}
-	glog.Infof("listening on %v", listener.Addr())
+	logger.Infof("listening on %v", listener.Addr())
 
 	serveConnections(listener, serverConfig)
 }
@@ -80,7 +79,7 @@ func serveConnections(listener net.Listener, serverConfig *ssh.ServerConfig) {
 	for {
 		nConn, err := listener.Accept()
 		if err != nil {
-			glog.Fatal("failed to accept incoming connection", err)
+			logger.Fatalf("failed to accept incoming connection: %v", err)
 		}
 
 		// populate authorized keys for handshake
@@ -88,9 +87,9 @@ func serveConnections(listener net.Listener, serverConfig *ssh.ServerConfig) {
 		// Before use, a handshake must be performed on the incoming net.Conn.
 		_, channels, requests, err := ssh.NewServerConn(nConn, serverConfig)
 		if err != nil {
-			glog.Fatal("failed to handshake", err)
+			logger.Fatalf("failed to handshake: %v", err)
 		}
-		glog.Info("SSH server established")
+		logger.Infof("SSH server established")
 
 		// The incoming Request channel must be serviced.
 		go ssh.DiscardRequests(requests)
@@ -101,14 +100,14 @@ func serveConnections(listener net.Listener, serverConfig *ssh.ServerConfig) {
 				// Channels have a type, depending on the application level
 				// protocol intended. In the case of an SFTP session, this is "subsystem"
 				// with a payload string of "<length=4>sftp"
-				glog.Infof("Incoming channel: %s", newChannel.ChannelType())
+				logger.Infof("Incoming channel: %s", newChannel.ChannelType())
 				if newChannel.ChannelType() != "session" {
 					newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
 					continue
 				}
 				channel, requests, err := newChannel.Accept()
 				if err != nil {
-					glog.Fatal("could not accept channel.", err)
+					logger.Fatalf("could not accept channel: %v", err)
 				}
 
 				// Sessions have out-of-band requests such as "shell",
@@ -116,11 +115,11 @@ func serveConnections(listener net.Listener, serverConfig *ssh.ServerConfig) {
 				// "subsystem" request.
 				go func(in <-chan *ssh.Request) {
 					for req := range in {
-						glog.Infof("Request: %v", req.Type)
+						logger.Infof("Request: %v", req.Type)
 						ok := false
 						switch req.Type {
 						case "subsystem":
-							glog.Infof("Subsystem: %s", req.Payload[4:])
+							logger.Infof("Subsystem: %s", req.Payload[4:])
 							if string(req.Payload[4:]) == "sftp" {
 								ok = true
 								atomic.AddUint64(&connectionCount, 1)
@@ -138,20 +137,20 @@ func serveConnections(listener net.Listener, serverConfig *ssh.ServerConfig) {
 					serverOptions...,
 				)
 				if err != nil {
-					glog.Fatal(err)
+					logger.Fatalf("%v", err)
 				}
 				if err := server.Serve(); err == io.EOF {
 					server.Close()
 					atomic.AddUint64(&connectionCount, ^uint64(0))
 					currentCount := atomic.LoadUint64(&connectionCount)
 					if currentCount == 0 {
-						glog.Info("last sftp client exited.")
+						logger.Infof("last sftp client exited.")
 						os.Exit(0)
 					} else {
-						glog.Info("sftp client exited session.")
+						logger.Infof("sftp client exited session.")
 					}
 				} else if err != nil {
-					glog.Fatal("sftp server completed with error:", err)
+					logger.Fatalf("sftp server completed with error: %v", err)
 				}
 			}
 		}()
@@ -186,13 +185,13 @@ func serverConfig() *ssh.ServerConfig {
 func populateAuthorizedKeysMap() {
 	authorizedKeysBytes, err := ioutil.ReadFile(minishiftConstants.ProfileAuthorizedKeysPath())
 	if err != nil {
-		glog.Fatalf("Failed to load authorized_keys, err: %v", err)
+		logger.Fatalf("Failed to load authorized_keys, err: %v", err)
 	}
 
 	for len(authorizedKeysBytes) > 0 {
 		pubKey, _, _, rest, err := ssh.ParseAuthorizedKey(authorizedKeysBytes)
 		if err != nil {
-			glog.Fatal(err)
+			logger.Fatalf("%v", err)
 		}
 
 		authorizedKeysMap[string(pubKey.Marshal())] = true
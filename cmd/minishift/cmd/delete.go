@@ -19,17 +19,25 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/provision"
+	libmachineState "github.com/docker/machine/libmachine/state"
 	registrationUtil "github.com/minishift/minishift/cmd/minishift/cmd/registration"
 	"github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/agent"
+	minishiftCluster "github.com/minishift/minishift/pkg/minishift/cluster"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder"
+	"github.com/minishift/minishift/pkg/minishift/network/dockerforward"
+	"github.com/minishift/minishift/pkg/minishift/network/ipwatch"
 	"github.com/minishift/minishift/pkg/minishift/oc"
 	pkgUtil "github.com/minishift/minishift/pkg/util"
 	"github.com/minishift/minishift/pkg/util/filehelper"
@@ -50,9 +58,13 @@ var (
 
 	forceFlag      bool
 	clearCacheFlag bool
+	keepDataFlag   bool
 )
 
 func runDelete(cmd *cobra.Command, args []string) {
+	util.LockProfilesOrExit(constants.ProfileName)
+	util.ExitIfProtected(constants.ProfileName, forceFlag)
+
 	if clearCacheFlag {
 		clearCache()
 	}
@@ -78,6 +90,10 @@ func runDelete(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if keepDataFlag && !minishiftCluster.IsKeepDataSupported(host.Driver.DriverName()) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Keeping the VM data is not supported for the '%s' driver", host.Driver.DriverName()))
+	}
+
 	if host.Driver.DriverName() == "generic" {
 		if err := util.OcClusterDown(host); err != nil {
 			atexit.ExitWithMessage(1, err.Error())
@@ -86,7 +102,8 @@ func runDelete(cmd *cobra.Command, args []string) {
 			atexit.ExitWithMessage(1, err.Error())
 		}
 	}
-	// Remove entries from global kube config
+	// Restore the context the user worked with and remove the entries from global kube config
+	util.RestoreKubeContext()
 	clusterIP, _ := cluster.GetHostIP(api)
 	err = cleanKubeConfig(clusterIP)
 	if err != nil {
@@ -95,6 +112,32 @@ func runDelete(cmd *cobra.Command, args []string) {
 
 	// Unregistration, do not allow to be skipped
 	registrationUtil.UnregisterHost(api, false, forceFlag)
+
+	if keepDataFlag {
+		keepDataDisk(host)
+	} else {
+		discardDataDisk()
+	}
+
+	if err := dockerforward.Stop(); err != nil {
+		fmt.Println("Unable to stop forwarding to the Docker daemon:", err)
+	}
+	if err := ipwatch.Stop(); err != nil {
+		fmt.Println("Unable to stop watching the IP address:", err)
+	}
+	if err := hostfolder.StopSyncs(); err != nil {
+		fmt.Println("Unable to stop synchronizing the host folders:", err)
+	}
+	if err := hostfolder.RemoveNFSExports(runtime.GOOS, constants.ProfileName); err != nil {
+		fmt.Println("Unable to remove the NFS exports of the host folders:", err)
+	}
+	if err := agent.UninstallService(); err != nil {
+		fmt.Println("Unable to unregister the Minishift agent:", err)
+	}
+	if err := agent.Stop(); err != nil {
+		fmt.Println("Unable to stop the Minishift agent:", err)
+	}
+
 	fmt.Println("Deleting the Minishift VM...")
 	if err := cluster.DeleteHost(api); err != nil {
 		handleFailedHostDeletion(err)
@@ -105,6 +148,42 @@ func runDelete(cmd *cobra.Command, args []string) {
 	fmt.Println("Minishift VM deleted.")
 }
 
+// keepDataDisk removes everything but the Docker data from the disk of the VM, stops the VM and moves its disk into
+// the data directory of the profile. A stopped VM is pruned when its disk is reused by 'minishift start'.
+func keepDataDisk(host *host.Host) {
+	fmt.Println("Keeping the data disk of the Minishift VM...")
+	if vmState, err := host.Driver.GetState(); err == nil && vmState == libmachineState.Running {
+		if err := minishiftCluster.Prune(provision.GenericSSHCommander{Driver: host.Driver}); err != nil {
+			fmt.Println("Unable to remove the OpenShift cluster from the data disk:", err)
+		}
+	}
+	if err := host.Stop(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error stopping the Minishift VM: %v", err))
+	}
+
+	if err := dataDisk().Keep(host.Driver.DriverName()); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error keeping the data disk: %v", err))
+	}
+}
+
+// discardDataDisk removes the disk a previous 'minishift delete --keep-data' kept, but no start reused
+func discardDataDisk() {
+	discarded, err := dataDisk().Discard()
+	if err != nil {
+		fmt.Println("Unable to remove the kept data disk:", err)
+	} else if discarded {
+		fmt.Println("Removed the data disk kept by a previous 'minishift delete --keep-data'.")
+	}
+}
+
+func dataDisk() *minishiftCluster.DataDisk {
+	return &minishiftCluster.DataDisk{
+		DataDir:     state.InstanceDirs.Data,
+		MachineDir:  filepath.Join(state.InstanceDirs.Machines, constants.MachineName),
+		MachineName: constants.MachineName,
+	}
+}
+
 func clearCache() {
 	if !forceFlag {
 		hasConfirmed := pkgUtil.AskForConfirmation("This will delete the cache content for all profiles.")
@@ -121,8 +200,13 @@ func clearCache() {
 	}
 }
 
-// Remove the current cluster's entries from global kubeconfig file
+// Remove the current cluster's entries from global kubeconfig file. Only the entries recorded for the profile are
+// removed, all entries of the cluster are removed for clusters started before the entries were recorded.
 func cleanKubeConfig(clusterIP string) error {
+	if handled, err := util.RemoveKubeConfigEntries(constants.ProfileName); handled {
+		return err
+	}
+
 	kubeConfigPath, err := oc.GetGlobalKubeConfigPath()
 	if err != nil {
 		return err
@@ -211,7 +295,8 @@ func deleteExistingDirectory(hostVm *host.Host) error {
 }
 
 func init() {
-	deleteCmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Forces the deletion of the VM specific files in MINISHIFT_HOME.")
+	deleteCmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Forces the deletion of the VM specific files in MINISHIFT_HOME, also if the profile is protected.")
 	deleteCmd.Flags().BoolVar(&clearCacheFlag, "clear-cache", false, "Deletes all cached content. This affects all profiles.")
+	deleteCmd.Flags().BoolVar(&keepDataFlag, "keep-data", false, "Keeps the data disk of the VM with the Docker images and the build cache in /var/lib/docker, to be reused by the next 'minishift start'.")
 	RootCmd.AddCommand(deleteCmd)
 }
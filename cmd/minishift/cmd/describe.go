@@ -0,0 +1,328 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/state"
+	cmdAddon "github.com/minishift/minishift/cmd/minishift/cmd/addon"
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	cmdState "github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	localAPI "github.com/minishift/minishift/pkg/minishift/api"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder"
+	"github.com/minishift/minishift/pkg/minishift/identityprovider"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	openshiftVersion "github.com/minishift/minishift/pkg/minishift/openshift/version"
+	"github.com/minishift/minishift/pkg/minishift/tls"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/minishift/minishift/pkg/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	describeOutput string
+
+	// describeCmd represents the describe command
+	describeCmd = &cobra.Command{
+		Use:   "describe",
+		Short: "Describes the profile and its OpenShift cluster.",
+		Long: `Describes the profile and its OpenShift cluster: the versions, the driver, the networking, the URLs, how to
+log in, the expiry of the certificates, the host folders, the enabled add-ons and the recent lifecycle events. With
+'--output json' the description is printed as a single JSON document for tools such as IDE plugins.`,
+		Run: runDescribe,
+	}
+)
+
+func init() {
+	describeCmd.Flags().StringVarP(&describeOutput, "output", "o", "", "Prints the description in the specified format. Supported format: json")
+	RootCmd.AddCommand(describeCmd)
+}
+
+func runDescribe(cmd *cobra.Command, args []string) {
+	if describeOutput != "" && describeOutput != jsonOutput {
+		atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("Unsupported output format '%s'. Only '%s' is supported.", describeOutput, jsonOutput))
+	}
+	if describeOutput == jsonOutput {
+		atexit.ReportErrorsAsJSON()
+	}
+
+	api := libmachine.NewClient(cmdState.InstanceDirs.Home, cmdState.InstanceDirs.Certs)
+	defer api.Close()
+
+	description, err := describeProfile(api)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error describing the profile: %v", err))
+	}
+
+	if describeOutput == jsonOutput {
+		out, err := json.MarshalIndent(description, "", "  ")
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error creating JSON output: %v", err))
+		}
+		fmt.Fprintln(os.Stdout, string(out))
+		return
+	}
+	printDescription(description, os.Stdout)
+}
+
+func describeProfile(api libmachine.API) (*localAPI.Description, error) {
+	vmStatus, err := cluster.GetHostStatus(api, constants.MachineName)
+	if err != nil {
+		return nil, err
+	}
+
+	description := &localAPI.Description{
+		Profile:      constants.ProfileName,
+		VMStatus:     vmStatus,
+		Versions:     localAPI.Versions{Minishift: version.GetMinishiftVersion(), OpenShift: minishiftConfig.InstanceStateConfig.OpenshiftVersion},
+		Nameservers:  getSlice(configCmd.NameServers.Name),
+		Credentials:  describeCredentials(viper.GetString(configCmd.IdentityProvider.Name), minishiftConfig.InstanceConfig.Users),
+		Certificates: describeHostCertificates(),
+		HostFolders:  []localAPI.HostFolder{},
+		AddOns:       describeAddOns(),
+		Events:       describeLifecycleEvents(minishiftConfig.InstanceStateConfig.LifecycleEvents),
+	}
+
+	var hostVm *host.Host
+	if exists, err := api.Exists(constants.MachineName); err == nil && exists {
+		existingVm, err := api.Load(constants.MachineName)
+		if err != nil {
+			return nil, err
+		}
+		description.Driver = existingVm.DriverName
+		if vmStatus == state.Running.String() {
+			hostVm = existingVm
+		}
+	}
+	if hostVm != nil {
+		ip, err := hostVm.Driver.GetIP()
+		if err != nil {
+			return nil, err
+		}
+		description.IP = ip
+		description.RoutingSuffix = configCmd.GetDefaultRoutingSuffix(ip)
+		description.URLs = &localAPI.URLs{
+			Console:   fmt.Sprintf("https://%s:%d/console", configCmd.GetDefaultPublicHostName(ip), constants.APIServerPort),
+			APIServer: fmt.Sprintf("https://%s:%d", configCmd.GetDefaultPublicHostName(ip), constants.APIServerPort),
+			Docker:    fmt.Sprintf("tcp://%s:2376", ip),
+		}
+		sshCommander := provision.GenericSSHCommander{Driver: hostVm.Driver}
+		if runningVersion, err := openshiftVersion.GetOpenshiftVersion(sshCommander); err == nil {
+			description.Versions.OpenShift = strings.TrimSpace(strings.Split(runningVersion, "\n")[0])
+		}
+		if cert, err := tls.ServingCert(docker.NewVmDockerCommander(sshCommander)); err == nil {
+			description.Certificates = append(description.Certificates, localAPI.Certificate{
+				Name:     "API server",
+				Path:     "master.server.crt",
+				NotAfter: cert.NotAfter,
+			})
+		}
+	}
+
+	manager, err := hostfolder.NewManager(minishiftConfig.InstanceConfig, minishiftConfig.AllInstancesConfig)
+	if err != nil {
+		return nil, err
+	}
+	if manager.ExistAny() {
+		var mountInfos []hostfolder.MountInfo
+		if hostVm != nil {
+			mountInfos, err = manager.List(hostVm.Driver)
+		} else {
+			mountInfos, err = manager.List(nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range mountInfos {
+			description.HostFolders = append(description.HostFolders, localAPI.HostFolder{
+				Name:       info.Name,
+				Type:       info.Type,
+				Source:     info.Source,
+				MountPoint: info.MountPoint,
+				Mounted:    info.Mounted,
+			})
+		}
+	}
+
+	return description, nil
+}
+
+// describeCredentials describes how to log in, given the explicitly configured identity provider and the users
+// declared in the profile. Unless configured explicitly, the identity provider is htpasswd as soon as a user has a
+// password and accepts any password otherwise.
+func describeCredentials(configuredProvider string, users map[string]*minishiftConfig.User) localAPI.Credentials {
+	credentials := localAPI.Credentials{IdentityProvider: configuredProvider, Users: []localAPI.User{}}
+	for _, name := range openshift.UserNames(users) {
+		credentials.Users = append(credentials.Users, localAPI.User{
+			Name:         name,
+			HasPassword:  users[name].PasswordHash != "",
+			ClusterRoles: users[name].ClusterRoles,
+		})
+	}
+	if credentials.IdentityProvider == "" {
+		credentials.IdentityProvider = identityprovider.AllowAll
+		if openshift.HtpasswdContent(users) != "" {
+			credentials.IdentityProvider = identityprovider.Htpasswd
+		}
+	}
+
+	adminHint := "Run 'oc login -u system:admin' for cluster administration."
+	switch credentials.IdentityProvider {
+	case identityprovider.AllowAll:
+		credentials.Hint = fmt.Sprintf("Log in as any user, for example 'developer', with any password. %s", adminHint)
+	case identityprovider.Htpasswd:
+		credentials.Hint = fmt.Sprintf("Log in as one of the users with a password, which 'minishift openshift user add' set. %s", adminHint)
+	default:
+		credentials.Hint = fmt.Sprintf("Log in with the credentials of the %s identity provider. %s", credentials.IdentityProvider, adminHint)
+	}
+	return credentials
+}
+
+// describeHostCertificates describes the certificates securing the connection to the Docker daemon of the VM
+func describeHostCertificates() []localAPI.Certificate {
+	certificates := []localAPI.Certificate{}
+	for _, cert := range []struct{ name, path string }{
+		{"Docker CA", filepath.Join(cmdState.InstanceDirs.Certs, "ca.pem")},
+		{"Docker client", filepath.Join(cmdState.InstanceDirs.Certs, "cert.pem")},
+		{"Docker server", filepath.Join(cmdState.InstanceDirs.Machines, constants.MachineName, "server.pem")},
+	} {
+		parsed, err := tls.ReadCertificateFile(cert.path)
+		if err != nil {
+			continue
+		}
+		certificates = append(certificates, localAPI.Certificate{Name: cert.name, Path: cert.path, NotAfter: parsed.NotAfter})
+	}
+	return certificates
+}
+
+// describeLifecycleEvents returns the recent lifecycle events, the latest first
+func describeLifecycleEvents(events []minishiftConfig.LifecycleEvent) []localAPI.LifecycleEvent {
+	described := []localAPI.LifecycleEvent{}
+	for i := len(events) - 1; i >= 0; i-- {
+		described = append(described, localAPI.LifecycleEvent{State: string(events[i].State), Message: events[i].Message, Time: events[i].Time})
+	}
+	return described
+}
+
+func describeAddOns() []localAPI.AddOn {
+	addOns := []localAPI.AddOn{}
+	for _, addOn := range cmdAddon.GetAddOnManager().List() {
+		if addOn.IsEnabled() {
+			addOns = append(addOns, localAPI.AddOn{Name: addOn.MetaData().Name(), Priority: addOn.GetPriority()})
+		}
+	}
+	sort.Slice(addOns, func(i, j int) bool {
+		if addOns[i].Priority != addOns[j].Priority {
+			return addOns[i].Priority < addOns[j].Priority
+		}
+		return addOns[i].Name < addOns[j].Name
+	})
+	return addOns
+}
+
+func printDescription(description *localAPI.Description, out io.Writer) {
+	printDescriptionAt(description, out, time.Now())
+}
+
+func printDescriptionAt(description *localAPI.Description, out io.Writer, now time.Time) {
+	fmt.Fprintln(out, fmt.Sprintf("Profile:           %s", description.Profile))
+	fmt.Fprintln(out, fmt.Sprintf("VM:                %s", description.VMStatus))
+	if description.Driver != "" {
+		fmt.Fprintln(out, fmt.Sprintf("Driver:            %s", description.Driver))
+	}
+	fmt.Fprintln(out, fmt.Sprintf("Minishift version: %s", description.Versions.Minishift))
+	if description.Versions.OpenShift != "" {
+		fmt.Fprintln(out, fmt.Sprintf("OpenShift version: %s", description.Versions.OpenShift))
+	}
+	if description.IP != "" {
+		fmt.Fprintln(out, fmt.Sprintf("IP:                %s", description.IP))
+		fmt.Fprintln(out, fmt.Sprintf("Routing suffix:    %s", description.RoutingSuffix))
+	}
+	if len(description.Nameservers) > 0 {
+		fmt.Fprintln(out, fmt.Sprintf("Nameservers:       %s", strings.Join(description.Nameservers, ", ")))
+	}
+	if description.URLs != nil {
+		fmt.Fprintln(out, fmt.Sprintf("Console:           %s", description.URLs.Console))
+		fmt.Fprintln(out, fmt.Sprintf("API server:        %s", description.URLs.APIServer))
+		fmt.Fprintln(out, fmt.Sprintf("Docker:            %s", description.URLs.Docker))
+	}
+	fmt.Fprintln(out, fmt.Sprintf("Identity provider: %s", description.Credentials.IdentityProvider))
+	fmt.Fprintln(out, fmt.Sprintf("Login:             %s", description.Credentials.Hint))
+
+	hostFolders := []string{}
+	for _, hostFolder := range description.HostFolders {
+		mounted := ""
+		if hostFolder.Mounted {
+			mounted = ", mounted"
+		}
+		hostFolders = append(hostFolders, fmt.Sprintf("%s (%s%s)", hostFolder.Name, hostFolder.MountPoint, mounted))
+	}
+	addOns := []string{}
+	for _, addOn := range description.AddOns {
+		addOns = append(addOns, addOn.Name)
+	}
+	fmt.Fprintln(out, fmt.Sprintf("Host folders:      %s", joinOrNone(hostFolders)))
+	fmt.Fprintln(out, fmt.Sprintf("Enabled add-ons:   %s", joinOrNone(addOns)))
+
+	if len(description.Certificates) > 0 {
+		fmt.Fprintln(out, "Certificates:")
+		for _, cert := range description.Certificates {
+			fmt.Fprintln(out, fmt.Sprintf("  %-16s %s", cert.Name+":", describeExpiry(cert.NotAfter, now)))
+		}
+	}
+	if len(description.Events) > 0 {
+		fmt.Fprintln(out, "Recent events:")
+		for _, event := range description.Events {
+			line := fmt.Sprintf("  %s  %s", event.Time.Format("2006-01-02 15:04:05"), event.State)
+			if event.Message != "" {
+				line = fmt.Sprintf("%s: %s", line, event.Message)
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+}
+
+func describeExpiry(notAfter time.Time, now time.Time) string {
+	days := int(notAfter.Sub(now).Hours() / 24)
+	if notAfter.Before(now) {
+		return fmt.Sprintf("expired on %s", notAfter.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("expires on %s (in %d days)", notAfter.Format("2006-01-02"), days)
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	localAPI "github.com/minishift/minishift/pkg/minishift/api"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Credentials_Without_Users_Accept_Any_Password(t *testing.T) {
+	credentials := describeCredentials("", map[string]*minishiftConfig.User{})
+
+	assert.Equal(t, "allow-all", credentials.IdentityProvider)
+	assert.Empty(t, credentials.Users)
+	assert.Contains(t, credentials.Hint, "with any password")
+}
+
+func Test_Credentials_With_Password_Use_Htpasswd(t *testing.T) {
+	users := map[string]*minishiftConfig.User{
+		"john": {PasswordHash: "{SHA}secret", ClusterRoles: []string{"cluster-admin"}},
+		"anne": {},
+	}
+	credentials := describeCredentials("", users)
+
+	assert.Equal(t, "htpasswd", credentials.IdentityProvider)
+	assert.Equal(t, []localAPI.User{
+		{Name: "anne"},
+		{Name: "john", HasPassword: true, ClusterRoles: []string{"cluster-admin"}},
+	}, credentials.Users)
+}
+
+func Test_Credentials_Use_Configured_Identity_Provider(t *testing.T) {
+	credentials := describeCredentials("ldap", map[string]*minishiftConfig.User{})
+
+	assert.Equal(t, "ldap", credentials.IdentityProvider)
+	assert.Contains(t, credentials.Hint, "ldap identity provider")
+}
+
+func Test_Description_Of_Stopped_Profile(t *testing.T) {
+	description := &localAPI.Description{
+		Profile:     "dev",
+		VMStatus:    "Stopped",
+		Versions:    localAPI.Versions{Minishift: "v1.34.0", OpenShift: "v3.11.0"},
+		Credentials: localAPI.Credentials{IdentityProvider: "allow-all", Hint: "Log in as any user."},
+		HostFolders: []localAPI.HostFolder{{Name: "src", MountPoint: "/mnt/sda1/src"}},
+	}
+
+	var out bytes.Buffer
+	printDescription(description, &out)
+	expected := `Profile:           dev
+VM:                Stopped
+Minishift version: v1.34.0
+OpenShift version: v3.11.0
+Identity provider: allow-all
+Login:             Log in as any user.
+Host folders:      src (/mnt/sda1/src)
+Enabled add-ons:   none
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func Test_Description_Lists_Certificates_And_Latest_Events_First(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	events := describeLifecycleEvents([]minishiftConfig.LifecycleEvent{
+		{State: minishiftConfig.Creating, Time: now.Add(-2 * time.Hour)},
+		{State: minishiftConfig.Error, Message: "Error starting the VM", Time: now.Add(-time.Hour)},
+	})
+	description := &localAPI.Description{
+		Profile:     "dev",
+		VMStatus:    "Stopped",
+		Driver:      "kvm",
+		Versions:    localAPI.Versions{Minishift: "v1.34.0"},
+		Nameservers: []string{"8.8.8.8", "8.8.4.4"},
+		Credentials: localAPI.Credentials{IdentityProvider: "allow-all", Hint: "Log in as any user."},
+		Certificates: []localAPI.Certificate{
+			{Name: "Docker CA", NotAfter: now.Add(10 * 24 * time.Hour)},
+			{Name: "API server", NotAfter: now.Add(-time.Hour)},
+		},
+		Events: events,
+	}
+
+	var out bytes.Buffer
+	printDescriptionAt(description, &out, now)
+	expected := `Profile:           dev
+VM:                Stopped
+Driver:            kvm
+Minishift version: v1.34.0
+Nameservers:       8.8.8.8, 8.8.4.4
+Identity provider: allow-all
+Login:             Log in as any user.
+Host folders:      none
+Enabled add-ons:   none
+Certificates:
+  Docker CA:       expires on 2019-10-11 (in 10 days)
+  API server:      expired on 2019-10-01
+Recent events:
+  2019-10-01 11:00:00  Error: Error starting the VM
+  2019-10-01 10:00:00  Creating
+`
+	assert.Equal(t, expected, out.String())
+}
@@ -0,0 +1,129 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/provision"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	cmdState "github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/diskusage"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/filehelper"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+// diskUsageCmd represents the disk-usage command
+var diskUsageCmd = &cobra.Command{
+	Use:   "disk-usage",
+	Short: "Displays the disk space used by Minishift.",
+	Long: `Displays the disk space used on the host by the cache and the VMs of all profiles, as well as the disk space used
+within the running VM of the profile by container images, the OpenShift cluster and the persistent volumes.`,
+	Run: runDiskUsage,
+}
+
+func runDiskUsage(cmd *cobra.Command, args []string) {
+	home := constants.GetMinishiftHomeDir()
+	hostUsage, err := hostDiskUsage(home, profileActions.GetProfileList())
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error determining the disk usage of '%s': %v", home, err))
+	}
+	fmt.Println(fmt.Sprintf("Host (%s):", home))
+	printDiskUsage(os.Stdout, hostUsage)
+
+	api := libmachine.NewClient(cmdState.InstanceDirs.Home, cmdState.InstanceDirs.Certs)
+	defer api.Close()
+
+	fmt.Println()
+	host, err := api.Load(constants.MachineName)
+	if err != nil || !cmdUtil.IsHostRunning(host.Driver) {
+		fmt.Println(fmt.Sprintf("VM of profile '%s': Not running", constants.ProfileName))
+	} else {
+		sshCommander := provision.GenericSSHCommander{Driver: host.Driver}
+		vmUsage, err := diskusage.VMUsage(sshCommander, diskusage.VMDirectories)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error determining the disk usage of the VM: %v", err))
+		}
+		mountpoint := StorageDisk
+		if host.Driver.DriverName() == "generic" {
+			mountpoint = StorageDiskForGeneric
+		}
+		diskSize, diskUse, mountedOn := getDiskUsage(host.Driver, mountpoint)
+		fmt.Println(fmt.Sprintf("VM of profile '%s' (%s of %s used, mounted on %s):", constants.ProfileName, diskUse, diskSize, mountedOn))
+		printDiskUsage(os.Stdout, vmUsage)
+	}
+
+	fmt.Println()
+	fmt.Println("To free disk space, use 'minishift image delete --all' to delete the cached images, 'minishift delete --clear-cache'")
+	fmt.Println("to delete all cached content or 'minishift profile delete PROFILE_NAME' to delete the profiles you no longer need.")
+}
+
+// hostDiskUsage returns the disk space used by each part of the cache and by the VM and the isolated cache of each profile
+func hostDiskUsage(home string, profiles []string) ([]diskusage.Entry, error) {
+	cacheUsage, err := diskusage.Subdirectories(filepath.Join(home, "cache"))
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []diskusage.Entry
+	for _, entry := range cacheUsage {
+		entry.Name = filepath.Join("cache", entry.Name)
+		usage = append(usage, entry)
+	}
+
+	for _, profile := range profiles {
+		machineDir := filepath.Join(constants.GetProfileHomeDir(profile), "machines", profile)
+		size, err := diskusage.DirSize(machineDir)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, diskusage.Entry{Name: fmt.Sprintf("VM of profile '%s'", profile), Path: machineDir, Bytes: size})
+
+		cacheDir := filepath.Join(constants.GetProfileHomeDir(profile), "cache")
+		if profile == constants.DefaultProfileName || !filehelper.Exists(cacheDir) {
+			continue
+		}
+		size, err = diskusage.DirSize(cacheDir)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, diskusage.Entry{Name: fmt.Sprintf("Cache of profile '%s'", profile), Path: cacheDir, Bytes: size})
+	}
+	return usage, nil
+}
+
+func printDiskUsage(out io.Writer, usage []diskusage.Entry) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	for _, entry := range usage {
+		fmt.Fprintln(w, fmt.Sprintf("  %s\t%s", entry.Name, units.HumanSize(float64(entry.Bytes))))
+	}
+	fmt.Fprintln(w, fmt.Sprintf("  Total\t%s", units.HumanSize(float64(diskusage.Total(usage)))))
+	w.Flush()
+}
+
+func init() {
+	RootCmd.AddCommand(diskUsageCmd)
+}
@@ -26,6 +26,7 @@ import (
 	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/minishift/network/dns"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 )
@@ -53,6 +54,11 @@ func stopDns(cmd *cobra.Command, args []string) {
 	if err != nil {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Error starting the DNS server: %s", err.Error()))
 	}
+
+	minishiftConfig.InstanceStateConfig.DNSEnabled = false
+	if err := minishiftConfig.InstanceStateConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error writing the instance state: %s", err.Error()))
+	}
 }
 
 func init() {
@@ -21,22 +21,35 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/minishift/minishift/pkg/minikube/constants"
 
 	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/provision"
 	"github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/network"
+	"github.com/minishift/minishift/pkg/minishift/network/dockerforward"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/util/filehelper"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/minishift/minishift/pkg/util/shell"
 	"github.com/spf13/cobra"
 )
 
 const (
+	remoteCertsDirName = "docker-remote-certs"
+
 	envTmpl = `{{ .Prefix }}DOCKER_TLS_VERIFY{{ .Delimiter }}{{ .DockerTLSVerify }}{{ .Suffix }}{{ .Prefix }}DOCKER_HOST{{ .Delimiter }}{{ .DockerHost }}{{ .Suffix }}{{ .Prefix }}DOCKER_CERT_PATH{{ .Delimiter }}{{ .DockerCertPath }}{{ .Suffix }}{{ if .NoProxyVar }}{{ .Prefix }}{{ .NoProxyVar }}{{ .Delimiter }}{{ .NoProxyValue }}{{ .Suffix }}{{end}}{{ .UsageHint }}`
 )
 
@@ -44,6 +57,10 @@ var (
 	noProxy    bool
 	forceShell string
 	unset      bool
+	bindIP     string
+	bindPort   int
+	// regenerateCerts allows regenerating the Docker server certificate, which restarts Docker and OpenShift
+	regenerateCerts bool
 )
 
 type DockerShellConfig struct {
@@ -100,7 +117,8 @@ func getConfigUnset(forceShell string, noProxy bool) (*DockerShellConfig, error)
 	shellCfg := &DockerShellConfig{}
 
 	if noProxy {
-		shellCfg.NoProxyVar, shellCfg.NoProxyValue = shell.FindNoProxyFromEnv()
+		// only the name is needed, the variable is cleared
+		shellCfg.NoProxyVar, _ = shell.FindNoProxyFromEnv()
 		cmdLine = cmdLine + " --no-proxy"
 	}
 
@@ -110,6 +128,111 @@ func getConfigUnset(forceShell string, noProxy bool) (*DockerShellConfig, error)
 	return shellCfg, nil
 }
 
+// setRemoteConfig points the shell configuration to the address of the host forwarding to the Docker daemon of the VM.
+// The Docker server certificate is regenerated if it is not yet valid for the bind IP and regenerateCerts is set.
+func setRemoteConfig(api libmachine.API, hostVm *host.Host, shellCfg *DockerShellConfig, bindIP string, bindPort int) error {
+	if !isHostIP(bindIP) {
+		return fmt.Errorf("'%s' is not an IP address of this host. Use one of %s", bindIP, strings.Join(hostIPs(), ", "))
+	}
+
+	if err := ensureServerCertSAN(api, hostVm, bindIP); err != nil {
+		return err
+	}
+
+	certPath := filepath.Join(state.InstanceDirs.Home, remoteCertsDirName)
+	if err := writeClientCerts(hostVm, certPath); err != nil {
+		return fmt.Errorf("Error writing the Docker client certificates: %s", err.Error())
+	}
+
+	if err := dockerforward.EnsureDaemonRunning(bindIP, bindPort); err != nil {
+		return fmt.Errorf("Error forwarding %s:%d to the Docker daemon: %s", bindIP, bindPort, err.Error())
+	}
+
+	userShell, err := shell.GetShell(forceShell)
+	if err != nil {
+		return err
+	}
+
+	shellCfg.DockerHost = fmt.Sprintf("tcp://%s", net.JoinHostPort(bindIP, strconv.Itoa(bindPort)))
+	shellCfg.DockerCertPath = certPath
+	comment := shell.CommentPrefix(userShell)
+	shellCfg.UsageHint = fmt.Sprintf("%s Copy the content of %s to the other machine and adjust DOCKER_CERT_PATH accordingly.\n", comment, certPath) +
+		shell.GenerateUsageHint(userShell, fmt.Sprintf("minishift docker-env --bind-ip %s", bindIP))
+
+	return nil
+}
+
+// ensureServerCertSAN adds the IP to the subject alternative names of the Docker server certificate. Regenerating the
+// certificate restarts the Docker daemon, hence a running OpenShift cluster is restarted as well. This only happens
+// if the user opted in via regenerateCerts.
+func ensureServerCertSAN(api libmachine.API, hostVm *host.Host, ip string) error {
+	authOptions := hostVm.HostOptions.AuthOptions
+	for _, san := range authOptions.ServerCertSANs {
+		if san == ip {
+			return nil
+		}
+	}
+
+	if !regenerateCerts {
+		return fmt.Errorf("The Docker server certificate is not valid for %s. Regenerating it restarts the Docker daemon and a running OpenShift cluster. Run the command again with --regenerate-certs to proceed.", ip)
+	}
+
+	// progress goes to stderr, since stdout is evaluated by the shell
+	fmt.Fprintln(os.Stderr, fmt.Sprintf("-- Regenerating the Docker server certificate for %s ...", ip))
+	dockerCommander := docker.NewVmDockerCommander(provision.GenericSSHCommander{Driver: hostVm.Driver})
+	authOptions.ServerCertSANs = append(authOptions.ServerCertSANs, ip)
+	restartOpenShift, err := util.RegenerateDockerCerts(api, hostVm, dockerCommander)
+	if err != nil {
+		return fmt.Errorf("Error regenerating the Docker server certificate: %s", err.Error())
+	}
+
+	if restartOpenShift {
+		fmt.Fprintln(os.Stderr, "-- Restarting OpenShift ...")
+		if _, err := openshift.RestartOpenShift(dockerCommander); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeClientCerts copies the CA and the client certificate into certPath. Unlike the certificates directory of
+// the instance, certPath does not contain the CA key and can be shared with other machines.
+func writeClientCerts(hostVm *host.Host, certPath string) error {
+	if err := os.MkdirAll(certPath, 0700); err != nil {
+		return err
+	}
+
+	authOptions := hostVm.HostOptions.AuthOptions
+	certs := map[string]string{
+		"ca.pem":   authOptions.CaCertPath,
+		"cert.pem": authOptions.ClientCertPath,
+		"key.pem":  authOptions.ClientKeyPath,
+	}
+	for name, src := range certs {
+		if err := filehelper.CopyFile(src, filepath.Join(certPath, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isHostIP(ip string) bool {
+	for _, hostIP := range hostIPs() {
+		if hostIP == ip {
+			return true
+		}
+	}
+	return false
+}
+
+func hostIPs() []string {
+	ips := []string{}
+	for _, cidr := range network.HostIPs() {
+		ips = append(ips, strings.Split(cidr, "/")[0])
+	}
+	return ips
+}
+
 func executeTemplateStdout(shellCfg *DockerShellConfig) error {
 	tmpl := template.Must(template.New("envConfig").Parse(envTmpl))
 	return tmpl.Execute(os.Stdout, shellCfg)
@@ -122,6 +245,16 @@ var dockerEnvCmd = &cobra.Command{
 	Long:  `Sets Docker environment variables, similar to '$(docker-machine env)'.`,
 	Run: func(cmd *cobra.Command, args []string) {
 
+		if unset {
+			// leaving the environment does not require a running VM
+			shellCfg, err := getConfigUnset(forceShell, noProxy)
+			if err != nil {
+				atexit.ExitWithMessage(1, fmt.Sprintf("Error unsetting environment variables: %s", err.Error()))
+			}
+			executeTemplateStdout(shellCfg)
+			return
+		}
+
 		api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
 		defer api.Close()
 
@@ -134,16 +267,18 @@ var dockerEnvCmd = &cobra.Command{
 
 		util.ExitIfNotRunning(host.Driver, constants.MachineName)
 
-		var shellCfg *DockerShellConfig
-		if unset {
-			shellCfg, err = getConfigUnset(forceShell, noProxy)
-			if err != nil {
-				atexit.ExitWithMessage(1, fmt.Sprintf("Error unsetting environment variables: %s", err.Error()))
-			}
-		} else {
-			shellCfg, err = getConfigSet(api, forceShell, noProxy)
-			if err != nil {
-				atexit.ExitWithMessage(1, fmt.Sprintf("Error setting environment variables: %s", err.Error()))
+		if bindIP != "" && noProxy {
+			atexit.ExitWithMessage(1, "The --no-proxy flag cannot be used together with --bind-ip.")
+		}
+
+		shellCfg, err := getConfigSet(api, forceShell, noProxy)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error setting environment variables: %s", err.Error()))
+		}
+
+		if bindIP != "" {
+			if err := setRemoteConfig(api, host, shellCfg, bindIP, bindPort); err != nil {
+				atexit.ExitWithMessage(1, err.Error())
 			}
 		}
 
@@ -160,4 +295,7 @@ func init() {
 	}
 	dockerEnvCmd.Flags().StringVar(&forceShell, "shell", "", "Force setting the environment for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh]. Default is auto-detect.")
 	dockerEnvCmd.Flags().BoolVarP(&unset, "unset", "u", false, "Clear the environment variable values instead of setting them.")
+	dockerEnvCmd.Flags().StringVar(&bindIP, "bind-ip", "", "Expose the Docker daemon on the specified IP of the host and set the environment for using it from another machine.")
+	dockerEnvCmd.Flags().IntVar(&bindPort, "bind-port", dockerforward.DefaultPort, "The port of the host the Docker daemon is exposed on. Used together with --bind-ip.")
+	dockerEnvCmd.Flags().BoolVar(&regenerateCerts, "regenerate-certs", false, "Regenerate the Docker server certificate if it is not valid for the bind IP yet. This restarts the Docker daemon and a running OpenShift cluster. Used together with --bind-ip.")
 }
@@ -0,0 +1,191 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package home
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	cmdState "github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/agent"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/network/registrycache"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/filehelper"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/minishift/minishift/pkg/util/os/process"
+	"github.com/spf13/cobra"
+)
+
+var homeMigrateCmd = &cobra.Command{
+	Use:   "migrate NEW_HOME",
+	Short: "Moves the Minishift home directory to a new location.",
+	Long: `Moves the Minishift home directory, including the cache and all profiles, to a new location. The VMs of all profiles
+are registered with their hypervisor from the new location and the paths stored in the configuration and state files
+are updated, so that the VMs keep working. All VMs must be stopped. Afterwards, set the MINISHIFT_HOME environment
+variable to the new location.`,
+	Run: runHomeMigrate,
+}
+
+func runHomeMigrate(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You must provide the new location of the Minishift home directory.")
+	}
+	newHome, err := filepath.Abs(args[0])
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Invalid location '%s': %v", args[0], err))
+	}
+	oldHome := constants.GetMinishiftHomeDir()
+
+	profiles := profileActions.GetProfileList()
+	cmdUtil.LockProfilesOrExit(profiles...)
+
+	if agent.IsRunning() || agent.IsServiceInstalled() {
+		atexit.ExitWithMessage(1, fmt.Sprintf("The Minishift agent of profile '%s' uses the current home directory. Stop it using 'minishift daemon stop --uninstall' first.", constants.ProfileName))
+	}
+
+	var machines []profileActions.Machine
+	states := make(map[string]*minishiftConfig.InstanceStateConfigType)
+	for _, profile := range profiles {
+		if machine := stoppedMachine(profile); machine != nil {
+			machines = append(machines, *machine)
+		}
+		if stateConfig := instanceState(profile); stateConfig != nil {
+			states[profile] = stateConfig
+		}
+	}
+
+	for profile, stateConfig := range states {
+		stopDaemons(profile, stateConfig)
+	}
+
+	if pid := registrycache.GetPID(); pid != 0 {
+		fmt.Println(fmt.Sprintf("-- Stopping the registry cache (pid %d), it is restarted by the next 'minishift start'", pid))
+		if err := registrycache.StopRegistryCacheDaemon(); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error stopping the registry cache: %v", err))
+		}
+	}
+
+	fmt.Println(fmt.Sprintf("-- Moving the Minishift home directory from '%s' to '%s'", oldHome, newHome))
+	// the profile locks are files within the home directory, which cannot be moved while they are open on Windows
+	cmdUtil.ReleaseProfileLocks()
+	if err := profileActions.RelocateHome(oldHome, newHome, machines); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error moving the Minishift home directory: %v", err))
+	}
+
+	fmt.Println(fmt.Sprintf("The Minishift home directory was moved to '%s'.", newHome))
+	fmt.Println(fmt.Sprintf("Set the %s environment variable to use it, for example:", constants.MiniShiftHomeEnv))
+	if runtime.GOOS == "windows" {
+		fmt.Println(fmt.Sprintf("    setx %s \"%s\"", constants.MiniShiftHomeEnv, newHome))
+	} else {
+		fmt.Println(fmt.Sprintf("    export %s=%s", constants.MiniShiftHomeEnv, newHome))
+	}
+}
+
+// stoppedMachine returns the VM of the specified profile, making sure it is stopped and can be relocated. If the
+// profile has no VM, nil is returned.
+func stoppedMachine(profileName string) *profileActions.Machine {
+	profileHome := constants.GetProfileHomeDir(profileName)
+	profileDirs := cmdState.GetMinishiftDirsStructure(profileHome)
+	api := libmachine.NewClient(profileDirs.Home, profileDirs.Certs)
+	defer api.Close()
+
+	if !cmdUtil.VMExists(api, profileName) {
+		return nil
+	}
+
+	host, err := api.Load(profileName)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error loading the VM of profile '%s': %v", profileName, err))
+	}
+
+	if !profileActions.SupportsRename(host.DriverName) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Relocating a VM of the '%s' driver is not supported. Delete the VM using 'minishift delete --profile %s' first.", host.DriverName, profileName))
+	}
+
+	// the generic driver manages an existing machine which is not stopped by Minishift
+	if host.DriverName != "generic" {
+		if vmState, err := host.Driver.GetState(); err != nil || vmState != state.Stopped {
+			atexit.ExitWithMessage(1, fmt.Sprintf("The VM of profile '%s' must be stopped to move the home directory. Run 'minishift stop --profile %s' first.", profileName, profileName))
+		}
+	}
+
+	return &profileActions.Machine{ProfileHome: profileHome, Name: profileName, DriverName: host.DriverName}
+}
+
+// instanceState returns the instance state of the specified profile, making sure its agent does not run, or nil if
+// the profile has no instance state.
+func instanceState(profileName string) *minishiftConfig.InstanceStateConfigType {
+	statePath := filepath.Join(constants.GetProfileHomeDir(profileName), "machines", profileName+"-state.json")
+	if !filehelper.Exists(statePath) {
+		return nil
+	}
+
+	stateConfig, err := minishiftConfig.NewInstanceStateConfig(statePath)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error reading the instance state of profile '%s': %v", profileName, err))
+	}
+	if process.IsRunning(stateConfig.AgentPID) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("The Minishift agent of profile '%s' uses the current home directory. Stop it using 'minishift daemon stop --uninstall --profile %s' first.", profileName, profileName))
+	}
+	return stateConfig
+}
+
+// stopDaemons stops the background processes of a profile which use the files of the current home directory, like
+// the host folder sync, the Docker forward and the IP watcher. They are started again by the next 'minishift start'.
+func stopDaemons(profileName string, stateConfig *minishiftConfig.InstanceStateConfigType) {
+	daemons := map[string]int{
+		"IP watcher":     stateConfig.IPWatchPID,
+		"Docker forward": stateConfig.DockerForwardPID,
+		"SFTP tunnel":    stateConfig.SftpTunnelPID,
+	}
+	for name, pid := range stateConfig.HostFolderSyncPIDs {
+		daemons[fmt.Sprintf("sync of host folder '%s'", name)] = pid
+	}
+
+	stopped := false
+	for name, pid := range daemons {
+		if !process.IsRunning(pid) {
+			continue
+		}
+		fmt.Println(fmt.Sprintf("-- Stopping the %s of profile '%s' (pid %d)", name, profileName, pid))
+		if err := process.Kill(pid); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error stopping the %s of profile '%s': %v", name, profileName, err))
+		}
+		stopped = true
+	}
+	if !stopped {
+		return
+	}
+
+	stateConfig.IPWatchPID = 0
+	stateConfig.DockerForwardPID = 0
+	stateConfig.SftpTunnelPID = 0
+	stateConfig.HostFolderSyncPIDs = nil
+	if err := stateConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error writing '%s': %v", stateConfig.FilePath, err))
+	}
+}
+
+func init() {
+	HomeCmd.AddCommand(homeMigrateCmd)
+}
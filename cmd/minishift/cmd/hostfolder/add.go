@@ -18,9 +18,11 @@ package hostfolder
 
 import (
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"strings"
 
+	"github.com/minishift/minishift/pkg/minikube/constants"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	hostFolderConfig "github.com/minishift/minishift/pkg/minishift/hostfolder"
 	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
@@ -40,11 +42,16 @@ const (
 	noPassword           = "you need to specify a password"
 	noDomain             = "you need to specify the Windows domain"
 	unknownType          = "'%s' is an unknown host folder type"
+	unknownTransport     = "'%s' is an unknown SSHFS transport"
+	noNFSOnWindows       = "NFS host folders are only supported on Linux and macOS hosts"
 	nonSupportedTtyError = "not a tty supported terminal"
 	shareTypeFlag        = "type"
 	sourceFlag           = "source"
 	targetFlag           = "target"
 	optionsFlag          = "options"
+	transportFlag        = "transport"
+	pullFlag             = "pull"
+	excludeFlag          = "exclude"
 	interactiveFlag      = "interactive"
 	instanceOnlyFlag     = "instance-only"
 	usersShareFlag       = "users-share"
@@ -58,6 +65,9 @@ var (
 	source       string
 	target       string
 	options      string
+	transport    string
+	pullPaths    []string
+	excludes     []string
 )
 
 var addCmd = &cobra.Command{
@@ -69,10 +79,13 @@ var addCmd = &cobra.Command{
 
 func init() {
 	HostFolderCmd.AddCommand(addCmd)
-	addCmd.Flags().StringVarP(&shareType, shareTypeFlag, "t", "sshfs", "The host folder type. Allowed types are [cifs|sshfs].")
+	addCmd.Flags().StringVarP(&shareType, shareTypeFlag, "t", "sshfs", "The host folder type. Allowed types are [cifs|nfs|sshfs|sync].")
 	addCmd.Flags().StringVar(&source, sourceFlag, "", "The source of the host folder.")
 	addCmd.Flags().StringVar(&target, targetFlag, "", "The target (mount point) of the host folder.")
 	addCmd.Flags().StringVar(&options, optionsFlag, "", "Host folder type specific options.")
+	addCmd.Flags().StringVar(&transport, transportFlag, hostFolderConfig.SSHFSTransportTunnel, fmt.Sprintf("How SSHFS host folders connect to the host. Allowed transports are [%s|%s].", hostFolderConfig.SSHFSTransportTunnel, hostFolderConfig.SSHFSTransportDirect))
+	addCmd.Flags().StringSliceVar(&pullPaths, pullFlag, nil, "Paths of a sync host folder which are copied back from the VM to the host.")
+	addCmd.Flags().StringSliceVar(&excludes, excludeFlag, nil, "Patterns of files and directories a sync host folder does not synchronize.")
 	addCmd.Flags().BoolVar(&instanceOnly, instanceOnlyFlag, false, "Defines the host folder only for the current Minishift instance.")
 	addCmd.Flags().BoolVarP(&interactive, interactiveFlag, "i", false, "Allows to interactively provide the required parameters.")
 
@@ -124,6 +137,21 @@ func addHostFolder(cmd *cobra.Command, args []string) {
 		} else {
 			addSSHFSNonInteractive(hostFolderManager, name)
 		}
+	case hostFolderConfig.NFS.String():
+		if runtime.GOOS == "windows" {
+			atexit.ExitWithMessage(1, noNFSOnWindows)
+		}
+		if interactive {
+			addNFSInteractive(hostFolderManager, name)
+		} else {
+			addNFSNonInteractive(hostFolderManager, name)
+		}
+	case hostFolderConfig.Sync.String():
+		if interactive {
+			addSyncInteractive(hostFolderManager, name)
+		} else {
+			addSyncNonInteractive(hostFolderManager, name)
+		}
 	default:
 		atexit.ExitWithMessage(1, fmt.Sprintf(unknownType, shareType))
 	}
@@ -152,6 +180,7 @@ func addSSHFSInteractive(manager *hostFolderConfig.Manager, name string) error {
 		Options: map[string]string{
 			config.Source:     source,
 			config.MountPoint: mountPath,
+			config.Transport:  hostFolderConfig.SSHFSTransportTunnel,
 		},
 	}
 	hostFolder := hostFolderConfig.NewSSHFSHostFolder(config, minishiftConfig.AllInstancesConfig)
@@ -173,6 +202,14 @@ func addSSHFSNonInteractive(manager *hostFolderConfig.Manager, name string) erro
 		atexit.ExitWithMessage(1, noTarget)
 	}
 
+	if transport != hostFolderConfig.SSHFSTransportTunnel && transport != hostFolderConfig.SSHFSTransportDirect {
+		atexit.ExitWithMessage(1, fmt.Sprintf(unknownTransport, transport))
+	}
+
+	if err := hostFolderConfig.ValidateSSHFSOptions(options); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
 	config := config.HostFolderConfig{
 		Name: name,
 		Type: hostFolderConfig.SSHFS.String(),
@@ -180,6 +217,7 @@ func addSSHFSNonInteractive(manager *hostFolderConfig.Manager, name string) erro
 			config.Source:       source,
 			config.MountPoint:   target,
 			config.ExtraOptions: options,
+			config.Transport:    transport,
 		},
 	}
 	hostFolder := hostFolderConfig.NewSSHFSHostFolder(config, minishiftConfig.AllInstancesConfig)
@@ -188,6 +226,103 @@ func addSSHFSNonInteractive(manager *hostFolderConfig.Manager, name string) erro
 	return nil
 }
 
+func addNFSInteractive(manager *hostFolderConfig.Manager, name string) {
+	source := util.ReadInputFromStdin("Source path")
+	source, err := homedir.Expand(source)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	if source == "" {
+		atexit.ExitWithMessage(1, noSource)
+	}
+
+	addNFS(manager, name, source, readInputForMountPoint(name), "")
+}
+
+func addNFSNonInteractive(manager *hostFolderConfig.Manager, name string) {
+	if source == "" {
+		atexit.ExitWithMessage(1, noSource)
+	}
+
+	if target == "" {
+		atexit.ExitWithMessage(1, noTarget)
+	}
+
+	addNFS(manager, name, source, target, options)
+}
+
+func addNFS(manager *hostFolderConfig.Manager, name string, source string, mountPoint string, options string) {
+	// the export needs an absolute path
+	absSource, err := filepath.Abs(source)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	config := config.HostFolderConfig{
+		Name: name,
+		Type: hostFolderConfig.NFS.String(),
+		Options: map[string]string{
+			config.Source:       absSource,
+			config.MountPoint:   mountPoint,
+			config.ExtraOptions: options,
+		},
+	}
+	hostFolder := hostFolderConfig.NewNFSHostFolder(config)
+	manager.Add(hostFolder, !instanceOnly)
+}
+
+func addSyncInteractive(manager *hostFolderConfig.Manager, name string) {
+	source := util.ReadInputFromStdin("Source path")
+	source, err := homedir.Expand(source)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	if source == "" {
+		atexit.ExitWithMessage(1, noSource)
+	}
+
+	mountPoint := readInputForMountPoint(name)
+	pullPaths := util.ReadInputFromStdin("Pull paths (comma separated)")
+	excludes := util.ReadInputFromStdin("Excludes (comma separated)")
+
+	addSync(manager, name, source, mountPoint, pullPaths, excludes)
+}
+
+func addSyncNonInteractive(manager *hostFolderConfig.Manager, name string) {
+	if source == "" {
+		atexit.ExitWithMessage(1, noSource)
+	}
+
+	if target == "" {
+		atexit.ExitWithMessage(1, noTarget)
+	}
+
+	addSync(manager, name, source, target, strings.Join(pullPaths, ","), strings.Join(excludes, ","))
+}
+
+func addSync(manager *hostFolderConfig.Manager, name string, source string, mountPoint string, pullPaths string, excludes string) {
+	// the synchronizing process does not run in the current working directory
+	absSource, err := filepath.Abs(source)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	config := config.HostFolderConfig{
+		Name: name,
+		Type: hostFolderConfig.Sync.String(),
+		Options: map[string]string{
+			config.Source:     absSource,
+			config.MountPoint: mountPoint,
+			config.PullPaths:  pullPaths,
+			config.Excludes:   excludes,
+		},
+	}
+	hostFolder := hostFolderConfig.NewSyncHostFolder(config)
+	manager.Add(hostFolder, !instanceOnly)
+}
+
 func addCIFSInteractive(manager *hostFolderConfig.Manager, name string) error {
 	var uncPath string
 	if usersShare {
@@ -212,11 +347,6 @@ func addCIFSInteractive(manager *hostFolderConfig.Manager, name string) error {
 		atexit.ExitWithMessage(1, noPassword)
 	}
 
-	password, err := util.EncryptText(password)
-	if err != nil {
-		return err
-	}
-
 	domain := util.ReadInputFromStdin("Domain")
 
 	config := config.HostFolderConfig{
@@ -225,12 +355,14 @@ func addCIFSInteractive(manager *hostFolderConfig.Manager, name string) error {
 		Options: map[string]string{
 			config.MountPoint:   mountPoint,
 			config.UncPath:      minishiftStrings.ConvertSlashes(uncPath),
-			config.UserName:     username,
-			config.Password:     password,
-			config.Domain:       domain,
 			config.ExtraOptions: options,
 		},
 	}
+	storeCIFSCredentials(config, hostFolderConfig.Credentials{
+		UserName: username,
+		Password: password,
+		Domain:   domain,
+	})
 	hostFolder := hostFolderConfig.NewCifsHostFolder(config)
 	manager.Add(hostFolder, !instanceOnly)
 
@@ -256,10 +388,10 @@ func addCIFSNonInteractive(manager *hostFolderConfig.Manager, name string) {
 		atexit.ExitWithMessage(1, noPassword)
 	}
 
-	var domain string
-	var ok bool
-	if domain, ok = optionsMap[config.Domain]; !ok {
-		domain = ""
+	credentials := hostFolderConfig.Credentials{
+		UserName: optionsMap[config.UserName],
+		Password: optionsMap[config.Password],
+		Domain:   optionsMap[config.Domain],
 	}
 
 	config := config.HostFolderConfig{
@@ -268,22 +400,43 @@ func addCIFSNonInteractive(manager *hostFolderConfig.Manager, name string) {
 		Options: map[string]string{
 			config.UncPath:    minishiftStrings.ConvertSlashes(source),
 			config.MountPoint: target,
-			config.UserName:   optionsMap[config.UserName],
-			config.Password:   optionsMap[config.Password],
-			config.Domain:     domain,
 		},
 	}
+	storeCIFSCredentials(config, credentials)
 	hostFolder := hostFolderConfig.NewCifsHostFolder(config)
 	manager.Add(hostFolder, !instanceOnly)
 }
 
+// storeCIFSCredentials stores the credentials in the keychain of the OS and references them from the options of the
+// host folder. If no keychain is available, for example on a headless Linux without a Secret Service, the credentials
+// are stored in the options, with the password encrypted, the way host folders were defined before.
+func storeCIFSCredentials(hostFolder config.HostFolderConfig, credentials hostFolderConfig.Credentials) {
+	key := hostFolderConfig.CredentialsKey(constants.ProfileName, hostFolder.Name, !instanceOnly)
+	err := hostFolderConfig.StoreCredentials(key, credentials)
+	if err == nil {
+		hostFolder.Options[config.CredentialsKey] = key
+		return
+	}
+
+	fmt.Println(fmt.Sprintf("   WARN: %s. The credentials are stored encrypted in the host folder configuration instead.", err))
+	password, err := util.EncryptText(credentials.Password)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error encrypting the password: %v", err))
+	}
+	hostFolder.Options[config.UserName] = credentials.UserName
+	hostFolder.Options[config.Password] = password
+	if credentials.Domain != "" {
+		hostFolder.Options[config.Domain] = credentials.Domain
+	}
+}
+
 // returns the name and full-name for shareType
 func readNameAndTypeInteractive() (string, string) {
 	name := util.ReadInputFromStdin("Name")
 	if name == "" {
 		atexit.ExitWithMessage(1, noName)
 	}
-	shareType := strings.ToLower(util.ReadInputFromStdin("Type [sshfs, cifs (S/c)]"))
+	shareType := strings.ToLower(util.ReadInputFromStdin("Type [sshfs, cifs, nfs, sync (S/c/n/y)]"))
 
 	if shareType == "s" || shareType == "" {
 		return name, hostFolderConfig.SSHFS.String()
@@ -293,5 +446,13 @@ func readNameAndTypeInteractive() (string, string) {
 		return name, hostFolderConfig.CIFS.String()
 	}
 
+	if shareType == "n" {
+		return name, hostFolderConfig.NFS.String()
+	}
+
+	if shareType == "y" {
+		return name, hostFolderConfig.Sync.String()
+	}
+
 	return name, shareType
 }
@@ -102,6 +102,7 @@ func Test_username_required(t *testing.T) {
 	atexit.RegisterExitHandler(cli.VerifyExitCodeAndMessage(t, tee, 1, noUserName))
 	source = "/home/johndoe"
 	target = "/var/tmp"
+	shareType = "cifs"
 	addHostFolder(nil, []string{"foo"})
 }
 
@@ -120,24 +121,7 @@ func Test_password_required(t *testing.T) {
 	source = "/home/johndoe"
 	target = "/var/tmp"
 	options = "username=johndoe"
-	addHostFolder(nil, []string{"foo"})
-}
-
-func Test_domain_required(t *testing.T) {
-	var err error
-	tmpMinishiftHomeDir := cli.SetupTmpMinishiftHome(t)
-	config.InstanceConfig, err = config.NewInstanceConfig(filepath.Join(tmpMinishiftHomeDir, "config"))
-	config.AllInstancesConfig, err = config.NewAllInstancesConfig(filepath.Join(tmpMinishiftHomeDir, "config"))
-	assert.NoError(t, err, "Unexpected error setting instance config")
-
-	tee := cli.CreateTee(t, true)
-	defer cli.TearDown(tmpMinishiftHomeDir, tee)
-	defer viper.Reset()
-
-	atexit.RegisterExitHandler(cli.VerifyExitCodeAndMessage(t, tee, 1, noDomain))
-	source = "/home/johndoe"
-	target = "/var/tmp"
-	options = "username=johndoe,password==,123"
+	shareType = "cifs"
 	addHostFolder(nil, []string{"foo"})
 }
 
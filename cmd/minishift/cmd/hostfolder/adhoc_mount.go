@@ -0,0 +1,130 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"runtime"
+	"syscall"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
+	"github.com/spf13/cobra"
+)
+
+const adHocMountUsage = "Usage: minishift mount SOURCE TARGET"
+
+var adHocMountOptions string
+
+// AdHocMountCmd mounts a host directory into the VM for the lifetime of the command
+var AdHocMountCmd = &cobra.Command{
+	Use:   "mount SOURCE TARGET",
+	Short: "Mounts a host directory into the Minishift VM until the command is interrupted.",
+	Long: `Mounts the host directory SOURCE at TARGET in the Minishift VM until the command is interrupted, for example with Ctrl+C.
+Unlike host folders, the mount is not persisted and not mounted again when the VM is restarted. The directory is mounted using SSHFS over a reverse SSH tunnel, which works with all drivers and does not open a port on the host.`,
+	Example: "  minishift mount ./src /home/docker/src",
+	Run:     runAdHocMount,
+}
+
+func runAdHocMount(cmd *cobra.Command, args []string) {
+	source, target, err := parseAdHocMountArgs(args)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	util.ExitIfUndefined(api, constants.MachineName)
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	util.ExitIfNotRunning(host.Driver, constants.MachineName)
+
+	hostFolderManager := getHostFolderManager()
+	hostFolder := newAdHocHostFolder(source, target, adHocMountOptions)
+	if err := hostFolderManager.MountTransient(host.Driver, hostFolder); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error mounting '%s' at '%s': %v", source, target, err))
+	}
+
+	fmt.Println(fmt.Sprintf("Mounted '%s' at '%s'. Press Ctrl+C to unmount.", source, target))
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+
+	fmt.Println(fmt.Sprintf("Unmounting '%s'", target))
+	if err := hostFolderManager.UmountTransient(host.Driver, hostFolder); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+}
+
+// parseAdHocMountArgs returns the absolute source directory on the host and the mount point in the VM.
+func parseAdHocMountArgs(args []string) (string, string, error) {
+	if len(args) != 2 {
+		return "", "", fmt.Errorf(adHocMountUsage)
+	}
+
+	source, err := filepath.Abs(args[0])
+	if err != nil {
+		return "", "", err
+	}
+	if fileInfo, err := os.Stat(source); err != nil || !fileInfo.IsDir() {
+		return "", "", fmt.Errorf("'%s' is not a directory on the host", args[0])
+	}
+
+	target := args[1]
+	if !path.IsAbs(target) || path.Clean(target) == "/" {
+		return "", "", fmt.Errorf("The target '%s' needs to be an absolute path other than '/'", target)
+	}
+	return source, path.Clean(target), nil
+}
+
+func newAdHocHostFolder(source string, target string, options string) hostfolder.HostFolder {
+	if runtime.GOOS == "windows" {
+		source = minishiftStrings.ConvertSlashes(source)
+	}
+
+	hostFolderConfig := config.HostFolderConfig{
+		Name: "mount-" + path.Base(target),
+		Type: hostfolder.SSHFS.String(),
+		Options: map[string]string{
+			config.Source:       source,
+			config.MountPoint:   target,
+			config.ExtraOptions: options,
+			config.Transport:    hostfolder.SSHFSTransportTunnel,
+		},
+	}
+	return hostfolder.NewSSHFSHostFolder(hostFolderConfig, minishiftConfig.AllInstancesConfig)
+}
+
+func init() {
+	AdHocMountCmd.Flags().StringVar(&adHocMountOptions, optionsFlag, "", "Additional options passed to the SSHFS mount.")
+}
@@ -0,0 +1,61 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ad_hoc_mount_args(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-mount-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	source, target, err := parseAdHocMountArgs([]string{testDir, "/home/docker/src/"})
+	assert.NoError(t, err)
+	assert.Equal(t, testDir, source)
+	assert.Equal(t, "/home/docker/src", target)
+
+	_, _, err = parseAdHocMountArgs([]string{testDir})
+	assert.EqualError(t, err, adHocMountUsage)
+
+	_, _, err = parseAdHocMountArgs([]string{filepath.Join(testDir, "missing"), "/home/docker/src"})
+	assert.Error(t, err, "Expected error for missing source directory")
+
+	_, _, err = parseAdHocMountArgs([]string{testDir, "src"})
+	assert.Error(t, err, "Expected error for relative target")
+
+	_, _, err = parseAdHocMountArgs([]string{testDir, "/"})
+	assert.Error(t, err, "Expected error for root target")
+}
+
+func Test_ad_hoc_host_folder(t *testing.T) {
+	hostFolder := newAdHocHostFolder("/tmp/src", "/home/docker/src", "-o ro")
+	hostFolderConfig := hostFolder.Config()
+
+	assert.Equal(t, "sshfs", hostFolderConfig.Type)
+	assert.Equal(t, "mount-src", hostFolderConfig.Name)
+	assert.Equal(t, "/home/docker/src", hostFolderConfig.MountPoint())
+	assert.Equal(t, "-o ro", hostFolderConfig.Option(config.ExtraOptions))
+	assert.Equal(t, "tunnel", hostFolderConfig.Option(config.Transport))
+}
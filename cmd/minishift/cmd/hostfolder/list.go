@@ -45,7 +45,7 @@ var listCmd = &cobra.Command{
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 4, 8, 3, ' ', 0)
-		fmt.Fprintln(w, "Name\tType\tSource\tMountpoint\tMounted")
+		fmt.Fprintln(w, "Name\tType\tSource\tMountpoint\tMounted\tHealth")
 
 		for _, info := range mountInfos {
 			mounted := "N"
@@ -54,12 +54,13 @@ var listCmd = &cobra.Command{
 			}
 
 			fmt.Fprintln(w,
-				fmt.Sprintf("%s\t%s\t%s\t%s\t%s",
+				fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s",
 					info.Name,
 					info.Type,
 					info.Source,
 					info.MountPoint,
-					mounted))
+					mounted,
+					info.Health))
 		}
 
 		w.Flush()
@@ -30,6 +30,7 @@ import (
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cache"
 	"github.com/minishift/minishift/pkg/minishift/docker/image"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
@@ -93,13 +94,16 @@ func exportImage(cmd *cobra.Command, args []string) {
 	}
 
 	imageCacheConfig := &image.ImageCacheConfig{
-		HostCacheDir:      state.InstanceDirs.ImageCache,
-		CachedImages:      normalizedImageNames,
-		Out:               out,
-		ImageMissStrategy: image.Pull,
+		HostCacheDir:       state.InstanceDirs.ImageCache,
+		CachedImages:       normalizedImageNames,
+		Out:                out,
+		ImageMissStrategy:  image.Pull,
+		Workers:            viper.GetInt(config.ImageCachingWorkers.Name),
+		InsecureRegistries: viper.GetStringSlice(config.InsecureRegistry.Name),
 	}
 
-	_, err = handler.ExportImages(imageCacheConfig, overwrite)
+	exportedImages, err := handler.ExportImages(imageCacheConfig, overwrite)
+	util.RecordCacheReferences(cache.ImageArtifact, exportedImages...)
 	if err != nil {
 		msg := fmt.Sprintf("Container image export failed:\n%v", err)
 		if logToFile {
@@ -26,6 +26,7 @@ import (
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cache"
 	"github.com/minishift/minishift/pkg/minishift/docker/image"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
@@ -98,9 +99,11 @@ func importImage(cmd *cobra.Command, args []string) {
 		CachedImages:      normalizedImageNames,
 		Out:               os.Stdout,
 		ImageMissStrategy: image.Skip,
+		Workers:           viper.GetInt(config.ImageCachingWorkers.Name),
 	}
 
 	importedImages, err := handler.ImportImages(imageCacheConfig)
+	util.RecordCacheReferences(cache.ImageArtifact, importedImages...)
 	if err != nil {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Container image import failed:\n%v", err))
 	}
@@ -18,19 +18,34 @@ package image
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	units "github.com/docker/go-units"
 	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/docker/image"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
 )
 
+const (
+	createdFormat = "2006-01-02 15:04"
+	notAvailable  = "-"
+)
+
 var (
 	dockerDaemonImages bool
+	imageDetails       bool
 
 	imageCacheListCmd = &cobra.Command{
 		Use:   "list ",
 		Short: "Displays the locally cached images.",
-		Long:  "Displays the locally cached images.",
+		Long:  "Displays the locally cached images. Use --vm to list the images of the Docker daemon instead or --details to list the images of both, including sizes and the pods using them.",
 		Run:   listImages,
 	}
 )
@@ -39,7 +54,9 @@ func listImages(cmd *cobra.Command, args []string) {
 	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
 	defer api.Close()
 
-	if dockerDaemonImages {
+	if imageDetails {
+		listImageInventory(api)
+	} else if dockerDaemonImages {
 		listDockerDaemonImages(api)
 	} else {
 		listCachedImages()
@@ -63,6 +80,64 @@ func listDockerDaemonImages(api *libmachine.Client) {
 	}
 }
 
+// listImageInventory prints the images of the Docker daemon and of the local cache together with their sizes,
+// creation dates and the pods using them. The Docker daemon is only queried if the VM is running.
+func listImageInventory(api *libmachine.Client) {
+	localHandler, err := image.NewLocalOnlyOciImageHandler()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Cannot create the image handler: %v", err))
+	}
+
+	cachedImages, err := localHandler.GetCachedImageDetails(&image.ImageCacheConfig{HostCacheDir: state.InstanceDirs.ImageCache})
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error reading the local image cache: %v", err))
+	}
+
+	var vmImages []image.ImageDetails
+	var pods map[string][]string
+	if host, err := api.Load(constants.MachineName); err == nil && util.IsHostRunning(host.Driver) {
+		envMap, err := cluster.GetHostDockerEnv(api)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error determining Docker daemon settings: %v", err))
+		}
+
+		handler, err := image.NewOciImageHandler(host.Driver, envMap)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Cannot create the image handler: %v", err))
+		}
+
+		if vmImages, err = handler.GetDockerImageDetails(); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error retrieving image list from Docker daemon: %v", err))
+		}
+		if pods, err = handler.GetPodsByImageID(); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error retrieving the pods of the Docker daemon: %v", err))
+		}
+	}
+
+	display := new(tabwriter.Writer)
+	display.Init(os.Stdout, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(display, "IMAGE\tVM SIZE\tCACHE SIZE\tCREATED\tPODS")
+	for _, entry := range image.BuildInventory(vmImages, cachedImages, pods) {
+		vmSize, cacheSize, created := notAvailable, notAvailable, notAvailable
+		if entry.VM != nil {
+			vmSize = units.HumanSize(float64(entry.VM.Size))
+			created = entry.VM.Created.Format(createdFormat)
+		}
+		if entry.Cache != nil {
+			cacheSize = units.HumanSize(float64(entry.Cache.Size))
+			if entry.VM == nil && !entry.Cache.Created.IsZero() {
+				created = entry.Cache.Created.Format(createdFormat)
+			}
+		}
+		podList := notAvailable
+		if len(entry.Pods) > 0 {
+			podList = strings.Join(entry.Pods, ",")
+		}
+		fmt.Fprintln(display, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", entry.Name, vmSize, cacheSize, created, podList))
+	}
+	display.Flush()
+}
+
 func printImageList(images []string) {
 	for _, i := range images {
 		fmt.Println(i)
@@ -71,5 +146,6 @@ func printImageList(images []string) {
 
 func init() {
 	imageCacheListCmd.Flags().BoolVar(&dockerDaemonImages, "vm", false, "Prints the available images in the Docker daemon.")
+	imageCacheListCmd.Flags().BoolVar(&imageDetails, "details", false, "Prints the images of the Docker daemon and the local cache with their sizes, creation dates and the pods using them.")
 	ImageCmd.AddCommand(imageCacheListCmd)
 }
@@ -0,0 +1,109 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/docker/image"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	fromHostFlag          = "from-host"
+	hostCliFlag           = "host-cli"
+	noHostImagesSpecified = "No images specified. Either pass the images as arguments or configure them via 'minishift config set host-images <image>[,<image>]'."
+)
+
+var (
+	loadFromHost bool
+	loadHostCli  string
+
+	imageLoadCmd = &cobra.Command{
+		Use:   "load [image ...]",
+		Short: "Loads the specified images from the container engine of the host into the Docker daemon.",
+		Long: `Loads the specified images from the container engine of the host into the Docker daemon.
+The images are exported via 'docker save' or 'podman save' on the host and streamed into the VM.
+If no images are specified, the images configured via 'host-images' are loaded.`,
+		Run: loadImages,
+	}
+)
+
+func loadImages(cmd *cobra.Command, args []string) {
+	if !loadFromHost {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Currently images can only be loaded from the host. Use the '--%s' flag.", fromHostFlag))
+	}
+
+	images := args
+	if len(images) == 0 {
+		images = viper.GetStringSlice(config.HostImages.Name)
+	}
+
+	if len(images) == 0 {
+		atexit.ExitWithMessage(0, noHostImagesSpecified)
+	}
+
+	normalizedImageNames, err := normalizeImageNames(images)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("%v contains an invalid image names:\n%v", images, err.Error()))
+	}
+
+	hostCli := loadHostCli
+	if hostCli == "" {
+		hostCli = viper.GetString(config.HostImagesCli.Name)
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	util.ExitIfUndefined(api, constants.MachineName)
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error creating the VM client: %v", err))
+	}
+
+	util.ExitIfNotRunning(host.Driver, constants.MachineName)
+
+	loader, err := image.NewHostImageLoader(host.Driver, hostCli)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	loadedImages, err := loader.LoadImages(normalizedImageNames, os.Stdout)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Loading images from the host failed:\n%v", err))
+	}
+
+	if len(loadedImages) < len(normalizedImageNames) {
+		atexit.ExitWithMessage(1, "At least one image could not be loaded.")
+	}
+}
+
+func init() {
+	imageLoadCmd.Flags().BoolVar(&loadFromHost, fromHostFlag, false, "Loads the images from the container engine of the host.")
+	imageLoadCmd.Flags().StringVar(&loadHostCli, hostCliFlag, "", fmt.Sprintf("The container engine client of the host to use. One of %v. Detected automatically if not specified.", image.SupportedHostClis))
+	ImageCmd.AddCommand(imageLoadCmd)
+}
@@ -18,12 +18,15 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/docker/machine/libmachine"
 	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	minishiftNetwork "github.com/minishift/minishift/pkg/minishift/network"
+	"github.com/minishift/minishift/pkg/minishift/network/ipwatch"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
 )
@@ -31,6 +34,9 @@ import (
 var (
 	configureAsStatic  bool
 	configureAsDynamic bool
+	watchIP            bool
+	watchInBackground  bool
+	watchInterval      time.Duration
 )
 
 // ipCmd represents the ip command
@@ -47,6 +53,12 @@ var ipCmd = &cobra.Command{
 		if configureAsStatic && configureAsDynamic {
 			atexit.ExitWithMessage(1, "Invalid options specified")
 		}
+		if watchInBackground && !watchIP {
+			atexit.ExitWithMessage(1, "The --background flag can only be used together with --watch")
+		}
+		if watchIP && (configureAsStatic || configureAsDynamic) {
+			atexit.ExitWithMessage(1, "The --watch flag cannot be used together with --set-static or --set-dhcp")
+		}
 
 		host, err := api.Load(constants.MachineName)
 		if err != nil {
@@ -54,7 +66,9 @@ var ipCmd = &cobra.Command{
 		}
 		cmdUtil.ExitIfNotRunning(host.Driver, constants.MachineName)
 
-		if configureAsDynamic {
+		if watchIP {
+			runIPWatch(api)
+		} else if configureAsDynamic {
 			minishiftNetwork.ConfigureDynamicAssignment(host.Driver)
 		} else if configureAsStatic {
 			msg, err := minishiftNetwork.ConfigureStaticAssignment(host.Driver)
@@ -75,6 +89,30 @@ var ipCmd = &cobra.Command{
 func init() {
 	ipCmd.Flags().BoolVar(&configureAsStatic, "set-static", false, "Sets the current assigned IP address as static address for the instance")
 	ipCmd.Flags().BoolVar(&configureAsDynamic, "set-dhcp", false, "Sets network configuration to use DHCP to assign IP address to the instance")
+	ipCmd.Flags().BoolVar(&watchIP, "watch", false, "Watches the IP address and updates the kubeconfig, certificates and DNS configuration when it changes")
+	ipCmd.Flags().BoolVar(&watchInBackground, "background", false, "Keeps watching the IP address in a background process. Used together with --watch")
+	ipCmd.Flags().DurationVar(&watchInterval, "interval", ipwatch.DefaultInterval, "The interval in which the IP address is checked. Used together with --watch")
 
 	RootCmd.AddCommand(ipCmd)
 }
+
+// runIPWatch reconciles changes of the IP of the VM, either until interrupted or in a background process.
+func runIPWatch(api libmachine.API) {
+	if watchInBackground {
+		if err := ipwatch.EnsureDaemonRunning(watchInterval); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error starting the IP watcher: %s", err.Error()))
+		}
+		fmt.Println(fmt.Sprintf("Watching the IP address in the background with pid %d", minishiftConfig.InstanceStateConfig.IPWatchPID))
+		return
+	}
+	if ipwatch.IsRunning() {
+		atexit.ExitWithMessage(1, fmt.Sprintf("The IP address is already watched in the background by pid %d", minishiftConfig.InstanceStateConfig.IPWatchPID))
+	}
+
+	watcher := cmdUtil.NewIPWatcher(api)
+	if _, err := watcher.Check(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting IP: %s", err.Error()))
+	}
+	fmt.Println(fmt.Sprintf("Watching the IP address %s. Press Ctrl-C to stop.", watcher.LastIP()))
+	watcher.Watch(watchInterval, make(chan struct{}))
+}
@@ -0,0 +1,32 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var IsoCmd = &cobra.Command{
+	Use:   "iso SUBCOMMAND [flags]",
+	Short: "Manages the ISO flavors used to provision the Minishift VM.",
+	Long: `Manages the ISO flavors used to provision the Minishift VM.
+An ISO flavor is a named ISO image together with the capabilities provisioning relies on, like the init system and the package manager of the image.
+Besides the built-in flavors, you can add aliases for your own ISO images via 'minishift iso add-alias'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
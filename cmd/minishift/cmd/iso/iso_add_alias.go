@@ -0,0 +1,59 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"fmt"
+
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/iso"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initSystem     string
+	packageManager string
+	dockerVersions []string
+
+	isoAddAliasCmd = &cobra.Command{
+		Use:   "add-alias ALIAS URL",
+		Short: "Adds an ISO flavor for a custom ISO image.",
+		Long: `Adds an ISO flavor for a custom ISO image, which all profiles can then select by its alias.
+The init system and the package manager of the image tell provisioning how to handle the image. An existing alias is replaced.`,
+		Run: runIsoAddAlias,
+	}
+)
+
+func runIsoAddAlias(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		atexit.ExitWithMessage(1, "usage: minishift iso add-alias ALIAS URL")
+	}
+
+	flavor := minishiftConfig.IsoFlavor{URL: args[1], InitSystem: initSystem, PackageManager: packageManager, DockerVersions: dockerVersions}
+	if err := iso.AddAlias(args[0], flavor); err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("Error adding the ISO alias '%s': %v", args[0], err))
+	}
+	fmt.Println(fmt.Sprintf("ISO alias '%s' added for '%s'", args[0], args[1]))
+}
+
+func init() {
+	isoAddAliasCmd.Flags().StringVar(&initSystem, "init-system", iso.InitSystemSystemd, fmt.Sprintf("The init system of the ISO image. Possible values: %v", iso.ValidInitSystems))
+	isoAddAliasCmd.Flags().StringVar(&packageManager, "package-manager", iso.PackageManagerNone, fmt.Sprintf("The package manager of the ISO image. Possible values: %v", iso.ValidPackageManagers))
+	isoAddAliasCmd.Flags().StringSliceVar(&dockerVersions, "docker-versions", nil, "The Docker engine versions which can be installed with the package manager of the ISO image.")
+	IsoCmd.AddCommand(isoAddAliasCmd)
+}
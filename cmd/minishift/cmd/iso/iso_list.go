@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/iso"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var isoListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the available ISO flavors.",
+	Long: `Lists the built-in ISO flavors and the ones added via 'minishift iso add-alias'.
+The flavor selected by the active profile is marked as active.`,
+	Run: runIsoList,
+}
+
+func runIsoList(cmd *cobra.Command, args []string) {
+	selected := iso.ForISO(selectedIso())
+
+	display := new(tabwriter.Writer)
+	display.Init(os.Stdout, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(display, "NAME\tINIT SYSTEM\tPACKAGE MANAGER\tURL\t")
+	for _, flavor := range iso.Flavors() {
+		status := ""
+		if flavor.Name == selected.Name {
+			status = "(Active)"
+		}
+		fmt.Fprintln(display, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", flavor.Name, flavor.InitSystem, flavor.PackageManager, flavor.URL, status))
+	}
+	if selected.Name == iso.CustomFlavorName {
+		fmt.Fprintln(display, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", selected.Name, "-", "-", selected.URL, "(Active)"))
+	}
+	display.Flush()
+}
+
+// selectedIso returns the ISO alias or URL configured for the active profile
+func selectedIso() string {
+	if isoURL := viper.GetString(configCmd.ISOUrl.Name); isoURL != "" {
+		return isoURL
+	}
+	return minishiftConstants.CentOsIsoAlias
+}
+
+func init() {
+	IsoCmd.AddCommand(isoListCmd)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"fmt"
+
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/iso"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var isoSetCmd = &cobra.Command{
+	Use:   "set FLAVOR|URL",
+	Short: "Selects the ISO flavor used by the active profile.",
+	Long: `Selects the ISO flavor, or the URL of an ISO image, used by the active profile.
+The selection is stored as the 'iso-url' setting of the profile and takes effect when the Minishift VM is created.`,
+	Run: runIsoSet,
+}
+
+func runIsoSet(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "usage: minishift iso set FLAVOR|URL")
+	}
+
+	if err := configCmd.Set(configCmd.ISOUrl.Name, args[0], true); err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
+
+	flavor := iso.ForISO(args[0])
+	fmt.Println(fmt.Sprintf("Profile '%s' uses the '%s' ISO flavor (%s)", constants.ProfileName, flavor.Name, flavor.URL))
+}
+
+func init() {
+	IsoCmd.AddCommand(isoSetCmd)
+}
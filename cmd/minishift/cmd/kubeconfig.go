@@ -0,0 +1,63 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/kubeconfig"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	systemAdminKubeConfig bool
+	printContextName      bool
+)
+
+// kubeConfigCmd represents the kubeconfig command
+var kubeConfigCmd = &cobra.Command{
+	Use:   "kubeconfig",
+	Short: "Prints the path of the kubeconfig file holding the context of the profile.",
+	Long: `Prints the path of the kubeconfig file holding the context of the profile. The context is named 'minishift/<profile>'.
+It becomes the current context when the cluster is started, the previous context is restored when the cluster is stopped or deleted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if printContextName {
+			fmt.Println(kubeconfig.ContextName(constants.ProfileName))
+			return
+		}
+
+		if systemAdminKubeConfig {
+			fmt.Println(constants.KubeConfigPath)
+			return
+		}
+
+		kubeConfigPath, err := oc.GetGlobalKubeConfigPath()
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error determining the kubeconfig path: %v", err))
+		}
+		fmt.Println(kubeConfigPath)
+	},
+}
+
+func init() {
+	kubeConfigCmd.Flags().BoolVar(&systemAdminKubeConfig, "system-admin", false, "Prints the path of the kubeconfig file with the system:admin credentials of the profile.")
+	kubeConfigCmd.Flags().BoolVar(&printContextName, "context", false, "Prints the name of the context of the profile instead of the path.")
+	RootCmd.AddCommand(kubeConfigCmd)
+}
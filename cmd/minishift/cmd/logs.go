@@ -19,17 +19,28 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/provision"
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
 )
 
 var (
-	follow bool
-	tail   int64
+	follow     bool
+	tail       int64
+	audit      bool
+	components []string
+	logUnits   []string
+	since      string
+	until      string
 )
 
 // logsCmd represents the logs command
@@ -40,6 +51,21 @@ var logsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
 		defer api.Close()
+		selectsSources := len(components) > 0 || len(logUnits) > 0
+		if audit {
+			if selectsSources || since != "" || until != "" {
+				atexit.ExitWithMessage(1, "The --audit flag cannot be used together with --component, --unit, --since or --until.")
+			}
+			printAuditLogs(api)
+			return
+		}
+		if selectsSources {
+			printComponentLogs(api)
+			return
+		}
+		if since != "" || until != "" {
+			atexit.ExitWithMessage(1, "The --since and --until flags require --component or --unit.")
+		}
 		s, err := cluster.GetHostLogs(api, follow, tail)
 		if err != nil {
 			atexit.ExitWithMessage(1, fmt.Sprintf("Error getting logs: %s", err.Error()))
@@ -51,5 +77,62 @@ var logsCmd = &cobra.Command{
 func init() {
 	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Continuously print the logs entries")
 	logsCmd.Flags().Int64VarP(&tail, "tail", "t", -1, "Number of lines to show from the end of the logs")
+	logsCmd.Flags().StringSliceVarP(&components, "component", "c", nil, fmt.Sprintf("Print the logs of the specified components only, each line prefixed by its component. Valid components are %s", strings.Join(openshift.LogComponents(), ", ")))
+	logsCmd.Flags().StringSliceVarP(&logUnits, "unit", "u", nil, "Print the journal of the specified systemd units of the VM, each line prefixed by its unit")
+	logsCmd.Flags().StringVar(&since, "since", "", "Only show entries since the specified time, either a duration like 10m or a timestamp like '2019-10-14 10:00:00'")
+	logsCmd.Flags().StringVar(&until, "until", "", "Only show entries until the specified time, either a duration like 5m, a timestamp or 'now'. Only supported for systemd units")
+	logsCmd.Flags().BoolVar(&audit, "audit", false, "Print the API audit log instead. Requires the audit-logging setting to be enabled")
 	RootCmd.AddCommand(logsCmd)
 }
+
+// printAuditLogs streams the audit logs of the API servers from the VM.
+func printAuditLogs(api libmachine.API) {
+	host, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting logs: %s", err.Error()))
+	}
+
+	for _, file := range openshift.AuditLogFiles() {
+		if _, err := host.RunSSHCommand(fmt.Sprintf("sudo test -f %s", file)); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("The API audit log '%s' does not exist. Use 'minishift config set %s on' and restart the cluster to enable it.", file, configCmd.AuditLogging.Name))
+		}
+	}
+
+	client, err := host.CreateSSHClient()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting logs: %s", err.Error()))
+	}
+	if err := client.Shell(openshift.AuditLogCommand(follow, tail)); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting logs: %s", err.Error()))
+	}
+}
+
+// printComponentLogs streams the logs of the selected components and systemd units from the VM, interleaved line by line.
+func printComponentLogs(api libmachine.API) {
+	host, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting logs: %s", err.Error()))
+	}
+
+	options := openshift.LogOptions{Follow: follow, Tail: tail, Since: since, Until: until}
+	dockerCommander := docker.NewVmDockerCommander(provision.GenericSSHCommander{Driver: host.Driver})
+	logs, err := openshift.ComponentLogCommands(components, options, dockerCommander)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting logs: %s", err.Error()))
+	}
+	unitLogs, err := openshift.UnitLogCommands(logUnits, options)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting logs: %s", err.Error()))
+	}
+	logs = append(logs, unitLogs...)
+
+	client, err := sshutil.NewSSHClient(host.Driver)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting logs: %s", err.Error()))
+	}
+	defer client.Close()
+
+	if err := openshift.StreamComponentLogs(client, logs, os.Stdout); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	// Namespace is the namespace the monitoring add-on deploys Prometheus, node-exporter and Grafana into
+	Namespace = "monitoring"
+)
+
+var MonitoringCmd = &cobra.Command{
+	Use:   "monitoring SUBCOMMAND [flags]",
+	Short: "Provides access to the monitoring stack deployed by the 'monitoring' add-on.",
+	Long:  `Provides access to the Prometheus and Grafana instances deployed by the 'monitoring' add-on.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
@@ -0,0 +1,99 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+const (
+	grafanaService    = "grafana"
+	prometheusService = "prometheus"
+)
+
+var (
+	prometheus bool
+	inBrowser  bool
+)
+
+var urlCmd = &cobra.Command{
+	Use:   "url",
+	Short: "Prints the URL of Grafana or Prometheus.",
+	Long:  "Prints the route URL of the Grafana dashboard deployed by the 'monitoring' add-on. Use --prometheus to print the URL of the Prometheus UI instead.",
+	Run:   runURL,
+}
+
+func init() {
+	urlCmd.Flags().BoolVar(&prometheus, "prometheus", false, "Print the URL of Prometheus instead of Grafana.")
+	urlCmd.Flags().BoolVar(&inBrowser, "in-browser", false, "Open the URL in the default browser.")
+	MonitoringCmd.AddCommand(urlCmd)
+}
+
+func runURL(cmd *cobra.Command, args []string) {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	util.ExitIfUndefined(api, constants.MachineName)
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	util.ExitIfNotRunning(host.Driver, constants.MachineName)
+
+	serviceName := grafanaService
+	if prometheus {
+		serviceName = prometheusService
+	}
+
+	services, err := openshift.GetServices(Namespace)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("%s. Make sure the 'monitoring' add-on is applied.", err.Error()))
+	}
+
+	url := routeURL(services, serviceName)
+	if url == "" {
+		atexit.ExitWithMessage(1, fmt.Sprintf("No route found for service '%s' in namespace '%s'. Make sure the 'monitoring' add-on is applied.", serviceName, Namespace))
+	}
+
+	if inBrowser {
+		fmt.Println(fmt.Sprintf("Opening %s in the default browser...", url))
+		browser.OpenURL(url)
+		return
+	}
+	fmt.Println(url)
+}
+
+// routeURL returns the first route URL of the named service or the empty string if the service has no route.
+func routeURL(services []openshift.Service, name string) string {
+	for _, service := range services {
+		if service.Name == name && len(service.URL) > 0 {
+			return service.URL[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cache"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var ocListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the cached oc binaries.",
+	Long: `Lists the oc binaries cached for the current OS and architecture.
+The binary used by the active profile is marked as active, followed by the profiles using each binary.`,
+	Run: runOcList,
+}
+
+func runOcList(cmd *cobra.Command, args []string) {
+	versions := cache.CachedOcVersions(state.InstanceDirs.Cache)
+	if len(versions) == 0 {
+		atexit.ExitWithMessage(0, "No oc binaries are cached.")
+	}
+
+	display := new(tabwriter.Writer)
+	display.Init(os.Stdout, 0, 8, 2, '\t', 0)
+	for _, version := range versions {
+		ocBinary := cache.Oc{OpenShiftVersion: version, MinishiftCacheDir: state.InstanceDirs.Cache}
+		status := ""
+		if filepath.Join(ocBinary.GetCacheFilepath(), constants.OC_BINARY_NAME) == minishiftConfig.InstanceStateConfig.OcPath {
+			status = "(Active)"
+		}
+
+		usedBy := ""
+		if profiles := ocReferences(ocBinary); len(profiles) > 0 {
+			usedBy = fmt.Sprintf("used by: %s", strings.Join(profiles, ", "))
+		}
+		fmt.Fprintln(display, fmt.Sprintf("- %s\t%s\t%s", version, status, usedBy))
+	}
+	display.Flush()
+}
+
+// ocReferences returns the profiles which use the specified oc binary
+func ocReferences(ocBinary cache.Oc) []string {
+	if minishiftConfig.AllInstancesConfig == nil {
+		return nil
+	}
+	return minishiftConfig.AllInstancesConfig.CacheReferences[cache.ArtifactKey(cache.OcArtifact, ocBinary.GetCacheFilepath())]
+}
+
+func init() {
+	OcCmd.AddCommand(ocListCmd)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cache"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	forceRemoval bool
+
+	ocRemoveCmd = &cobra.Command{
+		Use:   "remove VERSION",
+		Short: "Removes a cached oc binary.",
+		Long: `Removes the cached oc binary of the specified version for the current OS and architecture.
+Binaries used by a profile are only removed if --force is specified.`,
+		Run: runOcRemove,
+	}
+)
+
+func runOcRemove(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "usage: minishift oc remove VERSION")
+	}
+	version := args[0]
+	if err := minishiftConfig.IsValidOcVersion("", version); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	ocBinary := cache.Oc{OpenShiftVersion: version, MinishiftCacheDir: state.InstanceDirs.Cache}
+
+	if !forceRemoval {
+		if filepath.Join(ocBinary.GetCacheFilepath(), constants.OC_BINARY_NAME) == minishiftConfig.InstanceStateConfig.OcPath {
+			atexit.ExitWithMessage(1, fmt.Sprintf("The oc binary version '%s' is used by the active profile. Select a different version via 'minishift oc use' first.", version))
+		}
+		if profiles := ocReferences(ocBinary); len(profiles) > 0 {
+			atexit.ExitWithMessage(1, fmt.Sprintf("The oc binary version '%s' is used by the profiles '%s'. Use --force to remove it anyway.", version, strings.Join(profiles, ", ")))
+		}
+	}
+
+	if err := cache.RemoveCachedOc(state.InstanceDirs.Cache, version); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error removing the oc binary version '%s': %v", version, err))
+	}
+
+	if minishiftConfig.AllInstancesConfig != nil && ocReferences(ocBinary) != nil {
+		delete(minishiftConfig.AllInstancesConfig.CacheReferences, cache.ArtifactKey(cache.OcArtifact, ocBinary.GetCacheFilepath()))
+		if err := minishiftConfig.AllInstancesConfig.Write(); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error updating the cache references: %v", err))
+		}
+	}
+	fmt.Println(fmt.Sprintf("Removed the oc binary version '%s'", version))
+}
+
+func init() {
+	ocRemoveCmd.Flags().BoolVarP(&forceRemoval, "force", "f", false, "Removes the oc binary even if it is used by a profile.")
+	OcCmd.AddCommand(ocRemoveCmd)
+}
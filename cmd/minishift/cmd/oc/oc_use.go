@@ -0,0 +1,94 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oc
+
+import (
+	"fmt"
+
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cache"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// autoVersion selects the oc version matching the OpenShift version of the cluster
+	autoVersion = "auto"
+)
+
+var ocUseCmd = &cobra.Command{
+	Use:   "use VERSION|auto",
+	Short: "Selects the oc version used by the active profile.",
+	Long: `Selects the oc version used by the active profile. The binary is downloaded and verified against the checksums
+of the OpenShift release if it is not cached yet. Use 'auto' to use the oc version matching the OpenShift version of the cluster again.`,
+	Run: runOcUse,
+}
+
+func runOcUse(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "usage: minishift oc use VERSION|auto")
+	}
+	version := args[0]
+
+	if version == autoVersion {
+		if err := unsetOcVersion(); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error updating the configuration of profile '%s': %v", constants.ProfileName, err))
+		}
+	} else {
+		if err := configCmd.Set(configCmd.OcVersion.Name, version, false); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+		viper.Set(configCmd.OcVersion.Name, version)
+	}
+
+	openShiftVersion := minishiftConfig.InstanceStateConfig.OpenshiftVersion
+	if openShiftVersion == "" {
+		if version == autoVersion {
+			fmt.Println(fmt.Sprintf("Profile '%s' will use the oc version matching the OpenShift version of the cluster", constants.ProfileName))
+			return
+		}
+
+		ocBinary := cache.Oc{OpenShiftVersion: version, MinishiftCacheDir: state.InstanceDirs.Cache}
+		if err := ocBinary.EnsureIsCached(); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+		fmt.Println(fmt.Sprintf("Profile '%s' will use oc version '%s' once the cluster is started", constants.ProfileName, version))
+		return
+	}
+
+	ocPath := cmdUtil.CacheOc(openShiftVersion)
+	fmt.Println(fmt.Sprintf("Profile '%s' uses oc version '%s' (%s)", constants.ProfileName, cmdUtil.SelectedOcVersion(openShiftVersion), ocPath))
+}
+
+func unsetOcVersion() error {
+	conf, err := minishiftConfig.ReadViperConfig(constants.ConfigFile)
+	if err != nil {
+		return err
+	}
+	delete(conf, configCmd.OcVersion.Name)
+	viper.Set(configCmd.OcVersion.Name, "")
+	return minishiftConfig.WriteViperConfig(constants.ConfigFile, conf)
+}
+
+func init() {
+	OcCmd.AddCommand(ocUseCmd)
+}
@@ -29,13 +29,15 @@ import (
 	"github.com/minishift/minishift/pkg/minikube/constants"
 	"github.com/minishift/minishift/pkg/minishift/config"
 	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	minishiftOs "github.com/minishift/minishift/pkg/util/os"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/minishift/minishift/pkg/util/shell"
 	"github.com/spf13/cobra"
 )
 
 const (
-	ocEnvTmpl = `{{ .Prefix }}PATH{{ .Delimiter }}{{ .OcDirPath }}{{ .PathSuffix }}{{ if .NoProxyVar }}{{ .Prefix }}{{ .NoProxyVar }}{{ .Delimiter }}{{ .NoProxyValue }}{{ .Suffix }}{{end}}{{ .UsageHint }}`
+	ocEnvTmpl      = `{{ .Prefix }}PATH{{ .Delimiter }}{{ .OcDirPath }}{{ .PathSuffix }}{{ if .NoProxyVar }}{{ .Prefix }}{{ .NoProxyVar }}{{ .Delimiter }}{{ .NoProxyValue }}{{ .Suffix }}{{end}}{{ .UsageHint }}`
+	ocEnvUnsetTmpl = `{{ .Prefix }}PATH{{ .Delimiter }}{{ .PathValue }}{{ .Suffix }}{{ if .NoProxyVar }}{{ .UnsetPrefix }}{{ .NoProxyVar }}{{ .UnsetDelimiter }}{{ .UnsetSuffix }}{{end}}{{ .UsageHint }}`
 )
 
 type OcShellConfig struct {
@@ -44,6 +46,12 @@ type OcShellConfig struct {
 	UsageHint    string
 	NoProxyVar   string
 	NoProxyValue string
+
+	// PathValue is the value of PATH without the directory of the oc binary, used when unsetting the environment
+	PathValue      string
+	UnsetPrefix    string
+	UnsetDelimiter string
+	UnsetSuffix    string
 }
 
 func getOcShellConfig(api libmachine.API, ocPath string, forcedShell string, noProxy bool) (*OcShellConfig, error) {
@@ -77,16 +85,70 @@ func getOcShellConfig(api libmachine.API, ocPath string, forcedShell string, noP
 	return shellCfg, nil
 }
 
-func executeOcTemplateStdout(shellCfg *OcShellConfig) error {
-	tmpl := template.Must(template.New("envConfig").Parse(ocEnvTmpl))
+// getOcShellConfigUnset returns the shell configuration removing the directory of the oc binary from the PATH
+// of the calling shell. The variable holding the no-proxy settings is cleared if noProxy is set.
+func getOcShellConfigUnset(ocPath string, pathValue string, forcedShell string, noProxy bool) (*OcShellConfig, error) {
+	userShell, err := shell.GetShell(forcedShell)
+	if err != nil {
+		return nil, err
+	}
+
+	cmdLine := "minishift oc-env --unset"
+	if constants.ProfileName != profileActions.GetActiveProfile() {
+		cmdLine = fmt.Sprintf("minishift oc-env --unset --profile=%s", constants.ProfileName)
+	}
+
+	// without a recorded oc binary, there is nothing to remove from PATH
+	ocDirPath := ""
+	if ocPath != "" {
+		ocDirPath = filepath.Dir(ocPath)
+	}
+	shellCfg := &OcShellConfig{
+		OcDirPath: ocDirPath,
+		PathValue: shell.RemoveFromPath(userShell, pathValue, ocDirPath),
+	}
+
+	if noProxy {
+		cmdLine = cmdLine + " --no-proxy"
+		shellCfg.NoProxyVar, _ = shell.FindNoProxyFromEnv()
+	}
+
+	shellCfg.UsageHint = shell.GenerateUsageHint(userShell, cmdLine)
+	shellCfg.Prefix, shellCfg.Delimiter, shellCfg.Suffix, _ = shell.GetPrefixSuffixDelimiterForSet(userShell)
+	shellCfg.UnsetPrefix, shellCfg.UnsetSuffix, shellCfg.UnsetDelimiter = shell.GetPrefixSuffixDelimiterForUnSet(userShell)
+
+	return shellCfg, nil
+}
+
+func executeOcTemplateStdout(shellCfg *OcShellConfig, envTemplate string) error {
+	tmpl := template.Must(template.New("envConfig").Parse(envTemplate))
 	return tmpl.Execute(os.Stdout, shellCfg)
 }
 
+var (
+	psModule bool
+)
+
 var ocEnvCmd = &cobra.Command{
 	Use:   "oc-env",
 	Short: "Sets the path of the 'oc' binary.",
 	Long:  `Sets the path of OpenShift client binary 'oc'.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if psModule {
+			printPowerShellModule()
+			return
+		}
+
+		if unset {
+			// leaving the environment does not require a running VM
+			shellCfg, err := getOcShellConfigUnset(config.InstanceStateConfig.OcPath, os.Getenv("PATH"), forceShell, noProxy)
+			if err != nil {
+				atexit.ExitWithMessage(1, fmt.Sprintf("Error unsetting environment variables: %s", err.Error()))
+			}
+			executeOcTemplateStdout(shellCfg, ocEnvUnsetTmpl)
+			return
+		}
+
 		if config.InstanceStateConfig.OcPath == "" {
 			atexit.ExitWithMessage(1, "Cannot find the OpenShift client binary.\nMake sure that OpenShift was provisioned successfully.")
 		}
@@ -110,7 +172,7 @@ var ocEnvCmd = &cobra.Command{
 			atexit.ExitWithMessage(1, fmt.Sprintf("Error running the oc-env command: %s", err.Error()))
 		}
 
-		executeOcTemplateStdout(shellCfg)
+		executeOcTemplateStdout(shellCfg, ocEnvTmpl)
 	},
 }
 
@@ -122,4 +184,16 @@ func init() {
 		ocEnvCmd.Flags().BoolVar(&noProxy, "no-proxy", false, "Add the virtual machine IP to the no_proxy/NO_PROXY environment variable.")
 	}
 	ocEnvCmd.Flags().StringVar(&forceShell, "shell", "", "Force setting the environment for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh]. Default is auto-detect.")
+	ocEnvCmd.Flags().BoolVarP(&unset, "unset", "u", false, "Remove the directory of the 'oc' binary from the PATH instead of adding it.")
+	ocEnvCmd.Flags().BoolVar(&psModule, "ps-module", false, "Print the PowerShell function Invoke-MinishiftEnv, which sets up a PowerShell session for oc, docker and podman.")
+}
+
+// printPowerShellModule prints PowerShell helpers calling the running Minishift executable, so that they also work
+// if Minishift is not on the PATH.
+func printPowerShellModule() {
+	executable, err := minishiftOs.CurrentExecutable()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error determining the path of the Minishift executable: %s", err.Error()))
+	}
+	fmt.Print(shell.PowerShellModule(executable))
 }
@@ -34,3 +34,23 @@ func Test_unix_oc_path(t *testing.T) {
 	expectedOcDirPath := "/Users/john/.minishift/cache/oc/v1.5.0"
 	assert.Equal(t, shellConfig.OcDirPath, expectedOcDirPath)
 }
+
+func Test_unset_removes_oc_dir_from_path(t *testing.T) {
+	pathValue := "/Users/john/.minishift/cache/oc/v1.5.0:/usr/bin:/bin"
+	shellConfig, err := getOcShellConfigUnset("/Users/john/.minishift/cache/oc/v1.5.0/oc", pathValue, "bash", true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/usr/bin:/bin", shellConfig.PathValue)
+	assert.Equal(t, "unset ", shellConfig.UnsetPrefix)
+	assert.NotEmpty(t, shellConfig.NoProxyVar)
+	assert.Contains(t, shellConfig.UsageHint, "--no-proxy")
+
+	shellConfig, err = getOcShellConfigUnset("/Users/john/.minishift/cache/oc/v1.5.0/oc", pathValue, "fish", false)
+	assert.NoError(t, err)
+	assert.Equal(t, `/usr/bin" "/bin`, shellConfig.PathValue)
+	assert.Empty(t, shellConfig.NoProxyVar)
+
+	shellConfig, err = getOcShellConfigUnset("", ".:/usr/bin", "bash", false)
+	assert.NoError(t, err)
+	assert.Equal(t, ".:/usr/bin", shellConfig.PathValue)
+}
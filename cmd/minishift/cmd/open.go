@@ -0,0 +1,195 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+const portForwardTimeout = 15 * time.Second
+
+var (
+	openURLMode     bool
+	openPortForward bool
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open [component]",
+	Short: "Opens the web UI of a component in the default browser.",
+	Long: fmt.Sprintf(`Opens the web UI of a component in the default browser. Supported components are %s.
+The route of the component is used if it is exposed, otherwise a port-forward to its service is set up until the command is interrupted.
+Without a component, the components and whether they are installed are listed.`, strings.Join(openshift.WebComponentNames(), ", ")),
+	Run: runOpen,
+}
+
+func init() {
+	openCmd.Flags().BoolVar(&openURLMode, "url", false, "Print the URL instead of opening the browser.")
+	openCmd.Flags().BoolVar(&openPortForward, "port-forward", false, "Use a port-forward even if the component is exposed by a route.")
+	RootCmd.AddCommand(openCmd)
+}
+
+func runOpen(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		atexit.ExitWithMessage(1, "You can only specify one component.")
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	cmdUtil.ExitIfUndefined(api, constants.MachineName)
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	cmdUtil.ExitIfNotRunning(host.Driver, constants.MachineName)
+
+	if len(args) == 1 && args[0] == openshift.ConsoleComponent {
+		url, err := cluster.GetConsoleURL(api)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Cannot access the OpenShift console: %v", err))
+		}
+		openComponentURL(openshift.ConsoleComponent, url)
+		return
+	}
+
+	ocRunner, err := oc.NewOcRunner(minishiftConfig.InstanceStateConfig.OcPath, constants.KubeConfigPath)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	if len(args) == 0 {
+		listWebComponents(ocRunner)
+		return
+	}
+
+	component, err := openshift.GetWebComponent(args[0])
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	if !component.IsInstalled(ocRunner) {
+		msg := fmt.Sprintf("Component '%s' is not installed.", component.Name)
+		if component.AddOn != "" {
+			msg = fmt.Sprintf("%s Apply the '%s' add-on first.", msg, component.AddOn)
+		}
+		atexit.ExitWithMessage(1, msg)
+	}
+
+	if !openPortForward {
+		url, err := component.RouteURL(ocRunner)
+		if err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+		if url != "" {
+			openComponentURL(component.Name, url)
+			return
+		}
+	}
+
+	portForward(ocRunner, component)
+}
+
+func listWebComponents(ocRunner *oc.OcRunner) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tSTATUS")
+	fmt.Fprintln(w, fmt.Sprintf("%s\tinstalled", openshift.ConsoleComponent))
+	for _, name := range openshift.WebComponentNames()[1:] {
+		component, _ := openshift.GetWebComponent(name)
+		status := "not installed"
+		if component.IsInstalled(ocRunner) {
+			status = "installed"
+		}
+		fmt.Fprintln(w, fmt.Sprintf("%s\t%s", name, status))
+	}
+	w.Flush()
+}
+
+func openComponentURL(name string, url string) {
+	if openURLMode {
+		fmt.Println(url)
+		return
+	}
+	fmt.Println(fmt.Sprintf("Opening %s (%s) in the default browser...", name, url))
+	browser.OpenURL(url)
+}
+
+// portForward forwards a free local port to the service of the component and opens it. The command blocks until the
+// port-forward terminates, which happens when the user interrupts the command.
+func portForward(ocRunner *oc.OcRunner, component openshift.WebComponent) {
+	localPort, err := freeLocalPort()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error finding a free local port: %v", err))
+	}
+
+	forwardCmd := exec.Command(ocRunner.OcPath, component.PortForwardArgs(ocRunner, localPort)...)
+	if err := forwardCmd.Start(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error starting the port-forward to '%s': %v", component.Name, err))
+	}
+
+	address := fmt.Sprintf("localhost:%d", localPort)
+	if err := waitForPort(address, portForwardTimeout); err != nil {
+		forwardCmd.Process.Kill()
+		atexit.ExitWithMessage(1, fmt.Sprintf("The port-forward to '%s' did not become ready: %v", component.Name, err))
+	}
+
+	fmt.Println(fmt.Sprintf("Forwarding %s to service '%s' in namespace '%s'. Press Ctrl+C to stop.", address, component.Service, component.Namespace))
+	openComponentURL(component.Name, "http://"+address)
+	forwardCmd.Wait()
+}
+
+func freeLocalPort() (int, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+func waitForPort(address string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
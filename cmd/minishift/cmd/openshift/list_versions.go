@@ -21,28 +21,47 @@ import (
 
 	"fmt"
 
+	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cache"
 	openshiftVersions "github.com/minishift/minishift/pkg/minishift/openshift/version"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/minishift/minishift/pkg/version"
 	"github.com/spf13/cobra"
 )
 
-// getVersionsCmd represents the ip command
-var getVersionsCmd = &cobra.Command{
-	Use:   "list",
-	Short: "Gets the list of OpenShift versions that are available for Minishift.",
-	Long:  `Gets the list of OpenShift versions that are available for Minishift.`,
-	Run:   runVersionList,
-}
+var (
+	offline bool
+
+	// getVersionsCmd represents the ip command
+	getVersionsCmd = &cobra.Command{
+		Use:   "list",
+		Short: "Gets the list of OpenShift versions that are available for Minishift.",
+		Long: `Gets the list of OpenShift versions that are available for Minishift.
+The upstream releases are cached for a day. Versions for which the oc binary is cached are marked as cached,
+versions which cannot be provisioned by this Minishift release are marked as not supported.`,
+		Run: runVersionList,
+	}
+)
 
 func runVersionList(cmd *cobra.Command, args []string) {
-	err := openshiftVersions.PrintUpstreamVersions(os.Stdout, constants.MinimumSupportedOpenShiftVersion, version.GetOpenShiftVersion())
+	tags, err := openshiftVersions.GetReleases(state.InstanceDirs.Cache, offline)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error while trying to get list of available Origin versions: %s", err.Error()))
+	}
+
+	releases, err := openshiftVersions.ListReleases(tags, cache.CachedOcVersions(state.InstanceDirs.Cache), constants.MinimumSupportedOpenShiftVersion, version.GetOpenShiftVersion())
 	if err != nil {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Error while trying to get list of available Origin versions: %s", err.Error()))
 	}
+
+	if len(releases) == 0 {
+		atexit.ExitWithMessage(0, "No OpenShift versions are known offline. Run the command without --offline to fetch the upstream releases.")
+	}
+	openshiftVersions.PrintReleases(os.Stdout, releases)
 }
 
 func init() {
+	getVersionsCmd.Flags().BoolVar(&offline, "offline", false, "Lists the cached upstream releases and the versions with a cached oc binary without querying GitHub.")
 	versionCmd.AddCommand(getVersionsCmd)
 }
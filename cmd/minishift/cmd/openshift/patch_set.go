@@ -0,0 +1,40 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	noPatchSetsMessage   = "No patch sets are stored for this profile."
+	unknownPatchSetError = "There is no patch set named '%s'."
+)
+
+var patchSetCmd = &cobra.Command{
+	Use:   "patch-set SUBCOMMAND [flags]",
+	Short: "Manages the named OpenShift configuration patch sets of the profile.",
+	Long: `Manages the named OpenShift configuration patch sets of the profile. Patch sets are created with
+'minishift openshift config set --name' and are re-applied on every start.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(patchSetCmd)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var patchSetApplyCmd = &cobra.Command{
+	Use:   "apply [PATCH_SET_NAME]",
+	Short: "Applies the patch sets of the profile to the running OpenShift cluster.",
+	Long:  "Applies all patch sets of the profile or only the specified one to the running OpenShift cluster. Patch sets which are already applied are skipped.",
+	Run:   runPatchSetApply,
+}
+
+func init() {
+	patchSetCmd.AddCommand(patchSetApplyCmd)
+}
+
+func runPatchSetApply(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		atexit.ExitWithMessage(1, "You can specify at most one patch set name.")
+	}
+
+	patchSets := minishiftConfig.InstanceConfig.PatchSets
+	if len(args) == 1 {
+		patchSet, ok := patchSets[args[0]]
+		if !ok {
+			atexit.ExitWithMessage(1, fmt.Sprintf(unknownPatchSetError, args[0]))
+		}
+		patchSets = map[string]*minishiftConfig.PatchSet{args[0]: patchSet}
+	}
+
+	if len(patchSets) == 0 {
+		fmt.Println(noPatchSetsMessage)
+		return
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	host, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		atexit.ExitWithMessage(1, nonExistentMachineError)
+	}
+
+	sshCommander := provision.GenericSSHCommander{Driver: host.Driver}
+	dockerCommander := docker.NewVmDockerCommander(sshCommander)
+	if !openshift.IsRunning(dockerCommander) {
+		atexit.ExitWithMessage(1, nonExistentMachineError)
+	}
+
+	err = openshift.ApplyPatchSets(patchSets, dockerCommander)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var patchSetRemoveCmd = &cobra.Command{
+	Use:   "remove PATCH_SET_NAME",
+	Short: "Removes the specified patch set from the profile.",
+	Long: `Removes the specified patch set from the profile, so that it is no longer applied on start.
+The current OpenShift configuration is not changed.`,
+	Run: runPatchSetRemove,
+}
+
+func init() {
+	patchSetCmd.AddCommand(patchSetRemoveCmd)
+}
+
+func runPatchSetRemove(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You must specify the name of the patch set to remove.")
+	}
+
+	name := args[0]
+	if _, ok := minishiftConfig.InstanceConfig.PatchSets[name]; !ok {
+		atexit.ExitWithMessage(1, fmt.Sprintf(unknownPatchSetError, name))
+	}
+
+	delete(minishiftConfig.InstanceConfig.PatchSets, name)
+	if err := minishiftConfig.InstanceConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error removing patch set '%s': %v", name, err))
+	}
+	fmt.Println(fmt.Sprintf("Patch set '%s' removed.", name))
+}
@@ -0,0 +1,53 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/spf13/cobra"
+)
+
+var patchSetViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Displays the patch sets stored for the profile.",
+	Long:  "Displays the patch sets stored for the profile in the order they get applied.",
+	Run:   runPatchSetView,
+}
+
+func init() {
+	patchSetCmd.AddCommand(patchSetViewCmd)
+}
+
+func runPatchSetView(cmd *cobra.Command, args []string) {
+	patchSets := minishiftConfig.InstanceConfig.PatchSets
+	if len(patchSets) == 0 {
+		fmt.Println(noPatchSetsMessage)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTARGET\tPATCH")
+	for _, name := range openshift.PatchSetNames(patchSets) {
+		fmt.Fprintln(w, fmt.Sprintf("%s\t%s\t%s", name, patchSets[name].Target, patchSets[name].Patch))
+	}
+	w.Flush()
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/docker/machine/libmachine/provision"
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/minishift/docker"
 	"github.com/minishift/minishift/pkg/minishift/openshift"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
@@ -35,6 +36,7 @@ import (
 const (
 	targetFlag = "target"
 	patchFlag  = "patch"
+	nameFlag   = "name"
 
 	unknownPatchTargetError = "Unkown patch target. Only 'master', 'node' and 'kube' are supported."
 	emptyPatchError         = "You must specify a patch using the --patch flag."
@@ -42,20 +44,23 @@ const (
 )
 
 var (
-	target string
-	patch  string
+	target    string
+	patch     string
+	patchName string
 )
 
 var setCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Patches the OpenShift configuration resource with the specified patch.",
-	Long:  "Patches the OpenShift configuration resource with the specified patch. The patch must be a valid JSON file.",
-	Run:   runPatch,
+	Long: `Patches the OpenShift configuration resource with the specified patch. The patch must be a valid JSON file.
+If a name is specified, the patch is stored as patch set in the profile and re-applied on every start.`,
+	Run: runPatch,
 }
 
 func init() {
 	setCmd.Flags().StringVar(&target, targetFlag, "master", "Target configuration to patch. Options are 'master', 'node' and 'kube'.")
 	setCmd.Flags().StringVar(&patch, patchFlag, "", "The patch to apply.")
+	setCmd.Flags().StringVar(&patchName, nameFlag, "", "Stores the patch as named patch set which is re-applied on every start.")
 	configCmd.AddCommand(setCmd)
 }
 
@@ -67,11 +72,22 @@ func runPatch(cmd *cobra.Command, args []string) {
 
 	validatePatch(patch)
 
+	if patchName != "" {
+		minishiftConfig.InstanceConfig.PatchSets[patchName] = &minishiftConfig.PatchSet{Target: target, Patch: patch}
+		if err := minishiftConfig.InstanceConfig.Write(); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error storing patch set '%s': %v", patchName, err))
+		}
+		fmt.Println(fmt.Sprintf("Patch set '%s' stored. It will be re-applied on every start.", patchName))
+	}
+
 	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
 	defer api.Close()
 
 	host, err := cluster.CheckIfApiExistsAndLoad(api)
 	if err != nil {
+		if patchName != "" {
+			return
+		}
 		atexit.ExitWithMessage(1, nonExistentMachineError)
 	}
 
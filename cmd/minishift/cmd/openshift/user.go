@@ -0,0 +1,48 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	cmdConfig "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	noUsersMessage   = "No users are declared for this profile."
+	unknownUserError = "There is no user named '%s' declared for this profile."
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user SUBCOMMAND [flags]",
+	Short: "Manages the OpenShift users declared in the profile.",
+	Long: `Manages the OpenShift users declared in the profile. Declared users, their passwords, cluster roles and
+security context constraints are reconciled on every start.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+func init() {
+	OpenShiftCmd.AddCommand(userCmd)
+}
+
+// isIdentityProviderManaged returns true if the identity provider follows the declared users, i.e. if it is not
+// configured explicitly via identity-provider.
+func isIdentityProviderManaged() bool {
+	return viper.GetString(cmdConfig.IdentityProvider.Name) == ""
+}
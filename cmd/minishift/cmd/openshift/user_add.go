@@ -0,0 +1,108 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	stringUtils "github.com/minishift/minishift/pkg/util/strings"
+	"github.com/spf13/cobra"
+)
+
+const (
+	passwordFlag    = "password"
+	clusterRoleFlag = "cluster-role"
+	sccFlag         = "scc"
+)
+
+var (
+	userPassword     string
+	userClusterRoles []string
+	userSCCs         []string
+)
+
+var userAddCmd = &cobra.Command{
+	Use:   "add USER_NAME [flags]",
+	Short: "Declares an OpenShift user in the profile.",
+	Long: `Declares an OpenShift user in the profile or updates the declaration of an existing one. The user is
+reconciled right away if the OpenShift cluster is running and on every start.
+
+Once any declared user has a password, OpenShift only accepts the passwords of the declared users instead of accepting
+any password.`,
+	Example: `  minishift openshift user add developer --password developer --cluster-role cluster-admin
+  minishift openshift user add builder --scc anyuid`,
+	Run: runUserAdd,
+}
+
+func init() {
+	userAddCmd.Flags().StringVar(&userPassword, passwordFlag, "", "The password of the user. Only a hash of the password is stored.")
+	userAddCmd.Flags().StringSliceVar(&userClusterRoles, clusterRoleFlag, nil, "Cluster role to bind to the user. Can be specified multiple times.")
+	userAddCmd.Flags().StringSliceVar(&userSCCs, sccFlag, nil, "Security context constraint to add to the user. Can be specified multiple times.")
+	userCmd.AddCommand(userAddCmd)
+}
+
+func runUserAdd(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You must specify the name of the user to add.")
+	}
+
+	name := args[0]
+	if err := openshift.ValidateUserName(name); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	user, ok := minishiftConfig.InstanceConfig.Users[name]
+	if !ok {
+		user = &minishiftConfig.User{}
+		minishiftConfig.InstanceConfig.Users[name] = user
+	}
+	if userPassword != "" {
+		hash, err := openshift.HashPassword(userPassword)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error hashing the password of user '%s': %v", name, err))
+		}
+		user.PasswordHash = hash
+	}
+	user.ClusterRoles = appendMissing(user.ClusterRoles, userClusterRoles)
+	user.SCCs = appendMissing(user.SCCs, userSCCs)
+
+	if err := minishiftConfig.InstanceConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error storing user '%s': %v", name, err))
+	}
+	fmt.Println(fmt.Sprintf("User '%s' stored. It will be reconciled on every start.", name))
+
+	dockerCommander, ocRunner, running, err := util.RunningOpenShiftCluster()
+	if running && err == nil {
+		err = openshift.ReconcileUsers(minishiftConfig.InstanceConfig.Users, isIdentityProviderManaged(), dockerCommander, ocRunner)
+	}
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error reconciling user '%s': %v", name, err))
+	}
+}
+
+func appendMissing(values []string, additions []string) []string {
+	for _, value := range additions {
+		if !stringUtils.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,59 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/spf13/cobra"
+)
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the OpenShift users declared in the profile.",
+	Long:  "Lists the OpenShift users declared in the profile together with their cluster roles and security context constraints.",
+	Run:   runUserList,
+}
+
+func init() {
+	userCmd.AddCommand(userListCmd)
+}
+
+func runUserList(cmd *cobra.Command, args []string) {
+	users := minishiftConfig.InstanceConfig.Users
+	if len(users) == 0 {
+		fmt.Println(noUsersMessage)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPASSWORD\tCLUSTER ROLES\tSCCS")
+	for _, name := range openshift.UserNames(users) {
+		user := users[name]
+		password := "no"
+		if user.PasswordHash != "" {
+			password = "yes"
+		}
+		fmt.Fprintln(w, fmt.Sprintf("%s\t%s\t%s\t%s", name, password, strings.Join(user.ClusterRoles, ","), strings.Join(user.SCCs, ",")))
+	}
+	w.Flush()
+}
@@ -0,0 +1,70 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var userRemoveCmd = &cobra.Command{
+	Use:   "remove USER_NAME",
+	Short: "Removes the specified user from the profile.",
+	Long: `Removes the specified user from the profile. If the OpenShift cluster is running, the user, its identity and
+its cluster role and security context constraint bindings are deleted from the cluster as well.`,
+	Run: runUserRemove,
+}
+
+func init() {
+	userCmd.AddCommand(userRemoveCmd)
+}
+
+func runUserRemove(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You must specify the name of the user to remove.")
+	}
+
+	name := args[0]
+	users := minishiftConfig.InstanceConfig.Users
+	user, ok := users[name]
+	if !ok {
+		atexit.ExitWithMessage(1, fmt.Sprintf(unknownUserError, name))
+	}
+
+	delete(users, name)
+	if err := minishiftConfig.InstanceConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error removing user '%s': %v", name, err))
+	}
+
+	dockerCommander, ocRunner, running, err := util.RunningOpenShiftCluster()
+	if running && err == nil {
+		err = openshift.RemoveUser(name, user, users, isIdentityProviderManaged(), dockerCommander, ocRunner)
+	}
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error removing user '%s' from the cluster: %v", name, err))
+	}
+	if running {
+		fmt.Println(fmt.Sprintf("User '%s' removed from the profile and the cluster.", name))
+	} else {
+		fmt.Println(fmt.Sprintf("User '%s' removed from the profile.", name))
+	}
+}
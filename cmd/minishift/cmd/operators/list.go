@@ -0,0 +1,63 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/pkg/minishift/operators"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var catalog string
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the operators of the installed catalogs.",
+	Long:  "Lists the operators of the catalogs installed by the 'olm' add-on together with their provider and channels.",
+	Run:   runList,
+}
+
+func init() {
+	listCmd.Flags().StringVar(&catalog, "catalog", "", "Only list the operators of the specified catalog source.")
+	OperatorsCmd.AddCommand(listCmd)
+}
+
+func runList(cmd *cobra.Command, args []string) {
+	_, ocRunner := util.RunningCluster()
+
+	available, err := operators.List(ocRunner, catalog)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	if len(available) == 0 {
+		fmt.Println("No operators found. The catalog might still be loading.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDISPLAY NAME\tPROVIDER\tCATALOG\tDEFAULT CHANNEL\tCHANNELS")
+	for _, operator := range available {
+		fmt.Fprintln(w, fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s", operator.Name, operator.DisplayName, operator.Provider, operator.Catalog, operator.DefaultChannel, strings.Join(operator.Channels, ",")))
+	}
+	w.Flush()
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/minishift/minishift/pkg/util/shell"
+	"github.com/spf13/cobra"
+)
+
+const (
+	podmanEnvTmpl = `{{ .Prefix }}PODMAN_VARLINK_BRIDGE{{ .Delimiter }}{{ .VarlinkBridge }}{{ .Suffix }}{{ .UsageHint }}`
+)
+
+type PodmanShellConfig struct {
+	shell.ShellConfig
+	VarlinkBridge string
+	UsageHint     string
+}
+
+// podmanVarlinkBridge returns the command the podman remote client uses to connect to the varlink service of the VM
+func podmanVarlinkBridge(sshUser string, ip string, port int, keyPath string) string {
+	return fmt.Sprintf(`ssh -F /dev/null -o IdentitiesOnly=yes -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -o LogLevel=quiet -i %s -p %d %s@%s -- sudo varlink -A \'podman varlink \\\$VARLINK_ADDRESS\' bridge`,
+		keyPath, port, sshUser, ip)
+}
+
+func getPodmanConfigSet(driver drivers.Driver, forceShell string) (*PodmanShellConfig, error) {
+	userShell, err := shell.GetShell(forceShell)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, err := driver.GetSSHHostname()
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := driver.GetSSHPort()
+	if err != nil {
+		return nil, err
+	}
+
+	cmdLine := "minishift podman-env"
+	if constants.ProfileName != profileActions.GetActiveProfile() {
+		cmdLine = fmt.Sprintf("minishift podman-env --profile=%s", constants.ProfileName)
+	}
+
+	shellCfg := &PodmanShellConfig{
+		VarlinkBridge: podmanVarlinkBridge(driver.GetSSHUsername(), ip, port, driver.GetSSHKeyPath()),
+	}
+	shellCfg.UsageHint = shell.GenerateUsageHint(userShell, cmdLine)
+	shellCfg.Prefix, shellCfg.Delimiter, shellCfg.Suffix, _ = shell.GetPrefixSuffixDelimiterForSet(userShell)
+
+	return shellCfg, nil
+}
+
+func getPodmanConfigUnset(forceShell string) (*PodmanShellConfig, error) {
+	userShell, err := shell.GetShell(forceShell)
+	if err != nil {
+		return nil, err
+	}
+
+	shellCfg := &PodmanShellConfig{}
+	shellCfg.UsageHint = shell.GenerateUsageHint(userShell, "minishift podman-env --unset")
+	shellCfg.Prefix, shellCfg.Suffix, shellCfg.Delimiter = shell.GetPrefixSuffixDelimiterForUnSet(userShell)
+
+	return shellCfg, nil
+}
+
+func executePodmanTemplateStdout(shellCfg *PodmanShellConfig) error {
+	tmpl := template.Must(template.New("envConfig").Parse(podmanEnvTmpl))
+	return tmpl.Execute(os.Stdout, shellCfg)
+}
+
+var podmanEnvCmd = &cobra.Command{
+	Use:   "podman-env",
+	Short: "Sets the environment for the podman remote client.",
+	Long:  `Sets the environment for the podman remote client, so that 'podman' on the host builds and runs containers in the Minishift VM.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var (
+			shellCfg *PodmanShellConfig
+			err      error
+		)
+		if unset {
+			shellCfg, err = getPodmanConfigUnset(forceShell)
+			if err != nil {
+				atexit.ExitWithMessage(1, fmt.Sprintf("Error unsetting environment variables: %s", err.Error()))
+			}
+			executePodmanTemplateStdout(shellCfg)
+			return
+		}
+
+		api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+		defer api.Close()
+
+		util.ExitIfUndefined(api, constants.MachineName)
+
+		host, err := api.Load(constants.MachineName)
+		if err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+
+		util.ExitIfNotRunning(host.Driver, constants.MachineName)
+
+		if _, err := host.RunSSHCommand("which podman varlink"); err != nil {
+			atexit.ExitWithMessage(1, "The Minishift VM does not provide podman. Use an ISO which contains podman and varlink.")
+		}
+
+		shellCfg, err = getPodmanConfigSet(host.Driver, forceShell)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error setting environment variables: %s", err.Error()))
+		}
+
+		executePodmanTemplateStdout(shellCfg)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(podmanEnvCmd)
+	podmanEnvCmd.Flags().StringVar(&forceShell, "shell", "", "Force setting the environment for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh]. Default is auto-detect.")
+	podmanEnvCmd.Flags().BoolVarP(&unset, "unset", "u", false, "Clear the environment variable values instead of setting them.")
+}
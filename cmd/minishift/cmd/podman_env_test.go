@@ -0,0 +1,30 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_podman_varlink_bridge(t *testing.T) {
+	bridge := podmanVarlinkBridge("docker", "192.168.99.100", 22, "/home/john/.minishift/machines/minishift/id_rsa")
+
+	assert.Contains(t, bridge, "-i /home/john/.minishift/machines/minishift/id_rsa -p 22 docker@192.168.99.100")
+	assert.Contains(t, bridge, `-- sudo varlink -A \'podman varlink \\\$VARLINK_ADDRESS\' bridge`)
+}
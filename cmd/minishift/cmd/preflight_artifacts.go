@@ -18,10 +18,12 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
 	"github.com/minishift/minishift/pkg/minishift/oc"
 	"github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -68,3 +70,28 @@ func determineInitialClusterupParameters() []string {
 
 	return clusterUpParams
 }
+
+// validateClusterUpFlags validates the flags of the cluster-up-flags setting against the flags supported by
+// 'oc cluster up' of the selected OpenShift version and returns them. Unlike the other checks it cannot be skipped.
+func validateClusterUpFlags() []string {
+	flags := oc.GroupClusterUpFlags(viper.GetStringSlice(configCmd.ClusterUpFlags.Name))
+	if len(flags) == 0 {
+		return nil
+	}
+
+	for _, flag := range flags {
+		name, err := oc.ClusterUpFlagName(flag)
+		if err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+		if clusterUpFlagSet.Lookup(name) != nil || name == "base-dir" {
+			atexit.ExitWithMessage(1, fmt.Sprintf("The flag '--%s' is managed by Minishift and cannot be passed via '%s'. Use 'minishift config set %s' instead.", name, configCmd.ClusterUpFlags.Name, name))
+		}
+	}
+
+	if err := oc.ValidateClusterUpFlags(flags, ocPath, &util.RealRunner{}); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Invalid '%s' setting: %v", configCmd.ClusterUpFlags.Name, err))
+	}
+	fmt.Printf("-- Passing extra flags to 'oc cluster up' ... %s\n", strings.Join(flags, " "))
+	return flags
+}
@@ -0,0 +1,32 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var PresetCmd = &cobra.Command{
+	Use:   "preset SUBCOMMAND [flags]",
+	Short: "Manages the presets used by 'minishift start --preset'.",
+	Long: `Manages the presets used by 'minishift start --preset'. A preset bundles the sizing, the add-ons and the flags of a
+profile. Minishift ships the 'developer', 'ci' and 'demo' presets. User-defined presets are stored as YAML files in
+the presets directory of the Minishift home directory and can be shared within a team.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
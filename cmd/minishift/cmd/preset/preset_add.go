@@ -0,0 +1,57 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+import (
+	"fmt"
+
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/preset"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var presetAddCmd = &cobra.Command{
+	Use:   "add PRESET_FILE",
+	Short: "Adds a user-defined preset.",
+	Long: `Validates a preset file and copies it into the presets directory. The preset is available under the name of the
+file without its extension. An existing preset of the same name, including a built-in preset, is replaced.`,
+	Run: runPresetAdd,
+}
+
+func runPresetAdd(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You must specify the preset file to add.")
+	}
+
+	p, err := preset.ReadFile(args[0])
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error reading the preset: %v", err))
+	}
+	if err := configCmd.ValidatePreset(p); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	if _, err := preset.Add(minishiftConstants.GetPresetsDir(), args[0]); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error adding the preset: %v", err))
+	}
+	fmt.Println(fmt.Sprintf("Preset '%s' added. Run 'minishift start --preset %s' to use it.", p.Name, p.Name))
+}
+
+func init() {
+	PresetCmd.AddCommand(presetAddCmd)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/preset"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var presetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the available presets.",
+	Long:  "Lists the presets shipped with Minishift and the user-defined presets of the presets directory.",
+	Run:   runPresetList,
+}
+
+func runPresetList(cmd *cobra.Command, args []string) {
+	presets, err := preset.List(minishiftConstants.GetPresetsDir())
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error listing the presets: %v", err))
+	}
+
+	display := new(tabwriter.Writer)
+	display.Init(os.Stdout, 0, 8, 2, '\t', 0)
+	fmt.Fprintln(display, "NAME\tSOURCE\tDESCRIPTION")
+	for _, p := range presets {
+		source := "built-in"
+		if p.Source != "" {
+			source = p.Source
+		}
+		fmt.Fprintln(display, fmt.Sprintf("%s\t%s\t%s", p.Name, source, p.Description))
+	}
+	display.Flush()
+}
+
+func init() {
+	PresetCmd.AddCommand(presetListCmd)
+}
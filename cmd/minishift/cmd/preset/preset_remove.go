@@ -0,0 +1,49 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+import (
+	"fmt"
+
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/preset"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var presetRemoveCmd = &cobra.Command{
+	Use:   "remove PRESET_NAME",
+	Short: "Removes a user-defined preset.",
+	Long: `Removes a user-defined preset from the presets directory. If the preset replaced a built-in preset of the same
+name, the built-in preset is used again.`,
+	Run: runPresetRemove,
+}
+
+func runPresetRemove(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You must specify the name of the preset to remove.")
+	}
+
+	if err := preset.Remove(minishiftConstants.GetPresetsDir(), args[0]); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error removing the preset: %v", err))
+	}
+	fmt.Println(fmt.Sprintf("Preset '%s' removed.", args[0]))
+}
+
+func init() {
+	PresetCmd.AddCommand(presetRemoveCmd)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+import (
+	"fmt"
+
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/preset"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var presetViewCmd = &cobra.Command{
+	Use:   "view PRESET_NAME",
+	Short: "Displays the content of a preset.",
+	Long: `Displays the content of a preset in YAML format. Redirect the output into a file to share the preset or to use it as
+the starting point of a user-defined preset.`,
+	Run: runPresetView,
+}
+
+func runPresetView(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "You must specify the name of the preset to view.")
+	}
+
+	p, err := preset.Load(minishiftConstants.GetPresetsDir(), args[0])
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	out, err := p.YAML()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error displaying the preset '%s': %v", p.Name, err))
+	}
+	fmt.Print(out)
+}
+
+func init() {
+	PresetCmd.AddCommand(presetViewCmd)
+}
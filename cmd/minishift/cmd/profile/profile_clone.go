@@ -0,0 +1,115 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	cmdState "github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cloneConfigOnly bool
+
+	profileCloneCmd = &cobra.Command{
+		Use:   "clone SRC_PROFILE_NAME NEW_PROFILE_NAME",
+		Short: "Clones an existing profile including its VM.",
+		Long: `Clones the configuration, add-ons and add-on state of an existing profile into a new profile. If the driver of the
+profile supports it, the stopped VM is cloned as well as a linked clone, sharing the disk image of the source VM up to
+the time of the clone. With other drivers, or with --config-only, a new VM is created on the first start of the new profile.`,
+		Run: runProfileClone,
+	}
+)
+
+func runProfileClone(cmd *cobra.Command, args []string) {
+	validateCopyProfileCmd(args)
+	srcProfile := args[0]
+	newProfile := args[1]
+	cmdUtil.LockProfilesOrExit(srcProfile, newProfile)
+
+	if !cmdUtil.IsValidProfile(srcProfile) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Profile %s does not exist", srcProfile))
+	}
+
+	if cmdUtil.IsValidProfile(newProfile) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Profile '%s' already exists. You must provide a non-existant profile name", newProfile))
+	}
+
+	srcHome := constants.GetProfileHomeDir(srcProfile)
+	newHome := constants.GetProfileHomeDir(newProfile)
+
+	driverName := ""
+	if !cloneConfigOnly {
+		driverName = clonableDriver(srcProfile)
+	}
+
+	if err := profileActions.Clone(srcHome, srcProfile, newHome, newProfile); err != nil {
+		os.RemoveAll(newHome)
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error cloning profile '%s': %v", srcProfile, err))
+	}
+
+	if driverName != "" {
+		fmt.Println(fmt.Sprintf("-- Creating a linked clone of the '%s' VM ... ", srcProfile))
+		if err := profileActions.CloneMachine(srcHome, srcProfile, newHome, newProfile, driverName); err != nil {
+			os.RemoveAll(newHome)
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error cloning the VM of profile '%s': %v", srcProfile, err))
+		}
+	}
+	cmdUtil.CreateMinishiftDirs(cmdState.GetMinishiftDirsStructure(newHome))
+
+	fmt.Println(fmt.Sprintf("Profile '%s' is cloned successfully from profile '%s'", newProfile, srcProfile))
+}
+
+// clonableDriver returns the name of the driver of the VM of the profile if the VM can be cloned. If the profile has
+// no VM or the driver does not support linked clones, an empty string is returned.
+func clonableDriver(profileName string) string {
+	profileDirs := cmdState.GetMinishiftDirsStructure(constants.GetProfileHomeDir(profileName))
+	api := libmachine.NewClient(profileDirs.Home, profileDirs.Certs)
+	defer api.Close()
+
+	if !cmdUtil.VMExists(api, profileName) {
+		return ""
+	}
+
+	host, err := api.Load(profileName)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error loading the VM of profile '%s': %v", profileName, err))
+	}
+
+	if !profileActions.SupportsLinkedClone(host.DriverName) {
+		fmt.Println(fmt.Sprintf("The '%s' driver does not support cloning the VM. Only the configuration of profile '%s' is cloned.", host.DriverName, profileName))
+		return ""
+	}
+
+	if vmState, err := host.Driver.GetState(); err != nil || vmState != state.Stopped {
+		atexit.ExitWithMessage(1, fmt.Sprintf("The VM of profile '%s' must be stopped to be cloned. Run 'minishift stop --profile %s' first or pass --config-only.", profileName, profileName))
+	}
+	return host.DriverName
+}
+
+func init() {
+	profileCloneCmd.Flags().BoolVar(&cloneConfigOnly, "config-only", false, "Clone the configuration only, without cloning the VM.")
+	ProfileCmd.AddCommand(profileCloneCmd)
+}
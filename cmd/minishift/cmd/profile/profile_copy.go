@@ -44,6 +44,7 @@ func copyProfile(cmd *cobra.Command, args []string) {
 	validateCopyProfileCmd(args)
 	srcProfile := args[0]
 	newProfile := args[1]
+	cmdUtil.LockProfilesOrExit(srcProfile, newProfile)
 
 	if !cmdUtil.IsValidProfile(srcProfile) {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Profile %s does not exist", srcProfile))
@@ -19,20 +19,29 @@ package profile
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
 
 	"github.com/docker/machine/libmachine"
-	"github.com/golang/glog"
+	"github.com/minishift/minishift/cmd/minishift/cmd/config"
 	registrationUtil "github.com/minishift/minishift/cmd/minishift/cmd/registration"
 	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
 	cmdState "github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder"
 	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
 	pkgUtil "github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/filehelper"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+var logger = logging.For("profile")
+
 var (
 	profileDeleteCmd = &cobra.Command{
 		Use:   "delete PROFILE_NAME",
@@ -41,11 +50,13 @@ var (
 		Run:   runProfileDelete,
 	}
 	forceProfileDeletion bool
+	cleanCache           bool
 )
 
 func runProfileDelete(cmd *cobra.Command, args []string) {
 	validateArgs(args)
 	profileName := args[0]
+	cmdUtil.LockProfilesOrExit(profileName)
 
 	if !cmdUtil.IsValidProfile(profileName) {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Error: '%s' is not a valid profile", profileName))
@@ -55,6 +66,8 @@ func runProfileDelete(cmd *cobra.Command, args []string) {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Default profile '%s' can not be deleted", profileName))
 	}
 
+	cmdUtil.ExitIfProtected(profileName, forceProfileDeletion)
+
 	if !forceProfileDeletion {
 		var hasConfirmed bool
 		if profileActions.GetActiveProfile() == profileName {
@@ -79,9 +92,15 @@ func runProfileDelete(cmd *cobra.Command, args []string) {
 			return
 		}
 
-		if glog.V(2) {
-			fmt.Println(fmt.Sprintf("Deleted: Minishift VM '%s'", constants.MachineName))
-		}
+		logger.Debugf("Deleted: Minishift VM '%s'", constants.MachineName)
+	}
+
+	if err := hostfolder.RemoveNFSExports(runtime.GOOS, profileName); err != nil {
+		fmt.Println("Unable to remove the NFS exports of the host folders:", err)
+	}
+
+	if _, err := cmdUtil.RemoveKubeConfigEntries(profileName); err != nil {
+		fmt.Println("Unable to delete entries from kube config:", err)
 	}
 
 	err := os.RemoveAll(profileDirs.Home)
@@ -89,12 +108,19 @@ func runProfileDelete(cmd *cobra.Command, args []string) {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Error deleting '%s': %v", profileDirs.Home, err.Error()))
 	}
 
-	if glog.V(2) {
-		fmt.Println(fmt.Sprintf("Deleted: '%s'", profileDirs.Home))
-	}
+	logger.Debugf("Deleted: '%s'", profileDirs.Home)
 
 	fmt.Println(fmt.Sprintf("Profile '%s' deleted successfully.", profileName))
 
+	if err := profileActions.SetProtected(profileName, false); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	if err := profileActions.ClearLabels(profileName); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	cleanOrphanedCacheArtifacts(profileName)
+
 	// When active profile is deleted, reset the active profile to default profile
 	if profileActions.GetActiveProfile() == profileName {
 		fmt.Println(fmt.Sprintf("Switching to default profile '%s' as the active profile.", constants.DefaultProfileName))
@@ -105,7 +131,65 @@ func runProfileDelete(cmd *cobra.Command, args []string) {
 	}
 }
 
+// cleanOrphanedCacheArtifacts removes the references of the deleted profile to cached artifacts and
+// deletes the artifacts which are not used by any other profile, if requested
+func cleanOrphanedCacheArtifacts(profileName string) {
+	if minishiftConfig.AllInstancesConfig == nil || minishiftConfig.AllInstancesConfig.CacheReferences == nil {
+		return
+	}
+
+	orphaned := minishiftConfig.AllInstancesConfig.CacheReferences.RemoveProfile(profileName)
+	if err := minishiftConfig.AllInstancesConfig.Write(); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error updating cache references: %v", err))
+	}
+	orphaned = append(orphaned, untrackedCacheArtifacts()...)
+
+	if len(orphaned) == 0 {
+		return
+	}
+
+	shouldClean := cleanCache || viper.GetBool(config.AutoCleanCache.Name)
+	if !shouldClean {
+		if forceProfileDeletion {
+			fmt.Println(fmt.Sprintf("%d cached artifacts are not used by any profile anymore. Use '--clean-cache' to delete them.", len(orphaned)))
+			return
+		}
+		shouldClean = pkgUtil.AskForConfirmation(fmt.Sprintf("%d cached artifacts are not used by any profile anymore. Delete them?", len(orphaned)))
+	}
+
+	if !shouldClean {
+		return
+	}
+
+	if err := cmdUtil.DeleteCachedArtifacts(orphaned); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error deleting cached artifacts: %v", err))
+	}
+	fmt.Println(fmt.Sprintf("Deleted %d cached artifacts.", len(orphaned)))
+}
+
+// untrackedCacheArtifacts returns the artifacts in the shared cache which no profile references. These are left over by
+// profiles created before cache references were recorded. As long as a remaining profile with a VM has not recorded
+// its references yet, such artifacts might still be in use and none are returned.
+func untrackedCacheArtifacts() []string {
+	references := minishiftConfig.AllInstancesConfig.CacheReferences
+	for _, profile := range profileActions.GetProfileList() {
+		profileDirs := cmdState.GetMinishiftDirsStructure(constants.GetProfileHomeDir(profile))
+		if filehelper.Exists(filepath.Join(profileDirs.Machines, profile)) && !references.HasProfile(profile) {
+			logger.Debugf("Keeping untracked cached artifacts, profile '%s' has not recorded its cache references yet", profile)
+			return nil
+		}
+	}
+
+	keys, err := cmdUtil.SharedCacheArtifacts()
+	if err != nil {
+		logger.Debugf("Error listing the cached artifacts: %v", err)
+		return nil
+	}
+	return references.Untracked(keys)
+}
+
 func init() {
-	profileDeleteCmd.Flags().BoolVarP(&forceProfileDeletion, "force", "f", false, "Forces the deletion of profile and related files in MINISHIFT_HOME.")
+	profileDeleteCmd.Flags().BoolVarP(&forceProfileDeletion, "force", "f", false, "Forces the deletion of profile and related files in MINISHIFT_HOME, also if the profile is protected.")
+	profileDeleteCmd.Flags().BoolVar(&cleanCache, "clean-cache", false, "Deletes cached artifacts which are not used by any other profile.")
 	ProfileCmd.AddCommand(profileDeleteCmd)
 }
@@ -0,0 +1,78 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/state"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/minishift/minishift/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFile  string
+	includeDisk bool
+
+	profileExportCmd = &cobra.Command{
+		Use:   "export PROFILE_NAME",
+		Short: "Exports a profile into an archive.",
+		Long: `Exports the configuration, certificates, add-ons and add-on state of a profile into a tar.gz archive, which can be
+imported on another machine using 'minishift profile import'. With --include-disk the disk image of the VM is
+exported as well, in which case the VM must be stopped.`,
+		Run: runProfileExport,
+	}
+)
+
+func runProfileExport(cmd *cobra.Command, args []string) {
+	validateArgs(args)
+	profileName := args[0]
+	cmdUtil.LockProfilesOrExit(profileName)
+
+	if !cmdUtil.IsValidProfile(profileName) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Profile '%s' does not exist", profileName))
+	}
+
+	if includeDisk && cmdUtil.GetVMStatus(profileName) == state.Running.String() {
+		atexit.ExitWithMessage(1, fmt.Sprintf("The VM of profile '%s' must be stopped to export its disk image. Run 'minishift stop --profile %s' first.", profileName, profileName))
+	}
+
+	if exportFile == "" {
+		exportFile = fmt.Sprintf("minishift-profile-%s.tar.gz", profileName)
+	}
+
+	manifest := profileActions.ArchiveManifest{
+		Profile:          profileName,
+		MinishiftVersion: version.GetMinishiftVersion(),
+		IncludesDisk:     includeDisk,
+	}
+	if err := profileActions.Export(exportFile, constants.GetProfileHomeDir(profileName), manifest); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error exporting profile '%s': %v", profileName, err))
+	}
+
+	fmt.Println(fmt.Sprintf("Profile '%s' exported to '%s'", profileName, exportFile))
+}
+
+func init() {
+	profileExportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "The archive to write. Defaults to minishift-profile-<PROFILE_NAME>.tar.gz in the current directory.")
+	profileExportCmd.Flags().BoolVar(&includeDisk, "include-disk", false, "Include the disk image of the VM. The VM must be stopped.")
+	ProfileCmd.AddCommand(profileExportCmd)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	cmdState "github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importName string
+
+	profileImportCmd = &cobra.Command{
+		Use:   "import ARCHIVE",
+		Short: "Imports a profile from an archive.",
+		Long: `Imports a profile from an archive created by 'minishift profile export'. The profile is created under the name
+it was exported with, unless a different name is specified using --name. Profiles whose archive contains the disk image
+must keep their name.`,
+		Run: runProfileImport,
+	}
+)
+
+func runProfileImport(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		atexit.ExitWithMessage(1, "The profile archive to import must be provided.")
+	}
+	archive := args[0]
+
+	manifest, err := profileActions.ReadArchiveManifest(archive)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	profileName := importName
+	if profileName == "" {
+		profileName = manifest.Profile
+	}
+	if !cmdUtil.IsValidProfileName(profileName) {
+		atexit.ExitWithMessage(1, invalidNameMessage)
+	}
+	cmdUtil.LockProfilesOrExit(profileName)
+	if cmdUtil.IsValidProfile(profileName) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Profile '%s' already exists. Use --name to import the profile under a different name.", profileName))
+	}
+
+	profileHome := constants.GetProfileHomeDir(profileName)
+	if _, err := profileActions.Import(archive, profileHome, profileName); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error importing profile '%s': %v", profileName, err))
+	}
+	cmdUtil.CreateMinishiftDirs(cmdState.GetMinishiftDirsStructure(profileHome))
+
+	fmt.Println(fmt.Sprintf("Profile '%s' imported. Run 'minishift profile set %s' to make it the active profile.", profileName, profileName))
+}
+
+func init() {
+	profileImportCmd.Flags().StringVar(&importName, "name", "", "The name of the imported profile. Defaults to the name the profile was exported with.")
+	ProfileCmd.AddCommand(profileImportCmd)
+}
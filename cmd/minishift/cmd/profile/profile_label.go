@@ -0,0 +1,98 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileLabelCmd = &cobra.Command{
+		Use:   "label SUBCOMMAND [flags]",
+		Short: "Manages the labels of profiles.",
+		Long:  "Manages the key=value labels attached to profiles. Use 'minishift profile list --selector' to list the profiles with certain labels.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	profileLabelSetCmd = &cobra.Command{
+		Use:   "set PROFILE_NAME KEY=VALUE [KEY=VALUE ...]",
+		Short: "Attaches labels to a profile.",
+		Long:  "Attaches labels to a profile. The value of an existing label with the same key is replaced.",
+		Run:   runProfileLabelSet,
+	}
+
+	profileLabelRemoveCmd = &cobra.Command{
+		Use:   "remove PROFILE_NAME KEY [KEY ...]",
+		Short: "Removes labels from a profile.",
+		Long:  "Removes the labels with the specified keys from a profile.",
+		Run:   runProfileLabelRemove,
+	}
+)
+
+func runProfileLabelSet(cmd *cobra.Command, args []string) {
+	profileName := validateLabelArgs(args, "Usage: minishift profile label set PROFILE_NAME KEY=VALUE [KEY=VALUE ...]")
+	labels, err := profileActions.ParseLabels(args[1:], false)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	if err := profileActions.SetLabels(profileName, labels); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	fmt.Println(fmt.Sprintf("Labels of profile '%s': %s", profileName, profileActions.FormatLabels(profileActions.GetLabels(profileName))))
+}
+
+func runProfileLabelRemove(cmd *cobra.Command, args []string) {
+	profileName := validateLabelArgs(args, "Usage: minishift profile label remove PROFILE_NAME KEY [KEY ...]")
+	labels, err := profileActions.ParseLabels(args[1:], true)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	var keys []string
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	if err := profileActions.RemoveLabels(profileName, keys...); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	fmt.Println(fmt.Sprintf("Labels of profile '%s': %s", profileName, orDash(profileActions.FormatLabels(profileActions.GetLabels(profileName)))))
+}
+
+func validateLabelArgs(args []string, usage string) string {
+	if len(args) < 2 {
+		atexit.ExitWithMessage(1, usage)
+	}
+	validateArgs(args[:1])
+	if !cmdUtil.IsValidProfile(args[0]) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error: '%s' is not a valid profile", args[0]))
+	}
+	return args[0]
+}
+
+func init() {
+	profileLabelCmd.AddCommand(profileLabelSetCmd)
+	profileLabelCmd.AddCommand(profileLabelRemoveCmd)
+	ProfileCmd.AddCommand(profileLabelCmd)
+}
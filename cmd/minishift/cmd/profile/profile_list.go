@@ -17,43 +17,115 @@ limitations under the License.
 package profile
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"text/tabwriter"
 
+	"github.com/docker/go-units"
 	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	localAPI "github.com/minishift/minishift/pkg/minishift/api"
 	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
 )
 
-var profileListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "Lists profiles.",
-	Long:  "Lists the existing profiles.",
-	Run: func(cmd *cobra.Command, args []string) {
-		profiles := profileActions.GetProfileList()
-		displayProfiles(profiles)
-	},
+const jsonOutput = "json"
+
+var (
+	profileListOutput   string
+	profileListSelector []string
+
+	profileListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "Lists profiles.",
+		Long: `Lists the existing profiles with the driver, the status, the IP address, the OpenShift version, the memory,
+the CPUs, the disk usage of their VM and their labels. The IP address is only shown while the VM is running. With
+--selector, only the profiles having all of the specified labels are listed.`,
+		Run: runProfileList,
+	}
+)
+
+func runProfileList(cmd *cobra.Command, args []string) {
+	if profileListOutput != "" && profileListOutput != jsonOutput {
+		atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("Unsupported output format '%s'. Only '%s' is supported.", profileListOutput, jsonOutput))
+	}
+	if profileListOutput == jsonOutput {
+		atexit.ReportErrorsAsJSON()
+	}
+
+	selector, err := profileActions.ParseLabels(profileListSelector, false)
+	if err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
+
+	profiles := profileActions.GetProfileList()
+	sort.Strings(profiles)
+	activeProfile := profileActions.GetActiveProfile()
+	summaries := []localAPI.Profile{}
+	for _, profile := range profiles {
+		if !profileActions.MatchesSelector(profileActions.GetLabels(profile), selector) {
+			continue
+		}
+		summaries = append(summaries, cmdUtil.GetProfileSummary(profile, profile == activeProfile))
+	}
+
+	if profileListOutput == jsonOutput {
+		out, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error creating JSON output: %v", err))
+		}
+		fmt.Fprintln(os.Stdout, string(out))
+		return
+	}
+	displayProfiles(summaries, os.Stdout)
 }
 
-func displayProfiles(profiles []string) {
+func displayProfiles(profiles []localAPI.Profile, out io.Writer) {
 	display := new(tabwriter.Writer)
-	display.Init(os.Stdout, 0, 8, 2, '\t', 0)
+	display.Init(out, 0, 8, 2, '\t', 0)
 
-	activeProfile := profileActions.GetActiveProfile()
-	sort.Strings(profiles)
+	fmt.Fprintln(display, "  PROFILE\tSTATUS\tDRIVER\tIP\tOPENSHIFT\tMEMORY\tCPUS\tDISK\tLABELS")
 	for _, profile := range profiles {
-		vmStatus := cmdUtil.GetVMStatus(profile)
-		if profile == activeProfile {
-			fmt.Fprintln(display, fmt.Sprintf("- %s\t%s\t%s", activeProfile, vmStatus, "(Active)"))
-		} else {
-			fmt.Fprintln(display, fmt.Sprintf("- %s\t%s", profile, vmStatus))
+		var markers []string
+		if profile.Active {
+			markers = append(markers, "Active")
+		}
+		if profile.Protected {
+			markers = append(markers, "Protected")
 		}
+		name := profile.Name
+		if len(markers) > 0 {
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(markers, ", "))
+		}
+		memory := "-"
+		if profile.MemoryMB > 0 {
+			memory = units.BytesSize(float64(profile.MemoryMB) * units.MiB)
+		}
+		cpus := "-"
+		if profile.CPUs > 0 {
+			cpus = strconv.Itoa(profile.CPUs)
+		}
+		fmt.Fprintln(display, fmt.Sprintf("- %s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s", name, profile.VMStatus,
+			orDash(profile.Driver), orDash(profile.IP), orDash(profile.OpenShiftVersion), memory, cpus,
+			units.HumanSize(float64(profile.DiskUsageBytes)), orDash(profileActions.FormatLabels(profile.Labels))))
 	}
 	display.Flush()
 }
 
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
 func init() {
+	profileListCmd.Flags().StringVarP(&profileListOutput, "output", "o", "", "Prints the profiles in the specified format. Supported format: json")
+	profileListCmd.Flags().StringSliceVarP(&profileListSelector, "selector", "l", nil, "Lists only the profiles with the specified labels, for example team=payments.")
 	ProfileCmd.AddCommand(profileListCmd)
 }
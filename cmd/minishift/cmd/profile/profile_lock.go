@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileLockCmd = &cobra.Command{
+		Use:   "lock PROFILE_NAME",
+		Short: "Protects a profile against accidental deletion.",
+		Long: `Protects a profile against accidental deletion. Deleting the VM or the profile, resetting its configuration and
+restoring a backup then require the --force flag.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			setProtected(args, true)
+		},
+	}
+
+	profileUnlockCmd = &cobra.Command{
+		Use:   "unlock PROFILE_NAME",
+		Short: "Removes the protection of a profile.",
+		Long:  "Removes the protection of a profile set by 'minishift profile lock'.",
+		Run: func(cmd *cobra.Command, args []string) {
+			setProtected(args, false)
+		},
+	}
+)
+
+func setProtected(args []string, protected bool) {
+	validateArgs(args)
+	profileName := args[0]
+	if !cmdUtil.IsValidProfile(profileName) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error: '%s' is not a valid profile", profileName))
+	}
+
+	if err := profileActions.SetProtected(profileName, protected); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	if protected {
+		fmt.Println(fmt.Sprintf("Profile '%s' is protected.", profileName))
+	} else {
+		fmt.Println(fmt.Sprintf("Profile '%s' is not protected anymore.", profileName))
+	}
+}
+
+func init() {
+	ProfileCmd.AddCommand(profileLockCmd)
+	ProfileCmd.AddCommand(profileUnlockCmd)
+}
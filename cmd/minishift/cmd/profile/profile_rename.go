@@ -0,0 +1,171 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
+	cmdState "github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/kubeconfig"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	profileRenameCmd = &cobra.Command{
+		Use:   "rename PROFILE_NAME NEW_PROFILE_NAME",
+		Short: "Renames a profile.",
+		Long: `Renames a profile together with its VM. The profile directory, the VM, the kubeconfig context and the references
+to cached artifacts are renamed consistently. The VM must be stopped. The hostname of the VM is updated on the next start.`,
+		Run: runProfileRename,
+	}
+)
+
+func runProfileRename(cmd *cobra.Command, args []string) {
+	validateCopyProfileCmd(args)
+	oldProfile := args[0]
+	newProfile := args[1]
+	cmdUtil.LockProfilesOrExit(oldProfile, newProfile)
+
+	if !cmdUtil.IsValidProfile(oldProfile) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Profile %s does not exist", oldProfile))
+	}
+
+	if oldProfile == constants.DefaultProfileName {
+		atexit.ExitWithMessage(1, fmt.Sprintf("The default profile '%s' cannot be renamed", constants.DefaultProfileName))
+	}
+
+	if cmdUtil.IsValidProfile(newProfile) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Profile '%s' already exists. You must provide a non-existant profile name", newProfile))
+	}
+
+	driverName := renamableDriver(oldProfile)
+	if err := profileActions.Rename(constants.GetProfileHomeDir(oldProfile), oldProfile, constants.GetProfileHomeDir(newProfile), newProfile, driverName); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error renaming profile '%s': %v", oldProfile, err))
+	}
+
+	renameKubeContext(oldProfile, newProfile)
+	renameReferences(oldProfile, newProfile)
+
+	if profileActions.IsProtected(oldProfile) {
+		if err := profileActions.SetProtected(newProfile, true); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+		if err := profileActions.SetProtected(oldProfile, false); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+	}
+
+	if err := profileActions.RenameLabels(oldProfile, newProfile); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	if profileActions.GetActiveProfile() == oldProfile {
+		if err := profileActions.SetActiveProfile(newProfile); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+	}
+
+	fmt.Println(fmt.Sprintf("Profile '%s' renamed to '%s'", oldProfile, newProfile))
+}
+
+// renamableDriver returns the name of the driver of the VM of the profile, making sure the VM can be renamed.
+// If the profile has no VM, an empty string is returned.
+func renamableDriver(profileName string) string {
+	profileDirs := cmdState.GetMinishiftDirsStructure(constants.GetProfileHomeDir(profileName))
+	api := libmachine.NewClient(profileDirs.Home, profileDirs.Certs)
+	defer api.Close()
+
+	if !cmdUtil.VMExists(api, profileName) {
+		return ""
+	}
+
+	host, err := api.Load(profileName)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error loading the VM of profile '%s': %v", profileName, err))
+	}
+
+	if !profileActions.SupportsRename(host.DriverName) {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Renaming a VM of the '%s' driver is not supported. Delete the VM using 'minishift delete --profile %s' first.", host.DriverName, profileName))
+	}
+
+	// the generic driver manages an existing machine which is not stopped by Minishift
+	if host.DriverName != "generic" {
+		if vmState, err := host.Driver.GetState(); err != nil || vmState != state.Stopped {
+			atexit.ExitWithMessage(1, fmt.Sprintf("The VM of profile '%s' must be stopped to be renamed. Run 'minishift stop --profile %s' first.", profileName, profileName))
+		}
+	}
+	return host.DriverName
+}
+
+// renameKubeContext renames the context of the profile in the user's kubeconfig
+func renameKubeContext(oldProfile string, newProfile string) {
+	kubeConfigPath, err := oc.GetGlobalKubeConfigPath()
+	if err != nil {
+		return
+	}
+	if _, err := os.Stat(kubeConfigPath); os.IsNotExist(err) {
+		return
+	}
+
+	kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to rename the context in '%s': %v", kubeConfigPath, err))
+		return
+	}
+
+	if kubeconfig.RenameContext(kubeConfig, oldProfile, newProfile) {
+		if err := clientcmd.WriteToFile(*kubeConfig, kubeConfigPath); err != nil {
+			fmt.Println(fmt.Sprintf("Unable to rename the context in '%s': %v", kubeConfigPath, err))
+		}
+	}
+}
+
+// renameReferences moves the references of the profile to cached artifacts and kubeconfig entries to the new name
+func renameReferences(oldProfile string, newProfile string) {
+	if minishiftConfig.AllInstancesConfig == nil {
+		return
+	}
+
+	changed := false
+	if minishiftConfig.AllInstancesConfig.CacheReferences != nil {
+		changed = minishiftConfig.AllInstancesConfig.CacheReferences.RenameProfile(oldProfile, newProfile)
+	}
+	if refs := minishiftConfig.AllInstancesConfig.KubeConfigReferences; refs != nil {
+		refs.RenameKey(kubeconfig.EntryKey(kubeconfig.ContextEntry, kubeconfig.ContextName(oldProfile)), kubeconfig.EntryKey(kubeconfig.ContextEntry, kubeconfig.ContextName(newProfile)))
+		changed = refs.RenameProfile(oldProfile, newProfile) || changed
+	}
+
+	if changed {
+		if err := minishiftConfig.AllInstancesConfig.Write(); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error updating the references of profile '%s': %v", newProfile, err))
+		}
+	}
+}
+
+func init() {
+	ProfileCmd.AddCommand(profileRenameCmd)
+}
@@ -19,7 +19,6 @@ package profile
 import (
 	"fmt"
 
-	"github.com/golang/glog"
 	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/pkg/minishift/config"
 	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
@@ -42,9 +41,7 @@ func runProfile(cmd *cobra.Command, args []string) {
 	if cmdUtil.DoesVMExist(config.AllInstancesConfig.ActiveProfile) {
 		err := cmdUtil.RemoveCurrentContext()
 		if err != nil {
-			if glog.V(2) {
-				fmt.Println(fmt.Sprintf("%s", err.Error()))
-			}
+			logger.Debugf("%s", err.Error())
 		}
 	}
 
@@ -55,10 +52,7 @@ func runProfile(cmd *cobra.Command, args []string) {
 	fmt.Printf("Profile '%s' set as active profile.\n", profileName)
 	err = cmdUtil.SetOcContext(profileName)
 	if err != nil {
-		if glog.V(2) {
-			fmt.Println(fmt.Sprintf("%s", err.Error()))
-			fmt.Println(fmt.Sprintf("oc testing.cli context could not changed for '%s'.", profileName))
-		}
+		logger.Debugf("oc CLI context could not be changed for '%s': %v", profileName, err)
 	}
 }
 
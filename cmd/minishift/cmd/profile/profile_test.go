@@ -17,10 +17,14 @@ limitations under the License.
 package profile
 
 import (
+	"bytes"
+	"regexp"
 	"testing"
 
 	"github.com/minishift/minishift/cmd/testing/cli"
+	localAPI "github.com/minishift/minishift/pkg/minishift/api"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_validate_args_exit_when_profilename_empty(t *testing.T) {
@@ -51,3 +55,18 @@ func Test_validate_args_exit_when_more_then_one_args(t *testing.T) {
 	atexit.RegisterExitHandler(cli.VerifyExitCodeAndMessage(t, tee, 1, expectedOut))
 	validateArgs([]string{"foo", "bar", "baz"})
 }
+
+func Test_display_profiles(t *testing.T) {
+	profiles := []localAPI.Profile{
+		{Name: "minishift", Active: true, VMStatus: "Running", Driver: "virtualbox", IP: "192.168.99.100", OpenShiftVersion: "v3.11.0", MemoryMB: 4096, CPUs: 2, DiskUsageBytes: 7400000000},
+		{Name: "demo", VMStatus: "Does Not Exist", Protected: true, Labels: map[string]string{"team": "payments", "env": "demo"}},
+	}
+
+	out := new(bytes.Buffer)
+	displayProfiles(profiles, out)
+
+	lines := regexp.MustCompile(`\s+`).ReplaceAllString(out.String(), " ")
+	assert.Contains(t, lines, "PROFILE STATUS DRIVER IP OPENSHIFT MEMORY CPUS DISK LABELS")
+	assert.Contains(t, lines, "- minishift (Active) Running virtualbox 192.168.99.100 v3.11.0 4 GiB 2 7.4 GB -")
+	assert.Contains(t, lines, "- demo (Protected) Does Not Exist - - - - - 0 B env=demo,team=payments")
+}
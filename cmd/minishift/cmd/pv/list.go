@@ -0,0 +1,59 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pv
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/pkg/minishift/pv"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the pre-created persistent volumes.",
+	Long:  "Lists the pre-created persistent volumes together with their capacity, reclaim policy, status and claim.",
+	Run:   runList,
+}
+
+func init() {
+	PvCmd.AddCommand(listCmd)
+}
+
+func runList(cmd *cobra.Command, args []string) {
+	_, ocRunner := util.RunningCluster()
+
+	volumes, err := pv.List(ocRunner)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	if len(volumes) == 0 {
+		fmt.Println("No persistent volumes found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCAPACITY\tRECLAIM POLICY\tSTATUS\tCLAIM")
+	for _, volume := range volumes {
+		fmt.Fprintln(w, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", volume.Name, volume.Capacity, volume.ReclaimPolicy, volume.Phase, volume.Claim))
+	}
+	w.Flush()
+}
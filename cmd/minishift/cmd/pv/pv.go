@@ -0,0 +1,31 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pv
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var PvCmd = &cobra.Command{
+	Use:   "pv SUBCOMMAND [flags]",
+	Short: "Inspects and reclaims the pre-created persistent volumes.",
+	Long: `Inspects and reclaims the pre-created hostPath persistent volumes. The number, size and reclaim policy of the
+volumes can be configured via the 'pv-count', 'pv-size' and 'pv-reclaim-policy' settings.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pv
+
+import (
+	"fmt"
+
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/pkg/minishift/pv"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var recycleCmd = &cobra.Command{
+	Use:   "recycle [PV_NAME ...]",
+	Short: "Wipes released persistent volumes and makes them available again.",
+	Long: `Wipes the data of the specified released persistent volumes and removes their claim reference, so that they
+become available for new claims. If no volume is specified, all released volumes are recycled. This is required for
+volumes using the 'Retain' reclaim policy.`,
+	Run: runRecycle,
+}
+
+func init() {
+	PvCmd.AddCommand(recycleCmd)
+}
+
+func runRecycle(cmd *cobra.Command, args []string) {
+	sshCommander, ocRunner := util.RunningCluster()
+
+	recycled, err := pv.Recycle(args, sshCommander, ocRunner)
+	for _, name := range recycled {
+		fmt.Println(fmt.Sprintf("Persistent volume '%s' recycled.", name))
+	}
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	if len(recycled) == 0 {
+		fmt.Println("No released persistent volumes found.")
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/cmd/addon"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	instanceState "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+const (
+	toDockerFlag = "to-docker"
+	toPodmanFlag = "to-podman"
+)
+
+var (
+	toDocker bool
+	toPodman bool
+
+	loginCmd = &cobra.Command{
+		Use:   "login",
+		Short: "Logs the docker or podman client of the host into the OpenShift registry.",
+		Long: `Logs the docker or podman client of the host into the OpenShift registry.
+The registry route is used if the registry-route add-on is enabled, otherwise the registry service address.
+The token of the user currently logged in with 'oc' is used as password.`,
+		Run: runLogin,
+	}
+)
+
+func runLogin(cmd *cobra.Command, args []string) {
+	if toDocker && toPodman {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Only one of '--%s' and '--%s' can be specified.", toDockerFlag, toPodmanFlag))
+	}
+
+	cli := "docker"
+	if toPodman {
+		cli = "podman"
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	util.ExitIfUndefined(api, constants.MachineName)
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	util.ExitIfNotRunning(host.Driver, constants.MachineName)
+
+	registryRoute := addon.GetAddOnManager().Get("registry-route")
+	registryAddonEnabled := registryRoute != nil && registryRoute.IsEnabled()
+	registry, err := openshift.GetDockerRegistryInfo(registryAddonEnabled, instanceState.InstanceStateConfig.OpenshiftVersion)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error determining the registry address: %v", err))
+	}
+	registry = strings.TrimSpace(registry)
+
+	user, token, err := openshift.GetCurrentUserToken()
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	fmt.Println(fmt.Sprintf("Logging '%s' into '%s' as '%s'", cli, registry, user))
+	if err := openshift.LoginToRegistry(cli, registry, user, token, os.Stdout); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+}
+
+func init() {
+	loginCmd.Flags().BoolVar(&toDocker, toDockerFlag, false, "Logs the docker client into the registry. This is the default.")
+	loginCmd.Flags().BoolVar(&toPodman, toPodmanFlag, false, "Logs the podman client into the registry.")
+	RegistryCmd.AddCommand(loginCmd)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	goflag "flag"
 	"fmt"
@@ -28,22 +29,33 @@ import (
 	"github.com/docker/machine/libmachine/log"
 	"github.com/golang/glog"
 	"github.com/minishift/minishift/cmd/minishift/cmd/addon"
-	cmdAddon "github.com/minishift/minishift/cmd/minishift/cmd/addon"
+	cmdBackup "github.com/minishift/minishift/cmd/minishift/cmd/backup"
+	cmdBundle "github.com/minishift/minishift/cmd/minishift/cmd/bundle"
 	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
 	daemonCmd "github.com/minishift/minishift/cmd/minishift/cmd/daemon"
 	"github.com/minishift/minishift/cmd/minishift/cmd/dns"
+	cmdHome "github.com/minishift/minishift/cmd/minishift/cmd/home"
 	hostfolderCmd "github.com/minishift/minishift/cmd/minishift/cmd/hostfolder"
 	"github.com/minishift/minishift/cmd/minishift/cmd/image"
-	cmdImage "github.com/minishift/minishift/cmd/minishift/cmd/image"
+	cmdIso "github.com/minishift/minishift/cmd/minishift/cmd/iso"
+	cmdMonitoring "github.com/minishift/minishift/cmd/minishift/cmd/monitoring"
+	cmdOc "github.com/minishift/minishift/cmd/minishift/cmd/oc"
 	cmdOpenshift "github.com/minishift/minishift/cmd/minishift/cmd/openshift"
+	cmdOperators "github.com/minishift/minishift/cmd/minishift/cmd/operators"
+	cmdPreset "github.com/minishift/minishift/cmd/minishift/cmd/preset"
 	cmdProfile "github.com/minishift/minishift/cmd/minishift/cmd/profile"
+	cmdPv "github.com/minishift/minishift/cmd/minishift/cmd/pv"
+	cmdRegistry "github.com/minishift/minishift/cmd/minishift/cmd/registry"
 	servicesCmd "github.com/minishift/minishift/cmd/minishift/cmd/services"
 	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/pkg/minikube/constants"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/config/migration"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
 	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+	"github.com/minishift/minishift/pkg/util/download"
 	"github.com/minishift/minishift/pkg/util/filehelper"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/minishift/minishift/pkg/version"
 	"github.com/spf13/cobra"
@@ -55,12 +67,16 @@ import (
 
 const (
 	showLibmachineLogs = "show-libmachine-logs"
+	logLevelFlag       = "log-level"
+	lastFailureLogFile = "last-failure.log"
 	profileCmd         = "profile"
 	profileFlag        = "profile"
 	profileSetCmd      = "set"
 	invalidProfileName = "Profile names must consist of alphanumeric characters only."
 )
 
+var logger = logging.For("cmd")
+
 var viperWhiteList = []string{
 	"v",
 	"alsologtostderr",
@@ -72,6 +88,8 @@ var RootCmd = &cobra.Command{
 	Use:   "minishift",
 	Short: "Minishift is a tool for application development in local OpenShift clusters.",
 	Long:  `Minishift is a command-line tool that provisions and manages single-node OpenShift clusters optimized for development workflows.`,
+	// PersistentPreRun is replaced by the PersistentPreRun of a subcommand, if it defines one. The completion and
+	// version commands do so, and thus run without profile setup and update check.
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		var (
 			err                    error
@@ -88,6 +106,10 @@ var RootCmd = &cobra.Command{
 
 		// Initialize the instance directory structure
 		state.InstanceDirs = state.GetMinishiftDirsStructure(constants.Minipath)
+		if viper.GetBool(configCmd.IsolatedCache.Name) {
+			state.InstanceDirs.IsolateCache()
+		}
+		download.SetMirrors(viper.GetStringSlice(configCmd.DownloadMirrors.Name))
 
 		constants.KubeConfigPath = filepath.Join(state.InstanceDirs.Machines, constants.MachineName+"_kubeconfig")
 
@@ -104,18 +126,12 @@ var RootCmd = &cobra.Command{
 		// Ensure the global viper config file exists.
 		cmdUtil.EnsureConfigFileExists(constants.GlobalConfigFile)
 
+		// Upgrade the files of the profile written by older versions of Minishift, before any of them gets created
+		migrateProfileFiles(cmd)
+
 		// Ensure the viper config file exists.
 		cmdUtil.EnsureConfigFileExists(constants.ConfigFile)
 
-		// Read the config file and get the details about existing image cache.
-		// This should be removed after 2-3 release of minishift.
-		cfg, err := minishiftConfig.ReadViperConfig(constants.ConfigFile)
-		if err != nil {
-			atexit.ExitWithMessage(1, err.Error())
-		}
-		cacheImages := cmdImage.GetConfiguredCachedImages(cfg)
-		addonConfig := cmdAddon.GetAddOnConfiguration()
-
 		// If AllInstanceConfig is not defined we should define it now.
 		if minishiftConfig.AllInstancesConfig == nil {
 			ensureAllInstanceConfigPath(constants.AllInstanceConfigPath)
@@ -125,14 +141,6 @@ var RootCmd = &cobra.Command{
 			}
 		}
 
-		// If older instance state config file exists, rename the file else create a new file
-		_, err = os.Stat(minishiftConstants.GetInstanceStateConfigOldPath())
-		if err == nil {
-			if err := os.Rename(minishiftConstants.GetInstanceStateConfigOldPath(), minishiftConstants.GetInstanceStateConfigPath()); err != nil {
-				atexit.ExitWithMessage(1, fmt.Sprintf("Error moving old state config to new one: %s", err.Error()))
-			}
-		}
-
 		minishiftConfig.InstanceStateConfig, err = minishiftConfig.NewInstanceStateConfig(minishiftConstants.GetInstanceStateConfigPath())
 		if err != nil {
 			atexit.ExitWithMessage(1, fmt.Sprintf("Error creating config for VM: %s", err.Error()))
@@ -144,42 +152,6 @@ var RootCmd = &cobra.Command{
 			atexit.ExitWithMessage(1, fmt.Sprintf("Error creating config for VM: %s", err.Error()))
 		}
 
-		// If hostfolder config exists for an instance then copy it to the new instance state config.
-		// This change should be removed after few releases.
-		hostfolder := minishiftConfig.InstanceStateConfig.HostFolders
-		if len(hostfolder) != 0 {
-			minishiftConfig.InstanceConfig.HostFolders = hostfolder
-			if err != minishiftConfig.InstanceConfig.Write() {
-				atexit.ExitWithMessage(1, fmt.Sprintf("Error coping existing hostfolder to new instance config: %s", err.Error()))
-			}
-		}
-
-		// If cache-config exists then copy it to the new instance config.
-		// This should be removed after 2-3 release of Minishift.
-		if len(cacheImages) != 0 {
-			minishiftConfig.InstanceConfig.CacheImages = cacheImages
-			if err != minishiftConfig.InstanceConfig.Write() {
-				atexit.ExitWithMessage(1, fmt.Sprintf("Error coping existing cache images to new instance config: %s", err.Error()))
-			}
-			delete(cfg, "cache-images")
-			if err != minishiftConfig.WriteViperConfig(constants.ConfigFile, cfg) {
-				atexit.ExitWithMessage(1, fmt.Sprintf("Error removing the cache-images entry from older config %s: %s", constants.ConfigFile, err.Error()))
-			}
-		}
-
-		// If addon config exists then copy it to the new instance config.
-		// This should be removed after 2-3 release of Minishift.
-		if len(addonConfig) != 0 {
-			minishiftConfig.InstanceConfig.AddonConfig = addonConfig
-			if err != minishiftConfig.InstanceConfig.Write() {
-				atexit.ExitWithMessage(1, fmt.Sprintf("Error coping existing addon config to new instance config: %s", err.Error()))
-			}
-			delete(cfg, "addons")
-			if err != minishiftConfig.WriteViperConfig(constants.ConfigFile, cfg) {
-				atexit.ExitWithMessage(1, fmt.Sprintf("Error removing the addon config entry from older config %s: %s", constants.ConfigFile, err.Error()))
-			}
-		}
-
 		if isAddonInstallRequired {
 			if err := cmdUtil.UnpackAddons(state.InstanceDirs.Addons); err != nil {
 				atexit.ExitWithMessage(1, fmt.Sprintf("Error installing default add-ons : %s", err))
@@ -193,12 +165,17 @@ var RootCmd = &cobra.Command{
 			}
 		}
 
+		// the daemons run in the background without terminal
+		if cmd.Hidden && cmd.HasParent() && cmd.Parent() == daemonCmd.DaemonCmd {
+			logToFile(cmd.Name())
+		}
+
 		if minishiftConfig.EnableExperimental {
-			glog.Info("Experimental features are enabled")
+			logger.Infof("Experimental features are enabled")
 		}
 
 		shouldShowLibmachineLogs := viper.GetBool(showLibmachineLogs)
-		if glog.V(3) {
+		if logging.For("libmachine").IsDebug() {
 			log.SetDebug(true)
 		}
 		if !shouldShowLibmachineLogs {
@@ -209,12 +186,39 @@ var RootCmd = &cobra.Command{
 		setDefaultActiveProfile()
 
 		// Adding minishift version information to debug logs
-		if glog.V(2) {
-			fmt.Println(fmt.Sprintf("-- minishift version: v%s+%s", version.GetMinishiftVersion(), version.GetCommitSha()))
-		}
+		logger.Debugf("minishift version: v%s+%s", version.GetMinishiftVersion(), version.GetCommitSha())
+
+		startUpdateCheck(cmd)
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		printUpdateNotice(cmd)
 	},
 }
 
+// migrateProfileFiles upgrades the configuration and state files of the profile to the current schema version.
+// Files written by a newer version of Minishift are not touched, only 'minishift update' may run on them.
+func migrateProfileFiles(cmd *cobra.Command) {
+	applied, err := migration.Run(migration.Files{
+		ProfileDir:          constants.Minipath,
+		ConfigFile:          constants.ConfigFile,
+		InstanceConfig:      minishiftConstants.GetInstanceConfigPath(),
+		InstanceState:       minishiftConstants.GetInstanceStateConfigPath(),
+		LegacyInstanceState: minishiftConstants.GetInstanceStateConfigOldPath(),
+	})
+	if err == migration.ErrNewerSchema {
+		if cmd == updateCmd {
+			return
+		}
+		atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("%s. Update Minishift using 'minishift update'.", err.Error()))
+	}
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	for _, description := range applied {
+		logger.Debugf("Migrated the profile '%s': %s", constants.ProfileName, description)
+	}
+}
+
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -240,6 +244,16 @@ func setFlagsUsingViper() {
 	}
 }
 
+// flagDefault returns the default value of the start flag or the persistent flag with the specified name.
+func flagDefault(name string) (string, bool) {
+	for _, flags := range []*pflag.FlagSet{startCmd.Flags(), RootCmd.PersistentFlags()} {
+		if flag := flags.Lookup(name); flag != nil {
+			return flag.DefValue, true
+		}
+	}
+	return "", false
+}
+
 func processEnvVariables() {
 	enableExperimental, err := cmdUtil.GetBoolEnv(minishiftConstants.MinishiftEnableExperimental)
 	if err == cmdUtil.BooleanFormatError {
@@ -253,14 +267,27 @@ func init() {
 	processEnvVariables()
 	RootCmd.PersistentFlags().Bool(showLibmachineLogs, false, "Show logs from libmachine.")
 	RootCmd.PersistentFlags().String(profileFlag, constants.DefaultProfileName, "Profile name")
+	RootCmd.PersistentFlags().String(logLevelFlag, "", "The log level, optionally per subsystem, for example 'info' or 'warn,hostfolder=debug'. Supported levels are debug, info, warn and error (default warn).")
 	RootCmd.AddCommand(configCmd.ConfigCmd)
+	configCmd.FlagDefault = flagDefault
 	RootCmd.AddCommand(cmdOpenshift.OpenShiftCmd)
 	RootCmd.AddCommand(hostfolderCmd.HostFolderCmd)
+	RootCmd.AddCommand(hostfolderCmd.AdHocMountCmd)
 	RootCmd.AddCommand(servicesCmd.ServicesCmd)
 	RootCmd.AddCommand(daemonCmd.DaemonCmd)
 	RootCmd.AddCommand(addon.AddonsCmd)
 	RootCmd.AddCommand(image.ImageCmd)
 	RootCmd.AddCommand(cmdProfile.ProfileCmd)
+	RootCmd.AddCommand(cmdRegistry.RegistryCmd)
+	RootCmd.AddCommand(cmdPv.PvCmd)
+	RootCmd.AddCommand(cmdMonitoring.MonitoringCmd)
+	RootCmd.AddCommand(cmdOperators.OperatorsCmd)
+	RootCmd.AddCommand(cmdOc.OcCmd)
+	RootCmd.AddCommand(cmdBundle.BundleCmd)
+	RootCmd.AddCommand(cmdBackup.BackupCmd)
+	RootCmd.AddCommand(cmdHome.HomeCmd)
+	RootCmd.AddCommand(cmdPreset.PresetCmd)
+	RootCmd.AddCommand(cmdIso.IsoCmd)
 	if minishiftConfig.EnableExperimental {
 		RootCmd.AddCommand(dns.DnsCmd)
 	}
@@ -270,7 +297,7 @@ func init() {
 		logDir.Value.Set(constants.MakeMiniPath("logs"))
 	}
 	viper.BindPFlags(RootCmd.PersistentFlags())
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initConfig, initLogging)
 	verbosity := pflag.Lookup("v")
 	verbosity.Usage += ". Level varies from 1 to 5 (default 1)."
 }
@@ -286,21 +313,81 @@ func initConfig() {
 	// Initializing the global config file with Viper
 	viper.SetConfigFile(constants.GlobalConfigFile)
 	viper.SetConfigType("json")
-	err := viper.ReadInConfig()
-	if err != nil {
-		glog.Warningf("Error reading config file at '%s': %s", constants.GlobalConfigFile, err)
+	if err := viper.ReadInConfig(); err != nil {
+		logConfigReadError(constants.GlobalConfigFile, err)
 	}
 
 	configPath := constants.ConfigFile
 	viper.SetConfigFile(configPath)
 	viper.SetConfigType("json")
-	err = viper.MergeInConfig()
-	if err != nil {
-		glog.Warningf("Error reading config file at '%s': %s", configPath, err)
+	if err := viper.MergeInConfig(); err != nil {
+		logConfigReadError(configPath, err)
 	}
 	setupViper()
 }
 
+// logConfigReadError warns about a config file which cannot be read. A missing config file is normal, e.g. before
+// the first 'minishift config set', and only logged at debug level.
+func logConfigReadError(path string, err error) {
+	if os.IsNotExist(err) {
+		logger.Debugf("No config file at '%s'", path)
+		return
+	}
+	logger.Warnf("Error reading config file at '%s': %s", path, err)
+}
+
+// initLogging configures the log levels from --log-level and the log format from MINISHIFT_LOG_FORMAT.
+// Without explicit log level, the verbosity 2 of '-v' enables debug output and verbosity 3 adds the libmachine logs.
+func initLogging() {
+	levelSpec := viper.GetString(logLevelFlag)
+	if levelSpec == "" && glog.V(3) {
+		levelSpec = "debug"
+	} else if levelSpec == "" && glog.V(2) {
+		levelSpec = "debug,libmachine=error"
+	}
+
+	if err := logging.Configure(levelSpec, os.Getenv(minishiftConstants.MinishiftLogFormat)); err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
+	atexit.RegisterExitHandler(writeLastFailureLog)
+}
+
+// writeLastFailureLog writes the recent log entries of all levels into the logs directory if the command fails,
+// so that they can be attached to bug reports.
+func writeLastFailureLog(code int) bool {
+	if code == 0 || len(logging.Recent()) == 0 {
+		return false
+	}
+
+	var buf bytes.Buffer
+	if err := logging.WriteRecent(&buf, logging.TextFormat); err != nil {
+		return false
+	}
+	logPath := constants.MakeMiniPath("logs", lastFailureLogFile)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0777); err == nil {
+		ioutil.WriteFile(logPath, buf.Bytes(), 0644)
+	}
+	return false
+}
+
+// logToFile makes a background process log into the logs directory. Unless a log level is specified, the process
+// logs at info level.
+func logToFile(name string) {
+	logPath := constants.MakeMiniPath("logs", fmt.Sprintf("%s-%s.log", name, constants.ProfileName))
+	if err := os.MkdirAll(filepath.Dir(logPath), 0777); err != nil {
+		return
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+
+	if viper.GetString(logLevelFlag) == "" {
+		logging.Configure("info", os.Getenv(minishiftConstants.MinishiftLogFormat))
+	}
+	logging.SetOutput(logFile)
+}
+
 // initializeProfile always return profile name based on below checks.
 // 1. If profile set <PROFILE_NAME> is used then return PROFILE_NAME
 // 2. If --profile <PROFILE_NAME> then return PROFILE_NAME
@@ -372,6 +459,9 @@ func setupViper() {
 	// e.g. show-libmachine-logs => $ENVPREFIX_SHOW_LIBMACHINE_LOGS
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	viper.AutomaticEnv()
+	for _, err := range configCmd.ApplyEnvOverrides() {
+		logger.Warnf("%v", err)
+	}
 	setFlagsUsingViper()
 }
 
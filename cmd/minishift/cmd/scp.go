@@ -0,0 +1,91 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/filetransfer"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+// scpCmd represents the scp command
+var scpCmd = &cobra.Command{
+	Use:   "scp SOURCE DESTINATION",
+	Short: "Copies a file to or from the Minishift VM.",
+	Long: `Copies a file to or from the Minishift VM. Paths on the VM are prefixed with the name of the VM, which is the name of the profile, for example 'minishift:/home/docker/file.txt'.
+If the destination is a directory, the file keeps its name.`,
+	Example: `  minishift scp ./file.txt minishift:/home/docker/
+  minishift scp minishift:/var/lib/minishift/base/master/master-config.yaml .`,
+	Run: runScp,
+}
+
+func runScp(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		atexit.ExitWithMessage(1, "Usage: minishift scp SOURCE DESTINATION")
+	}
+
+	source, sourceIsRemote := parseVMPath(args[0], constants.MachineName)
+	destination, destinationIsRemote := parseVMPath(args[1], constants.MachineName)
+	if sourceIsRemote == destinationIsRemote {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Exactly one of source and destination needs to be a path on the VM, prefixed with '%s:'", constants.MachineName))
+	}
+
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	client, err := cluster.NewSSHClient(api)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Cannot establish SSH connection to the VM: %v", err))
+	}
+	defer client.Close()
+
+	transfer := filetransfer.New(client, filetransfer.Options{})
+	if sourceIsRemote {
+		_, err = transfer.FromVM(source, destination)
+	} else {
+		_, err = transfer.ToVM(source, destination)
+	}
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error copying '%s' to '%s': %v", args[0], args[1], err))
+	}
+}
+
+// parseVMPath returns the path and whether the path refers to the VM with the specified name.
+// A remote path without path after the prefix refers to the home directory of the VM user.
+func parseVMPath(arg string, machineName string) (string, bool) {
+	prefix := machineName + ":"
+	if !strings.HasPrefix(arg, prefix) {
+		return arg, false
+	}
+
+	path := strings.TrimPrefix(arg, prefix)
+	if path == "" {
+		path = "."
+	}
+	return path, true
+}
+
+func init() {
+	RootCmd.AddCommand(scpCmd)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_VM_Path(t *testing.T) {
+	var testCases = []struct {
+		arg              string
+		expectedPath     string
+		expectedIsRemote bool
+	}{
+		{"minishift:/home/docker/file.txt", "/home/docker/file.txt", true},
+		{"minishift:", ".", true},
+		{"./file.txt", "./file.txt", false},
+		{"C:\\Users\\file.txt", "C:\\Users\\file.txt", false},
+		{"other:/tmp/file.txt", "other:/tmp/file.txt", false},
+	}
+
+	for _, testCase := range testCases {
+		path, isRemote := parseVMPath(testCase.arg, "minishift")
+		assert.Equal(t, testCase.expectedPath, path)
+		assert.Equal(t, testCase.expectedIsRemote, isRemote)
+	}
+}
@@ -17,15 +17,19 @@ limitations under the License.
 package services
 
 import (
+	"fmt"
 	"runtime"
 
+	"github.com/minishift/minishift/cmd/minishift/cmd/config"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
 	"github.com/minishift/minishift/pkg/minishift/network/proxy"
+	"github.com/minishift/minishift/pkg/minishift/network/registrycache"
 	"github.com/minishift/minishift/pkg/minishift/systemtray"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 const (
@@ -65,6 +69,10 @@ func runServiceStart(cmd *cobra.Command, args []string) {
 		atexit.ExitWithMessage(0, "Start functionality for SFTP daemon is not available")
 	case minishiftConstants.ProxyDaemon:
 		proxy.EnsureProxyDaemonRunning()
+	case minishiftConstants.RegistryCacheDaemon:
+		if err := registrycache.EnsureRegistryCacheDaemonRunning(viper.GetInt(config.ServicesRegistryCachePort.Name), ""); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error starting the registry cache: %v", err))
+		}
 	default:
 		return
 	}
@@ -24,6 +24,7 @@ import (
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
 	"github.com/minishift/minishift/pkg/minishift/network/proxy"
+	"github.com/minishift/minishift/pkg/minishift/network/registrycache"
 	"github.com/minishift/minishift/pkg/minishift/systemtray"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
@@ -83,6 +84,13 @@ func runServiceStop(cmd *cobra.Command, args []string) {
 			proc.Kill()
 			atexit.ExitWithMessage(0, fmt.Sprintf("Killed process with PID: %d\n", pid))
 		}
+	case minishiftConstants.RegistryCacheDaemon:
+		if pid := registrycache.GetPID(); pid > 0 {
+			if err := registrycache.StopRegistryCacheDaemon(); err != nil {
+				atexit.ExitWithMessage(1, fmt.Sprintf("Error stopping the registry cache: %v", err))
+			}
+			atexit.ExitWithMessage(0, fmt.Sprintf("Killed process with PID: %d\n", pid))
+		}
 	default:
 		return
 	}
@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/docker/machine/libmachine"
 	"github.com/minishift/minishift/cmd/minishift/state"
@@ -34,11 +36,21 @@ const (
 var sshCmd = &cobra.Command{
 	Use:   "ssh [-- COMMAND]",
 	Short: "Log in to or run a command on a Minishift VM with SSH.",
-	Long:  "Log in to or run a command on a Minishift VM with SSH. This command is similar to 'docker-machine ssh'.",
+	Long: `Log in to or run a command on a Minishift VM with SSH. This command is similar to 'docker-machine ssh'.
+
+Commands passed after '--' are run non-interactively and the exit status of the command is the exit status of 'minishift ssh'.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
 		defer api.Close()
 
+		if len(args) > 0 {
+			exitStatus, err := cluster.RunSSHCommand(api, strings.Join(args, " "), os.Stdin, os.Stdout, os.Stderr)
+			if err != nil {
+				atexit.ExitWithMessage(1, fmt.Sprintf("Cannot establish SSH connection to the VM: %s", err.Error()))
+			}
+			atexit.Exit(exitStatus)
+		}
+
 		err := cluster.CreateSSHShell(api, args)
 		if err != nil {
 			if err.Error() == SshCommunicationError {
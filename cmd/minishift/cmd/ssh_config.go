@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/minishift/minishift/cmd/minishift/cmd/util"
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sshConfigHostAlias string
+)
+
+// sshConfigCmd represents the ssh-config command
+var sshConfigCmd = &cobra.Command{
+	Use:   "ssh-config",
+	Short: "Prints an OpenSSH configuration for connecting to the Minishift VM.",
+	Long: `Prints an OpenSSH client configuration stanza for connecting to the Minishift VM of the current profile.
+Append the output to '~/.ssh/config' to target the VM from SSH based tools, like VS Code Remote-SSH or Ansible.
+
+The IP of the VM can change when the VM is recreated, in which case the configuration needs to be regenerated.`,
+	Example: `  minishift ssh-config >> ~/.ssh/config
+  ssh minishift`,
+	Run: runSSHConfig,
+}
+
+func init() {
+	sshConfigCmd.Flags().StringVar(&sshConfigHostAlias, "host-alias", "", "The name of the host in the SSH configuration. Defaults to the name of the profile.")
+	RootCmd.AddCommand(sshConfigCmd)
+}
+
+func runSSHConfig(cmd *cobra.Command, args []string) {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	util.ExitIfUndefined(api, constants.MachineName)
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	util.ExitIfNotRunning(host.Driver, constants.MachineName)
+
+	hostname, err := host.Driver.GetSSHHostname()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting the IP of the VM: %s", err.Error()))
+	}
+	port, err := host.Driver.GetSSHPort()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting the SSH port of the VM: %s", err.Error()))
+	}
+
+	alias := sshConfigHostAlias
+	if alias == "" {
+		alias = constants.ProfileName
+	}
+
+	fmt.Print(sshConfigStanza(alias, hostname, port, host.Driver.GetSSHUsername(), host.Driver.GetSSHKeyPath()))
+}
+
+// sshConfigStanza returns the OpenSSH client configuration for the VM. Host key checking is disabled, since the VM
+// gets a new host key every time it is recreated.
+func sshConfigStanza(alias string, hostname string, port int, user string, keyPath string) string {
+	var stanza bytes.Buffer
+	fmt.Fprintf(&stanza, "Host %s\n", alias)
+	fmt.Fprintf(&stanza, "  HostName %s\n", hostname)
+	fmt.Fprintf(&stanza, "  User %s\n", user)
+	fmt.Fprintf(&stanza, "  Port %d\n", port)
+	fmt.Fprintf(&stanza, "  IdentityFile %s\n", sshConfigValue(keyPath))
+	stanza.WriteString("  IdentitiesOnly yes\n")
+	stanza.WriteString("  StrictHostKeyChecking no\n")
+	stanza.WriteString("  UserKnownHostsFile /dev/null\n")
+	stanza.WriteString("  LogLevel QUIET\n")
+	return stanza.String()
+}
+
+// sshConfigValue quotes values containing whitespace, as required by the OpenSSH configuration format.
+func sshConfigValue(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return fmt.Sprintf("\"%s\"", value)
+	}
+	return value
+}
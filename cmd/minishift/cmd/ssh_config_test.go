@@ -0,0 +1,43 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ssh_config_stanza(t *testing.T) {
+	expected := `Host minishift
+  HostName 192.168.99.100
+  User docker
+  Port 22
+  IdentityFile /home/john/.minishift/machines/minishift/id_rsa
+  IdentitiesOnly yes
+  StrictHostKeyChecking no
+  UserKnownHostsFile /dev/null
+  LogLevel QUIET
+`
+	assert.Equal(t, expected, sshConfigStanza("minishift", "192.168.99.100", 22, "docker", "/home/john/.minishift/machines/minishift/id_rsa"))
+}
+
+func Test_ssh_config_quotes_paths_with_spaces(t *testing.T) {
+	stanza := sshConfigStanza("dev", "127.0.0.1", 2222, "docker", "C:\\Users\\John Doe\\.minishift\\machines\\minishift\\id_rsa")
+	assert.Contains(t, stanza, "  Port 2222\n")
+	assert.Contains(t, stanza, `  IdentityFile "C:\Users\John Doe\.minishift\machines\minishift\id_rsa"`)
+}
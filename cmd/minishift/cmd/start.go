@@ -22,6 +22,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/docker/go-units"
@@ -30,7 +31,6 @@ import (
 	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/provision"
 	"github.com/docker/machine/libmachine/ssh"
-	"github.com/golang/glog"
 	"github.com/minishift/minishift/cmd/minishift/cmd/addon"
 	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
 	registrationUtil "github.com/minishift/minishift/cmd/minishift/cmd/registration"
@@ -39,23 +39,34 @@ import (
 	"github.com/minishift/minishift/pkg/minikube/cluster"
 	"github.com/minishift/minishift/pkg/minikube/constants"
 	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/minishift/minishift/pkg/minishift/cache"
+	"github.com/minishift/minishift/pkg/minishift/cloudinit"
 	minishiftCluster "github.com/minishift/minishift/pkg/minishift/cluster"
 	"github.com/minishift/minishift/pkg/minishift/clusterup"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/containerruntime"
 	"github.com/minishift/minishift/pkg/minishift/docker"
 	"github.com/minishift/minishift/pkg/minishift/docker/image"
 	"github.com/minishift/minishift/pkg/minishift/hostfolder"
+	"github.com/minishift/minishift/pkg/minishift/identityprovider"
+	"github.com/minishift/minishift/pkg/minishift/imagepolicy"
+	"github.com/minishift/minishift/pkg/minishift/iso"
 	minishiftNetwork "github.com/minishift/minishift/pkg/minishift/network"
 	minishiftProxy "github.com/minishift/minishift/pkg/minishift/network/proxy"
+	"github.com/minishift/minishift/pkg/minishift/network/registrycache"
+	"github.com/minishift/minishift/pkg/minishift/oc"
 	"github.com/minishift/minishift/pkg/minishift/openshift"
+	"github.com/minishift/minishift/pkg/minishift/preset"
 	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
 	"github.com/minishift/minishift/pkg/minishift/provisioner"
+	"github.com/minishift/minishift/pkg/minishift/pv"
 	"github.com/minishift/minishift/pkg/minishift/remotehost"
 	"github.com/minishift/minishift/pkg/minishift/systemtray"
 	minishiftTLS "github.com/minishift/minishift/pkg/minishift/tls"
 	"github.com/minishift/minishift/pkg/util"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/minishift/minishift/pkg/util/os/lock"
 	"github.com/minishift/minishift/pkg/util/progressdots"
 	stringUtils "github.com/minishift/minishift/pkg/util/strings"
 	"github.com/minishift/minishift/pkg/version"
@@ -68,14 +79,17 @@ const (
 	commandName             = "start"
 	defaultInsecureRegistry = "172.30.0.0/16"
 	genericDriver           = "generic"
+
+	serviceCatalogTimeout      = 5 * time.Minute
+	serviceCatalogPollInterval = 5 * time.Second
 )
 
 var (
 	dockerEnv               []string
 	openShiftEnv            []string
 	shellProxyEnv           util.ProxyConfig
-	unsupportedIsoUrlFormat = fmt.Sprintf("Unsupported value for iso-url. It can be a URL, file URI or one of the following aliases: [%s].",
-		strings.Join(minishiftConstants.ValidIsoAliases, ","))
+	registryCacheMirror     string
+	unsupportedIsoUrlFormat = "Unsupported value for iso-url. It can be a URL, file URI or an ISO flavor listed by 'minishift iso list'."
 
 	// custom flags variable
 	dockerEnvFlag = &flag.Flag{
@@ -99,6 +113,13 @@ var (
 		Value:     cmdUtil.NewStringSliceValue([]string{}, &[]string{}),
 	}
 
+	dockerStorageOptFlag = &flag.Flag{
+		Name:      configCmd.DockerStorageOpt.Name,
+		Shorthand: "",
+		Usage:     "Storage driver options to pass to the Docker daemon in the form <key>=<value>.",
+		Value:     cmdUtil.NewStringSliceValue([]string{}, &[]string{}),
+	}
+
 	registryMirrorFlag = &flag.Flag{
 		Name:      configCmd.RegistryMirror.Name,
 		Shorthand: "",
@@ -106,6 +127,13 @@ var (
 		Value:     cmdUtil.NewStringSliceValue([]string{}, &[]string{}),
 	}
 
+	apiExtraSANsFlag = &flag.Flag{
+		Name:      configCmd.APIExtraSANs.Name,
+		Shorthand: "",
+		Usage:     "Additional host names and IP addresses to include in the serving certificates of the API server.",
+		Value:     cmdUtil.NewStringSliceValue([]string{}, &[]string{}),
+	}
+
 	nameServersFlag = &flag.Flag{
 		Name:      configCmd.NameServers.Name,
 		Shorthand: "",
@@ -123,6 +151,8 @@ var (
 
 	// ocPath
 	ocPath = ""
+
+	startForceFlag bool
 )
 
 // init configures the command line options of this command
@@ -145,6 +175,7 @@ For the latter see 'minishift config -h'.`,
 	startFlagSet = initStartFlags()
 	startCmd.Flags().AddFlagSet(startFlagSet)
 	startCmd.Flags().AddFlagSet(initSubscriptionManagerFlags())
+	startCmd.Flags().BoolVar(&startForceFlag, "force", false, "Starts the instance even if the VM driver, the disk size or the OpenShift version differ from the last start.")
 
 	viper.BindPFlags(startCmd.Flags())
 	RootCmd.AddCommand(startCmd)
@@ -158,31 +189,58 @@ For the latter see 'minishift config -h'.`,
 
 // runStart handles all command line arguments, launches the VM and provisions OpenShift
 func runStart(cmd *cobra.Command, args []string) {
+	if err := configCmd.DecryptSecrets(configCmd.Password, configCmd.HttpProxy, configCmd.HttpsProxy, configCmd.LDAPBindPassword); err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
 	fmt.Println(fmt.Sprintf("-- Starting profile '%s'", constants.ProfileName))
+	cmdUtil.LockProfilesOrExit(constants.ProfileName)
 
 	libMachineClient := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
 	defer libMachineClient.Close()
 
+	// a previous start which did not complete needs to be resumed, even though the VM might be running already
+	previousLifecycle := minishiftConfig.InstanceStateConfig.Lifecycle
+	if !previousLifecycle.IsIncomplete() {
+		ensureNotRunning(libMachineClient, constants.MachineName)
+	}
+
+	// to determine whether we need to run post cluster up actions,
+	// we need to determine whether this is a restart prior to potentially creating a new VM
+	isRestart := cmdUtil.VMExists(libMachineClient, constants.MachineName)
+	isRestart = recoverIncompleteStart(libMachineClient, previousLifecycle, isRestart)
+	if isRestart {
+		checkStartFlagDivergence(cmd.Flags(), minishiftConfig.InstanceStateConfig.StartFlags, startForceFlag)
+		if cmd.Flags().Changed(configCmd.Preset.Name) {
+			fmt.Println(fmt.Sprintf("-- Ignoring the preset '%s', presets are only applied when the VM is created", viper.GetString(configCmd.Preset.Name)))
+		}
+	} else {
+		applyPreset(cmd.Flags())
+	}
+
 	if viper.GetString(configCmd.VmDriver.Name) == genericDriver {
 		cmdUtil.ValidateGenericDriverFlags(viper.GetString(configCmd.RemoteIPAddress.Name),
 			viper.GetString(configCmd.RemoteSSHUser.Name),
 			viper.GetString(configCmd.SSHKeyToConnectRemote.Name))
 	}
-
-	ensureNotRunning(libMachineClient, constants.MachineName)
 	addVersionPrefixToOpenshiftVersion()
-
-	// to determine whether we need to run post cluster up actions,
-	// we need to determine whether this is a restart prior to potentially creating a new VM
-	isRestart := cmdUtil.VMExists(libMachineClient, constants.MachineName)
+	validateContainerRuntime()
+	minishiftConfig.InstanceStateConfig.Lifecycle.InitialStart = !isRestart
+	cmdUtil.SetLifecycleState(minishiftConfig.Creating)
+	cmdUtil.RecordLifecycleErrors()
 
 	// create and handle proxy config for local environment
 	proxyConfig := handleProxyConfig()
 
+	// determine the mirror address of the host-side registry cache
+	handleRegistryCache()
+
 	// Get proper OpenShift version
 	requestedOpenShiftVersion, err := cmdUtil.GetOpenShiftReleaseVersion()
+	if _, ok := err.(*cmdUtil.InvalidVersionError); ok {
+		atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("Error getting OpenShift version: %v", err))
+	}
 	if err != nil {
-		atexit.ExitWithMessage(1, fmt.Sprintf("Error getting OpenShift version: %v", err))
+		atexit.ExitWithReason(atexit.NetworkError, fmt.Sprintf("Error getting OpenShift version: %v", err))
 	}
 
 	// preflight check (before start)
@@ -199,25 +257,41 @@ func runStart(cmd *cobra.Command, args []string) {
 	// Cache OC binary before starting the VM and perform oc command option check
 	ocPath = cmdUtil.CacheOc(requestedOpenShiftVersion)
 	preflightChecksForArtifacts()
+	clusterUpFlags := validateClusterUpFlags()
 
 	setSubscriptionManagerParameters()
 
+	// remember the context the user works with, it is restored once the cluster is stopped or deleted
+	cmdUtil.RememberKubeContext()
+
 	fmt.Print("-- Starting the OpenShift cluster")
 
 	hostVm := startHost(libMachineClient)
+	cmdUtil.SetLifecycleState(minishiftConfig.Provisioning)
 	if !isRestart {
+		restoreDataDisk(hostVm)
 		minishiftConfig.InstanceStateConfig.TimeZone = viper.GetString(configCmd.TimeZone.Name)
+		minishiftConfig.InstanceStateConfig.CloudInitUserData = readCloudInitUserData()
 		minishiftConfig.InstanceStateConfig.Write()
 	}
 	timezone.SetTimeZone(hostVm)
+	applyCloudInit(hostVm.Driver)
 	registrationUtil.RegisterHost(libMachineClient)
 
 	// Forcibly set nameservers when configured
 	minishiftNetwork.AddNameserversToInstance(hostVm.Driver, getSlice(configCmd.NameServers.Name))
 	// to support intermediate proxy
 	minishiftTLS.SetCACertificate(hostVm.Driver)
+	if err := minishiftTLS.SetRegistryCACertificates(hostVm.Driver, getSlice(configCmd.RegistryCAs.Name)); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error adding the registry CA certificates: %v", err))
+	}
+	applyImagePolicy(hostVm.Driver)
 
 	ip, _ := hostVm.Driver.GetIP()
+	if ip != "" {
+		// the start takes care of the new IP itself, the IP watcher must not reconcile it
+		cmdUtil.RecordLastKnownIP(ip)
+	}
 	localProxy := viper.GetBool(configCmd.LocalProxy.Name)
 	// This is a hack/workaround as the actual values are modified in `cluster.go` for the VM
 	if proxyConfig.IsEnabled() {
@@ -239,6 +313,12 @@ func runStart(cmd *cobra.Command, args []string) {
 		proxyConfig.ApplyToEnvironment()
 	}
 
+	if registryCacheMirror != "" {
+		hostip, _ := minishiftNetwork.DetermineHostIP(hostVm.Driver)
+		startRegistryCache(hostip)
+		minishiftNetwork.AddHostEntryToInstance(hostVm.Driver, registrycache.HostAlias, hostip)
+	}
+
 	// preflight checks and set static-ip (after start)
 	if viper.GetString(configCmd.VmDriver.Name) != genericDriver {
 		preflightChecksAfterStartingHost(hostVm.Driver)
@@ -257,6 +337,10 @@ func runStart(cmd *cobra.Command, args []string) {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Error creating required host directories: %v", err))
 	}
 
+	if err := minishiftNetwork.EnsureHostname(hostVm.Driver); err != nil {
+		logger.Warnf("Error updating the hostname of the VM: %v", err)
+	}
+
 	autoMountHostFolders(hostVm.Driver)
 
 	// start the minishift system tray
@@ -271,6 +355,7 @@ func runStart(cmd *cobra.Command, args []string) {
 		if !isRestart {
 			importContainerImages(hostVm.Driver, libMachineClient, requestedOpenShiftVersion)
 		}
+		loadHostImages(hostVm.Driver)
 
 		sshCommander := provision.GenericSSHCommander{Driver: hostVm.Driver}
 		dockerCommander := docker.NewVmDockerCommander(sshCommander)
@@ -279,6 +364,8 @@ func runStart(cmd *cobra.Command, args []string) {
 			atexit.ExitWithMessage(1, err.Error())
 		}
 
+		kubernetesOnly := determineKubernetesOnly(isRestart, clusterUpFlags)
+
 		clusterUpConfig := &clusterup.ClusterUpConfig{
 			OpenShiftVersion:     requestedOpenShiftVersion,
 			MachineName:          constants.MachineName,
@@ -294,17 +381,34 @@ func runStart(cmd *cobra.Command, args []string) {
 			SSHCommander:         sshCommander,
 			OcBinaryPathInsideVM: fmt.Sprintf("%s/oc", minishiftConstants.OcPathInsideVM),
 			SshUser:              sshCommander.Driver.GetSSHUsername(),
+			ComponentImages: map[string]string{
+				clusterup.RouterComponent:     viper.GetString(configCmd.RouterImage.Name),
+				clusterup.RegistryComponent:   viper.GetString(configCmd.RegistryImage.Name),
+				clusterup.WebConsoleComponent: viper.GetString(configCmd.WebConsoleImage.Name),
+			},
+			ExtraFlags:     clusterUpFlags,
+			KubernetesOnly: kubernetesOnly,
 		}
 
 		clusterUpParams := cmdUtil.DetermineClusterUpParameters(clusterUpConfig, strings.TrimSpace(dockerbridgeSubnet), clusterUpFlagSet)
 		fmt.Println("-- OpenShift cluster will be configured with ...")
 		fmt.Println("   Version:", requestedOpenShiftVersion)
+		if kubernetesOnly {
+			fmt.Println("   Mode: Kubernetes only")
+		}
 
 		err = cmdUtil.PullOpenshiftImageAndCopyOcBinary(dockerCommander, requestedOpenShiftVersion)
+		if err != nil {
+			atexit.ExitWithReason(atexit.DownloadError, err.Error())
+		}
+
+		containerRuntime := determineContainerRuntime(isRestart)
+		err = containerruntime.EnsureRuntimeEnabled(sshCommander, containerRuntime)
 		if err != nil {
 			atexit.ExitWithMessage(1, err.Error())
 		}
 
+		cmdUtil.SetLifecycleState(minishiftConfig.ClusterStarting)
 		fmt.Printf("-- Starting OpenShift cluster ")
 		progressDots := progressdots.New()
 		progressDots.Start()
@@ -317,27 +421,359 @@ func runStart(cmd *cobra.Command, args []string) {
 
 		out, err := clusterup.ClusterUp(clusterUpConfig, clusterUpParams)
 		if err != nil {
-			atexit.ExitWithMessage(1, fmt.Sprintf("Error during 'cluster up' execution: %v", err))
+			atexit.ExitWithReason(atexit.ClusterUpFailure, fmt.Sprintf("Error during 'cluster up' execution: %v", err))
 		}
 		progressDots.Stop()
 		fmt.Printf("\n%s\n", out)
 
 		if !IsOpenShiftRunning(hostVm.Driver) && !viper.GetBool(configCmd.WriteConfig.Name) {
-			atexit.ExitWithMessage(1, "OpenShift provisioning failed. origin container failed to start.")
+			atexit.ExitWithReason(atexit.ClusterUpFailure, "OpenShift provisioning failed. origin container failed to start.")
 		}
 
 		if !isRestart {
 			if !viper.GetBool(configCmd.WriteConfig.Name) {
+				configureContainerRuntime(dockerCommander, containerRuntime)
 				postClusterUp(hostVm, clusterUpConfig)
 			}
 			exportContainerImages(hostVm.Driver, libMachineClient, requestedOpenShiftVersion)
 		}
+		if !viper.GetBool(configCmd.WriteConfig.Name) {
+			ensureAPIExtraSANs(dockerCommander)
+			applyPatchSets(dockerCommander)
+			if !kubernetesOnly {
+				importExtraTemplates(ocPath)
+				applyComponentImages(ocPath, clusterUpConfig.ComponentImages)
+			}
+			reconcilePersistentVolumes(sshCommander, ocPath)
+			configureIdentityProvider(hostVm.Driver, dockerCommander)
+			reconcileUsers(dockerCommander, ocPath)
+			configureAuditLogging(dockerCommander)
+			enableServiceCatalog(sshCommander, ocPath, requestedOpenShiftVersion)
+		}
 		if isRestart {
 			err = cmdUtil.SetOcContext(minishiftConfig.AllInstancesConfig.ActiveProfile)
 			if err != nil {
 				atexit.ExitWithMessage(1, fmt.Sprintf("Could not set oc CLI context for '%s' profile: %v", profileActions.GetActiveProfile(), err))
 			}
 		}
+		cmdUtil.RecordKubeConfigEntries(ip)
+	}
+	recordStartFlags(startFlagSet)
+	cmdUtil.SetLifecycleState(minishiftConfig.Running)
+}
+
+// recoverIncompleteStart prepares resuming the previous operation if it crashed, got interrupted or failed. A VM
+// which was left behind half created is deleted, and the initial provisioning is repeated as long as it did not
+// complete. The function returns whether the start is a restart of a provisioned instance.
+func recoverIncompleteStart(libMachineClient *libmachine.Client, previous minishiftConfig.Lifecycle, isRestart bool) bool {
+	if previous.IsIncomplete() {
+		fmt.Println(fmt.Sprintf("-- Resuming the previous operation, which stopped during '%s'", previous.LastPhase()))
+		if previous.LastPhase() == minishiftConfig.Creating && previous.InitialStart && isRestart {
+			fmt.Println("   Deleting the partially created VM")
+			if err := cluster.DeleteHost(libMachineClient); err != nil {
+				if err := libMachineClient.Remove(constants.MachineName); err != nil {
+					atexit.ExitWithMessage(1, fmt.Sprintf("Error deleting the partially created VM: %v\nRun 'minishift delete --force' and start again.", err))
+				}
+			}
+			return false
+		}
+	}
+
+	return isRestart && !previous.InitialStart
+}
+
+// enableServiceCatalog adds the service catalog components which are not installed yet and waits until all of them
+// are ready, so that the start fails instead of leaving a service catalog behind which never comes up.
+func enableServiceCatalog(sshCommander provision.SSHCommander, ocPath string, openShiftVersion string) {
+	if !viper.GetBool(configCmd.ServiceCatalog.Name) {
+		return
+	}
+
+	ocRunner, err := oc.NewOcRunner(ocPath, constants.KubeConfigPath)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	imageToUse := fmt.Sprintf("'%s:%s'", minishiftConstants.ImageNameForClusterUpImageFlag, openShiftVersion)
+	ocPathInsideVM := fmt.Sprintf("%s/oc", minishiftConstants.OcPathInsideVM)
+	for _, component := range openshift.ServiceCatalogComponents {
+		if openshift.IsComponentInstalled(ocRunner, component) {
+			continue
+		}
+		fmt.Printf("-- Adding the '%s' component ...", component)
+		progressDots := progressdots.New()
+		progressDots.Start()
+		_, err := clusterup.AddComponent(sshCommander, ocPathInsideVM, minishiftConstants.BaseDirInsideInstance, component, imageToUse)
+		progressDots.Stop()
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("\nError adding the '%s' component: %v", component, err))
+		}
+		fmt.Println(" OK")
+	}
+
+	fmt.Print("-- Waiting for the service catalog to become ready ...")
+	progressDots := progressdots.New()
+	progressDots.Start()
+	err = openshift.WaitForServiceCatalog(ocRunner, serviceCatalogTimeout, serviceCatalogPollInterval)
+	progressDots.Stop()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("\n%v\nUse 'minishift status' to check the state of the service catalog.", err))
+	}
+	fmt.Println(" OK")
+}
+
+// restoreDataDisk replaces the disk of the newly created VM with the disk kept by 'minishift delete --keep-data'.
+// The Docker images and build cache are preserved, the state of the previous OpenShift cluster is removed.
+func restoreDataDisk(hostVm *host.Host) {
+	disk := dataDisk()
+	if !disk.IsKept(hostVm.Driver.DriverName()) {
+		return
+	}
+
+	fmt.Print("-- Reusing the data disk of the previous Minishift VM ...")
+	progressDots := progressdots.New()
+	progressDots.Start()
+	err := hostVm.Stop()
+	if err == nil {
+		err = disk.Restore(hostVm.Driver.DriverName())
+	}
+	if err == nil {
+		err = hostVm.Start()
+	}
+	if err == nil {
+		err = hostVm.ConfigureAuth()
+	}
+	if err == nil {
+		err = minishiftCluster.Prune(provision.GenericSSHCommander{Driver: hostVm.Driver})
+	}
+	progressDots.Stop()
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("\nError reusing the data disk: %v", err))
+	}
+	fmt.Println(" OK")
+}
+
+// validateContainerRuntime exits if the requested container runtime is not supported
+func validateContainerRuntime() {
+	if err := minishiftConfig.IsValidContainerRuntime(configCmd.ContainerRuntime.Name, viper.GetString(configCmd.ContainerRuntime.Name)); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+}
+
+// determineContainerRuntime returns the container runtime of the kubelet. The runtime is only configured on the
+// initial provision, so on restarts the runtime the instance got provisioned with is used.
+func determineContainerRuntime(isRestart bool) string {
+	requested := viper.GetString(configCmd.ContainerRuntime.Name)
+	provisioned := minishiftConfig.InstanceStateConfig.ContainerRuntime
+	if !isRestart || provisioned == "" {
+		minishiftConfig.InstanceStateConfig.ContainerRuntime = requested
+		minishiftConfig.InstanceStateConfig.Write()
+		return requested
+	}
+
+	if provisioned != requested {
+		fmt.Println(fmt.Sprintf("   WARN: The instance uses the container runtime '%s'. Delete the instance to switch to '%s'.", provisioned, requested))
+	}
+	return provisioned
+}
+
+// determineKubernetesOnly returns whether the instance runs in Kubernetes only mode. The mode is fixed when the
+// instance is provisioned, since 'cluster up' cannot add the OpenShift components later on.
+func determineKubernetesOnly(isRestart bool, clusterUpFlags []string) bool {
+	requested := viper.GetBool(configCmd.KubernetesOnly.Name)
+	provisioned := minishiftConfig.InstanceStateConfig.KubernetesOnly
+	if !isRestart {
+		minishiftConfig.InstanceStateConfig.KubernetesOnly = requested
+		minishiftConfig.InstanceStateConfig.Write()
+		provisioned = requested
+	} else if provisioned != requested {
+		fmt.Println(fmt.Sprintf("   WARN: The instance was provisioned with '%s' set to '%t'. Delete the instance to change the mode.", configCmd.KubernetesOnly.Name, provisioned))
+	}
+
+	if !provisioned {
+		return false
+	}
+	if viper.GetBool(configCmd.ServiceCatalog.Name) {
+		atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("The service catalog cannot be enabled in combination with '%s'.", configCmd.KubernetesOnly.Name))
+	}
+	for _, flag := range clusterUpFlags {
+		if name, _ := oc.ClusterUpFlagName(flag); name == "enable" {
+			atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("The flag '--enable' cannot be passed via '%s' in combination with '%s'.", configCmd.ClusterUpFlags.Name, configCmd.KubernetesOnly.Name))
+		}
+	}
+	return true
+}
+
+// configureContainerRuntime configures the kubelet to use the specified container runtime and restarts OpenShift
+func configureContainerRuntime(dockerCommander docker.DockerCommander, containerRuntime string) {
+	if !containerruntime.IsRemote(containerRuntime) {
+		return
+	}
+
+	fmt.Println(fmt.Sprintf("-- Configuring the kubelet to use the '%s' container runtime", containerRuntime))
+	patch, err := containerruntime.KubeletArgumentsPatch(containerRuntime)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	ok, err := openshift.Patch(openshift.GetOpenShiftPatchTarget("node"), patch, dockerCommander)
+	if err != nil || !ok {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error configuring the container runtime '%s': %v", containerRuntime, err))
+	}
+}
+
+// ensureAPIExtraSANs re-issues the serving certificates of the API servers if they lack any of the names specified
+// via api-extra-sans, so that the API can be reached under these names as well.
+func ensureAPIExtraSANs(dockerCommander docker.DockerCommander) {
+	sans := getSlice(configCmd.APIExtraSANs.Name)
+	if len(sans) == 0 {
+		return
+	}
+
+	reissued, err := minishiftTLS.EnsureServingCertSANs(sans, dockerCommander)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error adding the extra names to the API serving certificates: %v", err))
+	}
+	if !reissued {
+		return
+	}
+
+	fmt.Println(fmt.Sprintf("-- Restarting OpenShift to serve the API for %s", strings.Join(sans, ", ")))
+	if _, err := openshift.RestartOpenShift(dockerCommander); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error restarting OpenShift: %v", err))
+	}
+}
+
+// applyPatchSets re-applies the OpenShift configuration patch sets stored in the profile.
+func applyPatchSets(dockerCommander docker.DockerCommander) {
+	patchSets := minishiftConfig.InstanceConfig.PatchSets
+	if len(patchSets) == 0 {
+		return
+	}
+
+	fmt.Println(fmt.Sprintf("-- Applying %d OpenShift configuration patch set(s)", len(patchSets)))
+	err := openshift.ApplyPatchSets(patchSets, dockerCommander)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+}
+
+// importExtraTemplates imports the templates and image streams configured via extra-templates into the openshift namespace.
+func importExtraTemplates(ocPath string) {
+	sources := viper.GetStringSlice(configCmd.ExtraTemplates.Name)
+	if len(sources) == 0 {
+		return
+	}
+
+	fmt.Println("-- Importing extra templates and image streams")
+	ocRunner, err := oc.NewOcRunner(ocPath, constants.KubeConfigPath)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	err = openshift.ImportTemplates(ocRunner, sources)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error importing extra templates: %v", err))
+	}
+}
+
+// applyComponentImages replaces the images of the router, the registry and the web console as specified via the
+// image settings. It runs on every start, so that the overrides survive a restart and changed settings take effect.
+func applyComponentImages(ocPath string, images map[string]string) {
+	ocRunner, err := oc.NewOcRunner(ocPath, constants.KubeConfigPath)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	if err := clusterup.ApplyComponentImages(ocRunner, images); err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+}
+
+// reconcilePersistentVolumes adjusts the pre-created persistent volumes to pv-count, pv-size and pv-reclaim-policy.
+// For the default pool created by 'cluster up' only the volumes of a former, larger pool are removed.
+func reconcilePersistentVolumes(sshCommander provision.SSHCommander, ocPath string) {
+	pool := pv.NewPool(viper.GetInt(configCmd.PVCount.Name), viper.GetString(configCmd.PVSize.Name), viper.GetString(configCmd.PVReclaimPolicy.Name))
+	ocRunner, err := oc.NewOcRunner(ocPath, constants.KubeConfigPath)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	if pool.IsDefault() {
+		if err := pool.RemoveExtraVolumes(ocRunner); err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error reconciling persistent volumes: %v", err))
+		}
+		return
+	}
+
+	fmt.Println(fmt.Sprintf("-- Reconciling %d persistent volume(s) of %s", pool.Count, pool.Size))
+	if err := pool.Reconcile(sshCommander, ocRunner); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error reconciling persistent volumes: %v", err))
+	}
+}
+
+// configureIdentityProvider configures the identity provider specified via identity-provider, including the files it references.
+// Without the setting, reconcileUsers manages the identity provider and restores the default one if needed.
+func configureIdentityProvider(driver drivers.Driver, dockerCommander docker.DockerCommander) {
+	kind := viper.GetString(configCmd.IdentityProvider.Name)
+	if kind == "" {
+		return
+	}
+
+	provider := &identityprovider.IdentityProvider{
+		Kind:             kind,
+		HtpasswdFile:     viper.GetString(configCmd.HtpasswdFile.Name),
+		LDAPURL:          viper.GetString(configCmd.LDAPURL.Name),
+		LDAPBindDN:       viper.GetString(configCmd.LDAPBindDN.Name),
+		LDAPBindPassword: viper.GetString(configCmd.LDAPBindPassword.Name),
+		LDAPCAFile:       viper.GetString(configCmd.LDAPCA.Name),
+	}
+
+	fmt.Println(fmt.Sprintf("-- Configuring the '%s' identity provider", kind))
+	client, err := sshutil.NewSSHClient(driver)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error configuring the identity provider: %v", err))
+	}
+	defer client.Close()
+
+	err = openshift.ConfigureIdentityProvider(provider, minishiftConfig.InstanceConfig.Users, client, dockerCommander)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error configuring the identity provider: %v", err))
+	}
+}
+
+// configureAuditLogging enables or disables the audit log of the API servers as specified via audit-logging.
+// The configuration of the API servers is left alone unless the audit log is or was enabled.
+func configureAuditLogging(dockerCommander docker.DockerCommander) {
+	enabled := viper.GetBool(configCmd.AuditLogging.Name)
+	if !enabled && !minishiftConfig.InstanceStateConfig.AuditLogging {
+		return
+	}
+
+	if enabled {
+		fmt.Println("-- Enabling the API audit log")
+	} else {
+		fmt.Println("-- Disabling the API audit log")
+	}
+	if err := openshift.ConfigureAuditLogging(enabled, dockerCommander); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error configuring the API audit log: %v", err))
+	}
+	minishiftConfig.InstanceStateConfig.AuditLogging = enabled
+	minishiftConfig.InstanceStateConfig.Write()
+}
+
+// reconcileUsers makes sure the users declared in the profile exist with their passwords and role bindings. Unless
+// identity-provider is set, it also switches between the htpasswd and the default identity provider.
+func reconcileUsers(dockerCommander docker.DockerCommander, ocPath string) {
+	users := minishiftConfig.InstanceConfig.Users
+	if len(users) > 0 {
+		fmt.Println(fmt.Sprintf("-- Reconciling %d declared OpenShift user(s)", len(users)))
+	}
+	ocRunner, err := oc.NewOcRunner(ocPath, constants.KubeConfigPath)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	manageIdentityProvider := viper.GetString(configCmd.IdentityProvider.Name) == ""
+	err = openshift.ReconcileUsers(users, manageIdentityProvider, dockerCommander, ocRunner)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error reconciling users: %v", err))
 	}
 }
 
@@ -347,7 +783,7 @@ func postClusterUp(hostVm *host.Host, clusterUpConfig *clusterup.ClusterUpConfig
 	sshCommander := provision.GenericSSHCommander{Driver: hostVm.Driver}
 	err := clusterup.PostClusterUp(clusterUpConfig, sshCommander, addon.GetAddOnManager(), &util.RealRunner{})
 	if err != nil {
-		atexit.ExitWithMessage(1, fmt.Sprintf("Error during post cluster up configuration: %v", err))
+		atexit.ExitWithReason(atexit.ClusterUpFailure, fmt.Sprintf("Error during post cluster up configuration: %v", err))
 	}
 }
 
@@ -395,17 +831,13 @@ func handleProxyConfig() *util.ProxyConfig {
 		if proxyConfig.HttpProxy() != "" && !localProxy {
 			// workaround - as these should never be persisted here
 			viper.Set(configCmd.HttpProxy.Name, proxyConfig.HttpProxy())
-			if glog.V(5) {
-				fmt.Println(fmt.Sprintf("\tUsing http proxy: %s", proxyConfig.HttpProxy()))
-			}
+			logger.Debugf("\tUsing http proxy: %s", proxyConfig.HttpProxy())
 		}
 
 		if proxyConfig.HttpsProxy() != "" && !localProxy {
 			// workaround - as these should never be persisted here
 			viper.Set(configCmd.HttpsProxy.Name, proxyConfig.HttpsProxy())
-			if glog.V(5) {
-				fmt.Println(fmt.Sprintf("\tUsing https proxy: %s", proxyConfig.HttpsProxy()))
-			}
+			logger.Debugf("\tUsing https proxy: %s", proxyConfig.HttpsProxy())
 		}
 		viper.Set(configCmd.NoProxyList.Name, proxyConfig.NoProxy())
 	}
@@ -413,6 +845,58 @@ func handleProxyConfig() *util.ProxyConfig {
 	return proxyConfig
 }
 
+// handleRegistryCache records the mirror address of the registry cache if enabled
+func handleRegistryCache() {
+	if !viper.GetBool(configCmd.RegistryCache.Name) {
+		return
+	}
+
+	registryCacheMirror = registrycache.MirrorURL(viper.GetInt(configCmd.ServicesRegistryCachePort.Name))
+}
+
+// startRegistryCache starts the registry cache daemon listening on localhost and the host address on the instance network
+func startRegistryCache(hostip string) {
+	fmt.Println("-- Starting registry cache")
+	port := viper.GetInt(configCmd.ServicesRegistryCachePort.Name)
+	if err := registrycache.EnsureRegistryCacheDaemonRunning(port, hostip); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error starting the registry cache: %v", err))
+	}
+}
+
+// dockerDaemonConfig returns the configuration of the Docker daemon which is rendered into its systemd drop-in.
+// Variables of the docker-env-file take precedence over the proxy settings and are overridden by docker-env.
+func dockerDaemonConfig() docker.DaemonConfig {
+	env := append([]string{}, dockerEnv...)
+	if envFile := viper.GetString(configCmd.DockerEnvFile.Name); envFile != "" {
+		fileEnv, err := docker.ReadEnvFile(envFile)
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error reading the Docker environment file '%s': %v", envFile, err))
+		}
+		env = append(env, fileEnv...)
+	}
+	env = append(env, getSlice(configCmd.DockerEnv.Name)...)
+
+	// the flags are not validated like the configuration properties
+	for _, option := range getSlice(configCmd.DockerStorageOpt.Name) {
+		if err := docker.ValidateStorageOption(option); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+	}
+	for _, option := range getSlice(configCmd.DockerEngineOpt.Name) {
+		if err := docker.ValidateOption(option); err != nil {
+			atexit.ExitWithMessage(1, err.Error())
+		}
+	}
+
+	return docker.DaemonConfig{
+		Env:                env,
+		InsecureRegistries: determineInsecureRegistry(configCmd.InsecureRegistry.Name),
+		RegistryMirrors:    determineRegistryMirror(configCmd.RegistryMirror.Name),
+		StorageOptions:     getSlice(configCmd.DockerStorageOpt.Name),
+		Options:            docker.MergeOptions(nil, getSlice(configCmd.DockerEngineOpt.Name)),
+	}
+}
+
 // getSlice return slice for provided key otherwise nil
 func getSlice(key string) []string {
 	if viper.IsSet(key) {
@@ -423,14 +907,23 @@ func getSlice(key string) []string {
 
 func determineInsecureRegistry(key string) []string {
 	s := getSlice(key)
-	if s != nil {
-		for _, v := range s {
-			if v == defaultInsecureRegistry {
-				return s
-			}
-		}
+	if !stringUtils.Contains(s, defaultInsecureRegistry) {
+		s = append(s, defaultInsecureRegistry)
+	}
+	// the registry cache is served via plain HTTP
+	if registryCacheMirror != "" {
+		s = append(s, strings.TrimPrefix(registryCacheMirror, "http://"))
+	}
+	return s
+}
+
+// determineRegistryMirror returns the configured registry mirrors, including the registry cache if it is enabled
+func determineRegistryMirror(key string) []string {
+	s := getSlice(key)
+	if registryCacheMirror != "" && !stringUtils.Contains(s, registryCacheMirror) {
+		s = append(s, registryCacheMirror)
 	}
-	return append(s, defaultInsecureRegistry)
+	return s
 }
 
 func startHost(libMachineClient *libmachine.Client) *host.Host {
@@ -438,14 +931,12 @@ func startHost(libMachineClient *libmachine.Client) *host.Host {
 	machineConfig := &cluster.MachineConfig{
 		MinikubeISO:           determineIsoUrl(viper.GetString(configCmd.ISOUrl.Name)),
 		ISOCacheDir:           state.InstanceDirs.IsoCache,
+		ISOSigningKey:         viper.GetString(configCmd.ISOSigningKey.Name),
 		Memory:                calculateMemorySize(viper.GetString(configCmd.Memory.Name)),
 		CPUs:                  viper.GetInt(configCmd.CPUs.Name),
 		DiskSize:              calculateDiskSize(viper.GetString(configCmd.DiskSize.Name)),
 		VMDriver:              viper.GetString(configCmd.VmDriver.Name),
-		DockerEnv:             append(dockerEnv, getSlice(configCmd.DockerEnv.Name)...),
-		DockerEngineOpt:       getSlice(configCmd.DockerEngineOpt.Name),
-		InsecureRegistry:      determineInsecureRegistry(configCmd.InsecureRegistry.Name),
-		RegistryMirror:        getSlice(configCmd.RegistryMirror.Name),
+		DockerDaemon:          dockerDaemonConfig(),
 		HostOnlyCIDR:          viper.GetString(configCmd.HostOnlyCIDR.Name),
 		HypervVirtualSwitch:   viper.GetString(configCmd.HypervVirtualSwitch.Name),
 		ShellProxyEnv:         shellProxyEnv,
@@ -455,6 +946,22 @@ func startHost(libMachineClient *libmachine.Client) *host.Host {
 		UsingLocalProxy:       viper.GetBool(configCmd.LocalProxy.Name),
 	}
 	minishiftConfig.InstanceStateConfig.VMDriver = machineConfig.VMDriver
+	dockerVersion := viper.GetString(configCmd.DockerVersion.Name)
+	if machineConfig.VMDriver != genericDriver {
+		flavor := iso.ForISO(machineConfig.MinikubeISO)
+		minishiftConfig.InstanceStateConfig.IsoFlavor = flavor.Name
+		minishiftConfig.InstanceStateConfig.InitSystem = flavor.InitSystem
+		minishiftConfig.InstanceStateConfig.PackageManager = flavor.PackageManager
+		if dockerVersion != "" {
+			if err := flavor.ValidateDockerVersion(dockerVersion); err != nil {
+				atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+			}
+		}
+		machineConfig.DockerEngine = docker.Engine{Version: dockerVersion, PackageManager: flavor.PackageManager, ProxyEnv: shellProxyEnv.ProxyConfig()}
+	} else if dockerVersion != "" {
+		atexit.ExitWithReason(atexit.ConfigurationError, "Selecting the Docker version is not supported by the generic driver")
+	}
+	minishiftConfig.InstanceStateConfig.DockerVersion = dockerVersion
 	minishiftConfig.InstanceStateConfig.Write()
 
 	fmt.Printf(" using '%s' hypervisor ...\n", machineConfig.VMDriver)
@@ -496,13 +1003,13 @@ func startHost(libMachineClient *libmachine.Client) *host.Host {
 		hostVm, err = cluster.StartHost(libMachineClient, *machineConfig)
 		if err != nil {
 			fmt.Print(" FAIL ")
-			glog.Errorf("Error starting the VM: %s. Retrying.\n", err)
+			logger.Errorf("Error starting the VM: %s. Retrying.\n", err)
 		}
 		return err
 	}
 	err := util.Retry(3, start)
 	if err != nil {
-		atexit.ExitWithMessage(1, fmt.Sprintf("Error starting the VM: %v", err))
+		atexit.ExitWithReason(atexit.DriverError, fmt.Sprintf("Error starting the VM: %v", err))
 	}
 	progressDots.Stop()
 
@@ -537,9 +1044,14 @@ func autoMountHostFolders(driver drivers.Driver) {
 	if err != nil {
 		atexit.ExitWithMessage(1, err.Error())
 	}
-	if isAutoMount() && hostFolderManager.ExistAny() {
+	if !hostFolderManager.ExistAny() {
+		return
+	}
+	if isAutoMount() {
 		fmt.Println("-- Mounting host folders")
 		hostFolderManager.MountAll(driver)
+	} else if err := hostFolderManager.RestoreMounts(driver); err != nil {
+		fmt.Println(fmt.Sprintf("   WARN: Host folders mounted before the restart could not be mounted again: %v", err))
 	}
 }
 
@@ -580,16 +1092,40 @@ func importContainerImages(driver drivers.Driver, api libmachine.API, openShiftV
 
 	handler := getImageHandler(driver, envMap)
 	config := &image.ImageCacheConfig{
-		HostCacheDir: state.InstanceDirs.ImageCache,
-		CachedImages: images,
-		Out:          os.Stdout,
+		HostCacheDir:       state.InstanceDirs.ImageCache,
+		CachedImages:       images,
+		Out:                os.Stdout,
+		ImageMissStrategy:  image.Pull,
+		Workers:            viper.GetInt(configCmd.ImageCachingWorkers.Name),
+		InsecureRegistries: determineInsecureRegistry(configCmd.InsecureRegistry.Name),
 	}
-	_, err = handler.ImportImages(config)
+	importedImages, err := handler.ImportImages(config)
+	cmdUtil.RecordCacheReferences(cache.ImageArtifact, importedImages...)
 	if err != nil {
 		fmt.Println(fmt.Sprintf("  WARN: At least one image could not be imported. Error: %s ", err.Error()))
 	}
 }
 
+// loadHostImages loads the images configured via 'host-images' from the container engine of the host into the VM
+func loadHostImages(driver drivers.Driver) {
+	images := getSlice(configCmd.HostImages.Name)
+	if len(images) == 0 {
+		return
+	}
+
+	loader, err := image.NewHostImageLoader(driver, viper.GetString(configCmd.HostImagesCli.Name))
+	if err != nil {
+		fmt.Println(fmt.Sprintf("  WARN: Images cannot be loaded from the host. Error: %s ", err.Error()))
+		return
+	}
+
+	fmt.Println("-- Loading images from the host")
+	_, err = loader.LoadImages(images, os.Stdout)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("  WARN: At least one image could not be loaded from the host. Error: %s ", err.Error()))
+	}
+}
+
 func getImageHandler(driver drivers.Driver, envMap map[string]string) image.ImageHandler {
 	handler, err := image.NewOciImageHandler(driver, envMap)
 	if err != nil {
@@ -648,7 +1184,7 @@ func calculateMemorySize(memorySize string) int {
 	size, err := units.RAMInBytes(memorySize)
 	if err != nil {
 		fmt.Println()
-		atexit.ExitWithMessage(1, fmt.Sprintf("Memory size is not valid: %v", err))
+		atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("Memory size is not valid: %v", err))
 	}
 
 	return int(size / units.MiB)
@@ -663,26 +1199,26 @@ func calculateDiskSize(humanReadableSize string) int {
 	size, err := units.FromHumanSize(humanReadableSize)
 	if err != nil {
 		fmt.Println()
-		atexit.ExitWithMessage(1, fmt.Sprintf("Disk size is not valid: %v", err))
+		atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("Disk size is not valid: %v", err))
 	}
 
 	return int(size / units.MB)
 }
 
-func determineIsoUrl(iso string) string {
-	isoNotSpecified := ""
+func determineIsoUrl(isoURL string) string {
+	if isoURL == "" {
+		isoURL = minishiftConstants.CentOsIsoAlias
+	}
 
-	switch strings.ToLower(iso) {
-	case minishiftConstants.CentOsIsoAlias, isoNotSpecified:
-		iso = constants.DefaultCentOsIsoUrl
-	default:
-		if !(govalidator.IsURL(iso) || strings.HasPrefix(iso, "file:")) {
-			fmt.Println()
-			atexit.ExitWithMessage(1, unsupportedIsoUrlFormat)
-		}
+	if flavor, found := iso.Lookup(isoURL); found {
+		return flavor.URL
+	}
+	if !(govalidator.IsURL(isoURL) || strings.HasPrefix(isoURL, "file:")) {
+		fmt.Println()
+		atexit.ExitWithReason(atexit.ConfigurationError, unsupportedIsoUrlFormat)
 	}
 
-	return iso
+	return isoURL
 }
 
 // initStartFlags creates the CLI flags which needs to be passed on to 'libmachine'
@@ -700,11 +1236,19 @@ func initStartFlags() *flag.FlagSet {
 	startFlagSet.String(configCmd.RemoteIPAddress.Name, "", "IP address of the remote machine to provision OpenShift on")
 	startFlagSet.String(configCmd.RemoteSSHUser.Name, "", "The username of the remote machine to provision OpenShift on")
 	startFlagSet.String(configCmd.SSHKeyToConnectRemote.Name, "", "SSH private key location on the host to connect remote machine")
-	startFlagSet.String(configCmd.ISOUrl.Name, minishiftConstants.CentOsIsoAlias, "Location of the minishift ISO. Can be a URL, file URI or one of the following short names: [centos].")
+	startFlagSet.String(configCmd.ISOUrl.Name, minishiftConstants.CentOsIsoAlias, "Location of the minishift ISO. Can be a URL, file URI or an ISO flavor listed by 'minishift iso list'.")
 	startFlagSet.String(configCmd.TimeZone.Name, constants.DefaultTimeZone, "TimeZone for Minishift VM")
+	startFlagSet.String(configCmd.CloudInit.Name, "", "Path of a cloud-init user-data file applied to the Minishift VM when it is created, for example to install extra packages or add users.")
+	startFlagSet.Bool(configCmd.ServiceCatalog.Name, false, "Install the service catalog and the template service broker and wait until they are ready.")
+	startFlagSet.Bool(configCmd.KubernetesOnly.Name, false, "Provision a plain Kubernetes control plane without the OpenShift components like router, registry and web console.")
+	startFlagSet.String(configCmd.ContainerRuntime.Name, containerruntime.DefaultRuntime, fmt.Sprintf("The container runtime used by the kubelet. Possible values: %v", containerruntime.SupportedRuntimes))
+	startFlagSet.String(configCmd.Preset.Name, "", "The preset bundling the sizing, the add-ons and the flags to start with, for example 'developer', 'ci' or 'demo'. See 'minishift preset list'.")
 
 	startFlagSet.AddFlag(dockerEnvFlag)
 	startFlagSet.AddFlag(dockerEngineOptFlag)
+	startFlagSet.AddFlag(dockerStorageOptFlag)
+	startFlagSet.String(configCmd.DockerVersion.Name, "", fmt.Sprintf("The Docker engine version to install in the VM, from those supported by the ISO flavor. CentOS ISO: %v", iso.CentOsDockerVersions))
+	startFlagSet.String(configCmd.DockerEnvFile.Name, "", "Path of a file with environment variables to pass to the Docker daemon, one <key>=<value> per line.")
 	startFlagSet.AddFlag(insecureRegistryFlag)
 	startFlagSet.AddFlag(registryMirrorFlag)
 	startFlagSet.AddFlag(cmdUtil.AddOnEnvFlag)
@@ -717,6 +1261,7 @@ func initStartFlags() *flag.FlagSet {
 		startFlagSet.String(configCmd.HypervVirtualSwitch.Name, "Default Switch", "Specify which Virtual Switch to use for the instance (Hyper-V only)")
 	}
 	startFlagSet.AddFlag(nameServersFlag)
+	startFlagSet.AddFlag(apiExtraSANsFlag)
 
 	if minishiftConfig.EnableExperimental {
 		startFlagSet.Bool(configCmd.NoProvision.Name, false, "Do not provision the VM with OpenShift (experimental)")
@@ -741,6 +1286,10 @@ func populateStartFlagsToViperConfig() {
 	startFlagSet.AddFlag(cmdUtil.HttpProxyFlag)
 	startFlagSet.AddFlag(cmdUtil.HttpsProxyFlag)
 	startFlagSet.VisitAll(func(flag *flag.Flag) {
+		// values of environment variables are meant for the current invocation only
+		if _, isEnvOverride := configCmd.EnvOverride(flag.Name); isEnvOverride && !flag.Changed {
+			return
+		}
 		if viper.IsSet(flag.Name) {
 			switch value := viper.Get(flag.Name).(type) {
 			case string:
@@ -811,6 +1360,72 @@ func getPasswordInteractive(message string) string {
 	return util.ReadPasswordFromStdin(message)
 }
 
+// applyPreset applies the configuration properties of the preset selected by --preset or the persistent configuration
+// and enables its add-ons, replacing the add-ons of the preset the previous VM was created with. Flags and environment
+// variables take precedence over the values of the preset. It is only called when the VM gets created.
+func applyPreset(flags *flag.FlagSet) {
+	name := viper.GetString(configCmd.Preset.Name)
+	if name == "" {
+		addon.ApplyPresetAddOns(nil)
+		return
+	}
+
+	p, err := preset.Load(minishiftConstants.GetPresetsDir(), name)
+	if err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
+	if err := configCmd.ApplyPreset(p, flags); err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
+	fmt.Println(fmt.Sprintf("-- Applying preset '%s'", p.Name))
+	addon.ApplyPresetAddOns(p.AddOns)
+}
+
+// applyImagePolicy copies the configured image signature policy as well as the signature storage configuration into the VM
+func applyImagePolicy(driver drivers.Driver) {
+	policy := viper.GetString(configCmd.ImagePolicy.Name)
+	registriesDir := viper.GetString(configCmd.ImageSignatureConfig.Name)
+	if policy == "" && registriesDir == "" {
+		return
+	}
+
+	if err := imagepolicy.Apply(driver, policy, registriesDir); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error applying the image signature policy: %v", err))
+	}
+}
+
+// readCloudInitUserData returns the content of the configured cloud-init user-data file, empty if none is configured
+func readCloudInitUserData() string {
+	path := viper.GetString(configCmd.CloudInit.Name)
+	if path == "" {
+		return ""
+	}
+
+	userData, err := cloudinit.Parse(path)
+	if err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
+	return string(userData.Content)
+}
+
+// applyCloudInit applies the cloud-init user-data recorded when the VM was created. Since the root file system of
+// the ISO is not persistent, it is applied on every start.
+func applyCloudInit(driver drivers.Driver) {
+	userData := minishiftConfig.InstanceStateConfig.CloudInitUserData
+	if userData == "" {
+		return
+	}
+
+	packageManager := minishiftConfig.InstanceStateConfig.PackageManager
+	if packageManager == iso.PackageManagerNone {
+		packageManager = ""
+	}
+	fmt.Println("-- Applying the cloud-init user-data ...")
+	if err := cloudinit.Apply(driver, userData, constants.MachineName, packageManager); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error applying the cloud-init user-data: %v", err))
+	}
+}
+
 func applyDockerEnvToProcessEnv(libMachineClient *libmachine.Client) {
 	// Making sure the required Docker environment variables are set to make 'cluster up' work
 	envMap, err := cluster.GetHostDockerEnv(libMachineClient)
@@ -830,11 +1445,30 @@ func IsOpenShiftRunning(driver drivers.Driver) bool {
 }
 
 func cacheMinishiftISO(config *cluster.MachineConfig) {
+	isoLock, err := lock.Wait(config.GetISOCacheFilepath()+".lock", cache.DownloadLockTimeout,
+		"-- Waiting for another Minishift process caching the ISO")
+	if err != nil {
+		atexit.ExitWithReason(atexit.DownloadError, fmt.Sprintf("Error locking the ISO cache: %s", err.Error()))
+	}
+	defer isoLock.Release()
+
+	// a corrupted ISO fails in mysterious ways when booting the VM
+	if config.IsMinikubeISOCached() {
+		if err := iso.VerifyCached(config.GetISOCacheFilepath(), config.MinikubeISO, config.ISOSigningKey); err != nil {
+			atexit.ExitWithReason(atexit.DownloadError, fmt.Sprintf("The cached ISO '%s' is corrupted: %v. Run 'minishift delete --clear-cache' and start again.",
+				config.GetISOCacheFilepath(), err))
+		}
+	}
+
 	if config.ShouldCacheMinikubeISO() {
 		if err := config.CacheMinikubeISOFromURL(); err != nil {
-			atexit.ExitWithMessage(1, fmt.Sprintf("Error caching the ISO: %s", err.Error()))
+			atexit.ExitWithReason(atexit.DownloadError, fmt.Sprintf("Error caching the ISO: %s", err.Error()))
 		}
 	}
+
+	if config.IsMinikubeISOCached() {
+		cmdUtil.RecordCacheReferences(cache.IsoArtifact, config.GetISOCacheFilepath())
+	}
 }
 
 // if skip-startup-checks set to true then return true and skip preflight checks
@@ -858,9 +1492,7 @@ func setStaticIP(hostVm *host.Host) {
 	_, err := minishiftNetwork.ConfigureStaticAssignment(hostVm.Driver)
 	if err != nil {
 		fmt.Println("WARN")
-		if glog.V(2) {
-			fmt.Println(err)
-		}
+		logger.Debugf("%s", err)
 	} else {
 		fmt.Println("OK")
 	}
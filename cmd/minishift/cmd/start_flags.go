@@ -0,0 +1,119 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// immutableStartFlags are the start flags which only take effect when the VM is created. Changing them for an existing
+// instance has no effect or produces a broken cluster.
+var immutableStartFlags = []string{
+	configCmd.VmDriver.Name,
+	configCmd.DiskSize.Name,
+	configCmd.OpenshiftVersion.Name,
+}
+
+// recordStartFlags stores the effective values of the start flags in the instance state, so that the next start
+// can detect changes of immutable settings. Secret values are redacted.
+func recordStartFlags(flags *flag.FlagSet) {
+	recorded := make(map[string]string)
+	flags.VisitAll(func(flag *flag.Flag) {
+		recorded[flag.Name] = fmt.Sprintf("%v", configCmd.RedactedValue(flag.Name, effectiveStartFlagValue(flag.Name)))
+	})
+	minishiftConfig.InstanceStateConfig.StartFlags = recorded
+	minishiftConfig.InstanceStateConfig.Write()
+}
+
+// checkStartFlagDivergence exits if an immutable setting which is explicitly set differs from the value of the last
+// successful start. Defaults are not compared, since they change between Minishift releases. With force, the start
+// continues after printing a warning.
+func checkStartFlagDivergence(flags *flag.FlagSet, recorded map[string]string, force bool) {
+	diverging := divergingStartFlags(recorded, func(name string) (string, bool) {
+		value := effectiveStartFlagValue(name)
+		origin, err := configCmd.EffectiveValue(flags, name)
+		if err == nil && origin.Origin == configCmd.OriginDefault && value == flags.Lookup(name).DefValue {
+			return "", false
+		}
+		return value, true
+	})
+	if len(diverging) == 0 {
+		return
+	}
+
+	if force {
+		for _, difference := range diverging {
+			fmt.Println(fmt.Sprintf("   WARN: %s. The change has no effect on the existing instance.", difference))
+		}
+		return
+	}
+	atexit.ExitWithReason(atexit.ConfigurationError, fmt.Sprintf("The following settings differ from the last start of the instance:\n  %s\n"+
+		"Delete the instance to apply them, restore the previous values or use '--force' to start anyway.", strings.Join(diverging, "\n  ")))
+}
+
+// divergingStartFlags returns a description of every immutable setting whose requested value differs from the
+// recorded one. The lookup returns the requested value and whether the setting is requested explicitly.
+func divergingStartFlags(recorded map[string]string, lookup func(string) (string, bool)) []string {
+	var diverging []string
+	for _, name := range immutableStartFlags {
+		previous, ok := recorded[name]
+		if !ok {
+			continue
+		}
+		requested, ok := lookup(name)
+		if !ok || normalizeStartFlagValue(name, requested) == normalizeStartFlagValue(name, previous) {
+			continue
+		}
+		diverging = append(diverging, fmt.Sprintf("'%s' is '%s', but the instance was started with '%s'", name, requested, previous))
+	}
+	sort.Strings(diverging)
+	return diverging
+}
+
+// normalizeStartFlagValue returns a representation of the value which does not depend on the notation used, for
+// example the disk size in MB.
+func normalizeStartFlagValue(name string, value string) string {
+	switch name {
+	case configCmd.DiskSize.Name:
+		return fmt.Sprintf("%d", calculateDiskSize(value))
+	case configCmd.OpenshiftVersion.Name:
+		return strings.TrimPrefix(value, "v")
+	}
+	return value
+}
+
+func effectiveStartFlagValue(name string) string {
+	if values, ok := viper.Get(name).([]interface{}); ok {
+		var entries []string
+		for _, value := range values {
+			entries = append(entries, fmt.Sprintf("%v", value))
+		}
+		return strings.Join(entries, ",")
+	}
+	if values, ok := viper.Get(name).([]string); ok {
+		return strings.Join(values, ",")
+	}
+	return viper.GetString(name)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDivergingStartFlags(t *testing.T) {
+	recorded := map[string]string{
+		"vm-driver":         "kvm",
+		"disk-size":         "20GB",
+		"openshift-version": "v3.11.0",
+		"memory":            "4GB",
+	}
+	requested := map[string]string{
+		"vm-driver":         "virtualbox",
+		"disk-size":         "20000MB",
+		"openshift-version": "3.11.0",
+		"memory":            "8GB",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := requested[name]
+		return value, ok
+	}
+
+	diverging := divergingStartFlags(recorded, lookup)
+	assert.Equal(t, []string{"'vm-driver' is 'virtualbox', but the instance was started with 'kvm'"}, diverging,
+		"Only immutable settings are compared, independent of their notation")
+
+	requested["disk-size"] = "40GB"
+	delete(requested, "vm-driver")
+	diverging = divergingStartFlags(recorded, lookup)
+	assert.Equal(t, []string{"'disk-size' is '40GB', but the instance was started with '20GB'"}, diverging)
+
+	assert.Empty(t, divergingStartFlags(nil, lookup), "Instances started before flags were recorded are not checked")
+}
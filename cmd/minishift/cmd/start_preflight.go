@@ -226,6 +226,24 @@ func preflightChecksAfterStartingHost(driver drivers.Driver) {
 		"Insufficient disk space on the persistent storage volume")
 }
 
+// preflightCheckReasons classifies the failure of the pre-flight checks, keyed by the name of their skip option.
+// Checks which are not listed here fail with the generic exit code.
+var preflightCheckReasons = map[string]atexit.Reason{
+	configCmd.SkipCheckOpenShiftRelease.Name: atexit.ConfigurationError,
+	configCmd.SkipCheckOpenShiftVersion.Name: atexit.ConfigurationError,
+	configCmd.SkipCheckIsoUrl.Name:           atexit.ConfigurationError,
+	configCmd.SkipCheckVMDriver.Name:         atexit.DriverError,
+	configCmd.SkipCheckHyperkit.Name:         atexit.DriverError,
+	configCmd.SkipCheckHyperkitDriver.Name:   atexit.DriverError,
+	configCmd.SkipCheckKVMDriver.Name:        atexit.DriverError,
+	configCmd.SkipCheckHyperVDriver.Name:     atexit.DriverError,
+	configCmd.SkipCheckVBoxInstalled.Name:    atexit.DriverError,
+	configCmd.SkipInstanceIP.Name:            atexit.NetworkError,
+	configCmd.SkipCheckNameservers.Name:      atexit.NetworkError,
+	configCmd.SkipCheckNetworkPing.Name:      atexit.NetworkError,
+	configCmd.SkipCheckNetworkHTTP.Name:      atexit.NetworkError,
+}
+
 // preflightCheckFunc returns true when check passed
 type preflightCheckFunc func() bool
 
@@ -260,7 +278,7 @@ func preflightCheckSucceedsOrFails(configNameOverrideIfSkipped string, execute p
 	if isConfiguredToWarn {
 		fmt.Println(errorMessage)
 	} else {
-		atexit.ExitWithMessage(1, errorMessage)
+		exitWithPreflightFailure(configNameOverrideIfSkipped, errorMessage)
 	}
 }
 
@@ -292,8 +310,17 @@ func preflightCheckSucceedsOrFailsWithDriver(configNameOverrideIfSkipped string,
 	if isConfiguredToWarn {
 		fmt.Println(errorMessage)
 	} else {
-		atexit.ExitWithMessage(1, errorMessage)
+		exitWithPreflightFailure(configNameOverrideIfSkipped, errorMessage)
+	}
+}
+
+// exitWithPreflightFailure exits with the reason of the failed pre-flight check.
+func exitWithPreflightFailure(configNameOverrideIfSkipped string, errorMessage string) {
+	reason, ok := preflightCheckReasons[configNameOverrideIfSkipped]
+	if !ok {
+		reason = atexit.GenericError
 	}
+	atexit.ExitWithReason(reason, errorMessage)
 }
 
 func checkDeprecation() bool {
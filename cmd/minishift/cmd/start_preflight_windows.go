@@ -25,7 +25,6 @@ import (
 	"github.com/minishift/minishift/pkg/util/filehelper"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 
-	"github.com/golang/glog"
 	"golang.org/x/sys/windows/registry"
 )
 
@@ -63,9 +62,7 @@ func findVBoxInstallDirInRegistry() (string, error) {
 	registryKey, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Oracle\VirtualBox`, registry.QUERY_VALUE)
 	if err != nil {
 		errorMessage := fmt.Sprintf("Can't find VirtualBox registry entries, is VirtualBox really installed properly? %s", err)
-		if glog.V(2) {
-			fmt.Println(errorMessage)
-		}
+		logger.Debugf("%s", errorMessage)
 		return "", fmt.Errorf(errorMessage)
 	}
 	defer registryKey.Close()
@@ -73,9 +70,7 @@ func findVBoxInstallDirInRegistry() (string, error) {
 	installDir, _, err := registryKey.GetStringValue("InstallDir")
 	if err != nil {
 		errorMessage := fmt.Sprintf("Can't find InstallDir registry key within VirtualBox registries entries, is VirtualBox really installed properly? %s", err)
-		if glog.V(2) {
-			fmt.Println(errorMessage)
-		}
+		logger.Debugf("%s", errorMessage)
 		return "", fmt.Errorf(errorMessage)
 	}
 
@@ -224,6 +224,8 @@ func TestDetermineIsoUrl(t *testing.T) {
 	}{
 		{"centos", constants.DefaultCentOsIsoUrl},
 		{"CentOs", constants.DefaultCentOsIsoUrl},
+		{"b2d", constants.DefaultB2dIsoUrl},
+		{"", constants.DefaultCentOsIsoUrl},
 		{"http://my.custom.url/myiso.iso", "http://my.custom.url/myiso.iso"},
 		{"https://my.custom.url/myiso.iso", "https://my.custom.url/myiso.iso"},
 		{"https://my.custom.url/Myiso.iso", "https://my.custom.url/Myiso.iso"},
@@ -239,7 +241,7 @@ func TestDetermineIsoUrl(t *testing.T) {
 
 func TestDetermineIsoUrlWithInvalidName(t *testing.T) {
 	tee = cli.CreateTee(t, true)
-	atexit.RegisterExitHandler(cli.VerifyExitCodeAndMessage(t, tee, 1, unsupportedIsoUrlFormat))
+	atexit.RegisterExitHandler(cli.VerifyExitCodeAndMessage(t, tee, atexit.ConfigurationError.ExitCode(), unsupportedIsoUrlFormat))
 	defer tearDown()
 
 	determineIsoUrl("foo")
@@ -330,3 +332,26 @@ func tearDown() {
 		}
 	}
 }
+
+func Test_recoverIncompleteStart(t *testing.T) {
+	var tests = []struct {
+		previous  instanceState.Lifecycle
+		isRestart bool
+		expected  bool
+	}{
+		{instanceState.Lifecycle{}, true, true},
+		{instanceState.Lifecycle{}, false, false},
+		{instanceState.Lifecycle{State: instanceState.Running}, true, true},
+		{instanceState.Lifecycle{State: instanceState.ClusterStarting, InitialStart: true}, true, false},
+		{instanceState.Lifecycle{State: instanceState.Error, Phase: instanceState.Provisioning, InitialStart: true}, true, false},
+		{instanceState.Lifecycle{State: instanceState.Stopped, InitialStart: true}, true, false},
+		{instanceState.Lifecycle{State: instanceState.ClusterStarting}, true, true},
+		{instanceState.Lifecycle{State: instanceState.Stopping}, true, true},
+		{instanceState.Lifecycle{State: instanceState.Creating, InitialStart: true}, false, false},
+	}
+
+	for _, test := range tests {
+		actual := recoverIncompleteStart(nil, test.previous, test.isRestart)
+		assert.Equal(t, test.expected, actual, "Unexpected result for %+v", test.previous)
+	}
+}
@@ -28,13 +28,19 @@ import (
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/provision"
 	"github.com/docker/machine/libmachine/state"
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
 	cmdState "github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
 	openshiftVersion "github.com/minishift/minishift/pkg/minishift/openshift/version"
 	"github.com/minishift/minishift/pkg/minishift/registration"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var statusFormat = `Minishift:  {{.MinishiftStatus}}
@@ -42,14 +48,18 @@ Profile:    {{.ProfileName}}
 OpenShift:  {{.ClusterStatus}}
 DiskUsage:  {{.DiskUsage}}
 CacheUsage: {{.CacheUsage}} (used by oc binary, ISO or cached images)
-`
+{{if .ServiceCatalog}}ServiceCatalog: {{.ServiceCatalog}}
+{{end}}{{if .Lifecycle}}Lifecycle:  {{.Lifecycle}}
+{{end}}`
 
 var statusFormatWithRegistration = `Minishift:  {{.MinishiftStatus}}
 Profile:    {{.ProfileName}}
 OpenShift:  {{.ClusterStatus}}
 DiskUsage:  {{.DiskUsage}}
 CacheUsage: {{.CacheUsage}} (used by oc binary, ISO or cached images)
-RHSM: 	    {{.Registration}}
+{{if .ServiceCatalog}}ServiceCatalog: {{.ServiceCatalog}}
+{{end}}{{if .Lifecycle}}Lifecycle:  {{.Lifecycle}}
+{{end}}RHSM: 	    {{.Registration}}
 `
 
 type Status struct {
@@ -58,6 +68,8 @@ type Status struct {
 	ClusterStatus   string
 	DiskUsage       string
 	CacheUsage      string
+	ServiceCatalog  string
+	Lifecycle       string
 }
 
 type StatusWithRegistration struct {
@@ -83,6 +95,9 @@ func runStatus(cmd *cobra.Command, args []string) {
 		if err != nil {
 			atexit.ExitWithMessage(1, fmt.Sprintf("Error getting cluster status: %s", err.Error()))
 		}
+		if lifecycle := describeLifecycle(); lifecycle != "" {
+			s = fmt.Sprintf("%s\nLifecycle:  %s", s, lifecycle)
+		}
 		atexit.ExitWithMessage(0, s)
 	}
 	sshCommander := provision.GenericSSHCommander{Driver: host.Driver}
@@ -90,7 +105,9 @@ func runStatus(cmd *cobra.Command, args []string) {
 	openshiftStatus := "Stopped"
 	diskUsage := "Unknown"
 	cacheUsage := "Unknown"
+	serviceCatalogStatus := ""
 	profileName := constants.ProfileName
+	lifecycle := describeLifecycle()
 
 	vmStatus, err := cluster.GetHostStatus(api, constants.MachineName)
 	if err != nil {
@@ -104,6 +121,9 @@ func runStatus(cmd *cobra.Command, args []string) {
 		openshiftVersion, err := openshiftVersion.GetOpenshiftVersion(sshCommander)
 		if err == nil {
 			openshiftStatus = fmt.Sprintf("Running (%s)", strings.Split(openshiftVersion, "\n")[0])
+			if viper.GetBool(configCmd.ServiceCatalog.Name) {
+				serviceCatalogStatus = getServiceCatalogStatus()
+			}
 		}
 
 		diskSize, diskUse, mountpoint := getDiskUsage(host.Driver, StorageDisk)
@@ -121,9 +141,8 @@ func runStatus(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	cacheDir := filepath.Join(constants.GetMinishiftHomeDir(), "cache")
 	var size int64
-	err = filepath.Walk(cacheDir, func(_ string, info os.FileInfo, err error) error {
+	err = filepath.Walk(cmdState.InstanceDirs.Cache, func(_ string, info os.FileInfo, err error) error {
 		if !info.IsDir() {
 			size += info.Size()
 		}
@@ -135,14 +154,48 @@ func runStatus(cmd *cobra.Command, args []string) {
 
 	cacheUsage = units.HumanSize(float64(size))
 	if supportsRegistration {
-		status := StatusWithRegistration{Status{vmStatus, profileName, openshiftStatus, diskUsage, cacheUsage}, rhelRegistration}
+		status := StatusWithRegistration{Status{vmStatus, profileName, openshiftStatus, diskUsage, cacheUsage, serviceCatalogStatus, lifecycle}, rhelRegistration}
 		printStatus(status, statusFormatWithRegistration)
 	} else {
-		status := Status{vmStatus, profileName, openshiftStatus, diskUsage, cacheUsage}
+		status := Status{vmStatus, profileName, openshiftStatus, diskUsage, cacheUsage, serviceCatalogStatus, lifecycle}
 		printStatus(status, statusFormat)
 	}
 }
 
+// describeLifecycle explains where the last operation on the instance stopped if it did not complete, together with
+// how to recover.
+func describeLifecycle() string {
+	lifecycle := minishiftConfig.InstanceStateConfig.Lifecycle
+	description := cmdUtil.DescribeLifecycle(lifecycle)
+	if description == "" || cmdUtil.IsProfileLocked(constants.ProfileName) {
+		return description
+	}
+	if lifecycle.LastPhase() == minishiftConfig.Stopping {
+		return fmt.Sprintf("%s. Run 'minishift stop' to complete stopping or 'minishift start' to resume.", description)
+	}
+	return fmt.Sprintf("%s. Run 'minishift start' to resume.", description)
+}
+
+// getServiceCatalogStatus summarizes the readiness of the service catalog components, naming the components which
+// are not ready together with the reason.
+func getServiceCatalogStatus() string {
+	ocRunner, err := oc.NewOcRunner(minishiftConfig.InstanceStateConfig.OcPath, constants.KubeConfigPath)
+	if err != nil {
+		return "Unknown"
+	}
+
+	var notReady []string
+	for _, status := range openshift.ServiceCatalogStatus(ocRunner) {
+		if !status.Ready {
+			notReady = append(notReady, fmt.Sprintf("%s: %s", status.Name, status.Reason))
+		}
+	}
+	if len(notReady) > 0 {
+		return fmt.Sprintf("Not Ready (%s)", strings.Join(notReady, ", "))
+	}
+	return "Ready"
+}
+
 func printStatus(status interface{}, statusFormat string) {
 	tmpl, err := template.New("status").Parse(statusFormat)
 	if err != nil {
@@ -25,6 +25,8 @@ import (
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/cluster"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/spf13/cobra"
 )
@@ -39,6 +41,8 @@ VM but does not delete any associated files. To start the cluster again, use the
 }
 
 func runStop(cmd *cobra.Command, args []string) {
+	util.LockProfilesOrExit(constants.ProfileName)
+
 	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
 	defer api.Close()
 
@@ -52,10 +56,19 @@ func runStop(cmd *cobra.Command, args []string) {
 
 	// check if VM is already in stopped state
 	if util.IsHostStopped(hostVm.Driver) {
+		if minishiftConfig.InstanceStateConfig.Lifecycle.State == minishiftConfig.Stopping {
+			util.SetLifecycleState(minishiftConfig.Stopped)
+		}
 		atexit.ExitWithMessage(0, fmt.Sprintf("The '%s' VM is already stopped.", constants.MachineName))
 	}
 
 	fmt.Println("Stopping the OpenShift cluster...")
+	util.SetLifecycleState(minishiftConfig.Stopping)
+	util.RecordLifecycleErrors()
+
+	if err := hostfolder.SuspendSyncs(); err != nil {
+		fmt.Println("Unable to stop synchronizing the host folders:", err)
+	}
 
 	if hostVm.Driver.DriverName() == "generic" {
 		if err := util.OcClusterDown(hostVm); err != nil {
@@ -69,7 +82,9 @@ func runStop(cmd *cobra.Command, args []string) {
 			atexit.ExitWithMessage(1, fmt.Sprintf("Error stopping cluster: %s", err.Error()))
 		}
 	}
+	util.SetLifecycleState(minishiftConfig.Stopped)
 	fmt.Println("Cluster stopped.")
+	util.RestoreKubeContext()
 }
 
 func init() {
@@ -0,0 +1,62 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/util/logging"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/spf13/cobra"
+)
+
+var supportBundleOutput string
+
+// supportBundleCmd represents the support-bundle command
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle",
+	Short: "Collects the Minishift logs into an archive to attach to bug reports.",
+	Long: `Collects the log files of the logs directory, including the logs of the background processes and the log
+entries of the last failed command, into a gzipped tar archive to attach to bug reports.`,
+	Run: runSupportBundle,
+}
+
+func runSupportBundle(cmd *cobra.Command, args []string) {
+	output := supportBundleOutput
+	if output == "" {
+		output = fmt.Sprintf("minishift-support-%s.tar.gz", time.Now().Format("20060102-150405"))
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error creating the support bundle: %v", err))
+	}
+	defer file.Close()
+
+	if err := logging.WriteSupportBundle(file, constants.MakeMiniPath("logs")); err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Error creating the support bundle: %v", err))
+	}
+	fmt.Println(fmt.Sprintf("Support bundle written to '%s'.", output))
+}
+
+func init() {
+	supportBundleCmd.Flags().StringVarP(&supportBundleOutput, "output", "o", "", "The file the support bundle is written to (default minishift-support-<timestamp>.tar.gz).")
+	RootCmd.AddCommand(supportBundleCmd)
+}
@@ -27,6 +27,7 @@ import (
 	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
 	cmdutil "github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	validations "github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/util"
 	"github.com/minishift/minishift/pkg/version"
 	"github.com/spf13/cobra"
@@ -44,19 +45,26 @@ type UpdateMarker struct {
 const (
 	updateForceFlag = "force"
 	addonForceFlag  = "update-addons"
+
+	// updateAvailableExitCode is the exit code of 'update --check-only' if a newer version is available
+	updateAvailableExitCode = 2
 )
 
 var (
 	addonForce  bool
 	force       bool
+	checkOnly   bool
 	versionFlag string
+	channelFlag string
 )
 
 var updateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Updates Minishift to the latest version.",
-	Long:  `Checks for the latest version of Minishift, prompts the user, and updates the binary if the user answers 'y'.`,
-	Run:   runUpdate,
+	Long: `Checks for the latest version of Minishift on the release channel, prompts the user, and updates the binary if the user answers 'y'.
+The stable channel only offers final releases, the beta channel pre-releases as well. The downloaded binary is verified against its published checksum before it replaces the current executable.
+With --check-only, the command only reports whether a newer version is available and exits with status 2 if so.`,
+	Run: runUpdate,
 }
 
 var (
@@ -66,6 +74,9 @@ var (
 )
 
 func runUpdate(cmd *cobra.Command, args []string) {
+	if err := configCmd.DecryptSecrets(configCmd.HttpProxy, configCmd.HttpsProxy); err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
 	proxyConfig, err := util.NewProxyConfig(viper.GetString(configCmd.HttpProxy.Name), viper.GetString(configCmd.HttpsProxy.Name), "")
 	if err != nil {
 		atexit.ExitWithMessage(1, err.Error())
@@ -79,15 +90,23 @@ func runUpdate(cmd *cobra.Command, args []string) {
 	if err != nil {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Update failed: %s", err))
 	}
-	versionToUpdate, err := update.LatestVersion()
-	if err != nil {
-		atexit.ExitWithMessage(1, fmt.Sprintf("Update failed: %s", err))
-	}
+	channel := updateChannel()
+	var versionToUpdate semver.Version
 	if versionFlag != "" {
 		versionToUpdate, err = semver.Make(versionFlag)
-		if err != nil {
-			atexit.ExitWithMessage(1, fmt.Sprintf("Update failed: %s", err))
+		if err == nil {
+			err = update.IsReleasedOnChannel(versionToUpdate, channel)
 		}
+	} else {
+		versionToUpdate, err = update.LatestVersion(channel)
+	}
+	if err != nil {
+		atexit.ExitWithMessage(1, fmt.Sprintf("Update failed: %s", err))
+	}
+
+	if checkOnly {
+		checkForUpdate(currentVersion, versionToUpdate, channel)
+		return
 	}
 
 	if versionToUpdate.Major > currentVersion.Major {
@@ -97,6 +116,28 @@ func runUpdate(cmd *cobra.Command, args []string) {
 	performUpdate(currentVersion, versionToUpdate)
 }
 
+// updateChannel returns the release channel selected via flag or the update-channel setting
+func updateChannel() string {
+	channel := channelFlag
+	if channel == "" {
+		channel = viper.GetString(configCmd.UpdateChannel.Name)
+	}
+	if err := validations.IsValidUpdateChannel(configCmd.UpdateChannel.Name, channel); err != nil {
+		atexit.ExitWithReason(atexit.ConfigurationError, err.Error())
+	}
+	return channel
+}
+
+// checkForUpdate reports whether a newer version is available, exiting with updateAvailableExitCode if so
+func checkForUpdate(currentVersion, versionToUpdate semver.Version, channel string) {
+	if !update.IsNewerVersion(currentVersion, versionToUpdate) {
+		fmt.Println(fmt.Sprintf("Minishift %s is up to date on the '%s' channel.", currentVersion, channel))
+		return
+	}
+	fmt.Println(fmt.Sprintf("Minishift %s is available on the '%s' channel, the current version is %s.", versionToUpdate, channel, currentVersion))
+	atexit.Exit(updateAvailableExitCode)
+}
+
 func init() {
 	RootCmd.AddCommand(updateCmd)
 	updateCmd.Flags().AddFlag(cmdutil.HttpProxyFlag)
@@ -104,6 +145,8 @@ func init() {
 	updateCmd.Flags().BoolVarP(&force, updateForceFlag, "f", false, "Force update the binary.")
 	updateCmd.Flags().BoolVarP(&addonForce, addonForceFlag, "", false, "Force update the add-ons after the binary update. Otherwise, prompt the user to update add-ons.")
 	updateCmd.Flags().StringVar(&versionFlag, "version", "", "Specify the version to update (without 'v')")
+	updateCmd.Flags().StringVar(&channelFlag, "channel", "", fmt.Sprintf("The release channel to update from. Possible values: %v. Defaults to the update-channel setting.", update.ValidChannels))
+	updateCmd.Flags().BoolVar(&checkOnly, "check-only", false, "Only check whether a newer version is available. Exits with status 2 if so.")
 }
 
 func createUpdateMarker(markerPath string, data UpdateMarker) error {
@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/update"
+	"github.com/minishift/minishift/pkg/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// updateCheckWait is the time a command waits at its end for a running update check to complete
+const updateCheckWait = 2 * time.Second
+
+// updateCheckDone is closed once the update check started by this command completes
+var updateCheckDone chan struct{}
+
+// startUpdateCheck looks up the latest versions in the background if the last update check is older than a day.
+// Failures, for example because the host is offline, are only logged at debug level.
+func startUpdateCheck(cmd *cobra.Command) {
+	if !isUpdateCheckEnabled(cmd) {
+		return
+	}
+
+	statePath := minishiftConstants.GetUpdateCheckStatePath()
+	due := false
+	// the attempt is recorded up front, so that hosts without network access check once per day only
+	err := update.UpdateCheckState(statePath, func(state *update.CheckState) bool {
+		now := time.Now()
+		if !state.IsCheckDue(now) {
+			return false
+		}
+		state.LastCheck = now
+		due = true
+		return true
+	})
+	if err != nil {
+		logger.Debugf("Error recording the update check: %v", err)
+		return
+	}
+	if !due {
+		return
+	}
+
+	updateCheckDone = make(chan struct{})
+	go func() {
+		defer close(updateCheckDone)
+		latest, err := update.CheckLatestVersions(viper.GetString(configCmd.UpdateChannel.Name))
+		if err != nil {
+			logger.Debugf("Update check failed: %v", err)
+			return
+		}
+		err = update.UpdateCheckState(statePath, func(state *update.CheckState) bool {
+			state.Minishift = latest.Minishift
+			state.CentOsIso = latest.CentOsIso
+			state.OpenShift = latest.OpenShift
+			return true
+		})
+		if err != nil {
+			logger.Debugf("Error recording the update check: %v", err)
+		}
+	}()
+}
+
+// printUpdateNotice prints a one-line notice to stderr about the newer versions found by the update checks,
+// at most once per day.
+func printUpdateNotice(cmd *cobra.Command) {
+	if !isUpdateCheckEnabled(cmd) {
+		return
+	}
+	if updateCheckDone != nil {
+		select {
+		case <-updateCheckDone:
+		case <-time.After(updateCheckWait):
+		}
+	}
+
+	notice := ""
+	err := update.UpdateCheckState(minishiftConstants.GetUpdateCheckStatePath(), func(state *update.CheckState) bool {
+		now := time.Now()
+		if !state.IsNoticeDue(now) {
+			return false
+		}
+		notice = state.Notice(update.InstalledVersions{
+			Minishift: version.GetMinishiftVersion(),
+			CentOsIso: version.GetCentOsIsoVersion(),
+			OpenShift: version.GetOpenShiftVersion(),
+		})
+		if notice == "" {
+			return false
+		}
+		state.LastNotice = now
+		return true
+	})
+	if err != nil {
+		logger.Debugf("Error recording the update notice: %v", err)
+	}
+	if notice != "" {
+		fmt.Fprintln(os.Stderr, notice)
+	}
+}
+
+// isUpdateCheckEnabled returns false if the update checks are disabled via skip-update-check, for commands which
+// manage updates themselves or run in the background, and for development builds without a release version.
+// Commands defining their own PersistentPreRun, such as completion and version, never start an update check, since
+// cobra only runs the closest PersistentPreRun. They are excluded here, so that they do not print notices either.
+func isUpdateCheckEnabled(cmd *cobra.Command) bool {
+	if viper.GetBool(configCmd.SkipUpdateCheck.Name) {
+		return false
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Hidden || c == updateCmd || c == versionCmd || c == completionCmd {
+			return false
+		}
+	}
+	return !version.IsDevelopmentBuild()
+}
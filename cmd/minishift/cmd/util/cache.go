@@ -0,0 +1,164 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cache"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker/image"
+	pkgUtil "github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/logging"
+)
+
+var logger = logging.For("util")
+
+// RecordCacheReferences records that the active profile uses the specified cached artifacts. Artifacts in the cache
+// of a profile with isolated cache are deleted along with the profile, hence not recorded.
+func RecordCacheReferences(kind cache.ArtifactKind, ids ...string) {
+	if minishiftConfig.AllInstancesConfig == nil || (state.InstanceDirs != nil && state.InstanceDirs.IsCacheIsolated()) {
+		return
+	}
+
+	if minishiftConfig.AllInstancesConfig.CacheReferences == nil {
+		minishiftConfig.AllInstancesConfig.CacheReferences = cache.References{}
+	}
+
+	changed := false
+	for _, id := range ids {
+		if minishiftConfig.AllInstancesConfig.CacheReferences.Add(cache.ArtifactKey(kind, id), constants.ProfileName) {
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := minishiftConfig.AllInstancesConfig.Write(); err != nil {
+			logger.Debugf("Error recording cache references: %v", err)
+		}
+	}
+}
+
+// ReleaseCacheReference records that the active profile does not use the specified cached artifact anymore
+func ReleaseCacheReference(kind cache.ArtifactKind, id string) {
+	if minishiftConfig.AllInstancesConfig == nil || minishiftConfig.AllInstancesConfig.CacheReferences == nil {
+		return
+	}
+
+	if minishiftConfig.AllInstancesConfig.CacheReferences.Remove(cache.ArtifactKey(kind, id), constants.ProfileName) {
+		if err := minishiftConfig.AllInstancesConfig.Write(); err != nil {
+			logger.Debugf("Error recording cache references: %v", err)
+		}
+	}
+}
+
+// DeleteCachedArtifacts removes the artifacts with the specified keys from the shared cache
+func DeleteCachedArtifacts(keys []string) error {
+	multiError := pkgUtil.MultiError{}
+	var images []string
+	for _, key := range keys {
+		kind, id := cache.ParseArtifactKey(key)
+		switch kind {
+		case cache.IsoArtifact, cache.OcArtifact:
+			if err := os.RemoveAll(id); err != nil {
+				multiError.Collect(err)
+			} else {
+				logger.Debugf("Deleted: '%s'", id)
+			}
+		case cache.ImageArtifact:
+			images = append(images, id)
+		}
+	}
+
+	if len(images) > 0 {
+		multiError.Collect(deleteCachedImages(images))
+	}
+
+	return multiError.ToError()
+}
+
+// SharedCacheArtifacts returns the keys of the ISOs, oc binaries and images in the cache shared by all profiles
+func SharedCacheArtifacts() ([]string, error) {
+	sharedDirs := state.GetMinishiftDirsStructure(constants.GetMinishiftHomeDir())
+
+	var keys []string
+	walkCache := func(dir string, match func(path string) (cache.ArtifactKind, string, bool)) error {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return nil
+		}
+		return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			if kind, id, ok := match(path); ok {
+				keys = append(keys, cache.ArtifactKey(kind, id))
+			}
+			return nil
+		})
+	}
+
+	err := walkCache(sharedDirs.IsoCache, func(path string) (cache.ArtifactKind, string, bool) {
+		return cache.IsoArtifact, path, strings.HasSuffix(path, ".iso")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = walkCache(sharedDirs.OcCache, func(path string) (cache.ArtifactKind, string, bool) {
+		return cache.OcArtifact, filepath.Dir(path), filepath.Base(path) == constants.OC_BINARY_NAME
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	handler, err := image.NewLocalOnlyOciImageHandler()
+	if err != nil {
+		return nil, err
+	}
+	for imageName := range handler.GetCachedImages(&image.ImageCacheConfig{HostCacheDir: sharedDirs.ImageCache}) {
+		keys = append(keys, cache.ArtifactKey(cache.ImageArtifact, imageName))
+	}
+
+	return keys, nil
+}
+
+func deleteCachedImages(images []string) error {
+	handler, err := image.NewLocalOnlyOciImageHandler()
+	if err != nil {
+		return err
+	}
+
+	imageCacheConfig := &image.ImageCacheConfig{
+		HostCacheDir: state.InstanceDirs.ImageCache,
+		Out:          os.Stdout,
+	}
+	for _, imageName := range images {
+		if handler.IsImageCached(imageCacheConfig, imageName) {
+			imageCacheConfig.CachedImages = append(imageCacheConfig.CachedImages, imageName)
+		}
+	}
+
+	if len(imageCacheConfig.CachedImages) == 0 {
+		return nil
+	}
+	_, err = handler.PruneImages(imageCacheConfig)
+	return err
+}
@@ -0,0 +1,172 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/kubeconfig"
+	"github.com/minishift/minishift/pkg/minishift/network/dns"
+	"github.com/minishift/minishift/pkg/minishift/network/ipwatch"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	minishiftTLS "github.com/minishift/minishift/pkg/minishift/tls"
+)
+
+// NewIPWatcher creates a watcher reconciling changes of the IP of the VM, starting from the last known IP.
+func NewIPWatcher(api libmachine.API) *ipwatch.Watcher {
+	getIP := func() (string, error) {
+		hostVm, err := api.Load(constants.MachineName)
+		if err != nil {
+			return "", err
+		}
+		if vmState, err := hostVm.Driver.GetState(); err != nil || vmState != state.Running {
+			return "", nil
+		}
+		return hostVm.Driver.GetIP()
+	}
+	reconcile := func(oldIP string, newIP string) error {
+		hostVm, err := api.Load(constants.MachineName)
+		if err != nil {
+			return err
+		}
+		return ReconcileIPChange(api, hostVm, oldIP, newIP)
+	}
+	statePath := minishiftConfig.InstanceStateConfig.FilePath
+	lastKnownIP := func() string {
+		stateConfig, err := minishiftConfig.NewInstanceStateConfig(statePath)
+		if err != nil {
+			return ""
+		}
+		return stateConfig.LastKnownIP
+	}
+	return ipwatch.New(lastKnownIP, getIP, reconcile)
+}
+
+// RegenerateDockerCerts regenerates the server certificate of the Docker daemon of the VM for the current IP of the
+// VM and the SANs of the auth options. Regenerating the certificate restarts the Docker daemon, the method returns
+// true if OpenShift was running before and hence needs to be restarted.
+func RegenerateDockerCerts(api libmachine.API, hostVm *host.Host, commander docker.DockerCommander) (bool, error) {
+	status, _ := commander.Status(minishiftConstants.OpenshiftContainerName)
+
+	if err := hostVm.ConfigureAuth(); err != nil {
+		return false, err
+	}
+	if err := api.Save(hostVm); err != nil {
+		return false, err
+	}
+	return status == "running", nil
+}
+
+// ReconcileIPChange updates the configuration referring to the previous IP of the VM: the server URLs in the
+// kubeconfig files, the certificates of the Docker daemon and the API servers, the public URLs and routing subdomain of
+// OpenShift and the web console as well as the DNS server of the VM.
+// Once done, the new IP is recorded as last known IP of the instance.
+func ReconcileIPChange(api libmachine.API, hostVm *host.Host, oldIP string, newIP string) error {
+	fmt.Println(fmt.Sprintf("-- The IP of the VM changed from %s to %s", oldIP, newIP))
+
+	for _, path := range kubeConfigPaths() {
+		clusters, err := kubeconfig.UpdateServerIP(path, oldIP, newIP)
+		if err != nil {
+			return fmt.Errorf("Error updating the server URLs in '%s': %v", path, err)
+		}
+		if len(clusters) > 0 {
+			fmt.Println(fmt.Sprintf("-- Updated the server URL of %d cluster(s) in '%s'", len(clusters), path))
+		}
+	}
+
+	fmt.Println("-- Regenerating the Docker server certificate")
+	dockerCommander := docker.NewVmDockerCommander(provision.GenericSSHCommander{Driver: hostVm.Driver})
+	restartOpenShift, err := RegenerateDockerCerts(api, hostVm, dockerCommander)
+	if err != nil {
+		return fmt.Errorf("Error regenerating the Docker server certificate: %v", err)
+	}
+
+	if restartOpenShift {
+		if _, err := minishiftTLS.EnsureServingCertSANs([]string{newIP}, dockerCommander); err != nil {
+			return fmt.Errorf("Error adding %s to the API serving certificates: %v", newIP, err)
+		}
+
+		fmt.Println("-- Updating the public URLs of OpenShift")
+		if err := openshift.UpdatePublicIP(oldIP, newIP, dockerCommander); err != nil {
+			return fmt.Errorf("Error updating the public URLs of the master configuration: %v", err)
+		}
+		if ocRunner, err := oc.NewOcRunner(minishiftConfig.InstanceStateConfig.OcPath, constants.KubeConfigPath); err == nil {
+			if err := openshift.UpdateWebConsolePublicIP(oldIP, newIP, ocRunner); err != nil {
+				return fmt.Errorf("Error updating the public URLs of the web console configuration: %v", err)
+			}
+		}
+	}
+
+	if dns.Status(hostVm.Driver) {
+		fmt.Println("-- Reconfiguring the DNS server")
+		if _, err := dns.Reconfigure(hostVm.Driver); err != nil {
+			return fmt.Errorf("Error reconfiguring the DNS server: %v", err)
+		}
+	}
+
+	if restartOpenShift {
+		fmt.Println("-- Restarting OpenShift")
+		if _, err := openshift.RestartOpenShift(dockerCommander); err != nil {
+			return fmt.Errorf("Error restarting OpenShift: %v", err)
+		}
+	}
+
+	return recordLastKnownIP(newIP)
+}
+
+// RecordLastKnownIP records the IP of the VM, so that a later change of the IP can be detected.
+func RecordLastKnownIP(ip string) {
+	if err := recordLastKnownIP(ip); err != nil {
+		fmt.Println(fmt.Sprintf("Error recording the IP of the VM: %v", err))
+	}
+}
+
+// recordLastKnownIP re-reads the instance state before writing it, since the IP watcher runs alongside other
+// commands of the same profile.
+func recordLastKnownIP(ip string) error {
+	stateConfig, err := minishiftConfig.NewInstanceStateConfig(minishiftConfig.InstanceStateConfig.FilePath)
+	if err != nil {
+		return err
+	}
+	if stateConfig.LastKnownIP == ip {
+		return nil
+	}
+
+	stateConfig.LastKnownIP = ip
+	if err := stateConfig.Write(); err != nil {
+		return err
+	}
+	minishiftConfig.InstanceStateConfig = stateConfig
+	return nil
+}
+
+func kubeConfigPaths() []string {
+	paths := []string{constants.KubeConfigPath}
+	if globalPath, err := oc.GetGlobalKubeConfigPath(); err == nil {
+		paths = append(paths, globalPath)
+	}
+	return paths
+}
@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cache"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/kubeconfig"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// RememberKubeContext records the current context of the global kubeconfig, so that it can be restored once the
+// cluster is stopped or deleted. Contexts managed by Minishift are not recorded.
+func RememberKubeContext() {
+	kubeConfigPath, err := oc.GetGlobalKubeConfigPath()
+	if err != nil {
+		return
+	}
+
+	current, err := kubeconfig.CurrentContext(kubeConfigPath)
+	if err != nil {
+		logger.Debugf("Error reading the current context of '%s': %v", kubeConfigPath, err)
+		return
+	}
+
+	if current == "" || kubeconfig.IsMinishiftContext(current, constants.ProfileName) || current == minishiftConfig.InstanceStateConfig.PreviousKubeContext {
+		return
+	}
+
+	minishiftConfig.InstanceStateConfig.PreviousKubeContext = current
+	if err := minishiftConfig.InstanceStateConfig.Write(); err != nil {
+		logger.Debugf("Error recording the current context: %v", err)
+	}
+}
+
+// RestoreKubeContext switches the global kubeconfig back to the context which was current before the cluster was started.
+func RestoreKubeContext() {
+	kubeConfigPath, err := oc.GetGlobalKubeConfigPath()
+	if err != nil {
+		return
+	}
+
+	previous := minishiftConfig.InstanceStateConfig.PreviousKubeContext
+	restored, err := kubeconfig.RestoreContext(kubeConfigPath, constants.ProfileName, previous)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Unable to restore the previous context in '%s': %v", kubeConfigPath, err))
+		return
+	}
+
+	if restored && previous != "" {
+		fmt.Println(fmt.Sprintf("Switched the current context back to '%s'", previous))
+	}
+}
+
+// RecordKubeConfigEntries records the entries of the user's kubeconfig which were added for the cluster of the active
+// profile, so that they can be removed once the profile is deleted without touching the entries of other profiles.
+// Entries which differ from the profile's own kubeconfig were kept when merging and are not recorded.
+func RecordKubeConfigEntries(ip string) {
+	if minishiftConfig.AllInstancesConfig == nil {
+		return
+	}
+
+	kubeConfigPath, err := oc.GetGlobalKubeConfigPath()
+	if err != nil {
+		return
+	}
+	kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+	if err != nil {
+		logger.Debugf("Error reading '%s': %v", kubeConfigPath, err)
+		return
+	}
+	profileKubeConfig, err := clientcmd.LoadFromFile(constants.KubeConfigPath)
+	if err != nil {
+		logger.Debugf("Error reading '%s': %v", constants.KubeConfigPath, err)
+		return
+	}
+	conflicts := kubeconfig.ConflictingEntryKeys(kubeConfig, profileKubeConfig)
+
+	if minishiftConfig.AllInstancesConfig.KubeConfigReferences == nil {
+		minishiftConfig.AllInstancesConfig.KubeConfigReferences = cache.References{}
+	}
+
+	changed := false
+	for _, key := range kubeconfig.ProfileEntryKeys(kubeConfig, kubeconfig.ClusterName(ip, constants.APIServerPort), constants.ProfileName) {
+		if minishiftStrings.Contains(conflicts, key) {
+			continue
+		}
+		if minishiftConfig.AllInstancesConfig.KubeConfigReferences.Add(key, constants.ProfileName) {
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := minishiftConfig.AllInstancesConfig.Write(); err != nil {
+			logger.Debugf("Error recording kubeconfig references: %v", err)
+		}
+	}
+}
+
+// RemoveKubeConfigEntries removes the entries recorded for the specified profile from the user's kubeconfig.
+// Entries which are still used by another profile are kept. Returns false if no entries were recorded for the profile.
+func RemoveKubeConfigEntries(profile string) (bool, error) {
+	if minishiftConfig.AllInstancesConfig == nil || minishiftConfig.AllInstancesConfig.KubeConfigReferences == nil {
+		return false, nil
+	}
+
+	recorded := false
+	for _, profiles := range minishiftConfig.AllInstancesConfig.KubeConfigReferences {
+		for _, p := range profiles {
+			if p == profile {
+				recorded = true
+			}
+		}
+	}
+	if !recorded {
+		return false, nil
+	}
+
+	orphaned := minishiftConfig.AllInstancesConfig.KubeConfigReferences.RemoveProfile(profile)
+	if err := minishiftConfig.AllInstancesConfig.Write(); err != nil {
+		return true, err
+	}
+
+	kubeConfigPath, err := oc.GetGlobalKubeConfigPath()
+	if err != nil {
+		return true, err
+	}
+	kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+	if err != nil {
+		return true, err
+	}
+
+	fmt.Println(fmt.Sprintf("Removing entries from kubeconfig for profile: %s", profile))
+	kubeconfig.RemoveEntries(kubeConfig, orphaned)
+	return true, clientcmd.WriteToFile(*kubeConfig, kubeConfigPath)
+}
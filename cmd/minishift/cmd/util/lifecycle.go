@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+)
+
+// SetLifecycleState records the lifecycle state of the instance. Failing to persist the state is not fatal, since
+// it only affects the diagnostics of status and the recovery of start.
+func SetLifecycleState(state minishiftConfig.LifecycleState) {
+	if err := minishiftConfig.InstanceStateConfig.SetLifecycleState(state); err != nil {
+		logger.Warnf("Error recording the lifecycle state '%s': %v", state, err)
+	}
+}
+
+// RecordLifecycleErrors registers an exit handler which records the failure of the current operation together with
+// the lifecycle phase it failed in.
+func RecordLifecycleErrors() {
+	atexit.RegisterExitHandler(func(code int) bool {
+		if code == 0 || !minishiftConfig.InstanceStateConfig.Lifecycle.State.IsTransient() {
+			return false
+		}
+		if err := minishiftConfig.InstanceStateConfig.SetLifecycleError(atexit.LastMessage()); err != nil {
+			logger.Warnf("Error recording the lifecycle failure: %v", err)
+		}
+		return false
+	})
+}
+
+// DescribeLifecycle explains where the last operation on the instance stopped if it crashed, got interrupted or
+// failed, or names the phase of an operation still in progress. An empty string is returned if the last operation
+// completed.
+func DescribeLifecycle(lifecycle minishiftConfig.Lifecycle) string {
+	if !lifecycle.IsIncomplete() {
+		return ""
+	}
+
+	since := units.HumanDuration(time.Since(lifecycle.Updated))
+	if lifecycle.State == minishiftConfig.Error {
+		return fmt.Sprintf("Failed during '%s' %s ago (%s)", lifecycle.Phase, since, strings.TrimSpace(lifecycle.Message))
+	}
+	if IsProfileLocked(constants.ProfileName) {
+		return fmt.Sprintf("In progress ('%s' for %s)", lifecycle.State, since)
+	}
+	return fmt.Sprintf("Interrupted during '%s' %s ago", lifecycle.State, since)
+}
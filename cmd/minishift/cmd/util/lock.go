@@ -0,0 +1,73 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/minishift/minishift/pkg/util/os/lock"
+)
+
+// profileLocks keeps the acquired profile locks referenced until the process exits, which releases them
+var profileLocks = make(map[string]*lock.FileLock)
+
+// ProfileLockPath returns the path of the lock file guarding operations on the specified profile
+func ProfileLockPath(profile string) string {
+	return filepath.Join(constants.GetMinishiftHomeDir(), "locks", profile+".lock")
+}
+
+// LockProfilesOrExit acquires the locks of the specified profiles for the remainder of the process. If another
+// Minishift process operates on one of the profiles, the command exits with an error instead of waiting.
+func LockProfilesOrExit(profiles ...string) {
+	for _, profile := range profiles {
+		if _, ok := profileLocks[profile]; ok {
+			continue
+		}
+		profileLock, err := lock.TryAcquire(ProfileLockPath(profile))
+		if err == lock.ErrLocked {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Another Minishift process is operating on profile '%s'. Wait for it to finish and try again.", profile))
+		}
+		if err != nil {
+			atexit.ExitWithMessage(1, fmt.Sprintf("Error locking profile '%s': %v", profile, err))
+		}
+		profileLocks[profile] = profileLock
+	}
+}
+
+// IsProfileLocked returns true if a Minishift process, including the current one, is operating on the specified profile.
+func IsProfileLocked(profile string) bool {
+	if _, ok := profileLocks[profile]; ok {
+		return true
+	}
+	profileLock, err := lock.TryAcquire(ProfileLockPath(profile))
+	if err != nil {
+		return err == lock.ErrLocked
+	}
+	profileLock.Release()
+	return false
+}
+
+// ReleaseProfileLocks releases the profile locks acquired by LockProfilesOrExit before the process exits.
+func ReleaseProfileLocks() {
+	for profile, profileLock := range profileLocks {
+		profileLock.Release()
+		delete(profileLocks, profile)
+	}
+}
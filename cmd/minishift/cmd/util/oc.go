@@ -26,25 +26,32 @@ import (
 	"path/filepath"
 
 	"github.com/docker/machine/libmachine"
-	"github.com/golang/glog"
+	"github.com/docker/machine/libmachine/provision"
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
 	"github.com/minishift/minishift/cmd/minishift/state"
 	cmdState "github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/cluster"
 	"github.com/minishift/minishift/pkg/minikube/constants"
 	"github.com/minishift/minishift/pkg/minishift/cache"
 	"github.com/minishift/minishift/pkg/minishift/clusterup"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
 	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/kubeconfig"
 	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+	openshiftVersion "github.com/minishift/minishift/pkg/minishift/openshift/version"
 	utils "github.com/minishift/minishift/pkg/util"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/minishift/minishift/pkg/util/progressdots"
+	"github.com/spf13/viper"
 )
 
-// CacheOc ensures that the oc binary matching the requested OpenShift version is cached on the host
+// CacheOc ensures that the oc binary for the requested OpenShift version is cached on the host.
+// The oc version matches the OpenShift version, unless a version is selected for the profile via 'minishift oc use'.
 func CacheOc(openShiftVersion string) string {
 	ocBinary := cache.Oc{
-		OpenShiftVersion:  openShiftVersion,
+		OpenShiftVersion:  SelectedOcVersion(openShiftVersion),
 		MinishiftCacheDir: state.InstanceDirs.Cache,
 	}
 	if err := ocBinary.EnsureIsCached(); err != nil {
@@ -52,7 +59,12 @@ func CacheOc(openShiftVersion string) string {
 	}
 
 	// Update MACHINE_NAME.json for oc path
-	minishiftConfig.InstanceStateConfig.OcPath = filepath.Join(ocBinary.GetCacheFilepath(), constants.OC_BINARY_NAME)
+	ocPath := filepath.Join(ocBinary.GetCacheFilepath(), constants.OC_BINARY_NAME)
+	if previousOcPath := minishiftConfig.InstanceStateConfig.OcPath; previousOcPath != "" && previousOcPath != ocPath {
+		ReleaseCacheReference(cache.OcArtifact, filepath.Dir(previousOcPath))
+	}
+	minishiftConfig.InstanceStateConfig.OcPath = ocPath
+	RecordCacheReferences(cache.OcArtifact, ocBinary.GetCacheFilepath())
 	minishiftConfig.InstanceStateConfig.OpenshiftVersion = openShiftVersion
 	if err := minishiftConfig.InstanceStateConfig.Write(); err != nil {
 		atexit.ExitWithMessage(1, fmt.Sprintf("Error updating oc path in config of VM: %v", err))
@@ -61,6 +73,20 @@ func CacheOc(openShiftVersion string) string {
 	return minishiftConfig.InstanceStateConfig.OcPath
 }
 
+// SelectedOcVersion returns the oc version selected for the active profile or the OpenShift version of the cluster
+// if none is selected. A warning is printed if the selected version does not match the minor version of the cluster.
+func SelectedOcVersion(openShiftVersion string) string {
+	ocVersion := viper.GetString(configCmd.OcVersion.Name)
+	if ocVersion == "" {
+		return openShiftVersion
+	}
+
+	if sameVersion, err := openshiftVersion.HaveSameMinorVersion(ocVersion, openShiftVersion); err == nil && !sameVersion {
+		fmt.Println(fmt.Sprintf("-- Using oc version '%s' with OpenShift version '%s'. Some commands might not work as expected.", ocVersion, openShiftVersion))
+	}
+	return ocVersion
+}
+
 func SetOcContext(profileName string) error {
 	// Need to create the kube config path for the profile for ocrunner to use it.
 	kubeConfigPath := filepath.Join(constants.Minipath, "machines", profileName+"_kubeconfig")
@@ -86,9 +112,7 @@ func SetOcContext(profileName string) error {
 
 	err, ocPath := getOcPathForProfile()
 	if err != nil {
-		if glog.V(2) {
-			fmt.Println(fmt.Sprintf("%s", err.Error()))
-		}
+		logger.Debugf("%s", err.Error())
 		return errors.New(fmt.Sprintf("Error getting the oc path for profile '%s'", profileName))
 	}
 
@@ -96,7 +120,7 @@ func SetOcContext(profileName string) error {
 	if err != nil {
 		return errors.New(fmt.Sprintf("Error during setting '%s' as active profile: %s", profileName, err.Error()))
 	}
-	err = ocRunner.AddCliContext(constants.MachineName, ip, minishiftConstants.DefaultUser, minishiftConstants.DefaultProject, &utils.RealRunner{}, ocPath)
+	err = ocRunner.AddCliContext(kubeconfig.ContextName(profileName), ip, minishiftConstants.DefaultUser, minishiftConstants.DefaultProject, &utils.RealRunner{}, ocPath)
 	if err != nil {
 		return errors.New(fmt.Sprintf("Error during setting '%s' as active profile: %s", profileName, err.Error()))
 	}
@@ -112,9 +136,7 @@ func RemoveCurrentContext() error {
 
 	ocRunner, err := oc.NewOcRunner(ocPath, filepath.Join(constants.Minipath, "machines", constants.MachineName+"_kubeconfig"))
 	if err != nil {
-		if glog.V(2) {
-			fmt.Println(fmt.Sprintf("%s", err.Error()))
-		}
+		logger.Debugf("%s", err.Error())
 		return errors.New("Error unsetting current-context")
 	}
 
@@ -162,9 +184,7 @@ func PullOpenshiftImageAndCopyOcBinary(dockerCommander docker.DockerCommander, r
 		progressDots.Stop()
 		fmt.Println(" OK")
 	} else {
-		if glog.V(2) {
-			fmt.Printf("-- OpenShift container image exists ...")
-		}
+		logger.Debugf("-- OpenShift container image exists ...")
 	}
 	fmt.Printf("-- Copying oc binary from the OpenShift container image to VM ...")
 	err = clusterup.CopyOcBinaryFromImageToVM(dockerCommander, minishiftConstants.GetOpenshiftImageToFetchOC(requestedOpenShiftVersion), minishiftConstants.OcPathInsideVM)
@@ -174,3 +194,48 @@ func PullOpenshiftImageAndCopyOcBinary(dockerCommander docker.DockerCommander, r
 	fmt.Println(" OK")
 	return nil
 }
+
+// RunningCluster returns an SSH commander and an oc runner for the running Minishift VM. The command exits if the VM
+// does not exist or is not running.
+func RunningCluster() (provision.SSHCommander, *oc.OcRunner) {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	ExitIfUndefined(api, constants.MachineName)
+
+	host, err := api.Load(constants.MachineName)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+
+	ExitIfNotRunning(host.Driver, constants.MachineName)
+
+	ocRunner, err := oc.NewOcRunner(minishiftConfig.InstanceStateConfig.OcPath, constants.KubeConfigPath)
+	if err != nil {
+		atexit.ExitWithMessage(1, err.Error())
+	}
+	return provision.GenericSSHCommander{Driver: host.Driver}, ocRunner
+}
+
+// RunningOpenShiftCluster returns a Docker commander and an oc runner for the running OpenShift cluster. Unlike
+// RunningCluster it does not exit, false is returned instead if there is no running cluster.
+func RunningOpenShiftCluster() (docker.DockerCommander, *oc.OcRunner, bool, error) {
+	api := libmachine.NewClient(state.InstanceDirs.Home, state.InstanceDirs.Certs)
+	defer api.Close()
+
+	host, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return nil, nil, false, nil
+	}
+
+	dockerCommander := docker.NewVmDockerCommander(provision.GenericSSHCommander{Driver: host.Driver})
+	if !openshift.IsRunning(dockerCommander) {
+		return nil, nil, false, nil
+	}
+
+	ocRunner, err := oc.NewOcRunner(minishiftConfig.InstanceStateConfig.OcPath, constants.KubeConfigPath)
+	if err != nil {
+		return nil, nil, true, err
+	}
+	return dockerCommander, ocRunner, true, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	cmdState "github.com/minishift/minishift/cmd/minishift/state"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	localAPI "github.com/minishift/minishift/pkg/minishift/api"
+	"github.com/minishift/minishift/pkg/minishift/diskusage"
+	profileActions "github.com/minishift/minishift/pkg/minishift/profile"
+)
+
+// GetProfileSummary returns the driver, the VM status, the IP address, the OpenShift version, the allocated resources
+// and the disk usage of the VM of the specified profile. Information which cannot be determined is left empty.
+func GetProfileSummary(profileName string, active bool) localAPI.Profile {
+	summary := localAPI.Profile{Name: profileName, Active: active, VMStatus: "Does Not Exist", Protected: profileActions.IsProtected(profileName),
+		Labels: profileActions.GetLabels(profileName)}
+
+	profileHome := constants.GetProfileHomeDir(profileName)
+	machineDir := filepath.Join(profileHome, "machines", profileName)
+	if size, err := diskusage.DirSize(machineDir); err == nil {
+		summary.DiskUsageBytes = size
+	}
+	summary.OpenShiftVersion = recordedOpenShiftVersion(filepath.Join(profileHome, "machines", profileName+"-state.json"))
+
+	profileDirs := cmdState.GetMinishiftDirsStructure(profileHome)
+	api := libmachine.NewClient(profileDirs.Home, profileDirs.Certs)
+	defer api.Close()
+
+	if exists, err := api.Exists(profileName); err != nil || !exists {
+		return summary
+	}
+	host, err := api.Load(profileName)
+	if err != nil {
+		summary.VMStatus = fmt.Sprintf("Error getting the VM status: %s", err.Error())
+		return summary
+	}
+	summary.Driver = host.DriverName
+	summary.MemoryMB, summary.CPUs, _ = profileActions.MachineResources(filepath.Join(machineDir, "config.json"))
+
+	vmState, err := host.Driver.GetState()
+	if err != nil {
+		summary.VMStatus = fmt.Sprintf("Error getting the VM status: %s", err.Error())
+		return summary
+	}
+	if vmState.String() == "" {
+		return summary
+	}
+	summary.VMStatus = vmState.String()
+	if vmState == state.Running {
+		summary.IP, _ = host.Driver.GetIP()
+	}
+	return summary
+}
+
+// recordedOpenShiftVersion returns the OpenShift version recorded in the instance state of a profile by the last start
+func recordedOpenShiftVersion(instanceStateFile string) string {
+	raw, err := ioutil.ReadFile(instanceStateFile)
+	if err != nil {
+		return ""
+	}
+	var instanceState struct {
+		OpenshiftVersion string
+	}
+	if err := json.Unmarshal(raw, &instanceState); err != nil {
+		return ""
+	}
+	return instanceState.OpenshiftVersion
+}
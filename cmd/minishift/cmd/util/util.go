@@ -55,6 +55,12 @@ var (
 		"htpasswd-identity-provider",
 		"admissions-webhook",
 		"redhat-registry-login",
+		"hostpath-provisioner",
+		"monitoring",
+		"logging",
+		"service-mesh",
+		"olm",
+		"jenkins",
 	}
 )
 
@@ -88,6 +94,14 @@ func ExitIfNotRunning(driver drivers.Driver, machineName string) {
 	}
 }
 
+// ExitIfProtected exits unless force is set, if the profile was protected with 'minishift profile lock'.
+func ExitIfProtected(profileName string, force bool) {
+	if force || !profileActions.IsProtected(profileName) {
+		return
+	}
+	atexit.ExitWithMessage(1, fmt.Sprintf("Profile '%s' is protected. Use --force to run this command or 'minishift profile unlock %s' to remove the protection.", profileName, profileName))
+}
+
 func GetVMStatus(profileName string) string {
 	var status string
 	profileDirs := cmdState.GetMinishiftDirsStructure(constants.GetProfileHomeDir(profileName))
@@ -177,6 +191,19 @@ func OcClusterDown(hostVm *host.Host) error {
 	return err
 }
 
+// InvalidVersionError is returned by GetOpenShiftReleaseVersion if the requested OpenShift version is not a valid
+// version, as opposed to an error determining the latest release.
+type InvalidVersionError struct {
+	Version string
+	Err     error
+}
+
+func (e *InvalidVersionError) Error() string {
+	return e.Err.Error()
+}
+
+// GetOpenShiftReleaseVersion returns the requested OpenShift version, resolving 'latest' to the latest release.
+// If the version is not valid, the version is returned together with an InvalidVersionError.
 func GetOpenShiftReleaseVersion() (string, error) {
 	tag := viper.GetString(configCmd.OpenshiftVersion.Name)
 	// tag is in the form of vMajor.minor.patch e.g v3.9.0
@@ -194,6 +221,9 @@ func GetOpenShiftReleaseVersion() (string, error) {
 		return sortedtags[len(sortedtags)-1], nil
 	}
 
+	if _, err := openshiftVersion.IsGreaterOrEqualToBaseVersion(tag, constants.MinimumSupportedOpenShiftVersion); err != nil {
+		return tag, &InvalidVersionError{Version: tag, Err: err}
+	}
 	return tag, nil
 }
 
@@ -19,6 +19,8 @@ package util
 import (
 	"testing"
 
+	configCmd "github.com/minishift/minishift/cmd/minishift/cmd/config"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -48,3 +50,17 @@ func TestIsValidProfileName(t *testing.T) {
 		assert.Equal(t, v.expected, got)
 	}
 }
+
+func TestGetOpenShiftReleaseVersion(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set(configCmd.OpenshiftVersion.Name, "v3.11.0")
+	version, err := GetOpenShiftReleaseVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "v3.11.0", version)
+
+	viper.Set(configCmd.OpenshiftVersion.Name, "vfoo")
+	version, err = GetOpenShiftReleaseVersion()
+	assert.IsType(t, &InvalidVersionError{}, err, "An invalid version should not be reported as failure to determine the release")
+	assert.Equal(t, "vfoo", version)
+}
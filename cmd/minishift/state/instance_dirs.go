@@ -23,18 +23,21 @@ import (
 )
 
 type MinishiftDirs struct {
-	Home         string
-	Config       string
-	GlobalConfig string
-	Machines     string
-	Certs        string
-	Cache        string
-	IsoCache     string
-	OcCache      string
-	ImageCache   string
-	Addons       string
-	Logs         string
-	Tmp          string
+	Home          string
+	Config        string
+	GlobalConfig  string
+	Machines      string
+	Certs         string
+	Cache         string
+	IsoCache      string
+	OcCache       string
+	ImageCache    string
+	RegistryCache string
+	AddonRepos    string
+	Addons        string
+	Data          string
+	Logs          string
+	Tmp           string
 }
 
 var InstanceDirs *MinishiftDirs
@@ -42,21 +45,42 @@ var InstanceDirs *MinishiftDirs
 // Constructor for MinishiftDirs
 func GetMinishiftDirsStructure(baseDir string) *MinishiftDirs {
 	minishiftHomeDir := constants.GetMinishiftHomeDir()
-	// We use a global cache, sharing the cache directory of the default 'minishift' instance
-	cacheDir := filepath.Join(minishiftHomeDir, "cache")
 
-	return &MinishiftDirs{
+	dirs := &MinishiftDirs{
 		Home:         baseDir,
 		Certs:        filepath.Join(baseDir, "certs"),
 		Machines:     filepath.Join(baseDir, "machines"),
 		Addons:       filepath.Join(baseDir, "addons"),
+		Data:         filepath.Join(baseDir, "data"),
 		Logs:         filepath.Join(baseDir, "logs"),
 		Tmp:          filepath.Join(baseDir, "tmp"),
 		Config:       filepath.Join(baseDir, "config"),
 		GlobalConfig: filepath.Join(minishiftHomeDir, "config"),
-		Cache:        cacheDir,
-		IsoCache:     filepath.Join(cacheDir, "iso"),
-		OcCache:      filepath.Join(cacheDir, "oc"),
-		ImageCache:   filepath.Join(cacheDir, "images"),
 	}
+	// We use a global cache, sharing the cache directory of the default 'minishift' instance
+	dirs.setCacheDir(sharedCacheDir())
+	return dirs
+}
+
+// IsolateCache makes the profile use a cache directory of its own instead of the cache shared by all profiles
+func (dirs *MinishiftDirs) IsolateCache() {
+	dirs.setCacheDir(filepath.Join(dirs.Home, "cache"))
+}
+
+// IsCacheIsolated returns true if the profile does not use the cache shared by all profiles
+func (dirs *MinishiftDirs) IsCacheIsolated() bool {
+	return dirs.Cache != sharedCacheDir()
+}
+
+func (dirs *MinishiftDirs) setCacheDir(cacheDir string) {
+	dirs.Cache = cacheDir
+	dirs.IsoCache = filepath.Join(cacheDir, "iso")
+	dirs.OcCache = filepath.Join(cacheDir, "oc")
+	dirs.ImageCache = filepath.Join(cacheDir, "images")
+	dirs.RegistryCache = filepath.Join(cacheDir, "registry")
+	dirs.AddonRepos = filepath.Join(cacheDir, "addon-repos")
+}
+
+func sharedCacheDir() string {
+	return filepath.Join(constants.GetMinishiftHomeDir(), "cache")
 }
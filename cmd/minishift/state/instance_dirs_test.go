@@ -0,0 +1,42 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsolateCache(t *testing.T) {
+	profileDir := constants.GetProfileHomeDir("ci-job")
+	dirs := GetMinishiftDirsStructure(profileDir)
+	assert.False(t, dirs.IsCacheIsolated())
+	assert.Equal(t, filepath.Join(constants.GetMinishiftHomeDir(), "cache", "iso"), dirs.IsoCache)
+
+	dirs.IsolateCache()
+	assert.True(t, dirs.IsCacheIsolated())
+	assert.Equal(t, filepath.Join(profileDir, "cache"), dirs.Cache)
+	assert.Equal(t, filepath.Join(profileDir, "cache", "images"), dirs.ImageCache)
+	assert.Equal(t, filepath.Join(profileDir, "cache", "addon-repos"), dirs.AddonRepos)
+
+	dirs = GetMinishiftDirsStructure(constants.GetMinishiftHomeDir())
+	dirs.IsolateCache()
+	assert.False(t, dirs.IsCacheIsolated(), "The default profile always uses the shared cache")
+}
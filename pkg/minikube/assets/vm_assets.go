@@ -17,8 +17,11 @@ limitations under the License.
 package assets
 
 import (
+	"bytes"
 	"io"
 	"os"
+	"strings"
+	"text/template"
 
 	"github.com/pkg/errors"
 )
@@ -103,6 +106,19 @@ type MemoryAsset struct {
 	BaseAsset
 }
 
+func NewMemoryAsset(data []byte, targetDir, targetName, permissions string) *MemoryAsset {
+	return &MemoryAsset{
+		BaseAsset{
+			data:        data,
+			reader:      bytes.NewReader(data),
+			Length:      int64(len(data)),
+			TargetDir:   targetDir,
+			TargetName:  targetName,
+			Permissions: permissions,
+		},
+	}
+}
+
 func (m *MemoryAsset) GetLength() int64 {
 	return m.Length
 }
@@ -110,3 +126,27 @@ func (m *MemoryAsset) GetLength() int64 {
 func (m *MemoryAsset) Read(p []byte) (int, error) {
 	return m.reader.Read(p)
 }
+
+// TemplateAsset is a MemoryAsset whose content is rendered from a text/template
+type TemplateAsset struct {
+	MemoryAsset
+}
+
+// NewTemplateAsset renders the template with the data and returns the result as asset
+func NewTemplateAsset(tmpl string, data interface{}, targetDir, targetName, permissions string) (*TemplateAsset, error) {
+	t, err := template.New(targetName).Funcs(template.FuncMap{"join": strings.Join}).Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the template of %s", targetName)
+	}
+
+	var content bytes.Buffer
+	if err := t.Execute(&content, data); err != nil {
+		return nil, errors.Wrapf(err, "Error rendering the template of %s", targetName)
+	}
+	return &TemplateAsset{*NewMemoryAsset(content.Bytes(), targetDir, targetName, permissions)}, nil
+}
+
+// Content returns the rendered content
+func (t *TemplateAsset) Content() []byte {
+	return t.data
+}
@@ -17,33 +17,34 @@ limitations under the License.
 package cluster
 
 import (
-	"bytes"
-	"crypto/sha256"
 	"flag"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"encoding/hex"
 	"encoding/json"
 
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/engine"
 	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/provision"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	minishiftISO "github.com/minishift/minishift/pkg/minishift/iso"
 	minishiftNetwork "github.com/minishift/minishift/pkg/minishift/network"
 	minishiftUtil "github.com/minishift/minishift/pkg/minishift/util"
 	"github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/download"
 	"github.com/minishift/minishift/pkg/util/os/atexit"
 	"github.com/pkg/errors"
-	pb "gopkg.in/cheggaaa/pb.v1"
+	"golang.org/x/crypto/ssh"
 )
 
 var (
@@ -100,6 +101,14 @@ func StartHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 		}
 	}
 
+	// the root file system of the ISO is not persistent, the selected Docker version needs to be installed again
+	if _, err := config.DockerEngine.Install(provision.GenericSSHCommander{Driver: h.Driver}); err != nil {
+		return nil, fmt.Errorf("Error installing the Docker engine: %s", err)
+	}
+	// the drop-in is picked up when configuring the authorization restarts the Docker daemon
+	if _, err := docker.WriteDropIn(h.Driver, &config.DockerDaemon); err != nil {
+		return nil, fmt.Errorf("Error configuring the Docker daemon: %s", err)
+	}
 	if err := h.ConfigureAuth(); err != nil {
 		return nil, fmt.Errorf("Error configuring authorization on host: %s", err)
 	}
@@ -164,16 +173,15 @@ type sshAble interface {
 type MachineConfig struct {
 	MinikubeISO           string
 	ISOCacheDir           string
+	ISOSigningKey         string // armored public key the published ISO checksums must be signed with, if set
 	Memory                int
 	CPUs                  int
 	DiskSize              int
 	VMDriver              string
-	DockerEnv             []string // Each entry is formatted as KEY=VALUE.
-	DockerEngineOpt       []string
-	InsecureRegistry      []string
-	RegistryMirror        []string
-	HostOnlyCIDR          string           // Only used by the virtualbox driver
-	ShellProxyEnv         util.ProxyConfig // Only used for proxy purpose
+	DockerDaemon          docker.DaemonConfig // rendered into the drop-in of the Docker service on every start
+	DockerEngine          docker.Engine       // installed by the provisioner on create and on every restart
+	HostOnlyCIDR          string              // Only used by the virtualbox driver
+	ShellProxyEnv         util.ProxyConfig    // Only used for proxy purpose
 	HypervVirtualSwitch   string
 	RemoteIPAddress       string // Only used for generic driver purpose to connect remote machine
 	RemoteSSHUser         string // Only used for generic driver purpose to specify ssh user
@@ -181,111 +189,96 @@ type MachineConfig struct {
 	UsingLocalProxy       bool
 }
 
-func engineOptions(config MachineConfig) *engine.Options {
-	o := engine.Options{
-		Env:              config.DockerEnv,
-		ArbitraryFlags:   config.DockerEngineOpt,
-		InsecureRegistry: config.InsecureRegistry,
-		RegistryMirror:   config.RegistryMirror,
-	}
-	return &o
-}
-
-// CacheMinikubeISOFromURL download minishift ISO from a given URI.
-// It also checks sha256sum if present and then put ISO to cached directory.
+// CacheMinikubeISOFromURL download minishift ISO from a given URI, falling back to the configured download mirrors and
+// resuming an interrupted download. It also checks the published sha256sum if present and then put ISO to cached
+// directory, recording its checksum for the verification of the cached ISO.
 func (m *MachineConfig) CacheMinikubeISOFromURL() error {
 	fmt.Println(fmt.Sprintf("\n   Downloading ISO '%s'", m.MinikubeISO))
-	response, err := http.Get(m.MinikubeISO)
+	err := os.MkdirAll(filepath.Join(m.ISOCacheDir, minishiftUtil.GetIsoPath(m.MinikubeISO)), os.ModePerm)
 	if err != nil {
 		return err
 	}
-	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("Received %d response from %s while trying to download the ISO", response.StatusCode, m.MinikubeISO)
-	}
-
-	err = os.MkdirAll(filepath.Join(m.ISOCacheDir, minishiftUtil.GetIsoPath(m.MinikubeISO)), os.ModePerm)
-	if err != nil {
-		return err
-	}
-	tmpISOFile, err := os.Create(m.GetISOCacheFilepath() + ".part")
-	if err != nil {
-		return err
+	// the ISO is only moved to its cache location once it is verified
+	unverifiedISO := m.GetISOCacheFilepath() + ".unverified"
+	hash := ""
+	if seed := m.findSeedISO(); seed != "" {
+		location, err := download.DeltaFromMirrors(m.MinikubeISO, seed, unverifiedISO)
+		if err == nil {
+			hash, err = m.verifyDownloadedISO(unverifiedISO, location)
+		}
+		if err != nil {
+			glog.Infof("Delta download of '%s' against '%s' failed, downloading the full ISO: %v", m.MinikubeISO, seed, err)
+		}
 	}
-	defer tmpISOFile.Close()
-
-	var iso io.Reader
-	iso = response.Body
-	hasher := sha256.New()
-	iso = io.TeeReader(iso, hasher)
-
-	if response.ContentLength > 0 {
-		bar := pb.New64(response.ContentLength).SetUnits(pb.U_BYTES)
-		bar.Start()
-		iso = bar.NewProxyReader(iso)
-		defer func() {
-			<-time.After(bar.RefreshRate)
-			fmt.Println()
-		}()
+	if hash == "" {
+		location, err := download.FromMirrors(m.MinikubeISO, unverifiedISO)
+		if err != nil {
+			return err
+		}
+		if hash, err = m.verifyDownloadedISO(unverifiedISO, location); err != nil {
+			return err
+		}
 	}
 
-	if _, err = io.Copy(tmpISOFile, iso); err != nil {
+	if err := os.Rename(unverifiedISO, m.GetISOCacheFilepath()); err != nil {
 		return err
 	}
 
-	if err := tmpISOFile.Sync(); err != nil {
-		return err
-	}
+	return minishiftISO.RecordChecksum(m.GetISOCacheFilepath(), hash)
+}
 
-	checkSum := m.getChecksum(m.MinikubeISO)
-	if checkSum != "" {
-		hash := hex.EncodeToString(hasher.Sum(nil))
-		if hash != checkSum {
-			return errors.New(fmt.Sprintf("Updated file has wrong checksum. Expected: %s, got: %s", hash, checkSum))
-		}
+// verifyDownloadedISO checks the ISO downloaded from location against the published sha256sum, if present, and returns
+// its checksum. An ISO with wrong checksum is removed. Unless a signing key is configured, failing to fetch the
+// published checksum is not fatal, since not every mirror publishes checksums.
+func (m *MachineConfig) verifyDownloadedISO(path string, location string) (string, error) {
+	hash, err := minishiftISO.Sha256Sum(path)
+	if err != nil {
+		return "", err
+	}
+	checkSum, err := minishiftISO.PublishedChecksum(location, m.ISOSigningKey)
+	if err != nil && m.ISOSigningKey != "" {
+		os.Remove(path)
+		return "", err
 	}
-
-	out, err := os.Create(m.GetISOCacheFilepath())
 	if err != nil {
-		return err
+		fmt.Println(fmt.Sprintf("   WARN: Unable to fetch the checksum of '%s': %v", location, err))
 	}
-	defer out.Close()
-
-	if err = renameFile(tmpISOFile, out); err != nil {
-		return nil
+	if checkSum != "" && hash != checkSum {
+		os.Remove(path)
+		return "", errors.New(fmt.Sprintf("Downloaded ISO has wrong checksum. Expected: %s, got: %s", checkSum, hash))
 	}
-
-	return nil
+	return hash, nil
 }
 
-func renameFile(oldFile, newFile *os.File) error {
-	// File descriptor need to be closed otherwise it will throw error
-	// for Windows https://github.com/minishift/minishift/issues/1186
-	oldFile.Close()
-	newFile.Close()
-	if err := os.Rename(oldFile.Name(), newFile.Name()); err != nil {
-		return err
+// findSeedISO returns the most recently modified cached ISO of another version of the same flavor, used as seed for
+// a delta download of the ISO. It returns an empty string if there is none.
+func (m *MachineConfig) findSeedISO() string {
+	isoPath := minishiftUtil.GetIsoPath(m.MinikubeISO)
+	if filepath.Base(isoPath) == "unnamed" {
+		return ""
 	}
-	return nil
-}
 
-// getChecksum Tries to get the checksum for a given URL. If the checksum cannot be retrieved the empty string is returned.
-func (m *MachineConfig) getChecksum(baseUrl string) string {
-	checksumURL := fmt.Sprintf(baseUrl + ".sha256")
-	checksumResp, err := http.Get(checksumURL)
+	candidates, err := filepath.Glob(filepath.Join(m.ISOCacheDir, filepath.Dir(isoPath), "*", filepath.Base(m.MinikubeISO)))
 	if err != nil {
 		return ""
 	}
-	defer checksumResp.Body.Close()
 
-	if checksumResp.StatusCode != 200 {
-		return ""
+	seed := ""
+	var seedModTime time.Time
+	for _, candidate := range candidates {
+		if candidate == m.GetISOCacheFilepath() {
+			continue
+		}
+		info, err := os.Stat(candidate)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if info.ModTime().After(seedModTime) {
+			seed, seedModTime = candidate, info.ModTime()
+		}
 	}
-
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(checksumResp.Body)
-	return strings.TrimSpace(buf.String())
+	return seed
 }
 
 func (m *MachineConfig) ShouldCacheMinikubeISO() bool {
@@ -341,7 +334,9 @@ func createHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 
 	h.HostOptions.AuthOptions.CertDir = constants.Minipath
 	h.HostOptions.AuthOptions.StorePath = constants.Minipath
-	h.HostOptions.EngineOptions = engineOptions(config)
+	// the daemon configuration is rendered into its own drop-in, the provisioner only needs the proxy settings to
+	// download the packages of the selected Docker version
+	h.HostOptions.EngineOptions = &engine.Options{Env: config.DockerEngine.ProxyEnv}
 
 	if err := api.Create(h); err != nil {
 		// Wait for all the logs to reach the client
@@ -353,6 +348,11 @@ func createHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 		return nil, fmt.Errorf("Error attempting to save store: %s", err)
 	}
 
+	// the provisioning started the Docker daemon without the configuration of the drop-in
+	if _, err := docker.ApplyDropIn(h.Driver, &config.DockerDaemon); err != nil {
+		return nil, fmt.Errorf("Error configuring the Docker daemon: %s", err)
+	}
+
 	if err = setProxyToShell(config, h); err != nil {
 		return nil, err
 	}
@@ -463,20 +463,11 @@ func CheckIfApiExistsAndLoad(api libmachine.API) (*host.Host, error) {
 }
 
 func CreateSSHShell(api libmachine.API, args []string) error {
-	host, err := CheckIfApiExistsAndLoad(api)
-	if err != nil {
-		return err
-	}
-
-	currentState, err := host.Driver.GetState()
+	host, err := getRunningHost(api)
 	if err != nil {
 		return err
 	}
 
-	if currentState != state.Running {
-		return fmt.Errorf("Error: Cannot run ssh command: Host %q is not running", constants.MachineName)
-	}
-
 	client, err := host.CreateSSHClient()
 	if err != nil {
 		return err
@@ -490,6 +481,43 @@ func CreateSSHShell(api libmachine.API, args []string) error {
 	return err
 }
 
+// RunSSHCommand runs the command on the VM non-interactively and returns the exit status of the command.
+func RunSSHCommand(api libmachine.API, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) (int, error) {
+	client, err := NewSSHClient(api)
+	if err != nil {
+		return -1, err
+	}
+	defer client.Close()
+
+	return sshutil.RunCommandWithExitStatus(client, command, stdin, stdout, stderr)
+}
+
+// NewSSHClient returns an SSH client for the running VM.
+func NewSSHClient(api libmachine.API) (*ssh.Client, error) {
+	host, err := getRunningHost(api)
+	if err != nil {
+		return nil, err
+	}
+	return sshutil.NewSSHClient(host.Driver)
+}
+
+func getRunningHost(api libmachine.API) (*host.Host, error) {
+	host, err := CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return nil, err
+	}
+
+	currentState, err := host.Driver.GetState()
+	if err != nil {
+		return nil, err
+	}
+
+	if currentState != state.Running {
+		return nil, fmt.Errorf("Error: Cannot run ssh command: Host %q is not running", constants.MachineName)
+	}
+	return host, nil
+}
+
 func GetConsoleURL(api libmachine.API) (string, error) {
 	host, err := CheckIfApiExistsAndLoad(api)
 	if err != nil {
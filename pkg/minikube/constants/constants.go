@@ -51,6 +51,7 @@ var (
 	AllInstanceConfigPath = filepath.Join(Minipath, "config", "allinstances.json")
 
 	DefaultCentOsIsoUrl = "https://github.com/minishift/minishift-centos-iso/releases/download/" + version.GetCentOsIsoVersion() + "/" + "minishift-centos7.iso"
+	DefaultB2dIsoUrl    = "https://github.com/minishift/minishift-b2d-iso/releases/download/" + version.GetB2dIsoVersion() + "/" + "minishift-b2d.iso"
 )
 
 // MakeMiniPath is a utility to calculate a relative path to our directory.
@@ -27,3 +27,4 @@ var SupportedVMDrivers = [...]string{
 
 const DefaultVMDriver = "hyperkit"
 const OC_BINARY_NAME = "oc"
+const KUBECTL_BINARY_NAME = "kubectl"
@@ -28,3 +28,4 @@ var SupportedVMDrivers = [...]string{
 
 const DefaultVMDriver = "kvm"
 const OC_BINARY_NAME = "oc"
+const KUBECTL_BINARY_NAME = "kubectl"
@@ -26,3 +26,4 @@ var SupportedVMDrivers = [...]string{
 
 const DefaultVMDriver = "hyperv"
 const OC_BINARY_NAME = "oc.exe"
+const KUBECTL_BINARY_NAME = "kubectl.exe"
@@ -17,14 +17,17 @@ limitations under the License.
 package sshutil
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net"
-	"path/filepath"
+	"path"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/minishift/minishift/pkg/minikube/assets"
+	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
 
 	"github.com/docker/machine/libmachine/drivers"
 	machinessh "github.com/docker/machine/libmachine/ssh"
@@ -94,9 +97,15 @@ func TransferFile(f assets.CopyableFile, client *ssh.Client) error {
 
 // Transfer uses an SSH session to copy a file to the remote machine.
 func Transfer(reader io.Reader, readerLen int64, remotedir, filename string, perm string, c *ssh.Client) error {
+	// the file name is part of the scp protocol header, which is terminated by a newline
+	if filename == "" || strings.ContainsAny(filename, "/\n") {
+		return fmt.Errorf("'%s' is not a valid file name", filename)
+	}
+
 	// Delete the old file first. This makes sure permissions get reset.
-	deleteCmd := fmt.Sprintf("sudo rm -f %s", filepath.Join(remotedir, filename))
-	mkdirCmd := fmt.Sprintf("sudo mkdir -p %s", remotedir)
+	// Paths on the VM always use forward slashes, also on Windows hosts.
+	deleteCmd := fmt.Sprintf("sudo rm -f %s", Quote(path.Join(remotedir, filename)))
+	mkdirCmd := fmt.Sprintf("sudo mkdir -p %s", Quote(remotedir))
 	for _, cmd := range []string{deleteCmd, mkdirCmd} {
 		if err := RunCommand(c, cmd); err != nil {
 			return errors.Wrapf(err, "Error running command: %s", cmd)
@@ -125,7 +134,7 @@ func Transfer(reader io.Reader, readerLen int64, remotedir, filename string, per
 		fmt.Fprint(w, "\x00")
 	}()
 
-	scpcmd := fmt.Sprintf("sudo scp -t %s", remotedir)
+	scpcmd := fmt.Sprintf("sudo scp -t %s", Quote(remotedir))
 	if err := s.Run(scpcmd); err != nil {
 		return errors.Wrap(err, "Error running scp command.")
 	}
@@ -144,6 +153,83 @@ func RunCommand(c *ssh.Client, cmd string) error {
 	return s.Run(cmd)
 }
 
+// RunCommandWithExitStatus runs the command non-interactively, connecting it to the specified streams, and returns
+// the exit status of the command. An error is only returned if the command could not be run or did not report an
+// exit status.
+func RunCommandWithExitStatus(c *ssh.Client, cmd string, stdin io.Reader, stdout io.Writer, stderr io.Writer) (int, error) {
+	s, err := c.NewSession()
+	if err != nil {
+		return -1, errors.Wrap(err, "Error creating a new session via ssh client.")
+	}
+	defer s.Close()
+
+	s.Stdout = stdout
+	s.Stderr = stderr
+	if stdin != nil {
+		// the input is copied without waiting for it to end, since the command might finish without reading it
+		w, err := s.StdinPipe()
+		if err != nil {
+			return -1, errors.Wrap(err, "Error accessing StdinPipe via ssh session.")
+		}
+		go func() {
+			defer w.Close()
+			io.Copy(w, stdin)
+		}()
+	}
+
+	err = s.Run(cmd)
+	if exitErr, ok := err.(*ssh.ExitError); ok {
+		return exitErr.ExitStatus(), nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+// RunCommandWithOutput runs the command and returns its standard output.
+func RunCommandWithOutput(c *ssh.Client, cmd string) (string, error) {
+	s, err := c.NewSession()
+	if err != nil {
+		return "", errors.Wrap(err, "Error creating a new session via ssh client.")
+	}
+	defer s.Close()
+
+	out, err := s.Output(cmd)
+	return string(out), err
+}
+
+// IsRemoteDir returns true if the specified path is a directory on the remote machine.
+func IsRemoteDir(c *ssh.Client, path string) bool {
+	return RunCommand(c, fmt.Sprintf("sudo test -d %s", Quote(path))) == nil
+}
+
+// Download copies the content of the file on the remote machine to the writer.
+func Download(c *ssh.Client, remotePath string, w io.Writer) error {
+	if err := RunCommand(c, fmt.Sprintf("sudo test -f %s", Quote(remotePath))); err != nil {
+		return fmt.Errorf("'%s' does not exist or is not a regular file", remotePath)
+	}
+
+	s, err := c.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "Error creating a new session via ssh client.")
+	}
+	defer s.Close()
+
+	var stderr bytes.Buffer
+	s.Stdout = w
+	s.Stderr = &stderr
+	if err := s.Run(fmt.Sprintf("sudo cat %s", Quote(remotePath))); err != nil {
+		return errors.Wrapf(err, "Error reading '%s': %s", remotePath, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Quote quotes the string for use as a single argument in a shell command on the remote machine.
+func Quote(s string) string {
+	return fmt.Sprintf("'%s'", minishiftStrings.EscapeSingleQuote(s))
+}
+
 type sshHost struct {
 	IP         string
 	Port       int
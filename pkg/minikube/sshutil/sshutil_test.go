@@ -95,3 +95,80 @@ func TestTransfer(t *testing.T) {
 	err = Transfer(bytes.NewReader(contents), int64(len(contents)), "/tmp", dest, "0777", c)
 	assert.NoError(t, err, "Error transferring bytes")
 }
+
+func TestTransferQuotesRemotePaths(t *testing.T) {
+	s, _ := tests.NewSSHServer()
+	port, err := s.Start()
+	assert.NoError(t, err, "Error starting ssh server")
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	c, err := NewSSHClient(d)
+	assert.NoError(t, err, "Error starting ssh client")
+
+	contents := []byte("testcontents")
+	err = Transfer(bytes.NewReader(contents), int64(len(contents)), "/tmp/my dir;reboot", "it's", "0644", c)
+	assert.NoError(t, err, "Error transferring bytes")
+	assert.Contains(t, s.Commands, `sudo rm -f '/tmp/my dir;reboot/it'"'"'s'`)
+	assert.Contains(t, s.Commands, "sudo mkdir -p '/tmp/my dir;reboot'")
+	assert.Contains(t, s.Commands, "sudo scp -t '/tmp/my dir;reboot'")
+
+	err = Transfer(bytes.NewReader(contents), int64(len(contents)), "/tmp", "foo\nC0777 1 bar", "0644", c)
+	assert.Error(t, err, "A file name with a newline should be rejected")
+}
+
+func TestRunCommandWithExitStatus(t *testing.T) {
+	s, _ := tests.NewSSHServer()
+	s.CommandToOutput = map[string]string{"echo foo": "foo"}
+	s.CommandToExitStatus = map[string]int{"false": 3}
+	port, err := s.Start()
+	assert.NoError(t, err, "Error starting ssh server")
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	c, err := NewSSHClient(d)
+	assert.NoError(t, err, "Error starting ssh client")
+
+	var stdout bytes.Buffer
+	exitStatus, err := RunCommandWithExitStatus(c, "echo foo", nil, &stdout, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, exitStatus)
+	assert.Equal(t, "foo", stdout.String())
+
+	exitStatus, err = RunCommandWithExitStatus(c, "false", nil, &stdout, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, exitStatus)
+}
+
+func TestDownload(t *testing.T) {
+	s, _ := tests.NewSSHServer()
+	s.CommandToOutput = map[string]string{"sudo cat '/tmp/foo'": "testcontents"}
+	s.CommandToExitStatus = map[string]int{"sudo test -f '/tmp/missing'": 1}
+	port, err := s.Start()
+	assert.NoError(t, err, "Error starting ssh server")
+	d := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress:  "127.0.0.1",
+			SSHKeyPath: "",
+		},
+	}
+	c, err := NewSSHClient(d)
+	assert.NoError(t, err, "Error starting ssh client")
+
+	var content bytes.Buffer
+	err = Download(c, "/tmp/foo", &content)
+	assert.NoError(t, err, "Error downloading file")
+	assert.Equal(t, "testcontents", content.String())
+
+	err = Download(c, "/tmp/missing", &content)
+	assert.Error(t, err, "Expected error downloading missing file")
+}
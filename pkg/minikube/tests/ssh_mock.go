@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/binary"
 	"io"
 	"net"
 	"strconv"
@@ -38,6 +39,8 @@ type SSHServer struct {
 	Transfers            *bytes.Buffer
 	HadASessionRequested bool
 	CommandToOutput      map[string]string
+	// CommandToExitStatus specifies the exit status reported for a command, 0 if not specified.
+	CommandToExitStatus map[string]int
 }
 
 // NewSSHServer returns a NewSSHServer instance, ready for use.
@@ -114,7 +117,9 @@ func (s *SSHServer) Start() (int, error) {
 					if val, ok := s.CommandToOutput[cmd.Command]; ok {
 						channel.Write([]byte(val))
 					}
-					channel.SendRequest("exit-status", false, []byte{0, 0, 0, 0})
+					status := make([]byte, 4)
+					binary.BigEndian.PutUint32(status, uint32(s.CommandToExitStatus[cmd.Command]))
+					channel.SendRequest("exit-status", false, status)
 
 					// Store anything that comes in over stdin.
 					io.Copy(s.Transfers, channel)
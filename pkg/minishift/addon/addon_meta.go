@@ -28,6 +28,7 @@ var requiredMetaTags = []string{NameMetaTagName, DescriptionMetaTagName}
 
 const (
 	requiredVars             = "Required-Vars"
+	requiredImages           = "Required-Images"
 	NameMetaTagName          = "Name"
 	DescriptionMetaTagName   = "Description"
 	RequiredOpenShiftVersion = "OpenShift-Version"
@@ -36,6 +37,8 @@ const (
 	anyMinishiftVersion      = ""
 	varDefaults              = "Var-Defaults"
 	dependsOn                = "Depends-On"
+	requires                 = "Requires"
+	addOnVersion             = "Version"
 )
 
 type RequiredVar struct {
@@ -48,12 +51,14 @@ type AddOnMeta interface {
 	Name() string
 	Description() []string
 	RequiredVars() ([]string, error)
+	RequiredImages() ([]string, error)
 	VarDefaults() ([]RequiredVar, error)
 	GetValue(key string) string
 	OpenShiftVersion() string
 	MinishiftVersion() string
 	Dependency() ([]string, error)
 	Url() string
+	Version() string
 }
 
 type DefaultAddOnMeta struct {
@@ -114,6 +119,14 @@ func (meta *DefaultAddOnMeta) RequiredVars() ([]string, error) {
 	}
 }
 
+// RequiredImages returns the container images the add-on needs, as specified by the Required-Images meta tag.
+func (meta *DefaultAddOnMeta) RequiredImages() ([]string, error) {
+	if val, contains := meta.headers[requiredImages].(string); contains {
+		return minishiftStrings.SplitAndTrim(val, ",")
+	}
+	return []string{}, nil
+}
+
 func (meta *DefaultAddOnMeta) VarDefaults() ([]RequiredVar, error) {
 	// Ignore errors as checking has been done during varDefaultsCheck as part of NewAddOnMeta
 	if val, contains := meta.headers[varDefaults].(string); contains {
@@ -138,6 +151,14 @@ func (meta *DefaultAddOnMeta) GetValue(key string) string {
 	return meta.headers[key].(string)
 }
 
+// Version returns the value of the optional Version header or an empty string if the header is not specified.
+func (meta *DefaultAddOnMeta) Version() string {
+	if val, contains := meta.headers[addOnVersion].(string); contains {
+		return val
+	}
+	return ""
+}
+
 func (meta *DefaultAddOnMeta) OpenShiftVersion() string {
 	if val, contains := meta.headers[RequiredOpenShiftVersion].(string); contains {
 		return val
@@ -152,19 +173,37 @@ func (meta *DefaultAddOnMeta) MinishiftVersion() string {
 	return anyMinishiftVersion
 }
 
+// Dependency returns the add-ons declared via the Depends-On and Requires headers.
 func (meta *DefaultAddOnMeta) Dependency() ([]string, error) {
-	if val, contains := meta.headers[dependsOn].(string); contains {
-		return minishiftStrings.SplitAndTrim(val, ",")
+	dependencies := []string{}
+	for _, header := range []string{dependsOn, requires} {
+		val, contains := meta.headers[header].(string)
+		if !contains {
+			continue
+		}
+		names, err := minishiftStrings.SplitAndTrim(val, ",")
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if !minishiftStrings.Contains(dependencies, name) {
+				dependencies = append(dependencies, name)
+			}
+		}
 	}
-	return []string{}, nil
+	return dependencies, nil
 }
 
 func checkDependencySemantic(headers map[string]interface{}) bool {
 	// Comma seperated list of dependencies
-	if headers[dependsOn] != nil {
-		dependencies := headers[dependsOn].(string)
-		match, _ := regexp.MatchString("^(([a-zA-Z][-[a-zA-Z]*)([,][ ]?[a-zA-Z][-[a-zA-Z]*)*)$", dependencies)
-		return match
+	for _, header := range []string{dependsOn, requires} {
+		if headers[header] != nil {
+			dependencies := headers[header].(string)
+			match, _ := regexp.MatchString("^(([a-zA-Z][-[a-zA-Z0-9]*)([,][ ]?[a-zA-Z][-[a-zA-Z0-9]*)*)$", dependencies)
+			if !match {
+				return false
+			}
+		}
 	}
 	return true
 }
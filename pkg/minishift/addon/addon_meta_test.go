@@ -101,6 +101,17 @@ func Test_required_vars_empty_if_not_specified(t *testing.T) {
 	assert.Equal(t, []string{}, requiredVars)
 }
 
+func Test_required_images_meta_is_extracted(t *testing.T) {
+	testMap := make(map[string]interface{})
+	testMap["Name"] = "acme"
+	testMap["Description"] = []string{"Acme Add-on"}
+	testMap["Required-Images"] = "docker.io/acme/server:1.0, docker.io/acme/ui:#{openshift-version}"
+
+	addOnMeta := getAddOnMeta(testMap, t)
+	requiredImages, _ := addOnMeta.RequiredImages()
+	assert.Equal(t, []string{"docker.io/acme/server:1.0", "docker.io/acme/ui:#{openshift-version}"}, requiredImages)
+}
+
 func Test_var_defaults_empty_if_not_specified(t *testing.T) {
 	testMap := make(map[string]interface{})
 	testMap["Name"] = "acme"
@@ -195,3 +206,17 @@ func Test_check_openshift_version_sematic(t *testing.T) {
 		assert.Equal(t, versionTest.expectedResult, checkVersionSemantic(versionTest.OpenshiftVersion))
 	}
 }
+
+func Test_requires_and_depends_on_are_merged(t *testing.T) {
+	testMap := make(map[string]interface{})
+	testMap["Name"] = "acme"
+	testMap["Description"] = []string{"Acme Add-on"}
+	testMap["Depends-On"] = "anyuid, admin-user"
+	testMap["Requires"] = "admin-user, registry2"
+
+	addOnMeta := getAddOnMeta(testMap, t)
+	dependencies, err := addOnMeta.Dependency()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"anyuid", "admin-user", "registry2"}, dependencies)
+}
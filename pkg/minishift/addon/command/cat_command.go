@@ -43,7 +43,7 @@ func (c *CatCommand) doExecute(ec *ExecutionContext, ignoreError bool, outputVar
 
 	// split off the actual 'cat' command. As we need to get file name of remaining string.
 	catString := strings.TrimPrefix(c.rawCommand, "cat")
-	fileName := strings.Replace(catString, " ", "", 1)
+	fileName := ec.Interpolate(strings.Replace(catString, " ", "", 1))
 
 	if fInfo, err = os.Stat(fileName); os.IsNotExist(err) {
 		return errors.New(fmt.Sprintf("File %s doesn't exist", fileName))
@@ -18,6 +18,7 @@ package command
 
 import (
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	pkgTesting "github.com/minishift/minishift/pkg/testing"
@@ -94,3 +95,21 @@ func Test_cat_command_when_file_not_exist(t *testing.T) {
 		tee.Close()
 	}
 }
+
+func Test_cat_command_interpolates_file_name(t *testing.T) {
+	tmpfile, err := ioutil.TempFile("", "storage-true")
+	assert.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+
+	_, err = tmpfile.Write([]byte(`{"persistent": true}`))
+	assert.NoError(t, err)
+	tmpfile.Close()
+
+	context := NewInterpolationContext()
+	context.AddToContext("PERSIST", "true")
+
+	cat := NewCatCommand(strings.Replace(tmpfile.Name(), "true", "#{PERSIST}", 1), false, "STORAGE")
+	err = cat.Execute(&ExecutionContext{interpolationContext: context})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"persistent": true}`, context.Interpolate("#{STORAGE}"))
+}
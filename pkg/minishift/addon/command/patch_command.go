@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/openshift"
+)
+
+const invalidPatchCommandError = "Unable to extract target and patch from cmd: '%s'. Expected 'patch <master|kube|node> <json>'"
+
+var patchRegexp = regexp.MustCompile(`(?s)^patch\s+(master|kube|node)\s+(.+)$`)
+
+// PatchCommand applies a JSON merge patch to an OpenShift configuration file. The patched configuration is validated
+// before it is written, but OpenShift is not restarted, so that an add-on can apply several patches in a row.
+type PatchCommand struct {
+	*defaultCommand
+}
+
+func NewPatchCommand(command string, ignoreError bool) *PatchCommand {
+	defaultCommand := &defaultCommand{rawCommand: command, ignoreError: ignoreError}
+	patchCommand := &PatchCommand{defaultCommand}
+	defaultCommand.fn = patchCommand.doExecute
+	return patchCommand
+}
+
+func (c *PatchCommand) doExecute(ec *ExecutionContext, ignoreError bool, outputVariable string) error {
+	target, patch, err := c.getTargetAndPatch(ec.Interpolate(c.rawCommand))
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(".")
+	_, err = openshift.PatchConfig(openshift.GetOpenShiftPatchTarget(target), patch, ec.GetDockerCommander())
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error executing command '%s': %s", c.rawCommand, err.Error()))
+	}
+	return nil
+}
+
+func (c *PatchCommand) getTargetAndPatch(cmd string) (string, string, error) {
+	matches := patchRegexp.FindStringSubmatch(strings.TrimSpace(cmd))
+	if matches == nil {
+		return "", "", errors.New(fmt.Sprintf(invalidPatchCommandError, cmd))
+	}
+	patch := strings.TrimSpace(matches[2])
+	if len(patch) > 1 && strings.HasPrefix(patch, "'") && strings.HasSuffix(patch, "'") {
+		patch = patch[1 : len(patch)-1]
+	}
+	return matches[1], patch, nil
+}
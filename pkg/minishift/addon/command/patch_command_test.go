@@ -0,0 +1,49 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_patch_target_and_patch_are_extracted(t *testing.T) {
+	var tests = []struct {
+		cmd    string
+		target string
+		patch  string
+	}{
+		{`patch master {"routingConfig": {"subdomain": "nip.io"}}`, "master", `{"routingConfig": {"subdomain": "nip.io"}}`},
+		{`patch kube '{"foo": "bar"}'`, "kube", `{"foo": "bar"}`},
+		{"patch node {\n\"foo\": \"bar\"\n}", "node", "{\n\"foo\": \"bar\"\n}"},
+	}
+
+	for _, test := range tests {
+		target, patch, err := NewPatchCommand(test.cmd, false).getTargetAndPatch(test.cmd)
+		assert.NoError(t, err)
+		assert.Equal(t, test.target, target)
+		assert.Equal(t, test.patch, patch)
+	}
+}
+
+func Test_patch_without_valid_target_fails(t *testing.T) {
+	for _, cmd := range []string{"patch {}", "patch foo {}", "patch master"} {
+		_, _, err := NewPatchCommand(cmd, false).getTargetAndPatch(cmd)
+		assert.Error(t, err, "Expected '%s' to fail", cmd)
+	}
+}
@@ -169,20 +169,76 @@ func (m *AddOnManager) Disable(addonName string) (*config.AddOnConfig, error) {
 	return &config.AddOnConfig{addonName, false, float64(addOn.GetPriority())}, nil
 }
 
-// Apply executes all enabled addons.
+// Apply executes all enabled addons. Add-ons required by enabled add-ons are applied before them, even if they are not enabled.
 func (m *AddOnManager) Apply(context *command.ExecutionContext) error {
-	addOns := m.mapToSlice()
-	sort.Sort(addon.ByPriority(addOns))
+	addOns, err := m.ApplicationOrder()
+	if err != nil {
+		return err
+	}
 
 	for _, addOn := range addOns {
-		if addOn.IsEnabled() {
-			err := m.ApplyAddOn(addOn, context)
-			if err != nil {
+		err := m.ApplyAddOn(addOn, context)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplicationOrder returns the enabled add-ons together with the add-ons they depend on, ordered so that each add-on
+// comes after its dependencies. Apart from that, add-ons are ordered by priority. An error is returned if a dependency
+// is not installed or if the dependencies are circular.
+func (m *AddOnManager) ApplicationOrder() ([]addon.AddOn, error) {
+	addOns := m.mapToSlice()
+	sort.Slice(addOns, func(i, j int) bool { return addOns[i].MetaData().Name() < addOns[j].MetaData().Name() })
+	sort.Stable(addon.ByPriority(addOns))
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var ordered []addon.AddOn
+
+	var visit func(addOn addon.AddOn, path []string) error
+	visit = func(addOn addon.AddOn, path []string) error {
+		name := addOn.MetaData().Name()
+		path = append(path, name)
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("Circular add-on dependency: %s", strings.Join(path, " -> "))
+		}
+
+		state[name] = visiting
+		dependencies, err := addOn.MetaData().Dependency()
+		if err != nil {
+			return err
+		}
+		for _, dependency := range dependencies {
+			dependentAddOn := m.Get(dependency)
+			if dependentAddOn == nil {
+				return fmt.Errorf("Add-on '%s' requires add-on '%s', which is not installed", name, dependency)
+			}
+			if err := visit(dependentAddOn, path); err != nil {
 				return err
 			}
 		}
+		state[name] = visited
+		ordered = append(ordered, addOn)
+		return nil
 	}
-	return nil
+
+	for _, addOn := range addOns {
+		if !addOn.IsEnabled() {
+			continue
+		}
+		if err := visit(addOn, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
 }
 
 func (m *AddOnManager) IsInstalled(name string) bool {
@@ -229,7 +285,7 @@ func (m *AddOnManager) ApplyAddOn(addOn addon.AddOn, context *command.ExecutionC
 	}
 
 	fmt.Print("\n")
-	return nil
+	return recordApplied(addOn)
 }
 
 func (m *AddOnManager) RemoveAddOn(addOn addon.AddOn, context *command.ExecutionContext) error {
@@ -263,7 +319,7 @@ func (m *AddOnManager) RemoveAddOn(addOn addon.AddOn, context *command.Execution
 		return err
 	}
 	fmt.Print("\n")
-	return nil
+	return recordRemoved(addOn)
 }
 
 func (m *AddOnManager) mapToSlice() []addon.AddOn {
@@ -64,7 +64,7 @@ func Test_create_addon_manager(t *testing.T) {
 
 	addOns := manager.List()
 
-	expectedNumberOfAddOns := 8
+	expectedNumberOfAddOns := 14
 	assert.Len(t, addOns, expectedNumberOfAddOns)
 }
 
@@ -278,3 +278,61 @@ func getTestAddonMap(name, description, requireVar, varDefault, openshiftVersion
 	}
 	return testAddonMap
 }
+
+func Test_dependencies_are_applied_before_dependent_addons(t *testing.T) {
+	manager := createManagerWithAddOns(t, map[string]string{
+		"app":      "admin,registry",
+		"admin":    "",
+		"registry": "admin",
+	}, "app")
+
+	addOns, err := manager.ApplicationOrder()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin", "registry", "app"}, addOnNames(addOns))
+}
+
+func Test_missing_dependency_leads_to_error(t *testing.T) {
+	manager := createManagerWithAddOns(t, map[string]string{"app": "admin"}, "app")
+
+	_, err := manager.ApplicationOrder()
+	assert.EqualError(t, err, "Add-on 'app' requires add-on 'admin', which is not installed")
+}
+
+func Test_circular_dependency_leads_to_error(t *testing.T) {
+	manager := createManagerWithAddOns(t, map[string]string{
+		"app":   "admin",
+		"admin": "users",
+		"users": "app",
+	}, "app")
+
+	_, err := manager.ApplicationOrder()
+	assert.EqualError(t, err, "Circular add-on dependency: app -> admin -> users -> app")
+}
+
+func createManagerWithAddOns(t *testing.T, dependencies map[string]string, enabled ...string) *AddOnManager {
+	addOns := make(map[string]addon.AddOn)
+	for name, requires := range dependencies {
+		headers := map[string]interface{}{"Name": name, "Description": []string{name}}
+		if requires != "" {
+			headers["Requires"] = requires
+		}
+		meta, err := addon.NewAddOnMeta(headers)
+		assert.NoError(t, err)
+		addOn := addon.NewAddOn(meta, nil, nil, nil, "")
+		for _, e := range enabled {
+			if e == name {
+				addOn.SetEnabled(true)
+			}
+		}
+		addOns[name] = addOn
+	}
+	return &AddOnManager{addOns: addOns}
+}
+
+func addOnNames(addOns []addon.AddOn) []string {
+	var names []string
+	for _, addOn := range addOns {
+		names = append(names, addOn.MetaData().Name())
+	}
+	return names
+}
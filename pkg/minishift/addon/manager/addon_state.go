@@ -0,0 +1,263 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/addon"
+	instanceState "github.com/minishift/minishift/pkg/minishift/config"
+)
+
+// AddOnChange describes how an installed add-on differs from the state it was applied in.
+type AddOnChange struct {
+	AddOn    addon.AddOn
+	Previous *instanceState.AppliedAddOn
+	Current  *instanceState.AppliedAddOn
+}
+
+// Snapshot captures the version, the file checksums and the definition of the specified add-on.
+func Snapshot(addOn addon.AddOn) (*instanceState.AppliedAddOn, error) {
+	snapshot := &instanceState.AppliedAddOn{Version: addOn.MetaData().Version(), Checksums: make(map[string]string)}
+	definitionFile := addOn.MetaData().Name() + ".addon"
+
+	dir := addOn.InstallPath()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		hash := sha256.Sum256(content)
+		snapshot.Checksums[relPath] = hex.EncodeToString(hash[:])
+		if relPath == definitionFile {
+			snapshot.Definition = string(content)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the content of add-on '%s': %v", addOn.MetaData().Name(), err)
+	}
+	return snapshot, nil
+}
+
+// ChangedAddOns returns the applied add-ons whose content changed since they were applied. If names are specified,
+// only these add-ons are checked. Add-ons which were never applied or which are no longer installed are skipped.
+func (m *AddOnManager) ChangedAddOns(names ...string) ([]AddOnChange, error) {
+	if instanceState.InstanceStateConfig == nil {
+		return nil, nil
+	}
+
+	applied := instanceState.InstanceStateConfig.AppliedAddOns
+	if len(names) == 0 {
+		for name := range applied {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var changes []AddOnChange
+	for _, name := range names {
+		previous, wasApplied := applied[name]
+		addOn := m.Get(name)
+		if !wasApplied || addOn == nil {
+			continue
+		}
+		current, err := Snapshot(addOn)
+		if err != nil {
+			return nil, err
+		}
+		if !sameChecksums(previous.Checksums, current.Checksums) {
+			changes = append(changes, AddOnChange{AddOn: addOn, Previous: previous, Current: current})
+		}
+	}
+	return changes, nil
+}
+
+// PendingAddOns returns the add-ons which need to be applied to bring the instance in line with the configuration,
+// in application order. These are the enabled add-ons, and the add-ons they depend on, which were never applied or
+// whose content changed since they were applied.
+func (m *AddOnManager) PendingAddOns() ([]addon.AddOn, error) {
+	addOns, err := m.ApplicationOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied map[string]*instanceState.AppliedAddOn
+	if instanceState.InstanceStateConfig != nil {
+		applied = instanceState.InstanceStateConfig.AppliedAddOns
+	}
+
+	var pending []addon.AddOn
+	for _, addOn := range addOns {
+		previous, wasApplied := applied[addOn.MetaData().Name()]
+		if wasApplied {
+			current, err := Snapshot(addOn)
+			if err != nil {
+				return nil, err
+			}
+			if sameChecksums(previous.Checksums, current.Checksums) {
+				continue
+			}
+		}
+		pending = append(pending, addOn)
+	}
+	return pending, nil
+}
+
+// Diff describes the changed files of the add-on and shows the changes of the add-on definition line by line.
+func (c AddOnChange) Diff() string {
+	var out bytes.Buffer
+	if c.Previous.Version != c.Current.Version {
+		fmt.Fprintf(&out, "Version: %s -> %s\n", displayVersion(c.Previous.Version), displayVersion(c.Current.Version))
+	}
+
+	var files []string
+	for file := range c.Previous.Checksums {
+		files = append(files, file)
+	}
+	for file := range c.Current.Checksums {
+		if _, ok := c.Previous.Checksums[file]; !ok {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		previous, inPrevious := c.Previous.Checksums[file]
+		current, inCurrent := c.Current.Checksums[file]
+		switch {
+		case !inPrevious:
+			fmt.Fprintf(&out, "added:    %s\n", file)
+		case !inCurrent:
+			fmt.Fprintf(&out, "deleted:  %s\n", file)
+		case previous != current:
+			fmt.Fprintf(&out, "modified: %s\n", file)
+		}
+	}
+
+	if c.Previous.Definition != c.Current.Definition {
+		out.WriteString(diffLines(c.Previous.Definition, c.Current.Definition))
+	}
+	return out.String()
+}
+
+// recordApplied stores the snapshot of the applied add-on in the instance state.
+func recordApplied(addOn addon.AddOn) error {
+	if instanceState.InstanceStateConfig == nil {
+		return nil
+	}
+
+	snapshot, err := Snapshot(addOn)
+	if err != nil {
+		return err
+	}
+	if instanceState.InstanceStateConfig.AppliedAddOns == nil {
+		instanceState.InstanceStateConfig.AppliedAddOns = make(map[string]*instanceState.AppliedAddOn)
+	}
+	instanceState.InstanceStateConfig.AppliedAddOns[addOn.MetaData().Name()] = snapshot
+	return instanceState.InstanceStateConfig.Write()
+}
+
+// recordRemoved removes the add-on from the applied add-ons of the instance state.
+func recordRemoved(addOn addon.AddOn) error {
+	if instanceState.InstanceStateConfig == nil {
+		return nil
+	}
+
+	delete(instanceState.InstanceStateConfig.AppliedAddOns, addOn.MetaData().Name())
+	return instanceState.InstanceStateConfig.Write()
+}
+
+func sameChecksums(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for file, checksum := range a {
+		if b[file] != checksum {
+			return false
+		}
+	}
+	return true
+}
+
+func displayVersion(version string) string {
+	if version == "" {
+		return "<none>"
+	}
+	return version
+}
+
+// diffLines returns a line based diff of the two texts, prefixing removed lines with '-' and added lines with '+'.
+func diffLines(previous string, current string) string {
+	a := strings.Split(strings.TrimSuffix(previous, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(current, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			fmt.Fprintf(&out, "- %s\n", a[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+ %s\n", b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		fmt.Fprintf(&out, "- %s\n", a[i])
+	}
+	for ; j < len(b); j++ {
+		fmt.Fprintf(&out, "+ %s\n", b[j])
+	}
+	return out.String()
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/addon/config"
+	instanceState "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var acmeAddOn = `# Name: acme
+# Description: Acme add-on
+# Version: 1.0
+
+oc new-project acme
+echo acme created
+`
+
+func Test_changed_addons_are_detected(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "minishift-test-addon-state-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	instanceState.InstanceStateConfig, err = instanceState.NewInstanceStateConfig(filepath.Join(tmpDir, "state.json"))
+	assert.NoError(t, err)
+	defer func() { instanceState.InstanceStateConfig = nil }()
+
+	addOnDir := filepath.Join(tmpDir, "addons", "acme")
+	assert.NoError(t, os.MkdirAll(addOnDir, 0777))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(addOnDir, "acme.addon"), []byte(acmeAddOn), 0644))
+
+	manager, err := NewAddOnManager(filepath.Join(tmpDir, "addons"), make(map[string]*config.AddOnConfig))
+	assert.NoError(t, err)
+	assert.NoError(t, recordApplied(manager.Get("acme")))
+
+	changes, err := manager.ChangedAddOns()
+	assert.NoError(t, err)
+	assert.Empty(t, changes, "Unchanged add-on should not be reported")
+
+	updated := `# Name: acme
+# Description: Acme add-on
+# Version: 1.1
+
+oc new-project acme
+oc new-app acme
+echo acme created
+`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(addOnDir, "acme.addon"), []byte(updated), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(addOnDir, "template.json"), []byte("{}"), 0644))
+	manager, err = NewAddOnManager(filepath.Join(tmpDir, "addons"), make(map[string]*config.AddOnConfig))
+	assert.NoError(t, err)
+
+	changes, err = manager.ChangedAddOns()
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+
+	expectedDiff := `Version: 1.0 -> 1.1
+modified: acme.addon
+added:    template.json
+- # Version: 1.0
++ # Version: 1.1
++ oc new-app acme
+`
+	assert.Equal(t, expectedDiff, changes[0].Diff())
+}
+
+func Test_removed_addons_are_no_longer_tracked(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "minishift-test-addon-state-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	instanceState.InstanceStateConfig, err = instanceState.NewInstanceStateConfig(filepath.Join(tmpDir, "state.json"))
+	assert.NoError(t, err)
+	defer func() { instanceState.InstanceStateConfig = nil }()
+
+	addOnDir := filepath.Join(tmpDir, "addons", "acme")
+	assert.NoError(t, os.MkdirAll(addOnDir, 0777))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(addOnDir, "acme.addon"), []byte(acmeAddOn), 0644))
+	manager, err := NewAddOnManager(filepath.Join(tmpDir, "addons"), make(map[string]*config.AddOnConfig))
+	assert.NoError(t, err)
+
+	assert.NoError(t, recordApplied(manager.Get("acme")))
+	assert.Contains(t, instanceState.InstanceStateConfig.AppliedAddOns, "acme")
+
+	assert.NoError(t, recordRemoved(manager.Get("acme")))
+	assert.NotContains(t, instanceState.InstanceStateConfig.AppliedAddOns, "acme")
+}
+
+func Test_pending_addons_are_enabled_and_not_applied_or_changed(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "minishift-test-addon-state-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	instanceState.InstanceStateConfig, err = instanceState.NewInstanceStateConfig(filepath.Join(tmpDir, "state.json"))
+	assert.NoError(t, err)
+	defer func() { instanceState.InstanceStateConfig = nil }()
+
+	addOnDir := filepath.Join(tmpDir, "addons", "acme")
+	assert.NoError(t, os.MkdirAll(addOnDir, 0777))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(addOnDir, "acme.addon"), []byte(acmeAddOn), 0644))
+
+	manager, err := NewAddOnManager(filepath.Join(tmpDir, "addons"), make(map[string]*config.AddOnConfig))
+	assert.NoError(t, err)
+	pending, err := manager.PendingAddOns()
+	assert.NoError(t, err)
+	assert.Empty(t, pending, "Disabled add-ons are not pending")
+
+	addOnConfigs := map[string]*config.AddOnConfig{"acme": {Name: "acme", Enabled: true}}
+	manager, err = NewAddOnManager(filepath.Join(tmpDir, "addons"), addOnConfigs)
+	assert.NoError(t, err)
+	pending, err = manager.PendingAddOns()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1, "Enabled add-on which was never applied is pending")
+
+	assert.NoError(t, recordApplied(manager.Get("acme")))
+	pending, err = manager.PendingAddOns()
+	assert.NoError(t, err)
+	assert.Empty(t, pending, "Applied add-on is not pending")
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(addOnDir, "template.json"), []byte("{}"), 0644))
+	pending, err = manager.PendingAddOns()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1, "Changed add-on is pending")
+}
@@ -18,6 +18,7 @@ package parser
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -39,9 +40,14 @@ const (
 	noAddOnDefinitionFoundError         = "There needs to be an addon file per addon directory. Found none in '%s'"
 	multipleAddOnDefinitionsError       = "There can only be one addon file per addon directory. Found '%s'"
 	multipleAddOnRemoveDefinitionsError = "There can only be one addon.remove file per addon directory. Found '%s'"
+	multipleRemoveSectionsError         = "There can only be one Remove section per addon file"
+	removeSectionAndFileError           = "An add-on can either have a Remove section or an addon.remove file, but not both"
 	regexToGetMetaTagInfo               = `^# ?([a-zA-Z-]*):(.*)`
 )
 
+// removeSection marks the start of the commands which remove the add-on again
+var removeSection = regexp.MustCompile(`^# ?Remove:\s*$`)
+
 // AddOnParser is responsible for loading an addon from file and converting it into an AddOn
 type AddOnParser struct {
 	handler CommandHandler
@@ -71,51 +77,63 @@ func NewAddOnParser() *AddOnParser {
 	catHandler := &CatCommandHandler{&defaultCommandHandler{}}
 	echoHandler.SetNext(catHandler)
 
+	patchHandler := &PatchCommandHandler{&defaultCommandHandler{}}
+	catHandler.SetNext(patchHandler)
+
 	parser.handler = ocHandler
 
 	return &parser
 }
 
-func (parser *AddOnParser) getAddOnContent(addOnDir, fileSuffix string) (addon.AddOnMeta, []command.Command, error) {
+func (parser *AddOnParser) getAddOnContent(addOnDir, fileSuffix string) (addon.AddOnMeta, []command.Command, []command.Command, error) {
 	var (
-		meta     addon.AddOnMeta
-		commands []command.Command
-		err      error
+		meta           addon.AddOnMeta
+		commands       []command.Command
+		removeCommands []command.Command
+		err            error
 	)
 
 	addonReader, err := parser.getAddOnContentReader(addOnDir, fileSuffix)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	if addonReader != nil {
-		meta, commands, err = parser.parseAddOnContent(addonReader)
+		meta, commands, removeCommands, err = parser.parseAddOnSections(addonReader)
 		var name string
 		if meta != nil {
 			name = meta.Name()
 		}
 		if err != nil {
-			return nil, nil, NewParseError(err.Error(), name, addOnDir)
+			return nil, nil, nil, NewParseError(err.Error(), name, addOnDir)
 		}
 		if filepath.Base(addOnDir) != name {
-			return nil, nil, NewParseError(fmt.Sprintf("Add-on directory name should match to addon name"), "", addOnDir)
+			return nil, nil, nil, NewParseError(fmt.Sprintf("Add-on directory name should match to addon name"), "", addOnDir)
 		}
 	}
 
-	return meta, commands, nil
+	return meta, commands, removeCommands, nil
 }
 
 // Parse parses the addon files containing in a directory provided via addOnDir and returns an AddOn instance.
 // If an error occurs, the error is returned.
 func (parser *AddOnParser) Parse(addOnDir string) (addon.AddOn, error) {
-	meta, commands, err := parser.getAddOnContent(addOnDir, ".addon")
+	meta, commands, inlineRemoveCommands, err := parser.getAddOnContent(addOnDir, ".addon")
 	if err != nil {
 		return nil, err
 	}
-	removeMeta, removeCommands, err := parser.getAddOnContent(addOnDir, ".addon.remove")
+	removeMeta, removeCommands, _, err := parser.getAddOnContent(addOnDir, ".addon.remove")
 	if err != nil {
 		return nil, err
 	}
 
+	// the Remove section of the addon file is used like an addon.remove file sharing the meta data of the add-on
+	if inlineRemoveCommands != nil {
+		if removeMeta != nil {
+			return nil, NewParseError(removeSectionAndFileError, meta.Name(), addOnDir)
+		}
+		removeMeta, removeCommands = meta, inlineRemoveCommands
+	}
+
 	return addon.NewAddOn(meta, removeMeta, commands, removeCommands, addOnDir), nil
 }
 
@@ -161,18 +179,25 @@ func (parser *AddOnParser) getAddOnContentReader(addOnDir string, fileSuffix str
 }
 
 func (parser *AddOnParser) parseAddOnContent(reader io.Reader) (addon.AddOnMeta, []command.Command, error) {
+	meta, commands, _, err := parser.parseAddOnSections(reader)
+	return meta, commands, err
+}
+
+// parseAddOnSections parses the add-on content and returns the meta data, the commands and the commands of the
+// Remove section. The returned remove commands are nil if the content has no Remove section.
+func (parser *AddOnParser) parseAddOnSections(reader io.Reader) (addon.AddOnMeta, []command.Command, []command.Command, error) {
 	scanner := bufio.NewScanner(reader)
 	meta, err := parser.parseHeader(scanner)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	commands, err := parser.parseCommands(scanner)
+	commands, removeCommands, err := parser.parseCommands(scanner)
 	if err != nil {
-		return meta, nil, err
+		return meta, nil, nil, err
 	}
 
-	return meta, commands, nil
+	return meta, commands, removeCommands, nil
 }
 
 func (parser *AddOnParser) parseHeader(scanner *bufio.Scanner) (addon.AddOnMeta, error) {
@@ -194,15 +219,23 @@ func (parser *AddOnParser) parseHeader(scanner *bufio.Scanner) (addon.AddOnMeta,
 	return headerMeta, nil
 }
 
-func (parser *AddOnParser) parseCommands(scanner *bufio.Scanner) ([]command.Command, error) {
-	var commands []command.Command
+func (parser *AddOnParser) parseCommands(scanner *bufio.Scanner) ([]command.Command, []command.Command, error) {
+	var commands, removeCommands []command.Command
 	for scanner.Scan() {
 		var outputVariable string
 		ignoreError := false
 		line := scanner.Text()
 
-		// skip blank and comment lines
 		line = strings.Trim(line, " ")
+		if removeSection.MatchString(line) {
+			if removeCommands != nil {
+				return nil, nil, errors.New(multipleRemoveSectionsError)
+			}
+			removeCommands = []command.Command{}
+			continue
+		}
+
+		// skip blank and comment lines
 		if len(line) == 0 || strings.HasPrefix(line, commentChar) {
 			continue
 		}
@@ -213,19 +246,23 @@ func (parser *AddOnParser) parseCommands(scanner *bufio.Scanner) ([]command.Comm
 		if strings.Contains(line, evaluationChar) {
 			cmdToken, err := minishiftStrings.SplitAndTrim(line, evaluationChar)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			outputVariable, line = cmdToken[0], cmdToken[1]
 		}
 		newCommand, err := parser.handler.Handle(parser.handler, line, ignoreError, outputVariable)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		commands = append(commands, newCommand)
+		if removeCommands != nil {
+			removeCommands = append(removeCommands, newCommand)
+		} else {
+			commands = append(commands, newCommand)
+		}
 	}
 
-	return commands, nil
+	return commands, removeCommands, nil
 }
 
 func createMetaData(header []string) (addon.AddOnMeta, error) {
@@ -277,6 +277,36 @@ func Test_multiple_addon_definitions_create_error(t *testing.T) {
 func TestInvalidAddonDirAndName(t *testing.T) {
 	testAddonName := "addon-dir-mismatch"
 	testAddonDir := filepath.Join(basepath, "..", "..", "..", "..", "test", "testdata", "testaddons", testAddonName)
-	_, _, err := testParser.getAddOnContent(testAddonDir, ".addon")
+	_, _, _, err := testParser.getAddOnContent(testAddonDir, ".addon")
 	assert.EqualError(t, err, "Add-on directory name should match to addon name")
 }
+
+var addOnWithRemoveSection = `# Name: acme
+# Description: Acme add-on
+
+oc new-project acme
+oc new-app acme
+
+# Remove:
+oc delete project acme
+`
+
+func Test_remove_section_is_parsed_into_remove_commands(t *testing.T) {
+	_, commands, removeCommands, err := testParser.parseAddOnSections(strings.NewReader(addOnWithRemoveSection))
+	assert.NoError(t, err, "Error in parsing addon content")
+
+	assert.Len(t, commands, 2)
+	assert.Len(t, removeCommands, 1)
+}
+
+func Test_content_without_remove_section_has_no_remove_commands(t *testing.T) {
+	_, _, removeCommands, err := testParser.parseAddOnSections(strings.NewReader(anyuid))
+	assert.NoError(t, err, "Error in parsing addon content")
+
+	assert.Nil(t, removeCommands)
+}
+
+func Test_multiple_remove_sections_lead_to_error(t *testing.T) {
+	_, _, _, err := testParser.parseAddOnSections(strings.NewReader(addOnWithRemoveSection + "# Remove:\noc delete project foo\n"))
+	assert.EqualError(t, err, multipleRemoveSectionsError)
+}
@@ -32,6 +32,7 @@ const (
 	sshCommand       = "ssh"
 	echoCommand      = "echo"
 	catCommand       = "cat"
+	patchCommand     = "patch"
 )
 
 type CommandHandler interface {
@@ -142,3 +143,14 @@ func (c *CatCommandHandler) Parse(s string, ignoreError bool, outputVariable str
 	}
 	return nil
 }
+
+type PatchCommandHandler struct {
+	*defaultCommandHandler
+}
+
+func (c *PatchCommandHandler) Parse(s string, ignoreError bool, outputVariable string) command.Command {
+	if strings.HasPrefix(s, patchCommand) {
+		return command.NewPatchCommand(s, ignoreError)
+	}
+	return nil
+}
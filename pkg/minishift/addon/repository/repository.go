@@ -0,0 +1,288 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/util/archive"
+	"github.com/minishift/minishift/pkg/util/filehelper"
+)
+
+const (
+	defaultRef = "default"
+)
+
+var (
+	archiveSuffixes = []string{".tar.gz", ".tgz", ".zip"}
+	validName       = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	// validPathComponent matches the repository names and refs which can be used as directory name in the cache
+	validPathComponent = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// Repository is a remote collection of add-ons, either a git repository or an HTTP(S) archive.
+type Repository struct {
+	Name string
+	URL  string
+	Ref  string
+}
+
+// NewRepository creates a repository for the specified URL. If name is empty, it is derived from the URL.
+// ref pins a git branch, tag or commit. It is not supported for archive repositories, the URL of which already identifies the version.
+func NewRepository(url string, name string, ref string) (*Repository, error) {
+	if !isGitURL(url) && !isArchiveURL(url) {
+		return nil, fmt.Errorf("'%s' is neither a git repository nor a .tar.gz, .tgz or .zip archive URL", url)
+	}
+	if ref != "" && !isGitURL(url) {
+		return nil, fmt.Errorf("A ref can only be specified for git repositories")
+	}
+
+	if name == "" {
+		name = nameFromURL(url)
+	}
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("'%s' is not a valid repository name", name)
+	}
+
+	return &Repository{Name: name, URL: url, Ref: ref}, nil
+}
+
+// IsGit returns true if the repository is a git repository, false if it is an archive.
+func (r *Repository) IsGit() bool {
+	return isGitURL(r.URL)
+}
+
+// Fetch makes the content of the repository at the specified ref available in the cache directory and returns its location.
+// If ref is empty, the ref the repository is pinned to is used. Already cached content is reused unless refresh is true.
+func (r *Repository) Fetch(cacheDir string, ref string, refresh bool) (string, error) {
+	if ref == "" {
+		ref = r.Ref
+	}
+	if ref != "" && !r.IsGit() {
+		return "", fmt.Errorf("Repository '%s' is an archive and cannot be fetched at ref '%s'", r.Name, ref)
+	}
+
+	dir, err := r.cacheDir(cacheDir, ref)
+	if err != nil {
+		return "", err
+	}
+	if filehelper.IsDirectory(dir) {
+		if !refresh {
+			return dir, nil
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0777); err != nil {
+		return "", err
+	}
+
+	// fetch into a temporary directory first, so that a failed fetch never leaves a partial cache entry
+	tmpDir, err := ioutil.TempDir(filepath.Dir(dir), ".fetch-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if r.IsGit() {
+		err = cloneGit(r.URL, ref, tmpDir)
+	} else {
+		err = downloadArchive(r.URL, tmpDir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Error fetching repository '%s': %v", r.Name, err)
+	}
+
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// cacheDir returns the directory the repository is cached in at the specified ref. The name and the ref are
+// validated, since the directory gets removed on refresh and must not point outside of the cache directory.
+func (r *Repository) cacheDir(cacheDir string, ref string) (string, error) {
+	if ref == "" {
+		ref = defaultRef
+	}
+	ref = strings.Replace(ref, "/", "_", -1)
+	if !isValidPathComponent(r.Name) {
+		return "", fmt.Errorf("'%s' is not a valid repository name", r.Name)
+	}
+	if !isValidPathComponent(ref) {
+		return "", fmt.Errorf("'%s' is not a valid ref of repository '%s'", ref, r.Name)
+	}
+	return filepath.Join(cacheDir, r.Name, ref), nil
+}
+
+func isValidPathComponent(name string) bool {
+	return validPathComponent.MatchString(name) && name != "." && name != ".."
+}
+
+// FindAddOn returns the directory of the add-on with the specified name within dir. An add-on directory is a
+// directory named after the add-on containing a '<name>.addon' file.
+func FindAddOn(dir string, name string) (string, error) {
+	var found string
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if found == "" && info.IsDir() && info.Name() == name && filehelper.Exists(filepath.Join(p, name+".addon")) {
+			found = p
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("No add-on named '%s' found", name)
+	}
+	return found, nil
+}
+
+// Resolve looks up the add-on with the specified name in the repositories, in the order they are specified,
+// and returns the directory of the first match. If ref is not empty, it overrides the refs the repositories are pinned to.
+func Resolve(repositories []Repository, cacheDir string, name string, ref string) (string, error) {
+	if len(repositories) == 0 {
+		return "", fmt.Errorf("No add-on repositories are configured")
+	}
+
+	for _, repo := range repositories {
+		if ref != "" && !repo.IsGit() {
+			continue
+		}
+		dir, err := repo.Fetch(cacheDir, ref, false)
+		if err != nil {
+			return "", err
+		}
+		if addOnDir, err := FindAddOn(dir, name); err == nil {
+			return addOnDir, nil
+		}
+	}
+	return "", fmt.Errorf("No add-on named '%s' found in the configured repositories", name)
+}
+
+// SplitNameAndRef splits an add-on reference of the form 'name@ref' into its parts.
+func SplitNameAndRef(reference string) (string, string) {
+	parts := strings.SplitN(reference, "@", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return reference, ""
+}
+
+func isGitURL(url string) bool {
+	if strings.HasPrefix(url, "git@") || strings.HasPrefix(url, "git://") || strings.HasPrefix(url, "ssh://") {
+		return true
+	}
+	return (strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "file://")) &&
+		strings.HasSuffix(url, ".git")
+}
+
+func isArchiveURL(url string) bool {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return false
+	}
+	for _, suffix := range archiveSuffixes {
+		if strings.HasSuffix(url, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func nameFromURL(url string) string {
+	name := path.Base(strings.Replace(url, ":", "/", -1))
+	for _, suffix := range append(archiveSuffixes, ".git") {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return name
+}
+
+func cloneGit(url string, ref string, targetDir string) error {
+	if err := runGit("", "clone", "--quiet", url, targetDir); err != nil {
+		return err
+	}
+	if ref != "" {
+		return runGit(targetDir, "checkout", "--quiet", ref)
+	}
+	return nil
+}
+
+func runGit(dir string, args ...string) error {
+	var out bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("'git %s' failed: %v %s", strings.Join(args, " "), err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
+
+func downloadArchive(url string, targetDir string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Download of '%s' returned '%s'", url, resp.Status)
+	}
+
+	archiveFile := filepath.Join(targetDir, ".archive")
+	file, err := os.Create(archiveFile)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, resp.Body)
+	file.Close()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archiveFile)
+
+	if strings.HasSuffix(url, ".zip") {
+		return archive.Unzip(archiveFile, targetDir)
+	}
+
+	tarFile := archiveFile + ".tar"
+	if err := archive.Ungzip(archiveFile, tarFile); err != nil {
+		return err
+	}
+	defer os.Remove(tarFile)
+	return archive.Untar(tarFile, targetDir)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testAddOn = "# Name: foo\n# Description: Test add-on\n\necho foo\n"
+
+func Test_repository_name_is_derived_from_url(t *testing.T) {
+	var tests = []struct {
+		url  string
+		name string
+	}{
+		{"https://github.com/minishift/minishift-addons.git", "minishift-addons"},
+		{"git@github.com:minishift/minishift-addons.git", "minishift-addons"},
+		{"https://example.com/addons/team-addons-1.2.tar.gz", "team-addons-1.2"},
+		{"https://example.com/addons/team.zip", "team"},
+	}
+
+	for _, test := range tests {
+		repo, err := NewRepository(test.url, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, test.name, repo.Name)
+	}
+}
+
+func Test_invalid_repositories_are_rejected(t *testing.T) {
+	_, err := NewRepository("https://example.com/addons", "", "")
+	assert.Error(t, err, "URL is neither git repository nor archive")
+
+	_, err = NewRepository("https://example.com/addons.tar.gz", "", "v1.0")
+	assert.Error(t, err, "Archives cannot be pinned to a ref")
+
+	_, err = NewRepository("https://example.com/addons.git", "../foo", "")
+	assert.Error(t, err, "Name must not contain path separators")
+}
+
+func Test_cache_dir_stays_within_the_cache(t *testing.T) {
+	repo := Repository{Name: "..", URL: "https://example.com/addons.git"}
+	_, err := repo.Fetch("/cache", "", true)
+	assert.EqualError(t, err, "'..' is not a valid repository name")
+
+	repo = Repository{Name: "team", URL: "https://example.com/addons.git"}
+	_, err = repo.Fetch("/cache", "..", true)
+	assert.EqualError(t, err, "'..' is not a valid ref of repository 'team'")
+
+	dir, err := repo.cacheDir("/cache", "release/v1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("/cache", "team", "release_v1.0"), dir)
+}
+
+func Test_split_name_and_ref(t *testing.T) {
+	name, ref := SplitNameAndRef("foo@v1.0")
+	assert.Equal(t, "foo", name)
+	assert.Equal(t, "v1.0", ref)
+
+	name, ref = SplitNameAndRef("foo")
+	assert.Equal(t, "foo", name)
+	assert.Equal(t, "", ref)
+}
+
+func Test_archive_repository_is_fetched_and_cached(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(createTarGz(t, map[string]string{"addons/foo/foo.addon": testAddOn}))
+	}))
+	defer server.Close()
+
+	cacheDir, err := ioutil.TempDir("", "minishift-test-addon-repos-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	repo, err := NewRepository(server.URL+"/team-addons.tar.gz", "", "")
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		addOnDir, err := Resolve([]Repository{*repo}, cacheDir, "foo", "")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(cacheDir, "team-addons", defaultRef, "addons", "foo"), addOnDir)
+	}
+	assert.Equal(t, 1, requests, "Repository should only be downloaded once")
+
+	_, err = Resolve([]Repository{*repo}, cacheDir, "bar", "")
+	assert.Error(t, err)
+
+	_, err = repo.Fetch(cacheDir, "", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests, "Refresh should download the repository again")
+}
+
+func createTarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		assert.NoError(t, err)
+		_, err = tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
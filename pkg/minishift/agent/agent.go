@@ -0,0 +1,154 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minishift/minishift/pkg/util/logging"
+)
+
+var logger = logging.For("agent")
+
+// Task is a unit of work the agent performs periodically, such as reconciling a changed IP of the VM.
+type Task struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// TaskStatus records the outcome of the last run of a task.
+type TaskStatus struct {
+	Name    string
+	LastRun time.Time
+	Error   string `json:",omitempty"`
+}
+
+// Status is the status the agent reports in its status file.
+type Status struct {
+	PID     int
+	Started time.Time
+	Tasks   []TaskStatus
+}
+
+// Agent runs its tasks in the specified interval. Tasks are run one after the other in the order they were
+// specified, so that they never access the VM or the instance state concurrently.
+type Agent struct {
+	tasks      []Task
+	statusPath string
+
+	statusLock sync.Mutex
+	status     Status
+}
+
+// New creates an agent running the specified tasks. If statusPath is not empty, the status of the agent is written
+// to the file after every run of a task.
+func New(statusPath string, tasks ...Task) *Agent {
+	status := Status{PID: os.Getpid(), Started: time.Now()}
+	for _, task := range tasks {
+		status.Tasks = append(status.Tasks, TaskStatus{Name: task.Name})
+	}
+	return &Agent{tasks: tasks, statusPath: statusPath, status: status}
+}
+
+// Run runs each task right away and then in its interval, until stop is closed.
+func (a *Agent) Run(stop <-chan struct{}) {
+	next := make([]time.Time, len(a.tasks))
+	for {
+		now := time.Now()
+		wakeUp := now.Add(time.Hour)
+		for i, task := range a.tasks {
+			if !now.Before(next[i]) {
+				a.runTask(i, task)
+				next[i] = time.Now().Add(task.Interval)
+			}
+			if next[i].Before(wakeUp) {
+				wakeUp = next[i]
+			}
+		}
+
+		timer := time.NewTimer(wakeUp.Sub(time.Now()))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Status returns the status of the agent and its tasks.
+func (a *Agent) Status() Status {
+	a.statusLock.Lock()
+	defer a.statusLock.Unlock()
+
+	status := a.status
+	status.Tasks = append([]TaskStatus(nil), a.status.Tasks...)
+	return status
+}
+
+func (a *Agent) runTask(i int, task Task) {
+	err := task.Run()
+	if err != nil {
+		logger.Errorf("Error running agent task %s: %v", task.Name, err)
+	}
+
+	a.statusLock.Lock()
+	a.status.Tasks[i].LastRun = time.Now()
+	a.status.Tasks[i].Error = ""
+	if err != nil {
+		a.status.Tasks[i].Error = err.Error()
+	}
+	a.statusLock.Unlock()
+
+	if a.statusPath != "" {
+		if err := WriteStatus(a.statusPath, a.Status()); err != nil {
+			logger.Errorf("Error writing the agent status to %s: %v", a.statusPath, err)
+		}
+	}
+}
+
+// WriteStatus writes the status of the agent to the specified file.
+func WriteStatus(path string, status Status) error {
+	content, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// ReadStatus reads the status of the agent from the specified file.
+func ReadStatus(path string) (*Status, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{}
+	if err := json.Unmarshal(content, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tasks_Run_In_Their_Interval_Until_Stopped(t *testing.T) {
+	runs := make(chan string, 100)
+	fast := Task{Name: "fast", Interval: 10 * time.Millisecond, Run: func() error {
+		runs <- "fast"
+		return nil
+	}}
+	slow := Task{Name: "slow", Interval: time.Hour, Run: func() error {
+		runs <- "slow"
+		return nil
+	}}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		New("", fast, slow).Run(stop)
+		close(done)
+	}()
+
+	counts := map[string]int{}
+	for counts["fast"] < 3 {
+		counts[<-runs]++
+	}
+	close(stop)
+	<-done
+
+	assert.Equal(t, 1, counts["slow"], "Slow task should only run once right away")
+}
+
+func Test_Status_Records_Last_Run_And_Error(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "minishift-test-agent-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(tmpDir)
+	statusPath := filepath.Join(tmpDir, "agent.json")
+
+	failing := Task{Name: "failing", Interval: time.Hour, Run: func() error {
+		return errors.New("VM not reachable")
+	}}
+	agent := New(statusPath, failing)
+	agent.runTask(0, failing)
+
+	status, err := ReadStatus(statusPath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.Getpid(), status.PID)
+	assert.Len(t, status.Tasks, 1)
+	assert.Equal(t, "failing", status.Tasks[0].Name)
+	assert.Equal(t, "VM not reachable", status.Tasks[0].Error)
+	assert.False(t, status.Tasks[0].LastRun.IsZero())
+}
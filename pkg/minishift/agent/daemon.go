@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/os/process"
+)
+
+// EnsureDaemonRunning starts the agent as background process, unless it is already running.
+func EnsureDaemonRunning() error {
+	if IsRunning() {
+		logger.Debugf("agent running with pid %d", config.InstanceStateConfig.AgentPID)
+		return nil
+	}
+
+	pid, err := process.StartMinishiftDaemon(Args()...)
+	if err != nil {
+		return err
+	}
+
+	config.InstanceStateConfig.AgentPID = pid
+	return config.InstanceStateConfig.Write()
+}
+
+// Stop stops the agent, if it is running.
+func Stop() error {
+	if !IsRunning() {
+		return nil
+	}
+
+	if err := process.Kill(config.InstanceStateConfig.AgentPID); err != nil {
+		return err
+	}
+
+	config.InstanceStateConfig.AgentPID = 0
+	return config.InstanceStateConfig.Write()
+}
+
+// IsRunning returns true if the agent is running in the background.
+func IsRunning() bool {
+	return process.IsRunning(config.InstanceStateConfig.AgentPID)
+}
+
+// Args returns the arguments with which the Minishift binary runs the agent of the current profile.
+func Args() []string {
+	return []string{
+		"daemon",
+		"run",
+		"--profile",
+		constants.ProfileName}
+}
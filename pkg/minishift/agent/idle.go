@@ -0,0 +1,142 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ActivityPorts are the ports of the VM which connections from the host count as activity: the API server,
+	// the Docker daemon and the router.
+	ActivityPorts = []int{8443, 2376, 80, 443}
+)
+
+// IdleTracker decides when the VM has been idle for longer than the configured timeout.
+type IdleTracker struct {
+	timeout    time.Duration
+	lastActive time.Time
+}
+
+// NewIdleTracker creates a tracker which considers the VM to be active at the specified time.
+func NewIdleTracker(timeout time.Duration, now time.Time) *IdleTracker {
+	return &IdleTracker{timeout: timeout, lastActive: now}
+}
+
+// Observe records whether the VM was active at the specified time. Returns true once the VM has been idle for
+// longer than the timeout.
+func (t *IdleTracker) Observe(active bool, now time.Time) bool {
+	if active {
+		t.lastActive = now
+		return false
+	}
+	return now.Sub(t.lastActive) > t.timeout
+}
+
+// SetTimeout changes the timeout after which the VM is considered to be idle.
+func (t *IdleTracker) SetTimeout(timeout time.Duration) {
+	t.timeout = timeout
+}
+
+// Reset considers the VM to be active at the specified time, for example after it was started.
+func (t *IdleTracker) Reset(now time.Time) {
+	t.lastActive = now
+}
+
+// IdleFor returns how long the VM has been idle at the specified time.
+func (t *IdleTracker) IdleFor(now time.Time) time.Duration {
+	return now.Sub(t.lastActive)
+}
+
+// ConnectionsCommand returns the command listing the TCP connections of the VM. The kernel tables are readable by all
+// users and, unlike netstat, always available.
+func ConnectionsCommand() string {
+	return "cat /proc/net/tcp /proc/net/tcp6 2>/dev/null"
+}
+
+// CountHostConnections counts the established connections listed in the /proc/net/tcp and /proc/net/tcp6 tables,
+// which originate from the specified IP of the host and target one of the specified ports of the VM.
+func CountHostConnections(tcpTables string, hostIP string, ports []int) int {
+	host := net.ParseIP(hostIP)
+	if host == nil {
+		return 0
+	}
+
+	count := 0
+	for _, line := range strings.Split(tcpTables, "\n") {
+		// sl local_address rem_address st ...
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[3] != tcpEstablished {
+			continue
+		}
+
+		_, localPort, err := parseAddress(fields[1])
+		if err != nil || !containsPort(ports, localPort) {
+			continue
+		}
+		remoteIP, _, err := parseAddress(fields[2])
+		if err != nil || !remoteIP.Equal(host) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// tcpEstablished is the state of established connections in the kernel TCP tables
+const tcpEstablished = "01"
+
+// parseAddress parses an address of the kernel TCP tables, like 0100007F:1F90. The IP is written as hex encoded
+// 32 bit words in the byte order of the VM, which is little endian.
+func parseAddress(address string) (net.IP, int, error) {
+	parts := strings.Split(address, ":")
+	if len(parts) != 2 || (len(parts[0]) != 8 && len(parts[0]) != 32) {
+		return nil, 0, fmt.Errorf("Invalid address '%s'", address)
+	}
+
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	raw, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return nil, 0, err
+	}
+	ip := make(net.IP, len(raw))
+	for word := 0; word < len(raw); word += 4 {
+		for i := 0; i < 4; i++ {
+			ip[word+i] = raw[word+3-i]
+		}
+	}
+	// IPv4 connections to IPv6 sockets are listed as ::ffff:<IPv4>, which Equal treats the same as the IPv4 address
+	return ip, int(port), nil
+}
+
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
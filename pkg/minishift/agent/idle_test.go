@@ -0,0 +1,55 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTCPTables = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 6463A8C0:0016 0163A8C0:D0AE 01 00000000:00000000 02:0008E3C1 00000000     0        0 20735 2 0000000000000000 20 4 31 10 -1
+   1: 6463A8C0:0948 0163A8C0:D11A 06 00000000:00000000 03:00000E2B 00000000     0        0 0 3 0000000000000000
+   2: 0100007F:20FB 0100007F:A8CA 01 00000000:00000000 00:00000000 00000000     0        0 24680 1 0000000000000000 20 4 30 10 -1
+  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0000000000000000FFFF00006463A8C0:20FB 0000000000000000FFFF00000163A8C0:D16C 01 00000000:00000000 00:00000000 00000000     0        0 25781 1 0000000000000000 20 4 30 10 -1
+   1: 0000000000000000FFFF000001001EAC:01BB 0000000000000000FFFF0000030011AC:9C48 01 00000000:00000000 00:00000000 00000000     0        0 25782 1 0000000000000000 20 4 30 10 -1
+`
+
+func Test_Only_Established_Connections_From_The_Host_Count(t *testing.T) {
+	assert.Equal(t, 1, CountHostConnections(testTCPTables, "192.168.99.1", ActivityPorts))
+	assert.Equal(t, 0, CountHostConnections(testTCPTables, "192.168.99.1", []int{2376}))
+	assert.Equal(t, 1, CountHostConnections(testTCPTables, "192.168.99.1", []int{22}))
+	assert.Equal(t, 1, CountHostConnections(testTCPTables, "172.17.0.3", []int{443}))
+	assert.Equal(t, 0, CountHostConnections("", "192.168.99.1", ActivityPorts))
+}
+
+func Test_VM_Is_Idle_After_Timeout_Without_Activity(t *testing.T) {
+	start := time.Now()
+	tracker := NewIdleTracker(30*time.Minute, start)
+
+	assert.False(t, tracker.Observe(false, start.Add(20*time.Minute)))
+	assert.False(t, tracker.Observe(true, start.Add(25*time.Minute)), "Activity should reset the idle time")
+	assert.False(t, tracker.Observe(false, start.Add(50*time.Minute)))
+	assert.True(t, tracker.Observe(false, start.Add(56*time.Minute)))
+	assert.Equal(t, 31*time.Minute, tracker.IdleFor(start.Add(56*time.Minute)))
+
+	tracker.Reset(start.Add(56 * time.Minute))
+	assert.False(t, tracker.Observe(false, start.Add(60*time.Minute)))
+}
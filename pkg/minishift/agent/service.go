@@ -0,0 +1,138 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+)
+
+// ServiceName returns the name under which the agent of the profile is registered with the service manager of the
+// host.
+func ServiceName(profile string) string {
+	return fmt.Sprintf("minishift-agent-%s", profile)
+}
+
+// LaunchdLabel returns the label of the launchd job running the agent of the profile.
+func LaunchdLabel(profile string) string {
+	return fmt.Sprintf("io.minishift.agent.%s", profile)
+}
+
+// SystemdUnit returns a systemd user unit running the agent of the profile, which is restarted if it fails.
+func SystemdUnit(profile string, executable string, args []string, env []string) string {
+	var unit bytes.Buffer
+	fmt.Fprintln(&unit, "[Unit]")
+	fmt.Fprintln(&unit, fmt.Sprintf("Description=Minishift agent of profile %s", profile))
+	fmt.Fprintln(&unit)
+	fmt.Fprintln(&unit, "[Service]")
+	for _, variable := range env {
+		fmt.Fprintln(&unit, fmt.Sprintf("Environment=%s", systemdQuote(variable)))
+	}
+	execStart := []string{systemdQuote(executable)}
+	for _, arg := range args {
+		execStart = append(execStart, systemdQuote(arg))
+	}
+	fmt.Fprintln(&unit, fmt.Sprintf("ExecStart=%s", strings.Join(execStart, " ")))
+	fmt.Fprintln(&unit, "Restart=on-failure")
+	fmt.Fprintln(&unit, "RestartSec=10")
+	fmt.Fprintln(&unit)
+	fmt.Fprintln(&unit, "[Install]")
+	fmt.Fprintln(&unit, "WantedBy=default.target")
+	return unit.String()
+}
+
+// LaunchdPlist returns a launchd property list running the agent of the profile at login, which is restarted if it
+// fails.
+func LaunchdPlist(profile string, executable string, args []string, env []string) string {
+	var plist bytes.Buffer
+	fmt.Fprintln(&plist, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(&plist, `<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">`)
+	fmt.Fprintln(&plist, `<plist version="1.0">`)
+	fmt.Fprintln(&plist, "<dict>")
+	fmt.Fprintln(&plist, "  <key>Label</key>")
+	fmt.Fprintln(&plist, fmt.Sprintf("  <string>%s</string>", xmlEscape(LaunchdLabel(profile))))
+	fmt.Fprintln(&plist, "  <key>ProgramArguments</key>")
+	fmt.Fprintln(&plist, "  <array>")
+	for _, arg := range append([]string{executable}, args...) {
+		fmt.Fprintln(&plist, fmt.Sprintf("    <string>%s</string>", xmlEscape(arg)))
+	}
+	fmt.Fprintln(&plist, "  </array>")
+	if len(env) > 0 {
+		fmt.Fprintln(&plist, "  <key>EnvironmentVariables</key>")
+		fmt.Fprintln(&plist, "  <dict>")
+		for _, variable := range env {
+			parts := strings.SplitN(variable, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			fmt.Fprintln(&plist, fmt.Sprintf("    <key>%s</key>", xmlEscape(parts[0])))
+			fmt.Fprintln(&plist, fmt.Sprintf("    <string>%s</string>", xmlEscape(parts[1])))
+		}
+		fmt.Fprintln(&plist, "  </dict>")
+	}
+	fmt.Fprintln(&plist, "  <key>RunAtLoad</key>")
+	fmt.Fprintln(&plist, "  <true/>")
+	fmt.Fprintln(&plist, "  <key>KeepAlive</key>")
+	fmt.Fprintln(&plist, "  <dict>")
+	fmt.Fprintln(&plist, "    <key>SuccessfulExit</key>")
+	fmt.Fprintln(&plist, "    <false/>")
+	fmt.Fprintln(&plist, "  </dict>")
+	fmt.Fprintln(&plist, "</dict>")
+	fmt.Fprintln(&plist, "</plist>")
+	return plist.String()
+}
+
+// TaskSchedulerCommand returns the command line the Task Scheduler task running the agent executes.
+func TaskSchedulerCommand(executable string, args []string) string {
+	command := []string{windowsQuote(executable)}
+	for _, arg := range args {
+		command = append(command, windowsQuote(arg))
+	}
+	return strings.Join(command, " ")
+}
+
+// serviceEnvironment returns the environment the service manager passes to the agent, so that the agent uses the
+// same Minishift home directory as the command which registered it.
+func serviceEnvironment() []string {
+	return []string{fmt.Sprintf("%s=%s", constants.MiniShiftHomeEnv, constants.GetMinishiftHomeDir())}
+}
+
+func systemdQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"\\") {
+		return s
+	}
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "\"", "\\\"", -1)
+	return fmt.Sprintf("\"%s\"", s)
+}
+
+func windowsQuote(s string) string {
+	if !strings.ContainsAny(s, " \t") {
+		return s
+	}
+	return fmt.Sprintf("\"%s\"", s)
+}
+
+func xmlEscape(s string) string {
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+)
+
+// ServiceManager is the service manager of the host with which the agent can be registered.
+const ServiceManager = "launchd"
+
+// InstallService registers the agent of the current profile as launchd agent of the user and starts it.
+func InstallService(executable string) error {
+	path := serviceFile()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	plist := LaunchdPlist(constants.ProfileName, executable, Args(), serviceEnvironment())
+	if err := ioutil.WriteFile(path, []byte(plist), 0644); err != nil {
+		return err
+	}
+
+	return launchctl("load", "-w", path)
+}
+
+// UninstallService stops the launchd agent of the current profile and removes it.
+func UninstallService() error {
+	if !IsServiceInstalled() {
+		return nil
+	}
+
+	if err := launchctl("unload", "-w", serviceFile()); err != nil {
+		return err
+	}
+	return os.Remove(serviceFile())
+}
+
+// IsServiceInstalled returns true if the agent of the current profile is registered as launchd agent.
+func IsServiceInstalled() bool {
+	_, err := os.Stat(serviceFile())
+	return err == nil
+}
+
+func serviceFile() string {
+	return filepath.Join(constants.GetHomeDir(), "Library", "LaunchAgents", LaunchdLabel(constants.ProfileName)+".plist")
+}
+
+func launchctl(args ...string) error {
+	out, err := exec.Command("launchctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Error running 'launchctl %s': %v %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
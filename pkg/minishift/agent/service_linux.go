@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+)
+
+// ServiceManager is the service manager of the host with which the agent can be registered.
+const ServiceManager = "systemd"
+
+// InstallService registers the agent of the current profile as systemd user unit and starts it.
+func InstallService(executable string) error {
+	path := serviceFile()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	unit := SystemdUnit(constants.ProfileName, executable, Args(), serviceEnvironment())
+	if err := ioutil.WriteFile(path, []byte(unit), 0644); err != nil {
+		return err
+	}
+
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	return systemctl("enable", "--now", serviceUnitName())
+}
+
+// UninstallService stops the systemd user unit of the agent of the current profile and removes it.
+func UninstallService() error {
+	if !IsServiceInstalled() {
+		return nil
+	}
+
+	if err := systemctl("disable", "--now", serviceUnitName()); err != nil {
+		return err
+	}
+	if err := os.Remove(serviceFile()); err != nil {
+		return err
+	}
+	return systemctl("daemon-reload")
+}
+
+// IsServiceInstalled returns true if the agent of the current profile is registered as systemd user unit.
+func IsServiceInstalled() bool {
+	_, err := os.Stat(serviceFile())
+	return err == nil
+}
+
+func serviceUnitName() string {
+	return ServiceName(constants.ProfileName) + ".service"
+}
+
+func serviceFile() string {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		configDir = filepath.Join(constants.GetHomeDir(), ".config")
+	}
+	return filepath.Join(configDir, "systemd", "user", serviceUnitName())
+}
+
+func systemctl(args ...string) error {
+	out, err := exec.Command("systemctl", append([]string{"--user"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Error running 'systemctl --user %s': %v %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testArgs = []string{"daemon", "run", "--profile", "dev"}
+	testEnv  = []string{"MINISHIFT_HOME=/home/john doe/.minishift"}
+)
+
+func Test_Systemd_Unit_Runs_Agent(t *testing.T) {
+	expected := `[Unit]
+Description=Minishift agent of profile dev
+
+[Service]
+Environment="MINISHIFT_HOME=/home/john doe/.minishift"
+ExecStart=/usr/local/bin/minishift daemon run --profile dev
+Restart=on-failure
+RestartSec=10
+
+[Install]
+WantedBy=default.target
+`
+	assert.Equal(t, expected, SystemdUnit("dev", "/usr/local/bin/minishift", testArgs, testEnv))
+}
+
+func Test_Launchd_Plist_Runs_Agent_At_Load(t *testing.T) {
+	plist := LaunchdPlist("dev", "/Users/john/bin/minishift & co", testArgs, testEnv)
+
+	assert.Contains(t, plist, "<string>io.minishift.agent.dev</string>")
+	assert.Contains(t, plist, "<string>/Users/john/bin/minishift &amp; co</string>\n    <string>daemon</string>\n    <string>run</string>")
+	assert.Contains(t, plist, "<key>MINISHIFT_HOME</key>\n    <string>/home/john doe/.minishift</string>")
+	assert.Contains(t, plist, "<key>RunAtLoad</key>\n  <true/>")
+}
+
+func Test_Task_Scheduler_Command_Quotes_Executable(t *testing.T) {
+	assert.Equal(t, `"C:\Program Files\minishift.exe" daemon run --profile dev`, TaskSchedulerCommand(`C:\Program Files\minishift.exe`, testArgs))
+}
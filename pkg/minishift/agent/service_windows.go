@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+)
+
+// ServiceManager is the service manager of the host with which the agent can be registered.
+const ServiceManager = "Task Scheduler"
+
+// InstallService registers the agent of the current profile as Task Scheduler task run at logon and starts it.
+// The task runs with the environment of the user, hence a custom MINISHIFT_HOME needs to be set as user variable.
+func InstallService(executable string) error {
+	err := schtasks("/Create", "/F", "/TN", taskName(), "/SC", "ONLOGON", "/RL", "LIMITED",
+		"/TR", TaskSchedulerCommand(executable, Args()))
+	if err != nil {
+		return err
+	}
+	return schtasks("/Run", "/TN", taskName())
+}
+
+// UninstallService ends the Task Scheduler task of the agent of the current profile and removes it.
+func UninstallService() error {
+	if !IsServiceInstalled() {
+		return nil
+	}
+
+	// ending a task which is not running fails, which is fine
+	schtasks("/End", "/TN", taskName())
+	return schtasks("/Delete", "/F", "/TN", taskName())
+}
+
+// IsServiceInstalled returns true if the agent of the current profile is registered as Task Scheduler task.
+func IsServiceInstalled() bool {
+	return schtasks("/Query", "/TN", taskName()) == nil
+}
+
+func taskName() string {
+	return fmt.Sprintf("Minishift\\%s", ServiceName(constants.ProfileName))
+}
+
+func schtasks(args ...string) error {
+	out, err := exec.Command("schtasks", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Error running 'schtasks %s': %v %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// +build !windows
+
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+)
+
+// DefaultAddress returns the path of the unix socket the API is served on, unless specified otherwise.
+func DefaultAddress() string {
+	return filepath.Join(constants.GetMinishiftHomeDir(), "minishift.sock")
+}
+
+// Listen listens on the unix socket at the specified path. The socket is only accessible by the current user.
+func Listen(address string) (net.Listener, error) {
+	// a socket left behind by a server which was killed prevents listening
+	if info, err := os.Lstat(address); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", address); err == nil {
+			conn.Close()
+			return nil, &net.OpError{Op: "listen", Net: "unix", Err: os.ErrExist}
+		}
+		os.Remove(address)
+	}
+
+	// the socket is created with the permissions of the umask, changing them afterwards leaves a window in which
+	// other users can connect
+	oldUmask := syscall.Umask(0177)
+	listener, err := net.Listen("unix", address)
+	syscall.Umask(oldUmask)
+	if err != nil {
+		return nil, err
+	}
+	return listener, nil
+}
@@ -0,0 +1,60 @@
+// +build !windows
+
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_API_Is_Served_On_Unix_Socket(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "minishift-test-api-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(tmpDir)
+	socket := filepath.Join(tmpDir, "minishift.sock")
+
+	listener, err := Listen(socket)
+	assert.NoError(t, err)
+	defer listener.Close()
+	go NewServer(&fakeBackend{}).Serve(listener)
+
+	info, err := os.Stat(socket)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	_, err = Listen(socket)
+	assert.Error(t, err, "Listening on a socket in use should fail")
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+			return net.Dial("unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://minishift/v1/profiles")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// DefaultAddress returns the name of the named pipe the API is served on, unless specified otherwise.
+func DefaultAddress() string {
+	return fmt.Sprintf(`\\.\pipe\minishift-%s`, os.Getenv("USERNAME"))
+}
+
+// Listen listens on the named pipe with the specified name. The pipe is only accessible by the current user.
+func Listen(address string) (net.Listener, error) {
+	// grant access to the owner of the pipe only
+	return winio.ListenPipe(address, &winio.PipeConfig{SecurityDescriptor: "D:P(A;;GA;;;OW)"})
+}
@@ -0,0 +1,192 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+)
+
+const (
+	// Version is the version of the API schema. Incompatible changes of the schema are made under a new version,
+	// which is served alongside the previous ones.
+	Version = "v1"
+)
+
+var (
+	// ErrProfileNotFound is returned by a backend for operations on a profile which does not exist.
+	ErrProfileNotFound = errors.New("Profile does not exist")
+	// ErrVMNotRunning is returned by a backend for operations requiring a running VM.
+	ErrVMNotRunning = errors.New("The VM is not running")
+)
+
+// VersionInfo is returned by GET /version and lets clients discover the supported schema versions.
+type VersionInfo struct {
+	MinishiftVersion string   `json:"minishiftVersion"`
+	APIVersions      []string `json:"apiVersions"`
+}
+
+// Profile is an entry of GET /v1/profiles and of 'minishift profile list --output json'. Memory, CPUs and the
+// driver are only known once the VM of the profile exists, the IP address only while it is running.
+type Profile struct {
+	Name             string            `json:"name"`
+	Active           bool              `json:"active"`
+	VMStatus         string            `json:"vmStatus"`
+	Driver           string            `json:"driver,omitempty"`
+	IP               string            `json:"ip,omitempty"`
+	OpenShiftVersion string            `json:"openshiftVersion,omitempty"`
+	MemoryMB         int               `json:"memoryMB,omitempty"`
+	CPUs             int               `json:"cpus,omitempty"`
+	DiskUsageBytes   int64             `json:"diskUsageBytes"`
+	Protected        bool              `json:"protected,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+}
+
+// Status is returned by GET /v1/profiles/{profile}/status.
+type Status struct {
+	Profile          string `json:"profile"`
+	VMStatus         string `json:"vmStatus"`
+	OpenShiftStatus  string `json:"openshiftStatus"`
+	OpenShiftVersion string `json:"openshiftVersion,omitempty"`
+	IP               string `json:"ip,omitempty"`
+}
+
+// Console is returned by GET /v1/profiles/{profile}/console.
+type Console struct {
+	Profile    string `json:"profile"`
+	ConsoleURL string `json:"consoleURL"`
+}
+
+// DockerEnv is returned by GET /v1/profiles/{profile}/docker-env. It holds the environment variables for using the
+// Docker daemon of the VM.
+type DockerEnv struct {
+	Profile string            `json:"profile"`
+	Env     map[string]string `json:"env"`
+}
+
+// Operation is returned by POST /v1/profiles/{profile}/start and POST /v1/profiles/{profile}/stop once the operation
+// finished.
+type Operation struct {
+	Profile   string `json:"profile"`
+	Operation string `json:"operation"`
+	Output    string `json:"output"`
+}
+
+// Description is returned by GET /v1/profiles/{profile}/describe and printed by 'minishift describe --output json'.
+// It holds everything tools need to integrate with the cluster of a profile.
+type Description struct {
+	Profile       string           `json:"profile"`
+	VMStatus      string           `json:"vmStatus"`
+	Versions      Versions         `json:"versions"`
+	Driver        string           `json:"driver,omitempty"`
+	IP            string           `json:"ip,omitempty"`
+	RoutingSuffix string           `json:"routingSuffix,omitempty"`
+	Nameservers   []string         `json:"nameservers,omitempty"`
+	URLs          *URLs            `json:"urls,omitempty"`
+	Credentials   Credentials      `json:"credentials"`
+	Certificates  []Certificate    `json:"certificates"`
+	HostFolders   []HostFolder     `json:"hostFolders"`
+	AddOns        []AddOn          `json:"addOns"`
+	Events        []LifecycleEvent `json:"events"`
+}
+
+// Versions are the versions of Minishift and of the cluster of a profile.
+type Versions struct {
+	Minishift string `json:"minishift"`
+	OpenShift string `json:"openshift,omitempty"`
+}
+
+// URLs are the endpoints of the cluster of a running profile.
+type URLs struct {
+	Console   string `json:"console"`
+	APIServer string `json:"apiServer"`
+	Docker    string `json:"docker"`
+}
+
+// Credentials describes how to log into the cluster. Passwords are never part of the description.
+type Credentials struct {
+	IdentityProvider string `json:"identityProvider"`
+	Users            []User `json:"users"`
+	Hint             string `json:"hint"`
+}
+
+// User is a user declared in the profile.
+type User struct {
+	Name         string   `json:"name"`
+	HasPassword  bool     `json:"hasPassword"`
+	ClusterRoles []string `json:"clusterRoles,omitempty"`
+}
+
+// HostFolder is a host folder defined for the profile.
+type HostFolder struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Source     string `json:"source"`
+	MountPoint string `json:"mountPoint"`
+	Mounted    bool   `json:"mounted"`
+}
+
+// AddOn is an add-on enabled for the profile.
+type AddOn struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+}
+
+// Certificate is a certificate of the profile and its expiry.
+type Certificate struct {
+	Name     string    `json:"name"`
+	Path     string    `json:"path"`
+	NotAfter time.Time `json:"notAfter"`
+}
+
+// LifecycleEvent is a recent transition of the lifecycle state of the profile, such as a start or a failure.
+type LifecycleEvent struct {
+	State   string    `json:"state"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Error is returned for failed requests. Failed operations carry the reason of the failure and whether the
+// operation can be retried.
+type Error struct {
+	Message   string        `json:"message"`
+	Reason    atexit.Reason `json:"reason,omitempty"`
+	Retryable bool          `json:"retryable,omitempty"`
+}
+
+// OperationError is returned by the backend if the command of an operation failed.
+type OperationError struct {
+	Message string
+	Reason  atexit.Reason
+}
+
+func (e *OperationError) Error() string {
+	return e.Message
+}
+
+// Backend performs the operations exposed by the API.
+type Backend interface {
+	Profiles() ([]Profile, error)
+	Status(profile string) (*Status, error)
+	Console(profile string) (*Console, error)
+	DockerEnv(profile string) (*DockerEnv, error)
+	Describe(profile string) (*Description, error)
+	Start(profile string) (*Operation, error)
+	Stop(profile string) (*Operation, error)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/util/logging"
+	"github.com/minishift/minishift/pkg/version"
+)
+
+var logger = logging.For("api")
+
+// Server serves the API on a local socket, so that tools such as IDE plugins do not need to parse the output of the
+// minishift commands.
+type Server struct {
+	backend Backend
+}
+
+// NewServer creates a server performing the operations with the specified backend.
+func NewServer(backend Backend) *Server {
+	return &Server{backend: backend}
+}
+
+// Serve serves the API on the listener. The call blocks until the listener fails or is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	return http.Serve(listener, s)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/version" {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		writeJSON(w, http.StatusOK, VersionInfo{MinishiftVersion: version.GetMinishiftVersion(), APIVersions: []string{Version}})
+		return
+	}
+
+	prefix := "/" + Version + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Unknown path '%s'", r.URL.Path))
+		return
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "profiles":
+		if allowMethod(w, r, http.MethodGet) {
+			profiles, err := s.backend.Profiles()
+			respond(w, profiles, err)
+		}
+	case len(parts) == 3 && parts[0] == "profiles":
+		s.serveProfile(w, r, parts[1], parts[2])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("Unknown path '%s'", r.URL.Path))
+	}
+}
+
+func (s *Server) serveProfile(w http.ResponseWriter, r *http.Request, profile string, resource string) {
+	switch resource {
+	case "status":
+		if allowMethod(w, r, http.MethodGet) {
+			status, err := s.backend.Status(profile)
+			respond(w, status, err)
+		}
+	case "console":
+		if allowMethod(w, r, http.MethodGet) {
+			console, err := s.backend.Console(profile)
+			respond(w, console, err)
+		}
+	case "docker-env":
+		if allowMethod(w, r, http.MethodGet) {
+			env, err := s.backend.DockerEnv(profile)
+			respond(w, env, err)
+		}
+	case "describe":
+		if allowMethod(w, r, http.MethodGet) {
+			description, err := s.backend.Describe(profile)
+			respond(w, description, err)
+		}
+	case "start":
+		if allowMethod(w, r, http.MethodPost) {
+			operation, err := s.backend.Start(profile)
+			respond(w, operation, err)
+		}
+	case "stop":
+		if allowMethod(w, r, http.MethodPost) {
+			operation, err := s.backend.Stop(profile)
+			respond(w, operation, err)
+		}
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("Unknown resource '%s' of profile '%s'", resource, profile))
+	}
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s is not allowed, use %s", r.Method, method))
+	return false
+}
+
+func respond(w http.ResponseWriter, result interface{}, err error) {
+	switch err {
+	case nil:
+		writeJSON(w, http.StatusOK, result)
+	case ErrProfileNotFound:
+		writeError(w, http.StatusNotFound, err)
+	case ErrVMNotRunning:
+		writeError(w, http.StatusConflict, err)
+	default:
+		if operationErr, ok := err.(*OperationError); ok {
+			writeJSON(w, http.StatusInternalServerError, Error{
+				Message:   operationErr.Message,
+				Reason:    operationErr.Reason,
+				Retryable: operationErr.Reason.IsRetryable(),
+			})
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, Error{Message: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logger.Errorf("Error writing the API response: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBackend struct {
+	started []string
+}
+
+func (b *fakeBackend) Profiles() ([]Profile, error) {
+	return []Profile{{Name: "minishift", Active: true, VMStatus: "Running"}, {Name: "dev", VMStatus: "Stopped"}}, nil
+}
+
+func (b *fakeBackend) Status(profile string) (*Status, error) {
+	if profile != "minishift" {
+		return nil, ErrProfileNotFound
+	}
+	return &Status{Profile: profile, VMStatus: "Running", OpenShiftStatus: "Running", OpenShiftVersion: "v3.11.0", IP: "192.168.99.100"}, nil
+}
+
+func (b *fakeBackend) Console(profile string) (*Console, error) {
+	return nil, ErrVMNotRunning
+}
+
+func (b *fakeBackend) DockerEnv(profile string) (*DockerEnv, error) {
+	return nil, errors.New("SSH connection failed")
+}
+
+func (b *fakeBackend) Describe(profile string) (*Description, error) {
+	return &Description{Profile: profile, VMStatus: "Stopped"}, nil
+}
+
+func (b *fakeBackend) Start(profile string) (*Operation, error) {
+	b.started = append(b.started, profile)
+	return &Operation{Profile: profile, Operation: "start", Output: "OpenShift server started."}, nil
+}
+
+func (b *fakeBackend) Stop(profile string) (*Operation, error) {
+	return nil, &OperationError{Message: "Error running 'stop'", Reason: atexit.NetworkError}
+}
+
+func Test_Version_Lists_Supported_API_Versions(t *testing.T) {
+	resp := request(NewServer(&fakeBackend{}), http.MethodGet, "/version")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var info VersionInfo
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+	assert.Equal(t, []string{Version}, info.APIVersions)
+}
+
+func Test_Profiles_And_Status_Are_Served_As_JSON(t *testing.T) {
+	server := NewServer(&fakeBackend{})
+
+	resp := request(server, http.MethodGet, "/v1/profiles")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	var profiles []Profile
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &profiles))
+	assert.Len(t, profiles, 2)
+	assert.True(t, profiles[0].Active)
+
+	resp = request(server, http.MethodGet, "/v1/profiles/minishift/status")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"profile":"minishift","vmStatus":"Running","openshiftStatus":"Running","openshiftVersion":"v3.11.0","ip":"192.168.99.100"}`, resp.Body.String())
+}
+
+func Test_Errors_Map_To_Status_Codes(t *testing.T) {
+	server := NewServer(&fakeBackend{})
+
+	var tests = []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{http.MethodGet, "/v1/profiles/unknown/status", http.StatusNotFound},
+		{http.MethodGet, "/v1/profiles/minishift/console", http.StatusConflict},
+		{http.MethodGet, "/v1/profiles/minishift/docker-env", http.StatusInternalServerError},
+		{http.MethodGet, "/v1/profiles/minishift/logs", http.StatusNotFound},
+		{http.MethodGet, "/v2/profiles", http.StatusNotFound},
+		{http.MethodPost, "/v1/profiles", http.StatusMethodNotAllowed},
+	}
+	for _, test := range tests {
+		resp := request(server, test.method, test.path)
+		assert.Equal(t, test.code, resp.Code, "Unexpected status for %s %s", test.method, test.path)
+
+		var apiErr Error
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &apiErr))
+		assert.NotEmpty(t, apiErr.Message)
+	}
+}
+
+func Test_Operations_Require_Post(t *testing.T) {
+	backend := &fakeBackend{}
+	server := NewServer(backend)
+
+	resp := request(server, http.MethodGet, "/v1/profiles/dev/start")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, http.MethodPost, resp.Header().Get("Allow"))
+	assert.Empty(t, backend.started)
+
+	resp = request(server, http.MethodPost, "/v1/profiles/dev/start")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, []string{"dev"}, backend.started)
+}
+
+func Test_Failed_Operations_Report_Reason(t *testing.T) {
+	resp := request(NewServer(&fakeBackend{}), http.MethodPost, "/v1/profiles/dev/stop")
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.JSONEq(t, `{"message":"Error running 'stop'","reason":"NetworkError","retryable":true}`, resp.Body.String())
+}
+
+func request(server *Server, method string, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+	return resp
+}
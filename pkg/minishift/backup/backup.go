@@ -0,0 +1,325 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/provision"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/pv"
+)
+
+const (
+	// ManifestFileName is the name of the file describing the content of a backup
+	ManifestFileName = "backup.json"
+
+	// FileExtension is the extension of backup archives
+	FileExtension = ".tar.gz"
+
+	// StagingDirInsideInstance holds the archives while they are copied from and to the VM. It is located on the
+	// persistent disk, since /tmp of the live ISO is kept in memory.
+	StagingDirInsideInstance = "/var/lib/minishift/backups"
+)
+
+var manifestDirInsideInstance = path.Join(StagingDirInsideInstance, "manifest")
+
+// DataDirectories are the directories inside the VM holding the cluster data, namely the etcd data and the
+// directories of the hostPath persistent volumes
+var DataDirectories = []string{
+	path.Join(minishiftConstants.BaseDirInsideInstance, "etcd"),
+	pv.DirInsideInstance,
+}
+
+// Manifest describes a backup.
+type Manifest struct {
+	Profile          string    `json:"profile"`
+	OpenShiftVersion string    `json:"openshift-version"`
+	Created          time.Time `json:"created"`
+	Directories      []string  `json:"directories"`
+	Scheduled        bool      `json:"scheduled,omitempty"`
+}
+
+// Backup is a backup archive on the host.
+type Backup struct {
+	Path     string
+	Size     int64
+	Manifest *Manifest
+}
+
+// ArchivePathInsideInstance returns the path of the archive with the specified name in the staging directory of the VM.
+func ArchivePathInsideInstance(name string) string {
+	return path.Join(StagingDirInsideInstance, name)
+}
+
+// PrepareStaging creates the staging directory inside the VM. Like /tmp, it is writable by all users.
+func PrepareStaging(commander provision.SSHCommander) error {
+	if _, err := commander.SSHCommand(fmt.Sprintf("sudo mkdir -p %[1]s && sudo chmod 1777 %[1]s", StagingDirInsideInstance)); err != nil {
+		return fmt.Errorf("Error creating '%s': %v", StagingDirInsideInstance, err)
+	}
+	return nil
+}
+
+// Create writes an archive of the cluster data to archivePath inside the VM. The containers are paused while the
+// archive is written, so that the data is consistent.
+func Create(commander provision.SSHCommander, archivePath string, manifest Manifest) error {
+	manifest.Directories = DataDirectories
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := PrepareStaging(commander); err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("mkdir -p %s && echo %s | base64 -d > %s",
+		manifestDirInsideInstance, base64.StdEncoding.EncodeToString(content), path.Join(manifestDirInsideInstance, ManifestFileName))
+	if _, err := commander.SSHCommand(cmd); err != nil {
+		return fmt.Errorf("Error writing the backup manifest: %v", err)
+	}
+	defer commander.SSHCommand(fmt.Sprintf("rm -rf %s", manifestDirInsideInstance))
+
+	if _, err := commander.SSHCommand(createCommand(archivePath)); err != nil {
+		return fmt.Errorf("Error archiving the cluster data: %v", err)
+	}
+	return nil
+}
+
+// Restore replaces the cluster data with the content of the archive at archivePath inside the VM. Only the specified
+// directories, as returned by Verify, are extracted. OpenShift is stopped while the data is replaced and started
+// afterwards.
+func Restore(commander provision.SSHCommander, archivePath string, directories []string) error {
+	if _, err := commander.SSHCommand(restoreCommand(archivePath, directories)); err != nil {
+		return fmt.Errorf("Error restoring the cluster data: %v", err)
+	}
+	return nil
+}
+
+func createCommand(archivePath string) string {
+	return fmt.Sprintf("docker ps -q | xargs -r docker pause; "+
+		"sudo tar -czf %s -C %s %s -C / $(for dir in %s; do [ -d $dir ] && echo ${dir#/}; done); rc=$?; "+
+		"docker ps -q --filter status=paused | xargs -r docker unpause; "+
+		"sudo chmod 644 %s; exit $rc",
+		archivePath, manifestDirInsideInstance, ManifestFileName, strings.Join(DataDirectories, " "), archivePath)
+}
+
+func restoreCommand(archivePath string, directories []string) string {
+	var members []string
+	for _, dir := range directories {
+		members = append(members, strings.TrimPrefix(dir, "/"))
+	}
+	return fmt.Sprintf("docker stop %s && docker ps -q --filter name=k8s_ | xargs -r docker stop && "+
+		"sudo rm -rf %s && sudo tar -xzf %s -C / %s; rc=$?; docker start %s; exit $rc",
+		minishiftConstants.OpenshiftContainerName, strings.Join(DataDirectories, " "), archivePath, strings.Join(members, " "),
+		minishiftConstants.OpenshiftContainerName)
+}
+
+// Verify checks that all entries of the backup archive on the host, besides the manifest, are located within the
+// DataDirectories listed in the manifest, so that restoring the archive cannot overwrite anything else in the VM. The
+// data directories contained in the archive are returned.
+func Verify(archivePath string, manifest *Manifest) ([]string, error) {
+	for _, dir := range manifest.Directories {
+		if !contains(DataDirectories, dir) {
+			return nil, fmt.Errorf("The backup '%s' contains the unsupported directory '%s'", archivePath, dir)
+		}
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a Minishift backup: %v", archivePath, err)
+	}
+	defer gz.Close()
+
+	present := make(map[string]bool)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error reading '%s': %v", archivePath, err)
+		}
+
+		name := path.Clean(header.Name)
+		if name == ManifestFileName {
+			continue
+		}
+		dir := containingDirectory(manifest.Directories, name)
+		if dir == "" || hasParentReference(header.Name) {
+			return nil, fmt.Errorf("The backup '%s' contains the entry '%s' outside of the data directories", archivePath, header.Name)
+		}
+		present[dir] = true
+	}
+
+	var directories []string
+	for _, dir := range manifest.Directories {
+		if present[dir] {
+			directories = append(directories, dir)
+		}
+	}
+	return directories, nil
+}
+
+// containingDirectory returns the directory the archive entry name, which is relative to /, belongs to, or the empty
+// string if it is not located within any of the directories.
+func containingDirectory(directories []string, name string) string {
+	for _, dir := range directories {
+		relative := strings.TrimPrefix(dir, "/")
+		if name == relative || strings.HasPrefix(name, relative+"/") {
+			return dir
+		}
+	}
+	return ""
+}
+
+func hasParentReference(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadManifest returns the manifest of the backup archive on the host.
+func ReadManifest(archivePath string) (*Manifest, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a Minishift backup: %v", archivePath, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("'%s' is not a Minishift backup", archivePath)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if path.Clean(header.Name) != ManifestFileName {
+			continue
+		}
+		manifest := &Manifest{}
+		if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+			return nil, fmt.Errorf("Invalid manifest in '%s': %v", archivePath, err)
+		}
+		return manifest, nil
+	}
+}
+
+// FileName returns the name of the archive for a backup of the profile created at the specified time
+func FileName(profile string, created time.Time) string {
+	return fmt.Sprintf("%s-%s%s", profile, created.Format("20060102-150405"), FileExtension)
+}
+
+// List returns the backups in dir, optionally only those of the specified profile, the newest first.
+func List(dir string, profile string) ([]Backup, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+FileExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, file := range files {
+		manifest, err := ReadManifest(file)
+		if err != nil {
+			continue
+		}
+		if profile != "" && manifest.Profile != profile {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, Backup{Path: file, Size: info.Size(), Manifest: manifest})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Manifest.Created.After(backups[j].Manifest.Created)
+	})
+	return backups, nil
+}
+
+// Due returns whether a scheduled backup is due, given the scheduled backups of the profile, the newest first.
+func Due(scheduled []Backup, interval time.Duration, now time.Time) bool {
+	if len(scheduled) == 0 {
+		return true
+	}
+	return !now.Before(scheduled[0].Manifest.Created.Add(interval))
+}
+
+// Scheduled returns the backups which were created by the schedule, keeping their order.
+func Scheduled(backups []Backup) []Backup {
+	var scheduled []Backup
+	for _, b := range backups {
+		if b.Manifest.Scheduled {
+			scheduled = append(scheduled, b)
+		}
+	}
+	return scheduled
+}
+
+// Prune removes the scheduled backups beyond the newest keep ones and returns the paths of the removed archives.
+// Backups created manually are never removed.
+func Prune(backups []Backup, keep int) ([]string, error) {
+	var removed []string
+	for i, b := range Scheduled(backups) {
+		if i < keep {
+			continue
+		}
+		if err := os.Remove(b.Path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, b.Path)
+	}
+	return removed, nil
+}
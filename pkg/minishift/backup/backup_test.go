@@ -0,0 +1,181 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingCommander struct {
+	provision.SSHCommander
+	commands []string
+}
+
+func (c *recordingCommander) SSHCommand(command string) (string, error) {
+	c.commands = append(c.commands, command)
+	return "", nil
+}
+
+func TestCreateUnpausesContainersAndCleansUp(t *testing.T) {
+	commander := &recordingCommander{}
+	archivePath := ArchivePathInsideInstance("backup.tar.gz")
+	assert.NoError(t, Create(commander, archivePath, Manifest{Profile: "minishift"}))
+
+	assert.Len(t, commander.commands, 4)
+	assert.Equal(t, "sudo mkdir -p /var/lib/minishift/backups && sudo chmod 1777 /var/lib/minishift/backups", commander.commands[0])
+	assert.Contains(t, commander.commands[1], "base64 -d > /var/lib/minishift/backups/manifest/backup.json")
+	archive := commander.commands[2]
+	assert.True(t, strings.Index(archive, "docker pause") < strings.Index(archive, "sudo tar -czf /var/lib/minishift/backups/backup.tar.gz"))
+	assert.True(t, strings.Index(archive, "sudo tar") < strings.Index(archive, "docker unpause"))
+	assert.Contains(t, archive, "/var/lib/minishift/base/etcd /var/lib/minishift/openshift.local.pv")
+	assert.Equal(t, "rm -rf /var/lib/minishift/backups/manifest", commander.commands[3])
+}
+
+func TestRestoreStartsOpenShiftAgain(t *testing.T) {
+	commander := &recordingCommander{}
+	assert.NoError(t, Restore(commander, "/var/lib/minishift/backups/backup.tar.gz", []string{"/var/lib/minishift/base/etcd"}))
+
+	assert.Len(t, commander.commands, 1)
+	restore := commander.commands[0]
+	assert.True(t, strings.HasPrefix(restore, "docker stop origin"))
+	assert.Contains(t, restore, "sudo tar -xzf /var/lib/minishift/backups/backup.tar.gz -C / var/lib/minishift/base/etcd;")
+	assert.True(t, strings.HasSuffix(restore, "docker start origin; exit $rc"))
+}
+
+func TestVerifyRestrictsEntriesToDataDirectories(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minishift-test-backup-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(dir)
+
+	manifest := &Manifest{Directories: DataDirectories}
+	var tests = []struct {
+		entries     []string
+		directories []string
+		valid       bool
+	}{
+		{[]string{"var/lib/minishift/base/etcd/", "var/lib/minishift/base/etcd/member/wal"}, []string{"/var/lib/minishift/base/etcd"}, true},
+		{[]string{"var/lib/minishift/base/etcd/db", "var/lib/minishift/openshift.local.pv/pv0001/data"}, DataDirectories, true},
+		{[]string{"etc/passwd"}, nil, false},
+		{[]string{"/var/lib/minishift/base/etcd/db"}, nil, false},
+		{[]string{"var/lib/minishift/base/etcd/../../../../etc/shadow"}, nil, false},
+		{[]string{"var/lib/minishift/base/etcd-other/db"}, nil, false},
+	}
+
+	for _, test := range tests {
+		archivePath := filepath.Join(dir, "backup.tar.gz")
+		writeArchive(t, archivePath, `{"profile": "minishift"}`, test.entries...)
+
+		directories, err := Verify(archivePath, manifest)
+		if test.valid {
+			assert.NoError(t, err, "Entries %v should be valid", test.entries)
+			assert.Equal(t, test.directories, directories)
+		} else {
+			assert.Error(t, err, "Entries %v should be rejected", test.entries)
+		}
+	}
+
+	_, err = Verify(filepath.Join(dir, "backup.tar.gz"), &Manifest{Directories: []string{"/etc"}})
+	assert.Error(t, err, "Directories which are no data directories should be rejected")
+}
+
+func TestListReadsManifests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minishift-test-backup-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(dir)
+
+	older := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	writeArchive(t, filepath.Join(dir, FileName("minishift", older)), `{"profile": "minishift", "created": "2019-10-01T12:00:00Z"}`)
+	writeArchive(t, filepath.Join(dir, FileName("minishift", newer)), `{"profile": "minishift", "created": "2019-10-01T13:00:00Z"}`)
+	writeArchive(t, filepath.Join(dir, FileName("other", newer)), `{"profile": "other", "created": "2019-10-01T13:00:00Z"}`)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "broken.tar.gz"), []byte("garbage"), 0644))
+
+	backups, err := List(dir, "minishift")
+	assert.NoError(t, err)
+	assert.Len(t, backups, 2)
+	assert.Equal(t, filepath.Join(dir, "minishift-20191001-130000.tar.gz"), backups[0].Path)
+	assert.Equal(t, older, backups[1].Manifest.Created)
+
+	backups, err = List(dir, "")
+	assert.NoError(t, err)
+	assert.Len(t, backups, 3)
+
+	_, err = ReadManifest(filepath.Join(dir, "broken.tar.gz"))
+	assert.Error(t, err)
+}
+
+func TestPruneKeepsManualBackups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minishift-test-backup-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(dir)
+
+	created := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		created = created.Add(time.Hour)
+		manifest := fmt.Sprintf(`{"profile": "minishift", "created": "%s", "scheduled": %t}`, created.Format(time.RFC3339), i != 0)
+		writeArchive(t, filepath.Join(dir, FileName("minishift", created)), manifest)
+	}
+
+	backups, err := List(dir, "minishift")
+	assert.NoError(t, err)
+	assert.Len(t, Scheduled(backups), 3)
+	assert.False(t, Due(Scheduled(backups), 2*time.Hour, created.Add(time.Hour)))
+	assert.True(t, Due(Scheduled(backups), 2*time.Hour, created.Add(2*time.Hour)))
+	assert.True(t, Due(nil, 2*time.Hour, created))
+
+	removed, err := Prune(backups, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "minishift-20191001-150000.tar.gz"),
+		filepath.Join(dir, "minishift-20191001-140000.tar.gz"),
+	}, removed)
+
+	backups, err = List(dir, "minishift")
+	assert.NoError(t, err)
+	assert.Len(t, backups, 2)
+	assert.True(t, backups[0].Manifest.Scheduled)
+	assert.False(t, backups[1].Manifest.Scheduled)
+}
+
+func writeArchive(t *testing.T, archivePath string, manifest string, entries ...string) {
+	f, err := os.Create(archivePath)
+	assert.NoError(t, err)
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: ManifestFileName, Mode: 0644, Size: int64(len(manifest))}))
+	_, err = tw.Write([]byte(manifest))
+	assert.NoError(t, err)
+
+	for _, entry := range entries {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: entry, Mode: 0644}))
+	}
+}
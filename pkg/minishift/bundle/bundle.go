@@ -0,0 +1,305 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/docker/image"
+	"github.com/minishift/minishift/pkg/util/archive"
+)
+
+const (
+	// ManifestFileName is the name of the file describing the content of a bundle
+	ManifestFileName = "bundle.json"
+
+	imageDir  = "image"
+	ocDir     = "oc"
+	indexFile = "index.json"
+)
+
+// Manifest describes the content of a bundle.
+type Manifest struct {
+	OpenShiftVersion string   `json:"openshift-version"`
+	Images           []string `json:"images"`
+	// OcOS is the operating system the contained oc binary is built for. It is empty if the bundle contains no oc binary.
+	OcOS string `json:"oc-os,omitempty"`
+}
+
+type blobReferences struct {
+	Config    *blobReference  `json:"config"`
+	Layers    []blobReference `json:"layers"`
+	Manifests []blobReference `json:"manifests"`
+}
+
+type blobReference struct {
+	Digest string `json:"digest"`
+}
+
+// Create writes a bundle containing the images listed in the manifest to bundlePath. The images are read from the
+// OCI image cache in imageCacheDir and must all be cached. If ocBinary is not empty, the oc binary is added as well.
+func Create(bundlePath string, manifest Manifest, imageCacheDir string, ocBinary string) error {
+	index, err := readIndex(imageCacheDir)
+	if err != nil {
+		return err
+	}
+
+	bundleIndex := &image.Index{Manifests: image.Manifests{}, SchemaVersion: 2}
+	blobs := make(map[string]bool)
+	for _, name := range manifest.Images {
+		entry := findManifest(index, name)
+		if entry == nil {
+			return fmt.Errorf("Image '%s' is not cached", name)
+		}
+		bundleIndex.Manifests = append(bundleIndex.Manifests, *entry)
+		if err := collectBlobs(imageCacheDir, entry.Digest, blobs); err != nil {
+			return fmt.Errorf("Error reading the cached blobs of '%s': %v", name, err)
+		}
+	}
+
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	rawManifest, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := archive.AddTarContent(tarWriter, ManifestFileName, rawManifest); err != nil {
+		return err
+	}
+
+	rawIndex, err := json.MarshalIndent(bundleIndex, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := archive.AddTarContent(tarWriter, path.Join(imageDir, indexFile), rawIndex); err != nil {
+		return err
+	}
+
+	for _, digest := range sortedKeys(blobs) {
+		if err := archive.AddTarFile(tarWriter, path.Join(imageDir, blobPath(digest)), filepath.Join(imageCacheDir, filepath.FromSlash(blobPath(digest))), 0644); err != nil {
+			return err
+		}
+	}
+
+	if ocBinary != "" {
+		if err := archive.AddTarFile(tarWriter, path.Join(ocDir, filepath.Base(ocBinary)), ocBinary, 0755); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// ReadManifest returns the manifest of the bundle at bundlePath.
+func ReadManifest(bundlePath string) (*Manifest, error) {
+	var manifest *Manifest
+	err := archive.WalkTarGz(bundlePath, func(header *tar.Header, r io.Reader) (bool, error) {
+		if header.Name != ManifestFileName {
+			return true, nil
+		}
+		manifest = &Manifest{}
+		return false, json.NewDecoder(r).Decode(manifest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("'%s' is not a Minishift bundle", bundlePath)
+	}
+	return manifest, nil
+}
+
+// Apply extracts the bundle at bundlePath. The images are added to the OCI image cache in imageCacheDir, skipping blobs
+// and images which are cached already. If ocTargetDir is not empty, the contained oc binary is extracted into it.
+func Apply(bundlePath string, imageCacheDir string, ocTargetDir string) error {
+	var bundleIndex *image.Index
+	err := archive.WalkTarGz(bundlePath, func(header *tar.Header, r io.Reader) (bool, error) {
+		name := path.Clean(header.Name)
+		if strings.HasPrefix(name, "..") || path.IsAbs(name) {
+			return false, fmt.Errorf("Invalid bundle entry '%s'", header.Name)
+		}
+
+		switch {
+		case name == path.Join(imageDir, indexFile):
+			bundleIndex = &image.Index{}
+			return true, json.NewDecoder(r).Decode(bundleIndex)
+		case strings.HasPrefix(name, imageDir+"/blobs/"):
+			relPath := strings.TrimPrefix(name, imageDir+"/")
+			digest, err := blobDigest(relPath)
+			if err != nil {
+				return false, err
+			}
+			target := filepath.Join(imageCacheDir, filepath.FromSlash(relPath))
+			if _, err := os.Stat(target); err == nil {
+				return true, nil
+			}
+			return true, archive.ExtractFile(&digestReader{r: r, hash: sha256.New(), digest: digest}, target, 0644)
+		case strings.HasPrefix(name, ocDir+"/") && ocTargetDir != "":
+			return true, archive.ExtractFile(r, filepath.Join(ocTargetDir, path.Base(name)), 0755)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if bundleIndex == nil {
+		return nil
+	}
+	return mergeIndex(imageCacheDir, bundleIndex)
+}
+
+// mergeIndex adds the image manifests of the bundle index to the index of the image cache
+func mergeIndex(imageCacheDir string, bundleIndex *image.Index) error {
+	index, err := readIndex(imageCacheDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range bundleIndex.Manifests {
+		if findManifest(index, entry.Annotations.Name) == nil {
+			index.Manifests = append(index.Manifests, entry)
+		}
+	}
+
+	rawIndex, err := json.MarshalIndent(index, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(imageCacheDir, indexFile), rawIndex, 0644)
+}
+
+// collectBlobs adds the specified blob as well as all blobs it references to the blob set
+func collectBlobs(imageCacheDir string, digest string, blobs map[string]bool) error {
+	if blobs[digest] {
+		return nil
+	}
+	blobs[digest] = true
+
+	raw, err := ioutil.ReadFile(filepath.Join(imageCacheDir, filepath.FromSlash(blobPath(digest))))
+	if err != nil {
+		return err
+	}
+
+	var refs blobReferences
+	if err := json.Unmarshal(raw, &refs); err != nil {
+		return err
+	}
+
+	if refs.Config != nil {
+		blobs[refs.Config.Digest] = true
+	}
+	for _, layer := range refs.Layers {
+		blobs[layer.Digest] = true
+	}
+	for _, manifest := range refs.Manifests {
+		if err := collectBlobs(imageCacheDir, manifest.Digest, blobs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readIndex(imageCacheDir string) (*image.Index, error) {
+	index := &image.Index{Manifests: image.Manifests{}, SchemaVersion: 2}
+	raw, err := ioutil.ReadFile(filepath.Join(imageCacheDir, indexFile))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+func findManifest(index *image.Index, name string) *image.Manifest {
+	for i := range index.Manifests {
+		if index.Manifests[i].Annotations.Name == name {
+			return &index.Manifests[i]
+		}
+	}
+	return nil
+}
+
+// blobPath returns the slash separated path of a blob relative to the image cache directory
+func blobPath(digest string) string {
+	return path.Join("blobs", strings.Replace(digest, ":", "/", 1))
+}
+
+// blobDigest returns the digest of the blob at the slash separated path relative to the image cache directory. Only
+// sha256 digests are supported.
+func blobDigest(relPath string) (string, error) {
+	parts := strings.Split(relPath, "/")
+	if len(parts) != 3 || parts[1] != "sha256" || len(parts[2]) != sha256.Size*2 {
+		return "", fmt.Errorf("Invalid blob '%s'", relPath)
+	}
+	if _, err := hex.DecodeString(parts[2]); err != nil {
+		return "", fmt.Errorf("Invalid blob '%s'", relPath)
+	}
+	return parts[1] + ":" + parts[2], nil
+}
+
+// digestReader fails the read of the last chunk if the content read does not match the digest
+type digestReader struct {
+	r      io.Reader
+	hash   hash.Hash
+	digest string
+}
+
+func (d *digestReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	d.hash.Write(p[:n])
+	if err == io.EOF && "sha256:"+hex.EncodeToString(d.hash.Sum(nil)) != d.digest {
+		return n, fmt.Errorf("The content of blob '%s' does not match its digest", d.digest)
+	}
+	return n, err
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
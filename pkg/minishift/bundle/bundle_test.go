@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/docker/image"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Create_And_Apply_Bundle(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-bundle-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	sourceCache := filepath.Join(testDir, "source")
+	sharedLayer := addBlob(t, sourceCache, "shared-layer")
+	routerLayer := addBlob(t, sourceCache, "router-layer")
+	addImage(t, sourceCache, "openshift/origin-control-plane:v3.11.0", sharedLayer)
+	addImage(t, sourceCache, "openshift/origin-haproxy-router:v3.11.0", sharedLayer, routerLayer)
+	addImage(t, sourceCache, "alpine:latest", addBlob(t, sourceCache, "alpine-layer"))
+
+	ocBinary := filepath.Join(testDir, "oc")
+	assert.NoError(t, ioutil.WriteFile(ocBinary, []byte("oc"), 0755))
+
+	bundlePath := filepath.Join(testDir, "bundle.tar.gz")
+	manifest := Manifest{
+		OpenShiftVersion: "v3.11.0",
+		Images:           []string{"openshift/origin-control-plane:v3.11.0", "openshift/origin-haproxy-router:v3.11.0"},
+		OcOS:             "linux",
+	}
+	assert.NoError(t, Create(bundlePath, manifest, sourceCache, ocBinary))
+
+	readManifest, err := ReadManifest(bundlePath)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, *readManifest)
+
+	targetCache := filepath.Join(testDir, "target")
+	ocTargetDir := filepath.Join(testDir, "oc-cache")
+	addImage(t, targetCache, "openshift/origin-control-plane:v3.11.0", sharedLayer)
+	assert.NoError(t, Apply(bundlePath, targetCache, ocTargetDir))
+
+	index, err := readIndex(targetCache)
+	assert.NoError(t, err)
+	var names []string
+	for _, entry := range index.Manifests {
+		names = append(names, entry.Annotations.Name)
+	}
+	assert.Equal(t, manifest.Images, names, "Already cached images should not be added twice")
+	assert.FileExists(t, filepath.Join(targetCache, filepath.FromSlash(blobPath(routerLayer))))
+	_, err = os.Stat(filepath.Join(targetCache, filepath.FromSlash(blobPath(digestOf("alpine-layer")))))
+	assert.True(t, os.IsNotExist(err), "Blobs of images not part of the bundle should not be included")
+	assert.FileExists(t, filepath.Join(ocTargetDir, "oc"))
+}
+
+func Test_Create_Fails_For_Uncached_Image(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-bundle-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	err = Create(filepath.Join(testDir, "bundle.tar.gz"), Manifest{Images: []string{"alpine:latest"}}, testDir, "")
+	assert.EqualError(t, err, "Image 'alpine:latest' is not cached")
+}
+
+func Test_Apply_Fails_For_Tampered_Blob(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-bundle-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	sourceCache := filepath.Join(testDir, "source")
+	layer := addBlob(t, sourceCache, "layer")
+	addImage(t, sourceCache, "alpine:latest", layer)
+	layerPath := filepath.Join(sourceCache, filepath.FromSlash(blobPath(layer)))
+	assert.NoError(t, ioutil.WriteFile(layerPath, []byte("tampered"), 0644))
+
+	bundlePath := filepath.Join(testDir, "bundle.tar.gz")
+	assert.NoError(t, Create(bundlePath, Manifest{OpenShiftVersion: "v3.11.0", Images: []string{"alpine:latest"}}, sourceCache, ""))
+
+	targetCache := filepath.Join(testDir, "target")
+	err = Apply(bundlePath, targetCache, "")
+	assert.EqualError(t, err, fmt.Sprintf("The content of blob '%s' does not match its digest", layer))
+	_, err = os.Stat(filepath.Join(targetCache, filepath.FromSlash(blobPath(layer))))
+	assert.True(t, os.IsNotExist(err), "The tampered blob should not be added to the cache")
+}
+
+func Test_Read_Manifest_Fails_For_Non_Bundle(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-bundle-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	notABundle := filepath.Join(testDir, "foo.json")
+	assert.NoError(t, ioutil.WriteFile(notABundle, []byte("{}"), 0644))
+	_, err = ReadManifest(notABundle)
+	assert.Error(t, err)
+}
+
+func addImage(t *testing.T, cacheDir string, name string, layers ...string) {
+	var layerRefs []string
+	for _, layer := range layers {
+		layerRefs = append(layerRefs, fmt.Sprintf(`{"digest": "%s"}`, layer))
+	}
+	config := addBlob(t, cacheDir, "config-"+name)
+	manifest := addBlob(t, cacheDir, fmt.Sprintf(`{"config": {"digest": "%s"}, "layers": [%s]}`, config, strings.Join(layerRefs, ", ")))
+
+	index, err := readIndex(cacheDir)
+	assert.NoError(t, err)
+	entry := image.Manifest{Digest: manifest}
+	entry.Annotations.Name = name
+	index.Manifests = append(index.Manifests, entry)
+	assert.NoError(t, mergeIndex(cacheDir, index))
+}
+
+func addBlob(t *testing.T, cacheDir string, content string) string {
+	digest := digestOf(content)
+	path := filepath.Join(cacheDir, filepath.FromSlash(blobPath(digest)))
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return digest
+}
+
+func digestOf(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
@@ -17,16 +17,36 @@ limitations under the License.
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
 	"github.com/minishift/minishift/pkg/minikube/constants"
 	"github.com/minishift/minishift/pkg/util/github"
 	minishiftos "github.com/minishift/minishift/pkg/util/os"
+	"github.com/minishift/minishift/pkg/util/os/lock"
 	"github.com/pkg/errors"
-	"os"
-	"path/filepath"
-	"runtime"
 )
 
-const OC_CACHE_DIR = "oc"
+const (
+	OC_CACHE_DIR = "oc"
+
+	// OcChecksumFile is the file next to the cached oc binary holding the SHA-256 checksum of the binary
+	OcChecksumFile = "oc.sha256"
+
+	// ocReleaseArch is the only architecture for which the oc binary is released upstream
+	ocReleaseArch = "amd64"
+
+	// DownloadLockTimeout is the time to wait for another process downloading the same artifact into the cache
+	DownloadLockTimeout = 30 * time.Minute
+)
 
 // Oc is a struct with methods designed for dealing with the oc binary
 type Oc struct {
@@ -34,19 +54,88 @@ type Oc struct {
 	MinishiftCacheDir string
 }
 
+// EnsureIsCached downloads the oc binary unless it is already cached. A cached binary which does not match its
+// recorded checksum is considered corrupt and downloaded again. Concurrent Minishift processes wait for each other,
+// so that the binary is downloaded only once.
 func (oc *Oc) EnsureIsCached() error {
-	if !oc.isCached() {
-		err := oc.cacheOc()
-		if err != nil {
-			return err
+	cacheLock, err := lock.Wait(oc.GetCacheFilepath()+".lock", DownloadLockTimeout,
+		fmt.Sprintf("-- Waiting for another Minishift process caching oc binary version '%s'", oc.OpenShiftVersion))
+	if err != nil {
+		return errors.Wrapf(err, "Error locking the cache of oc binary version '%s'", oc.OpenShiftVersion)
+	}
+	defer cacheLock.Release()
+
+	if oc.isCached() {
+		err := oc.Verify()
+		if err == nil {
+			return oc.ensureKubectl()
 		}
+		fmt.Println(fmt.Sprintf("-- Discarding cached oc binary version '%s': %v", oc.OpenShiftVersion, err))
+		if err := os.RemoveAll(oc.GetCacheFilepath()); err != nil {
+			return errors.Wrapf(err, "Error removing the cached oc binary '%s'", oc.GetCacheFilepath())
+		}
+	}
 
+	if err := oc.cacheOc(); err != nil {
+		return err
 	}
-	return nil
+	return oc.ensureKubectl()
 }
 
+// GetCacheFilepath returns the directory the oc binary for the current OS and architecture is cached in.
 func (oc *Oc) GetCacheFilepath() string {
-	return filepath.Join(oc.MinishiftCacheDir, OC_CACHE_DIR, oc.OpenShiftVersion, runtime.GOOS)
+	return filepath.Join(oc.MinishiftCacheDir, OC_CACHE_DIR, oc.OpenShiftVersion, OcPlatform())
+}
+
+// Verify checks the cached oc binary against the checksum recorded when it was downloaded.
+// Binaries cached by older Minishift releases have no checksum recorded and fail verification, so that they are
+// downloaded and verified again. Binaries placed manually for architectures without a release are not verified.
+func (oc *Oc) Verify() error {
+	binary := filepath.Join(oc.GetCacheFilepath(), constants.OC_BINARY_NAME)
+	actual, err := sha256Sum(binary)
+	if err != nil {
+		return err
+	}
+
+	checksumFile := filepath.Join(oc.GetCacheFilepath(), OcChecksumFile)
+	expected, err := ioutil.ReadFile(checksumFile)
+	if os.IsNotExist(err) {
+		if runtime.GOARCH != ocReleaseArch {
+			return nil
+		}
+		return errors.Errorf("no checksum recorded for '%s'", binary)
+	}
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(string(expected)) != actual {
+		return errors.Errorf("checksum mismatch - expected: %s, actual: %s", strings.TrimSpace(string(expected)), actual)
+	}
+	return nil
+}
+
+// ensureKubectl provides kubectl next to the cached oc binary. Invoked under this name oc acts as kubectl,
+// hence kubectl always matches the version of the cluster.
+func (oc *Oc) ensureKubectl() error {
+	kubectl := filepath.Join(oc.GetCacheFilepath(), constants.KUBECTL_BINARY_NAME)
+	if _, err := os.Lstat(kubectl); err == nil {
+		return nil
+	}
+
+	if err := os.Link(filepath.Join(oc.GetCacheFilepath(), constants.OC_BINARY_NAME), kubectl); err != nil {
+		return errors.Wrapf(err, "Error providing '%s'", kubectl)
+	}
+	return nil
+}
+
+// OcPlatform returns the name of the cache directory for the oc binary of the current OS and architecture.
+// For amd64 the name of the OS is used, so that the binaries cached by older Minishift releases are found.
+func OcPlatform() string {
+	if runtime.GOARCH == ocReleaseArch {
+		return runtime.GOOS
+	}
+	return fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
 }
 
 func (oc *Oc) isCached() bool {
@@ -56,12 +145,75 @@ func (oc *Oc) isCached() bool {
 	return true
 }
 
-// cacheOc downloads and caches the oc binary into the minishift directory
+// cacheOc downloads and caches the oc binary into the minishift directory. The download itself is verified against
+// the checksums of the OpenShift release, the checksum of the binary is recorded for later verification.
 func (oc *Oc) cacheOc() error {
+	if oc.isCached() {
+		return nil
+	}
+
+	if runtime.GOARCH != ocReleaseArch {
+		return errors.Errorf("The '%s' binary is not released for the '%s' architecture. Place the binary in '%s' to use it.",
+			github.OC.String(), runtime.GOARCH, oc.GetCacheFilepath())
+	}
+
+	if err := github.DownloadOpenShiftReleaseBinary(github.OC, minishiftos.CurrentOS(), oc.OpenShiftVersion, oc.GetCacheFilepath()); err != nil {
+		return errors.Wrapf(err, "Error attempting to download and cache '%s'", github.OC.String())
+	}
+
+	checksum, err := sha256Sum(filepath.Join(oc.GetCacheFilepath(), constants.OC_BINARY_NAME))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(oc.GetCacheFilepath(), OcChecksumFile), []byte(checksum), 0644)
+}
+
+// RemoveCachedOc removes the oc binary of the specified OpenShift version for the current OS and architecture
+// from the cache. The version directory is removed as well once it is empty.
+func RemoveCachedOc(minishiftCacheDir string, openShiftVersion string) error {
+	oc := Oc{OpenShiftVersion: openShiftVersion, MinishiftCacheDir: minishiftCacheDir}
 	if !oc.isCached() {
-		if err := github.DownloadOpenShiftReleaseBinary(github.OC, minishiftos.CurrentOS(), oc.OpenShiftVersion, oc.GetCacheFilepath()); err != nil {
-			return errors.Wrapf(err, "Error attempting to download and cache '%s'", github.OC.String())
-		}
+		return errors.Errorf("The oc binary version '%s' is not cached", openShiftVersion)
+	}
+
+	if err := os.RemoveAll(oc.GetCacheFilepath()); err != nil {
+		return err
+	}
+
+	versionDir := filepath.Dir(oc.GetCacheFilepath())
+	if content, err := ioutil.ReadDir(versionDir); err == nil && len(content) == 0 {
+		return os.Remove(versionDir)
 	}
 	return nil
 }
+
+func sha256Sum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// CachedOcVersions returns the OpenShift versions for which the oc binary of the current OS and architecture is cached
+func CachedOcVersions(minishiftCacheDir string) []string {
+	var versions []string
+	dirs, err := ioutil.ReadDir(filepath.Join(minishiftCacheDir, OC_CACHE_DIR))
+	if err != nil {
+		return versions
+	}
+
+	for _, dir := range dirs {
+		oc := Oc{OpenShiftVersion: dir.Name(), MinishiftCacheDir: minishiftCacheDir}
+		if dir.IsDir() && oc.isCached() {
+			versions = append(versions, dir.Name())
+		}
+	}
+	return versions
+}
@@ -51,6 +51,24 @@ func TestIsCached(t *testing.T) {
 	assert.True(t, testOc.isCached())
 }
 
+func TestCachedOcVersions(t *testing.T) {
+	setUp(t)
+	defer os.RemoveAll(testDir)
+
+	cacheDir := filepath.Join(testDir, "cache")
+	assert.Empty(t, CachedOcVersions(cacheDir))
+
+	for _, v := range []string{"v3.10.0", "v3.11.0"} {
+		ocDir := filepath.Join(cacheDir, "oc", v, runtime.GOOS)
+		os.MkdirAll(ocDir, os.ModePerm)
+		if v == "v3.11.0" {
+			ioutil.WriteFile(filepath.Join(ocDir, constants.OC_BINARY_NAME), []byte("foo"), os.ModePerm)
+		}
+	}
+
+	assert.Equal(t, []string{"v3.11.0"}, CachedOcVersions(cacheDir))
+}
+
 func TestCacheOc(t *testing.T) {
 	setUp(t)
 	defer os.RemoveAll(testDir) // clean up
@@ -68,6 +86,52 @@ func TestCacheOc(t *testing.T) {
 
 	err := testOc.cacheOc()
 	assert.NoError(t, err, "Error caching oc")
+	assert.FileExists(t, filepath.Join(testOc.GetCacheFilepath(), OcChecksumFile), "The checksum of the binary should have been recorded")
+	assert.NoError(t, testOc.Verify())
+}
+
+func TestVerify(t *testing.T) {
+	setUp(t)
+	defer os.RemoveAll(testDir)
+
+	os.MkdirAll(testOc.GetCacheFilepath(), os.ModePerm)
+	binary := filepath.Join(testOc.GetCacheFilepath(), constants.OC_BINARY_NAME)
+	ioutil.WriteFile(binary, []byte("foo"), os.ModePerm)
+
+	err := testOc.Verify()
+	assert.Error(t, err, "A binary without recorded checksum should fail verification")
+	assert.Contains(t, err.Error(), "no checksum recorded")
+	checksumFile := filepath.Join(testOc.GetCacheFilepath(), OcChecksumFile)
+	_, err = os.Stat(checksumFile)
+	assert.True(t, os.IsNotExist(err), "The checksum should not be recorded on verification")
+
+	ioutil.WriteFile(checksumFile, []byte("2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"), 0644)
+	assert.NoError(t, testOc.Verify())
+
+	assert.NoError(t, testOc.EnsureIsCached())
+	assert.FileExists(t, filepath.Join(testOc.GetCacheFilepath(), constants.KUBECTL_BINARY_NAME), "kubectl should be provided next to oc")
+
+	os.Remove(binary)
+	ioutil.WriteFile(binary, []byte("bar"), os.ModePerm)
+	err = testOc.Verify()
+	assert.Error(t, err, "A modified binary should fail verification")
+	assert.Contains(t, err.Error(), "checksum mismatch")
+}
+
+func TestRemoveCachedOc(t *testing.T) {
+	setUp(t)
+	defer os.RemoveAll(testDir)
+
+	cacheDir := filepath.Join(testDir, "cache")
+	assert.Error(t, RemoveCachedOc(cacheDir, "v1.3.1"), "Removing an uncached version should fail")
+
+	os.MkdirAll(testOc.GetCacheFilepath(), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(testOc.GetCacheFilepath(), constants.OC_BINARY_NAME), []byte("foo"), os.ModePerm)
+
+	assert.NoError(t, RemoveCachedOc(cacheDir, "v1.3.1"))
+	assert.Empty(t, CachedOcVersions(cacheDir))
+	_, err := os.Stat(filepath.Join(cacheDir, "oc", "v1.3.1"))
+	assert.True(t, os.IsNotExist(err), "The empty version directory should have been removed")
 }
 
 func setUp(t *testing.T) {
@@ -92,21 +156,21 @@ func addMockResponses(mockTransport *minitesting.MockRoundTripper) {
 	switch runtime.GOOS {
 	case "windows":
 		assetContent = filepath.Join(testDataDir, "openshift-origin-client-tools-v1.3.1-dad658de7465ba8a234a4fb40b5b446a45a4cee1-windows.zip")
-		mockTransport.RegisterResponse("https://api.github.com/repos/openshift/origin/releases/assets/2489312", &minitesting.CannedResponse{
+		mockTransport.RegisterResponse("https://github.com/openshift/origin/releases/download/v1.3.1/openshift-origin-client-tools-v1.3.1-dad658de7465ba8a234a4fb40b5b446a45a4cee1-windows.zip", &minitesting.CannedResponse{
 			ResponseType: minitesting.SERVE_FILE,
 			Response:     assetContent,
 			ContentType:  minitesting.OCTET_STREAM,
 		})
 	case "darwin":
 		assetContent = filepath.Join(testDataDir, "openshift-origin-client-tools-v1.3.1-2748423-mac.zip")
-		mockTransport.RegisterResponse("https://api.github.com/repos/openshift/origin/releases/assets/2586147", &minitesting.CannedResponse{
+		mockTransport.RegisterResponse("https://github.com/openshift/origin/releases/download/v1.3.1/openshift-origin-client-tools-v1.3.1-2748423-mac.zip", &minitesting.CannedResponse{
 			ResponseType: minitesting.SERVE_FILE,
 			Response:     assetContent,
 			ContentType:  minitesting.OCTET_STREAM,
 		})
 	case "linux":
 		assetContent = filepath.Join(testDataDir, "openshift-origin-client-tools-v1.3.1-dad658de7465ba8a234a4fb40b5b446a45a4cee1-linux-64bit.tar.gz")
-		mockTransport.RegisterResponse("https://api.github.com/repos/openshift/origin/releases/assets/2489310", &minitesting.CannedResponse{
+		mockTransport.RegisterResponse("https://github.com/openshift/origin/releases/download/v1.3.1/openshift-origin-client-tools-v1.3.1-dad658de7465ba8a234a4fb40b5b446a45a4cee1-linux-64bit.tar.gz", &minitesting.CannedResponse{
 			ResponseType: minitesting.SERVE_FILE,
 			Response:     assetContent,
 			ContentType:  minitesting.OCTET_STREAM,
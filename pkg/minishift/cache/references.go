@@ -0,0 +1,169 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sort"
+	"strings"
+)
+
+// ArtifactKind identifies the type of an artifact in the shared Minishift cache
+type ArtifactKind string
+
+const (
+	IsoArtifact   ArtifactKind = "iso"
+	OcArtifact    ArtifactKind = "oc"
+	ImageArtifact ArtifactKind = "image"
+)
+
+// References maps the key of a cached artifact to the names of the profiles using it
+type References map[string][]string
+
+// ArtifactKey returns the key under which the artifact of the given kind is tracked.
+// For files the id is the path of the cached file, for images it is the image name.
+func ArtifactKey(kind ArtifactKind, id string) string {
+	return string(kind) + ":" + id
+}
+
+// ParseArtifactKey splits an artifact key into its kind and id.
+func ParseArtifactKey(key string) (ArtifactKind, string) {
+	parts := strings.SplitN(key, ":", 2)
+	if len(parts) != 2 {
+		return "", key
+	}
+	return ArtifactKind(parts[0]), parts[1]
+}
+
+// Add records that the specified profile references the artifact. It returns true if the references changed.
+func (r References) Add(key string, profile string) bool {
+	for _, p := range r[key] {
+		if p == profile {
+			return false
+		}
+	}
+	r[key] = append(r[key], profile)
+	return true
+}
+
+// Remove drops the reference of the specified profile to the artifact. It returns true if the references changed.
+func (r References) Remove(key string, profile string) bool {
+	for i, p := range r[key] {
+		if p == profile {
+			r[key] = append(r[key][:i], r[key][i+1:]...)
+			if len(r[key]) == 0 {
+				delete(r, key)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveProfile drops all references of the specified profile. The keys of the artifacts which are not
+// referenced by any profile anymore are removed as well and returned in sorted order.
+func (r References) RemoveProfile(profile string) []string {
+	var orphaned []string
+	for key, profiles := range r {
+		var remaining []string
+		for _, p := range profiles {
+			if p != profile {
+				remaining = append(remaining, p)
+			}
+		}
+
+		if len(remaining) == len(profiles) {
+			continue
+		}
+
+		if len(remaining) == 0 {
+			delete(r, key)
+			orphaned = append(orphaned, key)
+		} else {
+			r[key] = remaining
+		}
+	}
+
+	sort.Strings(orphaned)
+	return orphaned
+}
+
+// Untracked returns the specified keys which are not referenced by any profile, in sorted order.
+func (r References) Untracked(keys []string) []string {
+	var untracked []string
+	for _, key := range keys {
+		if _, ok := r[key]; !ok {
+			untracked = append(untracked, key)
+		}
+	}
+
+	sort.Strings(untracked)
+	return untracked
+}
+
+// HasProfile returns true if the specified profile references at least one artifact.
+func (r References) HasProfile(profile string) bool {
+	for _, profiles := range r {
+		for _, p := range profiles {
+			if p == profile {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Keys returns the keys of the artifacts referenced by the specified profile, in sorted order.
+func (r References) Keys(profile string) []string {
+	var keys []string
+	for key, profiles := range r {
+		for _, p := range profiles {
+			if p == profile {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// RenameProfile replaces the references of profile old by references of profile new. It returns true if the references changed.
+func (r References) RenameProfile(old string, new string) bool {
+	changed := false
+	for key, profiles := range r {
+		for i, p := range profiles {
+			if p == old {
+				profiles[i] = new
+				changed = true
+			}
+		}
+		r[key] = profiles
+	}
+	return changed
+}
+
+// RenameKey moves the references of the artifact with key old to the key new.
+func (r References) RenameKey(old string, new string) {
+	profiles, ok := r[old]
+	if !ok {
+		return
+	}
+	delete(r, old)
+	for _, p := range profiles {
+		r.Add(new, p)
+	}
+}
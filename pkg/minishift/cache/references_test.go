@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Only_Unreferenced_Artifacts_Are_Orphaned(t *testing.T) {
+	refs := References{}
+	iso := ArtifactKey(IsoArtifact, "/cache/iso/minishift.iso")
+	image := ArtifactKey(ImageArtifact, "openshift/origin:v3.11.0")
+
+	assert.True(t, refs.Add(iso, "foo"))
+	assert.False(t, refs.Add(iso, "foo"))
+	refs.Add(iso, "bar")
+	refs.Add(image, "foo")
+	assert.Equal(t, []string{image, iso}, refs.Keys("foo"))
+	assert.Equal(t, []string{iso}, refs.Keys("bar"))
+
+	assert.Equal(t, []string{image}, refs.RemoveProfile("foo"))
+	assert.Equal(t, References{iso: {"bar"}}, refs)
+
+	assert.Equal(t, []string{iso}, refs.RemoveProfile("bar"))
+	assert.Empty(t, refs)
+	assert.Empty(t, refs.RemoveProfile("baz"))
+}
+
+func Test_Remove_Single_Reference(t *testing.T) {
+	refs := References{}
+	oc := ArtifactKey(OcArtifact, "/cache/oc/v3.11.0/linux")
+	refs.Add(oc, "foo")
+	refs.Add(oc, "bar")
+
+	assert.True(t, refs.Remove(oc, "foo"))
+	assert.False(t, refs.Remove(oc, "foo"))
+	assert.Equal(t, References{oc: {"bar"}}, refs)
+
+	assert.True(t, refs.Remove(oc, "bar"))
+	assert.Empty(t, refs)
+}
+
+func Test_Rename_Profile_References(t *testing.T) {
+	refs := References{}
+	iso := ArtifactKey(IsoArtifact, "/cache/iso/minishift.iso")
+	refs.Add(iso, "foo")
+	refs.Add(iso, "bar")
+
+	assert.True(t, refs.RenameProfile("foo", "baz"))
+	assert.False(t, refs.RenameProfile("foo", "baz"))
+	assert.Equal(t, References{iso: {"baz", "bar"}}, refs)
+
+	refs.RenameKey(iso, "renamed")
+	assert.Equal(t, References{"renamed": {"baz", "bar"}}, refs)
+}
+
+func Test_Parse_Artifact_Key(t *testing.T) {
+	kind, id := ParseArtifactKey(ArtifactKey(ImageArtifact, "docker.io/openshift/origin:v3.11.0"))
+	assert.Equal(t, ImageArtifact, kind)
+	assert.Equal(t, "docker.io/openshift/origin:v3.11.0", id)
+}
+
+func Test_Untracked_Artifacts(t *testing.T) {
+	refs := References{}
+	iso := ArtifactKey(IsoArtifact, "/cache/iso/minishift.iso")
+	oc := ArtifactKey(OcArtifact, "/cache/oc/v3.11.0/linux")
+	image := ArtifactKey(ImageArtifact, "openshift/origin:v3.11.0")
+	refs.Add(iso, "foo")
+
+	assert.Equal(t, []string{image, oc}, refs.Untracked([]string{oc, iso, image}))
+	assert.True(t, refs.HasProfile("foo"))
+	assert.False(t, refs.HasProfile("bar"))
+}
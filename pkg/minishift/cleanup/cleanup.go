@@ -0,0 +1,164 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cleanup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/util"
+)
+
+// Machine is a machine directory of a profile, containing the libmachine host configuration of a VM. HostOnlyCIDR
+// is only set for VirtualBox VMs.
+type Machine struct {
+	Name         string
+	Dir          string
+	DriverName   string
+	HostOnlyCIDR string
+}
+
+// Orphan is a left over of a VM, either a VM registered with the hypervisor whose machine directory is gone, a
+// machine directory whose VM is gone or a network resource no VM uses any longer.
+type Orphan struct {
+	Kind   string
+	Name   string
+	Reason string
+	remove func() error
+}
+
+func (o Orphan) String() string {
+	return fmt.Sprintf("%s '%s' (%s)", o.Kind, o.Name, o.Reason)
+}
+
+// Remove removes the orphaned resource
+func (o Orphan) Remove() error {
+	return o.remove()
+}
+
+// detector finds the orphans of a hypervisor. available returns false if the hypervisor is not installed.
+type detector interface {
+	available() bool
+	detect(home string, machines []Machine) ([]Orphan, error)
+}
+
+var detectors = []detector{
+	&virtualBoxDetector{},
+	&kvmDetector{},
+	&hypervDetector{},
+}
+
+// runCommand runs the command and returns its standard output, including the error output in the error
+var runCommand = func(name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Error running '%s %s': %v %s", filepath.Base(name), strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// Detect finds the orphans left behind by Minishift VMs in all installed hypervisors. home is the Minishift home
+// directory and machines the machine directories found in it. Orphans found by one hypervisor are returned even if
+// querying another hypervisor failed.
+func Detect(home string, machines []Machine) ([]Orphan, error) {
+	var orphans []Orphan
+	errors := util.MultiError{}
+	for _, d := range detectors {
+		if !d.available() {
+			continue
+		}
+		found, err := d.detect(home, machines)
+		errors.Collect(err)
+		orphans = append(orphans, found...)
+	}
+	return orphans, errors.ToError()
+}
+
+// FindMachines returns the machine directories of all profiles in the Minishift home directory.
+func FindMachines(home string) ([]Machine, error) {
+	patterns := []string{
+		filepath.Join(home, "machines", "*", "config.json"),
+		filepath.Join(home, "profiles", "*", "machines", "*", "config.json"),
+	}
+
+	var machines []Machine
+	for _, pattern := range patterns {
+		configs, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, config := range configs {
+			raw, err := ioutil.ReadFile(config)
+			if err != nil {
+				return nil, err
+			}
+			var hostConfig struct {
+				DriverName string
+				Driver     struct {
+					HostOnlyCIDR string
+				}
+			}
+			if err := json.Unmarshal(raw, &hostConfig); err != nil {
+				return nil, fmt.Errorf("Error reading '%s': %v", config, err)
+			}
+			dir := filepath.Dir(config)
+			machines = append(machines, Machine{
+				Name:         filepath.Base(dir),
+				Dir:          dir,
+				DriverName:   hostConfig.DriverName,
+				HostOnlyCIDR: hostConfig.Driver.HostOnlyCIDR,
+			})
+		}
+	}
+	return machines, nil
+}
+
+// orphanedMachineDirs returns the machine directories of the driver whose VM is not registered with the hypervisor
+func orphanedMachineDirs(machines []Machine, driverName string, kind string, registered map[string]bool) []Orphan {
+	var orphans []Orphan
+	for _, machine := range machines {
+		if machine.DriverName != driverName || registered[machine.Name] {
+			continue
+		}
+		dir := machine.Dir
+		orphans = append(orphans, Orphan{
+			Kind:   "Machine directory",
+			Name:   dir,
+			Reason: fmt.Sprintf("the %s VM '%s' does not exist", kind, machine.Name),
+			remove: func() error { return os.RemoveAll(dir) },
+		})
+	}
+	return orphans
+}
+
+// isWithin returns true if path is located within dir
+func isWithin(path string, dir string) bool {
+	return strings.HasPrefix(filepath.Clean(path), filepath.Clean(dir)+string(filepath.Separator))
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cleanup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FindMachines_Returns_Machines_Of_All_Profiles(t *testing.T) {
+	home := setupHome(t)
+	defer os.RemoveAll(home)
+
+	writeMachine(t, filepath.Join(home, "machines", "minishift"), "virtualbox")
+	writeMachine(t, filepath.Join(home, "profiles", "foo", "machines", "foo"), "kvm")
+	os.MkdirAll(filepath.Join(home, "machines", "no-config"), 0777)
+
+	machines, err := FindMachines(home)
+	assert.NoError(t, err)
+	assert.Equal(t, []Machine{
+		{Name: "minishift", Dir: filepath.Join(home, "machines", "minishift"), DriverName: "virtualbox"},
+		{Name: "foo", Dir: filepath.Join(home, "profiles", "foo", "machines", "foo"), DriverName: "kvm"},
+	}, machines)
+}
+
+func Test_VirtualBox_Orphans_Are_Detected(t *testing.T) {
+	home := setupHome(t)
+	defer os.RemoveAll(home)
+
+	existing := filepath.Join(home, "machines", "minishift")
+	writeMachine(t, existing, "virtualbox")
+	orphanedDir := filepath.Join(home, "profiles", "foo", "machines", "foo")
+	writeMachine(t, orphanedDir, "virtualbox")
+	content := `{"DriverName": "virtualbox", "Driver": {"MachineName": "foo", "HostOnlyCIDR": "192.168.42.1/24"}}`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(orphanedDir, "config.json"), []byte(content), 0644))
+	machines, _ := FindMachines(home)
+
+	var executed []string
+	defer fakeCommands(func(name string, args ...string) (string, error) {
+		executed = append(executed, strings.Join(args, " "))
+		switch strings.Join(args, " ") {
+		case "list vms":
+			return "\"minishift\" {1111-aaaa}\n\"bar\" {2222-bbbb}\n\"other\" {3333-cccc}\n", nil
+		case "showvminfo 1111-aaaa --machinereadable":
+			return fmt.Sprintf("CfgFile=\"%s\"\nhostonlyadapter2=\"vboxnet0\"\n", filepath.Join(existing, "minishift", "minishift.vbox")), nil
+		case "showvminfo 2222-bbbb --machinereadable":
+			return fmt.Sprintf("CfgFile=\"%s\"\n", filepath.Join(home, "profiles", "bar", "machines", "bar", "bar", "bar.vbox")), nil
+		case "showvminfo 3333-cccc --machinereadable":
+			return "CfgFile=\"/elsewhere/other/other.vbox\"\n", nil
+		case "list hostonlyifs":
+			return "Name:            vboxnet0\nIPAddress:       192.168.99.1\n\n" +
+				"Name:            vboxnet1\nIPAddress:       192.168.42.1\n\n" +
+				"Name:            vboxnet2\nIPAddress:       172.28.128.1\n\n" +
+				"Name:            vboxnet3\n", nil
+		}
+		return "", nil
+	})()
+	os.MkdirAll(filepath.Join(existing, "minishift"), 0777)
+	ioutil.WriteFile(filepath.Join(existing, "minishift", "minishift.vbox"), []byte{}, 0644)
+
+	orphans, err := (&virtualBoxDetector{}).detect(home, machines)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"VirtualBox VM 'bar' (its machine directory is gone)",
+		fmt.Sprintf("Machine directory '%s' (the VirtualBox VM 'foo' does not exist)", orphanedDir),
+		"VirtualBox host-only interface 'vboxnet1' (no VM uses it)",
+	}, describe(orphans))
+
+	executed = nil
+	for _, orphan := range orphans {
+		assert.NoError(t, orphan.Remove())
+	}
+	assert.Equal(t, []string{"unregistervm 2222-bbbb", "hostonlyif remove vboxnet1"}, executed)
+	assert.False(t, exists(orphanedDir), "Orphaned machine directory should be removed")
+	assert.True(t, exists(existing))
+}
+
+func Test_KVM_Orphans_Are_Detected(t *testing.T) {
+	home := setupHome(t)
+	defer os.RemoveAll(home)
+
+	writeMachine(t, filepath.Join(home, "machines", "minishift"), "kvm")
+	machines, _ := FindMachines(home)
+
+	var executed []string
+	defer fakeCommands(func(name string, args ...string) (string, error) {
+		command := strings.Join(args[2:], " ")
+		executed = append(executed, command)
+		switch command {
+		case "list --all --name":
+			return "foo\n\n", nil
+		case "dumpxml foo":
+			return fmt.Sprintf("<disk><source file='%s'/></disk><interface><source network='default'/></interface>", filepath.Join(home, "profiles", "foo", "machines", "foo", "foo.img")), nil
+		}
+		return "", nil
+	})()
+
+	orphans, err := (&kvmDetector{}).detect(home, machines)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		fmt.Sprintf("KVM domain 'foo' (its disk '%s' is gone)", filepath.Join(home, "profiles", "foo", "machines", "foo", "foo.img")),
+		fmt.Sprintf("Machine directory '%s' (the KVM VM 'minishift' does not exist)", filepath.Join(home, "machines", "minishift")),
+	}, describe(orphans))
+
+	executed = nil
+	orphans[0].Remove()
+	assert.Equal(t, []string{"destroy foo", "undefine foo"}, executed)
+}
+
+func Test_Detect_Returns_Orphans_Despite_Errors(t *testing.T) {
+	defer func(orig []detector) { detectors = orig }(detectors)
+	detectors = []detector{
+		&fakeDetector{err: fmt.Errorf("hypervisor not responding")},
+		&fakeDetector{orphans: []Orphan{{Kind: "KVM domain", Name: "foo", Reason: "test"}}},
+		&fakeDetector{unavailable: true, err: fmt.Errorf("should not be called")},
+	}
+
+	orphans, err := Detect("", nil)
+	assert.EqualError(t, err, "hypervisor not responding")
+	assert.Equal(t, []string{"KVM domain 'foo' (test)"}, describe(orphans))
+}
+
+type fakeDetector struct {
+	unavailable bool
+	orphans     []Orphan
+	err         error
+}
+
+func (d *fakeDetector) available() bool {
+	return !d.unavailable
+}
+
+func (d *fakeDetector) detect(home string, machines []Machine) ([]Orphan, error) {
+	return d.orphans, d.err
+}
+
+func fakeCommands(fake func(name string, args ...string) (string, error)) func() {
+	orig := runCommand
+	runCommand = fake
+	return func() { runCommand = orig }
+}
+
+func describe(orphans []Orphan) []string {
+	var descriptions []string
+	for _, orphan := range orphans {
+		descriptions = append(descriptions, orphan.String())
+	}
+	return descriptions
+}
+
+func setupHome(t *testing.T) string {
+	home, err := ioutil.TempDir("", "minishift-test-cleanup-")
+	assert.NoError(t, err, "Error creating temp directory")
+	return home
+}
+
+func writeMachine(t *testing.T, dir string, driverName string) {
+	assert.NoError(t, os.MkdirAll(dir, 0777))
+	content := fmt.Sprintf(`{"DriverName": "%s", "Driver": {"MachineName": "%s"}}`, driverName, filepath.Base(dir))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(content), 0644))
+}
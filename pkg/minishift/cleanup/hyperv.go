@@ -0,0 +1,102 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cleanup
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/shell/powershell"
+)
+
+// hypervExternalSwitch is the external virtual switch created by 'minishift setup' on Windows
+const hypervExternalSwitch = "minishift-external"
+
+// runPowerShell runs the PowerShell command and returns its standard output
+var runPowerShell = func(command string) (string, error) {
+	stdOut, stdErr, err := powershell.New().Execute(command)
+	if err != nil {
+		return "", fmt.Errorf("Error running '%s': %v %s", command, err, strings.TrimSpace(stdErr))
+	}
+	return stdOut, nil
+}
+
+type hypervDetector struct{}
+
+func (d *hypervDetector) available() bool {
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	out, err := runPowerShell("@(Get-Command Get-VM -ErrorAction SilentlyContinue).Count")
+	return err == nil && strings.TrimSpace(out) != "0"
+}
+
+func (d *hypervDetector) detect(home string, machines []Machine) ([]Orphan, error) {
+	out, err := runPowerShell("Get-VM | ForEach-Object { $_.Name + '|' + $_.Path }")
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	registered := make(map[string]bool)
+	for _, line := range lines(out) {
+		parts := strings.SplitN(line, "|", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name, path := parts[0], parts[1]
+		registered[name] = true
+		if !isWithin(path, home) || exists(path) {
+			continue
+		}
+		orphans = append(orphans, Orphan{
+			Kind:   "Hyper-V VM",
+			Name:   name,
+			Reason: "its machine directory is gone",
+			remove: func() error {
+				_, err := runPowerShell(fmt.Sprintf("Remove-VM -Name '%s' -Force", name))
+				return err
+			},
+		})
+	}
+	orphans = append(orphans, orphanedMachineDirs(machines, "hyperv", "Hyper-V", registered)...)
+
+	out, err = runPowerShell(fmt.Sprintf("Get-VMSwitch -Name '%s' -ErrorAction SilentlyContinue | ForEach-Object { $_.Name }", hypervExternalSwitch))
+	if err != nil || strings.TrimSpace(out) == "" {
+		return orphans, err
+	}
+	out, err = runPowerShell("Get-VMNetworkAdapter -VMName * | ForEach-Object { $_.SwitchName }")
+	if err != nil {
+		return orphans, err
+	}
+	for _, switchName := range lines(out) {
+		if switchName == hypervExternalSwitch {
+			return orphans, nil
+		}
+	}
+	orphans = append(orphans, Orphan{
+		Kind:   "Hyper-V virtual switch",
+		Name:   hypervExternalSwitch,
+		Reason: "no VM uses it",
+		remove: func() error {
+			_, err := runPowerShell(fmt.Sprintf("Remove-VMSwitch -Name '%s' -Force", hypervExternalSwitch))
+			return err
+		},
+	})
+	return orphans, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cleanup
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+)
+
+const libvirtURI = "qemu:///system"
+
+var domainDiskSource = regexp.MustCompile(`<source file='([^']+)'`)
+
+type kvmDetector struct{}
+
+func (d *kvmDetector) available() bool {
+	_, err := exec.LookPath("virsh")
+	return err == nil
+}
+
+func (d *kvmDetector) detect(home string, machines []Machine) ([]Orphan, error) {
+	out, err := virsh("list", "--all", "--name")
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	registered := make(map[string]bool)
+	for _, domain := range lines(out) {
+		registered[domain] = true
+		xml, err := virsh("dumpxml", domain)
+		if err != nil {
+			return nil, err
+		}
+		if missing := missingDisk(xml, home); missing != "" {
+			name := domain
+			orphans = append(orphans, Orphan{
+				Kind:   "KVM domain",
+				Name:   name,
+				Reason: fmt.Sprintf("its disk '%s' is gone", missing),
+				remove: func() error {
+					// destroy fails for domains which are not running
+					virsh("destroy", name)
+					_, err := virsh("undefine", name)
+					return err
+				},
+			})
+		}
+	}
+	// the libvirt network docker-machines is shared with docker-machine and therefore never considered an orphan
+	orphans = append(orphans, orphanedMachineDirs(machines, "kvm", "KVM", registered)...)
+	return orphans, nil
+}
+
+// missingDisk returns the first disk of the domain located within the Minishift home directory which does not exist
+func missingDisk(xml string, home string) string {
+	for _, match := range domainDiskSource.FindAllStringSubmatch(xml, -1) {
+		if isWithin(match[1], home) && !exists(match[1]) {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+func virsh(args ...string) (string, error) {
+	return runCommand("virsh", append([]string{"-c", libvirtURI}, args...)...)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cleanup
+
+import (
+	"bufio"
+	"net"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/profile"
+)
+
+// defaultHostOnlyCIDR is the default of the host-only-cidr setting
+const defaultHostOnlyCIDR = "192.168.99.1/24"
+
+var vboxListEntry = regexp.MustCompile(`^"(.*)" \{([0-9a-fA-F-]+)\}$`)
+
+type virtualBoxVM struct {
+	name             string
+	uuid             string
+	cfgFile          string
+	hostOnlyAdapters []string
+}
+
+type hostOnlyInterface struct {
+	name string
+	ip   net.IP
+}
+
+type virtualBoxDetector struct{}
+
+func (d *virtualBoxDetector) available() bool {
+	_, err := exec.LookPath(profile.VBoxManageCommand())
+	return err == nil
+}
+
+func (d *virtualBoxDetector) detect(home string, machines []Machine) ([]Orphan, error) {
+	vms, err := d.listVMs()
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	registered := make(map[string]bool)
+	usedInterfaces := make(map[string]bool)
+	for _, vm := range vms {
+		registered[vm.name] = true
+		for _, adapter := range vm.hostOnlyAdapters {
+			usedInterfaces[adapter] = true
+		}
+		if vm.cfgFile == "" || !isWithin(vm.cfgFile, home) || exists(vm.cfgFile) {
+			continue
+		}
+		uuid := vm.uuid
+		orphans = append(orphans, Orphan{
+			Kind:   "VirtualBox VM",
+			Name:   vm.name,
+			Reason: "its machine directory is gone",
+			remove: func() error {
+				_, err := vboxManage("unregistervm", uuid)
+				return err
+			},
+		})
+	}
+	orphans = append(orphans, orphanedMachineDirs(machines, "virtualbox", "VirtualBox", registered)...)
+
+	interfaces, err := d.listHostOnlyInterfaces()
+	if err != nil {
+		return orphans, err
+	}
+	// host-only interfaces are shared with other tools like Vagrant or docker-machine, only the ones within the
+	// networks of the Minishift VMs are considered
+	networks := hostOnlyNetworks(machines)
+	for _, iface := range interfaces {
+		if usedInterfaces[iface.name] || !withinAny(iface.ip, networks) {
+			continue
+		}
+		ifName := iface.name
+		orphans = append(orphans, Orphan{
+			Kind:   "VirtualBox host-only interface",
+			Name:   ifName,
+			Reason: "no VM uses it",
+			remove: func() error {
+				_, err := vboxManage("hostonlyif", "remove", ifName)
+				return err
+			},
+		})
+	}
+	return orphans, nil
+}
+
+func (d *virtualBoxDetector) listVMs() ([]virtualBoxVM, error) {
+	out, err := vboxManage("list", "vms")
+	if err != nil {
+		return nil, err
+	}
+
+	var vms []virtualBoxVM
+	for _, line := range lines(out) {
+		match := vboxListEntry.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		vm := virtualBoxVM{name: match[1], uuid: match[2]}
+		info, err := vboxManage("showvminfo", vm.uuid, "--machinereadable")
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines(info) {
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			value := strings.Trim(parts[1], "\"")
+			switch {
+			case parts[0] == "CfgFile":
+				vm.cfgFile = value
+			case strings.HasPrefix(parts[0], "hostonlyadapter"):
+				vm.hostOnlyAdapters = append(vm.hostOnlyAdapters, value)
+			}
+		}
+		vms = append(vms, vm)
+	}
+	return vms, nil
+}
+
+// listHostOnlyInterfaces returns all host-only interfaces of VirtualBox together with their IP address
+func (d *virtualBoxDetector) listHostOnlyInterfaces() ([]hostOnlyInterface, error) {
+	out, err := vboxManage("list", "hostonlyifs")
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaces []hostOnlyInterface
+	for _, line := range lines(out) {
+		switch {
+		case strings.HasPrefix(line, "Name:"):
+			interfaces = append(interfaces, hostOnlyInterface{name: strings.TrimSpace(strings.TrimPrefix(line, "Name:"))})
+		case strings.HasPrefix(line, "IPAddress:") && len(interfaces) > 0:
+			interfaces[len(interfaces)-1].ip = net.ParseIP(strings.TrimSpace(strings.TrimPrefix(line, "IPAddress:")))
+		}
+	}
+	return interfaces, nil
+}
+
+// hostOnlyNetworks returns the host-only networks of the VirtualBox VMs of Minishift, always including the default
+// network
+func hostOnlyNetworks(machines []Machine) []*net.IPNet {
+	cidrs := []string{defaultHostOnlyCIDR}
+	for _, machine := range machines {
+		if machine.DriverName == "virtualbox" && machine.HostOnlyCIDR != "" {
+			cidrs = append(cidrs, machine.HostOnlyCIDR)
+		}
+	}
+
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		if _, network, err := net.ParseCIDR(cidr); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+func withinAny(ip net.IP, networks []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func vboxManage(args ...string) (string, error) {
+	return runCommand(profile.VBoxManageCommand(), args...)
+}
+
+func lines(out string) []string {
+	var result []string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
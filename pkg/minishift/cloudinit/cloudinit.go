@@ -0,0 +1,318 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// SeedDirInVM is the directory of the cloud-init NoCloud data source inside the VM
+	SeedDirInVM = "/var/lib/cloud/seed/nocloud"
+
+	cloudConfigHeader = "#cloud-config"
+	scriptHeader      = "#!"
+	userDataFileName  = "user-data"
+	metaDataFileName  = "meta-data"
+)
+
+// UserData is a cloud-init user-data file, either a cloud-config document or a shell script
+type UserData struct {
+	Content []byte
+	Config  *CloudConfig // nil if the user-data is a script
+}
+
+// CloudConfig holds the cloud-config modules applied by Minishift if the ISO does not ship cloud-init itself
+type CloudConfig struct {
+	WriteFiles []WriteFile `yaml:"write_files,omitempty"`
+	Users      []User      `yaml:"users,omitempty"`
+	Mounts     [][]string  `yaml:"mounts,omitempty"`
+	Packages   []string    `yaml:"packages,omitempty"`
+	RunCmd     []RunCmd    `yaml:"runcmd,omitempty"`
+}
+
+// WriteFile is an entry of the write_files module
+type WriteFile struct {
+	Path        string `yaml:"path"`
+	Content     string `yaml:"content,omitempty"`
+	Encoding    string `yaml:"encoding,omitempty"`
+	Owner       string `yaml:"owner,omitempty"`
+	Permissions string `yaml:"permissions,omitempty"`
+	Append      bool   `yaml:"append,omitempty"`
+}
+
+// User is an entry of the users module
+type User struct {
+	Name              string     `yaml:"name"`
+	Groups            StringList `yaml:"groups,omitempty"`
+	Shell             string     `yaml:"shell,omitempty"`
+	Sudo              StringList `yaml:"sudo,omitempty"`
+	SSHAuthorizedKeys []string   `yaml:"ssh_authorized_keys,omitempty"`
+}
+
+// StringList is a list of strings which can also be given as a single comma separated string
+type StringList []string
+
+// UnmarshalYAML accepts a list of strings, a comma separated string or false for an empty list
+func (l *StringList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		*l = list
+		return nil
+	}
+
+	var disabled bool
+	if err := unmarshal(&disabled); err == nil && !disabled {
+		*l = nil
+		return nil
+	}
+
+	var value string
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	*l = nil
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			*l = append(*l, entry)
+		}
+	}
+	return nil
+}
+
+// RunCmd is an entry of the runcmd module, either a command line run by the shell or a list of arguments
+type RunCmd []string
+
+// UnmarshalYAML accepts a list of arguments or a single command line
+func (r *RunCmd) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var args []string
+	if err := unmarshal(&args); err == nil {
+		*r = args
+		return nil
+	}
+
+	var line string
+	if err := unmarshal(&line); err != nil {
+		return err
+	}
+	*r = []string{"sh", "-c", line}
+	return nil
+}
+
+// Parse reads and validates the user-data file at the specified path
+func Parse(path string) (*UserData, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	userData, err := ParseContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid cloud-init user-data file: %v", path, err)
+	}
+	return userData, nil
+}
+
+// ParseContent parses the content of a user-data file. Only cloud-config documents and scripts are supported.
+func ParseContent(content []byte) (*UserData, error) {
+	switch {
+	case bytes.HasPrefix(content, []byte(scriptHeader)):
+		return &UserData{Content: content}, nil
+	case bytes.HasPrefix(content, []byte(cloudConfigHeader)):
+		config := &CloudConfig{}
+		if err := yaml.Unmarshal(content, config); err != nil {
+			return nil, err
+		}
+		for _, file := range config.WriteFiles {
+			if file.Path == "" {
+				return nil, fmt.Errorf("write_files entry without path")
+			}
+		}
+		for _, user := range config.Users {
+			if user.Name == "" {
+				return nil, fmt.Errorf("users entry without name")
+			}
+		}
+		for _, mount := range config.Mounts {
+			if len(mount) < 2 {
+				return nil, fmt.Errorf("mounts entry %v needs a device and a mount point", mount)
+			}
+		}
+		return &UserData{Content: content, Config: config}, nil
+	default:
+		return nil, fmt.Errorf("the file needs to start with '%s' or '%s'", cloudConfigHeader, scriptHeader)
+	}
+}
+
+// Apply copies the user-data into the NoCloud seed directory of the VM and runs it. If the ISO ships cloud-init, it
+// processes the user-data itself. Otherwise scripts are run as is and the write_files, users, mounts, packages and
+// runcmd modules of a cloud-config document are applied by Minishift, using the package manager of the ISO flavor.
+// The package manager is empty if the ISO has none.
+//
+// The seed is copied over SSH rather than attached as a seed ISO when the VM is created, since not all drivers can
+// attach a second ISO and the Minishift ISO does not run cloud-init at boot. As the root file system of the ISO is
+// not persistent, Apply runs on every start, and the applied modules need to be safe to run repeatedly.
+func Apply(driver drivers.Driver, content string, instanceID string, packageManager string) error {
+	userData, err := ParseContent([]byte(content))
+	if err != nil {
+		return err
+	}
+
+	client, err := sshutil.NewSSHClient(driver)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	metaData := fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", instanceID, driver.GetMachineName())
+	if err := sshutil.Transfer(strings.NewReader(metaData), int64(len(metaData)), SeedDirInVM, metaDataFileName, "0644", client); err != nil {
+		return err
+	}
+	if err := sshutil.Transfer(bytes.NewReader(userData.Content), int64(len(userData.Content)), SeedDirInVM, userDataFileName, "0600", client); err != nil {
+		return err
+	}
+
+	sshCommander := provision.GenericSSHCommander{Driver: driver}
+	var commands []string
+	if _, err := sshCommander.SSHCommand("command -v cloud-init"); err == nil {
+		commands = []string{"sudo cloud-init init", "sudo cloud-init modules --mode=config", "sudo cloud-init modules --mode=final"}
+	} else if userData.Config == nil {
+		commands = []string{fmt.Sprintf("sudo sh %s/%s", SeedDirInVM, userDataFileName)}
+	} else if commands, err = Commands(userData.Config, packageManager); err != nil {
+		return err
+	}
+
+	for _, command := range commands {
+		if out, err := sshCommander.SSHCommand(command); err != nil {
+			return fmt.Errorf("Error running '%s': %v\n%s", command, err, out)
+		}
+	}
+	return nil
+}
+
+// Commands returns the shell commands applying the supported modules of config in the order cloud-init runs them.
+// Installing packages fails if packageManager is empty.
+func Commands(config *CloudConfig, packageManager string) ([]string, error) {
+	var commands []string
+	for _, file := range config.WriteFiles {
+		commands = append(commands, writeFileCommand(file))
+	}
+
+	for _, user := range config.Users {
+		commands = append(commands, userCommands(user)...)
+	}
+
+	for _, mount := range config.Mounts {
+		commands = append(commands, mountCommand(mount))
+	}
+
+	if len(config.Packages) > 0 {
+		if packageManager == "" {
+			return nil, fmt.Errorf("The ISO has no package manager to install the packages %v", config.Packages)
+		}
+		commands = append(commands, fmt.Sprintf("sudo %s install -y %s", packageManager, quoteAll(config.Packages)))
+	}
+
+	for _, args := range config.RunCmd {
+		commands = append(commands, "sudo "+quoteAll(args))
+	}
+	return commands, nil
+}
+
+func writeFileCommand(file WriteFile) string {
+	content := file.Content
+	if file.Encoding != "b64" && file.Encoding != "base64" {
+		content = base64.StdEncoding.EncodeToString([]byte(content))
+	}
+
+	path := sshutil.Quote(file.Path)
+	dir := file.Path[:strings.LastIndex(file.Path, "/")+1]
+	command := fmt.Sprintf("sudo mkdir -p %s && echo %s | base64 -d | sudo tee %s > /dev/null", sshutil.Quote(dir), sshutil.Quote(content), path)
+	if file.Append {
+		// the user-data is applied on every start, so the content is only appended if the file does not end with it yet
+		decoded, _ := base64.StdEncoding.DecodeString(content)
+		command = fmt.Sprintf("sudo mkdir -p %s && { [ \"$(sudo tail -c %d %s 2>/dev/null | base64 | tr -d '\\n')\" = %s ] || echo %s | base64 -d | sudo tee -a %s > /dev/null; }",
+			sshutil.Quote(dir), len(decoded), path, sshutil.Quote(content), sshutil.Quote(content), path)
+	}
+	if file.Permissions != "" {
+		command += fmt.Sprintf(" && sudo chmod %s %s", sshutil.Quote(file.Permissions), path)
+	}
+	if file.Owner != "" {
+		command += fmt.Sprintf(" && sudo chown %s %s", sshutil.Quote(file.Owner), path)
+	}
+	return command
+}
+
+func userCommands(user User) []string {
+	name := sshutil.Quote(user.Name)
+	useradd := "sudo useradd -m"
+	if user.Shell != "" {
+		useradd += " -s " + sshutil.Quote(user.Shell)
+	}
+	if len(user.Groups) > 0 {
+		useradd += " -G " + sshutil.Quote(strings.Join(user.Groups, ","))
+	}
+	commands := []string{fmt.Sprintf("id %s > /dev/null 2>&1 || %s %s", name, useradd, name)}
+
+	if len(user.Sudo) > 0 {
+		var rules []string
+		for _, rule := range user.Sudo {
+			rules = append(rules, fmt.Sprintf("%s %s", user.Name, rule))
+		}
+		content := base64.StdEncoding.EncodeToString([]byte(strings.Join(rules, "\n") + "\n"))
+		sudoers := sshutil.Quote("/etc/sudoers.d/90-cloud-init-" + user.Name)
+		commands = append(commands, fmt.Sprintf("echo %s | base64 -d | sudo tee %s > /dev/null && sudo chmod 0440 %s",
+			sshutil.Quote(content), sudoers, sudoers))
+	}
+
+	if len(user.SSHAuthorizedKeys) > 0 {
+		// keys already authorized by a previous start are not added again
+		addKeys := `mkdir -p -m 0700 ~/.ssh && touch ~/.ssh/authorized_keys && chmod 0600 ~/.ssh/authorized_keys && ` +
+			`while IFS= read -r key; do grep -qxF "$key" ~/.ssh/authorized_keys || echo "$key" >> ~/.ssh/authorized_keys; done`
+		content := base64.StdEncoding.EncodeToString([]byte(strings.Join(user.SSHAuthorizedKeys, "\n") + "\n"))
+		commands = append(commands, fmt.Sprintf("echo %s | base64 -d | sudo -H -u %s sh -c %s", sshutil.Quote(content), name, sshutil.Quote(addKeys)))
+	}
+	return commands
+}
+
+// mountCommand mounts an entry of the mounts module, given in fstab field order, unless it is mounted already
+func mountCommand(mount []string) string {
+	command := fmt.Sprintf("mountpoint -q %s || { sudo mkdir -p %s && sudo mount", sshutil.Quote(mount[1]), sshutil.Quote(mount[1]))
+	if len(mount) > 2 && mount[2] != "" && mount[2] != "auto" {
+		command += " -t " + sshutil.Quote(mount[2])
+	}
+	if len(mount) > 3 && mount[3] != "" && mount[3] != "defaults" {
+		command += " -o " + sshutil.Quote(mount[3])
+	}
+	return fmt.Sprintf("%s %s %s; }", command, sshutil.Quote(mount[0]), sshutil.Quote(mount[1]))
+}
+
+func quoteAll(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = sshutil.Quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
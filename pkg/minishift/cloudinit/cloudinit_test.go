@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const cloudConfig = `#cloud-config
+write_files:
+- path: /etc/motd
+  content: Welcome
+  permissions: '0644'
+users:
+- name: dev
+  groups: wheel, docker
+  sudo: ALL=(ALL) NOPASSWD:ALL
+  ssh_authorized_keys:
+  - ssh-rsa AAAA dev@example.com
+mounts:
+- [ "nfs.example.com:/export", /mnt/data, nfs, "ro", 0, 0 ]
+packages:
+- git
+- tmux
+runcmd:
+- [ systemctl, restart, sshd ]
+- echo 'done' > /tmp/cloud-init.done
+`
+
+func TestParseContentCloudConfig(t *testing.T) {
+	userData, err := ParseContent([]byte(cloudConfig))
+	assert.NoError(t, err)
+	assert.NotNil(t, userData.Config)
+
+	config := userData.Config
+	assert.Equal(t, "/etc/motd", config.WriteFiles[0].Path)
+	assert.Equal(t, StringList{"wheel", "docker"}, config.Users[0].Groups)
+	assert.Equal(t, StringList{"ALL=(ALL) NOPASSWD:ALL"}, config.Users[0].Sudo)
+	assert.Equal(t, []string{"nfs.example.com:/export", "/mnt/data", "nfs", "ro", "0", "0"}, config.Mounts[0])
+	assert.Equal(t, []string{"git", "tmux"}, config.Packages)
+	assert.Equal(t, RunCmd{"systemctl", "restart", "sshd"}, config.RunCmd[0])
+	assert.Equal(t, RunCmd{"sh", "-c", "echo 'done' > /tmp/cloud-init.done"}, config.RunCmd[1])
+}
+
+func TestParseContentScript(t *testing.T) {
+	userData, err := ParseContent([]byte("#!/bin/sh\necho hello\n"))
+	assert.NoError(t, err)
+	assert.Nil(t, userData.Config)
+}
+
+func TestParseContentInvalid(t *testing.T) {
+	var testCases = []struct {
+		content  string
+		expected string
+	}{
+		{"packages: [git]", "needs to start with"},
+		{"#cloud-config\nusers:\n- groups: wheel\n", "users entry without name"},
+		{"#cloud-config\nwrite_files:\n- content: foo\n", "write_files entry without path"},
+		{"#cloud-config\nmounts:\n- [ /dev/sdb ]\n", "needs a device and a mount point"},
+		{"#cloud-config\npackages: {git: true}\n", "cannot unmarshal"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := ParseContent([]byte(testCase.content))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), testCase.expected)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	userData, err := ParseContent([]byte(cloudConfig))
+	assert.NoError(t, err)
+
+	commands, err := Commands(userData.Config, "yum")
+	assert.NoError(t, err)
+	expected := []string{
+		"sudo mkdir -p '/etc/' && echo 'V2VsY29tZQ==' | base64 -d | sudo tee '/etc/motd' > /dev/null && sudo chmod '0644' '/etc/motd'",
+		"id 'dev' > /dev/null 2>&1 || sudo useradd -m -G 'wheel,docker' 'dev'",
+		"echo 'ZGV2IEFMTD0oQUxMKSBOT1BBU1NXRDpBTEwK' | base64 -d | sudo tee '/etc/sudoers.d/90-cloud-init-dev' > /dev/null && sudo chmod 0440 '/etc/sudoers.d/90-cloud-init-dev'",
+		"echo 'c3NoLXJzYSBBQUFBIGRldkBleGFtcGxlLmNvbQo=' | base64 -d | sudo -H -u 'dev' sh -c 'mkdir -p -m 0700 ~/.ssh && touch ~/.ssh/authorized_keys && chmod 0600 ~/.ssh/authorized_keys && while IFS= read -r key; do grep -qxF \"$key\" ~/.ssh/authorized_keys || echo \"$key\" >> ~/.ssh/authorized_keys; done'",
+		"mountpoint -q '/mnt/data' || { sudo mkdir -p '/mnt/data' && sudo mount -t 'nfs' -o 'ro' 'nfs.example.com:/export' '/mnt/data'; }",
+		"sudo yum install -y 'git' 'tmux'",
+		"sudo 'systemctl' 'restart' 'sshd'",
+		`sudo 'sh' '-c' 'echo '"'"'done'"'"' > /tmp/cloud-init.done'`,
+	}
+	assert.Equal(t, expected, commands)
+}
+
+func TestAppendingWriteFileIsIdempotent(t *testing.T) {
+	commands, err := Commands(&CloudConfig{WriteFiles: []WriteFile{{Path: "/etc/hosts", Content: "10.0.0.1 nfs\n", Append: true}}}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`sudo mkdir -p '/etc/' && { [ "$(sudo tail -c 13 '/etc/hosts' 2>/dev/null | base64 | tr -d '\n')" = 'MTAuMC4wLjEgbmZzCg==' ] || echo 'MTAuMC4wLjEgbmZzCg==' | base64 -d | sudo tee -a '/etc/hosts' > /dev/null; }`,
+	}, commands)
+}
+
+func TestCommandsWithoutPackageManager(t *testing.T) {
+	_, err := Commands(&CloudConfig{Packages: []string{"git"}}, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no package manager")
+}
@@ -0,0 +1,131 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/minishift/minishift/pkg/util/filehelper"
+)
+
+const (
+	dataDiskDriverFile = "driver"
+
+	// dataDiskStateDir is the directory on the disk holding the state of the OpenShift cluster, including its
+	// persistent volumes and the host folder units
+	dataDiskStateDir = "/var/lib/minishift"
+)
+
+var (
+	// the SSH keys of the VM are stored on its disk, so they need to be kept together with the disk
+	dataDiskKeyFiles = []string{"id_rsa", "id_rsa.pub"}
+)
+
+// DataDisk is the disk of a Minishift VM, which is kept across VM recreation. The root file system of the ISO is not
+// persistent, the disk only holds the data of the VM. Before the disk is kept and after it is restored, everything
+// but /var/lib/docker is removed from it, so that only the Docker images and the build cache are reused.
+type DataDisk struct {
+	// DataDir is the profile specific directory the disk is kept in while no VM exists
+	DataDir string
+	// MachineDir is the directory of the libmachine VM
+	MachineDir  string
+	MachineName string
+}
+
+// DiskFileName returns the name of the disk image the specified driver creates in the machine directory.
+// An empty string is returned if keeping the disk is not supported for the driver.
+func DiskFileName(driverName string, machineName string) string {
+	switch driverName {
+	case "kvm":
+		return machineName + ".img"
+	case "hyperkit", "xhyve":
+		return machineName + ".rawdisk"
+	case "hyperv":
+		return "disk.vhd"
+	}
+	return ""
+}
+
+// IsKeepDataSupported returns true if the disk of VMs created by the specified driver can be kept.
+func IsKeepDataSupported(driverName string) bool {
+	return DiskFileName(driverName, "minishift") != ""
+}
+
+// Keep moves the disk of the stopped VM into the data directory, so that it survives the deletion of the VM.
+func (d *DataDisk) Keep(driverName string) error {
+	diskFile := DiskFileName(driverName, d.MachineName)
+	if diskFile == "" {
+		return fmt.Errorf("Keeping the VM data is not supported for the '%s' driver", driverName)
+	}
+
+	if err := os.MkdirAll(d.DataDir, 0755); err != nil {
+		return err
+	}
+
+	for _, file := range append([]string{diskFile}, dataDiskKeyFiles...) {
+		if err := os.Rename(filepath.Join(d.MachineDir, file), filepath.Join(d.DataDir, file)); err != nil {
+			return fmt.Errorf("Error keeping '%s': %v", file, err)
+		}
+	}
+
+	return ioutil.WriteFile(filepath.Join(d.DataDir, dataDiskDriverFile), []byte(driverName), 0644)
+}
+
+// IsKept returns true if a disk created with the specified driver is kept in the data directory.
+func (d *DataDisk) IsKept(driverName string) bool {
+	content, err := ioutil.ReadFile(filepath.Join(d.DataDir, dataDiskDriverFile))
+	if err != nil || strings.TrimSpace(string(content)) != driverName {
+		return false
+	}
+	return filehelper.Exists(filepath.Join(d.DataDir, DiskFileName(driverName, d.MachineName)))
+}
+
+// Restore replaces the disk of the newly created and stopped VM with the kept disk.
+func (d *DataDisk) Restore(driverName string) error {
+	if !d.IsKept(driverName) {
+		return fmt.Errorf("No data disk for the '%s' driver is kept in '%s'", driverName, d.DataDir)
+	}
+
+	diskFile := DiskFileName(driverName, d.MachineName)
+	for _, file := range append([]string{diskFile}, dataDiskKeyFiles...) {
+		if err := os.Rename(filepath.Join(d.DataDir, file), filepath.Join(d.MachineDir, file)); err != nil {
+			return fmt.Errorf("Error restoring '%s': %v", file, err)
+		}
+	}
+
+	return os.Remove(filepath.Join(d.DataDir, dataDiskDriverFile))
+}
+
+// Discard removes a kept disk. It returns true if a disk was kept.
+func (d *DataDisk) Discard() (bool, error) {
+	if !filehelper.Exists(d.DataDir) {
+		return false, nil
+	}
+	return true, os.RemoveAll(d.DataDir)
+}
+
+// Prune removes the containers and the state of the OpenShift cluster from the disk of the running VM, leaving only
+// /var/lib/docker with the images and the build cache.
+func Prune(commander provision.SSHCommander) error {
+	_, err := commander.SSHCommand(fmt.Sprintf("docker ps -aq | xargs -r docker rm -f && sudo rm -rf %s", dataDiskStateDir))
+	return err
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Data_Disk_Is_Kept_And_Restored(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-datadisk-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	disk := &DataDisk{
+		DataDir:     filepath.Join(testDir, "data"),
+		MachineDir:  filepath.Join(testDir, "machines", "minishift"),
+		MachineName: "minishift",
+	}
+	assert.NoError(t, os.MkdirAll(disk.MachineDir, 0755))
+	for _, file := range []string{"minishift.img", "id_rsa", "id_rsa.pub"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(disk.MachineDir, file), []byte("old "+file), 0644))
+	}
+
+	assert.NoError(t, disk.Keep("kvm"))
+	assert.True(t, disk.IsKept("kvm"))
+	assert.False(t, disk.IsKept("hyperv"))
+
+	// the new VM comes with its own disk and keys
+	for _, file := range []string{"minishift.img", "id_rsa", "id_rsa.pub"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(disk.MachineDir, file), []byte("new "+file), 0644))
+	}
+
+	assert.NoError(t, disk.Restore("kvm"))
+	assert.False(t, disk.IsKept("kvm"))
+	content, _ := ioutil.ReadFile(filepath.Join(disk.MachineDir, "minishift.img"))
+	assert.Equal(t, "old minishift.img", string(content))
+	content, _ = ioutil.ReadFile(filepath.Join(disk.MachineDir, "id_rsa"))
+	assert.Equal(t, "old id_rsa", string(content))
+}
+
+func Test_Kept_Data_Disk_Is_Discarded(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-datadisk-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	disk := &DataDisk{DataDir: filepath.Join(testDir, "data"), MachineName: "minishift"}
+	discarded, err := disk.Discard()
+	assert.NoError(t, err)
+	assert.False(t, discarded)
+
+	assert.NoError(t, os.MkdirAll(disk.DataDir, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(disk.DataDir, "minishift.img"), []byte("disk"), 0644))
+	discarded, err = disk.Discard()
+	assert.NoError(t, err)
+	assert.True(t, discarded)
+	assert.False(t, disk.IsKept("kvm"))
+}
+
+func Test_Keep_Data_Is_Not_Supported_For_VirtualBox(t *testing.T) {
+	assert.False(t, IsKeepDataSupported("virtualbox"))
+	disk := &DataDisk{}
+	assert.EqualError(t, disk.Keep("virtualbox"), "Keeping the VM data is not supported for the 'virtualbox' driver")
+}
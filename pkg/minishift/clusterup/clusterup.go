@@ -22,7 +22,7 @@ import (
 
 	"github.com/docker/machine/libmachine/host"
 	"github.com/docker/machine/libmachine/provision"
-	"github.com/golang/glog"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"os"
 	"strings"
 
@@ -32,21 +32,45 @@ import (
 	"github.com/minishift/minishift/pkg/minishift/addon/manager"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	minishiftKubeConfig "github.com/minishift/minishift/pkg/minishift/kubeconfig"
 	"github.com/minishift/minishift/pkg/minishift/oc"
 	"regexp"
 
 	"github.com/minishift/minishift/pkg/minishift/docker"
 	"github.com/minishift/minishift/pkg/util"
 	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
+	"github.com/spf13/viper"
 )
 
+var logger = logging.For("clusterup")
+
 const (
 	ipKey            = "ip"
 	routingSuffixKey = "routing-suffix"
 	user             = "user"
 	envPrefix        = "env."
+	configPrefix     = "config."
+	// addOnEnvPrefix is the prefix of the environment variables available to add-ons
+	addOnEnvPrefix = "MINISHIFT_ADDON_"
+)
+
+var (
+	// addOnEnvVars are the environment variables available to add-ons besides the ones prefixed with addOnEnvPrefix
+	addOnEnvVars = map[string]bool{"USER": true, "USERNAME": true, "LANG": true, "TZ": true}
+
+	// OpenShiftComponents are the components 'cluster up' deploys on top of the Kubernetes control plane by default
+	OpenShiftComponents = []string{"centos-imagestreams", "persistent-volumes", "registry", "router", "sample-templates", "web-console"}
 )
 
+// KubernetesOnlyEnableFlag returns the 'cluster up' flag disabling all of the OpenShiftComponents.
+func KubernetesOnlyEnableFlag() string {
+	var disabled []string
+	for _, component := range OpenShiftComponents {
+		disabled = append(disabled, "-"+component)
+	}
+	return fmt.Sprintf("--enable=%s", strings.Join(disabled, ","))
+}
+
 type ClusterUpConfig struct {
 	OpenShiftVersion     string
 	MachineName          string
@@ -62,6 +86,9 @@ type ClusterUpConfig struct {
 	SSHCommander         provision.SSHCommander
 	OcBinaryPathInsideVM string
 	SshUser              string
+	ComponentImages      map[string]string
+	ExtraFlags           []string
+	KubernetesOnly       bool
 }
 
 // ClusterUp execute oc binary in order to run 'cluster up'
@@ -79,6 +106,13 @@ func ClusterUp(config *ClusterUpConfig, clusterUpParams map[string]string) (stri
 		cmdArgs = append(cmdArgs, value)
 	}
 
+	if config.KubernetesOnly {
+		cmdArgs = append(cmdArgs, KubernetesOnlyEnableFlag())
+	}
+
+	// Validated flags of the cluster-up-flags setting are passed on as is
+	cmdArgs = append(cmdArgs, config.ExtraFlags...)
+
 	if minishiftConfig.EnableExperimental {
 		// Deal with extra flags (add to command arguments)
 		if len(extraFlags) > 0 {
@@ -91,9 +125,7 @@ func ClusterUp(config *ClusterUpConfig, clusterUpParams map[string]string) (stri
 		}
 	}
 
-	if glog.V(2) {
-		fmt.Printf("-- Running 'oc' with: '%s'\n", strings.Join(cmdArgs, " "))
-	}
+	logger.Debugf("-- Running 'oc' with: '%s'", strings.Join(cmdArgs, " "))
 	cmd := fmt.Sprintf("%s %s", config.OcBinaryPathInsideVM, strings.Join(cmdArgs, " "))
 	out, err := config.SSHCommander.SSHCommand(cmd)
 	if err != nil {
@@ -105,9 +137,7 @@ func ClusterUp(config *ClusterUpConfig, clusterUpParams map[string]string) (stri
 // AddComponent add a component to running Openshift cluster
 func AddComponent(sshCommander provision.SSHCommander, ocBinaryPathInsideVM string, basedir string, componentName string, imageToUse string) (string, error) {
 	cmdArgs := []string{"cluster", "add", fmt.Sprintf("--base-dir=%s", basedir), fmt.Sprintf("--image=%s", imageToUse), componentName}
-	if glog.V(2) {
-		fmt.Printf("-- Running 'oc' with: '%s'\n", strings.Join(cmdArgs, " "))
-	}
+	logger.Debugf("-- Running 'oc' with: '%s'", strings.Join(cmdArgs, " "))
 	cmd := fmt.Sprintf("%s %s", ocBinaryPathInsideVM, strings.Join(cmdArgs, " "))
 	out, err := sshCommander.SSHCommand(cmd)
 	if err != nil {
@@ -133,16 +163,28 @@ func PostClusterUp(clusterUpConfig *ClusterUpConfig, sshCommander provision.SSHC
 		return err
 	}
 
-	err = ocRunner.AddSystemAdminEntryToKubeConfig(clusterUpConfig.OcPath)
+	var owned []string
+	if minishiftConfig.AllInstancesConfig != nil {
+		owned = minishiftConfig.AllInstancesConfig.KubeConfigReferences.Keys(clusterUpConfig.MachineName)
+	}
+	conflicts, err := ocRunner.AddSystemAdminEntryToKubeConfig(clusterUpConfig.OcPath, owned)
 	if err != nil {
 		return err
 	}
+	for _, key := range conflicts {
+		fmt.Println(fmt.Sprintf("   WARN: Keeping the existing %s in the kubeconfig, it is not owned by the '%s' profile", strings.Replace(key, "kubeconfig-", "", 1), clusterUpConfig.MachineName))
+	}
 
-	err = ocRunner.AddCliContext(clusterUpConfig.MachineName, clusterUpConfig.Ip, clusterUpConfig.User, clusterUpConfig.Project, runner, clusterUpConfig.OcPath)
+	err = ocRunner.AddCliContext(minishiftKubeConfig.ContextName(clusterUpConfig.MachineName), clusterUpConfig.Ip, clusterUpConfig.User, clusterUpConfig.Project, runner, clusterUpConfig.OcPath)
 	if err != nil {
 		return err
 	}
 
+	// the components the images and add-ons refer to are not deployed in Kubernetes only mode
+	if clusterUpConfig.KubernetesOnly {
+		return nil
+	}
+
 	err = applyAddOns(addOnManager, clusterUpConfig.Ip, clusterUpConfig.RoutingSuffix, clusterUpConfig.SshUser, clusterUpConfig.AddonEnv, ocRunner, sshCommander)
 	if err != nil {
 		return err
@@ -172,7 +214,10 @@ func GetExecutionContext(ip string, routingSuffix string, sshUser string, addOnE
 	context.AddToContext(ipKey, ip)
 	context.AddToContext(routingSuffixKey, routingSuffix)
 	context.AddToContext(user, sshUser)
+	addProfileConfigToContext(context, viper.AllSettings())
+	addEnvironmentToContext(context, os.Environ())
 
+	// variables specified explicitly take precedence over profile configuration and environment
 	for _, env := range addOnEnv {
 		match, _ := regexp.Match(".*=.*", []byte(env))
 		if !match {
@@ -199,6 +244,44 @@ func GetExecutionContext(ip string, routingSuffix string, sshUser string, addOnE
 	return context, nil
 }
 
+// addProfileConfigToContext makes the settings of the profile available as 'config.<key>' for interpolation.
+// Settings which are not a scalar or a list are skipped, and so are secrets and keys which are no configuration
+// property, like the decrypted credentials and flags of other commands.
+func addProfileConfigToContext(context *command.ExecutionContext, settings map[string]interface{}) {
+	for key, value := range settings {
+		if !configCmd.IsVisibleToAddOns(key) {
+			continue
+		}
+		switch v := value.(type) {
+		case string, bool, int, int64, float64:
+			context.AddToContext(configPrefix+key, fmt.Sprint(v))
+		case []string:
+			context.AddToContext(configPrefix+key, strings.Join(v, ","))
+		case []interface{}:
+			values := make([]string, len(v))
+			for i, element := range v {
+				values[i] = fmt.Sprint(element)
+			}
+			context.AddToContext(configPrefix+key, strings.Join(values, ","))
+		}
+	}
+}
+
+// addEnvironmentToContext makes the environment variables prefixed with MINISHIFT_ADDON_, as well as a few general
+// ones like USER, available as 'env.<name>' for interpolation. The rest of the environment might contain credentials.
+func addEnvironmentToContext(context *command.ExecutionContext, environ []string) {
+	for _, env := range environ {
+		tokens := strings.SplitN(env, "=", 2)
+		if len(tokens) != 2 || tokens[0] == "" {
+			continue
+		}
+		if !strings.HasPrefix(tokens[0], addOnEnvPrefix) && !addOnEnvVars[tokens[0]] {
+			continue
+		}
+		context.AddToContext(envPrefix+tokens[0], tokens[1])
+	}
+}
+
 func CopyOcBinaryFromImageToVM(dockerCommander docker.DockerCommander, image string, pathInVm string) error {
 	if _, err := dockerCommander.Create("--name tmp", image); err != nil {
 		return err
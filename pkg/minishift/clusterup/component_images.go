@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterup
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/oc"
+)
+
+const (
+	RouterComponent     = "router"
+	RegistryComponent   = "registry"
+	WebConsoleComponent = "web-console"
+)
+
+// component describes an OpenShift component deployed by 'cluster up' whose image can be overridden.
+type component struct {
+	resource  string
+	container string
+	namespace string
+}
+
+var components = map[string]component{
+	RouterComponent:     {resource: "dc/router", container: "router", namespace: "default"},
+	RegistryComponent:   {resource: "dc/docker-registry", container: "registry", namespace: "default"},
+	WebConsoleComponent: {resource: "deployment/webconsole", container: "webconsole", namespace: "openshift-web-console"},
+}
+
+// ApplyComponentImages replaces the images of the components deployed by 'cluster up' with the specified ones.
+// images maps component names to image references. Components without an image are left untouched.
+func ApplyComponentImages(ocRunner *oc.OcRunner, images map[string]string) error {
+	for _, name := range []string{RouterComponent, RegistryComponent, WebConsoleComponent} {
+		image := images[name]
+		if image == "" {
+			continue
+		}
+
+		c := components[name]
+		fmt.Println(fmt.Sprintf("-- Using image '%s' for component '%s'", image, name))
+		cmd := fmt.Sprintf("set image %s %s=%s -n %s", c.resource, c.container, image, c.namespace)
+		errBuffer := new(bytes.Buffer)
+		exitCode := ocRunner.Run(cmd, nil, errBuffer)
+		if exitCode != 0 {
+			return fmt.Errorf("Error setting the image of component '%s': %s", name, strings.TrimSpace(errBuffer.String()))
+		}
+	}
+	return nil
+}
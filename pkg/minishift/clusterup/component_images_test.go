@@ -0,0 +1,66 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterup
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingRunner struct {
+	commands []string
+	exitCode int
+}
+
+func (r *recordingRunner) Run(stdOut io.Writer, stdErr io.Writer, commandPath string, args ...string) int {
+	r.commands = append(r.commands, strings.Join(args, " "))
+	return r.exitCode
+}
+
+func (r *recordingRunner) Output(command string, args ...string) ([]byte, error) {
+	return nil, nil
+}
+
+func Test_component_images_are_set(t *testing.T) {
+	runner := &recordingRunner{}
+	ocRunner := &oc.OcRunner{OcPath: "oc", KubeConfigPath: "/tmp/kubeconfig", Runner: runner}
+
+	err := ApplyComponentImages(ocRunner, map[string]string{
+		RouterComponent:     "myregistry:5000/patched/router:latest",
+		RegistryComponent:   "",
+		WebConsoleComponent: "myregistry:5000/patched/console:latest",
+	})
+
+	assert.NoError(t, err)
+	expected := []string{
+		"--config=/tmp/kubeconfig set image dc/router router=myregistry:5000/patched/router:latest -n default",
+		"--config=/tmp/kubeconfig set image deployment/webconsole webconsole=myregistry:5000/patched/console:latest -n openshift-web-console",
+	}
+	assert.Equal(t, expected, runner.commands)
+}
+
+func Test_failing_component_image_update_returns_error(t *testing.T) {
+	runner := &recordingRunner{exitCode: 1}
+	ocRunner := &oc.OcRunner{OcPath: "oc", KubeConfigPath: "/tmp/kubeconfig", Runner: runner}
+
+	err := ApplyComponentImages(ocRunner, map[string]string{RegistryComponent: "patched/registry"})
+	assert.Error(t, err)
+}
@@ -19,21 +19,53 @@ package config
 import (
 	"encoding/json"
 	"errors"
-	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
 	"io/ioutil"
 	"os"
+	"reflect"
+	"time"
+
+	"github.com/minishift/minishift/pkg/minishift/addon/repository"
+	"github.com/minishift/minishift/pkg/minishift/cache"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/minishift/minishift/pkg/util/os/lock"
 )
 
+// writeLockTimeout is the time to wait for another Minishift process writing the global config file
+const writeLockTimeout = 10 * time.Second
+
 var AllInstancesConfig *GlobalConfigType
 
 type GlobalConfigType struct {
 	FilePath string `json:"-"`
+	// loaded is the content of the file as last read or written by this process, the base to merge changes against
+	loaded []byte
+
+	HostFolders          []config.HostFolderConfig
+	ActiveProfile        string
+	SftpdPID             int
+	ProxyPID             int
+	SystrayPID           int
+	RegistryCachePID     int
+	RegistryCacheHostIPs []string
+	CacheReferences      cache.References
+	AddonRepos           []repository.Repository
+	// KubeConfigReferences maps the entries added to the user's kubeconfig to the profiles which use them
+	KubeConfigReferences cache.References
+	// ProtectedProfiles are the profiles which can only be deleted or reset with --force
+	ProtectedProfiles []string
+	// ProfileLabels maps the profiles to the key=value labels attached with 'minishift profile label set'
+	ProfileLabels map[string]map[string]string
+	// IsoFlavors are the ISO flavors added with 'minishift iso add-alias', by alias
+	IsoFlavors map[string]IsoFlavor
+}
 
-	HostFolders   []config.HostFolderConfig
-	ActiveProfile string
-	SftpdPID      int
-	ProxyPID      int
-	SystrayPID    int
+// IsoFlavor is a user-defined ISO image and the capabilities provisioning relies on
+type IsoFlavor struct {
+	URL            string
+	InitSystem     string
+	PackageManager string
+	// DockerVersions are the Docker engine versions which can be installed with the package manager
+	DockerVersions []string
 }
 
 // Create new object with data if file exists or
@@ -57,17 +89,111 @@ func NewAllInstancesConfig(path string) (*GlobalConfigType, error) {
 	return cfg, nil
 }
 
+// Write writes the global config file. Since the file is shared between all profiles, the write is guarded by a
+// file lock and goes through a temporary file, so that concurrent Minishift processes never see a partial file.
+// Under the lock, the file is read again and the settings changed by this process since it loaded the file are merged
+// into the current content, so that the changes of other processes are kept.
 func (cfg *GlobalConfigType) Write() error {
-	jsonData, err := json.MarshalIndent(cfg, "", "\t")
+	fileLock, err := lock.Acquire(cfg.FilePath+".lock", writeLockTimeout)
 	if err != nil {
 		return err
 	}
+	defer fileLock.Release()
 
-	if err = ioutil.WriteFile(cfg.FilePath, jsonData, 0644); err != nil {
+	jsonData, err := cfg.mergeWithCurrent()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if err := writeFileAtomic(cfg.FilePath, jsonData); err != nil {
+		return err
+	}
+	return cfg.decode(jsonData)
+}
+
+// mergeWithCurrent returns the content of the file with the changes of this process applied. Settings changed by
+// both this and another process get the value of this process.
+func (cfg *GlobalConfigType) mergeWithCurrent() ([]byte, error) {
+	ours, err := json.MarshalIndent(cfg, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := ioutil.ReadFile(cfg.FilePath)
+	if os.IsNotExist(err) || (err == nil && !json.Valid(current)) {
+		return ours, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	base := cfg.loaded
+	if base == nil {
+		base = []byte("{}")
+	}
+
+	var baseValue, ourValue, currentValue interface{}
+	for _, decode := range []struct {
+		raw   []byte
+		value *interface{}
+	}{{base, &baseValue}, {ours, &ourValue}, {current, &currentValue}} {
+		if err := json.Unmarshal(decode.raw, decode.value); err != nil {
+			return nil, err
+		}
+	}
+
+	merged := mergeValues(baseValue, ourValue, currentValue)
+	return json.MarshalIndent(merged, "", "\t")
+}
+
+// mergeValues merges the changes from base to ours into theirs, recursing into objects, so that for example two
+// processes labelling different profiles both keep their labels.
+func mergeValues(base interface{}, ours interface{}, theirs interface{}) interface{} {
+	if reflect.DeepEqual(base, ours) {
+		return theirs
+	}
+	if reflect.DeepEqual(base, theirs) {
+		return ours
+	}
+
+	baseObject, baseIsObject := base.(map[string]interface{})
+	ourObject, oursIsObject := ours.(map[string]interface{})
+	theirObject, theirsIsObject := theirs.(map[string]interface{})
+	if !oursIsObject || !theirsIsObject {
+		return ours
+	}
+	if !baseIsObject {
+		baseObject = map[string]interface{}{}
+	}
+
+	merged := make(map[string]interface{})
+	keys := make(map[string]bool)
+	for _, object := range []map[string]interface{}{baseObject, ourObject, theirObject} {
+		for key := range object {
+			keys[key] = true
+		}
+	}
+	for key := range keys {
+		baseValue, inBase := baseObject[key]
+		ourValue, inOurs := ourObject[key]
+		theirValue, inTheirs := theirObject[key]
+
+		switch {
+		case inBase && !inOurs:
+			// removed by this process
+			continue
+		case inBase && !inTheirs && reflect.DeepEqual(baseValue, ourValue):
+			// removed by another process
+			continue
+		case !inOurs:
+			merged[key] = theirValue
+		case !inTheirs:
+			merged[key] = ourValue
+		default:
+			merged[key] = mergeValues(baseValue, ourValue, theirValue)
+		}
+	}
+	return merged
 }
 
 func (cfg *GlobalConfigType) Delete() error {
@@ -88,6 +214,15 @@ func (cfg *GlobalConfigType) read() error {
 		return errors.New("Invalid JSON")
 	}
 
-	json.Unmarshal(raw, &cfg)
+	return cfg.decode(raw)
+}
+
+// decode replaces the settings with the content of the file and records the content as base of the next write.
+func (cfg *GlobalConfigType) decode(raw []byte) error {
+	decoded := GlobalConfigType{FilePath: cfg.FilePath, HostFolders: []config.HostFolderConfig{}}
+	// settings of an unexpected type are left empty instead of failing, like they always were
+	json.Unmarshal(raw, &decoded)
+	decoded.loaded = raw
+	*cfg = decoded
 	return nil
 }
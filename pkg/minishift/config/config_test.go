@@ -18,9 +18,11 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -103,3 +105,67 @@ func setup(t *testing.T) {
 func teardown() {
 	os.RemoveAll(testDir)
 }
+
+func TestGlobalConfigWriteKeepsChangesOfOtherProcesses(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	path := filepath.Join(testDir, "allinstances.json")
+	first, err := NewAllInstancesConfig(path)
+	assert.NoError(t, err)
+	first.ProfileLabels = map[string]map[string]string{"foo": {"team": "a"}}
+	assert.NoError(t, first.Write())
+
+	// both processes load the file before either of them writes
+	one, _ := NewAllInstancesConfig(path)
+	other, _ := NewAllInstancesConfig(path)
+
+	one.ActiveProfile = "foo"
+	one.ProfileLabels["bar"] = map[string]string{"team": "b"}
+	assert.NoError(t, one.Write())
+
+	other.ProtectedProfiles = []string{"foo"}
+	delete(other.ProfileLabels, "foo")
+	assert.NoError(t, other.Write())
+
+	merged, _ := NewAllInstancesConfig(path)
+	assert.Equal(t, "foo", merged.ActiveProfile)
+	assert.Equal(t, []string{"foo"}, merged.ProtectedProfiles)
+	assert.Equal(t, map[string]map[string]string{"bar": {"team": "b"}}, merged.ProfileLabels)
+	assert.Equal(t, merged.ProfileLabels, other.ProfileLabels, "The writer should see the merged content")
+}
+
+func TestGlobalConfigConcurrentWriters(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	path := filepath.Join(testDir, "allinstances.json")
+	_, err := NewAllInstancesConfig(path)
+	assert.NoError(t, err)
+
+	const writes = 20
+	var wg sync.WaitGroup
+	for _, profile := range []string{"foo", "bar"} {
+		wg.Add(1)
+		go func(profile string) {
+			defer wg.Done()
+			cfg, err := NewAllInstancesConfig(path)
+			assert.NoError(t, err)
+			for i := 0; i < writes; i++ {
+				if cfg.ProfileLabels == nil {
+					cfg.ProfileLabels = make(map[string]map[string]string)
+				}
+				if cfg.ProfileLabels[profile] == nil {
+					cfg.ProfileLabels[profile] = make(map[string]string)
+				}
+				cfg.ProfileLabels[profile][fmt.Sprintf("label-%d", i)] = "value"
+				assert.NoError(t, cfg.Write())
+			}
+		}(profile)
+	}
+	wg.Wait()
+
+	cfg, _ := NewAllInstancesConfig(path)
+	assert.Len(t, cfg.ProfileLabels["foo"], writes)
+	assert.Len(t, cfg.ProfileLabels["bar"], writes)
+}
@@ -16,6 +16,12 @@ limitations under the License.
 
 package config
 
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
 func checkDriver(driverName string) bool {
 	if InstanceStateConfig.VMDriver == driverName {
 		return true
@@ -34,3 +40,30 @@ func IsHyperV() bool {
 func IsKVM() bool {
 	return checkDriver("kvm")
 }
+
+// writeFileAtomic writes the data to a temporary file next to path and renames it to path, so that a crash or an
+// interrupt never leaves a truncated file behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(data)
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmpFile.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
@@ -33,12 +33,29 @@ type InstanceConfigType struct {
 	CacheImages []string `json:"cache-images"`
 	HostFolders []hostFolderConfig.HostFolderConfig
 	AddonConfig map[string]*addOnConfig.AddOnConfig `json:"addons"`
+	PatchSets   map[string]*PatchSet                `json:"patch-sets"`
+	Users       map[string]*User                    `json:"users"`
+	// PresetAddOns are the add-ons enabled by the preset the VM was created with
+	PresetAddOns []string `json:"preset-addons,omitempty"`
+}
+
+// PatchSet is a named OpenShift configuration patch which gets re-applied on every start
+type PatchSet struct {
+	Target string `json:"target"`
+	Patch  string `json:"patch"`
+}
+
+// User is an OpenShift user declared in the profile which gets reconciled on every start
+type User struct {
+	PasswordHash string   `json:"password-hash,omitempty"`
+	ClusterRoles []string `json:"cluster-roles,omitempty"`
+	SCCs         []string `json:"sccs,omitempty"`
 }
 
 // Create new object with data if file exists or
 // Create json file and return object if doesn't exists
 func NewInstanceConfig(path string) (*InstanceConfigType, error) {
-	cfg := &InstanceConfigType{CacheImages: []string{}, HostFolders: []hostFolderConfig.HostFolderConfig{}, AddonConfig: make(map[string]*addOnConfig.AddOnConfig)}
+	cfg := &InstanceConfigType{CacheImages: []string{}, HostFolders: []hostFolderConfig.HostFolderConfig{}, AddonConfig: make(map[string]*addOnConfig.AddOnConfig), PatchSets: make(map[string]*PatchSet), Users: make(map[string]*User)}
 	cfg.FilePath = path
 
 	// Check json file existence
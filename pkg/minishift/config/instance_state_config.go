@@ -29,18 +29,47 @@ var InstanceStateConfig *InstanceStateConfigType
 
 type InstanceStateConfigType struct {
 	FilePath                  string                    `json:"-"`
+	SchemaVersion             int                       // version of the file layout, see the migration package
 	OcPath                    string                    // minishift state
 	IsRegistered              bool                      // minishift state
 	IsRHELBased               bool                      // minishift state
+	IsoFlavor                 string                    // minishift state
+	InitSystem                string                    // minishift state, init system of the ISO flavor, empty if unknown
+	PackageManager            string                    // minishift state, package manager of the ISO flavor, empty if unknown
+	DockerVersion             string                    // minishift state, Docker engine version selected on start, empty for the version of the ISO
 	SupportsNetworkAssignment bool                      // minishift state
 	SupportsDnsmasqServer     bool                      // minishift state
 	OpenshiftVersion          string                    // minishift state
 	TimeZone                  string                    // minishift state
+	CloudInitUserData         string                    // minishift state, cloud-init user-data supplied when the VM was created
+	ContainerRuntime          string                    // minishift state
+	KubernetesOnly            bool                      // minishift state
+	PreviousKubeContext       string                    // minishift state
+	SftpTunnelPID             int                       // minishift state
+	HostFolderSyncPIDs        map[string]int            // minishift state
+	DockerForwardPID          int                       // minishift state
+	DockerForwardAddress      string                    // minishift state
+	LastKnownIP               string                    // minishift state
+	IPWatchPID                int                       // minishift state
+	AgentPID                  int                       // minishift state
+	DNSEnabled                bool                      // minishift state
+	AuditLogging              bool                      // minishift state, whether the audit log of the API servers is enabled
 	HostFolders               []config.HostFolderConfig // This is temporary and should be removed after 2-3 release.
+	AppliedAddOns             map[string]*AppliedAddOn  // minishift state
+	Lifecycle                 Lifecycle                 // minishift state
+	LifecycleEvents           []LifecycleEvent          // minishift state, the recent lifecycle transitions, the oldest first
+	StartFlags                map[string]string         // minishift state, effective start flags of the last successful start
 
 	VMDriver string // general config
 }
 
+// AppliedAddOn records the state of an add-on at the time it was applied to the instance
+type AppliedAddOn struct {
+	Version    string
+	Checksums  map[string]string // checksum per file, relative to the add-on directory
+	Definition string            // content of the add-on file
+}
+
 // Create new object with data if file exists or
 // Create json file and return object if doesn't exists
 func NewInstanceStateConfig(path string) (*InstanceStateConfigType, error) {
@@ -68,11 +97,7 @@ func (cfg *InstanceStateConfigType) Write() error {
 		return err
 	}
 
-	if err = ioutil.WriteFile(cfg.FilePath, jsonData, 0644); err != nil {
-		return err
-	}
-
-	return nil
+	return writeFileAtomic(cfg.FilePath, jsonData)
 }
 
 func (cfg *InstanceStateConfigType) Delete() error {
@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"time"
+)
+
+// LifecycleState is the state of an instance as recorded by the commands driving it through its lifecycle
+type LifecycleState string
+
+const (
+	// Creating indicates that the VM is being created or started
+	Creating LifecycleState = "Creating"
+	// Provisioning indicates that the VM is being configured prior to starting the cluster
+	Provisioning LifecycleState = "Provisioning"
+	// ClusterStarting indicates that the OpenShift cluster is being started and configured
+	ClusterStarting LifecycleState = "ClusterStarting"
+	// Running indicates that the last start completed
+	Running LifecycleState = "Running"
+	// Stopping indicates that the cluster and the VM are being stopped
+	Stopping LifecycleState = "Stopping"
+	// Stopped indicates that the last stop completed
+	Stopped LifecycleState = "Stopped"
+	// Error indicates that the last operation failed. The phase it failed in is recorded alongside.
+	Error LifecycleState = "Error"
+)
+
+// maxLifecycleEvents is the number of lifecycle events kept in the instance state
+const maxLifecycleEvents = 20
+
+// Lifecycle is the persisted lifecycle state of an instance.
+type Lifecycle struct {
+	State LifecycleState
+	// Phase is the state the instance was in when the last operation failed. Only set for the Error state.
+	Phase LifecycleState `json:",omitempty"`
+	// Message describes the failure. Only set for the Error state.
+	Message string `json:",omitempty"`
+	// InitialStart is true while the instance has not completed its initial start
+	InitialStart bool `json:",omitempty"`
+	Updated      time.Time
+}
+
+// LifecycleEvent records a transition of the lifecycle state of an instance.
+type LifecycleEvent struct {
+	State LifecycleState
+	// Message describes the failure. Only set for the Error state.
+	Message string `json:",omitempty"`
+	Time    time.Time
+}
+
+// IsTransient returns true for the states which are only passed through while a command is operating on the instance.
+// Finding an instance in such a state without a command operating on it means the command crashed or got interrupted.
+func (s LifecycleState) IsTransient() bool {
+	return s == Creating || s == Provisioning || s == ClusterStarting || s == Stopping
+}
+
+// LastPhase returns the state the last operation reached, which for the Error state is the phase it failed in.
+func (l Lifecycle) LastPhase() LifecycleState {
+	if l.State == Error {
+		return l.Phase
+	}
+	return l.State
+}
+
+// IsIncomplete returns true if the last operation crashed, got interrupted or failed.
+func (l Lifecycle) IsIncomplete() bool {
+	return l.State == Error || l.State.IsTransient()
+}
+
+// SetLifecycleState records the specified state and writes the instance state. Reaching the Running state completes
+// the initial start.
+func (cfg *InstanceStateConfigType) SetLifecycleState(state LifecycleState) error {
+	cfg.Lifecycle = Lifecycle{
+		State:        state,
+		InitialStart: cfg.Lifecycle.InitialStart && state != Running,
+		Updated:      time.Now(),
+	}
+	cfg.recordLifecycleEvent()
+	return cfg.Write()
+}
+
+// SetLifecycleError records the failure of the current operation, remembering the phase it failed in, and writes
+// the instance state. An instance which already is in the Error state keeps the phase of the original failure.
+func (cfg *InstanceStateConfigType) SetLifecycleError(message string) error {
+	cfg.Lifecycle = Lifecycle{
+		State:        Error,
+		Phase:        cfg.Lifecycle.LastPhase(),
+		Message:      message,
+		InitialStart: cfg.Lifecycle.InitialStart,
+		Updated:      time.Now(),
+	}
+	cfg.recordLifecycleEvent()
+	return cfg.Write()
+}
+
+// recordLifecycleEvent appends the current lifecycle state to the recent lifecycle events, dropping the oldest
+// events beyond maxLifecycleEvents.
+func (cfg *InstanceStateConfigType) recordLifecycleEvent() {
+	cfg.LifecycleEvents = append(cfg.LifecycleEvents, LifecycleEvent{
+		State:   cfg.Lifecycle.State,
+		Message: cfg.Lifecycle.Message,
+		Time:    cfg.Lifecycle.Updated,
+	})
+	if len(cfg.LifecycleEvents) > maxLifecycleEvents {
+		cfg.LifecycleEvents = cfg.LifecycleEvents[len(cfg.LifecycleEvents)-maxLifecycleEvents:]
+	}
+}
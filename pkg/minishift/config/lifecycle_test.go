@@ -0,0 +1,111 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleStateIsPersisted(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	path := filepath.Join(testDir, "fake-machine.json")
+	cfg, _ := NewInstanceStateConfig(path)
+	cfg.Lifecycle.InitialStart = true
+	assert.NoError(t, cfg.SetLifecycleState(Provisioning))
+
+	readCfg, err := NewInstanceStateConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Provisioning, readCfg.Lifecycle.State)
+	assert.True(t, readCfg.Lifecycle.InitialStart)
+	assert.False(t, readCfg.Lifecycle.Updated.IsZero())
+	assert.True(t, readCfg.Lifecycle.IsIncomplete())
+
+	files, _ := ioutil.ReadDir(testDir)
+	assert.Len(t, files, 1, "No temporary files should be left behind")
+}
+
+func TestLifecycleErrorRecordsPhase(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	cfg, _ := NewInstanceStateConfig(filepath.Join(testDir, "fake-machine.json"))
+	cfg.Lifecycle.InitialStart = true
+	cfg.SetLifecycleState(ClusterStarting)
+	cfg.SetLifecycleError("Error during 'cluster up' execution")
+
+	assert.Equal(t, Error, cfg.Lifecycle.State)
+	assert.Equal(t, ClusterStarting, cfg.Lifecycle.Phase)
+	assert.Equal(t, ClusterStarting, cfg.Lifecycle.LastPhase())
+	assert.Equal(t, "Error during 'cluster up' execution", cfg.Lifecycle.Message)
+	assert.True(t, cfg.Lifecycle.InitialStart)
+	assert.True(t, cfg.Lifecycle.IsIncomplete())
+
+	cfg.SetLifecycleError("Another error")
+	assert.Equal(t, ClusterStarting, cfg.Lifecycle.Phase, "The phase of the original failure should be kept")
+}
+
+func TestRunningCompletesInitialStart(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	cfg, _ := NewInstanceStateConfig(filepath.Join(testDir, "fake-machine.json"))
+	cfg.Lifecycle.InitialStart = true
+	cfg.SetLifecycleState(Stopping)
+	assert.True(t, cfg.Lifecycle.InitialStart)
+
+	cfg.SetLifecycleState(Running)
+	assert.False(t, cfg.Lifecycle.InitialStart)
+	assert.False(t, cfg.Lifecycle.IsIncomplete())
+	assert.Empty(t, cfg.Lifecycle.Phase)
+}
+
+func TestLifecycleEventsAreRecorded(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	path := filepath.Join(testDir, "fake-machine.json")
+	cfg, _ := NewInstanceStateConfig(path)
+	cfg.SetLifecycleState(Creating)
+	cfg.SetLifecycleError("Error starting the VM")
+	for i := 0; i < maxLifecycleEvents-1; i++ {
+		cfg.SetLifecycleState(Stopped)
+	}
+	assert.Len(t, cfg.LifecycleEvents, maxLifecycleEvents)
+	assert.Equal(t, Error, cfg.LifecycleEvents[0].State)
+	assert.Equal(t, "Error starting the VM", cfg.LifecycleEvents[0].Message)
+	assert.Equal(t, Stopped, cfg.LifecycleEvents[maxLifecycleEvents-1].State)
+
+	readCfg, err := NewInstanceStateConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.LifecycleEvents[0].State, readCfg.LifecycleEvents[0].State)
+	assert.Len(t, readCfg.LifecycleEvents, maxLifecycleEvents)
+}
+
+func TestTransientLifecycleStates(t *testing.T) {
+	for _, state := range []LifecycleState{Creating, Provisioning, ClusterStarting, Stopping} {
+		assert.True(t, state.IsTransient(), "State %s should be transient", state)
+	}
+	for _, state := range []LifecycleState{Running, Stopped, Error, ""} {
+		assert.False(t, state.IsTransient(), "State %s should not be transient", state)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minishift/minishift/pkg/util/filehelper"
+)
+
+// schemaVersionKey is the key of the schema version in the instance state file
+const schemaVersionKey = "SchemaVersion"
+
+// ErrNewerSchema is returned if the files of the profile were written by a newer version of Minishift.
+var ErrNewerSchema = errors.New("The profile was written by a newer version of Minishift")
+
+// Files are the configuration and state files of a profile.
+type Files struct {
+	ProfileDir          string
+	ConfigFile          string // the persistent configuration managed by 'minishift config'
+	InstanceConfig      string
+	InstanceState       string
+	LegacyInstanceState string // the location of the instance state file before it got renamed
+}
+
+// Migration upgrades the files of a profile from the previous schema version.
+type Migration struct {
+	Description string
+	Migrate     func(files Files) error
+}
+
+// migrations upgrade the files to the schema version corresponding to their position, starting with version 1.
+// Migrations are only ever appended to this list, so that profiles written by any older version can be upgraded.
+var migrations = []Migration{
+	{Description: "Rename the instance state file", Migrate: renameInstanceState},
+	{Description: "Move the host folders from the instance state to the instance config", Migrate: moveHostFolders},
+	{Description: "Move the cached images and the add-on configuration to the instance config", Migrate: moveCacheImagesAndAddOns},
+}
+
+// CurrentVersion returns the schema version written by this version of Minishift.
+func CurrentVersion() int {
+	return len(migrations)
+}
+
+// Run upgrades the files of the profile to the current schema version and records the version in the instance state
+// file. The files are backed up before the first migration runs. The descriptions of the applied migrations are
+// returned. If the files were written by a newer version of Minishift, ErrNewerSchema is returned.
+func Run(files Files) ([]string, error) {
+	fresh := true
+	for _, file := range files.all() {
+		if hasContent(file) {
+			fresh = false
+		}
+	}
+
+	state, err := readJSON(files.InstanceState)
+	if err != nil {
+		return nil, err
+	}
+	version := 0
+	if value, ok := state[schemaVersionKey].(float64); ok {
+		version = int(value)
+	}
+
+	switch {
+	case version > CurrentVersion():
+		return nil, ErrNewerSchema
+	case version == CurrentVersion():
+		return nil, nil
+	}
+
+	var applied []string
+	if !fresh {
+		backupDir, err := backup(files, version)
+		if err != nil {
+			return nil, fmt.Errorf("Error backing up the profile before migrating it: %v", err)
+		}
+		for _, migration := range migrations[version:] {
+			if err := migration.Migrate(files); err != nil {
+				return applied, fmt.Errorf("Error migrating the profile. %s: %v\nThe files before the migration are kept in '%s'", migration.Description, err, backupDir)
+			}
+			applied = append(applied, migration.Description)
+		}
+	}
+
+	// the migrations may have changed the instance state
+	state, err = readJSON(files.InstanceState)
+	if err != nil {
+		return applied, err
+	}
+	state[schemaVersionKey] = CurrentVersion()
+	return applied, writeJSON(files.InstanceState, state)
+}
+
+func (files Files) all() []string {
+	return []string{files.ConfigFile, files.InstanceConfig, files.InstanceState, files.LegacyInstanceState}
+}
+
+// backup copies the existing files into the backups directory of the profile, keeping their path relative to the
+// profile directory. The backup directory is returned.
+func backup(files Files, version int) (string, error) {
+	backupDir := filepath.Join(files.ProfileDir, "backups", fmt.Sprintf("schema-%d-%s", version, time.Now().Format("20060102150405")))
+	for _, file := range files.all() {
+		if !filehelper.Exists(file) {
+			continue
+		}
+		relPath, err := filepath.Rel(files.ProfileDir, file)
+		if err != nil {
+			return "", err
+		}
+		target := filepath.Join(backupDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return "", err
+		}
+		if err := filehelper.CopyFile(file, target); err != nil {
+			return "", err
+		}
+	}
+	return backupDir, nil
+}
+
+// hasContent returns false if the file does not exist or is empty, for example the '{}' written for a new profile
+func hasContent(path string) bool {
+	content, err := readJSON(path)
+	return err != nil || len(content) > 0
+}
+
+// readJSON returns the content of the JSON file as map. A missing file results in an empty map.
+func readJSON(path string) (map[string]interface{}, error) {
+	content := make(map[string]interface{})
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return content, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return content, nil
+	}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, fmt.Errorf("Invalid JSON in '%s': %v", path, err)
+	}
+	return content, nil
+}
+
+func writeJSON(path string, content map[string]interface{}) error {
+	raw, err := json.MarshalIndent(content, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, raw, 0644)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Legacy_Profile_Is_Migrated(t *testing.T) {
+	files := setupProfile(t)
+	defer os.RemoveAll(files.ProfileDir)
+
+	writeFile(t, files.LegacyInstanceState, `{"OcPath": "/usr/bin/oc", "HostFolders": [{"Name": "src"}]}`)
+	writeFile(t, files.ConfigFile, `{"memory": "4GB", "cache-images": ["alpine"], "addons": {"anyuid": {"Name": "anyuid", "Enabled": true}}}`)
+
+	applied, err := Run(files)
+	assert.NoError(t, err)
+	assert.Len(t, applied, CurrentVersion())
+
+	state, err := readJSON(files.InstanceState)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"OcPath": "/usr/bin/oc", "SchemaVersion": float64(CurrentVersion())}, state)
+	assert.False(t, fileExists(files.LegacyInstanceState))
+
+	config, err := readJSON(files.ConfigFile)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"memory": "4GB"}, config)
+
+	instanceConfig, err := readJSON(files.InstanceConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"alpine"}, instanceConfig["cache-images"])
+	assert.Contains(t, instanceConfig["addons"], "anyuid")
+	assert.Len(t, instanceConfig["HostFolders"], 1)
+
+	backups, err := filepath.Glob(filepath.Join(files.ProfileDir, "backups", "schema-0-*", "machines", "test.json"))
+	assert.NoError(t, err)
+	assert.Len(t, backups, 1, "The files are backed up before the migration")
+
+	applied, err = Run(files)
+	assert.NoError(t, err)
+	assert.Empty(t, applied, "A migrated profile is not migrated again")
+}
+
+func Test_Fresh_Profile_Gets_Current_Version(t *testing.T) {
+	files := setupProfile(t)
+	defer os.RemoveAll(files.ProfileDir)
+
+	applied, err := Run(files)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.False(t, fileExists(filepath.Join(files.ProfileDir, "backups")), "Nothing to back up for a fresh profile")
+
+	state, err := readJSON(files.InstanceState)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(CurrentVersion()), state[schemaVersionKey])
+}
+
+func Test_Profile_With_Empty_Config_File_Is_Fresh(t *testing.T) {
+	files := setupProfile(t)
+	defer os.RemoveAll(files.ProfileDir)
+	writeFile(t, files.ConfigFile, "{}")
+
+	applied, err := Run(files)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.False(t, fileExists(filepath.Join(files.ProfileDir, "backups")), "Nothing to back up for a fresh profile")
+}
+
+func Test_Profile_Of_Newer_Version_Is_Not_Migrated(t *testing.T) {
+	files := setupProfile(t)
+	defer os.RemoveAll(files.ProfileDir)
+
+	writeFile(t, files.InstanceState, `{"SchemaVersion": 1000}`)
+	_, err := Run(files)
+	assert.Equal(t, ErrNewerSchema, err)
+}
+
+func setupProfile(t *testing.T) Files {
+	dir, err := ioutil.TempDir("", "minishift-test-migration-")
+	assert.NoError(t, err, "Error creating temp directory")
+	return Files{
+		ProfileDir:          dir,
+		ConfigFile:          filepath.Join(dir, "config", "config.json"),
+		InstanceConfig:      filepath.Join(dir, "config", "test.json"),
+		InstanceState:       filepath.Join(dir, "machines", "test-state.json"),
+		LegacyInstanceState: filepath.Join(dir, "machines", "test.json"),
+	}
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
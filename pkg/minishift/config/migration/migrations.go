@@ -0,0 +1,86 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"os"
+
+	"github.com/minishift/minishift/pkg/util/filehelper"
+)
+
+// renameInstanceState moves the instance state file from machines/<profile>.json to machines/<profile>-state.json
+func renameInstanceState(files Files) error {
+	if !filehelper.Exists(files.LegacyInstanceState) {
+		return nil
+	}
+	return os.Rename(files.LegacyInstanceState, files.InstanceState)
+}
+
+// moveHostFolders moves the host folders, which used to be kept in the instance state, to the instance config
+func moveHostFolders(files Files) error {
+	state, err := readJSON(files.InstanceState)
+	if err != nil {
+		return err
+	}
+	hostFolders, ok := state["HostFolders"].([]interface{})
+	if !ok || len(hostFolders) == 0 {
+		return nil
+	}
+
+	instanceConfig, err := readJSON(files.InstanceConfig)
+	if err != nil {
+		return err
+	}
+	instanceConfig["HostFolders"] = hostFolders
+	if err := writeJSON(files.InstanceConfig, instanceConfig); err != nil {
+		return err
+	}
+	delete(state, "HostFolders")
+	return writeJSON(files.InstanceState, state)
+}
+
+// moveCacheImagesAndAddOns moves the cached images and the add-on configuration, which used to be kept in the
+// persistent configuration, to the instance config
+func moveCacheImagesAndAddOns(files Files) error {
+	config, err := readJSON(files.ConfigFile)
+	if err != nil {
+		return err
+	}
+	instanceConfig, err := readJSON(files.InstanceConfig)
+	if err != nil {
+		return err
+	}
+
+	moved := false
+	for _, key := range []string{"cache-images", "addons"} {
+		if value, ok := config[key]; ok {
+			if value != nil {
+				instanceConfig[key] = value
+			}
+			delete(config, key)
+			moved = true
+		}
+	}
+	if !moved {
+		return nil
+	}
+
+	if err := writeJSON(files.InstanceConfig, instanceConfig); err != nil {
+		return err
+	}
+	return writeJSON(files.ConfigFile, config)
+}
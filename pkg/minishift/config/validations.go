@@ -17,22 +17,37 @@ limitations under the License.
 package config
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/containers/image/docker/reference"
 	units "github.com/docker/go-units"
 	"github.com/minishift/minishift/pkg/util/filehelper"
 
 	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/cloudinit"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/containerruntime"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/identityprovider"
+	"github.com/minishift/minishift/pkg/minishift/imagepolicy"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	openshiftVersion "github.com/minishift/minishift/pkg/minishift/openshift/version"
+	"github.com/minishift/minishift/pkg/minishift/preset"
+	"github.com/minishift/minishift/pkg/minishift/pv"
+	"github.com/minishift/minishift/pkg/minishift/tls"
+	"github.com/minishift/minishift/pkg/minishift/update"
 	"github.com/minishift/minishift/pkg/util"
 	stringUtils "github.com/minishift/minishift/pkg/util/strings"
 )
@@ -55,7 +70,17 @@ func IsValidDriver(string, driver string) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("Driver '%s' is not supported", driver)
+	return unsupportedValueError("Driver", driver, constants.SupportedVMDrivers[:])
+}
+
+// unsupportedValueError returns the error for a value which is not one of the possible values. If the value looks
+// like a typo of one of the possible values, the error suggests the closest match.
+func unsupportedValueError(kind string, value string, possibleValues []string) error {
+	message := fmt.Sprintf("%s '%s' is not supported.", kind, value)
+	if match := stringUtils.ClosestMatch(value, possibleValues); match != "" {
+		message = fmt.Sprintf("%s Did you mean '%s'?", message, match)
+	}
+	return fmt.Errorf("%s Possible values: %v", message, possibleValues)
 }
 
 func isValidHumanSize(size string) (bool, error) {
@@ -106,6 +131,17 @@ func IsPositive(name string, val string) error {
 	return nil
 }
 
+func IsPositiveDuration(name string, val string) error {
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return fmt.Errorf("%s:%v", name, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("%s must be > 0", name)
+	}
+	return nil
+}
+
 func IsValidCIDR(name string, cidr string) error {
 	_, _, err := net.ParseCIDR(cidr)
 	if err != nil {
@@ -130,11 +166,16 @@ func IsValidProxy(name string, uri string) error {
 }
 
 func IsValidISOUrl(_ string, isoURL string) error {
-	for _, isoAlias := range minishiftConstants.ValidIsoAliases {
+	isoAliases := validIsoAliases()
+	for _, isoAlias := range isoAliases {
 		if isoURL == isoAlias {
 			return nil
 		}
 	}
+	// anything which is neither a URL nor a path is meant to be an alias
+	if isoURL != "" && !strings.Contains(isoURL, "/") && !strings.HasSuffix(isoURL, ".iso") {
+		return unsupportedValueError("ISO alias", isoURL, isoAliases)
+	}
 	if !strings.HasSuffix(isoURL, ".iso") {
 		return fmt.Errorf("'%s' url is not valid", isoURL)
 	}
@@ -169,6 +210,32 @@ func IsValidISOUrl(_ string, isoURL string) error {
 	return nil
 }
 
+// validIsoAliases returns the aliases of the built-in ISO flavors followed by the ones added with 'minishift iso add-alias'
+func validIsoAliases() []string {
+	aliases := append([]string{}, minishiftConstants.ValidIsoAliases...)
+	if AllInstancesConfig == nil {
+		return aliases
+	}
+
+	var custom []string
+	for alias := range AllInstancesConfig.IsoFlavors {
+		custom = append(custom, alias)
+	}
+	sort.Strings(custom)
+	return append(aliases, custom...)
+}
+
+// IsValidDownloadMirrorSlice checks that each download mirror is the HTTP(S) URL of the mirror's base directory
+func IsValidDownloadMirrorSlice(name string, mirrorSlice string) error {
+	for _, mirror := range strings.Split(mirrorSlice, ",") {
+		uri, err := url.ParseRequestURI(strings.TrimSpace(mirror))
+		if err != nil || (uri.Scheme != "http" && uri.Scheme != "https") || uri.Host == "" {
+			return fmt.Errorf("%s mirror is not a valid HTTP(S) URL: '%s'", name, mirror)
+		}
+	}
+	return nil
+}
+
 func IsValidIPv4AddressSlice(name string, addressSlice string) error {
 	addresses := strings.Split(addressSlice, ",")
 
@@ -233,6 +300,185 @@ func IsSystemTrayAvailable(_ string, _ string) error {
 	return nil
 }
 
+func IsValidContainerRuntime(_ string, name string) error {
+	if !containerruntime.IsSupported(name) {
+		return unsupportedValueError("Container runtime", name, containerruntime.SupportedRuntimes)
+	}
+	return nil
+}
+
+func IsValidUpdateChannel(_ string, channel string) error {
+	if !stringUtils.Contains(update.ValidChannels, channel) {
+		return unsupportedValueError("Update channel", channel, update.ValidChannels)
+	}
+	return nil
+}
+
+func IsValidImagePolicy(_ string, path string) error {
+	return imagepolicy.ValidatePolicy(path)
+}
+
+func IsValidPreset(_ string, name string) error {
+	_, err := preset.Load(minishiftConstants.GetPresetsDir(), name)
+	return err
+}
+
+func IsValidImageSignatureConfig(_ string, path string) error {
+	return imagepolicy.ValidateRegistriesDir(path)
+}
+
+func IsValidCloudInit(_ string, path string) error {
+	_, err := cloudinit.Parse(path)
+	return err
+}
+
+// IsValidDockerOpt validates a single Docker daemon option. The value is not split at commas, so that options like
+// 'log-opt=labels=a,b' can be set.
+func IsValidDockerOpt(_ string, option string) error {
+	return docker.ValidateOption(strings.TrimSpace(option))
+}
+
+func IsValidDockerStorageOptSlice(_ string, options string) error {
+	for _, option := range strings.Split(options, ",") {
+		if err := docker.ValidateStorageOption(strings.TrimSpace(option)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func IsValidDockerFlagValueSlice(_ string, values string) error {
+	for _, value := range strings.Split(values, ",") {
+		if err := docker.ValidateFlagValue(strings.TrimSpace(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func IsValidDockerVersion(_ string, version string) error {
+	return docker.ValidateEngineVersion(version)
+}
+
+func IsValidDockerEnvFile(_ string, path string) error {
+	_, err := docker.ReadEnvFile(path)
+	return err
+}
+
+func IsValidRegistryCASlice(_ string, entries string) error {
+	for _, entry := range strings.Split(entries, ",") {
+		if _, err := tls.ParseRegistryCA(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsValidSANSlice checks that each entry is either an IP address or a DNS name
+func IsValidSANSlice(_ string, entries string) error {
+	for _, entry := range strings.Split(entries, ",") {
+		if err := tls.ValidateSAN(strings.TrimSpace(entry)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsValidClusterUpFlags checks the syntax of the flags. If the oc binary of the last start is available, the flags are
+// also checked against the flags its 'cluster up' supports.
+func IsValidClusterUpFlags(_ string, flags string) error {
+	flagList := oc.GroupClusterUpFlags(strings.Split(flags, ","))
+	for _, flag := range flagList {
+		if _, err := oc.ClusterUpFlagName(flag); err != nil {
+			return err
+		}
+	}
+
+	if InstanceStateConfig == nil || !filehelper.Exists(InstanceStateConfig.OcPath) {
+		return nil
+	}
+	return oc.ValidateClusterUpFlags(flagList, InstanceStateConfig.OcPath, util.RealRunner{})
+}
+
+var ocVersionRegexp = regexp.MustCompile(`^v\d+\.\d+\.\d+(-[0-9A-Za-z.]+)?$`)
+
+func IsValidOcVersion(_ string, version string) error {
+	if !ocVersionRegexp.MatchString(version) {
+		return fmt.Errorf("'%s' is not a valid oc version. The version needs to be of the form 'v3.11.0'", version)
+	}
+	return nil
+}
+
+// IsValidOpenShiftVersion checks that the version is 'latest' or a version which can be provisioned by Minishift.
+// If the upstream releases have been fetched recently, the version also needs to be one of them.
+func IsValidOpenShiftVersion(_ string, version string) error {
+	if version == "latest" || version == constants.VersionPrefix+"latest" {
+		return nil
+	}
+	if !strings.HasPrefix(version, constants.VersionPrefix) {
+		version = constants.VersionPrefix + version
+	}
+
+	releases := openshiftVersion.KnownReleases(filepath.Join(constants.GetMinishiftHomeDir(), "cache"))
+	if !ocVersionRegexp.MatchString(version) {
+		message := fmt.Sprintf("'%s' is not a valid OpenShift version. The version needs to be of the form 'v3.11.0'", version)
+		if match := stringUtils.ClosestMatch(version, releases); match != "" {
+			message = fmt.Sprintf("%s. Did you mean '%s'?", message, match)
+		}
+		return errors.New(message)
+	}
+
+	if !openshiftVersion.IsCompatibleVersion(version, constants.MinimumSupportedOpenShiftVersion) {
+		return fmt.Errorf("OpenShift version '%s' is not supported. Use 'minishift openshift version list' to list the supported versions", version)
+	}
+
+	if releases != nil && !stringUtils.Contains(releases, version) {
+		message := fmt.Sprintf("OpenShift version '%s' is not released.", version)
+		if match := stringUtils.ClosestMatch(version, releases); match != "" {
+			message = fmt.Sprintf("%s Did you mean '%s'?", message, match)
+		}
+		return fmt.Errorf("%s Use 'minishift openshift version list' to list the supported versions", message)
+	}
+	return nil
+}
+
+func IsValidIdentityProvider(_ string, kind string) error {
+	if !identityprovider.IsSupported(kind) {
+		return unsupportedValueError("Identity provider", kind, identityprovider.SupportedProviders)
+	}
+	return nil
+}
+
+func IsValidLDAPURL(_ string, ldapURL string) error {
+	return identityprovider.ValidateLDAPURL(ldapURL)
+}
+
+func IsValidPVCount(name string, count string) error {
+	i, err := strconv.Atoi(count)
+	if err != nil {
+		return fmt.Errorf("%s:%v", name, err)
+	}
+	return pv.ValidateCount(i)
+}
+
+func IsValidPVSize(_ string, size string) error {
+	return pv.ValidateSize(size)
+}
+
+func IsValidPVReclaimPolicy(_ string, policy string) error {
+	if err := pv.ValidateReclaimPolicy(policy); err != nil {
+		return unsupportedValueError("Reclaim policy", policy, pv.SupportedReclaimPolicies)
+	}
+	return nil
+}
+
+func IsValidImageReference(_ string, image string) error {
+	if _, err := reference.ParseNormalizedNamed(image); err != nil {
+		return fmt.Errorf("'%s' is not a valid image reference: %v", image, err)
+	}
+	return nil
+}
+
 func IsValidTimezone(_ string, timezone string) error {
 	_, err := time.LoadLocation(timezone)
 	if err != nil {
@@ -18,8 +18,13 @@ package config
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/minishift/minishift/pkg/minikube/constants"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -56,6 +61,16 @@ func TestDriver(t *testing.T) {
 	runValidations(t, tests, "vm-driver", IsValidDriver)
 }
 
+func TestDriverSuggestion(t *testing.T) {
+	err := IsValidDriver("vm-driver", "virtualbx")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Did you mean 'virtualbox'?")
+
+	err = IsValidDriver("vm-driver", "vkasdhfasjdf")
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "Did you mean")
+}
+
 func TestValidCIDR(t *testing.T) {
 	var tests = []validationTest{
 		{
@@ -124,7 +139,7 @@ func TestValidURL(t *testing.T) {
 		},
 		{
 			value:     "b2d",
-			shouldErr: true,
+			shouldErr: false,
 		},
 		{
 			value:     "random",
@@ -132,6 +147,21 @@ func TestValidURL(t *testing.T) {
 		},
 	}
 	runValidations(t, tests, "iso-url", IsValidISOUrl)
+
+	err := IsValidISOUrl("iso-url", "cnetos")
+	assert.EqualError(t, err, "ISO alias 'cnetos' is not supported. Did you mean 'centos'? Possible values: [b2d centos]")
+}
+
+func TestValidISOUrlAcceptsCustomAliases(t *testing.T) {
+	AllInstancesConfig = &GlobalConfigType{IsoFlavors: map[string]IsoFlavor{
+		"fedora": {URL: "https://example.com/fedora.iso"},
+	}}
+	defer func() { AllInstancesConfig = nil }()
+
+	assert.NoError(t, IsValidISOUrl("iso-url", "fedora"))
+
+	err := IsValidISOUrl("iso-url", "fedroa")
+	assert.EqualError(t, err, "ISO alias 'fedroa' is not supported. Did you mean 'fedora'? Possible values: [b2d centos fedora]")
 }
 
 func TestValidProxyURL(t *testing.T) {
@@ -312,3 +342,179 @@ func TestValidTimezone(t *testing.T) {
 	}
 	runValidations(t, tests, "timezone", IsValidTimezone)
 }
+
+func TestValidImageReference(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "docker.io/openshift/origin-haproxy-router:v3.11.0",
+			shouldErr: false,
+		},
+		{
+			value:     "myregistry:5000/patched/router",
+			shouldErr: false,
+		},
+		{
+			value:     "Invalid/Image",
+			shouldErr: true,
+		},
+		{
+			value:     "openshift/router:",
+			shouldErr: true,
+		},
+	}
+	runValidations(t, tests, "openshift.router-image", IsValidImageReference)
+}
+
+func TestValidClusterUpFlags(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "--enable=*,service-catalog",
+			shouldErr: false,
+		},
+		{
+			value:     "--forward-ports",
+			shouldErr: false,
+		},
+		{
+			value:     "enable=*",
+			shouldErr: true,
+		},
+		{
+			value:     "-v,--loglevel=5",
+			shouldErr: true,
+		},
+	}
+	runValidations(t, tests, "cluster-up-flags", IsValidClusterUpFlags)
+}
+
+func TestValidSANSlice(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "my-laptop.corp,192.168.1.50",
+			shouldErr: false,
+		},
+		{
+			value:     "my-laptop.corp, 192.168.1.50",
+			shouldErr: false,
+		},
+		{
+			value:     "my laptop",
+			shouldErr: true,
+		},
+	}
+	runValidations(t, tests, "api-extra-sans", IsValidSANSlice)
+}
+
+func TestValidDownloadMirrorSlice(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "https://mirror.example.com/github,http://10.0.0.1:8080",
+			shouldErr: false,
+		},
+		{
+			value:     "ftp://mirror.example.com/github",
+			shouldErr: true,
+		},
+		{
+			value:     "mirror.example.com",
+			shouldErr: true,
+		},
+	}
+	runValidations(t, tests, "download-mirrors", IsValidDownloadMirrorSlice)
+}
+
+func TestValidOcVersion(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "v3.11.0",
+			shouldErr: false,
+		},
+		{
+			value:     "v3.11.0-rc.0",
+			shouldErr: false,
+		},
+		{
+			value:     "3.11.0",
+			shouldErr: true,
+		},
+		{
+			value:     "v3.11",
+			shouldErr: true,
+		},
+	}
+	runValidations(t, tests, "oc-version", IsValidOcVersion)
+}
+
+func TestValidOpenShiftVersion(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-openshift-version-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+	os.Setenv(constants.MiniShiftHomeEnv, testDir)
+	defer os.Unsetenv(constants.MiniShiftHomeEnv)
+
+	var tests = []validationTest{
+		{value: "v3.11.0", shouldErr: false},
+		{value: "3.11.0", shouldErr: false},
+		{value: "latest", shouldErr: false},
+		{value: "v3.11", shouldErr: true},
+		{value: "v3.9.0", shouldErr: true},
+		{value: "v4.1.0", shouldErr: true},
+	}
+	runValidations(t, tests, "openshift-version", IsValidOpenShiftVersion)
+
+	releases := fmt.Sprintf(`{"fetched": "%s", "tags": ["v3.10.0", "v3.11.0"]}`, time.Now().Format(time.RFC3339))
+	assert.NoError(t, os.MkdirAll(filepath.Join(testDir, "cache"), 0777))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(testDir, "cache", "openshift-releases.json"), []byte(releases), 0644))
+
+	assert.NoError(t, IsValidOpenShiftVersion("openshift-version", "v3.11.0"))
+	err = IsValidOpenShiftVersion("openshift-version", "v3.11.1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Did you mean 'v3.11.0'?")
+	err = IsValidOpenShiftVersion("openshift-version", "v3.11")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Did you mean 'v3.11.0'?")
+}
+
+func TestValidIdentityProvider(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "htpasswd",
+			shouldErr: false,
+		},
+		{
+			value:     "ldap",
+			shouldErr: false,
+		},
+		{
+			value:     "github",
+			shouldErr: true,
+		},
+	}
+	runValidations(t, tests, "identity-provider", IsValidIdentityProvider)
+}
+
+func TestPositiveDuration(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "30m",
+			shouldErr: false,
+		},
+		{
+			value:     "1h30m",
+			shouldErr: false,
+		},
+		{
+			value:     "0s",
+			shouldErr: true,
+		},
+		{
+			value:     "-5m",
+			shouldErr: true,
+		},
+		{
+			value:     "30",
+			shouldErr: true,
+		},
+	}
+	runValidations(t, tests, "daemon-idle-timeout", IsPositiveDuration)
+}
@@ -25,6 +25,7 @@ import (
 
 const (
 	CentOsIsoAlias                 = "centos"
+	B2dIsoAlias                    = "b2d"
 	OpenshiftContainerName         = "origin"
 	OpenshiftApiContainerLabel     = "io.kubernetes.container.name=apiserver"
 	KubernetesApiContainerLabel    = "io.kubernetes.container.name=api"
@@ -40,17 +41,39 @@ const (
 	HypervDefaultVirtualSwitchName = "Default Switch"
 	DockerbridgeSubnetCmd          = `docker network inspect -f "{{range .IPAM.Config }}{{ .Subnet }}{{end}}" bridge`
 	MinishiftEnableExperimental    = "MINISHIFT_ENABLE_EXPERIMENTAL"
+	MinishiftLogFormat             = "MINISHIFT_LOG_FORMAT"
 	SystemtrayDaemon               = "systemtray"
 	SftpdDaemon                    = "sftpd"
 	ProxyDaemon                    = "proxy"
+	RegistryCacheDaemon            = "registry-cache"
 )
 
 var (
-	ValidIsoAliases = []string{CentOsIsoAlias}
+	ValidIsoAliases = []string{B2dIsoAlias, CentOsIsoAlias}
 	ValidComponents = []string{"automation-service-broker", "service-catalog", "template-service-broker"}
-	ValidServices   = []string{SystemtrayDaemon, SftpdDaemon, ProxyDaemon}
+	ValidServices   = []string{SystemtrayDaemon, SftpdDaemon, ProxyDaemon, RegistryCacheDaemon}
 )
 
+// GetBackupsDir returns the directory holding the cluster backups of all profiles
+func GetBackupsDir() string {
+	return filepath.Join(constants.GetMinishiftHomeDir(), "backups")
+}
+
+// GetUpdateCheckStatePath returns the path of the file recording the results of the background update checks
+func GetUpdateCheckStatePath() string {
+	return filepath.Join(constants.GetMinishiftHomeDir(), "update-check.json")
+}
+
+// GetPresetsDir returns the directory holding the user-defined presets, which all profiles share
+func GetPresetsDir() string {
+	return filepath.Join(constants.GetMinishiftHomeDir(), "presets")
+}
+
+// GetProvisionerPluginsDir returns the directory holding the provisioner plugins, which all profiles share
+func GetProvisionerPluginsDir() string {
+	return filepath.Join(constants.GetMinishiftHomeDir(), "provisioners")
+}
+
 // ProfileAuthorizedKeysPath returns the path of authorized_keys file in profile dir used for authentication purpose
 func ProfileAuthorizedKeysPath() string {
 	return filepath.Join(constants.Minipath, "certs", "authorized_keys")
@@ -75,6 +98,11 @@ func GetInstanceStateConfigOldPath() string {
 	return filepath.Join(constants.Minipath, "machines", constants.MachineName+".json")
 }
 
+// GetAgentStatusPath return the path of the status file the host agent of the instance writes
+func GetAgentStatusPath() string {
+	return filepath.Join(constants.Minipath, "machines", constants.MachineName+"-agent.json")
+}
+
 // GetInstanceConfigPath return the path of instance config json file
 func GetInstanceConfigPath() string {
 	return filepath.Join(constants.Minipath, "config", constants.MachineName+".json")
@@ -0,0 +1,116 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerruntime
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/machine/libmachine/provision"
+)
+
+const (
+	Docker     = "docker"
+	CRIO       = "crio"
+	Containerd = "containerd"
+
+	// DefaultRuntime is the container runtime used by the kubelet unless specified otherwise
+	DefaultRuntime = Docker
+)
+
+var (
+	// SupportedRuntimes lists the container runtimes the kubelet can be configured to use
+	SupportedRuntimes = []string{Docker, CRIO, Containerd}
+
+	runtimes = map[string]runtime{
+		Docker:     {service: "docker", socket: "/var/run/docker.sock"},
+		CRIO:       {service: "crio", socket: "/var/run/crio/crio.sock"},
+		Containerd: {service: "containerd", socket: "/run/containerd/containerd.sock"},
+	}
+)
+
+type runtime struct {
+	service string
+	socket  string
+}
+
+// IsSupported returns true if the specified container runtime is supported, false otherwise.
+func IsSupported(name string) bool {
+	_, ok := runtimes[name]
+	return ok
+}
+
+// SocketPath returns the path of the CRI socket of the specified container runtime inside the VM.
+func SocketPath(name string) string {
+	return runtimes[name].socket
+}
+
+// IsRemote returns true if the kubelet talks to the specified container runtime via CRI
+// rather than via its built-in Docker support.
+func IsRemote(name string) bool {
+	return name != Docker
+}
+
+// EnsureRuntimeEnabled makes sure the service of the specified container runtime is enabled and running in the VM.
+// Docker is always running, since it hosts the OpenShift containers themselves.
+func EnsureRuntimeEnabled(sshCommander provision.SSHCommander, name string) error {
+	if !IsSupported(name) {
+		return fmt.Errorf("Container runtime '%s' is not supported. Possible values: %v", name, SupportedRuntimes)
+	}
+
+	if !IsRemote(name) {
+		return nil
+	}
+
+	service := runtimes[name].service
+	if _, err := sshCommander.SSHCommand(fmt.Sprintf("systemctl cat %s.service", service)); err != nil {
+		return fmt.Errorf("The ISO does not provide the container runtime '%s'", name)
+	}
+
+	if _, err := sshCommander.SSHCommand(fmt.Sprintf("sudo systemctl enable --now %s.service", service)); err != nil {
+		return fmt.Errorf("Error enabling the container runtime '%s': %v", name, err)
+	}
+
+	return nil
+}
+
+// KubeletArgumentsPatch returns the patch for the node configuration which makes the kubelet use
+// the specified container runtime.
+func KubeletArgumentsPatch(name string) (string, error) {
+	if !IsSupported(name) {
+		return "", fmt.Errorf("Container runtime '%s' is not supported. Possible values: %v", name, SupportedRuntimes)
+	}
+
+	kubeletArguments := map[string][]string{
+		"container-runtime": {Docker},
+	}
+	if IsRemote(name) {
+		endpoint := "unix://" + SocketPath(name)
+		kubeletArguments = map[string][]string{
+			"container-runtime":          {"remote"},
+			"container-runtime-endpoint": {endpoint},
+			"image-service-endpoint":     {endpoint},
+			"runtime-request-timeout":    {"10m"},
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"kubeletArguments": kubeletArguments})
+	if err != nil {
+		return "", err
+	}
+	return string(patch), nil
+}
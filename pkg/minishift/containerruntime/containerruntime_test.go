@@ -0,0 +1,36 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerruntime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Kubelet_Arguments_Patch(t *testing.T) {
+	patch, err := KubeletArgumentsPatch(CRIO)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"kubeletArguments":{"container-runtime":["remote"],"container-runtime-endpoint":["unix:///var/run/crio/crio.sock"],"image-service-endpoint":["unix:///var/run/crio/crio.sock"],"runtime-request-timeout":["10m"]}}`, patch)
+
+	patch, err = KubeletArgumentsPatch(Docker)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"kubeletArguments":{"container-runtime":["docker"]}}`, patch)
+
+	_, err = KubeletArgumentsPatch("rkt")
+	assert.EqualError(t, err, "Container runtime 'rkt' is not supported. Possible values: [docker crio containerd]")
+}
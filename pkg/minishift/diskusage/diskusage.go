@@ -0,0 +1,135 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskusage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/provision"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/pv"
+)
+
+// VMDirectories are the directories within the VM which usually take up most of its disk
+var VMDirectories = []string{
+	"/var/lib/docker",
+	"/var/lib/origin",
+	minishiftConstants.BaseDirInsideInstance,
+	pv.DirInsideInstance,
+}
+
+// Entry is the disk space used by the file or directory at Path
+type Entry struct {
+	Name  string
+	Path  string
+	Bytes int64
+}
+
+// DirSize returns the disk space allocated by the files below path. Symbolic links are not followed.
+// A path which does not exist uses no space.
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += allocatedSize(info)
+		}
+		return nil
+	})
+	return size, err
+}
+
+// Subdirectories returns the disk space used by each of the subdirectories of dir, largest first.
+func Subdirectories(dir string) ([]Entry, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		size, err := DirSize(path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Name: file.Name(), Path: path, Bytes: size})
+	}
+	sortBySize(entries)
+	return entries, nil
+}
+
+// VMUsage returns the disk space used by each of the specified directories within the VM. Directories which do
+// not exist in the VM are omitted.
+func VMUsage(commander provision.SSHCommander, dirs []string) ([]Entry, error) {
+	// -H follows the directories which are symbolic links to the data disk
+	out, err := commander.SSHCommand(fmt.Sprintf("sudo du -skH %s 2>/dev/null; true", strings.Join(dirs, " ")))
+	if err != nil {
+		return nil, err
+	}
+	return parseDu(out), nil
+}
+
+// parseDu parses the output of 'du -sk', which lists the size in KiB and the path of each directory
+func parseDu(out string) []Entry {
+	var entries []Entry
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		kib, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		path := strings.Join(fields[1:], " ")
+		entries = append(entries, Entry{Name: path, Path: path, Bytes: kib * 1024})
+	}
+	return entries
+}
+
+// Total returns the disk space used by all entries
+func Total(entries []Entry) int64 {
+	var total int64
+	for _, entry := range entries {
+		total += entry.Bytes
+	}
+	return total
+}
+
+func sortBySize(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Bytes > entries[j].Bytes
+	})
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskusage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubdirectoriesAreSortedBySize(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-diskusage-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	files := map[string]int{
+		filepath.Join("iso", "centos", "minishift-centos7.iso"): 10000,
+		filepath.Join("images", "blobs", "sha256", "abc"):       100000,
+		filepath.Join("oc", "v3.11.0", "linux", "oc"):           1000,
+	}
+	for name, size := range files {
+		path := filepath.Join(testDir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(strings.Repeat("x", size)), 0644))
+	}
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(testDir, "ignored"), []byte("x"), 0644))
+
+	entries, err := Subdirectories(testDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, []string{"images", "iso", "oc"}, []string{entries[0].Name, entries[1].Name, entries[2].Name})
+	assert.True(t, entries[0].Bytes >= 100000, "Expected at least the size of the content")
+	assert.Equal(t, filepath.Join(testDir, "images"), entries[0].Path)
+}
+
+func TestMissingDirectoryUsesNoSpace(t *testing.T) {
+	size, err := DirSize(filepath.Join(os.TempDir(), "minishift-test-does-not-exist"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), size)
+
+	entries, err := Subdirectories(filepath.Join(os.TempDir(), "minishift-test-does-not-exist"))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestParseDu(t *testing.T) {
+	out := "2048\t/var/lib/docker\n4\t/var/lib/minishift/openshift.local.pv\ndu: cannot access\n\n"
+	entries := parseDu(out)
+	assert.Equal(t, []Entry{
+		{Name: "/var/lib/docker", Path: "/var/lib/docker", Bytes: 2048 * 1024},
+		{Name: "/var/lib/minishift/openshift.local.pv", Path: "/var/lib/minishift/openshift.local.pv", Bytes: 4 * 1024},
+	}, entries)
+	assert.Equal(t, int64(2052*1024), Total(entries))
+}
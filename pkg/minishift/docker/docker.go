@@ -19,13 +19,15 @@ package docker
 import (
 	"fmt"
 	"github.com/docker/machine/libmachine/provision"
-	"github.com/golang/glog"
 	"github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"github.com/pkg/errors"
 	"strings"
 	"time"
 )
 
+var logger = logging.For("docker")
+
 type DockerCommander interface {
 	// Ps returns the running containers of the Docker daemon and any error which occurred.
 	Ps() (string, error)
@@ -202,7 +204,7 @@ func (c VmDockerCommander) Status(container string) (string, error) {
 }
 
 func (c VmDockerCommander) logCommand(cmd string) {
-	glog.V(2).Info(fmt.Sprintf("Executing docker command: '%s'", cmd))
+	logger.Debugf("Executing docker command: '%s'", cmd)
 }
 
 func (c VmDockerCommander) Run(options string, container string) (bool, error) {
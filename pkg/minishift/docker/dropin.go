@@ -0,0 +1,116 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/pkg/minikube/assets"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+)
+
+const (
+	// DropInDir is the directory of the systemd drop-ins of the Docker service inside the VM
+	DropInDir = "/etc/systemd/system/docker.service.d"
+	// DropInName is the name of the drop-in rendered from the profile configuration. It sorts after the drop-in
+	// written by the provisioner, which passes the options variable to the Docker daemon.
+	DropInName = "20-minishift.conf"
+	// OptionsVariable is the environment variable holding the Docker daemon flags rendered into the drop-in
+	OptionsVariable = "MINISHIFT_DOCKER_OPTS"
+
+	dropInTemplate = `# Rendered by Minishift from the profile configuration on every start, do not edit
+[Service]
+{{ range .Env }}Environment={{ printf "%q" . }}
+{{ end }}Environment={{ printf "%q" (printf "` + OptionsVariable + `=%s" (join .Flags " ")) }}
+`
+)
+
+// DaemonConfig is the configuration of the Docker daemon which is rendered into its systemd drop-in
+type DaemonConfig struct {
+	Env                []string // Each entry is formatted as KEY=VALUE, including the proxy settings.
+	InsecureRegistries []string
+	RegistryMirrors    []string
+	StorageOptions     []string // Each entry is formatted as KEY=VALUE.
+	Options            []string // Arbitrary flags without the leading dashes, formatted as <flag>=<value>.
+}
+
+// Flags returns the Docker daemon flags of the configuration
+func (c *DaemonConfig) Flags() []string {
+	var flags []string
+	for _, registry := range c.InsecureRegistries {
+		flags = append(flags, "--insecure-registry "+registry)
+	}
+	for _, mirror := range c.RegistryMirrors {
+		flags = append(flags, "--registry-mirror "+mirror)
+	}
+	for _, option := range c.StorageOptions {
+		flags = append(flags, "--storage-opt "+option)
+	}
+	for _, option := range c.Options {
+		flags = append(flags, "--"+option)
+	}
+	return flags
+}
+
+// DropIn returns the rendered systemd drop-in of the Docker service
+func (c *DaemonConfig) DropIn() (*assets.TemplateAsset, error) {
+	return assets.NewTemplateAsset(dropInTemplate, c, DropInDir, DropInName, "0644")
+}
+
+// WriteDropIn renders the drop-in of the Docker service and copies it into the VM unless it is up to date. It
+// returns true if the drop-in changed, in which case systemd is reloaded, but the Docker daemon is not restarted.
+func WriteDropIn(driver drivers.Driver, config *DaemonConfig) (bool, error) {
+	dropIn, err := config.DropIn()
+	if err != nil {
+		return false, err
+	}
+
+	client, err := sshutil.NewSSHClient(driver)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+
+	var current bytes.Buffer
+	if err := sshutil.Download(client, DropInDir+"/"+DropInName, &current); err == nil && bytes.Equal(current.Bytes(), dropIn.Content()) {
+		return false, nil
+	}
+
+	if err := sshutil.TransferFile(dropIn, client); err != nil {
+		return false, fmt.Errorf("Error copying the Docker drop-in '%s': %v", DropInName, err)
+	}
+	if err := sshutil.RunCommand(client, "sudo systemctl daemon-reload"); err != nil {
+		return false, fmt.Errorf("Error reloading systemd: %v", err)
+	}
+	return true, nil
+}
+
+// ApplyDropIn writes the drop-in of the Docker service and restarts the Docker daemon if the drop-in changed. It
+// returns true if the Docker daemon was restarted.
+func ApplyDropIn(driver drivers.Driver, config *DaemonConfig) (bool, error) {
+	changed, err := WriteDropIn(driver, config)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	if _, err := drivers.RunSSHCommandFromDriver(driver, "sudo systemctl restart docker"); err != nil {
+		return false, fmt.Errorf("Error restarting the Docker daemon: %v", err)
+	}
+	return true, nil
+}
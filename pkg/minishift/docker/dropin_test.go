@@ -0,0 +1,52 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropIn(t *testing.T) {
+	config := &DaemonConfig{
+		Env:                []string{"HTTP_PROXY=http://proxy.foo.com:3128", "NO_PROXY=localhost,127.0.0.1"},
+		InsecureRegistries: []string{"172.30.0.0/16"},
+		RegistryMirrors:    []string{"https://mirror.foo.com"},
+		StorageOptions:     []string{"dm.basesize=20G"},
+		Options:            []string{"log-level=debug"},
+	}
+
+	dropIn, err := config.DropIn()
+	assert.NoError(t, err)
+	assert.Equal(t, DropInDir, dropIn.GetTargetDir())
+	assert.Equal(t, DropInName, dropIn.GetTargetName())
+
+	expected := `# Rendered by Minishift from the profile configuration on every start, do not edit
+[Service]
+Environment="HTTP_PROXY=http://proxy.foo.com:3128"
+Environment="NO_PROXY=localhost,127.0.0.1"
+Environment="MINISHIFT_DOCKER_OPTS=--insecure-registry 172.30.0.0/16 --registry-mirror https://mirror.foo.com --storage-opt dm.basesize=20G --log-level=debug"
+`
+	assert.Equal(t, expected, string(dropIn.Content()))
+}
+
+func TestDropInWithoutConfiguration(t *testing.T) {
+	dropIn, err := (&DaemonConfig{}).DropIn()
+	assert.NoError(t, err)
+	assert.Contains(t, string(dropIn.Content()), "[Service]\nEnvironment=\"MINISHIFT_DOCKER_OPTS=\"\n")
+}
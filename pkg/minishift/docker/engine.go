@@ -0,0 +1,190 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+)
+
+// EngineCacheDir is the directory on the persistent disk of the VM caching the packages of the selected Docker
+// engine versions, so that switching the version on every start neither needs network access nor a repository
+const EngineCacheDir = "/var/lib/minishift/docker-engine"
+
+var (
+	engineVersionRegexp = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+	clientVersionRegexp = regexp.MustCompile(`Docker version (\d+\.\d+\.\d+)`)
+
+	// enginePackages are the packages providing the Docker engine, which are removed before switching the version
+	enginePackages = []string{"docker", "docker-client", "docker-common", "docker-ce", "docker-ce-cli"}
+
+	// engineRepositories are the repositories of Docker CE by package manager
+	engineRepositories = map[string]string{
+		"yum": "https://download.docker.com/linux/centos/docker-ce.repo",
+		"dnf": "https://download.docker.com/linux/fedora/docker-ce.repo",
+	}
+)
+
+// Engine selects the version of the Docker engine installed in the VM
+type Engine struct {
+	Version        string   // empty to keep the version shipped with the ISO image
+	PackageManager string   // package manager of the ISO image, used to download the packages of the version
+	ProxyEnv       []string // proxy settings in the form KEY=VALUE used to download the packages
+}
+
+// ValidateEngineVersion returns an error if the version is not in the form <major>.<minor>.<patch>
+func ValidateEngineVersion(version string) error {
+	if !engineVersionRegexp.MatchString(version) {
+		return fmt.Errorf("Docker version '%s' needs to be in the form <major>.<minor>.<patch>, for example 18.09.9", version)
+	}
+	return nil
+}
+
+// IsCommunityEdition returns true if the version is a Docker CE version, which is packaged by Docker instead of the
+// distribution and ships the plain dockerd binary
+func IsCommunityEdition(version string) bool {
+	major, _ := strconv.Atoi(strings.Split(version, ".")[0])
+	return major > 1
+}
+
+// InstalledVersion returns the version of the Docker engine installed in the VM
+func InstalledVersion(commander provision.SSHCommander) (string, error) {
+	out, err := commander.SSHCommand("docker --version")
+	if err != nil {
+		return "", fmt.Errorf("Error determining the Docker version: %v", err)
+	}
+	match := clientVersionRegexp.FindStringSubmatch(out)
+	if match == nil {
+		return "", fmt.Errorf("Unexpected output of 'docker --version': %s", strings.TrimSpace(out))
+	}
+	return match[1], nil
+}
+
+// Install installs the selected version of the Docker engine, replacing the installed one. The packages are
+// downloaded once into EngineCacheDir and installed from there on later starts. It returns true if the version was
+// switched, in which case the Docker daemon is stopped and needs to be started again.
+func (e Engine) Install(commander provision.SSHCommander) (bool, error) {
+	if e.Version == "" {
+		return false, nil
+	}
+	if err := ValidateEngineVersion(e.Version); err != nil {
+		return false, err
+	}
+	if _, found := engineRepositories[e.PackageManager]; !found {
+		return false, fmt.Errorf("Selecting the Docker version is not supported without a package manager")
+	}
+
+	installed, err := InstalledVersion(commander)
+	if err != nil {
+		return false, err
+	}
+	if installed == e.Version {
+		return false, nil
+	}
+
+	if _, err := commander.SSHCommand(fmt.Sprintf("ls %s/*.rpm", e.cacheDir())); err != nil {
+		logger.Debugf("Downloading the packages of Docker %s into %s", e.Version, e.cacheDir())
+		if err := e.run(commander, e.downloadCommands()); err != nil {
+			return false, fmt.Errorf("Error downloading Docker %s: %v", e.Version, err)
+		}
+	}
+
+	logger.Debugf("Switching the Docker engine from version %s to %s", installed, e.Version)
+	if err := e.run(commander, e.installCommands()); err != nil {
+		return false, fmt.Errorf("Error installing Docker %s: %v", e.Version, err)
+	}
+	return true, nil
+}
+
+func (e Engine) run(commander provision.SSHCommander, commands []string) error {
+	for _, cmd := range commands {
+		if out, err := commander.SSHCommand(cmd); err != nil {
+			logger.Debugf("'%s' output:\n%s", cmd, out)
+			return err
+		}
+	}
+	return nil
+}
+
+func (e Engine) cacheDir() string {
+	return path.Join(EngineCacheDir, e.Version)
+}
+
+// packages returns the packages of the selected version. Docker 1.x is packaged by the distribution, later versions
+// are provided by the Docker CE repository.
+func (e Engine) packages() []string {
+	if !IsCommunityEdition(e.Version) {
+		return []string{"docker-" + e.Version}
+	}
+
+	packages := []string{"docker-ce-" + e.Version}
+	// the client is packaged separately since Docker 18.09
+	parts := strings.Split(e.Version, ".")
+	major, _ := strconv.Atoi(parts[0])
+	minor, _ := strconv.Atoi(parts[1])
+	if major > 18 || major == 18 && minor >= 9 {
+		packages = append(packages, "docker-ce-cli-"+e.Version)
+	}
+	return packages
+}
+
+// downloadCommands returns the commands downloading the packages of the selected version and the dependencies which
+// are not part of the ISO image into the cache directory. The download goes to a temporary directory first, so that
+// an interrupted download is not mistaken for a complete one.
+func (e Engine) downloadCommands() []string {
+	partDir := e.cacheDir() + ".part"
+	var commands []string
+	if IsCommunityEdition(e.Version) {
+		commands = append(commands, fmt.Sprintf("%s curl -sSL -o /etc/yum.repos.d/docker-ce.repo %s",
+			e.sudoWithProxy(), engineRepositories[e.PackageManager]))
+	}
+	return append(commands,
+		fmt.Sprintf("sudo rm -rf %s && sudo mkdir -p %s", partDir, partDir),
+		fmt.Sprintf("%s %s install -y --downloadonly --downloaddir=%s %s",
+			e.sudoWithProxy(), e.PackageManager, partDir, strings.Join(e.packages(), " ")),
+		fmt.Sprintf("sudo rm -rf %s && sudo mv %s %s", e.cacheDir(), partDir, e.cacheDir()))
+}
+
+// installCommands returns the commands replacing the installed Docker engine by the cached packages of the selected
+// version, without accessing any repository
+func (e Engine) installCommands() []string {
+	return []string{
+		"sudo systemctl stop docker",
+		fmt.Sprintf("installed=$(rpm -q --qf '%%{NAME}\\n' %s | grep -v 'is not installed'); if [ -n \"$installed\" ]; then sudo rpm -e --nodeps $installed; fi",
+			strings.Join(enginePackages, " ")),
+		fmt.Sprintf("sudo %s install -y --disablerepo='*' %s/*.rpm", e.PackageManager, e.cacheDir()),
+		"sudo systemctl daemon-reload",
+	}
+}
+
+// sudoWithProxy returns the sudo invocation passing the proxy settings to the command
+func (e Engine) sudoWithProxy() string {
+	if len(e.ProxyEnv) == 0 {
+		return "sudo"
+	}
+	var env []string
+	for _, variable := range e.ProxyEnv {
+		env = append(env, sshutil.Quote(variable))
+	}
+	return "sudo env " + strings.Join(env, " ")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingCommander struct {
+	provision.SSHCommander
+	version  string
+	cached   bool
+	commands []string
+}
+
+func (c *recordingCommander) SSHCommand(command string) (string, error) {
+	c.commands = append(c.commands, command)
+	switch {
+	case command == "docker --version":
+		return "Docker version " + c.version + ", build 7d71120/" + c.version + "\n", nil
+	case strings.HasPrefix(command, "ls ") && !c.cached:
+		return "", errors.New("No such file or directory")
+	}
+	return "", nil
+}
+
+func TestValidateEngineVersion(t *testing.T) {
+	assert.NoError(t, ValidateEngineVersion("18.09.9"))
+	assert.NoError(t, ValidateEngineVersion("1.13.1"))
+	assert.Error(t, ValidateEngineVersion("18.09"))
+	assert.Error(t, ValidateEngineVersion("18.06.1-ce"))
+	assert.Error(t, ValidateEngineVersion("latest"))
+}
+
+func TestIsCommunityEdition(t *testing.T) {
+	assert.True(t, IsCommunityEdition("18.09.9"))
+	assert.False(t, IsCommunityEdition("1.13.1"))
+}
+
+func TestInstallKeepsInstalledVersion(t *testing.T) {
+	commander := &recordingCommander{version: "1.13.1"}
+
+	switched, err := Engine{Version: "1.13.1", PackageManager: "yum"}.Install(commander)
+	assert.NoError(t, err)
+	assert.False(t, switched)
+	assert.Equal(t, []string{"docker --version"}, commander.commands)
+
+	switched, err = Engine{PackageManager: "yum"}.Install(commander)
+	assert.NoError(t, err)
+	assert.False(t, switched)
+	assert.Len(t, commander.commands, 1)
+}
+
+func TestInstallDownloadsVersionOnce(t *testing.T) {
+	commander := &recordingCommander{version: "1.13.1"}
+	engine := Engine{Version: "18.09.9", PackageManager: "yum", ProxyEnv: []string{"https_proxy=http://proxy.foo.com:3128"}}
+
+	switched, err := engine.Install(commander)
+	assert.NoError(t, err)
+	assert.True(t, switched)
+	assert.Equal(t, []string{
+		"docker --version",
+		"ls /var/lib/minishift/docker-engine/18.09.9/*.rpm",
+		"sudo env 'https_proxy=http://proxy.foo.com:3128' curl -sSL -o /etc/yum.repos.d/docker-ce.repo https://download.docker.com/linux/centos/docker-ce.repo",
+		"sudo rm -rf /var/lib/minishift/docker-engine/18.09.9.part && sudo mkdir -p /var/lib/minishift/docker-engine/18.09.9.part",
+		"sudo env 'https_proxy=http://proxy.foo.com:3128' yum install -y --downloadonly --downloaddir=/var/lib/minishift/docker-engine/18.09.9.part docker-ce-18.09.9 docker-ce-cli-18.09.9",
+		"sudo rm -rf /var/lib/minishift/docker-engine/18.09.9 && sudo mv /var/lib/minishift/docker-engine/18.09.9.part /var/lib/minishift/docker-engine/18.09.9",
+	}, commander.commands[:6])
+	assert.Equal(t, engine.installCommands(), commander.commands[6:])
+
+	commander = &recordingCommander{version: "1.13.1", cached: true}
+	switched, err = engine.Install(commander)
+	assert.NoError(t, err)
+	assert.True(t, switched)
+	assert.Equal(t, append([]string{"docker --version", "ls /var/lib/minishift/docker-engine/18.09.9/*.rpm"}, engine.installCommands()...), commander.commands)
+}
+
+func TestInstallCommandsAreOffline(t *testing.T) {
+	commands := Engine{Version: "18.09.9", PackageManager: "dnf"}.installCommands()
+	assert.Equal(t, "sudo systemctl stop docker", commands[0])
+	assert.True(t, strings.HasSuffix(commands[1], "sudo rpm -e --nodeps $installed; fi"))
+	assert.Equal(t, "sudo dnf install -y --disablerepo='*' /var/lib/minishift/docker-engine/18.09.9/*.rpm", commands[2])
+	assert.Equal(t, "sudo systemctl daemon-reload", commands[3])
+}
+
+func TestPackagesByVersion(t *testing.T) {
+	assert.Equal(t, []string{"docker-1.13.1"}, Engine{Version: "1.13.1"}.packages())
+	assert.Equal(t, []string{"docker-ce-18.06.3"}, Engine{Version: "18.06.3"}.packages())
+	assert.Equal(t, []string{"docker-ce-19.03.5", "docker-ce-cli-19.03.5"}, Engine{Version: "19.03.5"}.packages())
+
+	commands := Engine{Version: "1.13.1", PackageManager: "yum"}.downloadCommands()
+	assert.Len(t, commands, 3)
+	assert.Contains(t, commands[1], "sudo yum install -y --downloadonly")
+}
+
+func TestInstallNeedsPackageManager(t *testing.T) {
+	commander := &recordingCommander{version: "18.06.1-ce"}
+
+	_, err := Engine{Version: "18.09.9", PackageManager: ""}.Install(commander)
+	assert.EqualError(t, err, "Selecting the Docker version is not supported without a package manager")
+	assert.Empty(t, commander.commands)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/containers/image/signature"
+	"github.com/containers/image/transports/alltransports"
+	"github.com/docker/docker/api/types"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/docker/go-connections/tlsconfig"
+)
+
+const (
+	// dockerAPIVersion matches the API version the docker-daemon transport of containers/image uses
+	dockerAPIVersion = "1.22"
+	untaggedImage    = "<none>:<none>"
+)
+
+// GetDockerImages returns a map of images available in the Docker daemon. The daemon is queried via its API from the host.
+func (handler *OciImageHandler) GetDockerImages() (map[string]bool, error) {
+	client, err := handler.newDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	summaries, err := client.ImageList(context.TODO(), types.ImageListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the images of the Docker daemon: %v", err)
+	}
+
+	dockerImages := make(map[string]bool)
+	for _, summary := range summaries {
+		for _, tag := range summary.RepoTags {
+			// dangling images have no name which could be cached
+			if tag != untaggedImage {
+				dockerImages[tag] = true
+			}
+		}
+	}
+
+	return dockerImages, nil
+}
+
+// pullImage copies the image from its registry into the Docker daemon of the VM. The copy runs on the host,
+// so no Docker client is needed neither on the host nor within the VM.
+func (handler *OciImageHandler) pullImage(image string, config *ImageCacheConfig, policyContext *signature.PolicyContext) error {
+	srcRef, err := alltransports.ParseImageName(fmt.Sprintf("docker://%s", image))
+	if err != nil {
+		return fmt.Errorf("Invalid image source '%s': %v", image, err)
+	}
+
+	destRef, err := alltransports.ParseImageName(fmt.Sprintf("docker-daemon:%s", image))
+	if err != nil {
+		return fmt.Errorf("Invalid image destination '%s': %v", image, err)
+	}
+
+	return handler.copyImage(srcRef, destRef, policyContext, config)
+}
+
+// newDockerClient creates an API client for the Docker daemon of the VM, using the same connection settings
+// as the docker-daemon transport of containers/image.
+func (handler *OciImageHandler) newDockerClient() (*dockerclient.Client, error) {
+	if handler.dockerClientSettings == nil {
+		return nil, fmt.Errorf("No Docker daemon settings available")
+	}
+
+	url, err := dockerclient.ParseHostURL(handler.dockerClientSettings.DockerHost)
+	if err != nil {
+		return nil, err
+	}
+
+	var httpClient *http.Client
+	switch url.Scheme {
+	case "unix":
+		// unix sockets only work with the default client of the Docker API client
+	case "http":
+		httpClient = &http.Client{Transport: &http.Transport{}, CheckRedirect: dockerclient.CheckRedirect}
+	default:
+		options := tlsconfig.Options{InsecureSkipVerify: !handler.dockerClientSettings.DockerTLSVerify}
+		if certPath := handler.dockerClientSettings.DockerCertPath; certPath != "" {
+			options.CAFile = filepath.Join(certPath, "ca.pem")
+			options.CertFile = filepath.Join(certPath, "cert.pem")
+			options.KeyFile = filepath.Join(certPath, "key.pem")
+		}
+		tlsConfig, err := tlsconfig.Client(options)
+		if err != nil {
+			return nil, err
+		}
+		httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}, CheckRedirect: dockerclient.CheckRedirect}
+	}
+
+	return dockerclient.NewClient(handler.dockerClientSettings.DockerHost, dockerAPIVersion, httpClient, nil)
+}
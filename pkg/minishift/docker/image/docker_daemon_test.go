@@ -0,0 +1,50 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Docker_Images_Are_Listed_Via_Daemon_Api(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/v%s/images/json", dockerAPIVersion), r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[
+			{"Id": "sha256:1", "RepoTags": ["openshift/origin-control-plane:v3.11.0"]},
+			{"Id": "sha256:2", "RepoTags": ["alpine:latest", "alpine:3.9"]},
+			{"Id": "sha256:3", "RepoTags": ["<none>:<none>"]}
+		]`)
+	}))
+	defer server.Close()
+
+	handler := OciImageHandler{dockerClientSettings: &dockerClientConfig{DockerHost: server.URL}}
+	images, err := handler.GetDockerImages()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"openshift/origin-control-plane:v3.11.0": true, "alpine:latest": true, "alpine:3.9": true}, images)
+}
+
+func Test_Docker_Client_Requires_Daemon_Settings(t *testing.T) {
+	handler := OciImageHandler{}
+	_, err := handler.GetDockerImages()
+	assert.EqualError(t, err, "No Docker daemon settings available")
+}
@@ -0,0 +1,144 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+)
+
+var (
+	// SupportedHostClis are the container engine clients of the host which images can be loaded from
+	SupportedHostClis = []string{"docker", "podman"}
+)
+
+// HostImageLoader loads images from the container engine of the host into the Docker daemon of the VM.
+type HostImageLoader struct {
+	driver  drivers.Driver
+	hostCli string
+}
+
+// NewHostImageLoader creates a HostImageLoader using the specified container engine client of the host.
+// If hostCli is empty, the first available client out of SupportedHostClis is used.
+func NewHostImageLoader(driver drivers.Driver, hostCli string) (*HostImageLoader, error) {
+	if hostCli == "" {
+		hostCli = DetectHostCli()
+		if hostCli == "" {
+			return nil, fmt.Errorf("None of the container engine clients %v could be found on the host", SupportedHostClis)
+		}
+	} else if !isSupportedHostCli(hostCli) {
+		return nil, fmt.Errorf("Unsupported container engine client '%s'. Supported clients are %v", hostCli, SupportedHostClis)
+	}
+
+	return &HostImageLoader{driver: driver, hostCli: hostCli}, nil
+}
+
+// DetectHostCli returns the first container engine client out of SupportedHostClis available in the PATH of the host.
+func DetectHostCli() string {
+	for _, cli := range SupportedHostClis {
+		if _, err := exec.LookPath(cli); err == nil {
+			return cli
+		}
+	}
+	return ""
+}
+
+// LoadImages streams each of the specified images from the host into the Docker daemon of the VM.
+// The method returns the list of successfully loaded images and an error if one occurred.
+func (loader *HostImageLoader) LoadImages(images []string, out io.Writer) ([]string, error) {
+	return processImages(images, 1, out, "Loading", func(image string) (ProgressStatus, error) {
+		err := loader.loadImage(image)
+		if err != nil {
+			return FAIL, err
+		}
+		return OK, nil
+	})
+}
+
+func (loader *HostImageLoader) loadImage(image string) error {
+	var saveErr bytes.Buffer
+	saveCmd := exec.Command(loader.hostCli, "save", image)
+	saveCmd.Env = hostEnv()
+	saveCmd.Stderr = &saveErr
+	archive, err := saveCmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	sshClient, err := sshutil.NewSSHClient(loader.driver)
+	if err != nil {
+		return err
+	}
+	defer sshClient.Close()
+
+	session, err := sshClient.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	var loadOut bytes.Buffer
+	session.Stdin = archive
+	session.Stdout = &loadOut
+	session.Stderr = &loadOut
+
+	if err := saveCmd.Start(); err != nil {
+		return fmt.Errorf("Error running '%s save %s': %v", loader.hostCli, image, err)
+	}
+
+	loadErr := session.Run("docker load")
+	if loadErr != nil {
+		// nothing reads the output of the save command any longer, which would block it forever
+		saveCmd.Process.Kill()
+		saveCmd.Wait()
+		return fmt.Errorf("Error loading image '%s' into the Docker daemon: %v %s", image, loadErr, strings.TrimSpace(loadOut.String()))
+	}
+	if err := saveCmd.Wait(); err != nil {
+		return fmt.Errorf("Error running '%s save %s': %v %s", loader.hostCli, image, err, strings.TrimSpace(saveErr.String()))
+	}
+
+	return nil
+}
+
+// hostEnv returns the environment of the process without the DOCKER_* variables. Once 'minishift start' applied the
+// Docker environment of the VM, or 'minishift docker-env' has been evaluated, they point to the Docker daemon of the VM
+// instead of the container engine of the host.
+func hostEnv() []string {
+	var env []string
+	for _, variable := range os.Environ() {
+		if !strings.HasPrefix(strings.ToUpper(variable), "DOCKER_") {
+			env = append(env, variable)
+		}
+	}
+	return env
+}
+
+func isSupportedHostCli(hostCli string) bool {
+	for _, cli := range SupportedHostClis {
+		if cli == hostCli {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Unsupported_Host_Cli_Is_Rejected(t *testing.T) {
+	_, err := NewHostImageLoader(nil, "rkt")
+	assert.EqualError(t, err, "Unsupported container engine client 'rkt'. Supported clients are [docker podman]")
+
+	for _, cli := range SupportedHostClis {
+		loader, err := NewHostImageLoader(nil, cli)
+		assert.NoError(t, err)
+		assert.Equal(t, cli, loader.hostCli)
+	}
+}
+
+func Test_Host_Env_Excludes_Docker_Variables(t *testing.T) {
+	os.Setenv("DOCKER_HOST", "tcp://192.168.99.100:2376")
+	defer os.Unsetenv("DOCKER_HOST")
+
+	env := hostEnv()
+	assert.NotContains(t, env, "DOCKER_HOST=tcp://192.168.99.100:2376")
+	assert.Contains(t, env, "PATH="+os.Getenv("PATH"))
+}
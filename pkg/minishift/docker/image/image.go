@@ -36,6 +36,10 @@ type ImageCacheConfig struct {
 	CachedImages      []string
 	Out               io.Writer
 	ImageMissStrategy ImageMissStrategy
+	// Workers is the number of images imported or exported concurrently. Values smaller than 2 process the images serially.
+	Workers int
+	// InsecureRegistries are the registries, as host[:port] or CIDR, which are accessed without TLS verification when fetching images
+	InsecureRegistries []string
 }
 
 // GetOpenShiftImageNames returns the full images names for the images requires for a fully functioning OpenShift instance
@@ -47,6 +51,16 @@ func GetOpenShiftImageNames(version string) []string {
 	}
 }
 
+// GetClusterUpImageNames returns the full names of all images 'oc cluster up' uses to provision an OpenShift instance,
+// including the images of the components which are installed after the cluster is running.
+func GetClusterUpImageNames(version string) []string {
+	images := GetOpenShiftImageNames(version)
+	for _, component := range []string{"cli", "hyperkube", "hypershift", "node", "pod", "service-serving-cert-signer", "web-console"} {
+		images = append(images, fmt.Sprintf("openshift/origin-%s:%s", component, version))
+	}
+	return images
+}
+
 func CreateExportCommand(version string, profile string, images []string) (*exec.Cmd, error) {
 	cmd, err := os.CurrentExecutable()
 	if err != nil {
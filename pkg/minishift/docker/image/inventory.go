@@ -0,0 +1,190 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	stringUtils "github.com/minishift/minishift/pkg/util/strings"
+)
+
+const (
+	podNamespaceLabel = "io.kubernetes.pod.namespace"
+	podNameLabel      = "io.kubernetes.pod.name"
+)
+
+// ImageDetails describes an image of the Docker daemon or of the local cache.
+type ImageDetails struct {
+	Name    string
+	ID      string
+	Size    int64
+	Created time.Time
+}
+
+// InventoryEntry combines the details of an image in the Docker daemon and in the local cache with the pods using it.
+// VM and Cache are nil if the image is not available in the Docker daemon respectively the local cache.
+type InventoryEntry struct {
+	Name  string
+	VM    *ImageDetails
+	Cache *ImageDetails
+	Pods  []string
+}
+
+type ociManifest struct {
+	Config struct {
+		Digest string `json:"digest"`
+		Size   int64  `json:"size"`
+	} `json:"config"`
+	Layers []struct {
+		Size int64 `json:"size"`
+	} `json:"layers"`
+}
+
+type ociConfig struct {
+	Created time.Time `json:"created"`
+}
+
+// GetDockerImageDetails returns the details of the tagged images of the Docker daemon.
+func (handler *OciImageHandler) GetDockerImageDetails() ([]ImageDetails, error) {
+	client, err := handler.newDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	summaries, err := client.ImageList(context.TODO(), types.ImageListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the images of the Docker daemon: %v", err)
+	}
+
+	var details []ImageDetails
+	for _, summary := range summaries {
+		for _, tag := range summary.RepoTags {
+			if tag != untaggedImage {
+				details = append(details, ImageDetails{Name: tag, ID: summary.ID, Size: summary.Size, Created: time.Unix(summary.Created, 0)})
+			}
+		}
+	}
+	return details, nil
+}
+
+// GetPodsByImageID returns the pods, as namespace/name, using an image for each image ID. Only running containers
+// created by the kubelet are considered.
+func (handler *OciImageHandler) GetPodsByImageID() (map[string][]string, error) {
+	client, err := handler.newDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	containers, err := client.ContainerList(context.TODO(), types.ContainerListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the containers of the Docker daemon: %v", err)
+	}
+
+	pods := make(map[string][]string)
+	for _, container := range containers {
+		name, ok := container.Labels[podNameLabel]
+		if !ok {
+			continue
+		}
+		pod := fmt.Sprintf("%s/%s", container.Labels[podNamespaceLabel], name)
+		if !stringUtils.Contains(pods[container.ImageID], pod) {
+			pods[container.ImageID] = append(pods[container.ImageID], pod)
+		}
+	}
+	return pods, nil
+}
+
+// GetCachedImageDetails returns the details of the images in the local cache. The size of an image is the sum of
+// its blob sizes, blobs shared between images are accounted for each image.
+func (handler *OciImageHandler) GetCachedImageDetails(config *ImageCacheConfig) ([]ImageDetails, error) {
+	index, err := handler.getIndex(config.HostCacheDir)
+	if index == nil || err != nil {
+		return nil, err
+	}
+
+	var details []ImageDetails
+	for _, entry := range index.Manifests {
+		var manifest ociManifest
+		if err := readBlob(config.HostCacheDir, entry.Digest, &manifest); err != nil {
+			return nil, fmt.Errorf("Error reading the manifest of '%s': %v", entry.Annotations.Name, err)
+		}
+
+		size := entry.Size + manifest.Config.Size
+		for _, layer := range manifest.Layers {
+			size += layer.Size
+		}
+
+		var imageConfig ociConfig
+		if err := readBlob(config.HostCacheDir, manifest.Config.Digest, &imageConfig); err != nil {
+			return nil, fmt.Errorf("Error reading the configuration of '%s': %v", entry.Annotations.Name, err)
+		}
+
+		details = append(details, ImageDetails{Name: entry.Annotations.Name, ID: manifest.Config.Digest, Size: size, Created: imageConfig.Created})
+	}
+	return details, nil
+}
+
+// BuildInventory merges the images of the Docker daemon and of the local cache by name. The entries are sorted by name.
+func BuildInventory(vmImages []ImageDetails, cachedImages []ImageDetails, podsByImageID map[string][]string) []InventoryEntry {
+	entries := make(map[string]*InventoryEntry)
+	entry := func(name string) *InventoryEntry {
+		if _, found := entries[name]; !found {
+			entries[name] = &InventoryEntry{Name: name}
+		}
+		return entries[name]
+	}
+
+	for i := range vmImages {
+		e := entry(vmImages[i].Name)
+		e.VM = &vmImages[i]
+		e.Pods = podsByImageID[vmImages[i].ID]
+	}
+	for i := range cachedImages {
+		entry(cachedImages[i].Name).Cache = &cachedImages[i]
+	}
+
+	var names []string
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inventory := make([]InventoryEntry, 0, len(names))
+	for _, name := range names {
+		inventory = append(inventory, *entries[name])
+	}
+	return inventory
+}
+
+func readBlob(cacheDir string, digest string, v interface{}) error {
+	path := filepath.Join(cacheDir, "blobs", strings.Replace(digest, ":", string(filepath.Separator), 1))
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
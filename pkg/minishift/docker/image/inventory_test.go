@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Build_Inventory_Merges_VM_And_Cache(t *testing.T) {
+	vmImages := []ImageDetails{
+		{Name: "openshift/origin-pod:v3.11.0", ID: "sha256:1", Size: 200},
+		{Name: "alpine:latest", ID: "sha256:2", Size: 100},
+	}
+	cachedImages := []ImageDetails{
+		{Name: "openshift/origin-pod:v3.11.0", ID: "sha256:1", Size: 180},
+		{Name: "busybox:latest", ID: "sha256:3", Size: 50},
+	}
+	pods := map[string][]string{"sha256:1": {"default/router-1-abcde", "default/docker-registry-1-fghij"}}
+
+	inventory := BuildInventory(vmImages, cachedImages, pods)
+
+	assert.Len(t, inventory, 3)
+	assert.Equal(t, "alpine:latest", inventory[0].Name)
+	assert.Nil(t, inventory[0].Cache)
+	assert.Empty(t, inventory[0].Pods)
+	assert.Equal(t, "busybox:latest", inventory[1].Name)
+	assert.Nil(t, inventory[1].VM)
+	assert.Equal(t, "openshift/origin-pod:v3.11.0", inventory[2].Name)
+	assert.Equal(t, int64(200), inventory[2].VM.Size)
+	assert.Equal(t, int64(180), inventory[2].Cache.Size)
+	assert.Equal(t, pods["sha256:1"], inventory[2].Pods)
+}
+
+func Test_Cached_Image_Details(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "minishift-test-inventory-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	created := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	config := writeTestBlob(t, cacheDir, fmt.Sprintf(`{"created": "%s"}`, created.Format(time.RFC3339)))
+	manifest := writeTestBlob(t, cacheDir, fmt.Sprintf(`{"config": {"digest": "%s", "size": 10}, "layers": [{"size": 100}, {"size": 1000}]}`, config))
+	index := fmt.Sprintf(`{"schemaVersion": 2, "manifests": [{"digest": "%s", "size": 1, "annotations": {"org.opencontainers.image.ref.name": "alpine:latest"}}]}`, manifest)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "index.json"), []byte(index), 0644))
+
+	handler := OciImageHandler{}
+	details, err := handler.GetCachedImageDetails(&ImageCacheConfig{HostCacheDir: cacheDir})
+	assert.NoError(t, err)
+	assert.Equal(t, []ImageDetails{{Name: "alpine:latest", ID: config, Size: 1111, Created: created}}, details)
+}
+
+func Test_Pods_Are_Determined_From_Running_Containers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/v%s/containers/json", dockerAPIVersion), r.URL.Path)
+		fmt.Fprint(w, `[
+			{"Id": "a", "ImageID": "sha256:1", "Labels": {"io.kubernetes.pod.namespace": "default", "io.kubernetes.pod.name": "router-1-abcde"}},
+			{"Id": "b", "ImageID": "sha256:1", "Labels": {"io.kubernetes.pod.namespace": "default", "io.kubernetes.pod.name": "router-1-abcde"}},
+			{"Id": "c", "ImageID": "sha256:2", "Labels": {}}
+		]`)
+	}))
+	defer server.Close()
+
+	handler := OciImageHandler{dockerClientSettings: &dockerClientConfig{DockerHost: server.URL}}
+	pods, err := handler.GetPodsByImageID()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"sha256:1": {"default/router-1-abcde"}}, pods)
+}
+
+func writeTestBlob(t *testing.T, cacheDir string, content string) string {
+	d := digest.FromString(content)
+	assert.NoError(t, os.MkdirAll(filepath.Join(cacheDir, "blobs", "sha256"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "blobs", "sha256", d.Hex()), []byte(content), 0644))
+	return d.String()
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+)
+
+// layerTracker makes sure that a layer shared by images which are copied into the cache concurrently is only
+// transferred once. The first copy which finds the layer missing claims it, the other copies wait until it is written.
+type layerTracker struct {
+	mutex    sync.Mutex
+	inFlight map[digest.Digest]chan struct{}
+}
+
+// cacheLayers tracks the layers which are currently written into the cache
+var cacheLayers = newLayerTracker()
+
+func newLayerTracker() *layerTracker {
+	return &layerTracker{inFlight: make(map[digest.Digest]chan struct{})}
+}
+
+func (tracker *layerTracker) release(digests []digest.Digest) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	for _, d := range digests {
+		if done, ok := tracker.inFlight[d]; ok {
+			close(done)
+			delete(tracker.inFlight, d)
+		}
+	}
+}
+
+// layerDedupReference wraps the reference of a cache destination, so that its layers are tracked by the layerTracker.
+type layerDedupReference struct {
+	types.ImageReference
+	tracker *layerTracker
+}
+
+func (ref layerDedupReference) NewImageDestination(ctx context.Context, sys *types.SystemContext) (types.ImageDestination, error) {
+	dest, err := ref.ImageReference.NewImageDestination(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+	return &layerDedupDestination{ImageDestination: dest, tracker: ref.tracker}, nil
+}
+
+type layerDedupDestination struct {
+	types.ImageDestination
+	tracker *layerTracker
+	claimed []digest.Digest
+}
+
+// HasBlob waits for a copy transferring the same layer and then checks whether the layer is present. If it is
+// missing, this copy claims the layer, which it is going to transfer next.
+func (dest *layerDedupDestination) HasBlob(ctx context.Context, info types.BlobInfo) (bool, int64, error) {
+	for {
+		dest.tracker.mutex.Lock()
+		done, inFlight := dest.tracker.inFlight[info.Digest]
+		if !inFlight {
+			present, size, err := dest.ImageDestination.HasBlob(ctx, info)
+			if err == nil && !present {
+				dest.tracker.inFlight[info.Digest] = make(chan struct{})
+				dest.claimed = append(dest.claimed, info.Digest)
+			}
+			dest.tracker.mutex.Unlock()
+			return present, size, err
+		}
+		dest.tracker.mutex.Unlock()
+		// if the other copy failed, the layer is still missing and claimed by this copy in the next iteration
+		<-done
+	}
+}
+
+// PutBlob writes the layer and releases the claim. Layers of an image are copied one after the other, so the claim
+// is released even if the digest changed, for example due to compression.
+func (dest *layerDedupDestination) PutBlob(ctx context.Context, stream io.Reader, inputInfo types.BlobInfo, isConfig bool) (types.BlobInfo, error) {
+	defer dest.releaseClaims()
+	return dest.ImageDestination.PutBlob(ctx, stream, inputInfo, isConfig)
+}
+
+func (dest *layerDedupDestination) Close() error {
+	dest.releaseClaims()
+	return dest.ImageDestination.Close()
+}
+
+func (dest *layerDedupDestination) releaseClaims() {
+	dest.tracker.release(dest.claimed)
+	dest.claimed = nil
+}
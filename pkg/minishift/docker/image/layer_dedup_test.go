@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBlobStore is a destination keeping the written blobs in memory, shared by all destinations of a test
+type fakeBlobStore struct {
+	types.ImageDestination
+	mutex   sync.Mutex
+	blobs   map[digest.Digest]bool
+	puts    map[digest.Digest]int
+	failPut bool
+}
+
+func (store *fakeBlobStore) HasBlob(ctx context.Context, info types.BlobInfo) (bool, int64, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.blobs[info.Digest], 1, nil
+}
+
+func (store *fakeBlobStore) PutBlob(ctx context.Context, stream io.Reader, info types.BlobInfo, isConfig bool) (types.BlobInfo, error) {
+	// give concurrent copies the chance to ask for the same layer while it is written
+	time.Sleep(50 * time.Millisecond)
+	ioutil.ReadAll(stream)
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.puts[info.Digest]++
+	if store.failPut {
+		store.failPut = false
+		return types.BlobInfo{}, errors.New("write failed")
+	}
+	store.blobs[info.Digest] = true
+	return info, nil
+}
+
+func (store *fakeBlobStore) Close() error {
+	return nil
+}
+
+// copyLayer mimics the layer handling of copy.Image
+func copyLayer(dest types.ImageDestination, layer digest.Digest) error {
+	present, _, err := dest.HasBlob(context.TODO(), types.BlobInfo{Digest: layer})
+	if err != nil || present {
+		return err
+	}
+	_, err = dest.PutBlob(context.TODO(), strings.NewReader("layer"), types.BlobInfo{Digest: layer, Size: -1}, false)
+	return err
+}
+
+func Test_Shared_Layers_Are_Written_Once(t *testing.T) {
+	store := &fakeBlobStore{blobs: make(map[digest.Digest]bool), puts: make(map[digest.Digest]int)}
+	tracker := newLayerTracker()
+	shared := digest.FromString("shared")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dest := &layerDedupDestination{ImageDestination: store, tracker: tracker}
+			defer dest.Close()
+			assert.NoError(t, copyLayer(dest, shared))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, store.puts[shared], "The shared layer should be written exactly once")
+	assert.Empty(t, tracker.inFlight)
+}
+
+func Test_Layer_Is_Written_By_Waiting_Copy_If_Write_Fails(t *testing.T) {
+	store := &fakeBlobStore{blobs: make(map[digest.Digest]bool), puts: make(map[digest.Digest]int), failPut: true}
+	tracker := newLayerTracker()
+	shared := digest.FromString("shared")
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			dest := &layerDedupDestination{ImageDestination: store, tracker: tracker}
+			defer dest.Close()
+			errs <- copyLayer(dest, shared)
+		}()
+	}
+
+	assert.Error(t, <-errs)
+	assert.NoError(t, <-errs)
+	assert.Equal(t, 2, store.puts[shared])
+	assert.True(t, store.blobs[shared])
+}
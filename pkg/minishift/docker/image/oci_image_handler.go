@@ -21,8 +21,9 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"sync"
+	"time"
 
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -32,22 +33,30 @@ import (
 	"github.com/containers/image/transports/alltransports"
 	"github.com/containers/image/types"
 	"github.com/docker/machine/libmachine/drivers"
-	"github.com/minishift/minishift/pkg/minikube/sshutil"
 	"github.com/minishift/minishift/pkg/util"
 	"github.com/minishift/minishift/pkg/util/filehelper"
+	"github.com/minishift/minishift/pkg/util/logging"
+	"github.com/minishift/minishift/pkg/util/os/lock"
 	"github.com/minishift/minishift/pkg/util/progressdots"
-	"golang.org/x/crypto/ssh"
 	"io/ioutil"
 	"path/filepath"
 	"strings"
 )
 
+var logger = logging.For("image")
+
 // OciImageHandler is an ImageHandler implementation using OCI format to maintain the local cache.
 type OciImageHandler struct {
 	driver               drivers.Driver
 	dockerClientSettings *dockerClientConfig
 }
 
+// indexLock guards read-modify-write cycles of the cache index.json, since images can be exported concurrently
+var indexLock sync.Mutex
+
+// indexLockTimeout is the time to wait for another Minishift process updating the cache index.json
+const indexLockTimeout = 30 * time.Second
+
 type dockerClientConfig struct {
 	DockerHost      string
 	DockerCertPath  string
@@ -111,32 +120,24 @@ func (handler *OciImageHandler) ImportImages(config *ImageCacheConfig) ([]string
 		return importedImages, err
 	}
 
-	multiError := util.MultiError{}
-	for _, imageName := range config.CachedImages {
-		fmt.Fprint(out, fmt.Sprintf("   Importing '%s' ", imageName))
-		progressDots := progressdots.New()
-		progressDots.SetWriter(out)
-		progressDots.Start()
+	return processImages(config.CachedImages, config.Workers, out, "Importing", func(imageName string) (ProgressStatus, error) {
 		if _, found := availableImages[imageName]; found {
-			handler.endProgress(progressDots, out, OK)
-			importedImages = append(importedImages, imageName)
-			continue
-
+			return OK, nil
 		}
 
 		if !handler.IsImageCached(config, imageName) {
-			handler.endProgress(progressDots, out, CACHE_MISS)
-			continue
+			if config.ImageMissStrategy != Pull {
+				return CACHE_MISS, nil
+			}
+			if err := handler.syncImage(imageName, config, policyContext, out); err != nil {
+				logger.Debugf("Unable to fetch '%s' into the cache: %v", imageName, err)
+				return CACHE_MISS, nil
+			}
 		}
 
 		err := handler.importImage(imageName, config, policyContext, out)
-		handler.endProgress(progressDots, out, handler.progressStatusForError(err))
-		multiError.Collect(err)
-		if err == nil {
-			importedImages = append(importedImages, imageName)
-		}
-	}
-	return importedImages, multiError.ToError()
+		return handler.progressStatusForError(err), err
+	})
 }
 
 // ExportImages exports the images specified as part of the ImageCacheConfig from the VM to the host.
@@ -149,25 +150,33 @@ func (handler *OciImageHandler) ExportImages(config *ImageCacheConfig, overwrite
 		return exportedImages, fmt.Errorf("Error creating security context: %s", err.Error())
 	}
 
-	multiError := util.MultiError{}
-	for _, imageName := range config.CachedImages {
-		fmt.Fprint(out, fmt.Sprintf("Exporting '%s'", imageName))
-		err = nil
-		progressDots := progressdots.New()
-		progressDots.SetWriter(out)
-		progressDots.Start()
-
+	return processImages(config.CachedImages, config.Workers, out, "Exporting", func(imageName string) (ProgressStatus, error) {
+		var err error
 		if !handler.IsImageCached(config, imageName) || overwrite {
 			err = handler.exportImage(imageName, config, policyContext, out, overwrite)
 		}
-		handler.endProgress(progressDots, out, handler.progressStatusForError(err))
-		multiError.Collect(err)
-		if err != nil {
-			exportedImages = append(exportedImages, imageName)
-		}
+		return handler.progressStatusForError(err), err
+	})
+}
+
+// FetchImages fetches the images specified as part of the ImageCacheConfig from their registries into the cache,
+// without involving the Docker daemon of the VM. Images which are already cached are skipped.
+func (handler *OciImageHandler) FetchImages(config *ImageCacheConfig) ([]string, error) {
+	out := handler.getOutputWriter(config)
+	fetchedImages := []string{}
+
+	policyContext, err := handler.getPolicyContext()
+	if err != nil {
+		return fetchedImages, fmt.Errorf("Error creating security context: %s", err.Error())
 	}
 
-	return exportedImages, multiError.ToError()
+	return processImages(config.CachedImages, config.Workers, out, "Fetching", func(imageName string) (ProgressStatus, error) {
+		var err error
+		if !handler.IsImageCached(config, imageName) {
+			err = handler.syncImage(imageName, config, policyContext, out)
+		}
+		return handler.progressStatusForError(err), err
+	})
 }
 
 // PruneImages delete the specified as command line option.
@@ -231,52 +240,6 @@ func (handler *OciImageHandler) GetCachedImages(config *ImageCacheConfig) map[st
 	return cachedImages
 }
 
-func (handler *OciImageHandler) GetDockerImages() (map[string]bool, error) {
-	dockerImages := make(map[string]bool)
-
-	session, err := handler.createSSHSession()
-	if err != nil {
-		return nil, err
-	}
-	defer session.Close()
-
-	// We don't want to have an image which have <none> tag in our output list
-	// Also in case there is no image in docker daemon then exit code should be 0 instead 1
-	// and `|| true` is used.
-	// which refer to dangling image for docker.
-	cmd := "docker images --format '{{.Repository}}:{{.Tag}}' | grep -vw '<none>' || true"
-	var buffer bytes.Buffer
-	session.Stdout = &buffer
-	err = session.Run(cmd)
-	if err != nil {
-		return nil, fmt.Errorf("Error running command '%s': %v", cmd, err)
-	}
-
-	for _, image := range strings.Split(buffer.String(), "\n") {
-		if len(image) > 0 {
-			dockerImages[image] = true
-		}
-	}
-
-	return dockerImages, nil
-}
-
-func (handler *OciImageHandler) pullImage(image string, out io.Writer) error {
-	session, err := handler.createSSHSession()
-	if err != nil {
-		return err
-	}
-	defer session.Close()
-
-	cmd := fmt.Sprintf("docker pull %s", image)
-	cmdOut, err := session.CombinedOutput(cmd)
-	if err != nil {
-		return fmt.Errorf("Error running command '%s': %v \n%s", cmd, err, string(cmdOut[:]))
-	}
-
-	return nil
-}
-
 func (handler *OciImageHandler) getIndex(cacheDir string) (*Index, error) {
 	indexPath := filepath.Join(cacheDir, "index.json")
 	if !filehelper.Exists(indexPath) {
@@ -308,7 +271,7 @@ func (handler *OciImageHandler) importImage(image string, config *ImageCacheConf
 		return fmt.Errorf("Invalid image source '%s': %v", image, err)
 	}
 
-	err = handler.copyImage(srcRef, destRef, policyContext, config.HostCacheDir)
+	err = handler.copyImage(srcRef, destRef, policyContext, config)
 	if err != nil {
 		return err
 	}
@@ -323,9 +286,16 @@ func (handler *OciImageHandler) exportImage(image string, config *ImageCacheConf
 	}
 
 	if _, found := availableImages[image]; !found || overwrite {
-		err := handler.pullImage(image, config.Out)
-		if err != nil {
-			return err
+		// fetching the image from its registry only downloads the layers which are not cached yet
+		err := handler.syncImage(image, config, policyContext, out)
+		if err == nil {
+			return nil
+		}
+
+		if !found {
+			if err := handler.pullImage(image, config, policyContext); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -334,6 +304,11 @@ func (handler *OciImageHandler) exportImage(image string, config *ImageCacheConf
 		return fmt.Errorf("Invalid image source '%s': %v", image, err)
 	}
 
+	return handler.copyToCache(image, srcRef, config, policyContext)
+}
+
+// copyToCache copies the image from the specified source into the cache and adds it to the cache index.
+func (handler *OciImageHandler) copyToCache(image string, srcRef types.ImageReference, config *ImageCacheConfig, policyContext *signature.PolicyContext) error {
 	// ImageIndexLocation should be a directory location which will be atomic for each image.
 	// for an image "openshift/origin-control-plane:v3.10.0"
 	// it will be $HOME/.minishift/cache/image/openshift-origin-control-plane-v3.10.0
@@ -349,8 +324,11 @@ func (handler *OciImageHandler) exportImage(image string, config *ImageCacheConf
 		return fmt.Errorf("Invalid image destination '%v': %v", destRef, err)
 	}
 
-	err = handler.copyImage(srcRef, destRef, policyContext, config.HostCacheDir)
+	// layers are stored in the shared blob directory, layers which are cached already or which are written by
+	// a concurrent copy are not transferred again
+	err = handler.copyImage(srcRef, layerDedupReference{ImageReference: destRef, tracker: cacheLayers}, policyContext, config)
 	if err != nil {
+		os.RemoveAll(ImageIndexLocation)
 		return err
 	}
 
@@ -365,6 +343,12 @@ func (handler *OciImageHandler) exportImage(image string, config *ImageCacheConf
 		return err
 	}
 
+	unlock, err := lockIndex(config.HostCacheDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Get index of already available image
 	availableImageIndex, err := handler.getIndex(config.HostCacheDir)
 	if err != nil {
@@ -385,6 +369,12 @@ func (handler *OciImageHandler) exportImage(image string, config *ImageCacheConf
 }
 
 func (handler *OciImageHandler) pruneImage(image string, config *ImageCacheConfig) error {
+	unlock, err := lockIndex(config.HostCacheDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	index, err := handler.getIndex(config.HostCacheDir)
 	if index == nil || err != nil {
 		return err
@@ -404,14 +394,32 @@ func (handler *OciImageHandler) pruneImage(image string, config *ImageCacheConfi
 	return nil
 }
 
-func (handler *OciImageHandler) copyImage(srcRef types.ImageReference, destRef types.ImageReference, policyContext *signature.PolicyContext, cacheDir string) error {
+// lockIndex guards the cache index.json against concurrent updates, both from within this process and from other
+// Minishift processes sharing the same cache directory. The returned function releases the lock.
+func lockIndex(cacheDir string) (func(), error) {
+	indexLock.Lock()
+	fileLock, err := lock.Acquire(filepath.Join(cacheDir, "index.json.lock"), indexLockTimeout)
+	if err != nil {
+		indexLock.Unlock()
+		return nil, fmt.Errorf("Error locking the image cache index: %v", err)
+	}
+
+	return func() {
+		fileLock.Release()
+		indexLock.Unlock()
+	}, nil
+}
+
+func (handler *OciImageHandler) copyImage(srcRef types.ImageReference, destRef types.ImageReference, policyContext *signature.PolicyContext, config *ImageCacheConfig) error {
 	ctx := context.TODO()
+	sourceCtx := handler.getSystemContext(config.HostCacheDir)
+	sourceCtx.DockerInsecureSkipTLSVerify = isInsecureRegistry(srcRef, config.InsecureRegistries)
 	err := copy.Image(ctx, policyContext, destRef, srcRef, &copy.Options{
 		RemoveSignatures: false,
 		SignBy:           "",
 		ReportWriter:     nil,
-		SourceCtx:        handler.getSystemContext(cacheDir),
-		DestinationCtx:   handler.getSystemContext(cacheDir),
+		SourceCtx:        sourceCtx,
+		DestinationCtx:   handler.getSystemContext(config.HostCacheDir),
 	})
 	if err != nil {
 		return err
@@ -431,30 +439,19 @@ func (handler *OciImageHandler) getOutputWriter(config *ImageCacheConfig) io.Wri
 }
 
 func (handler *OciImageHandler) getSystemContext(cacheDir string) *types.SystemContext {
-	return &types.SystemContext{
-		DockerDaemonHost:                  handler.dockerClientSettings.DockerHost,
-		DockerDaemonCertPath:              handler.dockerClientSettings.DockerCertPath,
-		DockerDaemonInsecureSkipTLSVerify: !handler.dockerClientSettings.DockerTLSVerify,
-		OSChoice:                          "linux",
-		ArchitectureChoice:                "amd64",
-		OCIAcceptUncompressedLayers:       true,
-		OCISharedBlobDirPath:              filepath.Join(cacheDir, "blobs"),
-	}
-}
-
-// createSSHSession creates an interactive SSH session
-func (handler *OciImageHandler) createSSHSession() (*ssh.Session, error) {
-	sshClient, err := sshutil.NewSSHClient(handler.driver)
-	if err != nil {
-		return nil, err
-	}
-
-	session, err := sshClient.NewSession()
-	if err != nil {
-		return nil, err
-	}
-
-	return session, nil
+	ctx := &types.SystemContext{
+		OSChoice:                    "linux",
+		ArchitectureChoice:          "amd64",
+		OCIAcceptUncompressedLayers: true,
+		OCISharedBlobDirPath:        filepath.Join(cacheDir, "blobs"),
+	}
+	// a local only handler has no Docker daemon to talk to
+	if handler.dockerClientSettings != nil {
+		ctx.DockerDaemonHost = handler.dockerClientSettings.DockerHost
+		ctx.DockerDaemonCertPath = handler.dockerClientSettings.DockerCertPath
+		ctx.DockerDaemonInsecureSkipTLSVerify = !handler.dockerClientSettings.DockerTLSVerify
+	}
+	return ctx
 }
 
 func (handler *OciImageHandler) getPolicyContext() (*signature.PolicyContext, error) {
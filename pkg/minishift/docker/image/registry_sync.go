@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/docker/reference"
+	containerImage "github.com/containers/image/image"
+	"github.com/containers/image/signature"
+	"github.com/containers/image/transports/alltransports"
+	"github.com/containers/image/types"
+	"github.com/opencontainers/go-digest"
+)
+
+// syncImage copies the image from its registry into the cache. Layers are stored by digest in the blob directory
+// shared by all cached images, so layers which did not change between two versions of an image are not downloaded again.
+func (handler *OciImageHandler) syncImage(image string, config *ImageCacheConfig, policyContext *signature.PolicyContext, out io.Writer) error {
+	srcRef, err := alltransports.ParseImageName(fmt.Sprintf("docker://%s", image))
+	if err != nil {
+		return fmt.Errorf("Invalid image source '%s': %v", image, err)
+	}
+
+	if logger.IsDebug() {
+		layers, err := handler.getLayerDigests(srcRef, config.HostCacheDir)
+		if err == nil {
+			missing := missingBlobs(layers, filepath.Join(config.HostCacheDir, "blobs"))
+			logger.Debugf("Fetching %d of %d layers of '%s'", len(missing), len(layers), image)
+		}
+	}
+
+	return handler.copyToCache(image, srcRef, config, policyContext)
+}
+
+// getLayerDigests returns the digests of the layers referenced by the manifest of the image.
+func (handler *OciImageHandler) getLayerDigests(ref types.ImageReference, cacheDir string) ([]digest.Digest, error) {
+	ctx := context.TODO()
+	sys := handler.getSystemContext(cacheDir)
+	src, err := ref.NewImageSource(ctx, sys)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := containerImage.FromSource(ctx, sys, src)
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+	defer img.Close()
+
+	var layers []digest.Digest
+	for _, layer := range img.LayerInfos() {
+		layers = append(layers, layer.Digest)
+	}
+	return layers, nil
+}
+
+// missingBlobs returns the digests for which no blob exists in the specified OCI blob directory.
+func missingBlobs(digests []digest.Digest, blobDir string) []digest.Digest {
+	var missing []digest.Digest
+	for _, d := range digests {
+		if _, err := os.Stat(filepath.Join(blobDir, d.Algorithm().String(), d.Hex())); err != nil {
+			missing = append(missing, d)
+		}
+	}
+	return missing
+}
+
+// isInsecureRegistry returns true if the registry of the image reference matches one of the insecure registries.
+// Insecure registries are specified either as host[:port] or as CIDR.
+func isInsecureRegistry(ref types.ImageReference, insecureRegistries []string) bool {
+	named := ref.DockerReference()
+	if named == nil {
+		return false
+	}
+
+	domain := reference.Domain(named)
+	host := domain
+	if h, _, err := net.SplitHostPort(domain); err == nil {
+		host = h
+	}
+
+	for _, registry := range insecureRegistries {
+		if registry == domain || registry == host {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(registry); err == nil {
+			if ip := net.ParseIP(host); ip != nil && cidr.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/image/transports/alltransports"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Only_Missing_Blobs_Are_Reported(t *testing.T) {
+	blobDir, err := ioutil.TempDir("", "minishift-test-blobs-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(blobDir)
+
+	cached := digest.FromString("unchanged layer")
+	changed := digest.FromString("changed layer")
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(blobDir, "sha256"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(blobDir, "sha256", cached.Hex()), []byte("unchanged layer"), 0644))
+
+	assert.Equal(t, []digest.Digest{changed}, missingBlobs([]digest.Digest{cached, changed}, blobDir))
+}
+
+func Test_Insecure_Registries_Are_Matched_By_Host_And_CIDR(t *testing.T) {
+	insecureRegistries := []string{"172.30.0.0/16", "registry.local:5000", "myregistry"}
+
+	var registryTests = []struct {
+		image    string
+		insecure bool
+	}{
+		{"docker://172.30.1.1:5000/myproject/app:latest", true},
+		{"docker://registry.local:5000/app:latest", true},
+		{"docker://registry.local/app:latest", false},
+		{"docker://myregistry:443/app:latest", true},
+		{"docker://docker.io/library/alpine:latest", false},
+		{"docker-daemon:alpine:latest", false},
+	}
+
+	for _, registryTest := range registryTests {
+		ref, err := alltransports.ParseImageName(registryTest.image)
+		assert.NoError(t, err)
+		assert.Equal(t, registryTest.insecure, isInsecureRegistry(ref, insecureRegistries), "Unexpected result for '%s'", registryTest.image)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/progressdots"
+)
+
+const (
+	// DefaultWorkers is the number of images which are processed concurrently if not specified otherwise.
+	DefaultWorkers = 4
+)
+
+// imageTask processes a single image and reports the resulting progress status.
+type imageTask func(image string) (ProgressStatus, error)
+
+type imageResult struct {
+	status ProgressStatus
+	err    error
+}
+
+// processImages runs the given task for each of the images using a pool of at most workers goroutines.
+// Duplicate image names are only processed once. When only a single worker is used, the progress of each image is
+// reported using progress dots, otherwise a status line is written as soon as an image is done.
+// It returns the images for which the task succeeded, in the order they were specified, as well as the collected errors.
+func processImages(images []string, workers int, out io.Writer, action string, task imageTask) ([]string, error) {
+	images = uniqueImages(images)
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(images) {
+		workers = len(images)
+	}
+
+	results := make([]imageResult, len(images))
+	if workers <= 1 {
+		for i, image := range images {
+			fmt.Fprint(out, fmt.Sprintf("   %s '%s' ", action, image))
+			progressDots := progressdots.New()
+			progressDots.SetWriter(out)
+			progressDots.Start()
+			status, err := task(image)
+			progressDots.Stop()
+			fmt.Fprintf(out, " %s\n", status.String())
+			results[i] = imageResult{status: status, err: err}
+		}
+	} else {
+		var (
+			wg     sync.WaitGroup
+			outMux sync.Mutex
+			queue  = make(chan int)
+		)
+		fmt.Fprintln(out, fmt.Sprintf("   %s %d images using %d workers", action, len(images), workers))
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range queue {
+					status, err := task(images[i])
+					results[i] = imageResult{status: status, err: err}
+
+					outMux.Lock()
+					fmt.Fprintf(out, "   %s '%s' %s\n", action, images[i], status.String())
+					outMux.Unlock()
+				}
+			}()
+		}
+		for i := range images {
+			queue <- i
+		}
+		close(queue)
+		wg.Wait()
+	}
+
+	processed := []string{}
+	multiError := util.MultiError{}
+	for i, result := range results {
+		multiError.Collect(result.err)
+		if result.err == nil && result.status == OK {
+			processed = append(processed, images[i])
+		}
+	}
+	return processed, multiError.ToError()
+}
+
+// uniqueImages removes duplicate image names, keeping the order of first occurrence.
+func uniqueImages(images []string) []string {
+	seen := make(map[string]bool)
+	unique := []string{}
+	for _, image := range images {
+		if seen[image] {
+			continue
+		}
+		seen[image] = true
+		unique = append(unique, image)
+	}
+	return unique
+}
@@ -0,0 +1,58 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Process_Images_Deduplicates_And_Keeps_Order(t *testing.T) {
+	images := []string{"foo/a:latest", "foo/b:latest", "foo/a:latest", "foo/c:latest", "foo/d:latest"}
+
+	var mux sync.Mutex
+	calls := make(map[string]int)
+	task := func(image string) (ProgressStatus, error) {
+		mux.Lock()
+		calls[image]++
+		mux.Unlock()
+		switch image {
+		case "foo/c:latest":
+			return CACHE_MISS, nil
+		case "foo/d:latest":
+			return FAIL, errors.New("boom")
+		}
+		return OK, nil
+	}
+
+	for _, workers := range []int{0, 1, 3, 10} {
+		calls = make(map[string]int)
+		out := &bytes.Buffer{}
+		processed, err := processImages(images, workers, out, "Importing", task)
+
+		assert.EqualError(t, err, "boom")
+		assert.Equal(t, []string{"foo/a:latest", "foo/b:latest"}, processed)
+		for _, image := range uniqueImages(images) {
+			assert.Equal(t, 1, calls[image], "Image '%s' should be processed exactly once", image)
+			assert.Contains(t, out.String(), image)
+		}
+	}
+}
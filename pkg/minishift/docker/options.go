@@ -0,0 +1,158 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ReservedOptions are the Docker daemon flags managed by Minishift, which cannot be passed via docker-opt.
+	// Switching the storage driver would hide all images and containers stored by the current one.
+	ReservedOptions = []string{"host", "tls", "tlsverify", "tlscacert", "tlscert", "tlskey", "storage-driver"}
+
+	// repeatableOptions are the Docker daemon flags which can be given several times with a plain value
+	repeatableOptions = []string{"add-registry", "authorization-plugin", "block-registry", "dns", "dns-opt",
+		"dns-search", "insecure-registry", "label", "registry-mirror"}
+
+	optionNameRegexp = regexp.MustCompile("^[a-z][a-z0-9-]*$")
+	envNameRegexp    = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+)
+
+// ValidateOption returns an error if the option is not a Docker daemon flag in the form <flag>=<value> or <flag>,
+// or if the flag is managed by Minishift
+func ValidateOption(option string) error {
+	name := strings.SplitN(option, "=", 2)[0]
+	if !optionNameRegexp.MatchString(name) {
+		return fmt.Errorf("Docker option '%s' needs to be in the form <flag>=<value>, without leading dashes", option)
+	}
+	if err := ValidateFlagValue(option); err != nil {
+		return err
+	}
+	for _, reserved := range ReservedOptions {
+		if name == reserved {
+			return fmt.Errorf("Docker option '%s' is managed by Minishift and cannot be set", name)
+		}
+	}
+	return nil
+}
+
+// ValidateStorageOption returns an error if the storage driver option is not in the form <key>=<value>
+func ValidateStorageOption(option string) error {
+	parts := strings.SplitN(option, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("Docker storage option '%s' needs to be in the form <key>=<value>", option)
+	}
+	return ValidateFlagValue(option)
+}
+
+// ValidateFlagValue returns an error if the value contains whitespace. systemd splits the flags rendered into the
+// drop-in at whitespace, so such a value would break the command line of the Docker daemon.
+func ValidateFlagValue(value string) error {
+	if strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("'%s' must not contain whitespace", value)
+	}
+	return nil
+}
+
+// MergeOptions adds the options to the existing ones. An added option replaces an existing option with the same key,
+// which is the flag name or, for values in the form <key>=<value> like 'log-opt=max-size=10m', the flag name together
+// with the key of the value. Options of flags which can be given several times are only added once.
+func MergeOptions(existing []string, added []string) []string {
+	merged := append([]string{}, existing...)
+	for _, option := range added {
+		replaced := false
+		for i, current := range merged {
+			if optionKey(current) == optionKey(option) {
+				merged[i] = option
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, option)
+		}
+	}
+	return merged
+}
+
+// RemoveOptions removes the options matching the key from the existing ones. The key is either the key of an option
+// as described for MergeOptions, like 'log-opt=max-size', or a flag name, like 'log-opt', which removes all options of
+// the flag. It returns the remaining options and whether any option was removed.
+func RemoveOptions(existing []string, key string) ([]string, bool) {
+	var remaining []string
+	for _, option := range existing {
+		if optionKey(option) == key || strings.SplitN(option, "=", 2)[0] == key {
+			continue
+		}
+		remaining = append(remaining, option)
+	}
+	return remaining, len(remaining) != len(existing)
+}
+
+func optionKey(option string) string {
+	parts := strings.SplitN(option, "=", 3)
+	for _, repeatable := range repeatableOptions {
+		if parts[0] == repeatable {
+			return option
+		}
+	}
+	if len(parts) == 3 {
+		return parts[0] + "=" + parts[1]
+	}
+	return parts[0]
+}
+
+// ReadEnvFile reads the environment variables of the Docker daemon from a file in the format of 'docker run
+// --env-file'. Each line is either <key>=<value> or just <key>, which takes the value from the environment of
+// Minishift. Empty lines and lines starting with '#' are ignored.
+func ReadEnvFile(path string) ([]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var env []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if !envNameRegexp.MatchString(parts[0]) {
+			return nil, fmt.Errorf("Invalid variable name '%s' in line %d of '%s'", parts[0], lineNumber, path)
+		}
+		if len(parts) == 1 {
+			value, ok := os.LookupEnv(parts[0])
+			if !ok {
+				continue
+			}
+			line = parts[0] + "=" + value
+		}
+		env = append(env, line)
+	}
+	return env, scanner.Err()
+}
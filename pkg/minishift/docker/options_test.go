@@ -0,0 +1,96 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOption(t *testing.T) {
+	for _, option := range []string{"log-driver=json-file", "default-ulimit=nofile=1024:2048", "live-restore", "log-opt=max-size=10m"} {
+		assert.NoError(t, ValidateOption(option), option)
+	}
+
+	for _, option := range []string{"--log-driver=json-file", "", "=foo", "Log-Driver=journald", "host=tcp://0.0.0.0:2375", "tlsverify=false", "storage-driver=devicemapper", "label=team=payments and billing"} {
+		assert.Error(t, ValidateOption(option), option)
+	}
+}
+
+func TestRemoveOptions(t *testing.T) {
+	existing := []string{"log-driver=json-file", "log-opt=max-size=10m", "log-opt=max-file=3", "dns=8.8.8.8", "dns=8.8.4.4"}
+
+	remaining, removed := RemoveOptions(existing, "log-opt=max-size")
+	assert.True(t, removed)
+	assert.Equal(t, []string{"log-driver=json-file", "log-opt=max-file=3", "dns=8.8.8.8", "dns=8.8.4.4"}, remaining)
+
+	remaining, removed = RemoveOptions(existing, "dns=8.8.4.4")
+	assert.True(t, removed)
+	assert.Equal(t, []string{"log-driver=json-file", "log-opt=max-size=10m", "log-opt=max-file=3", "dns=8.8.8.8"}, remaining)
+
+	remaining, removed = RemoveOptions(existing, "log-opt")
+	assert.True(t, removed)
+	assert.Equal(t, []string{"log-driver=json-file", "dns=8.8.8.8", "dns=8.8.4.4"}, remaining)
+
+	_, removed = RemoveOptions(existing, "live-restore")
+	assert.False(t, removed)
+}
+
+func TestValidateStorageOption(t *testing.T) {
+	assert.NoError(t, ValidateStorageOption("dm.basesize=20G"))
+
+	for _, option := range []string{"dm.basesize", "=20G", "dm.basesize=", "dm.basesize=20 G"} {
+		assert.Error(t, ValidateStorageOption(option), option)
+	}
+}
+
+func TestMergeOptions(t *testing.T) {
+	existing := []string{"log-driver=journald", "log-opt=max-size=10m", "dns=8.8.8.8"}
+	added := []string{"log-driver=json-file", "log-opt=max-file=3", "log-opt=max-size=20m", "dns=8.8.4.4", "dns=8.8.8.8", "live-restore"}
+
+	expected := []string{"log-driver=json-file", "log-opt=max-size=20m", "dns=8.8.8.8", "log-opt=max-file=3", "dns=8.8.4.4", "live-restore"}
+	assert.Equal(t, expected, MergeOptions(existing, added))
+	assert.Equal(t, []string{"log-driver=journald", "log-opt=max-size=10m", "dns=8.8.8.8"}, existing)
+}
+
+func TestReadEnvFile(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-docker-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	os.Setenv("MINISHIFT_TEST_DOCKER_VAR", "from-host")
+	defer os.Unsetenv("MINISHIFT_TEST_DOCKER_VAR")
+
+	path := filepath.Join(testDir, "docker.env")
+	content := "# proxy settings\nHTTP_PROXY=http://proxy.foo.com:3128\n\n  NO_PROXY=localhost,127.0.0.1  \nMINISHIFT_TEST_DOCKER_VAR\nMINISHIFT_TEST_UNSET_VAR\n"
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+
+	env, err := ReadEnvFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"HTTP_PROXY=http://proxy.foo.com:3128", "NO_PROXY=localhost,127.0.0.1", "MINISHIFT_TEST_DOCKER_VAR=from-host"}, env)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("FOO=bar\nINVALID-NAME=baz\n"), 0644))
+	_, err = ReadEnvFile(path)
+	assert.EqualError(t, err, "Invalid variable name 'INVALID-NAME' in line 2 of '"+path+"'")
+
+	_, err = ReadEnvFile(filepath.Join(testDir, "missing.env"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,448 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesync
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"golang.org/x/crypto/ssh"
+)
+
+// batchSize limits the number of files passed as arguments to a single command in the VM
+const batchSize = 200
+
+// Options controls which files are synchronized between the host and the VM.
+type Options struct {
+	// PullPaths are the paths relative to the synchronized directory which are owned by the VM and copied back to the host
+	PullPaths []string
+	// Excludes are patterns of files and directories which are not synchronized
+	Excludes []string
+}
+
+// Syncer synchronizes a directory of the host with a directory in the VM. Changes on the host are pushed into the VM,
+// whereas changes within the pull paths are pulled back from the VM to the host.
+type Syncer struct {
+	client  *ssh.Client
+	source  string
+	target  string
+	options Options
+	// pulled holds the state of the files within the pull paths which were last known to be in sync
+	pulled manifest
+	// conflicts holds the state in the VM of the files which were not pulled due to a conflict
+	conflicts manifest
+}
+
+// fileState is the state of a file used to detect changes. Modification times are kept in seconds, since this is
+// the precision tar archives preserve.
+type fileState struct {
+	size    int64
+	modTime int64
+}
+
+// manifest maps the slash separated path of each file relative to the synchronized directory to its state
+type manifest map[string]fileState
+
+// New creates a Syncer synchronizing the source directory of the host with the target directory in the VM using
+// the specified SSH client of the VM.
+func New(client *ssh.Client, source string, target string, options Options) *Syncer {
+	return &Syncer{client: client, source: source, target: target, options: options, pulled: manifest{}, conflicts: manifest{}}
+}
+
+// Push copies the files added or changed on the host into the VM and removes the files deleted on the host from the
+// VM. Files within the pull paths are left alone. The number of copied files is returned.
+func (s *Syncer) Push() (int, error) {
+	local, err := s.localManifest(s.isPushed)
+	if err != nil {
+		return 0, err
+	}
+	remote, err := s.remoteManifest(".")
+	if err != nil {
+		return 0, err
+	}
+
+	changed, removed := diff(local, remote, s.isPushed)
+	for _, batch := range batches(removed) {
+		cmd := fmt.Sprintf("cd %s && sudo rm -f -- %s", sshutil.Quote(s.target), quoteAll(batch))
+		if err := sshutil.RunCommand(s.client, cmd); err != nil {
+			return 0, fmt.Errorf("Error removing files from '%s': %v", s.target, err)
+		}
+	}
+	if len(changed) > 0 {
+		if err := s.upload(changed); err != nil {
+			return 0, err
+		}
+	}
+	return len(changed), nil
+}
+
+// Pull copies the files within the pull paths which were added or changed in the VM to the host. Files are never
+// removed from the host. Files which were changed on the host as well are not overwritten, but reported as conflict.
+// The number of copied files is returned.
+func (s *Syncer) Pull() (int, error) {
+	if len(s.options.PullPaths) == 0 {
+		return 0, nil
+	}
+
+	remote, err := s.remoteManifest(s.options.PullPaths...)
+	if err != nil {
+		return 0, err
+	}
+	local, err := s.localManifest(s.isPulled)
+	if err != nil {
+		return 0, err
+	}
+
+	changed, conflicts := s.pullable(remote, local)
+	for _, rel := range conflicts {
+		if s.conflicts[rel] != remote[rel] {
+			logger.Errorf("Not pulling '%s', since it was changed on the host and in the VM", rel)
+			s.conflicts[rel] = remote[rel]
+		}
+	}
+	for _, batch := range batches(changed) {
+		if err := s.download(batch); err != nil {
+			return 0, err
+		}
+	}
+	for _, rel := range changed {
+		s.pulled[rel] = remote[rel]
+		delete(s.conflicts, rel)
+	}
+	return len(changed), nil
+}
+
+// pullable splits the files of the remote manifest which differ on the host into the files which can be pulled and
+// the files which were changed on the host as well. A file counts as changed on the host if it differs from the state
+// it was last pulled in or, if it was not pulled yet, if the host version is more recent than the one in the VM.
+func (s *Syncer) pullable(remote manifest, local manifest) ([]string, []string) {
+	changed, _ := diff(remote, local, s.isPulled)
+
+	var pullable, conflicts []string
+	for _, rel := range changed {
+		localState, exists := local[rel]
+		if !exists {
+			pullable = append(pullable, rel)
+			continue
+		}
+
+		pulledState, pulled := s.pulled[rel]
+		if (pulled && localState != pulledState) || (!pulled && localState.modTime > remote[rel].modTime) {
+			conflicts = append(conflicts, rel)
+		} else {
+			pullable = append(pullable, rel)
+		}
+	}
+	for rel, state := range remote {
+		if local[rel] == state {
+			s.pulled[rel] = state
+		}
+	}
+	return pullable, conflicts
+}
+
+// isPushed returns true if the file with the specified relative path is copied from the host into the VM
+func (s *Syncer) isPushed(rel string) bool {
+	return !matches(rel, s.options.Excludes) && !isBelow(rel, s.options.PullPaths)
+}
+
+// isPulled returns true if the file with the specified relative path is copied from the VM back to the host
+func (s *Syncer) isPulled(rel string) bool {
+	return !matches(rel, s.options.Excludes) && isBelow(rel, s.options.PullPaths)
+}
+
+func (s *Syncer) localManifest(include func(rel string) bool) (manifest, error) {
+	files := manifest{}
+	err := filepath.Walk(s.source, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.source, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if rel != "." && matches(rel, s.options.Excludes) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() && include(rel) {
+			files[rel] = fileState{size: info.Size(), modTime: info.ModTime().Unix()}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// remoteManifest lists the files below the specified paths relative to the target directory in the VM. Paths which
+// do not exist are skipped.
+func (s *Syncer) remoteManifest(paths ...string) (manifest, error) {
+	var startingPoints []string
+	for _, p := range paths {
+		// a leading './' keeps find from taking paths starting with '-' as options
+		startingPoint := path.Join(".", strings.Trim(p, "/"))
+		if startingPoint != "." {
+			startingPoint = "./" + startingPoint
+		}
+		startingPoints = append(startingPoints, sshutil.Quote(startingPoint))
+	}
+
+	cmd := fmt.Sprintf("if [ -d %s ]; then cd %s && sudo find %s -type f -printf '%%p\\t%%s\\t%%T@\\n' 2> /dev/null; fi; true",
+		sshutil.Quote(s.target),
+		sshutil.Quote(s.target),
+		strings.Join(startingPoints, " "))
+	out, err := sshutil.RunCommandWithOutput(s.client, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the files of '%s': %v", s.target, err)
+	}
+	return parseManifest(out)
+}
+
+// upload streams the specified files as tar archive into the VM, which keeps modification time and permissions
+func (s *Syncer) upload(files []string) error {
+	session, err := s.client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	reader, writer := io.Pipe()
+	session.Stdin = reader
+	go func() {
+		writer.CloseWithError(s.writeArchive(writer, files))
+	}()
+
+	cmd := fmt.Sprintf("sudo mkdir -p %s && sudo tar -x --no-same-owner -C %s", sshutil.Quote(s.target), sshutil.Quote(s.target))
+	if out, err := session.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("Error copying files to '%s': %v %s", s.target, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s *Syncer) writeArchive(w io.Writer, files []string) error {
+	archive := tar.NewWriter(w)
+	for _, rel := range files {
+		if err := addToArchive(archive, filepath.Join(s.source, filepath.FromSlash(rel)), rel); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// download extracts a tar archive of the specified files created in the VM into the source directory
+func (s *Syncer) download(files []string) error {
+	session, err := s.client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	out, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("cd %s && sudo tar -c -f - -- %s", sshutil.Quote(s.target), quoteAll(files))
+	if err := session.Start(cmd); err != nil {
+		return err
+	}
+
+	extractErr := s.extractArchive(out)
+	// drain the archive, so that tar in the VM can finish in case extracting failed
+	io.Copy(ioutil.Discard, out)
+	if err := session.Wait(); err != nil {
+		return fmt.Errorf("Error copying files from '%s': %v", s.target, err)
+	}
+	return extractErr
+}
+
+func (s *Syncer) extractArchive(r io.Reader) error {
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rel := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if header.Typeflag != tar.TypeReg || !s.isPulled(rel) {
+			continue
+		}
+		if err := extractFile(archive, header, filepath.Join(s.source, filepath.FromSlash(rel))); err != nil {
+			return err
+		}
+	}
+}
+
+func addToArchive(archive *tar.Writer, file string, name string) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		// the file was removed since the manifest was created and gets removed from the VM with the next push
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.ModTime = info.ModTime().Truncate(time.Second)
+
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.CopyN(archive, f, header.Size)
+	return err
+}
+
+func extractFile(r io.Reader, header *tar.Header, file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, header.FileInfo().Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(file, header.ModTime, header.ModTime)
+}
+
+// diff returns the paths of the files of the source manifest which are missing or differ in the target manifest,
+// as well as the paths of the files of the target manifest selected by include which are missing in the source.
+func diff(source manifest, target manifest, include func(rel string) bool) ([]string, []string) {
+	var changed, removed []string
+	for rel, state := range source {
+		if targetState, ok := target[rel]; !ok || targetState != state {
+			changed = append(changed, rel)
+		}
+	}
+	for rel := range target {
+		if _, ok := source[rel]; !ok && include(rel) {
+			removed = append(removed, rel)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return changed, removed
+}
+
+// parseManifest parses the output of 'find -printf "%p\t%s\t%T@\n"'
+func parseManifest(out string) (manifest, error) {
+	files := manifest{}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("Unexpected file listing '%s'", line)
+		}
+
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		modTime, err := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+		if err != nil {
+			return nil, err
+		}
+		files[strings.TrimPrefix(fields[0], "./")] = fileState{size: size, modTime: int64(modTime)}
+	}
+	return files, nil
+}
+
+// matches returns true if one of the patterns matches the relative path or one of its elements. A pattern without
+// wildcards also matches everything below it.
+func matches(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.Trim(pattern, "/")
+		if pattern == "" {
+			continue
+		}
+		if rel == pattern || strings.HasPrefix(rel, pattern+"/") {
+			return true
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		for _, element := range strings.Split(rel, "/") {
+			if ok, _ := path.Match(pattern, element); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isBelow returns true if the relative path is one of the specified paths or below one of them
+func isBelow(rel string, paths []string) bool {
+	for _, p := range paths {
+		p = strings.Trim(p, "/")
+		if p != "" && (rel == p || strings.HasPrefix(rel, p+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func batches(files []string) [][]string {
+	var result [][]string
+	for len(files) > batchSize {
+		result = append(result, files[:batchSize])
+		files = files[batchSize:]
+	}
+	if len(files) > 0 {
+		result = append(result, files)
+	}
+	return result
+}
+
+func quoteAll(files []string) string {
+	var quoted []string
+	for _, file := range files {
+		quoted = append(quoted, sshutil.Quote(file))
+	}
+	return strings.Join(quoted, " ")
+}
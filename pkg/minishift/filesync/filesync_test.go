@@ -0,0 +1,207 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesync
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/minishift/minishift/pkg/minikube/tests"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	testModTime = time.Unix(1500000000, 0)
+	testOptions = Options{PullPaths: []string{"build"}, Excludes: []string{".git", "*.swp"}}
+)
+
+func TestPush(t *testing.T) {
+	testDir := createTestDir(t, map[string]string{
+		"src/main.go":     "package main",
+		"src/unchanged":   "same",
+		"src/.main.swp":   "swap",
+		".git/HEAD":       "ref",
+		"build/output.js": "local output",
+	})
+	defer os.RemoveAll(testDir)
+
+	server, err := tests.NewSSHServer()
+	assert.NoError(t, err, "Error creating ssh server")
+	server.CommandToOutput = map[string]string{
+		manifestCmd("/home/docker/project", "'.'"): fmt.Sprintf("./src/unchanged\t4\t%d.0000000000\n./src/deleted\t1\t1.0\n./build/output.js\t9\t1.0\n", testModTime.Unix()),
+	}
+	syncer := New(newSSHClient(t, server), testDir, "/home/docker/project", testOptions)
+
+	count, err := syncer.Push()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, ok := server.Commands["cd '/home/docker/project' && sudo rm -f -- 'src/deleted'"]
+	assert.True(t, ok, "Expected file deleted on the host to be removed from the VM")
+	_, ok = server.Commands["sudo mkdir -p '/home/docker/project' && sudo tar -x --no-same-owner -C '/home/docker/project'"]
+	assert.True(t, ok, "Expected changed files to be extracted in the VM")
+
+	files := readArchive(t, server.Transfers)
+	assert.Equal(t, map[string]string{"src/main.go": "package main"}, files)
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	sourceDir := createTestDir(t, map[string]string{"build/app.js": "app", "src/main.go": "package main"})
+	defer os.RemoveAll(sourceDir)
+	targetDir, err := ioutil.TempDir("", "minishift-test-filesync-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(targetDir)
+
+	var archive bytes.Buffer
+	source := New(nil, sourceDir, "/home/docker/project", Options{})
+	assert.NoError(t, source.writeArchive(&archive, []string{"build/app.js", "src/main.go", "src/removed"}))
+
+	// only the pull paths are extracted on the host
+	target := New(nil, targetDir, "/home/docker/project", testOptions)
+	assert.NoError(t, target.extractArchive(&archive))
+
+	content, err := ioutil.ReadFile(filepath.Join(targetDir, "build", "app.js"))
+	assert.NoError(t, err)
+	assert.Equal(t, "app", string(content))
+	_, err = os.Stat(filepath.Join(targetDir, "src", "main.go"))
+	assert.True(t, os.IsNotExist(err), "Expected files outside of the pull paths not to be extracted")
+
+	local, err := target.localManifest(target.isPulled)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest{"build/app.js": {size: 3, modTime: testModTime.Unix()}}, local, "Expected modification time to be kept")
+}
+
+func TestDiff(t *testing.T) {
+	source := manifest{"a": {1, 10}, "b": {2, 20}, "c": {3, 30}}
+	target := manifest{"a": {1, 10}, "b": {2, 21}, "d": {4, 40}, "build/e": {5, 50}}
+
+	changed, removed := diff(source, target, func(rel string) bool { return !isBelow(rel, []string{"build"}) })
+	assert.Equal(t, []string{"b", "c"}, changed)
+	assert.Equal(t, []string{"d"}, removed)
+}
+
+func TestPullableDetectsConflicts(t *testing.T) {
+	syncer := New(nil, "/home/john/project", "/home/docker/project", testOptions)
+	syncer.pulled = manifest{"build/pulled": {1, 10}, "build/edited": {1, 10}}
+
+	remote := manifest{
+		"build/new":       {1, 20},
+		"build/pulled":    {2, 20},
+		"build/edited":    {2, 20},
+		"build/older":     {1, 20},
+		"build/newer":     {1, 20},
+		"build/unchanged": {1, 20},
+	}
+	local := manifest{
+		"build/pulled":    {1, 10},
+		"build/edited":    {3, 15},
+		"build/older":     {2, 15},
+		"build/newer":     {2, 25},
+		"build/unchanged": {1, 20},
+	}
+
+	pullable, conflicts := syncer.pullable(remote, local)
+	assert.Equal(t, []string{"build/new", "build/older", "build/pulled"}, pullable)
+	assert.Equal(t, []string{"build/edited", "build/newer"}, conflicts)
+	assert.Equal(t, fileState{1, 20}, syncer.pulled["build/unchanged"], "Expected files in sync to be remembered as pulled")
+}
+
+func TestParseManifest(t *testing.T) {
+	files, err := parseManifest("./src/main.go\t12\t1500000000.1234567890\n./build/app.js\t0\t1.0\n")
+	assert.NoError(t, err)
+	assert.Equal(t, manifest{"src/main.go": {12, 1500000000}, "build/app.js": {0, 1}}, files)
+
+	_, err = parseManifest("garbage\n")
+	assert.Error(t, err, "Expected error for unexpected file listing")
+}
+
+func TestMatches(t *testing.T) {
+	patterns := []string{".git", "*.swp", "node_modules/", "docs/*.pdf"}
+
+	assert.True(t, matches(".git/HEAD", patterns))
+	assert.True(t, matches("src/.main.swp", patterns))
+	assert.True(t, matches("web/node_modules/lib/index.js", patterns))
+	assert.True(t, matches("docs/manual.pdf", patterns))
+	assert.False(t, matches("src/main.go", patterns))
+	assert.False(t, matches(".gitignore", patterns))
+
+	assert.True(t, isBelow("build/app.js", []string{"/build/"}))
+	assert.False(t, isBelow("buildtools/app.js", []string{"build"}))
+}
+
+func TestBatches(t *testing.T) {
+	files := make([]string, batchSize*2+1)
+	result := batches(files)
+	assert.Len(t, result, 3)
+	assert.Len(t, result[2], 1)
+	assert.Empty(t, batches(nil))
+}
+
+func manifestCmd(target string, startingPoints string) string {
+	return fmt.Sprintf("if [ -d '%s' ]; then cd '%s' && sudo find %s -type f -printf '%%p\\t%%s\\t%%T@\\n' 2> /dev/null; fi; true", target, target, startingPoints)
+}
+
+func createTestDir(t *testing.T, files map[string]string) string {
+	testDir, err := ioutil.TempDir("", "minishift-test-filesync-")
+	assert.NoError(t, err)
+
+	for rel, content := range files {
+		file := filepath.Join(testDir, filepath.FromSlash(rel))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+		assert.NoError(t, os.Chtimes(file, testModTime, testModTime))
+	}
+	return testDir
+}
+
+func readArchive(t *testing.T, r *bytes.Buffer) map[string]string {
+	files := map[string]string{}
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err != nil {
+			break
+		}
+		content, err := ioutil.ReadAll(archive)
+		assert.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+	return files
+}
+
+func newSSHClient(t *testing.T, server *tests.SSHServer) *ssh.Client {
+	port, err := server.Start()
+	assert.NoError(t, err, "Error starting ssh server")
+	driver := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress: "127.0.0.1",
+		},
+	}
+	client, err := sshutil.NewSSHClient(driver)
+	assert.NoError(t, err, "Error creating ssh client")
+	return client
+}
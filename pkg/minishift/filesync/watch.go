@@ -0,0 +1,104 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesync
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/minishift/minishift/pkg/util/logging"
+)
+
+var logger = logging.For("filesync")
+
+// settleTime is the time without further changes on the host after which the changes are pushed into the VM
+const settleTime = 500 * time.Millisecond
+
+// Watch pushes changes on the host into the VM as they happen and pulls the pull paths back from the VM in the
+// specified interval. Watch blocks until the stop channel is closed or synchronizing fails, for example because the
+// connection to the VM was lost.
+func (s *Syncer) Watch(pullInterval time.Duration, stop <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := s.watchDirs(watcher, s.source); err != nil {
+		return err
+	}
+
+	pullTicker := time.NewTicker(pullInterval)
+	defer pullTicker.Stop()
+
+	var push <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event := <-watcher.Events:
+			rel, err := filepath.Rel(s.source, event.Name)
+			if err != nil || !s.isPushed(filepath.ToSlash(rel)) {
+				continue
+			}
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := s.watchDirs(watcher, event.Name); err != nil {
+						logger.Errorf("Error watching '%s': %v", event.Name, err)
+					}
+				}
+			}
+			push = time.After(settleTime)
+		case err := <-watcher.Errors:
+			logger.Errorf("Error watching '%s': %v", s.source, err)
+		case <-push:
+			push = nil
+			count, err := s.Push()
+			if err != nil {
+				return err
+			}
+			logger.Infof("Pushed %d files of '%s'", count, s.source)
+		case <-pullTicker.C:
+			count, err := s.Pull()
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				logger.Infof("Pulled %d files into '%s'", count, s.source)
+			}
+		}
+	}
+}
+
+// watchDirs adds the directory and all directories below it which are pushed into the VM to the watcher
+func (s *Syncer) watchDirs(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(s.source, file)
+		if err != nil {
+			return err
+		}
+		if rel != "." && !s.isPushed(filepath.ToSlash(rel)) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(file)
+	})
+}
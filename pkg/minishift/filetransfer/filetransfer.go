@@ -0,0 +1,274 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filetransfer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minishift/minishift/pkg/minikube/assets"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"golang.org/x/crypto/ssh"
+	pb "gopkg.in/cheggaaa/pb.v1"
+)
+
+// Options controls how files are copied between the host and the VM.
+type Options struct {
+	// Recursive allows to copy directories
+	Recursive bool
+	// Progress shows a progress bar for each copied file
+	Progress bool
+	// Out receives the name of each copied file, if set
+	Out io.Writer
+}
+
+// FileTransfer copies files and directories between the host and the VM. The checksum of each copied file is
+// verified and the permissions of the file are kept.
+type FileTransfer struct {
+	client  *ssh.Client
+	options Options
+}
+
+// New creates a FileTransfer using the specified SSH client of the VM.
+func New(client *ssh.Client, options Options) *FileTransfer {
+	return &FileTransfer{client: client, options: options}
+}
+
+// ToVM copies the local file or directory to the specified path on the VM. If the remote path is an existing
+// directory, the file or directory is copied into it. The number of copied files is returned.
+func (t *FileTransfer) ToVM(localPath string, remotePath string) (int, error) {
+	fileInfo, err := os.Stat(localPath)
+	if err != nil {
+		return 0, err
+	}
+	if fileInfo.IsDir() && !t.options.Recursive {
+		return 0, fmt.Errorf("'%s' is a directory. Use --recursive to copy directories", localPath)
+	}
+
+	target := remotePath
+	if strings.HasSuffix(remotePath, "/") || sshutil.IsRemoteDir(t.client, remotePath) {
+		target = path.Join(remotePath, filepath.Base(localPath))
+	}
+
+	if !fileInfo.IsDir() {
+		return 1, t.upload(localPath, fileInfo, target)
+	}
+
+	count := 0
+	err = filepath.Walk(localPath, func(file string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(localPath, file)
+		if err != nil {
+			return err
+		}
+		if err := t.upload(file, info, path.Join(target, filepath.ToSlash(rel))); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// FromVM copies the file or directory with the specified path on the VM to the local path. If the local path is an
+// existing directory, the file or directory is copied into it. The number of copied files is returned.
+func (t *FileTransfer) FromVM(remotePath string, localPath string) (int, error) {
+	isDir := sshutil.IsRemoteDir(t.client, remotePath)
+	if isDir && !t.options.Recursive {
+		return 0, fmt.Errorf("'%s' is a directory. Use --recursive to copy directories", remotePath)
+	}
+
+	target := localPath
+	if fileInfo, err := os.Stat(localPath); err == nil && fileInfo.IsDir() {
+		target = filepath.Join(localPath, path.Base(remotePath))
+	}
+
+	if !isDir {
+		return 1, t.download(remotePath, target)
+	}
+
+	out, err := sshutil.RunCommandWithOutput(t.client, fmt.Sprintf("sudo find %s -type f", sshutil.Quote(remotePath)))
+	if err != nil {
+		return 0, fmt.Errorf("Error listing the files of '%s': %v", remotePath, err)
+	}
+
+	count := 0
+	dir := strings.TrimSuffix(remotePath, "/") + "/"
+	for _, file := range strings.Split(strings.TrimSpace(out), "\n") {
+		if file == "" {
+			continue
+		}
+		rel := strings.TrimPrefix(file, dir)
+		if err := t.download(file, filepath.Join(target, filepath.FromSlash(rel))); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (t *FileTransfer) upload(localPath string, fileInfo os.FileInfo, remotePath string) error {
+	t.printCopying(localPath, remotePath)
+
+	checksum, err := fileChecksum(localPath)
+	if err != nil {
+		return err
+	}
+
+	asset, err := assets.NewFileAsset(localPath, path.Dir(remotePath), path.Base(remotePath), fmt.Sprintf("%04o", fileInfo.Mode().Perm()))
+	if err != nil {
+		return err
+	}
+
+	var file assets.CopyableFile = asset
+	if t.options.Progress && asset.GetLength() > 0 {
+		bar := t.startProgressBar(asset.GetLength())
+		file = &progressFile{CopyableFile: asset, reader: bar.NewProxyReader(asset)}
+		defer t.finishProgressBar(bar)
+	}
+
+	if err := sshutil.TransferFile(file, t.client); err != nil {
+		return err
+	}
+
+	return t.verifyRemoteChecksum(remotePath, checksum)
+}
+
+func (t *FileTransfer) download(remotePath string, localPath string) error {
+	t.printCopying(remotePath, localPath)
+
+	out, err := sshutil.RunCommandWithOutput(t.client, fmt.Sprintf("sudo stat -c '%%a %%s' %s", sshutil.Quote(remotePath)))
+	if err != nil {
+		return fmt.Errorf("Error reading the file mode of '%s': %v", remotePath, err)
+	}
+	mode, size, err := parseStat(out)
+	if err != nil {
+		return fmt.Errorf("Error reading the file mode of '%s': %v", remotePath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// the mode passed to OpenFile is subject to the umask and not applied to existing files
+	if err := file.Chmod(mode); err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	writers := []io.Writer{file, hash}
+	if t.options.Progress && size > 0 {
+		bar := t.startProgressBar(size)
+		writers = append(writers, bar)
+		defer t.finishProgressBar(bar)
+	}
+
+	if err := sshutil.Download(t.client, remotePath, io.MultiWriter(writers...)); err != nil {
+		return err
+	}
+
+	return t.verifyRemoteChecksum(remotePath, hex.EncodeToString(hash.Sum(nil)))
+}
+
+func (t *FileTransfer) verifyRemoteChecksum(remotePath string, expected string) error {
+	out, err := sshutil.RunCommandWithOutput(t.client, fmt.Sprintf("sudo sha256sum %s", sshutil.Quote(remotePath)))
+	if err != nil {
+		return fmt.Errorf("Error computing the checksum of '%s': %v", remotePath, err)
+	}
+
+	fields := strings.Fields(out)
+	if len(fields) == 0 || fields[0] != expected {
+		return fmt.Errorf("Checksum mismatch for '%s'", remotePath)
+	}
+	return nil
+}
+
+func (t *FileTransfer) printCopying(source string, target string) {
+	if t.options.Out != nil {
+		fmt.Fprintln(t.options.Out, fmt.Sprintf("Copying '%s' to '%s'", source, target))
+	}
+}
+
+func (t *FileTransfer) startProgressBar(size int64) *pb.ProgressBar {
+	bar := pb.New64(size).SetUnits(pb.U_BYTES)
+	if t.options.Out != nil {
+		bar.Output = t.options.Out
+	}
+	bar.Start()
+	return bar
+}
+
+func (t *FileTransfer) finishProgressBar(bar *pb.ProgressBar) {
+	<-time.After(bar.RefreshRate)
+	bar.Finish()
+}
+
+// progressFile is a CopyableFile reporting the progress of reading the wrapped file
+type progressFile struct {
+	assets.CopyableFile
+	reader io.Reader
+}
+
+func (f *progressFile) Read(p []byte) (int, error) {
+	return f.reader.Read(p)
+}
+
+// parseStat parses the output of 'stat -c "%a %s"' into file mode and size
+func parseStat(out string) (os.FileMode, int64, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("Unexpected output '%s'", strings.TrimSpace(out))
+	}
+
+	mode, err := strconv.ParseUint(fields[0], 8, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return os.FileMode(mode).Perm(), size, nil
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
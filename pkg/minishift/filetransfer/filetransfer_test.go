@@ -0,0 +1,144 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filetransfer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/minishift/minishift/pkg/minikube/tests"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	testContent  = []byte("testcontents")
+	testChecksum = checksumOf(testContent)
+)
+
+func TestToVM(t *testing.T) {
+	testDir, localFile := createTestFile(t)
+	defer os.RemoveAll(testDir)
+
+	server := startSSHServer(t)
+	server.CommandToExitStatus = map[string]int{
+		"sudo test -d '/home/docker/foo.txt'": 1,
+		"sudo test -d '/home/docker/bar.txt'": 1,
+	}
+	server.CommandToOutput = map[string]string{
+		"sudo sha256sum '/home/docker/foo.txt'": testChecksum + "  /home/docker/foo.txt\n",
+		"sudo sha256sum '/home/docker/bar.txt'": checksumOf([]byte("other")) + "  /home/docker/bar.txt\n",
+	}
+	transfer := New(newSSHClient(t, server), Options{})
+
+	count, err := transfer.ToVM(localFile, "/home/docker/foo.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Contains(t, server.Transfers.String(), string(testContent))
+	_, ok := server.Commands["sudo scp -t '/home/docker'"]
+	assert.True(t, ok, "Expected file to be transferred to '/home/docker'")
+
+	_, err = transfer.ToVM(localFile, "/home/docker/bar.txt")
+	assert.EqualError(t, err, "Checksum mismatch for '/home/docker/bar.txt'")
+
+	_, err = transfer.ToVM(testDir, "/home/docker/foo.txt")
+	assert.Error(t, err, "Expected error copying a directory without recursive option")
+}
+
+func TestFromVM(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-filetransfer-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	server := startSSHServer(t)
+	server.CommandToExitStatus = map[string]int{
+		"sudo test -d '/home/docker/foo.txt'": 1,
+	}
+	server.CommandToOutput = map[string]string{
+		"sudo stat -c '%a %s' '/home/docker/foo.txt'": "640 12\n",
+		"sudo cat '/home/docker/foo.txt'":             string(testContent),
+		"sudo sha256sum '/home/docker/foo.txt'":       testChecksum + "  /home/docker/foo.txt\n",
+	}
+	transfer := New(newSSHClient(t, server), Options{})
+
+	count, err := transfer.FromVM("/home/docker/foo.txt", testDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	localFile := filepath.Join(testDir, "foo.txt")
+	content, err := ioutil.ReadFile(localFile)
+	assert.NoError(t, err)
+	assert.Equal(t, testContent, content)
+
+	if runtime.GOOS != "windows" {
+		fileInfo, err := os.Stat(localFile)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0640), fileInfo.Mode().Perm())
+	}
+}
+
+func TestParseStat(t *testing.T) {
+	mode, size, err := parseStat("755 1024\n")
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), mode)
+	assert.Equal(t, int64(1024), size)
+
+	_, _, err = parseStat("stat: cannot stat")
+	assert.Error(t, err)
+}
+
+func createTestFile(t *testing.T) (string, string) {
+	testDir, err := ioutil.TempDir("", "minishift-test-filetransfer-")
+	assert.NoError(t, err)
+
+	localFile := filepath.Join(testDir, "foo.txt")
+	err = ioutil.WriteFile(localFile, testContent, 0644)
+	assert.NoError(t, err)
+	return testDir, localFile
+}
+
+func startSSHServer(t *testing.T) *tests.SSHServer {
+	server, err := tests.NewSSHServer()
+	assert.NoError(t, err, "Error creating ssh server")
+	return server
+}
+
+func newSSHClient(t *testing.T, server *tests.SSHServer) *ssh.Client {
+	port, err := server.Start()
+	assert.NoError(t, err, "Error starting ssh server")
+	driver := &tests.MockDriver{
+		Port: port,
+		BaseDriver: drivers.BaseDriver{
+			IPAddress: "127.0.0.1",
+		},
+	}
+	client, err := sshutil.NewSSHClient(driver)
+	assert.NoError(t, err, "Error creating ssh client")
+	return client
+}
+
+func checksumOf(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
@@ -24,10 +24,15 @@ import (
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
 	"github.com/minishift/minishift/pkg/minishift/network"
-	"github.com/minishift/minishift/pkg/util"
+	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
+	"path"
 	"strings"
 )
 
+const (
+	cifsCredentialsDir = "/var/lib/minishift/hostfolders"
+)
+
 type CifsHostFolder struct {
 	config config.HostFolderConfig
 }
@@ -57,47 +62,34 @@ func (h *CifsHostFolder) Mount(driver drivers.Driver) error {
 		return errors.New("host folder is unreachable")
 	}
 
-	password, err := util.DecryptText(h.config.Options[config.Password])
+	credentials, err := LoadCredentials(h.config)
 	if err != nil {
 		return err
 	}
 
-	cmd := fmt.Sprintf(
-		"sudo mount -t cifs %s %s -o username=%s,password=%s,%s",
-		h.config.Option(config.UncPath),
-		h.config.MountPoint(),
-		h.config.Option(config.UserName),
-		password,
-		h.config.Option(config.ExtraOptions))
-
-	if minishiftConfig.InstanceStateConfig.IsRHELBased {
-		cmd = fmt.Sprintf("%s,context=system_u:object_r:svirt_sandbox_file_t:s0", cmd)
-	}
-
-	if len(h.config.Options[config.Domain]) > 0 {
-		cmd = fmt.Sprintf("%s,domain=%s", cmd, h.config.Options["domain"])
-	}
-
-	if err := h.ensureMountPointExists(driver); err != nil {
+	// the credentials are only passed via a file readable by root, so that they do not show up in the process list
+	credentialsFile := h.credentialsFile()
+	if _, err := drivers.RunSSHCommandFromDriver(driver, writeCredentialsFileCmd(credentialsFile, credentials)); err != nil {
 		fmt.Println("FAIL")
-		return fmt.Errorf("error occured while creating mountpoint. %s", err)
+		return fmt.Errorf("error occured while writing the credentials file. %s", err)
 	}
 
-	if _, err := drivers.RunSSHCommandFromDriver(driver, cmd); err != nil {
+	if err := h.mountUnits(credentialsFile).install(driver); err != nil {
 		fmt.Println("FAIL")
-		return fmt.Errorf("error occured while mounting host folder: %s", err)
-	} else {
-		fmt.Println("OK")
+		return err
 	}
 
+	fmt.Println("OK")
 	return nil
 }
 
 func (h *CifsHostFolder) Umount(driver drivers.Driver) error {
-	cmd := fmt.Sprintf(
-		"sudo umount %s",
-		h.config.MountPoint())
+	if err := newMountUnits(h.config.Name, h.config.MountPoint()).remove(driver); err != nil {
+		fmt.Println("FAIL")
+		return err
+	}
 
+	cmd := fmt.Sprintf("sudo rm -f %s", h.credentialsFile())
 	if _, err := drivers.RunSSHCommandFromDriver(driver, cmd); err != nil {
 		fmt.Println("FAIL")
 		return fmt.Errorf("error during umounting of host folder: %s", err)
@@ -108,6 +100,40 @@ func (h *CifsHostFolder) Umount(driver drivers.Driver) error {
 	return nil
 }
 
+// mountUnits returns the systemd units mounting the share using the credentials in the specified file
+func (h *CifsHostFolder) mountUnits(credentialsFile string) *mountUnits {
+	units := newMountUnits(h.config.Name, h.config.MountPoint())
+	units.what = h.config.Option(config.UncPath)
+	units.fsType = "cifs"
+	units.options = []string{fmt.Sprintf("credentials=%s", credentialsFile)}
+	if extraOptions := h.config.Option(config.ExtraOptions); extraOptions != "" {
+		units.options = append(units.options, extraOptions)
+	}
+	if minishiftConfig.InstanceStateConfig.IsRHELBased {
+		units.options = append(units.options, "context=system_u:object_r:svirt_sandbox_file_t:s0")
+	}
+	return units
+}
+
+// credentialsFile returns the path of the file within the VM holding the credentials used to mount the host folder
+func (h *CifsHostFolder) credentialsFile() string {
+	return path.Join(cifsCredentialsDir, h.config.Name+".credentials")
+}
+
+// writeCredentialsFileCmd returns the command writing the credentials to the file with mode 0600. printf is a shell
+// builtin, hence the credentials do not show up in the process list.
+func writeCredentialsFileCmd(credentialsFile string, credentials Credentials) string {
+	content := fmt.Sprintf("username=%s\npassword=%s\n", credentials.UserName, credentials.Password)
+	if credentials.Domain != "" {
+		content = fmt.Sprintf("%sdomain=%s\n", content, credentials.Domain)
+	}
+
+	return fmt.Sprintf("sudo install -d -m 0700 %s && printf '%%s' '%s' | sudo install -m 0600 /dev/stdin %s",
+		path.Dir(credentialsFile),
+		minishiftStrings.EscapeSingleQuote(content),
+		credentialsFile)
+}
+
 func (h *CifsHostFolder) isCifsHostReachable(driver drivers.Driver) bool {
 	uncPath := h.config.Options[config.UncPath]
 
@@ -124,15 +150,3 @@ func (h *CifsHostFolder) isCifsHostReachable(driver drivers.Driver) bool {
 
 	return network.IsIPReachable(driver, host, false)
 }
-
-func (h *CifsHostFolder) ensureMountPointExists(driver drivers.Driver) error {
-	cmd := fmt.Sprintf(
-		"sudo mkdir -p %s",
-		h.config.MountPoint())
-
-	if _, err := drivers.RunSSHCommandFromDriver(driver, cmd); err != nil {
-		return err
-	}
-
-	return nil
-}
@@ -24,6 +24,13 @@ const (
 	Password     = "password"
 	Domain       = "domain"
 	ExtraOptions = "extra-options"
+	Transport    = "transport"
+	// CredentialsKey is the key under which the credentials of the host folder are stored in the keychain of the OS
+	CredentialsKey = "credentials-key"
+	// PullPaths are the comma separated paths of a sync host folder which are copied back from the VM to the host
+	PullPaths = "pull-paths"
+	// Excludes are the comma separated patterns of files a sync host folder does not synchronize
+	Excludes = "excludes"
 )
 
 type HostFolderConfig struct {
@@ -0,0 +1,110 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/minishift/minishift/pkg/util"
+	"github.com/zalando/go-keyring"
+)
+
+const credentialsService = "minishift-hostfolder"
+
+// Credentials are the credentials used to mount a CIFS host folder
+type Credentials struct {
+	UserName string `json:"username"`
+	Password string `json:"password"`
+	Domain   string `json:"domain,omitempty"`
+}
+
+// CredentialsKey returns the key under which the credentials of the host folder with the specified name are stored
+// in the keychain of the OS. Host folders of all instances and of a single profile use separate keys.
+func CredentialsKey(profile string, name string, allInstances bool) string {
+	if allInstances {
+		return fmt.Sprintf("global/%s", name)
+	}
+	return fmt.Sprintf("%s/%s", profile, name)
+}
+
+// StoreCredentials stores the credentials in the keychain of the OS under the specified key.
+func StoreCredentials(key string, credentials Credentials) error {
+	secret, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+
+	if err := keyring.Set(credentialsService, key, string(secret)); err != nil {
+		return fmt.Errorf("unable to store the credentials in the keychain of the OS: %s", err)
+	}
+	return nil
+}
+
+// LoadCredentials returns the credentials of the host folder. Host folders defined before the credentials were kept
+// in the keychain of the OS still carry the credentials in their options.
+func LoadCredentials(hostFolderConfig config.HostFolderConfig) (Credentials, error) {
+	key := hostFolderConfig.Option(config.CredentialsKey)
+	if key == "" {
+		return legacyCredentials(hostFolderConfig), nil
+	}
+
+	secret, err := keyring.Get(credentialsService, key)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("unable to read the credentials of host folder '%s' from the keychain of the OS: %s", hostFolderConfig.Name, err)
+	}
+
+	var credentials Credentials
+	if err := json.Unmarshal([]byte(secret), &credentials); err != nil {
+		return Credentials{}, fmt.Errorf("invalid credentials stored for host folder '%s': %s", hostFolderConfig.Name, err)
+	}
+	return credentials, nil
+}
+
+// DeleteCredentials removes the credentials of the host folder from the keychain of the OS.
+func DeleteCredentials(hostFolderConfig config.HostFolderConfig) error {
+	key := hostFolderConfig.Option(config.CredentialsKey)
+	if key == "" {
+		return nil
+	}
+
+	err := keyring.Delete(credentialsService, key)
+	if err != nil && err != keyring.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// hasLegacyCredentials returns true if the credentials are part of the options of the host folder
+func hasLegacyCredentials(hostFolderConfig config.HostFolderConfig) bool {
+	return hostFolderConfig.Option(config.UserName) != "" || hostFolderConfig.Option(config.Password) != ""
+}
+
+func legacyCredentials(hostFolderConfig config.HostFolderConfig) Credentials {
+	// interactively added host folders stored the password encrypted, others in plain text
+	password, err := util.DecryptText(hostFolderConfig.Option(config.Password))
+	if err != nil {
+		password = hostFolderConfig.Option(config.Password)
+	}
+
+	return Credentials{
+		UserName: hostFolderConfig.Option(config.UserName),
+		Password: password,
+		Domain:   hostFolderConfig.Option(config.Domain),
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/minishift/minishift/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+)
+
+func Test_credentials_key(t *testing.T) {
+	assert.Equal(t, "minishift/share", CredentialsKey("minishift", "share", false))
+	assert.Equal(t, "global/share", CredentialsKey("minishift", "share", true))
+}
+
+func Test_credentials_are_kept_in_keychain(t *testing.T) {
+	keyring.MockInit()
+
+	credentials := Credentials{UserName: "john", Password: "s3cr3t", Domain: "CORP"}
+	assert.NoError(t, StoreCredentials("minishift/share", credentials))
+
+	hostFolderConfig := config.HostFolderConfig{
+		Name:    "share",
+		Type:    CIFS.String(),
+		Options: map[string]string{config.CredentialsKey: "minishift/share"},
+	}
+	loaded, err := LoadCredentials(hostFolderConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, credentials, loaded)
+
+	assert.NoError(t, DeleteCredentials(hostFolderConfig))
+	_, err = LoadCredentials(hostFolderConfig)
+	assert.Error(t, err, "Expected error for deleted credentials")
+	assert.NoError(t, DeleteCredentials(hostFolderConfig), "Deleting missing credentials should not fail")
+}
+
+func Test_legacy_credentials(t *testing.T) {
+	encrypted, err := util.EncryptText("s3cr3t")
+	assert.NoError(t, err)
+
+	for _, password := range []string{encrypted, "s3cr3t"} {
+		hostFolderConfig := config.HostFolderConfig{
+			Name: "share",
+			Type: CIFS.String(),
+			Options: map[string]string{
+				config.UserName: "john",
+				config.Password: password,
+			},
+		}
+		assert.True(t, hasLegacyCredentials(hostFolderConfig))
+
+		credentials, err := LoadCredentials(hostFolderConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, Credentials{UserName: "john", Password: "s3cr3t"}, credentials)
+	}
+}
+
+func Test_cifs_credentials_file_command(t *testing.T) {
+	cmd := writeCredentialsFileCmd("/var/lib/minishift/hostfolders/share.credentials", Credentials{UserName: "john", Password: "it's"})
+	assert.Equal(t, "sudo install -d -m 0700 /var/lib/minishift/hostfolders && printf '%s' 'username=john\npassword=it'\"'\"'s\n' | sudo install -m 0600 /dev/stdin /var/lib/minishift/hostfolders/share.credentials", cmd)
+}
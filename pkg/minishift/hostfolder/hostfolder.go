@@ -29,15 +29,23 @@ const (
 
 	// CIFS defines the constant to be used for the CIFS host folder type.
 	CIFS
+
+	// NFS defines the constant to be used for the NFS host folder type.
+	NFS
+
+	// Sync defines the constant to be used for host folders synchronized between the host and the VM.
+	Sync
 )
 
 func (t Type) String() string {
 	names := [...]string{
 		"sshfs",
-		"cifs"}
+		"cifs",
+		"nfs",
+		"sync"}
 
 	// prevent panicking
-	if t < SSHFS || t > CIFS {
+	if t < SSHFS || t > Sync {
 		return "unknown"
 	}
 	return names[t]
@@ -23,17 +23,23 @@ import (
 
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/state"
-	"github.com/golang/glog"
+	"github.com/minishift/minishift/pkg/minikube/constants"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/minishift/minishift/pkg/util/logging"
+	"github.com/minishift/minishift/pkg/util/os/process"
+	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
 )
 
+var logger = logging.For("hostfolder")
+
 type MountInfo struct {
 	Name       string
 	Type       string
 	Source     string
 	MountPoint string
 	Mounted    bool
+	Health     string
 }
 
 // Manager is the central point for all operations around managing hostfolders.
@@ -78,6 +84,22 @@ func (m *Manager) Remove(name string) error {
 		return fmt.Errorf("no host folder defined with name '%s'", name)
 	}
 
+	if hostFolder := m.getHostFolder(name); hostFolder != nil {
+		if err := DeleteCredentials(hostFolder.Config()); err != nil {
+			logger.Errorf("Unable to remove the credentials of host folder '%s' from the keychain: %s", name, err)
+		}
+		if hostFolder.Config().Type == Sync.String() {
+			if err := stopSyncDaemon(name); err != nil {
+				logger.Errorf("Unable to stop the synchronization of host folder '%s': %s", name, err)
+			}
+		}
+		if nfsHostFolder, ok := hostFolder.(*NFSHostFolder); ok {
+			if err := nfsHostFolder.removeExport(); err != nil {
+				logger.Errorf("Unable to remove the NFS export of host folder '%s': %s", name, err)
+			}
+		}
+	}
+
 	m.instanceConfig.HostFolders = m.removeFromHostFolders(name, minishiftConfig.InstanceConfig.HostFolders)
 	m.instanceConfig.Write()
 
@@ -110,11 +132,12 @@ func (m *Manager) List(driver drivers.Driver) ([]MountInfo, error) {
 		switch hostFolder.Type {
 		case CIFS.String():
 			source = hostFolder.Options[config.UncPath]
-		case SSHFS.String():
+		case SSHFS.String(), NFS.String(), Sync.String():
 			source = hostFolder.Options[config.Source]
 		}
 
 		mounted := false
+		health := HealthUnknown
 		if isRunning {
 			isMounted, err := m.isHostFolderMounted(driver, hostFolder)
 			if err != nil {
@@ -122,6 +145,9 @@ func (m *Manager) List(driver drivers.Driver) ([]MountInfo, error) {
 			}
 			if isMounted {
 				mounted = true
+				health = HealthOK
+			} else if hostFolder.Type != Sync.String() {
+				health = newMountUnits(hostFolder.Name, hostFolder.MountPoint()).health(driver)
 			}
 		}
 
@@ -131,6 +157,7 @@ func (m *Manager) List(driver drivers.Driver) ([]MountInfo, error) {
 			Source:     source,
 			MountPoint: hostFolder.MountPoint(),
 			Mounted:    mounted,
+			Health:     health,
 		}
 
 		mounts = append(mounts, mount)
@@ -146,19 +173,54 @@ func (m *Manager) Mount(driver drivers.Driver, name string) error {
 		return errors.New("host is in the wrong state")
 	}
 
+	m.migrateLegacyCredentials(name)
+
 	hostFolder := m.getHostFolder(name)
 	if hostFolder == nil {
 		return fmt.Errorf("no host folder with name '%s' defined", name)
 	}
 
+	return m.mount(driver, hostFolder)
+}
+
+// MountTransient mounts the specified host folder into the running VM without adding it to the defined host folders.
+// An error is returned, if the VM is not running, the mount point is already in use or the mount fails.
+func (m *Manager) MountTransient(driver drivers.Driver, hostFolder HostFolder) error {
+	if !m.isHostRunning(driver) {
+		return errors.New("host is in the wrong state")
+	}
+
+	return m.mount(driver, hostFolder)
+}
+
+// UmountTransient umounts the specified host folder mounted via MountTransient. Umounting a host folder which is
+// not mounted is not an error.
+func (m *Manager) UmountTransient(driver drivers.Driver, hostFolder HostFolder) error {
+	if !m.isHostRunning(driver) {
+		return errors.New("host is in the wrong state")
+	}
+
+	hostFolderConfig := hostFolder.Config()
+	mounted, err := m.isHostFolderMounted(driver, hostFolderConfig)
+	if !mounted {
+		if err != nil {
+			return fmt.Errorf("error umouting '%s': %s", hostFolderConfig.MountPoint(), err)
+		}
+		return nil
+	}
+
+	return hostFolder.Umount(driver)
+}
+
+func (m *Manager) mount(driver drivers.Driver, hostFolder HostFolder) error {
 	m.ensureMountPointExists(driver, hostFolder.Config())
 
 	mounted, err := m.isHostFolderMounted(driver, hostFolder.Config())
 	if mounted {
 		if err != nil {
-			glog.Error(err.Error())
+			logger.Errorf("%s", err.Error())
 		}
-		glog.Info("SSH server established")
+		logger.Infof("SSH server established")
 		return fmt.Errorf("host folder is already mounted")
 	}
 
@@ -187,6 +249,36 @@ func (m *Manager) MountAll(driver drivers.Driver) error {
 	return nil
 }
 
+// RestoreMounts mounts the host folders again which were mounted before the VM got restarted. The units of these
+// host folders are kept in the VM, but systemd does not find them after a reboot. The synchronization of sync host
+// folders suspended on stop is resumed as well.
+func (m *Manager) RestoreMounts(driver drivers.Driver) error {
+	if !m.isHostRunning(driver) {
+		return errors.New("host is in the wrong state")
+	}
+
+	installed, err := installedAutomountUnits(driver)
+	if err != nil {
+		return err
+	}
+
+	hostFolderConfigs := m.allInstancesConfig.HostFolders
+	hostFolderConfigs = append(hostFolderConfigs, m.instanceConfig.HostFolders...)
+	for _, hostFolderConfig := range hostFolderConfigs {
+		automountName := newMountUnits(hostFolderConfig.Name, hostFolderConfig.MountPoint()).automountName()
+		if !minishiftStrings.Contains(installed, automountName) && !wasSyncSuspended(hostFolderConfig.Name) {
+			continue
+		}
+		if mounted, _ := m.isHostFolderMounted(driver, hostFolderConfig); mounted {
+			continue
+		}
+		if err := m.Mount(driver, hostFolderConfig.Name); err != nil {
+			return fmt.Errorf("error mounting host folder '%s': %s", hostFolderConfig.Name, err)
+		}
+	}
+	return nil
+}
+
 // Umount umounts the host folder specified by name. nil is returned on success.
 // An error is returned, if the VM is not running, the specified host folder does not exist or the mount fails.
 func (m *Manager) Umount(driver drivers.Driver, name string) error {
@@ -208,6 +300,9 @@ func (m *Manager) Umount(driver drivers.Driver, name string) error {
 		if err != nil {
 			return fmt.Errorf("error umouting hostfolder '%s': %s", name, err)
 		}
+		if wasSyncSuspended(name) {
+			return stopSyncDaemon(name)
+		}
 		return nil
 	}
 
@@ -219,6 +314,66 @@ func (m *Manager) Umount(driver drivers.Driver, name string) error {
 	return nil
 }
 
+// EnsureTunnelRunning restarts the sftp tunnel serving the sshfs host folders mounted over a reverse SSH tunnel, in
+// case the tunnel process terminated, for example because the SSH connection dropped. The mounts reconnect on their
+// own once the tunnel is back. Returns true if the tunnel was restarted.
+func (m *Manager) EnsureTunnelRunning(driver drivers.Driver) (bool, error) {
+	if !m.isHostRunning(driver) || process.IsRunning(minishiftConfig.InstanceStateConfig.SftpTunnelPID) {
+		return false, nil
+	}
+
+	hostFolderConfigs := append([]config.HostFolderConfig{}, m.instanceConfig.HostFolders...)
+	hostFolderConfigs = append(hostFolderConfigs, m.allInstancesConfig.HostFolders...)
+	for i := range hostFolderConfigs {
+		sshfsHostFolder, ok := m.hostFolderForConfig(&hostFolderConfigs[i]).(*SSHFSHostFolder)
+		if !ok || !sshfsHostFolder.usesTunnel() {
+			continue
+		}
+
+		mounted, err := m.isHostFolderMounted(driver, hostFolderConfigs[i])
+		if err != nil {
+			return false, err
+		}
+		if mounted {
+			return true, sshfsHostFolder.ensureTunnelDaemonRunning()
+		}
+	}
+
+	return false, nil
+}
+
+// migrateLegacyCredentials moves the credentials of a host folder defined with the credentials as part of its
+// options into the keychain of the OS. If the keychain is not available, the legacy credentials are kept.
+func (m *Manager) migrateLegacyCredentials(name string) {
+	migrate := func(hostFolderConfigs []config.HostFolderConfig, key string) bool {
+		for i := range hostFolderConfigs {
+			hostFolderConfig := &hostFolderConfigs[i]
+			if hostFolderConfig.Name != name || hostFolderConfig.Type != CIFS.String() || !hasLegacyCredentials(*hostFolderConfig) {
+				continue
+			}
+
+			if err := StoreCredentials(key, legacyCredentials(*hostFolderConfig)); err != nil {
+				logger.Errorf("Unable to migrate the credentials of host folder '%s': %s", name, err)
+				return false
+			}
+
+			delete(hostFolderConfig.Options, config.UserName)
+			delete(hostFolderConfig.Options, config.Password)
+			delete(hostFolderConfig.Options, config.Domain)
+			hostFolderConfig.Options[config.CredentialsKey] = key
+			return true
+		}
+		return false
+	}
+
+	if migrate(m.instanceConfig.HostFolders, CredentialsKey(constants.ProfileName, name, false)) {
+		m.instanceConfig.Write()
+	}
+	if migrate(m.allInstancesConfig.HostFolders, CredentialsKey(constants.ProfileName, name, true)) {
+		m.allInstancesConfig.Write()
+	}
+}
+
 func (m *Manager) getHostFolder(name string) HostFolder {
 	config := m.getHostFolderConfig(name, minishiftConfig.InstanceConfig.HostFolders)
 	if config != nil {
@@ -239,6 +394,10 @@ func (m *Manager) hostFolderForConfig(config *config.HostFolderConfig) HostFolde
 		return NewCifsHostFolder(*config)
 	case SSHFS.String():
 		return NewSSHFSHostFolder(*config, m.allInstancesConfig)
+	case NFS.String():
+		return NewNFSHostFolder(*config)
+	case Sync.String():
+		return NewSyncHostFolder(*config)
 	default:
 		return nil
 	}
@@ -283,6 +442,11 @@ func (m *Manager) removeFromHostFolders(name string, hostfolders []config.HostFo
 }
 
 func (m *Manager) isHostFolderMounted(driver drivers.Driver, hostFolderConfig config.HostFolderConfig) (bool, error) {
+	// sync host folders are not mounted, but kept in sync by a process on the host
+	if hostFolderConfig.Type == Sync.String() {
+		return isSyncRunning(hostFolderConfig.Name), nil
+	}
+
 	cmd := "cat /proc/mounts"
 	procMounts, err := drivers.RunSSHCommandFromDriver(driver, cmd)
 	if err != nil {
@@ -17,10 +17,33 @@ limitations under the License.
 package hostfolder
 
 import (
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
 
 func Test_type_string(t *testing.T) {
 	assert.Equal(t, CIFS.String(), "cifs", "unexpected string representation of host folder type")
+	assert.Equal(t, NFS.String(), "nfs", "unexpected string representation of host folder type")
+	assert.Equal(t, Sync.String(), "sync", "unexpected string representation of host folder type")
+}
+
+func Test_sshfs_transport(t *testing.T) {
+	legacy := &SSHFSHostFolder{config: config.HostFolderConfig{Type: SSHFS.String(), Options: map[string]string{}}}
+	assert.False(t, legacy.usesTunnel(), "host folders without transport should connect directly")
+
+	tunnel := &SSHFSHostFolder{config: config.HostFolderConfig{Type: SSHFS.String(), Options: map[string]string{config.Transport: SSHFSTransportTunnel}}}
+	assert.True(t, tunnel.usesTunnel())
+}
+
+func Test_sync_options(t *testing.T) {
+	options := SyncOptions(config.HostFolderConfig{
+		Type: Sync.String(),
+		Options: map[string]string{
+			config.PullPaths: "target, build/libs",
+			config.Excludes:  "",
+		},
+	})
+	assert.Equal(t, []string{"target", "build/libs"}, options.PullPaths)
+	assert.Empty(t, options.Excludes)
 }
@@ -0,0 +1,214 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var (
+	// exportsFile is the file the NFS server of the host reads its exports from
+	exportsFile = "/etc/exports"
+
+	// runHostCommand runs a command on the host, connected to the terminal so that sudo can ask for a password
+	runHostCommand = func(stdin string, name string, args ...string) error {
+		cmd := exec.Command(name, args...)
+		if stdin != "" {
+			cmd.Stdin = strings.NewReader(stdin)
+		} else {
+			cmd.Stdin = os.Stdin
+		}
+		cmd.Stdout = ioutil.Discard
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+)
+
+// exportMarker returns the comment preceding the export of the host folder in the exports file
+func exportMarker(profile string, name string) string {
+	return fmt.Sprintf("# minishift %s/%s", profile, name)
+}
+
+// validateExportSource checks that source can be written to the exports file and to the systemd mount unit
+func validateExportSource(source string) error {
+	if strings.ContainsAny(source, "\"\n\r") {
+		return fmt.Errorf("the source '%s' of an NFS host folder must not contain quotes or line breaks", source)
+	}
+	return nil
+}
+
+// exportLine returns the exports file entry sharing source with the client IP for the specified host OS. All
+// accesses are mapped to the user owning the directory on the host.
+func exportLine(goos string, source string, clientIP string, uid int, gid int) (string, error) {
+	switch goos {
+	case "linux":
+		return fmt.Sprintf("\"%s\" %s(rw,sync,no_subtree_check,all_squash,anonuid=%d,anongid=%d)", source, clientIP, uid, gid), nil
+	case "darwin":
+		return fmt.Sprintf("\"%s\" -alldirs -mapall=%d:%d %s", source, uid, gid, clientIP), nil
+	default:
+		return "", fmt.Errorf("NFS host folders are not supported on '%s' hosts", goos)
+	}
+}
+
+// addExport returns the exports file content with the export marked by marker set to line.
+func addExport(content string, marker string, line string) string {
+	content = removeExport(content, marker)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + marker + "\n" + line + "\n"
+}
+
+// removeExport returns the exports file content without the export marked by marker.
+func removeExport(content string, marker string) string {
+	var out bytes.Buffer
+	lines := strings.SplitAfter(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == marker {
+			// skip the export following the marker
+			i++
+			continue
+		}
+		out.WriteString(lines[i])
+	}
+	return out.String()
+}
+
+// removeProfileExports returns the exports file content without the exports of all host folders of the profile.
+func removeProfileExports(content string, profile string) string {
+	prefix := exportMarker(profile, "")
+	var out bytes.Buffer
+	lines := strings.SplitAfter(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), prefix) {
+			i++
+			continue
+		}
+		out.WriteString(lines[i])
+	}
+	return out.String()
+}
+
+// RemoveNFSExports removes the exports of the NFS host folders of the profile from the exports file of the host. The
+// exports are granted to the IP of the VM, hence they need to be removed together with the VM.
+func RemoveNFSExports(goos string, profile string) error {
+	return updateExports(goos, func(content string) string { return removeProfileExports(content, profile) })
+}
+
+// updateExports rewrites the exports file of the host using update and makes the NFS server of the host reload it.
+func updateExports(goos string, update func(content string) string) error {
+	content, err := ioutil.ReadFile(exportsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	newContent := update(string(content))
+	if newContent == string(content) {
+		return nil
+	}
+
+	fmt.Println(fmt.Sprintf("   Updating '%s', which requires administrative privileges", exportsFile))
+	if err := runHostCommand(newContent, "sudo", "tee", exportsFile); err != nil {
+		return fmt.Errorf("error writing '%s': %s", exportsFile, err)
+	}
+
+	switch goos {
+	case "linux":
+		return runHostCommand("", "sudo", "exportfs", "-ra")
+	case "darwin":
+		if err := runHostCommand("", "sudo", "nfsd", "checkexports"); err != nil {
+			return fmt.Errorf("invalid exports in '%s': %s", exportsFile, err)
+		}
+		return runHostCommand("", "sudo", "nfsd", "update")
+	}
+	return nil
+}
+
+// ensureNFSServerRunning starts the NFS server of the host and, on Linux, opens the firewall for NFS if firewalld is
+// running. The services are added to the firewall zone of the interface with the specified host IP, both to the
+// runtime and to the permanent configuration, so that the rules survive a reload of firewalld.
+func ensureNFSServerRunning(goos string, hostIP string) error {
+	switch goos {
+	case "linux":
+		if err := runHostCommand("", "sudo", "systemctl", "start", "nfs-server"); err != nil {
+			return fmt.Errorf("error starting the NFS server: %s", err)
+		}
+		if runHostCommand("", "firewall-cmd", "--state") == nil {
+			args := []string{"firewall-cmd", "--add-service=nfs", "--add-service=mountd", "--add-service=rpc-bind"}
+			if zone := firewallZone(hostIP); zone != "" {
+				args = append(args, "--zone="+zone)
+			}
+			for _, permanent := range []bool{false, true} {
+				cmdArgs := args
+				if permanent {
+					cmdArgs = append(cmdArgs, "--permanent")
+				}
+				if err := runHostCommand("", "sudo", cmdArgs...); err != nil {
+					return fmt.Errorf("error opening the firewall for NFS: %s", err)
+				}
+			}
+		}
+	case "darwin":
+		// enabling an enabled server is a no-op
+		if err := runHostCommand("", "sudo", "nfsd", "enable"); err != nil {
+			return fmt.Errorf("error starting the NFS server: %s", err)
+		}
+	default:
+		return fmt.Errorf("NFS host folders are not supported on '%s' hosts", goos)
+	}
+	return nil
+}
+
+// firewallZone returns the firewalld zone of the host interface with the specified IP, empty if it cannot be determined,
+// in which case firewalld uses the default zone.
+func firewallZone(hostIP string) string {
+	iface := interfaceWithIP(hostIP)
+	if iface == "" {
+		return ""
+	}
+	out, err := exec.Command("firewall-cmd", "--get-zone-of-interface="+iface).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// interfaceWithIP returns the name of the host interface with the specified IP, empty if there is none
+func interfaceWithIP(ip string) string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+	for _, iface := range interfaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.String() == ip {
+				return iface.Name
+			}
+		}
+	}
+	return ""
+}
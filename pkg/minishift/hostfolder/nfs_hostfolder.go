@@ -0,0 +1,108 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/minishift/minishift/pkg/minishift/network"
+)
+
+const (
+	defaultNFSMountOptions = "vers=3,tcp,nolock,actimeo=2"
+)
+
+// NFSHostFolder shares a host directory via the NFS server of the host. Minishift manages the export on the host
+// and the systemd units mounting the export in the VM.
+type NFSHostFolder struct {
+	config config.HostFolderConfig
+}
+
+func NewNFSHostFolder(config config.HostFolderConfig) HostFolder {
+	return &NFSHostFolder{config: config}
+}
+
+func (h *NFSHostFolder) Config() config.HostFolderConfig {
+	return h.config
+}
+
+func (h *NFSHostFolder) Mount(driver drivers.Driver) error {
+	fmt.Println(fmt.Sprintf("   Mounting '%s': '%s' as '%s'",
+		h.config.Name,
+		h.config.Option(config.Source),
+		h.config.MountPoint()))
+
+	hostIP, err := network.DetermineHostIP(driver)
+	if err != nil || hostIP == "" {
+		return fmt.Errorf("error determining the IP of the host: %v", err)
+	}
+	vmIP, err := driver.GetIP()
+	if err != nil {
+		return err
+	}
+
+	if err := validateExportSource(h.config.Option(config.Source)); err != nil {
+		return err
+	}
+	line, err := exportLine(runtime.GOOS, h.config.Option(config.Source), vmIP, os.Getuid(), os.Getgid())
+	if err != nil {
+		return err
+	}
+	if err := ensureNFSServerRunning(runtime.GOOS, hostIP); err != nil {
+		return err
+	}
+	marker := exportMarker(constants.ProfileName, h.config.Name)
+	if err := updateExports(runtime.GOOS, func(content string) string { return addExport(content, marker, line) }); err != nil {
+		return fmt.Errorf("error exporting '%s': %s", h.config.Option(config.Source), err)
+	}
+
+	return h.mountUnits(hostIP).install(driver)
+}
+
+// removeExport removes the export of the host folder from the exports file of the host
+func (h *NFSHostFolder) removeExport() error {
+	marker := exportMarker(constants.ProfileName, h.config.Name)
+	if err := updateExports(runtime.GOOS, func(content string) string { return removeExport(content, marker) }); err != nil {
+		return fmt.Errorf("error removing the export of '%s': %s", h.config.Option(config.Source), err)
+	}
+	return nil
+}
+
+func (h *NFSHostFolder) Umount(driver drivers.Driver) error {
+	if err := newMountUnits(h.config.Name, h.config.MountPoint()).remove(driver); err != nil {
+		return err
+	}
+
+	return h.removeExport()
+}
+
+// mountUnits returns the systemd units mounting the export of the host with the specified IP
+func (h *NFSHostFolder) mountUnits(hostIP string) *mountUnits {
+	units := newMountUnits(h.config.Name, h.config.MountPoint())
+	units.what = fmt.Sprintf("%s:%s", hostIP, h.config.Option(config.Source))
+	units.fsType = "nfs"
+	units.options = []string{defaultNFSMountOptions}
+	if extraOptions := h.config.Option(config.ExtraOptions); extraOptions != "" {
+		units.options = append(units.options, extraOptions)
+	}
+	return units
+}
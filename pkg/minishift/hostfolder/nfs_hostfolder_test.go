@@ -0,0 +1,127 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_nfs_export_line(t *testing.T) {
+	line, err := exportLine("linux", "/home/john/src", "192.168.42.10", 1000, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, "\"/home/john/src\" 192.168.42.10(rw,sync,no_subtree_check,all_squash,anonuid=1000,anongid=1000)", line)
+
+	line, err = exportLine("darwin", "/Users/john/src", "192.168.64.2", 501, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, "\"/Users/john/src\" -alldirs -mapall=501:20 192.168.64.2", line)
+
+	_, err = exportLine("windows", "C:/src", "192.168.99.100", 0, 0)
+	assert.Error(t, err, "Expected error for unsupported host OS")
+}
+
+func Test_nfs_exports_are_updated_in_place(t *testing.T) {
+	existing := "/srv/data 10.0.0.0/8(ro)"
+	marker := exportMarker("minishift", "src")
+	assert.Equal(t, "# minishift minishift/src", marker)
+
+	content := addExport(existing, marker, "\"/src\" 192.168.42.10(rw)")
+	assert.Equal(t, "/srv/data 10.0.0.0/8(ro)\n# minishift minishift/src\n\"/src\" 192.168.42.10(rw)\n", content)
+
+	content = addExport(content, marker, "\"/src\" 192.168.42.11(rw)")
+	assert.Equal(t, 1, strings.Count(content, marker), "Export should be replaced, not added again")
+	assert.Contains(t, content, "192.168.42.11")
+
+	content = removeExport(content, marker)
+	assert.Equal(t, "/srv/data 10.0.0.0/8(ro)\n", content)
+}
+
+func Test_nfs_exports_of_profile_are_removed(t *testing.T) {
+	content := "/srv/data 10.0.0.0/8(ro)\n" +
+		exportMarker("minishift", "src") + "\n\"/src\" 1.2.3.4(rw)\n" +
+		exportMarker("minishift-dev", "src") + "\n\"/dev\" 1.2.3.5(rw)\n" +
+		exportMarker("minishift", "docs") + "\n\"/docs\" 1.2.3.4(rw)\n"
+
+	assert.Equal(t, "/srv/data 10.0.0.0/8(ro)\n"+exportMarker("minishift-dev", "src")+"\n\"/dev\" 1.2.3.5(rw)\n",
+		removeProfileExports(content, "minishift"))
+}
+
+func Test_nfs_export_source_is_validated(t *testing.T) {
+	assert.NoError(t, validateExportSource("/home/john/my src"))
+	assert.Error(t, validateExportSource("/home/john/\"src"))
+	assert.Error(t, validateExportSource("/home/john/src\n/etc"))
+}
+
+func Test_nfs_update_exports(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-nfs-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	origExportsFile, origRunHostCommand := exportsFile, runHostCommand
+	defer func() { exportsFile, runHostCommand = origExportsFile, origRunHostCommand }()
+
+	exportsFile = filepath.Join(testDir, "exports")
+	var commands []string
+	runHostCommand = func(stdin string, name string, args ...string) error {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		if name == "sudo" && args[0] == "tee" {
+			return ioutil.WriteFile(args[1], []byte(stdin), 0644)
+		}
+		return nil
+	}
+
+	err = updateExports("linux", func(content string) string { return addExport(content, "# marker", "\"/src\" 1.2.3.4(rw)") })
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sudo tee " + exportsFile, "sudo exportfs -ra"}, commands)
+
+	content, err := ioutil.ReadFile(exportsFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "# marker\n\"/src\" 1.2.3.4(rw)\n", string(content))
+
+	// unchanged exports do not require a reload
+	commands = nil
+	err = updateExports("linux", func(content string) string { return content })
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+}
+
+func Test_nfs_mount_unit(t *testing.T) {
+	hostFolder := &NFSHostFolder{config: config.HostFolderConfig{
+		Name: "src",
+		Type: NFS.String(),
+		Options: map[string]string{
+			config.Source:       "/home/john/src",
+			config.MountPoint:   "/mnt/sda1/src",
+			config.ExtraOptions: "ro",
+		},
+	}}
+	unit := hostFolder.mountUnits("192.168.42.1").mountUnit()
+	assert.Contains(t, unit, "What=192.168.42.1:/home/john/src\n")
+	assert.Contains(t, unit, "Where=/mnt/sda1/src\n")
+	assert.Contains(t, unit, "Type=nfs\n")
+	assert.Contains(t, unit, "Options="+defaultNFSMountOptions+",ro\n")
+
+	hostFolder.config.Options[config.Source] = "/home/john/100%"
+	unit = hostFolder.mountUnits("192.168.42.1").mountUnit()
+	assert.Contains(t, unit, "What=192.168.42.1:/home/john/100%%\n")
+}
@@ -19,23 +19,27 @@ package hostfolder
 import (
 	"fmt"
 	"github.com/docker/machine/libmachine/drivers"
-	"github.com/golang/glog"
+	"github.com/minishift/minishift/pkg/minikube/constants"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
 	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
 	"github.com/minishift/minishift/pkg/util"
 	"github.com/minishift/minishift/pkg/util/filehelper"
-	"github.com/minishift/minishift/pkg/util/os"
 	"github.com/minishift/minishift/pkg/util/os/process"
-	goos "os"
-	"os/exec"
-	"runtime"
+	"strconv"
 	"strings"
-	"syscall"
+	"time"
 )
 
 const (
 	keyFile = "/home/docker/.ssh/id_rsa"
+
+	// SSHFSTransportTunnel mounts SSHFS host folders over a reverse tunnel of an SSH connection from the host to the VM
+	SSHFSTransportTunnel = "tunnel"
+	// SSHFSTransportDirect mounts SSHFS host folders by connecting from the VM to the sftp port of the host
+	SSHFSTransportDirect = "direct"
+
+	tunnelHost = "127.0.0.1"
 )
 
 var (
@@ -63,39 +67,37 @@ func (h *SSHFSHostFolder) Mount(driver drivers.Driver) error {
 		return err
 	}
 
-	if err := h.ensureSFTPDDaemonRunning(); err != nil {
-		return err
+	var ip string
+	if h.usesTunnel() {
+		if err := h.ensureTunnelDaemonRunning(); err != nil {
+			return err
+		}
+		if err := h.waitForTunnel(driver); err != nil {
+			return err
+		}
+		ip = tunnelHost
+	} else {
+		if err := h.ensureSFTPDDaemonRunning(); err != nil {
+			return err
+		}
+
+		hostIP, err := h.hostIP(driver)
+		if err != nil {
+			return err
+		}
+		ip = hostIP
 	}
 
-	ip, err := h.hostIP(driver)
+	units, err := h.mountUnits(ip)
 	if err != nil {
 		return err
 	}
 
-	// Mount command seems to fail occasionally. Give it a couple of attempts
-	mount := func() (err error) {
-		cmd := fmt.Sprintf(
-			"sudo sshfs docker@%s:%s %s -o IdentityFile=%s -o 'StrictHostKeyChecking=no' -o reconnect -o allow_other -o idmap=none %s -p %d",
-			ip,
-			h.config.Option(config.Source),
-			h.config.MountPoint(),
-			keyFile,
-			h.config.Option(config.ExtraOptions),
-			SftpPort)
-
-		if glog.V(2) {
-			fmt.Println(cmd)
-		}
-
-		if _, err := drivers.RunSSHCommandFromDriver(driver, cmd); err != nil {
-			return fmt.Errorf("error occured while mounting host folder: %s", err)
+	// The mount used to be retried around the sshfs call, install now retries starting the mount unit instead
+	if err := units.install(driver); err != nil {
+		if h.usesTunnel() {
+			return err
 		}
-
-		return nil
-	}
-
-	err = util.Retry(3, mount)
-	if err != nil {
 		errMsg := fmt.Sprintf("\nNote: Make sure that your network and firewall settings on the host allows port %d to be opened\n\n", SftpPort)
 		return fmt.Errorf("%s%s", errMsg, err)
 	}
@@ -104,13 +106,66 @@ func (h *SSHFSHostFolder) Mount(driver drivers.Driver) error {
 }
 
 func (h *SSHFSHostFolder) Umount(driver drivers.Driver) error {
-	cmd := fmt.Sprintf("sudo umount %s", h.config.MountPoint())
+	return newMountUnits(h.config.Name, h.config.MountPoint()).remove(driver)
+}
+
+// mountUnits returns the systemd units mounting the host folder from the sftp server reachable at the specified IP
+func (h *SSHFSHostFolder) mountUnits(ip string) (*mountUnits, error) {
+	extraOptions, err := sshfsMountOptions(h.config.Option(config.ExtraOptions))
+	if err != nil {
+		return nil, err
+	}
 
-	if _, err := drivers.RunSSHCommandFromDriver(driver, cmd); err != nil {
-		return fmt.Errorf("error during umounting of host folder: %s", err)
+	units := newMountUnits(h.config.Name, h.config.MountPoint())
+	units.what = fmt.Sprintf("docker@%s:%s", ip, h.config.Option(config.Source))
+	units.fsType = "fuse.sshfs"
+	units.options = []string{
+		fmt.Sprintf("IdentityFile=%s", keyFile),
+		"StrictHostKeyChecking=no",
+		"reconnect",
+		"ServerAliveInterval=15",
+		"allow_other",
+		"idmap=none",
+		fmt.Sprintf("port=%d", SftpPort),
+		"_netdev",
 	}
+	units.options = append(units.options, extraOptions...)
+	return units, nil
+}
 
-	return nil
+// ValidateSSHFSOptions returns an error if the specified sshfs options cannot be used for a host folder.
+func ValidateSSHFSOptions(extraOptions string) error {
+	_, err := sshfsMountOptions(extraOptions)
+	return err
+}
+
+// sshfsMountOptions converts the sshfs command line options of a host folder into mount options. Host folders
+// specify their options in the form '-o name=value' as passed to sshfs. An error is returned for any other option,
+// since it cannot be expressed in a mount unit.
+func sshfsMountOptions(extraOptions string) ([]string, error) {
+	var options []string
+	fields := strings.Fields(extraOptions)
+	for i := 0; i < len(fields); i++ {
+		var value string
+		switch {
+		case fields[i] == "-o" && i+1 < len(fields):
+			i++
+			value = fields[i]
+		case strings.HasPrefix(fields[i], "-o"):
+			value = strings.TrimPrefix(fields[i], "-o")
+		case fields[i] == "-C":
+			value = "compression=yes"
+		default:
+			return nil, fmt.Errorf("unsupported sshfs option '%s'. Only '-o' options and '-C' are supported", fields[i])
+		}
+
+		for _, option := range strings.Split(strings.Trim(value, "'\""), ",") {
+			if option != "" {
+				options = append(options, option)
+			}
+		}
+	}
+	return options, nil
 }
 
 func (h *SSHFSHostFolder) hostIP(driver drivers.Driver) (string, error) {
@@ -127,25 +182,54 @@ func (h *SSHFSHostFolder) hostIP(driver drivers.Driver) (string, error) {
 func (h *SSHFSHostFolder) ensureSFTPDDaemonRunning() error {
 	running := h.isRunning()
 	if running {
-		if glog.V(2) {
-			fmt.Println(fmt.Sprintf("sftpd running with pid %d", h.globalConfig.SftpdPID))
-		}
+		logger.Debugf("sftpd running with pid %d", h.globalConfig.SftpdPID)
 		return nil
 	}
 
-	sftpCmd, err := createSftpCommand()
+	pid, err := process.StartMinishiftDaemon("daemon", "sftpd")
 	if err != nil {
 		return err
 	}
 
-	err = sftpCmd.Start()
+	h.globalConfig.SftpdPID = pid
+	h.globalConfig.Write()
+	return nil
+}
+
+// usesTunnel returns true if the host folder is mounted over a reverse SSH tunnel. Host folders defined before the
+// transport could be chosen connect directly to the host.
+func (h *SSHFSHostFolder) usesTunnel() bool {
+	return h.config.Option(config.Transport) == SSHFSTransportTunnel
+}
+
+// ensureTunnelDaemonRunning starts the daemon serving sftp over a reverse tunnel of the SSH connection to the VM of the
+// current profile, unless it is running already.
+func (h *SSHFSHostFolder) ensureTunnelDaemonRunning() error {
+	if process.IsRunning(minishiftConfig.InstanceStateConfig.SftpTunnelPID) {
+		logger.Debugf("sftp tunnel running with pid %d", minishiftConfig.InstanceStateConfig.SftpTunnelPID)
+		return nil
+	}
+
+	pid, err := process.StartMinishiftDaemon("daemon", "sftp-tunnel", "--profile", constants.ProfileName, "--port", strconv.Itoa(SftpPort))
 	if err != nil {
 		return err
 	}
 
-	h.globalConfig.SftpdPID = sftpCmd.Process.Pid
-	h.globalConfig.Write()
-	return nil
+	minishiftConfig.InstanceStateConfig.SftpTunnelPID = pid
+	return minishiftConfig.InstanceStateConfig.Write()
+}
+
+// waitForTunnel waits for the tunnel daemon to listen on the sftp port within the VM.
+func (h *SSHFSHostFolder) waitForTunnel(driver drivers.Driver) error {
+	cmd := fmt.Sprintf("sudo netstat -ltn | grep -q '%s:%d '", tunnelHost, SftpPort)
+	waitForListener := func() error {
+		if _, err := drivers.RunSSHCommandFromDriver(driver, cmd); err != nil {
+			return &util.RetriableError{Err: fmt.Errorf("the sftp tunnel to the VM is not available")}
+		}
+		return nil
+	}
+
+	return util.RetryAfter(10, waitForListener, time.Second)
 }
 
 func (h *SSHFSHostFolder) ensureRSAKeyExists(driver drivers.Driver) error {
@@ -186,45 +270,5 @@ func (h *SSHFSHostFolder) cacheRSAKeys(driver drivers.Driver) error {
 }
 
 func (h *SSHFSHostFolder) isRunning() bool {
-	if h.globalConfig.SftpdPID <= 0 {
-		return false
-	}
-
-	process, err := goos.FindProcess(h.globalConfig.SftpdPID)
-	if err != nil {
-		return false
-	}
-
-	// for Windows FindProcess is enough
-	if runtime.GOOS == "windows" {
-		return true
-	}
-
-	// for non Windows we need to send a signal to get more information
-	err = process.Signal(syscall.Signal(0))
-	if err == nil {
-		return true
-	} else {
-		return false
-	}
-}
-
-func createSftpCommand() (*exec.Cmd, error) {
-	cmd, err := os.CurrentExecutable()
-	if err != nil {
-		return nil, err
-	}
-
-	args := []string{
-		"daemon",
-		"sftpd"}
-	exportCmd := exec.Command(cmd, args...)
-	// don't inherit any file handles
-	exportCmd.Stderr = nil
-	exportCmd.Stdin = nil
-	exportCmd.Stdout = nil
-	exportCmd.SysProcAttr = process.SysProcForBackgroundProcess()
-	exportCmd.Env = process.EnvForBackgroundProcess()
-
-	return exportCmd, nil
+	return process.IsRunning(h.globalConfig.SftpdPID)
 }
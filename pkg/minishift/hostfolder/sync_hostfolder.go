@@ -0,0 +1,183 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/filesync"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/minishift/minishift/pkg/util/os/process"
+)
+
+// SyncHostFolder copies a host directory into the VM instead of mounting it. A background process pushes changes on
+// the host into the VM and pulls the pull paths back to the host.
+type SyncHostFolder struct {
+	config config.HostFolderConfig
+}
+
+func NewSyncHostFolder(config config.HostFolderConfig) HostFolder {
+	return &SyncHostFolder{config: config}
+}
+
+func (h *SyncHostFolder) Config() config.HostFolderConfig {
+	return h.config
+}
+
+func (h *SyncHostFolder) Mount(driver drivers.Driver) error {
+	fmt.Println(fmt.Sprintf("   Synchronizing '%s': '%s' with '%s'",
+		h.config.Name,
+		h.config.Option(config.Source),
+		h.config.MountPoint()))
+
+	client, err := sshutil.NewSSHClient(driver)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	syncer := filesync.New(client, h.config.Option(config.Source), h.config.MountPoint(), SyncOptions(h.config))
+	count, err := syncer.Push()
+	if err != nil {
+		return fmt.Errorf("error occured while synchronizing host folder: %s", err)
+	}
+	logger.Debugf("Copied %d files into the VM", count)
+	if _, err := syncer.Pull(); err != nil {
+		return fmt.Errorf("error occured while synchronizing host folder: %s", err)
+	}
+
+	return h.ensureSyncDaemonRunning()
+}
+
+func (h *SyncHostFolder) Umount(driver drivers.Driver) error {
+	if err := stopSyncDaemon(h.config.Name); err != nil {
+		return fmt.Errorf("error stopping the synchronization of host folder: %s", err)
+	}
+
+	// pick up the output produced in the VM since the last pull of the stopped process
+	client, err := sshutil.NewSSHClient(driver)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	syncer := filesync.New(client, h.config.Option(config.Source), h.config.MountPoint(), SyncOptions(h.config))
+	if _, err := syncer.Pull(); err != nil {
+		return fmt.Errorf("error occured while synchronizing host folder: %s", err)
+	}
+	return nil
+}
+
+// SyncOptions returns the options for synchronizing the specified host folder.
+func SyncOptions(hostFolderConfig config.HostFolderConfig) filesync.Options {
+	return filesync.Options{
+		PullPaths: splitList(hostFolderConfig.Option(config.PullPaths)),
+		Excludes:  splitList(hostFolderConfig.Option(config.Excludes)),
+	}
+}
+
+func (h *SyncHostFolder) ensureSyncDaemonRunning() error {
+	if isSyncRunning(h.config.Name) {
+		logger.Debugf("sync of '%s' running with pid %d", h.config.Name, minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs[h.config.Name])
+		return nil
+	}
+
+	pid, err := process.StartMinishiftDaemon(syncArgs(h.config)...)
+	if err != nil {
+		return err
+	}
+
+	if minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs == nil {
+		minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs = make(map[string]int)
+	}
+	minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs[h.config.Name] = pid
+	return minishiftConfig.InstanceStateConfig.Write()
+}
+
+// isSyncRunning returns true if the process synchronizing the host folder with the specified name is running
+func isSyncRunning(name string) bool {
+	return process.IsRunning(minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs[name])
+}
+
+func stopSyncDaemon(name string) error {
+	if err := process.Kill(minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs[name]); err != nil {
+		return err
+	}
+
+	delete(minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs, name)
+	return minishiftConfig.InstanceStateConfig.Write()
+}
+
+// SuspendSyncs stops the processes synchronizing the sync host folders, for example because the VM gets stopped. The
+// host folders are kept in the state, so that RestoreMounts resumes their synchronization on the next start.
+func SuspendSyncs() error {
+	for name, pid := range minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs {
+		if err := process.Kill(pid); err != nil {
+			return err
+		}
+		minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs[name] = 0
+	}
+	return minishiftConfig.InstanceStateConfig.Write()
+}
+
+// StopSyncs stops the processes synchronizing the sync host folders and forgets about them.
+func StopSyncs() error {
+	for name := range minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs {
+		if err := stopSyncDaemon(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wasSyncSuspended returns true if the synchronization of the host folder with the specified name was suspended
+// via SuspendSyncs
+func wasSyncSuspended(name string) bool {
+	pid, ok := minishiftConfig.InstanceStateConfig.HostFolderSyncPIDs[name]
+	return ok && pid == 0
+}
+
+func syncArgs(hostFolderConfig config.HostFolderConfig) []string {
+	return []string{
+		"daemon",
+		"hostfolder-sync",
+		"--profile",
+		constants.ProfileName,
+		"--source",
+		hostFolderConfig.Option(config.Source),
+		"--target",
+		hostFolderConfig.MountPoint(),
+		"--pull",
+		hostFolderConfig.Option(config.PullPaths),
+		"--exclude",
+		hostFolderConfig.Option(config.Excludes)}
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,232 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/pkg/minikube/assets"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/minishift/minishift/pkg/util"
+)
+
+const (
+	// HealthOK indicates that the host folder is mounted
+	HealthOK = "OK"
+	// HealthPending indicates that the host folder gets mounted by systemd on the next access
+	HealthPending = "Pending"
+	// HealthFailed indicates that the last attempt of systemd to mount the host folder failed
+	HealthFailed = "Failed"
+	// HealthUnknown indicates that the host folder is not mounted and not managed by systemd
+	HealthUnknown = "-"
+
+	systemdUnitDir   = "/etc/systemd/system"
+	mountUnitDir     = "/var/lib/minishift/hostfolder-units"
+	mountTimeoutSecs = 30
+	mountRetries     = 5
+	mountRetryDelay  = 2 * time.Second
+)
+
+// mountUnits are the systemd mount and automount units of a host folder within the VM. The automount unit makes
+// systemd mount the host folder again on access after a failed mount. /etc/systemd/system does not survive a reboot
+// of the live ISO, hence the units are also kept in mountUnitDir, which marks the host folders to mount again on the
+// next start. The units are rendered again on start, since the IP of the host might have changed.
+type mountUnits struct {
+	name    string
+	what    string
+	where   string
+	fsType  string
+	options []string
+}
+
+func newMountUnits(name string, where string) *mountUnits {
+	return &mountUnits{name: name, where: path.Clean(where)}
+}
+
+func (u *mountUnits) mountName() string {
+	return mountUnitName(u.where)
+}
+
+func (u *mountUnits) automountName() string {
+	return strings.TrimSuffix(u.mountName(), ".mount") + ".automount"
+}
+
+func (u *mountUnits) mountUnit() string {
+	return fmt.Sprintf(`[Unit]
+Description=Minishift host folder %s
+Wants=network-online.target
+After=network-online.target
+
+[Mount]
+What=%s
+Where=%s
+Type=%s
+Options=%s
+TimeoutSec=%d
+`, escapeUnitValue(u.name), escapeUnitValue(u.what), escapeUnitValue(u.where), u.fsType, escapeUnitValue(strings.Join(u.options, ",")), mountTimeoutSecs)
+}
+
+func (u *mountUnits) automountUnit() string {
+	return fmt.Sprintf(`[Unit]
+Description=Automount of Minishift host folder %s
+
+[Automount]
+Where=%s
+
+[Install]
+WantedBy=multi-user.target
+`, escapeUnitValue(u.name), escapeUnitValue(u.where))
+}
+
+// escapeUnitValue escapes the '%' of a value of a unit file, which systemd would otherwise expand as specifier
+func escapeUnitValue(value string) string {
+	return strings.Replace(value, "%", "%%", -1)
+}
+
+// Assets returns the unit files which need to be copied into the VM.
+func (u *mountUnits) Assets() []assets.CopyableFile {
+	return []assets.CopyableFile{
+		assets.NewMemoryAsset([]byte(u.mountUnit()), mountUnitDir, u.mountName(), "0644"),
+		assets.NewMemoryAsset([]byte(u.automountUnit()), mountUnitDir, u.automountName(), "0644"),
+	}
+}
+
+// install copies the units into the VM, starts the automount unit and mounts the host folder. Mounting is retried,
+// since mounts occasionally fail while the network of the VM settles.
+func (u *mountUnits) install(driver drivers.Driver) error {
+	client, err := sshutil.NewSSHClient(driver)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for _, asset := range u.Assets() {
+		if err := sshutil.TransferFile(asset, client); err != nil {
+			return fmt.Errorf("error copying the systemd unit '%s': %s", asset.GetTargetName(), err)
+		}
+	}
+
+	cmd := fmt.Sprintf("sudo cp -f %s %s %s && sudo systemctl daemon-reload && sudo systemctl start %s",
+		sshutil.Quote(path.Join(mountUnitDir, u.mountName())),
+		sshutil.Quote(path.Join(mountUnitDir, u.automountName())),
+		systemdUnitDir,
+		sshutil.Quote(u.automountName()))
+	if _, err := drivers.RunSSHCommandFromDriver(driver, cmd); err != nil {
+		return fmt.Errorf("error enabling the systemd unit '%s': %s", u.automountName(), err)
+	}
+
+	start := func() error {
+		cmd := fmt.Sprintf("sudo systemctl start %s", sshutil.Quote(u.mountName()))
+		if _, err := drivers.RunSSHCommandFromDriver(driver, cmd); err != nil {
+			return &util.RetriableError{Err: fmt.Errorf("error occured while mounting host folder: %s", err)}
+		}
+		return nil
+	}
+	return util.RetryAfter(mountRetries, start, mountRetryDelay)
+}
+
+// remove unmounts the host folder and removes its units from the VM. Host folders mounted before the units were
+// used are unmounted as well.
+func (u *mountUnits) remove(driver drivers.Driver) error {
+	cmd := fmt.Sprintf("sudo systemctl disable --now %s 2> /dev/null; sudo systemctl stop %s 2> /dev/null; "+
+		"if mountpoint -q %s; then sudo umount %s; fi && sudo rm -f %s %s %s %s && sudo systemctl daemon-reload",
+		sshutil.Quote(u.automountName()),
+		sshutil.Quote(u.mountName()),
+		sshutil.Quote(u.where),
+		sshutil.Quote(u.where),
+		sshutil.Quote(path.Join(systemdUnitDir, u.automountName())),
+		sshutil.Quote(path.Join(systemdUnitDir, u.mountName())),
+		sshutil.Quote(path.Join(mountUnitDir, u.automountName())),
+		sshutil.Quote(path.Join(mountUnitDir, u.mountName())))
+	if _, err := drivers.RunSSHCommandFromDriver(driver, cmd); err != nil {
+		return fmt.Errorf("error during umounting of host folder: %s", err)
+	}
+	return nil
+}
+
+// health returns the health of the host folder as reported by systemd.
+func (u *mountUnits) health(driver drivers.Driver) string {
+	cmd := fmt.Sprintf("sudo systemctl is-active %s %s || true", sshutil.Quote(u.mountName()), sshutil.Quote(u.automountName()))
+	out, err := drivers.RunSSHCommandFromDriver(driver, cmd)
+	if err != nil {
+		return HealthUnknown
+	}
+
+	states := strings.Fields(out)
+	if len(states) != 2 {
+		return HealthUnknown
+	}
+	return mountHealth(states[0], states[1])
+}
+
+// installedAutomountUnits returns the names of the automount units kept in mountUnitDir.
+func installedAutomountUnits(driver drivers.Driver) ([]string, error) {
+	out, err := drivers.RunSSHCommandFromDriver(driver, fmt.Sprintf("ls -1 %s 2> /dev/null || true", mountUnitDir))
+	if err != nil {
+		return nil, err
+	}
+
+	var units []string
+	for _, name := range strings.Fields(out) {
+		if strings.HasSuffix(name, ".automount") {
+			units = append(units, name)
+		}
+	}
+	return units, nil
+}
+
+// mountHealth determines the health of a host folder from the active states of its mount and automount unit.
+func mountHealth(mountState string, automountState string) string {
+	switch {
+	case mountState == "active":
+		return HealthOK
+	case mountState == "failed" || automountState == "failed":
+		return HealthFailed
+	case mountState == "activating" || automountState == "active":
+		return HealthPending
+	default:
+		return HealthUnknown
+	}
+}
+
+// mountUnitName returns the name systemd requires for the mount unit of the mount point, as 'systemd-escape -p
+// --suffix=mount' does.
+func mountUnitName(mountPoint string) string {
+	trimmed := strings.Trim(path.Clean(mountPoint), "/")
+	if trimmed == "" {
+		return "-.mount"
+	}
+
+	var name bytes.Buffer
+	for i := 0; i < len(trimmed); i++ {
+		c := trimmed[i]
+		switch {
+		case c == '/':
+			name.WriteByte('-')
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '.' && i > 0:
+			name.WriteByte(c)
+		default:
+			name.WriteString(fmt.Sprintf("\\x%02x", c))
+		}
+	}
+	return name.String() + ".mount"
+}
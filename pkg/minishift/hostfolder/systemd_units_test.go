@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostfolder
+
+import (
+	"testing"
+
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/hostfolder/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mount_unit_names(t *testing.T) {
+	assert.Equal(t, "mnt-sda1-src.mount", mountUnitName("/mnt/sda1/src/"))
+	assert.Equal(t, "home-docker-my\\x2dproject.mount", mountUnitName("/home/docker/my-project"))
+
+	units := newMountUnits("src", "/mnt/sda1/src/")
+	assert.Equal(t, "mnt-sda1-src.mount", units.mountName())
+	assert.Equal(t, "mnt-sda1-src.automount", units.automountName())
+}
+
+func Test_mount_unit_assets(t *testing.T) {
+	units := newMountUnits("src", "/mnt/sda1/src")
+	units.what = "192.168.42.1:/home/john/src"
+	units.fsType = "nfs"
+	units.options = []string{"vers=3", "ro"}
+
+	files := units.Assets()
+	assert.Len(t, files, 2)
+	assert.Equal(t, mountUnitDir, files[0].GetTargetDir())
+	assert.Equal(t, "mnt-sda1-src.mount", files[0].GetTargetName())
+	assert.Equal(t, "mnt-sda1-src.automount", files[1].GetTargetName())
+
+	assert.Contains(t, units.mountUnit(), "What=192.168.42.1:/home/john/src\n")
+	assert.Contains(t, units.mountUnit(), "Options=vers=3,ro\n")
+	assert.Contains(t, units.mountUnit(), "After=network-online.target\n")
+	assert.Contains(t, units.automountUnit(), "Where=/mnt/sda1/src\n")
+	assert.Contains(t, units.automountUnit(), "WantedBy=multi-user.target\n")
+}
+
+func Test_mount_health(t *testing.T) {
+	var testCases = []struct {
+		mountState     string
+		automountState string
+		expectedHealth string
+	}{
+		{"active", "active", HealthOK},
+		{"active", "inactive", HealthOK},
+		{"failed", "active", HealthFailed},
+		{"inactive", "failed", HealthFailed},
+		{"inactive", "active", HealthPending},
+		{"activating", "inactive", HealthPending},
+		{"inactive", "inactive", HealthUnknown},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedHealth, mountHealth(testCase.mountState, testCase.automountState),
+			"Unexpected health for mount state '%s' and automount state '%s'", testCase.mountState, testCase.automountState)
+	}
+}
+
+func Test_sshfs_mount_units(t *testing.T) {
+	options, err := sshfsMountOptions("-o compression=no -ocache=yes,kernel_cache -C")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"compression=no", "cache=yes", "kernel_cache", "compression=yes"}, options)
+
+	options, err = sshfsMountOptions("")
+	assert.NoError(t, err)
+	assert.Empty(t, options)
+
+	_, err = sshfsMountOptions("-o ro -f")
+	assert.EqualError(t, err, "unsupported sshfs option '-f'. Only '-o' options and '-C' are supported")
+	assert.Error(t, ValidateSSHFSOptions("--debug"))
+
+	hostFolder := &SSHFSHostFolder{config: config.HostFolderConfig{
+		Name: "src",
+		Type: SSHFS.String(),
+		Options: map[string]string{
+			config.Source:       "/home/john/src",
+			config.MountPoint:   "/mnt/sda1/src",
+			config.ExtraOptions: "-o 'compression=no'",
+		},
+	}}
+	units, err := hostFolder.mountUnits(tunnelHost)
+	assert.NoError(t, err)
+	unit := units.mountUnit()
+	assert.Contains(t, unit, "What=docker@127.0.0.1:/home/john/src\n")
+	assert.Contains(t, unit, "Type=fuse.sshfs\n")
+	assert.Contains(t, unit, ",port=2022,_netdev,compression=no\n")
+}
+
+func Test_cifs_mount_units(t *testing.T) {
+	minishiftConfig.InstanceStateConfig = &minishiftConfig.InstanceStateConfigType{}
+
+	hostFolder := &CifsHostFolder{config: config.HostFolderConfig{
+		Name: "share",
+		Type: CIFS.String(),
+		Options: map[string]string{
+			config.UncPath:      "//192.168.99.1/share",
+			config.MountPoint:   "/mnt/sda1/share",
+			config.ExtraOptions: "uid=1000",
+		},
+	}}
+	unit := hostFolder.mountUnits(hostFolder.credentialsFile()).mountUnit()
+	assert.Contains(t, unit, "What=//192.168.99.1/share\n")
+	assert.Contains(t, unit, "Type=cifs\n")
+	assert.Contains(t, unit, "Options=credentials=/var/lib/minishift/hostfolders/share.credentials,uid=1000\n")
+}
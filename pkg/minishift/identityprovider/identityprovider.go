@@ -0,0 +1,201 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identityprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minikube/assets"
+)
+
+const (
+	AllowAll = "allow-all"
+	Htpasswd = "htpasswd"
+	LDAP     = "ldap"
+
+	// HtpasswdFileName is the name of the htpasswd file next to the master configuration
+	HtpasswdFileName = "users.htpasswd"
+
+	bindPasswordFileName = "ldap-bind-password"
+	caFileName           = "ldap-ca.crt"
+)
+
+var (
+	// SupportedProviders lists the identity providers which can be configured
+	SupportedProviders = []string{AllowAll, Htpasswd, LDAP}
+
+	providerNames = map[string]string{
+		AllowAll: "anypassword",
+		Htpasswd: "htpasswd",
+		LDAP:     "ldap",
+	}
+)
+
+// IdentityProvider describes the identity provider of the OpenShift cluster.
+type IdentityProvider struct {
+	Kind string
+
+	// HtpasswdFile is the htpasswd file on the host to use for the htpasswd identity provider
+	HtpasswdFile string
+
+	LDAPURL          string
+	LDAPBindDN       string
+	LDAPBindPassword string
+	// LDAPCAFile is the CA bundle on the host used to verify the certificate of the LDAP server
+	LDAPCAFile string
+}
+
+type identityProviderPatch struct {
+	OAuthConfig struct {
+		IdentityProviders []identityProviderConfig `json:"identityProviders"`
+	} `json:"oauthConfig"`
+}
+
+type identityProviderConfig struct {
+	Name          string                 `json:"name"`
+	Challenge     bool                   `json:"challenge"`
+	Login         bool                   `json:"login"`
+	MappingMethod string                 `json:"mappingMethod"`
+	Provider      map[string]interface{} `json:"provider"`
+}
+
+// IsSupported returns true if the specified identity provider is supported, false otherwise.
+func IsSupported(kind string) bool {
+	_, ok := providerNames[kind]
+	return ok
+}
+
+// ProviderName returns the name under which the identities of the specified identity provider are created.
+func ProviderName(kind string) string {
+	return providerNames[kind]
+}
+
+// ValidateLDAPURL returns an error if the specified URL is not an RFC 2255 ldap:// or ldaps:// URL.
+func ValidateLDAPURL(ldapURL string) error {
+	u, err := url.Parse(ldapURL)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+		return fmt.Errorf("'%s' is not a valid LDAP URL. Expected ldap://host:port/basedn?attribute or ldaps://host:port/basedn?attribute", ldapURL)
+	}
+	return nil
+}
+
+// Validate checks that the settings required by the kind of the identity provider are specified.
+func (p *IdentityProvider) Validate() error {
+	if !IsSupported(p.Kind) {
+		return fmt.Errorf("Identity provider '%s' is not supported. Possible values: %v", p.Kind, SupportedProviders)
+	}
+	if p.Kind == LDAP {
+		if p.LDAPURL == "" {
+			return fmt.Errorf("The LDAP identity provider requires an LDAP URL")
+		}
+		if err := ValidateLDAPURL(p.LDAPURL); err != nil {
+			return err
+		}
+		if p.LDAPBindPassword != "" && p.LDAPBindDN == "" {
+			return fmt.Errorf("An LDAP bind password requires a bind DN")
+		}
+	}
+	return nil
+}
+
+// Patch returns the master configuration patch replacing the identity providers of the cluster with this one.
+func (p *IdentityProvider) Patch() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	provider := map[string]interface{}{"apiVersion": "v1"}
+	switch p.Kind {
+	case AllowAll:
+		provider["kind"] = "AllowAllPasswordIdentityProvider"
+	case Htpasswd:
+		provider["kind"] = "HTPasswdPasswordIdentityProvider"
+		provider["file"] = HtpasswdFileName
+	case LDAP:
+		provider["kind"] = "LDAPPasswordIdentityProvider"
+		provider["url"] = p.LDAPURL
+		provider["attributes"] = map[string][]string{
+			"id":                {"dn"},
+			"email":             {"mail"},
+			"name":              {"cn"},
+			"preferredUsername": {"uid"},
+		}
+		provider["insecure"] = strings.HasPrefix(p.LDAPURL, "ldap://") && p.LDAPCAFile == ""
+		if p.LDAPBindDN != "" {
+			provider["bindDN"] = p.LDAPBindDN
+		}
+		if p.LDAPBindPassword != "" {
+			provider["bindPassword"] = map[string]string{"file": bindPasswordFileName}
+		}
+		if p.LDAPCAFile != "" {
+			provider["ca"] = caFileName
+		}
+	}
+
+	patch := identityProviderPatch{}
+	patch.OAuthConfig.IdentityProviders = []identityProviderConfig{{
+		Name:          ProviderName(p.Kind),
+		Challenge:     true,
+		Login:         true,
+		MappingMethod: "claim",
+		Provider:      provider,
+	}}
+	content, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Assets returns the files the identity provider references, targeted at the specified master configuration
+// directory inside the VM. For the htpasswd identity provider, extraHtpasswd entries are appended to the
+// content of the htpasswd file.
+func (p *IdentityProvider) Assets(targetDir string, extraHtpasswd string) ([]assets.CopyableFile, error) {
+	var files []assets.CopyableFile
+	switch p.Kind {
+	case Htpasswd:
+		var content []byte
+		if p.HtpasswdFile != "" {
+			var err error
+			content, err = ioutil.ReadFile(p.HtpasswdFile)
+			if err != nil {
+				return nil, fmt.Errorf("Error reading htpasswd file: %v", err)
+			}
+			if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+				content = append(content, '\n')
+			}
+		}
+		content = append(content, []byte(extraHtpasswd)...)
+		files = append(files, assets.NewMemoryAsset(content, targetDir, HtpasswdFileName, "0600"))
+	case LDAP:
+		if p.LDAPBindPassword != "" {
+			files = append(files, assets.NewMemoryAsset([]byte(p.LDAPBindPassword), targetDir, bindPasswordFileName, "0600"))
+		}
+		if p.LDAPCAFile != "" {
+			content, err := ioutil.ReadFile(p.LDAPCAFile)
+			if err != nil {
+				return nil, fmt.Errorf("Error reading LDAP CA file: %v", err)
+			}
+			files = append(files, assets.NewMemoryAsset(content, targetDir, caFileName, "0644"))
+		}
+	}
+	return files, nil
+}
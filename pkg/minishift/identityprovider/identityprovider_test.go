@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identityprovider
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_allow_all_patch(t *testing.T) {
+	provider := &IdentityProvider{Kind: AllowAll}
+	patch, err := provider.Patch()
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"oauthConfig":{"identityProviders":[{"name":"anypassword","challenge":true,"login":true,"mappingMethod":"claim","provider":{"apiVersion":"v1","kind":"AllowAllPasswordIdentityProvider"}}]}}`, patch)
+}
+
+func Test_ldap_patch_references_secrets(t *testing.T) {
+	provider := &IdentityProvider{
+		Kind:             LDAP,
+		LDAPURL:          "ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid",
+		LDAPBindDN:       "cn=minishift,dc=example,dc=com",
+		LDAPBindPassword: "s3cret",
+		LDAPCAFile:       "ca.crt",
+	}
+	patch, err := provider.Patch()
+
+	assert.NoError(t, err)
+	assert.Contains(t, patch, `"kind":"LDAPPasswordIdentityProvider"`)
+	assert.Contains(t, patch, `"bindPassword":{"file":"ldap-bind-password"}`)
+	assert.Contains(t, patch, `"ca":"ldap-ca.crt"`)
+	assert.Contains(t, patch, `"insecure":false`)
+	assert.NotContains(t, patch, "s3cret")
+}
+
+func Test_invalid_providers_are_rejected(t *testing.T) {
+	for _, provider := range []*IdentityProvider{
+		{Kind: "github"},
+		{Kind: LDAP},
+		{Kind: LDAP, LDAPURL: "http://ldap.example.com"},
+		{Kind: LDAP, LDAPURL: "ldap://ldap.example.com", LDAPBindPassword: "s3cret"},
+	} {
+		_, err := provider.Patch()
+		assert.Error(t, err, "Expected error for %v", provider)
+	}
+}
+
+func Test_htpasswd_assets_include_declared_users(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minishift-test-identityprovider-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	htpasswdFile := filepath.Join(dir, "users.htpasswd")
+	assert.NoError(t, ioutil.WriteFile(htpasswdFile, []byte("jane:{SHA}abc"), 0600))
+
+	provider := &IdentityProvider{Kind: Htpasswd, HtpasswdFile: htpasswdFile}
+	files, err := provider.Assets("/var/lib/minishift/base/kube-apiserver", "john:{SHA}def\n")
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	content, err := ioutil.ReadAll(files[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "jane:{SHA}abc\njohn:{SHA}def\n", string(content))
+	assert.Equal(t, HtpasswdFileName, files[0].GetTargetName())
+	assert.Equal(t, "0600", files[0].GetPermissions())
+	assert.Equal(t, int64(len(content)), files[0].GetLength())
+}
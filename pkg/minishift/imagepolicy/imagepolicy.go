@@ -0,0 +1,108 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/signature"
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// PolicyDirInVM is the directory of the containers policy.json inside the VM
+	PolicyDirInVM = "/etc/containers"
+	// RegistriesDirInVM is the directory holding the signature storage configuration of the registries inside the VM
+	RegistriesDirInVM = "/etc/containers/registries.d"
+
+	policyFileName = "policy.json"
+)
+
+// ValidatePolicy returns an error if the specified file is not a valid containers policy.
+func ValidatePolicy(path string) error {
+	if _, err := signature.NewPolicyFromFile(path); err != nil {
+		return fmt.Errorf("'%s' is not a valid image signature policy: %v", path, err)
+	}
+	return nil
+}
+
+// ValidateRegistriesDir returns an error if the specified path is not a directory.
+func ValidateRegistriesDir(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", path)
+	}
+	return nil
+}
+
+// Apply copies the image signature policy and the registry configuration files into the VM. Either path can be empty.
+func Apply(driver drivers.Driver, policyPath string, registriesDir string) error {
+	if policyPath == "" && registriesDir == "" {
+		return nil
+	}
+
+	client, err := sshutil.NewSSHClient(driver)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if policyPath != "" {
+		if err := ValidatePolicy(policyPath); err != nil {
+			return err
+		}
+		if err := transferFile(policyPath, PolicyDirInVM, policyFileName, client); err != nil {
+			return err
+		}
+	}
+
+	if registriesDir != "" {
+		files, err := RegistryConfigFiles(registriesDir)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := transferFile(file, RegistriesDirInVM, filepath.Base(file), client); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegistryConfigFiles returns the signature storage configuration files contained in the specified directory.
+func RegistryConfigFiles(registriesDir string) ([]string, error) {
+	if err := ValidateRegistriesDir(registriesDir); err != nil {
+		return nil, err
+	}
+	return filepath.Glob(filepath.Join(registriesDir, "*.yaml"))
+}
+
+func transferFile(path string, remoteDir string, name string, client *ssh.Client) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return sshutil.Transfer(bytes.NewReader(content), int64(len(content)), remoteDir, name, "0644", client)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicy = `{
+  "default": [{"type": "reject"}],
+  "transports": {
+    "docker": {
+      "registry.access.redhat.com": [{"type": "signedBy", "keyType": "GPGKeys", "keyPath": "/etc/pki/rpm-gpg/RPM-GPG-KEY-redhat-release"}]
+    }
+  }
+}`
+
+func Test_Validate_Policy(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-imagepolicy-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	validPolicy := filepath.Join(testDir, "policy.json")
+	assert.NoError(t, ioutil.WriteFile(validPolicy, []byte(testPolicy), 0644))
+	assert.NoError(t, ValidatePolicy(validPolicy))
+
+	invalidPolicy := filepath.Join(testDir, "invalid.json")
+	assert.NoError(t, ioutil.WriteFile(invalidPolicy, []byte(`{"default": [{"type": "foo"}]}`), 0644))
+	assert.Error(t, ValidatePolicy(invalidPolicy))
+
+	assert.Error(t, ValidatePolicy(filepath.Join(testDir, "missing.json")))
+}
+
+func Test_Registry_Config_Files(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-imagepolicy-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	for _, name := range []string{"redhat.yaml", "default.yaml", "README"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(testDir, name), []byte("docker:"), 0644))
+	}
+
+	files, err := RegistryConfigFiles(testDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(testDir, "default.yaml"), filepath.Join(testDir, "redhat.yaml")}, files)
+
+	_, err = RegistryConfigFiles(filepath.Join(testDir, "redhat.yaml"))
+	assert.Error(t, err)
+}
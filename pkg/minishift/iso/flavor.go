@@ -0,0 +1,175 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	stringUtils "github.com/minishift/minishift/pkg/util/strings"
+)
+
+const (
+	InitSystemSystemd = "systemd"
+	InitSystemSysV    = "sysvinit"
+
+	PackageManagerYum  = "yum"
+	PackageManagerDnf  = "dnf"
+	PackageManagerNone = "none"
+
+	// CustomFlavorName is the flavor name of an ISO image specified by its URL rather than an alias
+	CustomFlavorName = "custom"
+)
+
+var (
+	ValidInitSystems     = []string{InitSystemSystemd, InitSystemSysV}
+	ValidPackageManagers = []string{PackageManagerDnf, PackageManagerNone, PackageManagerYum}
+
+	// CentOsDockerVersions are the Docker engine versions the CentOS ISO can switch to, the first one is shipped with the ISO
+	CentOsDockerVersions = []string{"1.13.1", "18.06.3", "18.09.9", "19.03.5"}
+)
+
+// Flavor is a named ISO image together with the capabilities provisioning relies on
+type Flavor struct {
+	Name           string
+	URL            string
+	InitSystem     string
+	PackageManager string
+	DockerVersions []string
+	BuiltIn        bool
+}
+
+// builtInFlavors returns the ISO flavors shipped with Minishift
+func builtInFlavors() []Flavor {
+	return []Flavor{
+		{
+			Name:           minishiftConstants.B2dIsoAlias,
+			URL:            constants.DefaultB2dIsoUrl,
+			InitSystem:     InitSystemSysV,
+			PackageManager: PackageManagerNone,
+			BuiltIn:        true,
+		},
+		{
+			Name:           minishiftConstants.CentOsIsoAlias,
+			URL:            constants.DefaultCentOsIsoUrl,
+			InitSystem:     InitSystemSystemd,
+			PackageManager: PackageManagerYum,
+			DockerVersions: CentOsDockerVersions,
+			BuiltIn:        true,
+		},
+	}
+}
+
+// Flavors returns the built-in ISO flavors followed by the ones added with 'minishift iso add-alias', sorted by name.
+func Flavors() []Flavor {
+	flavors := builtInFlavors()
+	if minishiftConfig.AllInstancesConfig == nil {
+		return flavors
+	}
+
+	var custom []Flavor
+	for alias, flavor := range minishiftConfig.AllInstancesConfig.IsoFlavors {
+		custom = append(custom, Flavor{
+			Name:           alias,
+			URL:            flavor.URL,
+			InitSystem:     flavor.InitSystem,
+			PackageManager: flavor.PackageManager,
+			DockerVersions: flavor.DockerVersions,
+		})
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+	return append(flavors, custom...)
+}
+
+// Lookup returns the flavor with the specified alias. Aliases are case insensitive.
+func Lookup(alias string) (Flavor, bool) {
+	for _, flavor := range Flavors() {
+		if strings.EqualFold(flavor.Name, alias) {
+			return flavor, true
+		}
+	}
+	return Flavor{}, false
+}
+
+// ValidateDockerVersion returns an error if the flavor cannot install the specified Docker engine version
+func (f Flavor) ValidateDockerVersion(version string) error {
+	if len(f.DockerVersions) == 0 {
+		return fmt.Errorf("The ISO flavor '%s' does not support selecting the Docker version", f.Name)
+	}
+	if !stringUtils.Contains(f.DockerVersions, version) {
+		return fmt.Errorf("Docker version '%s' is not supported by the ISO flavor '%s'. Possible values: %v", version, f.Name, f.DockerVersions)
+	}
+	return nil
+}
+
+// ForISO returns the flavor of the specified ISO alias or URL. An URL which does not belong to any flavor results in
+// a custom flavor with unknown capabilities.
+func ForISO(aliasOrURL string) Flavor {
+	if flavor, found := Lookup(aliasOrURL); found {
+		return flavor
+	}
+	for _, flavor := range Flavors() {
+		if flavor.URL == aliasOrURL {
+			return flavor
+		}
+	}
+	return Flavor{Name: CustomFlavorName, URL: aliasOrURL}
+}
+
+// AddAlias adds or replaces the user-defined flavor with the specified alias
+func AddAlias(alias string, flavor minishiftConfig.IsoFlavor) error {
+	if alias == "" || strings.Contains(alias, "/") || strings.HasSuffix(alias, ".iso") {
+		return fmt.Errorf("'%s' is not a valid alias. Aliases must neither contain '/' nor end with '.iso'", alias)
+	}
+	if strings.EqualFold(alias, CustomFlavorName) {
+		return fmt.Errorf("'%s' is reserved for ISO images specified by URL", alias)
+	}
+	if existing, found := Lookup(alias); found && existing.BuiltIn {
+		return fmt.Errorf("'%s' is a built-in ISO flavor and cannot be redefined", alias)
+	}
+	if !stringUtils.Contains(ValidInitSystems, flavor.InitSystem) {
+		return fmt.Errorf("Init system '%s' is not supported. Possible values: %v", flavor.InitSystem, ValidInitSystems)
+	}
+	if !stringUtils.Contains(ValidPackageManagers, flavor.PackageManager) {
+		return fmt.Errorf("Package manager '%s' is not supported. Possible values: %v", flavor.PackageManager, ValidPackageManagers)
+	}
+	if len(flavor.DockerVersions) > 0 && flavor.PackageManager == PackageManagerNone {
+		return fmt.Errorf("Selecting the Docker version requires a package manager")
+	}
+	for _, version := range flavor.DockerVersions {
+		if err := docker.ValidateEngineVersion(version); err != nil {
+			return err
+		}
+	}
+	if _, found := Lookup(flavor.URL); found {
+		return fmt.Errorf("'%s' is an alias, not the URL of an ISO image", flavor.URL)
+	}
+	if err := minishiftConfig.IsValidISOUrl("url", flavor.URL); err != nil {
+		return err
+	}
+
+	if minishiftConfig.AllInstancesConfig.IsoFlavors == nil {
+		minishiftConfig.AllInstancesConfig.IsoFlavors = map[string]minishiftConfig.IsoFlavor{}
+	}
+	minishiftConfig.AllInstancesConfig.IsoFlavors[alias] = flavor
+	return minishiftConfig.AllInstancesConfig.Write()
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlavors(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-iso-flavor-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	minishiftConfig.AllInstancesConfig, err = minishiftConfig.NewAllInstancesConfig(filepath.Join(testDir, "allinstances.json"))
+	assert.NoError(t, err)
+	defer func() { minishiftConfig.AllInstancesConfig = nil }()
+
+	err = AddAlias("centos", minishiftConfig.IsoFlavor{URL: "https://example.com/centos.iso", InitSystem: InitSystemSystemd, PackageManager: PackageManagerYum})
+	assert.EqualError(t, err, "'centos' is a built-in ISO flavor and cannot be redefined")
+
+	err = AddAlias("fedora", minishiftConfig.IsoFlavor{URL: "https://example.com/fedora.iso", InitSystem: "upstart", PackageManager: PackageManagerDnf})
+	assert.EqualError(t, err, "Init system 'upstart' is not supported. Possible values: [systemd sysvinit]")
+
+	err = AddAlias("fedora", minishiftConfig.IsoFlavor{URL: "https://example.com/fedora.iso", InitSystem: InitSystemSystemd, PackageManager: PackageManagerNone, DockerVersions: []string{"18.09.9"}})
+	assert.EqualError(t, err, "Selecting the Docker version requires a package manager")
+
+	err = AddAlias("fedora", minishiftConfig.IsoFlavor{URL: "https://example.com/fedora.iso", InitSystem: InitSystemSystemd, PackageManager: PackageManagerDnf, DockerVersions: []string{"18.09.9"}})
+	assert.NoError(t, err)
+
+	var names []string
+	for _, flavor := range Flavors() {
+		names = append(names, flavor.Name)
+	}
+	assert.Equal(t, []string{"b2d", "centos", "fedora"}, names)
+
+	flavor := ForISO("Fedora")
+	assert.Equal(t, "https://example.com/fedora.iso", flavor.URL)
+	assert.Equal(t, PackageManagerDnf, flavor.PackageManager)
+	assert.False(t, flavor.BuiltIn)
+	assert.NoError(t, flavor.ValidateDockerVersion("18.09.9"))
+	assert.EqualError(t, flavor.ValidateDockerVersion("19.03.5"), "Docker version '19.03.5' is not supported by the ISO flavor 'fedora'. Possible values: [18.09.9]")
+
+	flavor = ForISO(constants.DefaultCentOsIsoUrl)
+	assert.Equal(t, "centos", flavor.Name)
+	assert.Equal(t, InitSystemSystemd, flavor.InitSystem)
+	assert.NoError(t, flavor.ValidateDockerVersion("1.13.1"))
+
+	flavor = ForISO("b2d")
+	assert.EqualError(t, flavor.ValidateDockerVersion("1.13.1"), "The ISO flavor 'b2d' does not support selecting the Docker version")
+
+	flavor = ForISO("https://example.com/other.iso")
+	assert.Equal(t, CustomFlavorName, flavor.Name)
+	assert.Empty(t, flavor.InitSystem)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/util/download"
+	"golang.org/x/crypto/openpgp"
+)
+
+const (
+	// ChecksumsFileName is the name of the file published next to the ISO images listing their SHA-256 checksums
+	ChecksumsFileName = "SHA256SUMS"
+
+	// ChecksumFileExtension is the extension of the file next to the cached ISO holding its SHA-256 checksum
+	ChecksumFileExtension = ".sha256"
+)
+
+// signatureExtensions are the extensions of the detached signature of the checksums file, armored or binary
+var signatureExtensions = []string{".asc", ".sig"}
+
+// PublishedChecksum returns the SHA-256 checksum published for the ISO at isoURL. The checksum is looked up in the
+// SHA256SUMS file next to the ISO and, for ISOs published the old way, in the '.sha256' file of the ISO. If
+// signingKey is set, the SHA256SUMS file must carry a detached signature made with the key in this file. If no
+// checksum is published, an empty string is returned.
+func PublishedChecksum(isoURL string, signingKey string) (string, error) {
+	checksumsURL := fmt.Sprintf("%s/%s", isoURL[:strings.LastIndex(isoURL, "/")], ChecksumsFileName)
+	checksums, err := fetch(checksumsURL)
+	if err != nil {
+		return "", err
+	}
+
+	if checksums == nil {
+		if signingKey != "" {
+			return "", fmt.Errorf("No signed checksums found at '%s'", checksumsURL)
+		}
+		legacy, err := fetch(isoURL + ChecksumFileExtension)
+		if err != nil || legacy == nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(legacy)), nil
+	}
+
+	if signingKey != "" {
+		if err := verifySignature(checksumsURL, checksums, signingKey); err != nil {
+			return "", err
+		}
+	}
+
+	checksum, found := ParseChecksums(checksums, path.Base(isoURL))
+	if !found {
+		return "", fmt.Errorf("'%s' does not list a checksum for '%s'", checksumsURL, path.Base(isoURL))
+	}
+	return checksum, nil
+}
+
+// ParseChecksums returns the checksum of the file with the specified name from the content of a checksums file in
+// the format of sha256sum.
+func ParseChecksums(content []byte, name string) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
+
+// VerifySignature checks the detached signature, armored or binary, of content against the public keys in the
+// armored key ring.
+func VerifySignature(content []byte, signature []byte, keyRing io.Reader) error {
+	keys, err := openpgp.ReadArmoredKeyRing(keyRing)
+	if err != nil {
+		return fmt.Errorf("Error reading the signing key: %v", err)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN")) {
+		_, err = openpgp.CheckArmoredDetachedSignature(keys, bytes.NewReader(content), bytes.NewReader(signature))
+	} else {
+		_, err = openpgp.CheckDetachedSignature(keys, bytes.NewReader(content), bytes.NewReader(signature))
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid signature: %v", err)
+	}
+	return nil
+}
+
+// RecordChecksum records the checksum of the cached ISO for later verification.
+func RecordChecksum(isoPath string, checksum string) error {
+	return ioutil.WriteFile(isoPath+ChecksumFileExtension, []byte(checksum), 0644)
+}
+
+// VerifyCached checks the cached ISO against the checksum recorded when it was downloaded. ISOs cached by older
+// Minishift releases have no checksum recorded, they are verified against the checksum published for the ISO at
+// isoURL or one of its mirrors instead. If the published checksum cannot be fetched and no signing key is configured,
+// the ISO is used unverified and verified again on the next start.
+func VerifyCached(isoPath string, isoURL string, signingKey string) error {
+	actual, err := Sha256Sum(isoPath)
+	if err != nil {
+		return err
+	}
+
+	expected, err := ioutil.ReadFile(isoPath + ChecksumFileExtension)
+	if os.IsNotExist(err) {
+		return verifyUnrecorded(isoPath, actual, isoURL, signingKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(string(expected)) != actual {
+		return fmt.Errorf("checksum mismatch - expected: %s, actual: %s", strings.TrimSpace(string(expected)), actual)
+	}
+	return nil
+}
+
+func verifyUnrecorded(isoPath string, actual string, isoURL string, signingKey string) error {
+	var errs []string
+	for _, location := range download.Locations(isoURL) {
+		published, err := PublishedChecksum(location, signingKey)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if published != "" && published != actual {
+			return fmt.Errorf("checksum mismatch - expected: %s, actual: %s", published, actual)
+		}
+		// without published checksum, a new download could not be verified either
+		return RecordChecksum(isoPath, actual)
+	}
+
+	err := fmt.Errorf("No checksum recorded and the published checksum is not available: %s", strings.Join(errs, "; "))
+	if signingKey != "" {
+		return err
+	}
+	fmt.Println(fmt.Sprintf("   WARN: Unable to verify the cached ISO '%s'. %v", isoPath, err))
+	return nil
+}
+
+// Sha256Sum returns the hex encoded SHA-256 checksum of the file.
+func Sha256Sum(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func verifySignature(checksumsURL string, checksums []byte, signingKey string) error {
+	keyRing, err := os.Open(signingKey)
+	if err != nil {
+		return fmt.Errorf("Error opening the signing key: %v", err)
+	}
+	defer keyRing.Close()
+
+	for _, extension := range signatureExtensions {
+		signature, err := fetch(checksumsURL + extension)
+		if err != nil {
+			return err
+		}
+		if signature == nil {
+			continue
+		}
+		if err := VerifySignature(checksums, signature, keyRing); err != nil {
+			return fmt.Errorf("Error verifying '%s': %v", checksumsURL, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("No signature found for '%s'", checksumsURL)
+}
+
+// fetch returns the content at url, or nil if the server does not provide it
+func fetch(url string) ([]byte, error) {
+	response, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, nil
+	}
+	return ioutil.ReadAll(response.Body)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iso
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+const checksums = `3f1e7c1b1f4c2c2a9d4ad1c0a0a4d0b0b6c5a5e2f0f9a7b1c3e4d5f6a7b8c9d0  minishift-centos7.iso
+0000000000000000000000000000000000000000000000000000000000000000 *minishift-b2d.iso
+`
+
+func TestParseChecksums(t *testing.T) {
+	checksum, found := ParseChecksums([]byte(checksums), "minishift-centos7.iso")
+	assert.True(t, found)
+	assert.Equal(t, "3f1e7c1b1f4c2c2a9d4ad1c0a0a4d0b0b6c5a5e2f0f9a7b1c3e4d5f6a7b8c9d0", checksum)
+
+	_, found = ParseChecksums([]byte(checksums), "minishift-b2d.iso")
+	assert.True(t, found, "Binary mode marker should be ignored")
+
+	_, found = ParseChecksums([]byte(checksums), "other.iso")
+	assert.False(t, found)
+}
+
+func TestPublishedChecksum(t *testing.T) {
+	files := map[string]string{"/v1.15.0/SHA256SUMS": checksums, "/legacy/minishift-centos7.iso.sha256": "abc\n"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer server.Close()
+
+	checksum, err := PublishedChecksum(server.URL+"/v1.15.0/minishift-centos7.iso", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "3f1e7c1b1f4c2c2a9d4ad1c0a0a4d0b0b6c5a5e2f0f9a7b1c3e4d5f6a7b8c9d0", checksum)
+
+	_, err = PublishedChecksum(server.URL+"/v1.15.0/other.iso", "")
+	assert.Error(t, err)
+
+	checksum, err = PublishedChecksum(server.URL+"/legacy/minishift-centos7.iso", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", checksum)
+
+	checksum, err = PublishedChecksum(server.URL+"/none/minishift-centos7.iso", "")
+	assert.NoError(t, err)
+	assert.Empty(t, checksum)
+
+	_, err = PublishedChecksum(server.URL+"/legacy/minishift-centos7.iso", "signing-key.asc")
+	assert.Error(t, err, "Unsigned checksums should be rejected if a signing key is configured")
+}
+
+func TestVerifySignature(t *testing.T) {
+	entity, err := openpgp.NewEntity("Minishift", "test", "minishift@example.com", nil)
+	assert.NoError(t, err)
+
+	var keyRing bytes.Buffer
+	w, err := armor.Encode(&keyRing, openpgp.PrivateKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.SerializePrivate(w, nil))
+	w.Close()
+
+	var signature bytes.Buffer
+	assert.NoError(t, openpgp.ArmoredDetachSign(&signature, entity, bytes.NewReader([]byte(checksums)), nil))
+
+	assert.NoError(t, VerifySignature([]byte(checksums), signature.Bytes(), bytes.NewReader(keyRing.Bytes())))
+	assert.Error(t, VerifySignature([]byte("tampered"), signature.Bytes(), bytes.NewReader(keyRing.Bytes())))
+}
+
+func TestVerifyCached(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minishift-test-iso-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer server.Close()
+	isoURL := server.URL + "/v1.15.0/minishift-centos7.iso"
+
+	isoPath := filepath.Join(dir, "minishift-centos7.iso")
+	assert.NoError(t, ioutil.WriteFile(isoPath, []byte("iso"), 0644))
+
+	files["/v1.15.0/SHA256SUMS"] = "0000000000000000000000000000000000000000000000000000000000000000  minishift-centos7.iso\n"
+	assert.Error(t, VerifyCached(isoPath, isoURL, ""), "An ISO without recorded checksum should be verified against the published checksum")
+	_, err = os.Stat(isoPath + ChecksumFileExtension)
+	assert.True(t, os.IsNotExist(err), "The checksum of an ISO failing verification should not be recorded")
+
+	actual, err := Sha256Sum(isoPath)
+	assert.NoError(t, err)
+	files["/v1.15.0/SHA256SUMS"] = actual + "  minishift-centos7.iso\n"
+	assert.NoError(t, VerifyCached(isoPath, isoURL, ""))
+	assert.FileExists(t, isoPath+ChecksumFileExtension)
+
+	delete(files, "/v1.15.0/SHA256SUMS")
+	assert.NoError(t, VerifyCached(isoPath, isoURL, ""), "The recorded checksum should be used")
+
+	assert.NoError(t, ioutil.WriteFile(isoPath, []byte("corrupted"), 0644))
+	assert.Error(t, VerifyCached(isoPath, isoURL, ""))
+}
@@ -0,0 +1,98 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"os"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// ContextPrefix is the prefix of the kubeconfig contexts managed by Minishift
+	ContextPrefix = "minishift/"
+)
+
+// ContextName returns the name of the kubeconfig context of the specified profile.
+func ContextName(profile string) string {
+	return ContextPrefix + profile
+}
+
+// IsMinishiftContext returns true if the context is managed by Minishift. Older Minishift releases named the context
+// after the profile, such a context is considered to be managed as well.
+func IsMinishiftContext(context string, profile string) bool {
+	return strings.HasPrefix(context, ContextPrefix) || context == profile
+}
+
+// RenameContext renames the context of profile old to the context of profile new, keeping it as current context if it
+// was current before. Returns true if the kubeconfig changed.
+func RenameContext(kubeConfig *clientcmdapi.Config, old string, new string) bool {
+	context, exists := kubeConfig.Contexts[ContextName(old)]
+	if !exists {
+		return false
+	}
+
+	delete(kubeConfig.Contexts, ContextName(old))
+	kubeConfig.Contexts[ContextName(new)] = context
+	if kubeConfig.CurrentContext == ContextName(old) {
+		kubeConfig.CurrentContext = ContextName(new)
+	}
+	return true
+}
+
+// CurrentContext returns the current context of the kubeconfig file or an empty string if the file does not exist.
+func CurrentContext(kubeConfigPath string) (string, error) {
+	if _, err := os.Stat(kubeConfigPath); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+	if err != nil {
+		return "", err
+	}
+	return kubeConfig.CurrentContext, nil
+}
+
+// RestoreContext switches the current context from the context of the profile back to the previous context.
+// If the user switched to another context in the meantime, the kubeconfig file is left untouched.
+// If the previous context does not exist anymore the current context is unset. Returns true if the current context changed.
+func RestoreContext(kubeConfigPath string, profile string, previous string) (bool, error) {
+	if _, err := os.Stat(kubeConfigPath); os.IsNotExist(err) {
+		return false, nil
+	}
+
+	kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+	if err != nil {
+		return false, err
+	}
+
+	if kubeConfig.CurrentContext != ContextName(profile) {
+		return false, nil
+	}
+
+	if _, exists := kubeConfig.Contexts[previous]; !exists {
+		previous = ""
+	}
+	kubeConfig.CurrentContext = previous
+
+	if err := clientcmd.WriteToFile(*kubeConfig, kubeConfigPath); err != nil {
+		return false, err
+	}
+	return true, nil
+}
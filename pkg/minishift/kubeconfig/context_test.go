@@ -0,0 +1,105 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Minishift_Contexts(t *testing.T) {
+	assert.Equal(t, "minishift/dev", ContextName("dev"))
+	assert.True(t, IsMinishiftContext("minishift/dev", "minishift"))
+	assert.True(t, IsMinishiftContext("minishift", "minishift"))
+	assert.False(t, IsMinishiftContext("production", "minishift"))
+}
+
+func Test_Previous_Context_Is_Restored(t *testing.T) {
+	testDir, kubeConfigPath := writeKubeConfig(t, ContextName("minishift"), "production", ContextName("minishift"))
+	defer os.RemoveAll(testDir)
+
+	current, err := CurrentContext(kubeConfigPath)
+	assert.NoError(t, err)
+	assert.Equal(t, ContextName("minishift"), current)
+
+	restored, err := RestoreContext(kubeConfigPath, "minishift", "production")
+	assert.NoError(t, err)
+	assert.True(t, restored)
+
+	current, _ = CurrentContext(kubeConfigPath)
+	assert.Equal(t, "production", current)
+
+	restored, err = RestoreContext(kubeConfigPath, "minishift", "production")
+	assert.NoError(t, err)
+	assert.False(t, restored, "A context switched to by the user should not be changed")
+}
+
+func Test_Current_Context_Is_Unset_If_Previous_Context_Is_Gone(t *testing.T) {
+	testDir, kubeConfigPath := writeKubeConfig(t, ContextName("minishift"), ContextName("minishift"))
+	defer os.RemoveAll(testDir)
+
+	restored, err := RestoreContext(kubeConfigPath, "minishift", "production")
+	assert.NoError(t, err)
+	assert.True(t, restored)
+
+	current, _ := CurrentContext(kubeConfigPath)
+	assert.Empty(t, current)
+}
+
+func Test_Missing_KubeConfig_Is_Ignored(t *testing.T) {
+	current, err := CurrentContext(filepath.Join(os.TempDir(), "minishift-does-not-exist"))
+	assert.NoError(t, err)
+	assert.Empty(t, current)
+
+	restored, err := RestoreContext(filepath.Join(os.TempDir(), "minishift-does-not-exist"), "minishift", "production")
+	assert.NoError(t, err)
+	assert.False(t, restored)
+}
+
+func Test_Context_Is_Renamed_With_Profile(t *testing.T) {
+	kubeConfig := clientcmdapi.NewConfig()
+	kubeConfig.Contexts[ContextName("foo")] = &clientcmdapi.Context{Cluster: "cluster", AuthInfo: "user"}
+	kubeConfig.CurrentContext = ContextName("foo")
+
+	assert.True(t, RenameContext(kubeConfig, "foo", "bar"))
+	assert.Equal(t, ContextName("bar"), kubeConfig.CurrentContext)
+	assert.Contains(t, kubeConfig.Contexts, ContextName("bar"))
+	assert.NotContains(t, kubeConfig.Contexts, ContextName("foo"))
+
+	assert.False(t, RenameContext(kubeConfig, "foo", "bar"))
+}
+
+func writeKubeConfig(t *testing.T, current string, contexts ...string) (string, string) {
+	testDir, err := ioutil.TempDir("", "minishift-test-kubeconfig-")
+	assert.NoError(t, err)
+
+	kubeConfig := clientcmdapi.NewConfig()
+	for _, context := range contexts {
+		kubeConfig.Contexts[context] = &clientcmdapi.Context{Cluster: "cluster", AuthInfo: "user"}
+	}
+	kubeConfig.CurrentContext = current
+
+	kubeConfigPath := filepath.Join(testDir, "config")
+	assert.NoError(t, clientcmd.WriteToFile(*kubeConfig, kubeConfigPath))
+	return testDir, kubeConfigPath
+}
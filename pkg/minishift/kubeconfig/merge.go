@@ -0,0 +1,203 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// EntryKind identifies the type of an entry in a kubeconfig file
+type EntryKind string
+
+const (
+	ClusterEntry EntryKind = "cluster"
+	UserEntry    EntryKind = "user"
+	ContextEntry EntryKind = "context"
+)
+
+// EntryKey returns the key under which the kubeconfig entry of the given kind is tracked.
+func EntryKey(kind EntryKind, name string) string {
+	return fmt.Sprintf("kubeconfig-%s:%s", kind, name)
+}
+
+// ClusterName returns the name 'oc login' uses for the cluster entry of the API server with the specified IP and port.
+func ClusterName(ip string, port int) string {
+	return fmt.Sprintf("%s:%d", strings.Replace(ip, ".", "-", -1), port)
+}
+
+// Merge adds the clusters, users and contexts of the profile's kubeconfig to the user's kubeconfig. Entries of the
+// user's kubeconfig which are not part of the profile's kubeconfig, the current context, the preferences and the
+// extensions are kept as they are. Cluster names are derived from the IP of the cluster, hence an entry of the same
+// name might belong to another profile or to a third party. Such an entry is only replaced if its key is one of the
+// owned keys, otherwise it is kept and its key is returned as conflicting.
+func Merge(existing *clientcmdapi.Config, profileConfig *clientcmdapi.Config, owned []string) (*clientcmdapi.Config, []string) {
+	merged := clientcmdapi.NewConfig()
+	if existing != nil {
+		merged.APIVersion = existing.APIVersion
+		merged.Kind = existing.Kind
+		merged.Preferences = existing.Preferences
+		merged.CurrentContext = existing.CurrentContext
+		copyEntries(merged, existing)
+		for name, extension := range existing.Extensions {
+			merged.Extensions[name] = extension
+		}
+	}
+
+	if profileConfig == nil {
+		return merged, nil
+	}
+
+	var conflicts []string
+	for _, key := range ConflictingEntryKeys(merged, profileConfig) {
+		if !minishiftStrings.Contains(owned, key) {
+			conflicts = append(conflicts, key)
+		}
+	}
+
+	for name, cluster := range profileConfig.Clusters {
+		if !minishiftStrings.Contains(conflicts, EntryKey(ClusterEntry, name)) {
+			merged.Clusters[name] = cluster
+		}
+	}
+	for name, authInfo := range profileConfig.AuthInfos {
+		if !minishiftStrings.Contains(conflicts, EntryKey(UserEntry, name)) {
+			merged.AuthInfos[name] = authInfo
+		}
+	}
+	for name, context := range profileConfig.Contexts {
+		if !minishiftStrings.Contains(conflicts, EntryKey(ContextEntry, name)) {
+			merged.Contexts[name] = context
+		}
+	}
+	return merged, conflicts
+}
+
+// ConflictingEntryKeys returns the keys of the entries of the profile's kubeconfig for which the kubeconfig contains
+// an entry of the same name with different content, in sorted order.
+func ConflictingEntryKeys(kubeConfig *clientcmdapi.Config, profileConfig *clientcmdapi.Config) []string {
+	var keys []string
+	for name, cluster := range profileConfig.Clusters {
+		if existing, exists := kubeConfig.Clusters[name]; exists && !equalClusters(existing, cluster) {
+			keys = append(keys, EntryKey(ClusterEntry, name))
+		}
+	}
+	for name, authInfo := range profileConfig.AuthInfos {
+		if existing, exists := kubeConfig.AuthInfos[name]; exists && !equalAuthInfos(existing, authInfo) {
+			keys = append(keys, EntryKey(UserEntry, name))
+		}
+	}
+	for name, context := range profileConfig.Contexts {
+		if existing, exists := kubeConfig.Contexts[name]; exists && !equalContexts(existing, context) {
+			keys = append(keys, EntryKey(ContextEntry, name))
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// ProfileEntryKeys returns the keys of the entries created for the cluster with the specified name, either by
+// 'oc login' or by Minishift. Contexts created by the user for the cluster are not included.
+func ProfileEntryKeys(kubeConfig *clientcmdapi.Config, clusterName string, profile string) []string {
+	var keys []string
+	if _, exists := kubeConfig.Clusters[clusterName]; exists {
+		keys = append(keys, EntryKey(ClusterEntry, clusterName))
+	}
+
+	for name := range kubeConfig.AuthInfos {
+		// 'oc login' names users '<user>/<cluster>'
+		if strings.HasSuffix(name, "/"+clusterName) {
+			keys = append(keys, EntryKey(UserEntry, name))
+		}
+	}
+
+	for name, context := range kubeConfig.Contexts {
+		if context.Cluster != clusterName {
+			continue
+		}
+		// 'oc login' names contexts '<namespace>/<cluster>/<user>'
+		if name == ContextName(profile) || strings.Contains(name, "/"+clusterName+"/") {
+			keys = append(keys, EntryKey(ContextEntry, name))
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// RemoveEntries removes the entries with the specified keys from the kubeconfig. If the current context is removed,
+// the current context is unset.
+func RemoveEntries(kubeConfig *clientcmdapi.Config, keys []string) {
+	for _, key := range keys {
+		for _, kind := range []EntryKind{ClusterEntry, UserEntry, ContextEntry} {
+			prefix := EntryKey(kind, "")
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+
+			name := strings.TrimPrefix(key, prefix)
+			switch kind {
+			case ClusterEntry:
+				delete(kubeConfig.Clusters, name)
+			case UserEntry:
+				delete(kubeConfig.AuthInfos, name)
+			case ContextEntry:
+				delete(kubeConfig.Contexts, name)
+				if kubeConfig.CurrentContext == name {
+					kubeConfig.CurrentContext = ""
+				}
+			}
+		}
+	}
+}
+
+func copyEntries(target *clientcmdapi.Config, source *clientcmdapi.Config) {
+	for name, cluster := range source.Clusters {
+		target.Clusters[name] = cluster
+	}
+	for name, authInfo := range source.AuthInfos {
+		target.AuthInfos[name] = authInfo
+	}
+	for name, context := range source.Contexts {
+		target.Contexts[name] = context
+	}
+}
+
+// The location of origin is set when loading a kubeconfig file and is not part of the entry itself
+func equalClusters(a *clientcmdapi.Cluster, b *clientcmdapi.Cluster) bool {
+	x, y := *a, *b
+	x.LocationOfOrigin, y.LocationOfOrigin = "", ""
+	return reflect.DeepEqual(x, y)
+}
+
+func equalAuthInfos(a *clientcmdapi.AuthInfo, b *clientcmdapi.AuthInfo) bool {
+	x, y := *a, *b
+	x.LocationOfOrigin, y.LocationOfOrigin = "", ""
+	return reflect.DeepEqual(x, y)
+}
+
+func equalContexts(a *clientcmdapi.Context, b *clientcmdapi.Context) bool {
+	x, y := *a, *b
+	x.LocationOfOrigin, y.LocationOfOrigin = "", ""
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Merge_Keeps_Existing_Entries(t *testing.T) {
+	existing := testConfig("production", "prod-cluster", "admin/prod-cluster", "production")
+	existing.Preferences.Colors = true
+	profile := testConfig("default/192-168-42-10:8443/system:admin", "192-168-42-10:8443", "system:admin/192-168-42-10:8443", "default/192-168-42-10:8443/system:admin")
+
+	merged, conflicts := Merge(existing, profile, nil)
+
+	assert.Empty(t, conflicts)
+
+	assert.Equal(t, "production", merged.CurrentContext)
+	assert.True(t, merged.Preferences.Colors)
+	assert.Len(t, merged.Clusters, 2)
+	assert.Len(t, merged.AuthInfos, 2)
+	assert.Len(t, merged.Contexts, 2)
+}
+
+func Test_Merge_Keeps_Conflicting_Entries(t *testing.T) {
+	clusterName := ClusterName("192.168.42.10", 8443)
+	existing := testConfig("other", clusterName, "system:admin/"+clusterName, "other")
+	existing.Clusters[clusterName].Server = "https://other.example.com:8443"
+	profile := testConfig("default/"+clusterName+"/system:admin", clusterName, "system:admin/"+clusterName, "default/"+clusterName+"/system:admin")
+
+	merged, conflicts := Merge(existing, profile, nil)
+
+	assert.Equal(t, []string{EntryKey(ClusterEntry, clusterName)}, conflicts)
+	assert.Equal(t, "https://other.example.com:8443", merged.Clusters[clusterName].Server)
+	assert.Contains(t, merged.Contexts, "default/"+clusterName+"/system:admin")
+	assert.Equal(t, []string{EntryKey(ClusterEntry, clusterName)}, ConflictingEntryKeys(merged, profile))
+
+	merged, conflicts = Merge(existing, profile, []string{EntryKey(ClusterEntry, clusterName)})
+
+	assert.Empty(t, conflicts)
+	assert.Equal(t, "https://"+clusterName, merged.Clusters[clusterName].Server)
+	assert.Empty(t, ConflictingEntryKeys(merged, profile))
+}
+
+func Test_Profile_Entry_Keys(t *testing.T) {
+	clusterName := ClusterName("192.168.42.10", 8443)
+	assert.Equal(t, "192-168-42-10:8443", clusterName)
+
+	kubeConfig := testConfig("production", "prod-cluster", "admin/prod-cluster", "production")
+	addEntries(kubeConfig, "myproject/"+clusterName+"/developer", clusterName, "developer/"+clusterName)
+	addEntries(kubeConfig, ContextName("minishift"), clusterName, "developer/"+clusterName)
+	addEntries(kubeConfig, "my-context", clusterName, "developer/"+clusterName)
+
+	expected := []string{
+		EntryKey(ClusterEntry, clusterName),
+		EntryKey(ContextEntry, ContextName("minishift")),
+		EntryKey(ContextEntry, "myproject/"+clusterName+"/developer"),
+		EntryKey(UserEntry, "developer/"+clusterName),
+	}
+	assert.Equal(t, expected, ProfileEntryKeys(kubeConfig, clusterName, "minishift"))
+}
+
+func Test_Remove_Entries(t *testing.T) {
+	clusterName := ClusterName("192.168.42.10", 8443)
+	kubeConfig := testConfig("production", "prod-cluster", "admin/prod-cluster", "production")
+	addEntries(kubeConfig, ContextName("minishift"), clusterName, "developer/"+clusterName)
+	kubeConfig.CurrentContext = ContextName("minishift")
+
+	RemoveEntries(kubeConfig, ProfileEntryKeys(kubeConfig, clusterName, "minishift"))
+
+	assert.Equal(t, "", kubeConfig.CurrentContext)
+	assert.Contains(t, kubeConfig.Clusters, "prod-cluster")
+	assert.Contains(t, kubeConfig.AuthInfos, "admin/prod-cluster")
+	assert.Contains(t, kubeConfig.Contexts, "production")
+	assert.Len(t, kubeConfig.Clusters, 1)
+	assert.Len(t, kubeConfig.AuthInfos, 1)
+	assert.Len(t, kubeConfig.Contexts, 1)
+}
+
+func testConfig(currentContext string, cluster string, user string, context string) *clientcmdapi.Config {
+	kubeConfig := clientcmdapi.NewConfig()
+	kubeConfig.CurrentContext = currentContext
+	addEntries(kubeConfig, context, cluster, user)
+	return kubeConfig
+}
+
+func addEntries(kubeConfig *clientcmdapi.Config, context string, cluster string, user string) {
+	kubeConfig.Clusters[cluster] = &clientcmdapi.Cluster{Server: "https://" + cluster}
+	kubeConfig.AuthInfos[user] = &clientcmdapi.AuthInfo{Token: "token"}
+	kubeConfig.Contexts[context] = &clientcmdapi.Context{Cluster: cluster, AuthInfo: user}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"sort"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ReplaceServerIP points the clusters whose server URL refers to oldIP to newIP, keeping scheme and port.
+// Returns the names of the changed clusters.
+func ReplaceServerIP(kubeConfig *clientcmdapi.Config, oldIP string, newIP string) []string {
+	var changed []string
+	for name, cluster := range kubeConfig.Clusters {
+		server, err := url.Parse(cluster.Server)
+		if err != nil || server.Hostname() != oldIP {
+			continue
+		}
+
+		if port := server.Port(); port != "" {
+			server.Host = net.JoinHostPort(newIP, port)
+		} else {
+			server.Host = newIP
+		}
+		cluster.Server = server.String()
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// UpdateServerIP replaces oldIP with newIP in the server URLs of the kubeconfig file. The file is only written if a
+// cluster changed. A missing file is not an error.
+func UpdateServerIP(kubeConfigPath string, oldIP string, newIP string) ([]string, error) {
+	if _, err := os.Stat(kubeConfigPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := ReplaceServerIP(kubeConfig, oldIP, newIP)
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	return changed, clientcmd.WriteToFile(*kubeConfig, kubeConfigPath)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Server_IP_Is_Replaced(t *testing.T) {
+	kubeConfig := clientcmdapi.NewConfig()
+	kubeConfig.Clusters["192-168-99-100:8443"] = &clientcmdapi.Cluster{Server: "https://192.168.99.100:8443"}
+	kubeConfig.Clusters["no-port"] = &clientcmdapi.Cluster{Server: "https://192.168.99.100"}
+	kubeConfig.Clusters["other"] = &clientcmdapi.Cluster{Server: "https://192.168.99.1000:8443"}
+
+	changed := ReplaceServerIP(kubeConfig, "192.168.99.100", "192.168.99.101")
+	assert.Equal(t, []string{"192-168-99-100:8443", "no-port"}, changed)
+	assert.Equal(t, "https://192.168.99.101:8443", kubeConfig.Clusters["192-168-99-100:8443"].Server)
+	assert.Equal(t, "https://192.168.99.101", kubeConfig.Clusters["no-port"].Server)
+	assert.Equal(t, "https://192.168.99.1000:8443", kubeConfig.Clusters["other"].Server)
+}
+
+func Test_Kubeconfig_File_Is_Updated(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-kubeconfig-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	kubeConfigPath := filepath.Join(testDir, "config")
+	changed, err := UpdateServerIP(kubeConfigPath, "192.168.99.100", "192.168.99.101")
+	assert.NoError(t, err, "A missing kubeconfig should be ignored")
+	assert.Empty(t, changed)
+
+	kubeConfig := clientcmdapi.NewConfig()
+	kubeConfig.Clusters["192-168-99-100:8443"] = &clientcmdapi.Cluster{Server: "https://192.168.99.100:8443"}
+	assert.NoError(t, clientcmd.WriteToFile(*kubeConfig, kubeConfigPath))
+
+	changed, err = UpdateServerIP(kubeConfigPath, "192.168.99.100", "192.168.99.101")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192-168-99-100:8443"}, changed)
+
+	kubeConfig, err = clientcmd.LoadFromFile(kubeConfigPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://192.168.99.101:8443", kubeConfig.Clusters["192-168-99-100:8443"].Server)
+}
@@ -112,3 +112,26 @@ func Stop(driver drivers.Driver) (bool, error) {
 
 	return handleHostDNSSettingsAfterStop(ipAddress)
 }
+
+// Reconfigure points the DNS server to the current IP of the VM, for example after the IP changed, and restarts it.
+func Reconfigure(driver drivers.Driver) (bool, error) {
+	sshCommander := provision.GenericSSHCommander{Driver: driver}
+
+	ipAddress, err := driver.GetIP()
+	if err != nil {
+		return false, err
+	}
+
+	// the upstream resolvers in resolv.dnsmasq.conf are still valid, /etc/resolv.conf points to dnsmasq by now
+	routingSuffix := configCmd.GetDefaultRoutingSuffix(ipAddress)
+	if err := writeDnsmasqConfiguration(sshCommander, ipAddress, routingSuffix); err != nil {
+		return false, err
+	}
+
+	if _, err := getServiceCommander(driver).Restart(); err != nil {
+		return false, err
+	}
+
+	// perform host specific settings
+	return handleHostDNSSettingsAfterStart(ipAddress)
+}
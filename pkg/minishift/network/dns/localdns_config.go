@@ -63,20 +63,7 @@ func printDnsmasqConfiguration(dnsmasqConfiguration DnsmasqConfiguration) {
 }
 
 func handleConfiguration(sshCommander provision.SSHCommander, ipAddress string, routingDomain string) (bool, error) {
-
-	dnsmasqConfiguration := DnsmasqConfiguration{
-		Port:                dnsmasqPort,
-		ResolveFilename:     resolveFilename,
-		AdditionalHostsPath: additionalHostsPath,
-		Domain:              "minishift",
-		RoutingDomain:       routingDomain,
-		LocalIP:             ipAddress,
-	}
-	dnsmasqConfigurationFile := fillDnsmasqConfiguration(dnsmasqConfiguration) // perhaps move this to the struct as a ToString()
-	encodedDnsmasqConfigurationFile := base64.StdEncoding.EncodeToString([]byte(dnsmasqConfigurationFile))
-	configCommand := fmt.Sprintf(
-		"echo %s | openssl enc -base64 -d | sudo tee /var/lib/minishift/dnsmasq.conf > /dev/null",
-		encodedDnsmasqConfigurationFile)
+	configCommand := dnsmasqConfigurationCommand(ipAddress, routingDomain)
 
 	execCommand := fmt.Sprintf("sudo mkdir %s && %s && sudo cp /etc/resolv.conf %s",
 		additionalHostsPath,
@@ -98,3 +85,25 @@ func handleConfiguration(sshCommander provision.SSHCommander, ipAddress string,
 
 	return true, nil
 }
+
+// writeDnsmasqConfiguration overwrites the dnsmasq configuration with the one for the specified IP of the VM.
+func writeDnsmasqConfiguration(sshCommander provision.SSHCommander, ipAddress string, routingDomain string) error {
+	_, err := sshCommander.SSHCommand(fmt.Sprintf("sudo mkdir -p %s && %s", additionalHostsPath, dnsmasqConfigurationCommand(ipAddress, routingDomain)))
+	return err
+}
+
+func dnsmasqConfigurationCommand(ipAddress string, routingDomain string) string {
+	dnsmasqConfiguration := DnsmasqConfiguration{
+		Port:                dnsmasqPort,
+		ResolveFilename:     resolveFilename,
+		AdditionalHostsPath: additionalHostsPath,
+		Domain:              "minishift",
+		RoutingDomain:       routingDomain,
+		LocalIP:             ipAddress,
+	}
+	dnsmasqConfigurationFile := fillDnsmasqConfiguration(dnsmasqConfiguration) // perhaps move this to the struct as a ToString()
+	encodedDnsmasqConfigurationFile := base64.StdEncoding.EncodeToString([]byte(dnsmasqConfigurationFile))
+	return fmt.Sprintf(
+		"echo %s | openssl enc -base64 -d | sudo tee /var/lib/minishift/dnsmasq.conf > /dev/null",
+		encodedDnsmasqConfigurationFile)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dockerforward
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/logging"
+	"github.com/minishift/minishift/pkg/util/os/process"
+)
+
+var logger = logging.For("dockerforward")
+
+// EnsureDaemonRunning starts the process forwarding the specified address of the host to the Docker daemon of the VM
+// as background process. A running process forwarding a different address is replaced.
+func EnsureDaemonRunning(bindIP string, port int) error {
+	address := net.JoinHostPort(bindIP, strconv.Itoa(port))
+	if isRunning() {
+		if config.InstanceStateConfig.DockerForwardAddress == address {
+			logger.Debugf("docker forward running with pid %d", config.InstanceStateConfig.DockerForwardPID)
+			return nil
+		}
+		if err := Stop(); err != nil {
+			return err
+		}
+	}
+
+	pid, err := process.StartMinishiftDaemon(
+		"daemon",
+		"docker-forward",
+		"--profile",
+		constants.ProfileName,
+		"--bind-ip",
+		bindIP,
+		"--port",
+		strconv.Itoa(port))
+	if err != nil {
+		return err
+	}
+
+	config.InstanceStateConfig.DockerForwardPID = pid
+	config.InstanceStateConfig.DockerForwardAddress = address
+	return config.InstanceStateConfig.Write()
+}
+
+// Stop stops the process forwarding to the Docker daemon of the VM, if it is running.
+func Stop() error {
+	if err := process.Kill(config.InstanceStateConfig.DockerForwardPID); err != nil {
+		return err
+	}
+
+	config.InstanceStateConfig.DockerForwardPID = 0
+	config.InstanceStateConfig.DockerForwardAddress = ""
+	return config.InstanceStateConfig.Write()
+}
+
+// Restore restarts the process forwarding the recorded address of the host to the Docker daemon of the VM, in case the
+// process terminated. Returns true if the process was restarted.
+func Restore() (bool, error) {
+	address := config.InstanceStateConfig.DockerForwardAddress
+	if address == "" || isRunning() {
+		return false, nil
+	}
+
+	bindIP, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return false, err
+	}
+	bindPort, err := strconv.Atoi(port)
+	if err != nil {
+		return false, err
+	}
+	return true, EnsureDaemonRunning(bindIP, bindPort)
+}
+
+func isRunning() bool {
+	return process.IsRunning(config.InstanceStateConfig.DockerForwardPID)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dockerforward
+
+import (
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultPort is the port the Docker daemon of the VM is exposed on, unless specified otherwise
+	DefaultPort = 2376
+
+	dialTimeout = 10 * time.Second
+)
+
+// Serve accepts connections on the listener and forwards each of them to the Docker daemon at the address returned
+// by target. TLS is not terminated, so clients authenticate against the Docker daemon of the VM directly. Serve
+// returns when accepting connections fails.
+func Serve(listener net.Listener, target func() (string, error)) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go forward(conn, target)
+	}
+}
+
+// DockerAddress returns the address of the Docker daemon of the VM with the specified IP
+func DockerAddress(ip string) string {
+	return net.JoinHostPort(ip, strconv.Itoa(DefaultPort))
+}
+
+func forward(conn net.Conn, target func() (string, error)) {
+	defer conn.Close()
+
+	address, err := target()
+	if err != nil {
+		log.Println("Error determining the address of the Docker daemon:", err)
+		return
+	}
+	upstream, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		log.Println("Error connecting to the Docker daemon:", err)
+		return
+	}
+	defer upstream.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go pipe(upstream, conn, &wg)
+	go pipe(conn, upstream, &wg)
+	wg.Wait()
+}
+
+// pipe copies from src to dst until src is drained and then closes the write side of dst, so that the peer sees
+// the end of the stream while the other direction is still open.
+func pipe(dst net.Conn, src net.Conn, wg *sync.WaitGroup) {
+	defer wg.Done()
+	io.Copy(dst, src)
+	if tcpConn, ok := dst.(*net.TCPConn); ok {
+		tcpConn.CloseWrite()
+	} else {
+		dst.Close()
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dockerforward
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Connections_Are_Forwarded_To_Target(t *testing.T) {
+	upstream := listen(t)
+	defer upstream.Close()
+	go func() {
+		conn, err := upstream.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		request, _ := ioutil.ReadAll(conn)
+		conn.Write(append([]byte("echo: "), request...))
+	}()
+
+	forwarder := listen(t)
+	defer forwarder.Close()
+	go Serve(forwarder, func() (string, error) {
+		return upstream.Addr().String(), nil
+	})
+
+	conn, err := net.Dial("tcp", forwarder.Addr().String())
+	assert.NoError(t, err, "Error connecting to the forwarder")
+	defer conn.Close()
+
+	conn.Write([]byte("ping"))
+	conn.(*net.TCPConn).CloseWrite()
+	response, err := ioutil.ReadAll(conn)
+	assert.NoError(t, err, "Error reading the response")
+	assert.Equal(t, "echo: ping", string(response))
+}
+
+func Test_Connection_Is_Closed_If_Target_Is_Unknown(t *testing.T) {
+	forwarder := listen(t)
+	defer forwarder.Close()
+	go Serve(forwarder, func() (string, error) {
+		return "", errors.New("VM not found")
+	})
+
+	conn, err := net.Dial("tcp", forwarder.Addr().String())
+	assert.NoError(t, err, "Error connecting to the forwarder")
+	defer conn.Close()
+
+	response, err := ioutil.ReadAll(conn)
+	assert.NoError(t, err)
+	assert.Empty(t, response)
+}
+
+func Test_Docker_Address(t *testing.T) {
+	assert.Equal(t, "192.168.99.100:2376", DockerAddress("192.168.99.100"))
+}
+
+func listen(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err, "Error creating listener")
+	return listener
+}
@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipwatch
+
+import (
+	"time"
+
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/logging"
+	"github.com/minishift/minishift/pkg/util/os/process"
+)
+
+var logger = logging.For("ipwatch")
+
+// EnsureDaemonRunning starts the IP watcher as background process, unless it is already running.
+func EnsureDaemonRunning(interval time.Duration) error {
+	if IsRunning() {
+		logger.Debugf("IP watcher running with pid %d", config.InstanceStateConfig.IPWatchPID)
+		return nil
+	}
+
+	pid, err := process.StartMinishiftDaemon(
+		"daemon",
+		"ip-watch",
+		"--profile",
+		constants.ProfileName,
+		"--interval",
+		interval.String())
+	if err != nil {
+		return err
+	}
+
+	config.InstanceStateConfig.IPWatchPID = pid
+	return config.InstanceStateConfig.Write()
+}
+
+// Stop stops the IP watcher, if it is running.
+func Stop() error {
+	if !IsRunning() {
+		return nil
+	}
+
+	if err := process.Kill(config.InstanceStateConfig.IPWatchPID); err != nil {
+		return err
+	}
+
+	config.InstanceStateConfig.IPWatchPID = 0
+	return config.InstanceStateConfig.Write()
+}
+
+// IsRunning returns true if the IP watcher is running in the background.
+func IsRunning() bool {
+	return process.IsRunning(config.InstanceStateConfig.IPWatchPID)
+}
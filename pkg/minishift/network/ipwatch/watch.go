@@ -0,0 +1,94 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipwatch
+
+import (
+	"time"
+)
+
+const (
+	// DefaultInterval is the interval in which the IP of the VM is checked, unless specified otherwise
+	DefaultInterval = 10 * time.Second
+)
+
+// Watcher detects changes of the IP of the VM, for example after the host resumed from sleep or the DHCP lease of
+// the VM was renewed.
+type Watcher struct {
+	lastIP      string
+	lastKnownIP func() string
+	getIP       func() (string, error)
+	reconcile   func(oldIP string, newIP string) error
+}
+
+// New creates a Watcher. lastKnownIP returns the IP recorded for the instance, which is re-read with every check,
+// since starting the VM records the new IP itself. getIP returns the current IP of the VM, reconcile adjusts the
+// configuration referring to the old IP.
+func New(lastKnownIP func() string, getIP func() (string, error), reconcile func(oldIP string, newIP string) error) *Watcher {
+	return &Watcher{lastIP: lastKnownIP(), lastKnownIP: lastKnownIP, getIP: getIP, reconcile: reconcile}
+}
+
+// LastIP returns the last known IP of the VM.
+func (w *Watcher) LastIP() string {
+	return w.lastIP
+}
+
+// Check compares the current IP of the VM with the last known IP and reconciles a change. Returns true if the IP
+// changed and was reconciled. A failed reconciliation is retried with the next check, since the last known IP is
+// only updated once the reconciliation succeeded.
+func (w *Watcher) Check() (bool, error) {
+	ip, err := w.getIP()
+	if err != nil || ip == "" {
+		// the VM is stopped or not reachable yet
+		return false, err
+	}
+
+	// another command, for example 'minishift start', might have taken care of the new IP already
+	if recorded := w.lastKnownIP(); recorded != "" {
+		w.lastIP = recorded
+	}
+	if ip == w.lastIP {
+		return false, nil
+	}
+	if w.lastIP == "" {
+		w.lastIP = ip
+		return false, nil
+	}
+
+	if err := w.reconcile(w.lastIP, ip); err != nil {
+		return false, err
+	}
+	w.lastIP = ip
+	return true, nil
+}
+
+// Watch checks the IP in the specified interval until stop is closed.
+func (w *Watcher) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(); err != nil {
+			logger.Errorf("Error checking the IP of the VM: %v", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipwatch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeVM struct {
+	ip         string
+	err        error
+	reconciled [][2]string
+	failNext   bool
+}
+
+func (vm *fakeVM) getIP() (string, error) {
+	return vm.ip, vm.err
+}
+
+func (vm *fakeVM) reconcile(oldIP string, newIP string) error {
+	if vm.failNext {
+		vm.failNext = false
+		return errors.New("reconcile failed")
+	}
+	vm.reconciled = append(vm.reconciled, [2]string{oldIP, newIP})
+	return nil
+}
+
+func recorded(ip string) func() string {
+	return func() string { return ip }
+}
+
+func Test_Unchanged_IP_Is_Not_Reconciled(t *testing.T) {
+	vm := &fakeVM{ip: "192.168.99.100"}
+	watcher := New(recorded("192.168.99.100"), vm.getIP, vm.reconcile)
+
+	changed, err := watcher.Check()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Empty(t, vm.reconciled)
+}
+
+func Test_Changed_IP_Is_Reconciled(t *testing.T) {
+	vm := &fakeVM{ip: "192.168.99.101"}
+	watcher := New(recorded("192.168.99.100"), vm.getIP, vm.reconcile)
+
+	changed, err := watcher.Check()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, [][2]string{{"192.168.99.100", "192.168.99.101"}}, vm.reconciled)
+	assert.Equal(t, "192.168.99.101", watcher.LastIP())
+}
+
+func Test_First_IP_Is_Recorded_Without_Reconciling(t *testing.T) {
+	vm := &fakeVM{ip: "192.168.99.100"}
+	watcher := New(recorded(""), vm.getIP, vm.reconcile)
+
+	changed, err := watcher.Check()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Empty(t, vm.reconciled)
+	assert.Equal(t, "192.168.99.100", watcher.LastIP())
+}
+
+func Test_Failed_Reconciliation_Is_Retried(t *testing.T) {
+	vm := &fakeVM{ip: "192.168.99.101", failNext: true}
+	watcher := New(recorded("192.168.99.100"), vm.getIP, vm.reconcile)
+
+	_, err := watcher.Check()
+	assert.EqualError(t, err, "reconcile failed")
+	assert.Equal(t, "192.168.99.100", watcher.LastIP())
+
+	changed, err := watcher.Check()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, [][2]string{{"192.168.99.100", "192.168.99.101"}}, vm.reconciled)
+}
+
+func Test_Unreachable_VM_Keeps_Last_IP(t *testing.T) {
+	vm := &fakeVM{err: errors.New("VM stopped")}
+	watcher := New(recorded("192.168.99.100"), vm.getIP, vm.reconcile)
+
+	changed, err := watcher.Check()
+	assert.Error(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "192.168.99.100", watcher.LastIP())
+}
+
+func Test_IP_Recorded_By_Another_Command_Is_Not_Reconciled(t *testing.T) {
+	vm := &fakeVM{ip: "192.168.99.100"}
+	lastKnownIP := "192.168.99.100"
+	watcher := New(func() string { return lastKnownIP }, vm.getIP, vm.reconcile)
+
+	// 'minishift start' restarted the VM with a new IP and reconciled it itself
+	vm.ip = "192.168.99.101"
+	lastKnownIP = "192.168.99.101"
+
+	changed, err := watcher.Check()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Empty(t, vm.reconciled)
+	assert.Equal(t, "192.168.99.101", watcher.LastIP())
+}
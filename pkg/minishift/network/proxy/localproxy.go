@@ -31,13 +31,15 @@ import (
 	"syscall"
 
 	"github.com/elazarl/goproxy"
-	"github.com/golang/glog"
 	"github.com/minishift/minishift/pkg/minishift/config"
 	minishiftTLS "github.com/minishift/minishift/pkg/minishift/tls"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"github.com/minishift/minishift/pkg/util/os"
 	"github.com/minishift/minishift/pkg/util/os/process"
 )
 
+var logger = logging.For("proxy")
+
 const (
 	proxyAuthHeader = "Proxy-Authorization"
 )
@@ -116,9 +118,7 @@ func setCA(caCert, caKey []byte) error {
 
 func EnsureProxyDaemonRunning() error {
 	if isRunning() {
-		if glog.V(2) {
-			fmt.Println(fmt.Sprintf("proxy running with pid %d", config.AllInstancesConfig.ProxyPID))
-		}
+		logger.Debugf("proxy running with pid %d", config.AllInstancesConfig.ProxyPID)
 		return nil
 	}
 
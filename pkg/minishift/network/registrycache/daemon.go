@@ -0,0 +1,145 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrycache
+
+import (
+	"fmt"
+	goos "os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"syscall"
+
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/logging"
+	"github.com/minishift/minishift/pkg/util/os"
+	"github.com/minishift/minishift/pkg/util/os/process"
+	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
+)
+
+var logger = logging.For("registrycache")
+
+// EnsureRegistryCacheDaemonRunning starts the registry cache as background process, unless it is already running.
+// Besides localhost the registry cache listens on the host addresses of all instance networks it has been started
+// for. If hostIP is a new host address, a running registry cache is restarted listening on all of them, so that
+// the instances using the other addresses keep reaching it.
+func EnsureRegistryCacheDaemonRunning(port int, hostIP string) error {
+	hostIPs := config.AllInstancesConfig.RegistryCacheHostIPs
+	if hostIP != "" && !minishiftStrings.Contains(hostIPs, hostIP) {
+		hostIPs = append(hostIPs, hostIP)
+	}
+
+	if isRunning() {
+		if len(hostIPs) == len(config.AllInstancesConfig.RegistryCacheHostIPs) {
+			logger.Debugf("registry cache running with pid %d", config.AllInstancesConfig.RegistryCachePID)
+			return nil
+		}
+		if err := StopRegistryCacheDaemon(); err != nil {
+			return err
+		}
+	}
+
+	registryCacheCmd, err := createRegistryCacheCommand(port, hostIPs)
+	if err != nil {
+		return err
+	}
+
+	err = registryCacheCmd.Start()
+	if err != nil {
+		return err
+	}
+
+	config.AllInstancesConfig.RegistryCachePID = registryCacheCmd.Process.Pid
+	config.AllInstancesConfig.RegistryCacheHostIPs = hostIPs
+	config.AllInstancesConfig.Write()
+	return nil
+}
+
+// StopRegistryCacheDaemon stops the registry cache background process, if it is running.
+func StopRegistryCacheDaemon() error {
+	if !isRunning() {
+		return nil
+	}
+
+	registryCacheProcess, err := goos.FindProcess(config.AllInstancesConfig.RegistryCachePID)
+	if err != nil {
+		return err
+	}
+	if err := registryCacheProcess.Kill(); err != nil {
+		return err
+	}
+
+	config.AllInstancesConfig.RegistryCachePID = 0
+	return config.AllInstancesConfig.Write()
+}
+
+// MirrorURL returns the registry mirror address of the registry cache as seen from within the VM.
+func MirrorURL(port int) string {
+	return fmt.Sprintf("http://%s:%d", HostAlias, port)
+}
+
+// GetPID returns the PID of the registry cache process or 0 if the registry cache is not running.
+func GetPID() int {
+	if isRunning() {
+		return config.AllInstancesConfig.RegistryCachePID
+	}
+	return 0
+}
+
+func isRunning() bool {
+	if config.AllInstancesConfig.RegistryCachePID <= 0 {
+		return false
+	}
+
+	process, err := goos.FindProcess(config.AllInstancesConfig.RegistryCachePID)
+	if err != nil {
+		return false
+	}
+
+	// for Windows FindProcess is enough
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	// for non Windows we need to send a signal to get more information
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+func createRegistryCacheCommand(port int, hostIPs []string) (*exec.Cmd, error) {
+	cmd, err := os.CurrentExecutable()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"daemon",
+		"registry-cache",
+		"--port",
+		strconv.Itoa(port)}
+	for _, hostIP := range hostIPs {
+		args = append(args, "--host-ip", hostIP)
+	}
+	registryCacheCmd := exec.Command(cmd, args...)
+	// don't inherit any file handles
+	registryCacheCmd.Stderr = nil
+	registryCacheCmd.Stdin = nil
+	registryCacheCmd.Stdout = nil
+	registryCacheCmd.SysProcAttr = process.SysProcForBackgroundProcess()
+	registryCacheCmd.Env = process.EnvForBackgroundProcess()
+
+	return registryCacheCmd, nil
+}
@@ -0,0 +1,388 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrycache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// HostAlias is the name under which the registry cache is reachable from within the VM
+	HostAlias = "registrycache"
+	// DefaultUpstream is the registry for which the Docker daemon uses mirrors
+	DefaultUpstream = "https://registry-1.docker.io"
+	// DefaultPort is the port the registry cache listens on if not specified otherwise
+	DefaultPort = 5001
+
+	apiVersionHeader  = "Docker-Distribution-API-Version"
+	contentDigestHdr  = "Docker-Content-Digest"
+	contentTypeSuffix = ".type"
+)
+
+const (
+	// pathComponent, tagPattern and digestPattern follow the grammar of image references of the Docker distribution
+	pathComponent = `[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*`
+	namePattern   = pathComponent + `(?:/` + pathComponent + `)*`
+	tagPattern    = `[\w][\w.-]{0,127}`
+	digestPattern = `sha256:[a-f0-9]{64}`
+)
+
+var (
+	blobPath     = regexp.MustCompile(`^/v2/(` + namePattern + `)/blobs/(` + digestPattern + `)$`)
+	manifestPath = regexp.MustCompile(`^/v2/(` + namePattern + `)/manifests/(` + tagPattern + `|` + digestPattern + `)$`)
+	authParam    = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// RegistryCache is a pull-through cache for a Docker registry. Blobs and manifests fetched from the upstream
+// registry are kept in the cache directory, so that they survive the deletion of the Minishift VM.
+type RegistryCache struct {
+	cacheDir string
+	upstream string
+	client   *http.Client
+
+	tokensLock sync.Mutex
+	tokens     map[string]string
+}
+
+// NewRegistryCache creates a registry cache storing its content in cacheDir and pulling from the specified upstream registry.
+func NewRegistryCache(cacheDir string, upstream string) *RegistryCache {
+	if upstream == "" {
+		upstream = DefaultUpstream
+	}
+	return &RegistryCache{
+		cacheDir: cacheDir,
+		upstream: strings.TrimSuffix(upstream, "/"),
+		client:   http.DefaultClient,
+		tokens:   make(map[string]string),
+	}
+}
+
+// StartRegistryCache serves the registry cache on the specified port of localhost and of the given host addresses on
+// the instance networks. A host address which cannot be bound, for example because the network of a deleted instance
+// is gone, is logged and skipped. The call blocks until the server on localhost fails.
+func StartRegistryCache(port int, hostIPs []string, cacheDir string, upstream string) {
+	cache := NewRegistryCache(cacheDir, upstream)
+	bindAddrs := bindAddresses(port, hostIPs)
+
+	log.Println(fmt.Sprintf("Serving registry cache for %s on %s using %s", cache.upstream, strings.Join(bindAddrs, ", "), cacheDir))
+	for _, bindAddr := range bindAddrs[1:] {
+		go func(bindAddr string) {
+			log.Println(fmt.Sprintf("Error serving registry cache on %s: %v", bindAddr, http.ListenAndServe(bindAddr, cache)))
+		}(bindAddr)
+	}
+	log.Fatal(http.ListenAndServe(bindAddrs[0], cache))
+}
+
+func (c *RegistryCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "The registry cache is read-only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// the handler is not behind a ServeMux, so the path is not cleaned
+	for _, segment := range strings.Split(r.URL.Path, "/") {
+		if segment == ".." {
+			http.Error(w, "Invalid path", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set(apiVersionHeader, "registry/2.0")
+	if r.URL.Path == "/v2/" || r.URL.Path == "/v2" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if match := blobPath.FindStringSubmatch(r.URL.Path); match != nil {
+		c.serveBlob(w, r, match[1], match[2])
+		return
+	}
+
+	if match := manifestPath.FindStringSubmatch(r.URL.Path); match != nil {
+		c.serveManifest(w, r, match[1], match[2])
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+// serveBlob serves the blob from the cache. Blobs are content addressable and hence never need to be refreshed.
+func (c *RegistryCache) serveBlob(w http.ResponseWriter, r *http.Request, name string, digest string) {
+	path := c.blobFile(digest)
+	if _, err := os.Stat(path); err != nil {
+		if err := c.fetchBlob(name, digest, path); err != nil {
+			log.Println(fmt.Sprintf("Error fetching blob %s of %s: %v", digest, name, err))
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set(contentDigestHdr, digest)
+	http.ServeFile(w, r, path)
+}
+
+// serveManifest serves a manifest from upstream, caching it on the way. If the upstream registry cannot be reached,
+// the last cached version of the manifest is served.
+func (c *RegistryCache) serveManifest(w http.ResponseWriter, r *http.Request, name string, reference string) {
+	path := c.manifestFile(name, reference)
+	isDigest := strings.HasPrefix(reference, "sha256:")
+
+	// manifests referenced by digest are immutable
+	if !isDigest || !c.isCached(path) {
+		if err := c.fetchManifest(r, name, reference, path); err != nil {
+			if !c.isCached(path) {
+				log.Println(fmt.Sprintf("Error fetching manifest %s of %s: %v", reference, name, err))
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			log.Println(fmt.Sprintf("Serving cached manifest %s of %s: %v", reference, name, err))
+		}
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	contentType, _ := ioutil.ReadFile(path + contentTypeSuffix)
+
+	w.Header().Set("Content-Type", string(contentType))
+	w.Header().Set(contentDigestHdr, digestOf(content))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(content)
+	}
+}
+
+func (c *RegistryCache) fetchBlob(name string, digest string, path string) error {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/%s/blobs/%s", c.upstream, name, digest), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, name)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Upstream registry returned '%s'", resp.Status)
+	}
+
+	return writeVerified(resp.Body, digest, path)
+}
+
+func (c *RegistryCache) fetchManifest(r *http.Request, name string, reference string, path string) error {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/%s/manifests/%s", c.upstream, name, reference), nil)
+	if err != nil {
+		return err
+	}
+	for _, accept := range r.Header["Accept"] {
+		req.Header.Add("Accept", accept)
+	}
+
+	resp, err := c.do(req, name)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Upstream registry returned '%s'", resp.Status)
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	for _, p := range []string{path, c.manifestFile(name, digestOf(content))} {
+		if err := writeFile(p, content); err != nil {
+			return err
+		}
+		if err := writeFile(p+contentTypeSuffix, []byte(contentType)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// do executes the request against the upstream registry. If the registry requests a bearer token, an anonymous
+// pull token is requested for the repository and the request is retried.
+func (c *RegistryCache) do(req *http.Request, name string) (*http.Response, error) {
+	if token := c.token(name); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	token, err := c.requestToken(challenge, name)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return c.client.Do(req)
+}
+
+func (c *RegistryCache) requestToken(challenge string, name string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("Unsupported authentication challenge '%s'", challenge)
+	}
+
+	params := make(map[string]string)
+	for _, match := range authParam.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("Invalid authentication realm in challenge '%s'", challenge)
+	}
+	query := tokenURL.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", name)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	resp, err := c.client.Get(tokenURL.String())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Token request returned '%s'", resp.Status)
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", err
+	}
+
+	token := tokenResponse.Token
+	if token == "" {
+		token = tokenResponse.AccessToken
+	}
+	c.tokensLock.Lock()
+	c.tokens[name] = token
+	c.tokensLock.Unlock()
+
+	return token, nil
+}
+
+func (c *RegistryCache) token(name string) string {
+	c.tokensLock.Lock()
+	defer c.tokensLock.Unlock()
+	return c.tokens[name]
+}
+
+func (c *RegistryCache) blobFile(digest string) string {
+	return filepath.Join(c.cacheDir, "blobs", strings.Replace(digest, ":", string(filepath.Separator), 1))
+}
+
+func (c *RegistryCache) manifestFile(name string, reference string) string {
+	return filepath.Join(c.cacheDir, "manifests", filepath.FromSlash(name), strings.Replace(reference, ":", "-", 1))
+}
+
+func (c *RegistryCache) isCached(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// writeVerified writes the content of the reader to path, provided the content matches the expected digest.
+// The content is written to a temporary file first, so that concurrent requests never see a partial blob.
+func writeVerified(r io.Reader, digest string, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), ".download-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmpFile, hash), r)
+	tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	if actual := "sha256:" + hex.EncodeToString(hash.Sum(nil)); actual != digest {
+		return fmt.Errorf("Digest mismatch. Expected '%s' but got '%s'", digest, actual)
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// bindAddresses returns the addresses to listen on, localhost first. The registry cache is never exposed on all
+// interfaces, only on localhost and the host addresses through which the VMs reach the host.
+func bindAddresses(port int, hostIPs []string) []string {
+	addrs := []string{net.JoinHostPort("127.0.0.1", strconv.Itoa(port))}
+	for _, hostIP := range hostIPs {
+		if hostIP != "" && !net.ParseIP(hostIP).IsLoopback() {
+			addrs = append(addrs, net.JoinHostPort(hostIP, strconv.Itoa(port)))
+		}
+	}
+	return addrs
+}
+
+func digestOf(content []byte) string {
+	hash := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
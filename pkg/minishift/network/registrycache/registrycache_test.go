@@ -0,0 +1,168 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registrycache
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testManifest     = `{"schemaVersion": 2}`
+	testManifestType = "application/vnd.docker.distribution.manifest.v2+json"
+	testBlob         = "layer-content"
+	testToken        = "secret-token"
+)
+
+type fakeRegistry struct {
+	server   *httptest.Server
+	requests map[string]int
+}
+
+func newFakeRegistry() *fakeRegistry {
+	registry := &fakeRegistry{requests: make(map[string]int)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"token": "%s"}`, testToken)
+	})
+	mux.HandleFunc("/v2/library/busybox/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.test",scope="repository:library/busybox:pull"`, registry.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		registry.requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/v2/library/busybox/manifests/latest":
+			w.Header().Set("Content-Type", testManifestType)
+			fmt.Fprint(w, testManifest)
+		case "/v2/library/busybox/blobs/" + digestOf([]byte(testBlob)):
+			fmt.Fprint(w, testBlob)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	registry.server = httptest.NewServer(mux)
+	return registry
+}
+
+func Test_Blobs_Are_Served_From_Cache(t *testing.T) {
+	cacheDir, cache, upstream := setup(t)
+	defer os.RemoveAll(cacheDir)
+	defer upstream.server.Close()
+
+	blobURL := "/v2/library/busybox/blobs/" + digestOf([]byte(testBlob))
+	for i := 0; i < 2; i++ {
+		resp := get(cache, blobURL)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, testBlob, resp.Body.String())
+	}
+	assert.Equal(t, 1, upstream.requests[blobURL], "Blob should only be fetched once from upstream")
+}
+
+func Test_Blob_With_Wrong_Digest_Is_Not_Cached(t *testing.T) {
+	cacheDir, cache, upstream := setup(t)
+	defer os.RemoveAll(cacheDir)
+	defer upstream.server.Close()
+
+	resp := get(cache, "/v2/library/busybox/blobs/"+digestOf([]byte("other-content")))
+	assert.Equal(t, http.StatusBadGateway, resp.Code)
+}
+
+func Test_Cached_Manifest_Is_Served_When_Upstream_Is_Unavailable(t *testing.T) {
+	cacheDir, cache, upstream := setup(t)
+	defer os.RemoveAll(cacheDir)
+
+	resp := get(cache, "/v2/library/busybox/manifests/latest")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, testManifest, resp.Body.String())
+
+	upstream.server.Close()
+
+	resp = get(cache, "/v2/library/busybox/manifests/latest")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, testManifest, resp.Body.String())
+	assert.Equal(t, testManifestType, resp.Header().Get("Content-Type"))
+	assert.Equal(t, digestOf([]byte(testManifest)), resp.Header().Get(contentDigestHdr))
+
+	resp = get(cache, "/v2/library/busybox/manifests/"+digestOf([]byte(testManifest)))
+	assert.Equal(t, http.StatusOK, resp.Code, "Manifest should be cached by digest as well")
+
+	resp = get(cache, "/v2/library/alpine/manifests/latest")
+	assert.Equal(t, http.StatusBadGateway, resp.Code)
+}
+
+func Test_Registry_Cache_Is_Read_Only(t *testing.T) {
+	cache := NewRegistryCache("", "")
+
+	req := httptest.NewRequest(http.MethodPut, "/v2/library/busybox/manifests/latest", nil)
+	resp := httptest.NewRecorder()
+	cache.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+}
+
+func Test_Registry_Cache_Binds_To_Localhost(t *testing.T) {
+	assert.Equal(t, []string{"127.0.0.1:5001"}, bindAddresses(5001, nil))
+	assert.Equal(t, []string{"127.0.0.1:5001"}, bindAddresses(5001, []string{"127.0.0.1"}))
+	assert.Equal(t, []string{"127.0.0.1:5001", "192.168.99.1:5001", "192.168.42.1:5001"},
+		bindAddresses(5001, []string{"192.168.99.1", "192.168.42.1"}))
+}
+
+func Test_Registry_Cache_Rejects_Invalid_Paths(t *testing.T) {
+	cacheDir, cache, upstream := setup(t)
+	defer os.RemoveAll(cacheDir)
+	defer upstream.server.Close()
+
+	var invalidPaths = []struct {
+		path         string
+		expectedCode int
+	}{
+		{"/v2/../../../x/manifests/y", http.StatusBadRequest},
+		{"/v2/library/busybox/manifests/..", http.StatusBadRequest},
+		{"/v2/library/../busybox/manifests/latest", http.StatusBadRequest},
+		{"/v2/Library/busybox/manifests/latest", http.StatusNotFound},
+		{"/v2/library/busybox/manifests/.hidden", http.StatusNotFound},
+		{"/v2/library/busybox/blobs/sha256:abc", http.StatusNotFound},
+	}
+
+	for _, invalidPath := range invalidPaths {
+		resp := get(cache, invalidPath.path)
+		assert.Equal(t, invalidPath.expectedCode, resp.Code, invalidPath.path)
+	}
+	assert.Empty(t, upstream.requests, "Invalid paths should not be requested upstream")
+}
+
+func setup(t *testing.T) (string, *RegistryCache, *fakeRegistry) {
+	cacheDir, err := ioutil.TempDir("", "minishift-test-registry-cache-")
+	assert.NoError(t, err, "Error creating temp directory")
+
+	upstream := newFakeRegistry()
+	return cacheDir, NewRegistryCache(cacheDir, upstream.server.URL), upstream
+}
+
+func get(cache *RegistryCache, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	resp := httptest.NewRecorder()
+	cache.ServeHTTP(resp, req)
+	return resp
+}
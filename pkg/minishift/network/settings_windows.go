@@ -24,11 +24,13 @@ import (
 	"time"
 
 	hvkvp "github.com/gbraad/go-hvkvp"
-	"github.com/golang/glog"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 	"github.com/minishift/minishift/pkg/minishift/shell/powershell"
+	"github.com/minishift/minishift/pkg/util/logging"
 )
 
+var logger = logging.For("network")
+
 const (
 	resendInterval        = 5 * time.Second
 	resultSuccess         = "4096"
@@ -150,9 +152,7 @@ func doConfigure(success chan bool, command string) {
 	result, _, _ := posh.Execute(command)
 
 	if strings.Contains(result, resultSuccess) {
-		if glog.V(5) {
-			fmt.Printf("*")
-		}
+		logger.Debugf("Networking configuration of the VM succeeded")
 		if successCount > 3 {
 			success <- true
 		}
@@ -181,9 +181,7 @@ func newConfigBasher(bashing bashingFunc, command string) *configbasher {
 func (b *configbasher) start() {
 	go func() {
 		for {
-			if glog.V(5) {
-				fmt.Printf("+")
-			}
+			logger.Debugf("Sending the networking configuration to the VM")
 			b.bashing(b.handler, b.command)
 			time.Sleep(b.interval)
 		}
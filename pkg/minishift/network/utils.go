@@ -33,6 +33,9 @@ import (
 	"github.com/minishift/minishift/pkg/minishift/shell/powershell"
 )
 
+// dnsmasqHostsPath is the file with the additional host entries of the DNS server of the VM, see the dns package
+const dnsmasqHostsPath = "/var/lib/minishift/dnsmasq.hosts"
+
 var VMSwitch string
 
 // This will return the address as used by libmachine
@@ -94,6 +97,27 @@ func AddHostEntryToInstance(driver drivers.Driver, hostname string, ipAddress st
 		"Error adding host entry to instance")
 }
 
+// EnsureHostname sets the hostname of the VM to the machine name, in case the profile got renamed since the VM was provisioned.
+// The host entries of the old name, including those served by the DNS server of the VM, are renamed as well.
+func EnsureHostname(driver drivers.Driver) error {
+	current, err := drivers.RunSSHCommandFromDriver(driver, "hostname")
+	if err != nil {
+		return err
+	}
+
+	current = strings.TrimSpace(current)
+	name := driver.GetMachineName()
+	if current == name || current == "" || current == "localhost" {
+		return nil
+	}
+
+	_, err = drivers.RunSSHCommandFromDriver(driver, fmt.Sprintf(
+		"sudo hostname %[1]s && echo %[1]q | sudo tee /etc/hostname && sudo sed -i 's/\\b%[2]s\\b/%[1]s/g' /etc/hosts && "+
+			"{ [ ! -f %[3]s ] || sudo sed -i 's/\\b%[2]s\\b/%[1]s/g' %[3]s; }",
+		name, current, dnsmasqHostsPath))
+	return err
+}
+
 // AddNameserversToInstance will add additional nameservers to the end of the
 // /etc/resolv.conf file inside the instance.
 func AddNameserversToInstance(driver drivers.Driver, nameservers []string) {
@@ -26,18 +26,23 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/golang/glog"
 	"github.com/minishift/minishift/pkg/minikube/constants"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/kubeconfig"
 	"github.com/minishift/minishift/pkg/util"
 	"github.com/minishift/minishift/pkg/util/cmd"
 	"github.com/minishift/minishift/pkg/util/filehelper"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"github.com/pkg/errors"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"runtime"
 )
 
+var logger = logging.For("oc")
+
+var clusterUpFlagRegex = regexp.MustCompile(`^--([a-z0-9][a-z0-9-]*)(=\S*)?$`)
+
 const (
 	invalidOcPathError         = "The specified path to oc '%s' does not exist"
 	invalidKubeConfigPathError = "The specified path to the kube config '%s' does not exist"
@@ -72,6 +77,19 @@ func (oc *OcRunner) Run(command string, stdOut io.Writer, stdErr io.Writer) int
 	return oc.Runner.Run(stdOut, stdErr, oc.OcPath, args...)
 }
 
+// RunAsAdmin runs oc as system:admin against the Minishift kube config. The arguments are passed as is, without
+// splitting them at spaces. The standard output is returned, the standard error becomes the error message on failure.
+func (oc *OcRunner) RunAsAdmin(args ...string) (string, error) {
+	outBuffer := new(bytes.Buffer)
+	errBuffer := new(bytes.Buffer)
+	args = append([]string{fmt.Sprintf("--config=%s", oc.KubeConfigPath)}, append(args, "--as", "system:admin")...)
+	if exitCode := oc.Runner.Run(outBuffer, errBuffer, oc.OcPath, args...); exitCode != 0 {
+		logger.Debugf("'oc %s' failed: %s", strings.Join(args, " "), strings.TrimSpace(errBuffer.String()))
+		return "", errors.New(strings.TrimSpace(errBuffer.String()))
+	}
+	return outBuffer.String(), nil
+}
+
 func (oc *OcRunner) RunAsUser(command string, stdOut io.Writer, stdErr io.Writer) int {
 	args := strings.Split(command, " ")
 	return oc.Runner.Run(stdOut, stdErr, oc.OcPath, args...)
@@ -90,21 +108,20 @@ func (oc *OcRunner) AddSudoerRoleForUser(user string) error {
 	return nil
 }
 
-// AddSystemAdminEntrytoKubeConfig adds the system:admin certs to ~/.kube/config
-func (oc *OcRunner) AddSystemAdminEntryToKubeConfig(ocPath string) error {
+// AddSystemAdminEntrytoKubeConfig adds the system:admin certs to ~/.kube/config. Existing entries of the same name are
+// only replaced if they are owned by the profile, the keys of the conflicting entries which were kept are returned.
+func (oc *OcRunner) AddSystemAdminEntryToKubeConfig(ocPath string, owned []string) ([]string, error) {
 	var existingKubeConfig, newKubeConfig *clientcmdapi.Config
 
 	minishiftKubeConfigPath := oc.KubeConfigPath
 	globalKubeConfigPath, err := GetGlobalKubeConfigPath()
 	if err != nil {
-		return err
-	}
-	if glog.V(2) {
-		fmt.Println("Using Kubeconfig Path: ", globalKubeConfigPath)
+		return nil, err
 	}
+	logger.Debugf("Using Kubeconfig Path: %s", globalKubeConfigPath)
 	dir, _ := filepath.Split(globalKubeConfigPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("unable to create kubeconfig dir %s: %v", dir, err)
+		return nil, fmt.Errorf("unable to create kubeconfig dir %s: %v", dir, err)
 	}
 
 	// Make sure .kube/config exist if not then this will create
@@ -112,17 +129,17 @@ func (oc *OcRunner) AddSystemAdminEntryToKubeConfig(ocPath string) error {
 
 	existingKubeConfig, err = clientcmd.LoadFromFile(globalKubeConfigPath)
 	if err != nil {
-		return fmt.Errorf("Not able to load %s: %s", globalKubeConfigPath, err)
+		return nil, fmt.Errorf("Not able to load %s: %s", globalKubeConfigPath, err)
 	}
 
 	newKubeConfig, err = clientcmd.LoadFromFile(minishiftKubeConfigPath)
 	if err != nil {
-		return fmt.Errorf("Not able to load %s: %s", minishiftKubeConfigPath, err)
+		return nil, fmt.Errorf("Not able to load %s: %s", minishiftKubeConfigPath, err)
 	}
 
-	merged := mergeKubeConfigs([]*clientcmdapi.Config{existingKubeConfig, newKubeConfig})
+	merged, conflicts := kubeconfig.Merge(existingKubeConfig, newKubeConfig, owned)
 
-	return clientcmd.WriteToFile(*merged, globalKubeConfigPath)
+	return conflicts, clientcmd.WriteToFile(*merged, globalKubeConfigPath)
 }
 
 // AddCliContext adds a CLI context for the user and namespace for the current OpenShift cluster. See also
@@ -156,6 +173,55 @@ func (oc *OcRunner) AddCliContext(context string, ip string, username string, na
 	return nil
 }
 
+// GroupClusterUpFlags groups the comma separated entries of a flag list into flags. An entry which does not start with
+// '--' continues the value of the previous flag, so that '--enable=*,service-catalog' stays a single flag.
+func GroupClusterUpFlags(entries []string) []string {
+	var flags []string
+	for _, entry := range entries {
+		if len(flags) > 0 && !strings.HasPrefix(entry, "--") {
+			flags[len(flags)-1] = flags[len(flags)-1] + "," + entry
+			continue
+		}
+		flags = append(flags, entry)
+	}
+	return flags
+}
+
+// ClusterUpFlagName returns the name of a flag given as '--name' or '--name=value'.
+func ClusterUpFlagName(flag string) (string, error) {
+	matches := clusterUpFlagRegex.FindStringSubmatch(flag)
+	if matches == nil {
+		return "", fmt.Errorf("'%s' is not a valid flag. Flags need to be specified as '--name' or '--name=value' without spaces", flag)
+	}
+	return matches[1], nil
+}
+
+// ValidateClusterUpFlags checks that 'cluster up' of the specified oc binary supports all of the specified flags.
+func ValidateClusterUpFlags(flags []string, ocPath string, runner util.Runner) error {
+	var buffer bytes.Buffer
+	runner.Run(&buffer, new(bytes.Buffer), ocPath, "cluster", "up", "-h")
+	ocCommandOptions := parseOcHelpCommand(buffer.Bytes())
+	if ocCommandOptions == nil {
+		return fmt.Errorf("Cannot determine the flags supported by '%s cluster up'", ocPath)
+	}
+
+	var unsupported []string
+	for _, flag := range flags {
+		name, err := ClusterUpFlagName(flag)
+		if err != nil {
+			return err
+		}
+		if !flagExist(ocCommandOptions, name) {
+			unsupported = append(unsupported, "--"+name)
+		}
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("The flag(s) %s are not supported by 'oc cluster up' of this OpenShift version. Supported flags: --%s",
+			strings.Join(unsupported, ", "), strings.Join(ocCommandOptions, ", --"))
+	}
+	return nil
+}
+
 func SupportFlag(flag string, ocPath string, runner util.Runner) bool {
 	var buffer bytes.Buffer
 	cmdArgs := []string{"cluster", "up", "-h"}
@@ -194,32 +260,6 @@ func flagExist(ocCommandOptions []string, flag string) bool {
 	return false
 }
 
-func mergeKubeConfigs(configs []*clientcmdapi.Config) *clientcmdapi.Config {
-	mergedConfig := clientcmdapi.NewConfig()
-	for _, config := range configs {
-		if config == nil {
-			continue
-		}
-		// merge clusters
-		for cName, c := range config.Clusters {
-			mergedConfig.Clusters[cName] = c
-		}
-		// merge authinfos
-		for aName, a := range config.AuthInfos {
-			mergedConfig.AuthInfos[aName] = a
-		}
-		// merge contexts
-		for ctxName, ctx := range config.Contexts {
-			mergedConfig.Contexts[ctxName] = ctx
-		}
-		// merge extensions
-		for extName, ext := range config.Extensions {
-			mergedConfig.Extensions[extName] = ext
-		}
-	}
-	return mergedConfig
-}
-
 // GetGlobalKubeConfigPath returns the path to the first entry in KUBECONFIG environment variable
 // or if KUBECONFIG not set then $HOME/.kube/config
 func GetGlobalKubeConfigPath() (string, error) {
@@ -131,6 +131,40 @@ func TestFlagExist(t *testing.T) {
 	assert.False(t, flagExist(expectedOptions, "proxy"))
 }
 
+func Test_cluster_up_flag_values_with_commas_are_grouped(t *testing.T) {
+	assert.Equal(t, []string{"--enable=*,service-catalog", "--forward-ports"}, GroupClusterUpFlags([]string{"--enable=*", "service-catalog", "--forward-ports"}))
+	assert.Equal(t, []string{"enable=*", "--forward-ports"}, GroupClusterUpFlags([]string{"enable=*", "--forward-ports"}))
+}
+
+func Test_cluster_up_flag_names_are_parsed(t *testing.T) {
+	name, err := ClusterUpFlagName("--enable=*,service-catalog")
+	assert.NoError(t, err)
+	assert.Equal(t, "enable", name)
+
+	name, err = ClusterUpFlagName("--forward-ports")
+	assert.NoError(t, err)
+	assert.Equal(t, "forward-ports", name)
+
+	for _, flag := range []string{"enable=*", "-enable", "--enable=a b", "--"} {
+		_, err = ClusterUpFlagName(flag)
+		assert.Error(t, err, "Flag '%s' should be rejected", flag)
+	}
+}
+
+func Test_cluster_up_flags_are_validated_against_oc_help(t *testing.T) {
+	runner := &helpRunner{help: ocClusterUpHelp}
+
+	assert.NoError(t, ValidateClusterUpFlags([]string{"--enable=*,service-catalog", "--forward-ports=true"}, "oc", runner))
+	assert.Equal(t, []string{"cluster", "up", "-h"}, runner.args)
+
+	err := ValidateClusterUpFlags([]string{"--enable=*", "--enabel=*", "--loglevel=5"}, "oc", runner)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--enabel, --loglevel are not supported")
+
+	err = ValidateClusterUpFlags([]string{"--enable=*"}, "oc", &helpRunner{help: []byte("unknown command")})
+	assert.Error(t, err)
+}
+
 func Test_get_kubeconfig_global_path(t *testing.T) {
 	type testdata struct {
 		KubeConfig string
@@ -184,6 +218,21 @@ func (r *FakeRunner) Run(stdOut io.Writer, stdErr io.Writer, commandPath string,
 	return 0
 }
 
+type helpRunner struct {
+	help []byte
+	args []string
+}
+
+func (r *helpRunner) Output(command string, args ...string) ([]byte, error) {
+	return nil, nil
+}
+
+func (r *helpRunner) Run(stdOut io.Writer, stdErr io.Writer, commandPath string, args ...string) int {
+	r.args = args
+	stdOut.Write(r.help)
+	return 0
+}
+
 func getUserKubeConfigLocation() string {
 	usr, _ := user.Current()
 	return filepath.Join(usr.HomeDir, ".kube", "config")
@@ -0,0 +1,88 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/docker"
+)
+
+const (
+	// AuditLogFileName is the name of the audit log written by each of the API servers
+	AuditLogFileName = "audit.log"
+
+	auditEnablePatch  = `{"auditConfig": {"enabled": true, "auditFilePath": "/etc/origin/master/%s", "maximumFileRetentionDays": 3, "maximumRetainedFiles": 3, "maximumFileSizeMegabytes": 10}}`
+	auditDisablePatch = `{"auditConfig": {"enabled": false}}`
+)
+
+var (
+	// audit events of both API servers are needed to debug RBAC and admission of all resources
+	auditLoggingTargets = []string{"master", "kube"}
+)
+
+// AuditLogFiles returns the paths of the audit logs of both API servers inside the VM.
+func AuditLogFiles() []string {
+	var files []string
+	for _, name := range auditLoggingTargets {
+		target := GetOpenShiftPatchTarget(name)
+		files = append(files, path.Join(path.Dir(target.localConfigFilePath()), AuditLogFileName))
+	}
+	return files
+}
+
+// AuditLogCommand returns the command printing the audit logs of both API servers inside the VM. A negative tail
+// prints the logs in full.
+func AuditLogCommand(follow bool, tail int64) string {
+	lines := "+1"
+	if tail >= 0 {
+		lines = fmt.Sprintf("%d", tail)
+	}
+	cmd := fmt.Sprintf("sudo tail -n %s", lines)
+	if follow {
+		cmd = cmd + " -F"
+	}
+	return fmt.Sprintf("%s %s", cmd, strings.Join(AuditLogFiles(), " "))
+}
+
+// ConfigureAuditLogging enables or disables the audit log of both API servers. OpenShift is only restarted if the
+// configuration changes.
+func ConfigureAuditLogging(enabled bool, commander docker.DockerCommander) error {
+	enablePatch := fmt.Sprintf(auditEnablePatch, AuditLogFileName)
+	for _, name := range auditLoggingTargets {
+		target := GetOpenShiftPatchTarget(name)
+		isEnabled, err := IsPatchApplied(target, enablePatch, commander)
+		if err != nil {
+			return fmt.Errorf("Error checking the audit configuration of '%s': %v", name, err)
+		}
+		if isEnabled == enabled {
+			continue
+		}
+
+		patch := enablePatch
+		if !enabled {
+			patch = auditDisablePatch
+		}
+		ok, err := Patch(target, patch, commander)
+		if err != nil || !ok {
+			return fmt.Errorf("Error configuring the audit log of '%s': %v", name, err)
+		}
+	}
+	return nil
+}
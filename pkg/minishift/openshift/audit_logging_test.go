@@ -0,0 +1,29 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_audit_log_command(t *testing.T) {
+	files := "/var/lib/minishift/base/openshift-apiserver/audit.log /var/lib/minishift/base/kube-apiserver/audit.log"
+	assert.Equal(t, "sudo tail -n +1 "+files, AuditLogCommand(false, -1))
+	assert.Equal(t, "sudo tail -n 20 -F "+files, AuditLogCommand(true, 20))
+}
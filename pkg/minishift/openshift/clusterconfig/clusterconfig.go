@@ -0,0 +1,198 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterconfig provides a typed model of the OpenShift master and node configuration files. The files are
+// read, modified and validated in Go, so that a configuration change which would keep OpenShift from starting is
+// rejected before it is written to the VM.
+package clusterconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/ghodss/yaml"
+)
+
+const (
+	MasterConfigKind = "MasterConfig"
+	NodeConfigKind   = "NodeConfig"
+)
+
+// Config is an OpenShift configuration file. The full document is kept, so that settings which are not part of the
+// typed model survive a modification unchanged.
+type Config struct {
+	document map[string]interface{}
+}
+
+// Parse parses the YAML content of a master or node configuration file.
+func Parse(content []byte) (*Config, error) {
+	jsonContent, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing the configuration: %v", err)
+	}
+
+	document, err := decodeObject(jsonContent)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing the configuration: %v", err)
+	}
+	return &Config{document: document}, nil
+}
+
+// Kind returns the kind of the configuration, for example MasterConfigKind.
+func (c *Config) Kind() string {
+	kind, _ := c.document["kind"].(string)
+	return kind
+}
+
+// Copy returns a deep copy of the configuration.
+func (c *Config) Copy() *Config {
+	content, _ := json.Marshal(c.document)
+	document, _ := decodeObject(content)
+	return &Config{document: document}
+}
+
+// Patch applies a JSON merge patch (RFC 7386) to the configuration, the same format 'oc ex config patch' accepts.
+func (c *Config) Patch(patch string) error {
+	patchDocument, err := decodeObject([]byte(patch))
+	if err != nil {
+		return fmt.Errorf("The patch is not a valid JSON object: %v", err)
+	}
+	c.document = mergePatch(c.document, patchDocument)
+	return nil
+}
+
+// Master returns the typed model of a master configuration.
+func (c *Config) Master() (*MasterConfig, error) {
+	if c.Kind() != MasterConfigKind {
+		return nil, fmt.Errorf("Expected a configuration of kind '%s' but got '%s'", MasterConfigKind, c.Kind())
+	}
+	master := &MasterConfig{}
+	if err := c.decode(master); err != nil {
+		return nil, err
+	}
+	return master, nil
+}
+
+// Node returns the typed model of a node configuration.
+func (c *Config) Node() (*NodeConfig, error) {
+	if c.Kind() != NodeConfigKind {
+		return nil, fmt.Errorf("Expected a configuration of kind '%s' but got '%s'", NodeConfigKind, c.Kind())
+	}
+	node := &NodeConfig{}
+	if err := c.decode(node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// StringSetting returns the string setting at the path, for example "routingConfig", "subdomain". The empty string is
+// returned if the setting does not exist or is not a string.
+func (c *Config) StringSetting(path ...string) string {
+	var value interface{} = c.document
+	for _, key := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[key]
+	}
+	setting, _ := value.(string)
+	return setting
+}
+
+// Validate checks the configuration against its typed model.
+func (c *Config) Validate() error {
+	switch c.Kind() {
+	case MasterConfigKind:
+		master, err := c.Master()
+		if err != nil {
+			return err
+		}
+		return master.Validate()
+	case NodeConfigKind:
+		node, err := c.Node()
+		if err != nil {
+			return err
+		}
+		return node.Validate()
+	default:
+		return fmt.Errorf("Unsupported configuration kind '%s'", c.Kind())
+	}
+}
+
+// Equal returns true if both configurations have the same content, regardless of formatting and order of the settings.
+func (c *Config) Equal(other *Config) bool {
+	return reflect.DeepEqual(c.document, other.document)
+}
+
+// Bytes returns the configuration as YAML.
+func (c *Config) Bytes() ([]byte, error) {
+	content, err := json.Marshal(c.document)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(content)
+}
+
+// decode decodes the document into the typed model, failing on settings of the wrong type.
+func (c *Config) decode(model interface{}) error {
+	content, err := json.Marshal(c.document)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, model); err != nil {
+		return fmt.Errorf("Invalid %s: %v", c.Kind(), err)
+	}
+	return nil
+}
+
+// decodeObject decodes a JSON object, keeping numbers as they are instead of converting them to floats.
+func decodeObject(content []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var document map[string]interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, fmt.Errorf("Expected a JSON object")
+	}
+	return document, nil
+}
+
+// mergePatch merges the patch into the target as specified by RFC 7386. null values remove the setting, objects are
+// merged recursively and all other values, including lists, replace the existing value.
+func mergePatch(target map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		patchObject, ok := value.(map[string]interface{})
+		if !ok {
+			target[key] = value
+			continue
+		}
+		targetObject, _ := target[key].(map[string]interface{})
+		target[key] = mergePatch(targetObject, patchObject)
+	}
+	return target
+}
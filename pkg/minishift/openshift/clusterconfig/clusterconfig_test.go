@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const masterConfig = `apiVersion: v1
+kind: MasterConfig
+servingInfo:
+  bindAddress: 0.0.0.0:8443
+  maxRequestsInFlight: 1200
+corsAllowedOrigins:
+- (?i)//127\.0\.0\.1(:|\z)
+oauthConfig:
+  identityProviders:
+  - challenge: true
+    login: true
+    mappingMethod: claim
+    name: anypassword
+    provider:
+      apiVersion: v1
+      kind: AllowAllPasswordIdentityProvider
+`
+
+func Test_patch_keeps_settings_outside_of_the_typed_model(t *testing.T) {
+	config, err := Parse([]byte(masterConfig))
+	assert.NoError(t, err)
+
+	err = config.Patch(`{"routingConfig": {"subdomain": "192.168.99.100.nip.io"}, "corsAllowedOrigins": null}`)
+	assert.NoError(t, err)
+	assert.NoError(t, config.Validate())
+
+	content, err := config.Bytes()
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "maxRequestsInFlight: 1200")
+	assert.Contains(t, string(content), "subdomain: 192.168.99.100.nip.io")
+	assert.NotContains(t, string(content), "corsAllowedOrigins")
+}
+
+func Test_equal_ignores_formatting(t *testing.T) {
+	config, err := Parse([]byte(masterConfig))
+	assert.NoError(t, err)
+
+	patched := config.Copy()
+	assert.NoError(t, patched.Patch(`{"servingInfo": {"bindAddress": "0.0.0.0:8443"}}`))
+	assert.True(t, config.Equal(patched))
+
+	assert.NoError(t, patched.Patch(`{"servingInfo": {"bindAddress": "0.0.0.0:8444"}}`))
+	assert.False(t, config.Equal(patched))
+}
+
+func Test_invalid_patches_are_detected(t *testing.T) {
+	var tests = []struct {
+		patch       string
+		expectedErr string
+	}{
+		{`{"corsAllowedOrigins": ["(?i)//localhost("]}`, "invalid regular expression"},
+		{`{"routingConfig": {"subdomain": "Not_A_Domain"}}`, "not a valid DNS subdomain"},
+		{`{"oauthConfig": {"identityProviders": [{"name": "htpasswd", "mappingMethod": "copy", "provider": {"kind": "HTPasswdPasswordIdentityProvider"}}]}}`, "Invalid mappingMethod"},
+		{`{"auditConfig": {"enabled": "yes"}}`, "Invalid MasterConfig"},
+		{`{"auditConfig": {"enabled": true}}`, "auditFilePath must not be empty"},
+	}
+
+	for _, test := range tests {
+		config, err := Parse([]byte(masterConfig))
+		assert.NoError(t, err)
+		assert.NoError(t, config.Patch(test.patch))
+
+		err = config.Validate()
+		assert.Error(t, err, "Expected patch '%s' to fail validation", test.patch)
+		if err != nil {
+			assert.True(t, strings.Contains(err.Error(), test.expectedErr), "Unexpected error '%s' for patch '%s'", err.Error(), test.patch)
+		}
+	}
+}
+
+func Test_patch_needs_to_be_a_json_object(t *testing.T) {
+	config, err := Parse([]byte(masterConfig))
+	assert.NoError(t, err)
+	assert.Error(t, config.Patch("[]"))
+	assert.Error(t, config.Patch("{"))
+}
+
+func Test_node_config_is_validated(t *testing.T) {
+	config, err := Parse([]byte("apiVersion: v1\nkind: NodeConfig\nkubeletArguments:\n  container-runtime:\n  - docker\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, config.Validate())
+
+	assert.NoError(t, config.Patch(`{"kubeletArguments": {"container-runtime": []}}`))
+	assert.Error(t, config.Validate())
+}
+
+func Test_string_setting(t *testing.T) {
+	config, err := Parse([]byte(masterConfig))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "0.0.0.0:8443", config.StringSetting("servingInfo", "bindAddress"))
+	assert.Equal(t, "", config.StringSetting("servingInfo", "maxRequestsInFlight"), "Settings which are no strings are empty")
+	assert.Equal(t, "", config.StringSetting("routingConfig", "subdomain"), "Missing settings are empty")
+	assert.Equal(t, "", config.StringSetting("kind", "subdomain"))
+}
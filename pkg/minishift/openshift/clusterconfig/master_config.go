@@ -0,0 +1,168 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	dnsSubdomain   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	mappingMethods = []string{"claim", "lookup", "generate", "add"}
+)
+
+// MasterConfig is the typed model of the settings of a master configuration which Minishift and its add-ons modify.
+type MasterConfig struct {
+	APIVersion            string                 `json:"apiVersion,omitempty"`
+	Kind                  string                 `json:"kind,omitempty"`
+	ServingInfo           *ServingInfo           `json:"servingInfo,omitempty"`
+	CORSAllowedOrigins    []string               `json:"corsAllowedOrigins,omitempty"`
+	RoutingConfig         *RoutingConfig         `json:"routingConfig,omitempty"`
+	OAuthConfig           *OAuthConfig           `json:"oauthConfig,omitempty"`
+	AuditConfig           *AuditConfig           `json:"auditConfig,omitempty"`
+	AdmissionConfig       *AdmissionConfig       `json:"admissionConfig,omitempty"`
+	JenkinsPipelineConfig *JenkinsPipelineConfig `json:"jenkinsPipelineConfig,omitempty"`
+}
+
+// ServingInfo is the address the API server listens on.
+type ServingInfo struct {
+	BindAddress string `json:"bindAddress,omitempty"`
+}
+
+// RoutingConfig holds the default subdomain of routes.
+type RoutingConfig struct {
+	Subdomain string `json:"subdomain,omitempty"`
+}
+
+// OAuthConfig holds the identity providers users can log in with.
+type OAuthConfig struct {
+	IdentityProviders []IdentityProvider `json:"identityProviders,omitempty"`
+}
+
+// IdentityProvider is a single identity provider. The provider specific settings are kept as is.
+type IdentityProvider struct {
+	Name          string                 `json:"name"`
+	Challenge     bool                   `json:"challenge"`
+	Login         bool                   `json:"login"`
+	MappingMethod string                 `json:"mappingMethod,omitempty"`
+	Provider      map[string]interface{} `json:"provider"`
+}
+
+// AuditConfig configures the audit log of the API server.
+type AuditConfig struct {
+	Enabled                  bool   `json:"enabled"`
+	AuditFilePath            string `json:"auditFilePath,omitempty"`
+	MaximumFileRetentionDays int    `json:"maximumFileRetentionDays,omitempty"`
+	MaximumRetainedFiles     int    `json:"maximumRetainedFiles,omitempty"`
+	MaximumFileSizeMegabytes int    `json:"maximumFileSizeMegabytes,omitempty"`
+}
+
+// AdmissionConfig holds the configuration of the admission plugins.
+type AdmissionConfig struct {
+	PluginConfig map[string]AdmissionPluginConfig `json:"pluginConfig,omitempty"`
+}
+
+// AdmissionPluginConfig is the configuration of a single admission plugin, either inline or in a separate file.
+type AdmissionPluginConfig struct {
+	Location      string                 `json:"location,omitempty"`
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+}
+
+// JenkinsPipelineConfig configures the Jenkins instance provisioned for builds with the pipeline strategy.
+type JenkinsPipelineConfig struct {
+	AutoProvisionEnabled *bool             `json:"autoProvisionEnabled,omitempty"`
+	TemplateNamespace    string            `json:"templateNamespace,omitempty"`
+	TemplateName         string            `json:"templateName,omitempty"`
+	ServiceName          string            `json:"serviceName,omitempty"`
+	Parameters           map[string]string `json:"parameters,omitempty"`
+}
+
+// Validate checks the settings which are known to keep the API server from starting if they are wrong.
+func (m *MasterConfig) Validate() error {
+	if m.APIVersion != "v1" {
+		return fmt.Errorf("Unsupported apiVersion '%s' of the MasterConfig", m.APIVersion)
+	}
+	if m.ServingInfo != nil && m.ServingInfo.BindAddress == "" {
+		return fmt.Errorf("servingInfo.bindAddress must not be empty")
+	}
+
+	for _, origin := range m.CORSAllowedOrigins {
+		if _, err := regexp.Compile(origin); err != nil {
+			return fmt.Errorf("corsAllowedOrigins contains the invalid regular expression '%s': %v", origin, err)
+		}
+	}
+
+	if m.RoutingConfig != nil && m.RoutingConfig.Subdomain != "" && !dnsSubdomain.MatchString(m.RoutingConfig.Subdomain) {
+		return fmt.Errorf("routingConfig.subdomain '%s' is not a valid DNS subdomain", m.RoutingConfig.Subdomain)
+	}
+
+	if m.OAuthConfig != nil {
+		if err := validateIdentityProviders(m.OAuthConfig.IdentityProviders); err != nil {
+			return err
+		}
+	}
+
+	if m.AuditConfig != nil && m.AuditConfig.Enabled && m.AuditConfig.AuditFilePath == "" {
+		return fmt.Errorf("auditConfig.auditFilePath must not be empty if the audit log is enabled")
+	}
+
+	if m.AdmissionConfig != nil {
+		for name, plugin := range m.AdmissionConfig.PluginConfig {
+			if plugin.Location == "" && plugin.Configuration == nil {
+				return fmt.Errorf("admissionConfig.pluginConfig.%s needs either a location or a configuration", name)
+			}
+		}
+	}
+
+	if m.JenkinsPipelineConfig != nil && m.JenkinsPipelineConfig.TemplateName != "" && m.JenkinsPipelineConfig.TemplateNamespace == "" {
+		return fmt.Errorf("jenkinsPipelineConfig.templateNamespace must not be empty if a template name is specified")
+	}
+
+	return nil
+}
+
+func validateIdentityProviders(providers []IdentityProvider) error {
+	names := make(map[string]bool)
+	for _, provider := range providers {
+		if provider.Name == "" {
+			return fmt.Errorf("The name of an identity provider must not be empty")
+		}
+		if names[provider.Name] {
+			return fmt.Errorf("The identity provider name '%s' is used more than once", provider.Name)
+		}
+		names[provider.Name] = true
+
+		if provider.MappingMethod != "" && !contains(mappingMethods, provider.MappingMethod) {
+			return fmt.Errorf("Invalid mappingMethod '%s' of identity provider '%s'. Possible values: %s", provider.MappingMethod, provider.Name, strings.Join(mappingMethods, ", "))
+		}
+		if kind, _ := provider.Provider["kind"].(string); kind == "" {
+			return fmt.Errorf("The provider kind of identity provider '%s' must not be empty", provider.Name)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
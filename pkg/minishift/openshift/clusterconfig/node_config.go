@@ -0,0 +1,41 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+import (
+	"fmt"
+)
+
+// NodeConfig is the typed model of the settings of a node configuration which Minishift modifies.
+type NodeConfig struct {
+	APIVersion       string              `json:"apiVersion,omitempty"`
+	Kind             string              `json:"kind,omitempty"`
+	KubeletArguments map[string][]string `json:"kubeletArguments,omitempty"`
+}
+
+// Validate checks the settings which are known to keep the node from starting if they are wrong.
+func (n *NodeConfig) Validate() error {
+	if n.APIVersion != "v1" {
+		return fmt.Errorf("Unsupported apiVersion '%s' of the NodeConfig", n.APIVersion)
+	}
+	for name, values := range n.KubeletArguments {
+		if len(values) == 0 {
+			return fmt.Errorf("kubeletArguments.%s needs at least one value", name)
+		}
+	}
+	return nil
+}
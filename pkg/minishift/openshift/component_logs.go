@@ -0,0 +1,257 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	maxLogLineSize = 1024 * 1024
+)
+
+// logSource is either a systemd unit logging to journald or a container of the cluster, identified by name or label
+type logSource struct {
+	unit      string
+	container string
+	label     string
+}
+
+var (
+	logComponents = map[string][]logSource{
+		// the OpenShift controllers
+		"origin": {{label: "io.kubernetes.container.name=controllers"}},
+		"etcd":   {{label: "io.kubernetes.container.name=etcd"}},
+		// the OpenShift and the Kubernetes API server
+		"apiserver": {{label: minishiftConstants.OpenshiftApiContainerLabel}, {label: minishiftConstants.KubernetesApiContainerLabel}},
+		"docker":    {{unit: "docker"}},
+		// the node process of the origin container runs the kubelet
+		"kubelet": {{container: minishiftConstants.OpenshiftContainerName}},
+	}
+)
+
+// ComponentLog is the command printing the logs of a single source of a component inside the VM
+type ComponentLog struct {
+	Component string
+	Command   string
+}
+
+// LogOptions select the log entries printed. A negative Tail prints the logs in full. Since and Until are either
+// durations relative to now, like 10m, or timestamps understood by journalctl and docker.
+type LogOptions struct {
+	Follow bool
+	Tail   int64
+	Since  string
+	Until  string
+}
+
+// LogComponents returns the names of the cluster components whose logs can be printed.
+func LogComponents() []string {
+	var components []string
+	for component := range logComponents {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	return components
+}
+
+// ComponentLogCommands returns the commands printing the logs of the specified components inside the VM.
+func ComponentLogCommands(components []string, options LogOptions, commander docker.DockerCommander) ([]ComponentLog, error) {
+	var logs []ComponentLog
+	for _, component := range components {
+		sources, ok := logComponents[component]
+		if !ok {
+			return nil, fmt.Errorf("Unknown component '%s'. Valid components are %s", component, strings.Join(LogComponents(), ", "))
+		}
+
+		for _, source := range sources {
+			var (
+				cmd string
+				err error
+			)
+			switch {
+			case source.unit != "":
+				cmd, err = journalCommand(source.unit, options)
+			case source.container != "":
+				cmd, err = containerLogCommand(source.container, options)
+			default:
+				var id string
+				id, err = commander.GetID(source.label)
+				if err != nil {
+					return nil, fmt.Errorf("Error finding the container of component '%s': %v", component, err)
+				}
+				if id == "" {
+					return nil, fmt.Errorf("No container found for component '%s'. Is the OpenShift cluster running?", component)
+				}
+				cmd, err = containerLogCommand(id, options)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("Error getting the logs of component '%s': %v", component, err)
+			}
+			logs = append(logs, ComponentLog{Component: component, Command: cmd})
+		}
+	}
+	return logs, nil
+}
+
+// UnitLogCommands returns the commands printing the journal of the specified systemd units inside the VM.
+func UnitLogCommands(units []string, options LogOptions) ([]ComponentLog, error) {
+	var logs []ComponentLog
+	for _, unit := range units {
+		cmd, err := journalCommand(unit, options)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, ComponentLog{Component: unit, Command: cmd})
+	}
+	return logs, nil
+}
+
+// StreamComponentLogs runs the log commands concurrently and writes their output line by line to out, each line
+// prefixed by the name of its component. The call returns once all commands have terminated.
+func StreamComponentLogs(client *ssh.Client, logs []ComponentLog, out io.Writer) error {
+	width := 0
+	for _, log := range logs {
+		if len(log.Component) > width {
+			width = len(log.Component)
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		outLock  sync.Mutex
+		errLock  sync.Mutex
+		firstErr error
+	)
+	for _, log := range logs {
+		wg.Add(1)
+		go func(log ComponentLog) {
+			defer wg.Done()
+			prefix := fmt.Sprintf("%-*s | ", width, log.Component)
+			err := streamLog(client, log.Command, func(line string) {
+				outLock.Lock()
+				fmt.Fprintln(out, prefix+line)
+				outLock.Unlock()
+			})
+			if err != nil {
+				errLock.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("Error getting the logs of component '%s': %v", log.Component, err)
+				}
+				errLock.Unlock()
+			}
+		}(log)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+func streamLog(client *ssh.Client, cmd string, printLine func(string)) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	reader, writer := io.Pipe()
+	session.Stdout = writer
+	session.Stderr = writer
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), maxLogLineSize)
+		for scanner.Scan() {
+			printLine(scanner.Text())
+		}
+		// keep draining, so that the session does not block on overly long lines
+		io.Copy(ioutil.Discard, reader)
+	}()
+
+	err = session.Run(cmd)
+	writer.Close()
+	<-done
+	return err
+}
+
+func journalCommand(unit string, options LogOptions) (string, error) {
+	cmd := fmt.Sprintf("sudo journalctl --no-pager -u %s", sshutil.Quote(unit))
+	for _, flag := range []struct{ name, value string }{{"since", options.Since}, {"until", options.Until}} {
+		if flag.value == "" {
+			continue
+		}
+		value, err := journalTime(flag.value)
+		if err != nil {
+			return "", fmt.Errorf("Invalid value '%s' for --%s: %v", flag.value, flag.name, err)
+		}
+		cmd = fmt.Sprintf("%s --%s %s", cmd, flag.name, sshutil.Quote(value))
+	}
+	if options.Tail >= 0 {
+		cmd = fmt.Sprintf("%s -n %d", cmd, options.Tail)
+	}
+	if options.Follow {
+		cmd = cmd + " -f"
+	}
+	return cmd, nil
+}
+
+func containerLogCommand(container string, options LogOptions) (string, error) {
+	// the Docker daemon of the VM predates 'docker logs --until'
+	if options.Until != "" {
+		return "", errors.New("--until is only supported for the logs of systemd units")
+	}
+
+	cmd := fmt.Sprintf("docker logs %s", sshutil.Quote(container))
+	if options.Since != "" {
+		cmd = fmt.Sprintf("%s --since %s", cmd, sshutil.Quote(options.Since))
+	}
+	if options.Tail >= 0 {
+		cmd = fmt.Sprintf("%s --tail %d", cmd, options.Tail)
+	}
+	if options.Follow {
+		cmd = cmd + " -f"
+	}
+	return cmd, nil
+}
+
+// journalTime converts durations like 10m into the relative time syntax of journalctl. Other values, like timestamps
+// or 'now', are passed on as is.
+func journalTime(value string) (string, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return value, nil
+	}
+	if duration < 0 {
+		return "", errors.New("durations must not be negative")
+	}
+	return fmt.Sprintf("-%ds", int64(duration.Seconds())), nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/minishift/minishift/pkg/minikube/tests"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLabelCommander struct {
+	docker.DockerCommander
+	ids map[string]string
+}
+
+func (f *fakeLabelCommander) GetID(label string) (string, error) {
+	return f.ids[label], nil
+}
+
+func Test_component_log_commands(t *testing.T) {
+	commander := &fakeLabelCommander{ids: map[string]string{
+		minishiftConstants.OpenshiftApiContainerLabel:  "abc",
+		minishiftConstants.KubernetesApiContainerLabel: "def",
+	}}
+
+	logs, err := ComponentLogCommands([]string{"apiserver", "docker", "kubelet"}, LogOptions{Follow: true, Tail: 10}, commander)
+	assert.NoError(t, err)
+	assert.Equal(t, []ComponentLog{
+		{Component: "apiserver", Command: "docker logs 'abc' --tail 10 -f"},
+		{Component: "apiserver", Command: "docker logs 'def' --tail 10 -f"},
+		{Component: "docker", Command: "sudo journalctl --no-pager -u 'docker' -n 10 -f"},
+		{Component: "kubelet", Command: "docker logs 'origin' --tail 10 -f"},
+	}, logs)
+
+	logs, err = ComponentLogCommands([]string{"docker"}, LogOptions{Tail: -1}, commander)
+	assert.NoError(t, err)
+	assert.Equal(t, "sudo journalctl --no-pager -u 'docker'", logs[0].Command)
+
+	logs, err = ComponentLogCommands([]string{"kubelet"}, LogOptions{Tail: -1, Since: "10m"}, commander)
+	assert.NoError(t, err)
+	assert.Equal(t, "docker logs 'origin' --since '10m'", logs[0].Command)
+
+	_, err = ComponentLogCommands([]string{"kubelet"}, LogOptions{Tail: -1, Until: "now"}, commander)
+	assert.EqualError(t, err, "Error getting the logs of component 'kubelet': --until is only supported for the logs of systemd units")
+}
+
+func Test_unit_log_commands(t *testing.T) {
+	logs, err := UnitLogCommands([]string{"docker", "minishift-mount@x.service"}, LogOptions{Tail: -1, Since: "10m", Until: "now"})
+	assert.NoError(t, err)
+	assert.Equal(t, []ComponentLog{
+		{Component: "docker", Command: "sudo journalctl --no-pager -u 'docker' --since '-600s' --until 'now'"},
+		{Component: "minishift-mount@x.service", Command: "sudo journalctl --no-pager -u 'minishift-mount@x.service' --since '-600s' --until 'now'"},
+	}, logs)
+
+	logs, err = UnitLogCommands([]string{"it's"}, LogOptions{Tail: 5, Since: "2019-10-14 10:00:00"})
+	assert.NoError(t, err)
+	assert.Equal(t, `sudo journalctl --no-pager -u 'it'"'"'s' --since '2019-10-14 10:00:00' -n 5`, logs[0].Command)
+
+	_, err = UnitLogCommands([]string{"docker"}, LogOptions{Tail: -1, Since: "-5m"})
+	assert.EqualError(t, err, "Invalid value '-5m' for --since: durations must not be negative")
+}
+
+func Test_component_log_commands_fail_for_unknown_or_missing_components(t *testing.T) {
+	commander := &fakeLabelCommander{}
+
+	_, err := ComponentLogCommands([]string{"scheduler"}, LogOptions{Tail: -1}, commander)
+	assert.EqualError(t, err, "Unknown component 'scheduler'. Valid components are apiserver, docker, etcd, kubelet, origin")
+
+	_, err = ComponentLogCommands([]string{"etcd"}, LogOptions{Tail: -1}, commander)
+	assert.EqualError(t, err, "No container found for component 'etcd'. Is the OpenShift cluster running?")
+}
+
+func Test_stream_component_logs_prefixes_lines(t *testing.T) {
+	server, err := tests.NewSSHServer()
+	assert.NoError(t, err, "Error creating ssh server")
+	server.CommandToOutput = map[string]string{
+		"docker logs origin":                   "node started\nkubelet ready\n",
+		"sudo journalctl --no-pager -u docker": "daemon started",
+	}
+	port, err := server.Start()
+	assert.NoError(t, err, "Error starting ssh server")
+
+	driver := &tests.MockDriver{Port: port, BaseDriver: drivers.BaseDriver{IPAddress: "127.0.0.1", SSHKeyPath: ""}}
+	client, err := sshutil.NewSSHClient(driver)
+	assert.NoError(t, err, "Error creating ssh client")
+	defer client.Close()
+
+	var out bytes.Buffer
+	err = StreamComponentLogs(client, []ComponentLog{
+		{Component: "kubelet", Command: "docker logs origin"},
+		{Component: "docker", Command: "sudo journalctl --no-pager -u docker"},
+	}, &out)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines, "kubelet | node started")
+	assert.Contains(t, lines, "kubelet | kubelet ready")
+	assert.Contains(t, lines, "docker  | daemon started")
+}
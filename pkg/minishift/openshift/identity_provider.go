@@ -0,0 +1,53 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/minishift/minishift/pkg/minikube/sshutil"
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/identityprovider"
+	"golang.org/x/crypto/ssh"
+)
+
+// ConfigureIdentityProvider copies the files referenced by the identity provider next to the configuration of
+// both API servers and patches the configurations to use it. For the htpasswd identity provider, the users declared
+// with a password are added to the htpasswd file. OpenShift is only restarted if the identity provider changes.
+func ConfigureIdentityProvider(provider *identityprovider.IdentityProvider, users map[string]*config.User, client *ssh.Client, commander docker.DockerCommander) error {
+	patch, err := provider.Patch()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range identityProviderTargets {
+		target := GetOpenShiftPatchTarget(name)
+		files, err := provider.Assets(path.Dir(target.localConfigFilePath()), HtpasswdContent(users))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := sshutil.TransferFile(file, client); err != nil {
+				return fmt.Errorf("Error copying '%s' for the '%s' identity provider: %v", file.GetTargetName(), provider.Kind, err)
+			}
+		}
+	}
+
+	return applyIdentityProviderPatch(patch, commander)
+}
@@ -17,15 +17,20 @@ limitations under the License.
 package openshift
 
 import (
+	"encoding/base64"
 	"fmt"
-	"strings"
+	"time"
 
-	"github.com/golang/glog"
 	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
 	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/openshift/clusterconfig"
+	"github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"github.com/pborman/uuid"
 )
 
+var logger = logging.For("openshift")
+
 type OpenShiftPatchTarget struct {
 	target              string
 	containerConfigFile string
@@ -97,6 +102,20 @@ func RestartOpenShift(commander docker.DockerCommander) (bool, error) {
 	return ok, err
 }
 
+// WaitForAPIServer waits until the OpenShift API server reports to be healthy, for example after the origin container
+// got restarted.
+func WaitForAPIServer(commander docker.DockerCommander, attempts int, interval time.Duration) error {
+	healthy := func() error {
+		if _, err := commander.LocalExec("curl -ksf https://localhost:8443/healthz"); err != nil {
+			return &util.RetriableError{Err: fmt.Errorf("The OpenShift API server is not healthy: %v", err)}
+		}
+		return nil
+	}
+	return util.RetryAfter(attempts, healthy, interval)
+}
+
+// Patch applies the patch to the configuration of the target and restarts OpenShift. If OpenShift cannot be restarted
+// with the patched configuration, the previous configuration is restored.
 func Patch(target OpenShiftPatchTarget, patch string, commander docker.DockerCommander) (bool, error) {
 	fmt.Println(fmt.Sprintf("Patching OpenShift configuration '%s' with '%s'", target.localConfigFile, patch))
 
@@ -104,34 +123,58 @@ func Patch(target OpenShiftPatchTarget, patch string, commander docker.DockerCom
 	if err != nil {
 		return false, err
 	}
+	defer deleteBackup(target, patchId, commander)
 
-	localConfigPath := target.localConfigFilePath()
-
-	patchCommand := fmt.Sprintf("ex config patch %s --patch='%s'", localConfigPath, patch)
-	cmd := fmt.Sprintf("%s/oc %s", minishiftConstants.OcPathInsideVM, patchCommand)
-
-	result, err := commander.LocalExec(cmd)
+	changed, err := PatchConfig(target, patch, commander)
 	if err != nil {
-		glog.Error("Creating patched configuration failed. Not applying the changes.", err)
-		return false, nil
+		restoreConfig(target, patchId, commander)
+		return false, err
+	}
+	if !changed {
+		logger.Debugf("The configuration '%s' already contains the patch", target.localConfigFile)
+		return true, nil
 	}
 
-	// Tweak the result configuration, we need to escape single quotes
-	result = strings.Replace(result, "'", "'\\''", -1)
-	writeConfig(target, result, commander)
+	_, err = RestartOpenShift(commander)
 	if err != nil {
 		rollback(target, commander, patchId)
 		return false, nil
 	}
 
-	_, err = RestartOpenShift(commander)
+	return true, nil
+}
+
+// ReadConfig reads the configuration of the target from the VM.
+func ReadConfig(target OpenShiftPatchTarget, commander docker.DockerCommander) (*clusterconfig.Config, error) {
+	content, err := commander.LocalExec(fmt.Sprintf("sudo cat %s", target.localConfigFilePath()))
 	if err != nil {
-		rollback(target, commander, patchId)
-		return false, nil
+		return nil, err
+	}
+	return clusterconfig.Parse([]byte(content))
+}
+
+// PatchConfig applies the patch to the configuration of the target and writes it back to the VM, provided the patched
+// configuration is valid. OpenShift is not restarted. false is returned if the patch does not change the configuration.
+func PatchConfig(target OpenShiftPatchTarget, patch string, commander docker.DockerCommander) (bool, error) {
+	current, err := ReadConfig(target, commander)
+	if err != nil {
+		return false, err
 	}
 
-	deleteBackup(target, patchId, commander)
+	patched := current.Copy()
+	if err := patched.Patch(patch); err != nil {
+		return false, err
+	}
+	if current.Equal(patched) {
+		return false, nil
+	}
+	if err := patched.Validate(); err != nil {
+		return false, fmt.Errorf("Not applying the patch to '%s'. The resulting configuration is invalid: %v", target.localConfigFile, err)
+	}
 
+	if err := writeConfig(target, patched, commander); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
@@ -211,17 +254,20 @@ func restoreConfig(target OpenShiftPatchTarget, id string, commander docker.Dock
 	return nil
 }
 
-func writeConfig(target OpenShiftPatchTarget, config string, commander docker.DockerCommander) error {
-	path := target.localConfigFilePath()
-
-	writeCommand := fmt.Sprintf("sudo echo '%s' | sudo tee %s > /dev/null", config, path)
-
-	_, err := commander.LocalExec(writeCommand)
+// writeConfig writes the configuration to the VM. The content is transferred base64 encoded, so that it does not need
+// any shell quoting.
+func writeConfig(target OpenShiftPatchTarget, config *clusterconfig.Config, commander docker.DockerCommander) error {
+	content, err := config.Bytes()
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
 
+	path := target.localConfigFilePath()
+	writeCommand := fmt.Sprintf("echo '%s' | base64 -d | sudo tee %s > /dev/null", base64.StdEncoding.EncodeToString(content), path)
+	if _, err := commander.LocalExec(writeCommand); err != nil {
+		return fmt.Errorf("Error writing '%s': %v", path, err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,49 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+type unhealthyCommander struct {
+	docker.DockerCommander
+	failures int
+	calls    int
+}
+
+func (c *unhealthyCommander) LocalExec(cmd string) (string, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return "", errors.New("exit status 7")
+	}
+	return "ok", nil
+}
+
+func Test_wait_for_api_server_retries_until_healthy(t *testing.T) {
+	commander := &unhealthyCommander{failures: 2}
+	assert.NoError(t, WaitForAPIServer(commander, 5, 0))
+	assert.Equal(t, 3, commander.calls)
+
+	commander = &unhealthyCommander{failures: 5}
+	assert.Error(t, WaitForAPIServer(commander, 3, 0))
+	assert.Equal(t, 3, commander.calls)
+}
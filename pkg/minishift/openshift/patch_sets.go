@@ -0,0 +1,78 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+)
+
+// IsPatchApplied checks whether applying the patch to the target configuration would leave the configuration unchanged.
+func IsPatchApplied(target OpenShiftPatchTarget, patch string, commander docker.DockerCommander) (bool, error) {
+	current, err := ReadConfig(target, commander)
+	if err != nil {
+		return false, err
+	}
+
+	patched := current.Copy()
+	if err := patched.Patch(patch); err != nil {
+		return false, err
+	}
+
+	return current.Equal(patched), nil
+}
+
+// PatchSetNames returns the names of the specified patch sets in the order they get applied.
+func PatchSetNames(patchSets map[string]*config.PatchSet) []string {
+	var names []string
+	for name := range patchSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyPatchSets applies the specified patch sets in alphabetical order of their names.
+// Patch sets which are already reflected in the configuration are skipped, so that OpenShift only gets restarted if needed.
+func ApplyPatchSets(patchSets map[string]*config.PatchSet, commander docker.DockerCommander) error {
+	for _, name := range PatchSetNames(patchSets) {
+		patchSet := patchSets[name]
+		target := GetOpenShiftPatchTarget(patchSet.Target)
+
+		applied, err := IsPatchApplied(target, patchSet.Patch, commander)
+		if err != nil {
+			return fmt.Errorf("Error checking patch set '%s': %v", name, err)
+		}
+		if applied {
+			logger.Debugf("Patch set '%s' is already applied", name)
+			continue
+		}
+
+		ok, err := Patch(target, patchSet.Patch, commander)
+		if err != nil {
+			return fmt.Errorf("Error applying patch set '%s': %v", name, err)
+		}
+		// Patch restores the previous configuration if OpenShift does not restart with the patched one
+		if !ok {
+			return fmt.Errorf("Error applying patch set '%s': OpenShift failed to restart with the patched configuration, the previous configuration was restored", name)
+		}
+	}
+	return nil
+}
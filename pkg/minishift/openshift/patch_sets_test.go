@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConfigCommander struct {
+	docker.DockerCommander
+	current  string
+	patched  string
+	commands []string
+}
+
+func (f *fakeConfigCommander) LocalExec(cmd string) (string, error) {
+	f.commands = append(f.commands, cmd)
+	if strings.HasPrefix(cmd, "sudo cat ") {
+		return f.current, nil
+	}
+	return f.patched, nil
+}
+
+func Test_patch_set_names_are_sorted(t *testing.T) {
+	patchSets := map[string]*config.PatchSet{
+		"cors":    {Target: "master", Patch: "{}"},
+		"a-first": {Target: "node", Patch: "{}"},
+	}
+	assert.Equal(t, []string{"a-first", "cors"}, PatchSetNames(patchSets))
+}
+
+func Test_patch_is_applied_if_configuration_is_unchanged(t *testing.T) {
+	commander := &fakeConfigCommander{current: "foo: bar\n", patched: "foo: bar"}
+	applied, err := IsPatchApplied(GetOpenShiftPatchTarget("master"), `{"foo": "bar"}`, commander)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+
+	commander = &fakeConfigCommander{current: "foo: baz\n", patched: "foo: bar"}
+	applied, err = IsPatchApplied(GetOpenShiftPatchTarget("master"), `{"foo": "bar"}`, commander)
+	assert.NoError(t, err)
+	assert.False(t, applied)
+}
+
+func Test_applied_patch_sets_are_skipped(t *testing.T) {
+	commander := &fakeConfigCommander{current: "foo: bar", patched: "foo: bar"}
+	patchSets := map[string]*config.PatchSet{
+		"foo": {Target: "master", Patch: `{"foo": "bar"}`},
+	}
+
+	err := ApplyPatchSets(patchSets, commander)
+	assert.NoError(t, err)
+	assert.Len(t, commander.commands, 1, "Only the configuration should have been read")
+}
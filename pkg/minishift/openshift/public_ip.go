@@ -0,0 +1,181 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+)
+
+const (
+	webConsoleNamespace  = "openshift-web-console"
+	webConsoleConfigMap  = "webconsole-config"
+	webConsoleConfigFile = "webconsole-config.yaml"
+)
+
+var (
+	// publicIPSettings are the settings of the master configuration which refer to the IP of the VM
+	publicIPSettings = [][]string{
+		{"masterPublicURL"},
+		{"oauthConfig", "masterPublicURL"},
+		{"oauthConfig", "assetPublicURL"},
+		{"routingConfig", "subdomain"},
+		{"assetConfig", "publicURL"},
+		{"assetConfig", "masterPublicURL"},
+	}
+
+	// webConsolePublicIPSettings are the settings of the web console configuration which refer to the IP of the VM
+	webConsolePublicIPSettings = []string{"consolePublicURL", "masterPublicURL", "logoutPublicURL", "loggingPublicURL", "metricsPublicURL"}
+)
+
+// UpdatePublicIP replaces the previous IP of the VM in the public URLs and the routing subdomain of the master
+// configurations. OpenShift needs to be restarted for the change to take effect.
+func UpdatePublicIP(oldIP string, newIP string, commander docker.DockerCommander) error {
+	for _, name := range []string{"master", "kube"} {
+		target := GetOpenShiftPatchTarget(name)
+		config, err := ReadConfig(target, commander)
+		if err != nil {
+			return err
+		}
+
+		patch := make(map[string]interface{})
+		for _, setting := range publicIPSettings {
+			value := config.StringSetting(setting...)
+			if updated := replaceIP(value, oldIP, newIP); updated != value {
+				setValue(patch, setting, updated)
+			}
+		}
+		if len(patch) == 0 {
+			continue
+		}
+
+		content, err := json.Marshal(patch)
+		if err != nil {
+			return err
+		}
+		if _, err := PatchConfig(target, string(content), commander); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateWebConsolePublicIP replaces the previous IP of the VM in the public URLs of the web console configuration. The
+// web console is not deployed for all configurations, in which case there is nothing to do.
+func UpdateWebConsolePublicIP(oldIP string, newIP string, ocRunner *oc.OcRunner) error {
+	out, err := ocRunner.RunAsAdmin("get", "configmap", webConsoleConfigMap, "-n", webConsoleNamespace, "-o", "json")
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			return nil
+		}
+		return err
+	}
+
+	patch, err := webConsolePublicIPPatch(out, oldIP, newIP)
+	if err != nil || patch == "" {
+		return err
+	}
+	_, err = ocRunner.RunAsAdmin("patch", "configmap", webConsoleConfigMap, "-n", webConsoleNamespace, "--type", "merge", "-p", patch)
+	return err
+}
+
+// webConsolePublicIPPatch returns the merge patch for the web console config map, given as JSON, or the empty string
+// if the configuration does not refer to the previous IP.
+func webConsolePublicIPPatch(configMap string, oldIP string, newIP string) (string, error) {
+	var current struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(configMap), &current); err != nil {
+		return "", fmt.Errorf("Error parsing the web console configuration: %v", err)
+	}
+
+	var consoleConfig map[string]interface{}
+	if err := yaml.Unmarshal([]byte(current.Data[webConsoleConfigFile]), &consoleConfig); err != nil {
+		return "", fmt.Errorf("Error parsing the web console configuration: %v", err)
+	}
+	clusterInfo, _ := consoleConfig["clusterInfo"].(map[string]interface{})
+
+	changed := false
+	for _, setting := range webConsolePublicIPSettings {
+		value, _ := clusterInfo[setting].(string)
+		if updated := replaceIP(value, oldIP, newIP); updated != value {
+			clusterInfo[setting] = updated
+			changed = true
+		}
+	}
+	if !changed {
+		return "", nil
+	}
+
+	content, err := yaml.Marshal(consoleConfig)
+	if err != nil {
+		return "", err
+	}
+	patch, err := json.Marshal(map[string]interface{}{"data": map[string]string{webConsoleConfigFile: string(content)}})
+	if err != nil {
+		return "", err
+	}
+	return string(patch), nil
+}
+
+// replaceIP replaces the IP if it is the host of a URL or the leading part of a nip.io style domain. Other values,
+// including IPs which merely start with the same digits, are returned unchanged.
+func replaceIP(value string, oldIP string, newIP string) string {
+	if value == "" {
+		return value
+	}
+
+	if u, err := url.Parse(value); err == nil && u.Host != "" {
+		if u.Hostname() != oldIP {
+			return value
+		}
+		if port := u.Port(); port != "" {
+			u.Host = net.JoinHostPort(newIP, port)
+		} else {
+			u.Host = newIP
+		}
+		return u.String()
+	}
+
+	if strings.HasPrefix(value, oldIP+".") {
+		rest := strings.TrimPrefix(value, oldIP+".")
+		if rest != "" && (rest[0] < '0' || rest[0] > '9') {
+			return newIP + "." + rest
+		}
+	}
+	return value
+}
+
+// setValue sets the value at the path in the nested patch object.
+func setValue(patch map[string]interface{}, path []string, value string) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := patch[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			patch[key] = child
+		}
+		patch = child
+	}
+	patch[path[len(path)-1]] = value
+}
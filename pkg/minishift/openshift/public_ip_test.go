@@ -0,0 +1,74 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_replace_ip(t *testing.T) {
+	var tests = []struct {
+		value    string
+		expected string
+	}{
+		{"https://192.168.42.1:8443", "https://192.168.42.99:8443"},
+		{"https://192.168.42.1:8443/console/", "https://192.168.42.99:8443/console/"},
+		{"https://192.168.42.1", "https://192.168.42.99"},
+		{"192.168.42.1.nip.io", "192.168.42.99.nip.io"},
+		{"https://192.168.42.10:8443", "https://192.168.42.10:8443"},
+		{"192.168.42.10.nip.io", "192.168.42.10.nip.io"},
+		{"192.168.42.1.5.nip.io", "192.168.42.1.5.nip.io"},
+		{"https://example.com:8443", "https://example.com:8443"},
+		{"apps.example.com", "apps.example.com"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, replaceIP(test.value, "192.168.42.1", "192.168.42.99"), test.value)
+	}
+}
+
+func Test_web_console_public_ip_patch(t *testing.T) {
+	configMap := `{"data": {"webconsole-config.yaml": "apiVersion: webconsole.config.openshift.io/v1\nclusterInfo:\n  consolePublicURL: https://192.168.42.1:8443/console/\n  masterPublicURL: https://192.168.42.1:8443\n  logoutPublicURL: \"\"\nkind: WebConsoleConfiguration\n"}}`
+
+	patch, err := webConsolePublicIPPatch(configMap, "192.168.42.1", "192.168.42.99")
+	assert.NoError(t, err)
+	assert.Contains(t, patch, `consolePublicURL: https://192.168.42.99:8443/console/`)
+	assert.Contains(t, patch, `masterPublicURL: https://192.168.42.99:8443`)
+	assert.Contains(t, patch, `kind: WebConsoleConfiguration`)
+
+	patch, err = webConsolePublicIPPatch(configMap, "192.168.42.2", "192.168.42.99")
+	assert.NoError(t, err)
+	assert.Empty(t, patch, "No patch is needed if the configuration does not refer to the previous IP")
+}
+
+func Test_set_value_creates_nested_objects(t *testing.T) {
+	patch := make(map[string]interface{})
+	setValue(patch, []string{"masterPublicURL"}, "https://192.168.42.99:8443")
+	setValue(patch, []string{"oauthConfig", "masterPublicURL"}, "https://192.168.42.99:8443")
+	setValue(patch, []string{"oauthConfig", "assetPublicURL"}, "https://192.168.42.99:8443/console/")
+
+	assert.Equal(t, map[string]interface{}{
+		"masterPublicURL": "https://192.168.42.99:8443",
+		"oauthConfig": map[string]interface{}{
+			"masterPublicURL": "https://192.168.42.99:8443",
+			"assetPublicURL":  "https://192.168.42.99:8443/console/",
+		},
+	}, patch)
+}
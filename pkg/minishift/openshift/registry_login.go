@@ -0,0 +1,69 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	instanceState "github.com/minishift/minishift/pkg/minishift/config"
+)
+
+// GetCurrentUserToken returns the name and the token of the user currently logged in with oc.
+// The user's own kube config is used, not the one Minishift maintains for system:admin.
+func GetCurrentUserToken() (string, string, error) {
+	cmdName := instanceState.InstanceStateConfig.OcPath
+
+	user, err := runner.Output(cmdName, "whoami")
+	if err != nil {
+		return "", "", fmt.Errorf("Unable to determine the current oc user. Make sure you are logged in: %v", err)
+	}
+
+	token, err := runner.Output(cmdName, "whoami", "-t")
+	if err != nil || strings.TrimSpace(string(token)) == "" {
+		return "", "", fmt.Errorf("The current oc user '%s' has no token. Log in with a user which authenticates via OAuth", strings.TrimSpace(string(user)))
+	}
+
+	return strings.TrimSpace(string(user)), strings.TrimSpace(string(token)), nil
+}
+
+// RegistryLoginArgs returns the arguments to log the specified container engine client into the registry.
+// The token is passed via stdin, so that it does not show up in the process list.
+func RegistryLoginArgs(cli string, registry string, user string) []string {
+	args := []string{"login", "-u", user, "--password-stdin"}
+	if cli == "podman" {
+		// the registry route uses the self-signed certificate of the cluster
+		args = append(args, "--tls-verify=false")
+	}
+	return append(args, registry)
+}
+
+// LoginToRegistry logs the specified container engine client of the host into the registry using the given token.
+func LoginToRegistry(cli string, registry string, user string, token string, out io.Writer) error {
+	var errOut bytes.Buffer
+	cmd := exec.Command(cli, RegistryLoginArgs(cli, registry, user)...)
+	cmd.Stdin = strings.NewReader(token)
+	cmd.Stdout = out
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("Error running '%s login' for registry '%s': %v %s", cli, registry, err, strings.TrimSpace(errOut.String()))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"testing"
+
+	instanceState "github.com/minishift/minishift/pkg/minishift/config"
+	test "github.com/minishift/minishift/pkg/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_get_current_user_token(t *testing.T) {
+	instanceState.InstanceStateConfig = &instanceState.InstanceStateConfigType{OcPath: "oc"}
+	defer teardown()
+
+	fakeRunner := test.NewFakeRunner(t)
+	runner = fakeRunner.Runner
+	fakeRunner.ExpectAndReturn("whoami", "developer\n")
+	fakeRunner.ExpectAndReturn("whoami -t", "secret-token\n")
+
+	user, token, err := GetCurrentUserToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "developer", user)
+	assert.Equal(t, "secret-token", token)
+}
+
+func Test_registry_login_args(t *testing.T) {
+	assert.Equal(t, []string{"login", "-u", "developer", "--password-stdin", "docker-registry-default.192.168.99.100.nip.io"},
+		RegistryLoginArgs("docker", "docker-registry-default.192.168.99.100.nip.io", "developer"))
+	assert.Equal(t, []string{"login", "-u", "developer", "--password-stdin", "--tls-verify=false", "docker-registry-default.192.168.99.100.nip.io"},
+		RegistryLoginArgs("podman", "docker-registry-default.192.168.99.100.nip.io", "developer"))
+}
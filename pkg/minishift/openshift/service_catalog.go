@@ -0,0 +1,152 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minishift/minishift/pkg/minishift/oc"
+)
+
+const (
+	// ServiceCatalogComponent is the component providing the service catalog API server and controller manager
+	ServiceCatalogComponent = "service-catalog"
+	// TemplateServiceBrokerComponent is the component registering the templates of the cluster as services
+	TemplateServiceBrokerComponent = "template-service-broker"
+)
+
+var (
+	// ServiceCatalogComponents are the components 'oc cluster add' installs for a working service catalog, in order
+	ServiceCatalogComponents = []string{ServiceCatalogComponent, TemplateServiceBrokerComponent}
+
+	componentNamespaces = map[string]string{
+		ServiceCatalogComponent:        "kube-service-catalog",
+		TemplateServiceBrokerComponent: "openshift-template-service-broker",
+	}
+
+	// serviceCatalogGates are checked in order, every gate needs to pass for the service catalog to be ready
+	serviceCatalogGates = []readinessGate{
+		daemonSetGate(ServiceCatalogComponent, "apiserver"),
+		daemonSetGate(ServiceCatalogComponent, "controller-manager"),
+		conditionGate(ServiceCatalogComponent, "apiservice", "v1beta1.servicecatalog.k8s.io", "Available"),
+		daemonSetGate(TemplateServiceBrokerComponent, "apiserver"),
+		conditionGate(TemplateServiceBrokerComponent, "clusterservicebroker", "template-service-broker", "Ready"),
+	}
+)
+
+// readinessGate is a single check of a resource of a component. The gate passes if isReady accepts the output of
+// 'oc get' for the resource using the jsonPath template.
+type readinessGate struct {
+	component   string
+	description string
+	args        []string
+	jsonPath    string
+	isReady     func(output string) bool
+}
+
+// ComponentStatus is the readiness of a component. Reason names the first gate which did not pass.
+type ComponentStatus struct {
+	Name   string
+	Ready  bool
+	Reason string
+}
+
+func daemonSetGate(component string, name string) readinessGate {
+	return readinessGate{
+		component:   component,
+		description: fmt.Sprintf("daemon set '%s' is not ready", name),
+		args:        []string{"daemonset", name, "-n", componentNamespaces[component]},
+		jsonPath:    "{.status.numberReady}/{.status.desiredNumberScheduled}",
+		isReady: func(output string) bool {
+			counts := strings.Split(output, "/")
+			return len(counts) == 2 && counts[0] == counts[1] && counts[0] != "0"
+		},
+	}
+}
+
+func conditionGate(component string, kind string, name string, condition string) readinessGate {
+	return readinessGate{
+		component:   component,
+		description: fmt.Sprintf("%s '%s' is not %s", kind, name, strings.ToLower(condition)),
+		args:        []string{kind, name},
+		jsonPath:    fmt.Sprintf("{.status.conditions[?(@.type==\"%s\")].status}", condition),
+		isReady: func(output string) bool {
+			return output == "True"
+		},
+	}
+}
+
+// IsComponentInstalled returns true if the namespace of the specified component exists.
+func IsComponentInstalled(ocRunner *oc.OcRunner, component string) bool {
+	_, err := ocRunner.RunAsAdmin("get", "namespace", componentNamespaces[component])
+	return err == nil
+}
+
+// ServiceCatalogStatus returns the readiness of each of the ServiceCatalogComponents.
+func ServiceCatalogStatus(ocRunner *oc.OcRunner) []ComponentStatus {
+	var statuses []ComponentStatus
+	for _, component := range ServiceCatalogComponents {
+		status := ComponentStatus{Name: component, Ready: true}
+		if !IsComponentInstalled(ocRunner, component) {
+			status.Ready = false
+			status.Reason = "not installed"
+			statuses = append(statuses, status)
+			continue
+		}
+		for _, gate := range serviceCatalogGates {
+			if gate.component == component && !gate.passes(ocRunner) {
+				status.Ready = false
+				status.Reason = gate.description
+				break
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// WaitForServiceCatalog polls the readiness of the ServiceCatalogComponents until all of them are ready. An error
+// naming the components which are not ready is returned once the timeout has expired.
+func WaitForServiceCatalog(ocRunner *oc.OcRunner, timeout time.Duration, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var notReady []string
+		for _, status := range ServiceCatalogStatus(ocRunner) {
+			if !status.Ready {
+				notReady = append(notReady, fmt.Sprintf("%s (%s)", status.Name, status.Reason))
+			}
+		}
+		if len(notReady) == 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("The service catalog did not become ready within %s: %s", timeout, strings.Join(notReady, ", "))
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (gate readinessGate) passes(ocRunner *oc.OcRunner) bool {
+	args := append([]string{"get"}, gate.args...)
+	out, err := ocRunner.RunAsAdmin(append(args, "-o", fmt.Sprintf("jsonpath=%s", gate.jsonPath))...)
+	if err != nil {
+		return false
+	}
+	return gate.isReady(strings.TrimSpace(out))
+}
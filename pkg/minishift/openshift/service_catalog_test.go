@@ -0,0 +1,94 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClusterRunner answers 'oc get' calls with the configured output per resource, missing resources fail.
+type fakeClusterRunner struct {
+	resources map[string]string
+}
+
+func (r *fakeClusterRunner) Run(stdOut io.Writer, stdErr io.Writer, commandPath string, args ...string) int {
+	// strip --config and get
+	if len(args) < 4 || args[1] != "get" {
+		return 1
+	}
+	output, ok := r.resources[args[2]+"/"+args[3]]
+	if !ok {
+		return 1
+	}
+	fmt.Fprint(stdOut, output)
+	return 0
+}
+
+func (r *fakeClusterRunner) Output(command string, args ...string) ([]byte, error) {
+	return nil, nil
+}
+
+func readyServiceCatalog() map[string]string {
+	return map[string]string{
+		"namespace/kube-service-catalog":               "",
+		"namespace/openshift-template-service-broker":  "",
+		"daemonset/apiserver":                          "1/1",
+		"daemonset/controller-manager":                 "1/1",
+		"apiservice/v1beta1.servicecatalog.k8s.io":     "True",
+		"clusterservicebroker/template-service-broker": "True",
+	}
+}
+
+func Test_service_catalog_is_ready_when_all_gates_pass(t *testing.T) {
+	ocRunner := &oc.OcRunner{OcPath: "oc", KubeConfigPath: "/tmp/kubeconfig", Runner: &fakeClusterRunner{resources: readyServiceCatalog()}}
+
+	for _, status := range ServiceCatalogStatus(ocRunner) {
+		assert.True(t, status.Ready, "Component '%s' should be ready: %s", status.Name, status.Reason)
+	}
+	assert.NoError(t, WaitForServiceCatalog(ocRunner, time.Millisecond, time.Millisecond))
+}
+
+func Test_service_catalog_status_names_first_failing_gate(t *testing.T) {
+	resources := readyServiceCatalog()
+	resources["daemonset/controller-manager"] = "0/1"
+	delete(resources, "namespace/openshift-template-service-broker")
+	ocRunner := &oc.OcRunner{OcPath: "oc", KubeConfigPath: "/tmp/kubeconfig", Runner: &fakeClusterRunner{resources: resources}}
+
+	statuses := ServiceCatalogStatus(ocRunner)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, ComponentStatus{Name: ServiceCatalogComponent, Ready: false, Reason: "daemon set 'controller-manager' is not ready"}, statuses[0])
+	assert.Equal(t, ComponentStatus{Name: TemplateServiceBrokerComponent, Ready: false, Reason: "not installed"}, statuses[1])
+
+	err := WaitForServiceCatalog(ocRunner, time.Millisecond, time.Millisecond)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "template-service-broker (not installed)"), err.Error())
+}
+
+func Test_daemon_set_gate_requires_scheduled_pods(t *testing.T) {
+	gate := daemonSetGate(ServiceCatalogComponent, "apiserver")
+	assert.True(t, gate.isReady("1/1"))
+	assert.False(t, gate.isReady("0/0"))
+	assert.False(t, gate.isReady("0/1"))
+	assert.False(t, gate.isReady(""))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/minishift/minishift/pkg/util/filehelper"
+)
+
+const (
+	// TemplatesNamespace is the namespace extra templates and image streams are imported into
+	TemplatesNamespace = "openshift"
+)
+
+var templateFileSuffixes = []string{".json", ".yaml", ".yml"}
+
+// ValidateTemplateSource checks that the source is either an HTTP(S) URL or an existing file or directory.
+func ValidateTemplateSource(source string) error {
+	if isURL(source) {
+		return nil
+	}
+	if !filehelper.Exists(source) {
+		return fmt.Errorf("Template source '%s' is neither a URL nor an existing file or directory", source)
+	}
+	return nil
+}
+
+// TemplateFiles expands the specified sources into the list of files or URLs to import. Directories are expanded
+// into their JSON and YAML files in alphabetical order, files and URLs are used as is.
+func TemplateFiles(sources []string) ([]string, error) {
+	var files []string
+	for _, source := range sources {
+		if err := ValidateTemplateSource(source); err != nil {
+			return nil, err
+		}
+		if !filehelper.IsDirectory(source) {
+			files = append(files, source)
+			continue
+		}
+
+		entries, err := ioutil.ReadDir(source)
+		if err != nil {
+			return nil, err
+		}
+		var dirFiles []string
+		for _, entry := range entries {
+			if !entry.IsDir() && hasTemplateSuffix(entry.Name()) {
+				dirFiles = append(dirFiles, filepath.Join(source, entry.Name()))
+			}
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
+
+// ImportTemplates imports the templates and image streams of the specified sources into the openshift namespace.
+// Existing resources are updated, so that importing the same sources on every start is safe.
+func ImportTemplates(ocRunner *oc.OcRunner, sources []string) error {
+	files, err := TemplateFiles(sources)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		fmt.Println(fmt.Sprintf("   Importing '%s'", file))
+		errBuffer := new(bytes.Buffer)
+		args := []string{fmt.Sprintf("--config=%s", ocRunner.KubeConfigPath), "apply", "-f", file, "-n", TemplatesNamespace, "--as", "system:admin"}
+		exitCode := ocRunner.Runner.Run(os.Stdout, errBuffer, ocRunner.OcPath, args...)
+		if exitCode != 0 {
+			return fmt.Errorf("Error importing '%s': %s", file, strings.TrimSpace(errBuffer.String()))
+		}
+	}
+	return nil
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func hasTemplateSuffix(name string) bool {
+	for _, suffix := range templateFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
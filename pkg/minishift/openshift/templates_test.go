@@ -0,0 +1,50 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_template_sources_are_expanded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minishift-test-templates-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"b-imagestreams.yaml", "a-template.json", "README.md"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644))
+	}
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "nested.json"), 0755))
+
+	files, err := TemplateFiles([]string{dir, "https://example.com/templates/jenkins.json"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a-template.json"),
+		filepath.Join(dir, "b-imagestreams.yaml"),
+		"https://example.com/templates/jenkins.json",
+	}, files)
+}
+
+func Test_non_existing_template_source_is_invalid(t *testing.T) {
+	_, err := TemplateFiles([]string{filepath.Join("does", "not", "exist")})
+	assert.Error(t, err)
+}
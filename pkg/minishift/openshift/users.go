@@ -0,0 +1,205 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/identityprovider"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	validUserName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._@-]*$`)
+
+	// the configuration of both API servers needs to know about the identity provider
+	identityProviderTargets = []string{"master", "kube"}
+)
+
+// ValidateUserName checks that the name can be used as OpenShift user name as well as htpasswd entry.
+func ValidateUserName(name string) error {
+	if !validUserName.MatchString(name) {
+		return fmt.Errorf("'%s' is not a valid user name. Only letters, digits and the characters '.', '_', '@' and '-' are allowed", name)
+	}
+	return nil
+}
+
+// HashPassword returns the salted bcrypt hash of the password in the '$2y$' format written by 'htpasswd -B', which
+// the htpasswd identity provider understands.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return "$2y$" + strings.TrimPrefix(string(hash), "$2a$"), nil
+}
+
+// UserNames returns the names of the specified users in the order they get reconciled.
+func UserNames(users map[string]*config.User) []string {
+	var names []string
+	for name := range users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HtpasswdContent returns the content of the htpasswd file for all users which have a password.
+// An empty string is returned if none of the users has a password.
+func HtpasswdContent(users map[string]*config.User) string {
+	var lines []string
+	for _, name := range UserNames(users) {
+		if users[name].PasswordHash != "" {
+			lines = append(lines, fmt.Sprintf("%s:%s", name, users[name].PasswordHash))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// ReconcileUsers makes sure the specified users exist in the cluster and are bound to their cluster roles and
+// security context constraints. Unless the identity provider is configured explicitly, the htpasswd identity
+// provider replaces the default identity provider accepting any password as soon as any user has a password, and the
+// default identity provider is restored otherwise. OpenShift is only restarted if the identity provider changes.
+func ReconcileUsers(users map[string]*config.User, manageIdentityProvider bool, commander docker.DockerCommander, ocRunner *oc.OcRunner) error {
+	if manageIdentityProvider {
+		kind := identityprovider.AllowAll
+		if content := HtpasswdContent(users); content != "" {
+			if err := writeHtpasswdFiles(content, commander); err != nil {
+				return fmt.Errorf("Error writing the htpasswd file: %v", err)
+			}
+			kind = identityprovider.Htpasswd
+		}
+		if err := ensureIdentityProvider(kind, commander); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range UserNames(users) {
+		if err := reconcileUser(name, users[name], ocRunner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveUser deletes the user and its identity from the cluster and removes its cluster role and security context
+// constraint bindings. remaining are the users which are still declared in the profile. Unless the identity provider
+// is configured explicitly, the default identity provider accepting any password is restored if none of them has a
+// password.
+func RemoveUser(name string, user *config.User, remaining map[string]*config.User, manageIdentityProvider bool, commander docker.DockerCommander, ocRunner *oc.OcRunner) error {
+	for _, role := range user.ClusterRoles {
+		ocRunner.RunAsAdmin("adm", "policy", "remove-cluster-role-from-user", role, name)
+	}
+	for _, scc := range user.SCCs {
+		ocRunner.RunAsAdmin("adm", "policy", "remove-scc-from-user", scc, name)
+	}
+	for _, kind := range identityprovider.SupportedProviders {
+		ocRunner.RunAsAdmin("delete", "identity", fmt.Sprintf("%s:%s", identityprovider.ProviderName(kind), name))
+	}
+	ocRunner.RunAsAdmin("delete", "user", name)
+
+	if !manageIdentityProvider || user.PasswordHash == "" {
+		return nil
+	}
+
+	content := HtpasswdContent(remaining)
+	if err := writeHtpasswdFiles(content, commander); err != nil {
+		return fmt.Errorf("Error writing the htpasswd file: %v", err)
+	}
+	if content == "" {
+		return ensureIdentityProvider(identityprovider.AllowAll, commander)
+	}
+	return nil
+}
+
+func reconcileUser(name string, user *config.User, ocRunner *oc.OcRunner) error {
+	if _, err := ocRunner.RunAsAdmin("get", "user", name); err != nil {
+		if _, err := ocRunner.RunAsAdmin("create", "user", name); err != nil {
+			return fmt.Errorf("Error creating user '%s': %v", name, err)
+		}
+	}
+
+	for _, role := range user.ClusterRoles {
+		if _, err := ocRunner.RunAsAdmin("adm", "policy", "add-cluster-role-to-user", role, name); err != nil {
+			return fmt.Errorf("Error binding cluster role '%s' to user '%s': %v", role, name, err)
+		}
+	}
+	for _, scc := range user.SCCs {
+		if _, err := ocRunner.RunAsAdmin("adm", "policy", "add-scc-to-user", scc, name); err != nil {
+			return fmt.Errorf("Error adding security context constraint '%s' to user '%s': %v", scc, name, err)
+		}
+	}
+	return nil
+}
+
+// ensureIdentityProvider patches the API server configurations with the specified kind of identity provider,
+// unless the configurations already use it.
+func ensureIdentityProvider(kind string, commander docker.DockerCommander) error {
+	provider := &identityprovider.IdentityProvider{Kind: kind}
+	patch, err := provider.Patch()
+	if err != nil {
+		return err
+	}
+	return applyIdentityProviderPatch(patch, commander)
+}
+
+func applyIdentityProviderPatch(patch string, commander docker.DockerCommander) error {
+	for _, name := range identityProviderTargets {
+		target := GetOpenShiftPatchTarget(name)
+		applied, err := IsPatchApplied(target, patch, commander)
+		if err != nil {
+			return fmt.Errorf("Error checking the identity provider of '%s': %v", name, err)
+		}
+		if applied {
+			continue
+		}
+
+		ok, err := Patch(target, patch, commander)
+		if err != nil || !ok {
+			return fmt.Errorf("Error configuring the identity provider of '%s': %v", name, err)
+		}
+	}
+	return nil
+}
+
+// writeHtpasswdFiles writes the htpasswd file next to the configuration of both API servers. An empty content
+// removes the files.
+func writeHtpasswdFiles(content string, commander docker.DockerCommander) error {
+	for _, name := range identityProviderTargets {
+		target := GetOpenShiftPatchTarget(name)
+		file := path.Join(path.Dir(target.localConfigFilePath()), identityprovider.HtpasswdFileName)
+
+		cmd := fmt.Sprintf("sudo rm -f %s", file)
+		if content != "" {
+			cmd = fmt.Sprintf("echo -n '%s' | sudo tee %s > /dev/null", content, file)
+		}
+		if _, err := commander.LocalExec(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const allowAllConfig = `oauthConfig:
+  identityProviders:
+  - challenge: true
+    login: true
+    mappingMethod: claim
+    name: anypassword
+    provider:
+      apiVersion: v1
+      kind: AllowAllPasswordIdentityProvider
+`
+
+type fakeOcRunner struct {
+	commands []string
+	users    map[string]bool
+}
+
+func (r *fakeOcRunner) Run(stdOut io.Writer, stdErr io.Writer, commandPath string, args ...string) int {
+	// strip --config
+	cmd := strings.Join(args[1:], " ")
+	r.commands = append(r.commands, cmd)
+	if len(args) > 3 && args[1] == "get" && args[2] == "user" && !r.users[args[3]] {
+		return 1
+	}
+	return 0
+}
+
+func (r *fakeOcRunner) Output(command string, args ...string) ([]byte, error) {
+	return nil, nil
+}
+
+func Test_password_is_hashed_with_bcrypt(t *testing.T) {
+	hash, err := HashPassword("password")
+	assert.NoError(t, err)
+	assert.Regexp(t, `^\$2y\$10\$[./A-Za-z0-9]{53}$`, hash)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("password")))
+
+	other, _ := HashPassword("password")
+	assert.NotEqual(t, hash, other, "Hashes should be salted")
+}
+
+func Test_htpasswd_content_only_contains_users_with_password(t *testing.T) {
+	users := map[string]*config.User{
+		"developer": {PasswordHash: "$2y$10$developer"},
+		"admin":     {PasswordHash: "$2y$10$admin", ClusterRoles: []string{"cluster-admin"}},
+		"viewer":    {ClusterRoles: []string{"view"}},
+	}
+	expected := "admin:$2y$10$admin\ndeveloper:$2y$10$developer\n"
+	assert.Equal(t, expected, HtpasswdContent(users))
+
+	assert.Empty(t, HtpasswdContent(map[string]*config.User{"viewer": {}}))
+}
+
+func Test_user_names_are_validated(t *testing.T) {
+	assert.NoError(t, ValidateUserName("developer"))
+	assert.NoError(t, ValidateUserName("jane.doe@example.com"))
+	assert.Error(t, ValidateUserName("-developer"))
+	assert.Error(t, ValidateUserName("john doe"))
+	assert.Error(t, ValidateUserName("admin:x"))
+}
+
+func Test_users_are_created_and_bound_to_their_roles(t *testing.T) {
+	runner := &fakeOcRunner{users: map[string]bool{"developer": true}}
+	ocRunner := &oc.OcRunner{OcPath: "oc", KubeConfigPath: "/tmp/kubeconfig", Runner: runner}
+
+	users := map[string]*config.User{
+		"developer": {ClusterRoles: []string{"cluster-admin"}},
+		"builder":   {SCCs: []string{"anyuid"}},
+	}
+	commander := &fakeConfigCommander{current: allowAllConfig, patched: allowAllConfig}
+	err := ReconcileUsers(users, true, commander, ocRunner)
+
+	assert.NoError(t, err)
+	expected := []string{
+		"get user builder --as system:admin",
+		"create user builder --as system:admin",
+		"adm policy add-scc-to-user anyuid builder --as system:admin",
+		"get user developer --as system:admin",
+		"adm policy add-cluster-role-to-user cluster-admin developer --as system:admin",
+	}
+	assert.Equal(t, expected, runner.commands)
+	assert.Len(t, commander.commands, 2, "Only the configurations using the default identity provider should have been read")
+}
@@ -48,15 +48,12 @@ func PrintUpstreamVersions(output io.Writer, minSupportedVersion string, default
 		return err
 	}
 
-	releaseList, err := OpenShiftTagsByAscending(tags, minSupportedVersion, defaultVersion)
+	releases, err := ListReleases(tags, nil, minSupportedVersion, defaultVersion)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprint(output, "The following OpenShift versions are available: \n")
-	for _, tag := range releaseList {
-		fmt.Fprintf(output, "\t- %s\n", tag)
-	}
+	PrintReleases(output, releases)
 	return nil
 }
 
@@ -95,6 +92,20 @@ func IsGreaterOrEqualToBaseVersion(version string, baseVersion string) (bool, er
 	return false, nil
 }
 
+// HaveSameMinorVersion returns true if both versions share the same major and minor version
+func HaveSameMinorVersion(version string, otherVersion string) (bool, error) {
+	var parsed []semver.Version
+	for _, v := range []string{version, otherVersion} {
+		semVer, err := semver.Parse(strings.TrimPrefix(v, constants.VersionPrefix))
+		if err != nil {
+			return false, errors.New(fmt.Sprintf("Invalid version format '%s': %s", v, err.Error()))
+		}
+		parsed = append(parsed, semVer)
+	}
+
+	return parsed[0].Major == parsed[1].Major && parsed[0].Minor == parsed[1].Minor, nil
+}
+
 func GetGithubReleases() ([]string, error) {
 	var releaseTags []string
 	ctx := context.Background()
@@ -89,6 +89,29 @@ func TestIsGreaterOrEqualToBaseVersion(t *testing.T) {
 	}
 }
 
+func TestHaveSameMinorVersion(t *testing.T) {
+	var versionTestData = []struct {
+		version        string
+		otherVersion   string
+		expectedResult bool
+	}{
+		{"v3.11.0", "v3.11.0", true},
+		{"v3.11.0", "v3.11.1", true},
+		{"v3.10.0", "v3.11.0", false},
+		{"v3.11.0-rc.0", "v3.11.0", true},
+		{"v4.11.0", "v3.11.0", false},
+	}
+
+	for _, versionTest := range versionTestData {
+		actualResult, err := HaveSameMinorVersion(versionTest.version, versionTest.otherVersion)
+		assert.NoError(t, err)
+		assert.Equal(t, versionTest.expectedResult, actualResult, "%s and %s", versionTest.version, versionTest.otherVersion)
+	}
+
+	_, err := HaveSameMinorVersion("foo", "v3.11.0")
+	assert.EqualError(t, err, "Invalid version format 'foo': No Major.Minor.Patch elements found")
+}
+
 func TestIsPrerelease(t *testing.T) {
 	var versionTestData = []struct {
 		openshiftVersion string
@@ -0,0 +1,184 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	"github.com/minishift/minishift/pkg/util/logging"
+	stringUtils "github.com/minishift/minishift/pkg/util/strings"
+)
+
+var logger = logging.For("openshift-version")
+
+const (
+	// ReleaseCacheFile is the name of the file in the Minishift cache directory holding the last fetched releases
+	ReleaseCacheFile = "openshift-releases.json"
+	// ReleaseCacheTTL is the duration for which the cached releases are used without querying GitHub
+	ReleaseCacheTTL = 24 * time.Hour
+
+	// firstUnsupportedVersion is the first OpenShift version which cannot be provisioned via 'oc cluster up'
+	firstUnsupportedVersion = "v4.0.0-0"
+)
+
+// fetchReleases queries the upstream releases. It is a variable to allow replacing it in tests.
+var fetchReleases = GetGithubReleases
+
+type releaseCache struct {
+	Fetched time.Time `json:"fetched"`
+	Tags    []string  `json:"tags"`
+}
+
+// Release describes an OpenShift release as listed by 'openshift version list'.
+type Release struct {
+	Version    string
+	Compatible bool
+	Cached     bool
+	Default    bool
+}
+
+// GetReleases returns the upstream OpenShift release tags. The releases are cached in cacheDir and only fetched again
+// once the cache is older than ReleaseCacheTTL. If fetching fails, the cached releases are used regardless of their age.
+// In offline mode only the cached releases are returned.
+func GetReleases(cacheDir string, offline bool) ([]string, error) {
+	cacheFile := filepath.Join(cacheDir, ReleaseCacheFile)
+	cache, cacheErr := readReleaseCache(cacheFile)
+
+	if offline {
+		if cacheErr != nil {
+			return nil, nil
+		}
+		return cache.Tags, nil
+	}
+
+	if cacheErr == nil && time.Since(cache.Fetched) < ReleaseCacheTTL {
+		return cache.Tags, nil
+	}
+
+	tags, err := fetchReleases()
+	if err != nil {
+		if cacheErr == nil {
+			logger.Debugf("Using cached OpenShift releases of %s: %v", cache.Fetched.Format(time.RFC3339), err)
+			return cache.Tags, nil
+		}
+		return nil, err
+	}
+
+	if err := writeReleaseCache(cacheFile, &releaseCache{Fetched: time.Now(), Tags: tags}); err != nil {
+		logger.Debugf("Error caching the OpenShift releases: %v", err)
+	}
+	return tags, nil
+}
+
+// ListReleases combines the upstream release tags and the versions with a cached oc binary into a list of releases
+// sorted by version. Releases are compatible if they are at least minSupportedVersion and can be provisioned via 'oc cluster up'.
+func ListReleases(tags []string, cachedVersions []string, minSupportedVersion string, defaultVersion string) ([]Release, error) {
+	versions, err := OpenShiftTagsByAscending(tags, minSupportedVersion, defaultVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, cachedVersion := range cachedVersions {
+		if _, err := semver.Parse(strings.TrimPrefix(cachedVersion, constants.VersionPrefix)); err == nil && !stringUtils.Contains(versions, cachedVersion) {
+			versions = append(versions, cachedVersion)
+		}
+	}
+	versions = sortTagsViaSemverSort(versions)
+
+	var releases []Release
+	for _, v := range versions {
+		releases = append(releases, Release{
+			Version:    v,
+			Compatible: IsCompatibleVersion(v, minSupportedVersion),
+			Cached:     stringUtils.Contains(cachedVersions, v),
+			Default:    v == defaultVersion,
+		})
+	}
+	return releases, nil
+}
+
+// IsCompatibleVersion returns true if the version is at least minSupportedVersion and can be provisioned via 'oc cluster up'.
+func IsCompatibleVersion(version string, minSupportedVersion string) bool {
+	supported, _ := IsGreaterOrEqualToBaseVersion(version, minSupportedVersion)
+	unsupported, _ := IsGreaterOrEqualToBaseVersion(version, firstUnsupportedVersion)
+	return supported && !unsupported
+}
+
+// KnownReleases returns the cached upstream OpenShift release tags without querying GitHub. Nil is returned if
+// there are no cached releases or if the cache is older than ReleaseCacheTTL, since it might miss recent releases.
+func KnownReleases(cacheDir string) []string {
+	cache, err := readReleaseCache(filepath.Join(cacheDir, ReleaseCacheFile))
+	if err != nil || time.Since(cache.Fetched) >= ReleaseCacheTTL {
+		return nil
+	}
+	return cache.Tags
+}
+
+// PrintReleases prints the releases together with their default, cached and compatibility state
+func PrintReleases(output io.Writer, releases []Release) {
+	fmt.Fprint(output, "The following OpenShift versions are available: \n")
+	for _, release := range releases {
+		var marks []string
+		if release.Default {
+			marks = append(marks, "default")
+		}
+		if release.Cached {
+			marks = append(marks, "cached")
+		}
+		if !release.Compatible {
+			marks = append(marks, "not supported")
+		}
+
+		if len(marks) == 0 {
+			fmt.Fprintf(output, "\t- %s\n", release.Version)
+		} else {
+			fmt.Fprintf(output, "\t- %s (%s)\n", release.Version, strings.Join(marks, ", "))
+		}
+	}
+}
+
+func readReleaseCache(path string) (*releaseCache, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &releaseCache{}
+	if err := json.Unmarshal(raw, cache); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+func writeReleaseCache(path string, cache *releaseCache) error {
+	raw, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, raw, 0644)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReleasesUsesCache(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "minishift-test-releases-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+	defer func() { fetchReleases = GetGithubReleases }()
+
+	fetches := 0
+	fetchReleases = func() ([]string, error) {
+		fetches++
+		return []string{"v3.11.0"}, nil
+	}
+
+	tags, err := GetReleases(cacheDir, true)
+	assert.NoError(t, err)
+	assert.Empty(t, tags, "No releases should be returned offline without cache")
+
+	for i := 0; i < 2; i++ {
+		tags, err = GetReleases(cacheDir, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v3.11.0"}, tags)
+	}
+	assert.Equal(t, 1, fetches, "Releases should be fetched once and then served from the cache")
+
+	tags, err = GetReleases(cacheDir, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v3.11.0"}, tags)
+}
+
+func TestGetReleasesFallsBackToStaleCache(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "minishift-test-releases-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+	defer func() { fetchReleases = GetGithubReleases }()
+
+	cacheFile := filepath.Join(cacheDir, ReleaseCacheFile)
+	err = writeReleaseCache(cacheFile, &releaseCache{Fetched: time.Now().Add(-2 * ReleaseCacheTTL), Tags: []string{"v3.10.0"}})
+	assert.NoError(t, err)
+
+	fetchReleases = func() ([]string, error) {
+		return nil, errors.New("no network")
+	}
+
+	tags, err := GetReleases(cacheDir, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v3.10.0"}, tags)
+
+	os.Remove(cacheFile)
+	_, err = GetReleases(cacheDir, false)
+	assert.EqualError(t, err, "no network")
+}
+
+func TestKnownReleasesIgnoresStaleCache(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "minishift-test-releases-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	assert.Nil(t, KnownReleases(cacheDir))
+
+	cacheFile := filepath.Join(cacheDir, ReleaseCacheFile)
+	err = writeReleaseCache(cacheFile, &releaseCache{Fetched: time.Now(), Tags: []string{"v3.11.0"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v3.11.0"}, KnownReleases(cacheDir))
+
+	err = writeReleaseCache(cacheFile, &releaseCache{Fetched: time.Now().Add(-2 * ReleaseCacheTTL), Tags: []string{"v3.11.0"}})
+	assert.NoError(t, err)
+	assert.Nil(t, KnownReleases(cacheDir))
+}
+
+func TestListReleases(t *testing.T) {
+	tags := []string{"v3.11.0", "v3.9.0", "v3.10.0", "v4.0.0-alpha.0"}
+	releases, err := ListReleases(tags, []string{"v3.11.0", "v3.7.1"}, "v3.10.0", "v3.11.0")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Release{
+		{Version: "v3.7.1", Compatible: false, Cached: true},
+		{Version: "v3.10.0", Compatible: true},
+		{Version: "v3.11.0", Compatible: true, Cached: true, Default: true},
+		{Version: "v4.0.0-alpha.0", Compatible: false},
+	}, releases)
+
+	var out bytes.Buffer
+	PrintReleases(&out, releases)
+	assert.Equal(t, "The following OpenShift versions are available: \n"+
+		"\t- v3.7.1 (cached, not supported)\n"+
+		"\t- v3.10.0\n"+
+		"\t- v3.11.0 (default, cached)\n"+
+		"\t- v4.0.0-alpha.0 (not supported)\n", out.String())
+}
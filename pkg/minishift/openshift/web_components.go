@@ -0,0 +1,102 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/minishift/minishift/pkg/minishift/oc"
+)
+
+const (
+	// ConsoleComponent is the OpenShift web console, which is served by the API server instead of a route
+	ConsoleComponent = "console"
+)
+
+// WebComponent is a component with a web UI which can be opened via its route or a port-forward to its service.
+type WebComponent struct {
+	Name      string
+	Namespace string
+	Service   string
+	Port      int
+	// AddOn is the add-on deploying the component, if any
+	AddOn string
+}
+
+var webComponents = map[string]WebComponent{
+	"grafana":          {Name: "grafana", Namespace: "monitoring", Service: "grafana", Port: 3000, AddOn: "monitoring"},
+	"prometheus":       {Name: "prometheus", Namespace: "monitoring", Service: "prometheus", Port: 9090, AddOn: "monitoring"},
+	"kibana":           {Name: "kibana", Namespace: "logging", Service: "kibana", Port: 5601, AddOn: "logging"},
+	"registry-console": {Name: "registry-console", Namespace: "default", Service: "registry-console", Port: 9000},
+}
+
+// WebComponentNames returns the names of all components which can be opened, including the console.
+func WebComponentNames() []string {
+	names := []string{ConsoleComponent}
+	for name := range webComponents {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// GetWebComponent returns the component of the specified name. The console is not a WebComponent.
+func GetWebComponent(name string) (WebComponent, error) {
+	component, ok := webComponents[name]
+	if !ok {
+		return WebComponent{}, fmt.Errorf("Unknown component '%s'. Supported components are %v", name, WebComponentNames())
+	}
+	return component, nil
+}
+
+// IsInstalled returns true if the service of the component exists.
+func (c WebComponent) IsInstalled(ocRunner *oc.OcRunner) bool {
+	_, err := ocRunner.RunAsAdmin("get", "service", c.Service, "-n", c.Namespace)
+	return err == nil
+}
+
+// RouteURL returns the URL of the first route exposing the service of the component or the empty string if the
+// service is not exposed.
+func (c WebComponent) RouteURL(ocRunner *oc.OcRunner) (string, error) {
+	out, err := ocRunner.RunAsAdmin("get", "route", "-n", c.Namespace, "-o", "json")
+	if err != nil {
+		return "", fmt.Errorf("Error getting the routes of '%s': %v", c.Name, err)
+	}
+	return routeURLForService(out, c.Service)
+}
+
+// PortForwardArgs returns the arguments for 'oc' forwarding localPort to the service of the component.
+func (c WebComponent) PortForwardArgs(ocRunner *oc.OcRunner, localPort int) []string {
+	return []string{fmt.Sprintf("--config=%s", ocRunner.KubeConfigPath), "port-forward", fmt.Sprintf("svc/%s", c.Service),
+		fmt.Sprintf("%d:%d", localPort, c.Port), "-n", c.Namespace, "--as", "system:admin"}
+}
+
+func routeURLForService(content string, service string) (string, error) {
+	var routes RouteSpec
+	if err := json.Unmarshal([]byte(content), &routes); err != nil {
+		return "", fmt.Errorf("Error parsing the routes: %v", err)
+	}
+
+	for _, item := range routes.Items {
+		if item.Spec.To.Name == service && item.Spec.Host != "" {
+			return genUrl(Route{Name: service, Tls: item.Spec.Tls.Termination}, item.Spec.Host), nil
+		}
+	}
+	return "", nil
+}
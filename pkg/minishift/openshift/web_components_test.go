@@ -0,0 +1,53 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRoutes = `{"items": [
+  {"spec": {"host": "prometheus-monitoring.192.168.42.10.nip.io", "to": {"name": "prometheus"}}},
+  {"spec": {"host": "kibana-logging.192.168.42.10.nip.io", "tls": {"termination": "edge"}, "to": {"name": "kibana"}}}
+]}`
+
+func Test_route_url_uses_scheme_of_route(t *testing.T) {
+	url, err := routeURLForService(testRoutes, "prometheus")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://prometheus-monitoring.192.168.42.10.nip.io", url)
+
+	url, err = routeURLForService(testRoutes, "kibana")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://kibana-logging.192.168.42.10.nip.io", url)
+
+	url, err = routeURLForService(testRoutes, "grafana")
+	assert.NoError(t, err)
+	assert.Empty(t, url, "Services without route should not have a URL")
+}
+
+func Test_web_components_include_console(t *testing.T) {
+	assert.Equal(t, []string{"console", "grafana", "kibana", "prometheus", "registry-console"}, WebComponentNames())
+
+	_, err := GetWebComponent("console")
+	assert.Error(t, err)
+
+	component, err := GetWebComponent("kibana")
+	assert.NoError(t, err)
+	assert.Equal(t, "logging", component.Namespace)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/minishift/minishift/pkg/minishift/oc"
+)
+
+const (
+	// CatalogNamespace is the namespace the 'olm' add-on installs the Operator Lifecycle Manager and its catalogs into
+	CatalogNamespace = "olm"
+)
+
+// Operator is a package of an installed catalog which can be subscribed to.
+type Operator struct {
+	Name           string
+	DisplayName    string
+	Provider       string
+	Catalog        string
+	DefaultChannel string
+	Channels       []string
+}
+
+type packageManifestList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Status struct {
+			CatalogSource  string `json:"catalogSource"`
+			DefaultChannel string `json:"defaultChannel"`
+			Provider       struct {
+				Name string `json:"name"`
+			} `json:"provider"`
+			Channels []struct {
+				Name           string `json:"name"`
+				CurrentCSVDesc struct {
+					DisplayName string `json:"displayName"`
+				} `json:"currentCSVDesc"`
+			} `json:"channels"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// List returns the operators of the installed catalogs sorted by name. If catalog is not empty, only the operators
+// of that catalog are returned.
+func List(ocRunner *oc.OcRunner, catalog string) ([]Operator, error) {
+	out, err := ocRunner.RunAsAdmin("get", "packagemanifests", "-n", CatalogNamespace, "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the operators. Make sure the 'olm' add-on is applied: %v", err)
+	}
+
+	operators, err := parseOperators(out)
+	if err != nil {
+		return nil, err
+	}
+	if catalog == "" {
+		return operators, nil
+	}
+
+	var filtered []Operator
+	for _, operator := range operators {
+		if operator.Catalog == catalog {
+			filtered = append(filtered, operator)
+		}
+	}
+	return filtered, nil
+}
+
+// parseOperators parses the output of 'oc get packagemanifests -o json'.
+func parseOperators(content string) ([]Operator, error) {
+	list := packageManifestList{}
+	if err := json.Unmarshal([]byte(content), &list); err != nil {
+		return nil, fmt.Errorf("Error parsing the package manifests: %v", err)
+	}
+
+	var operators []Operator
+	for _, item := range list.Items {
+		operator := Operator{
+			Name:           item.Metadata.Name,
+			Provider:       item.Status.Provider.Name,
+			Catalog:        item.Status.CatalogSource,
+			DefaultChannel: item.Status.DefaultChannel,
+		}
+		for _, channel := range item.Status.Channels {
+			operator.Channels = append(operator.Channels, channel.Name)
+			if channel.Name == operator.DefaultChannel {
+				operator.DisplayName = channel.CurrentCSVDesc.DisplayName
+			}
+		}
+		if operator.DisplayName == "" {
+			operator.DisplayName = operator.Name
+		}
+		operators = append(operators, operator)
+	}
+	sort.Slice(operators, func(i, j int) bool { return operators[i].Name < operators[j].Name })
+	return operators, nil
+}
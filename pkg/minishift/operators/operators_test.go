@@ -0,0 +1,83 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/oc"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPackageManifests = `{"items": [
+  {"metadata": {"name": "strimzi-kafka-operator"}, "status": {"catalogSource": "operatorhubio-catalog", "defaultChannel": "stable",
+    "provider": {"name": "Strimzi"}, "channels": [{"name": "stable", "currentCSVDesc": {"displayName": "Strimzi Apache Kafka Operator"}}]}},
+  {"metadata": {"name": "etcd"}, "status": {"catalogSource": "operatorhubio-catalog", "defaultChannel": "singlenamespace-alpha",
+    "provider": {"name": "CNCF"}, "channels": [{"name": "clusterwide-alpha", "currentCSVDesc": {"displayName": "etcd (cluster wide)"}},
+    {"name": "singlenamespace-alpha", "currentCSVDesc": {"displayName": "etcd"}}]}},
+  {"metadata": {"name": "my-operator"}, "status": {"catalogSource": "my-catalog", "defaultChannel": "alpha", "provider": {"name": "ACME"}}}
+]}`
+
+type fakeOcRunner struct {
+	output string
+}
+
+func (r *fakeOcRunner) Run(stdOut io.Writer, stdErr io.Writer, commandPath string, args ...string) int {
+	fmt.Fprint(stdOut, r.output)
+	return 0
+}
+
+func (r *fakeOcRunner) Output(command string, args ...string) ([]byte, error) {
+	return nil, nil
+}
+
+func Test_operators_are_parsed_and_sorted(t *testing.T) {
+	operators, err := parseOperators(testPackageManifests)
+	assert.NoError(t, err)
+	assert.Len(t, operators, 3)
+
+	assert.Equal(t, Operator{
+		Name:           "etcd",
+		DisplayName:    "etcd",
+		Provider:       "CNCF",
+		Catalog:        "operatorhubio-catalog",
+		DefaultChannel: "singlenamespace-alpha",
+		Channels:       []string{"clusterwide-alpha", "singlenamespace-alpha"},
+	}, operators[0])
+	assert.Equal(t, "my-operator", operators[1].DisplayName, "Name should be used if there is no display name")
+	assert.Equal(t, "Strimzi Apache Kafka Operator", operators[2].DisplayName)
+}
+
+func Test_operators_are_filtered_by_catalog(t *testing.T) {
+	ocRunner := &oc.OcRunner{OcPath: "oc", KubeConfigPath: "/tmp/kubeconfig", Runner: &fakeOcRunner{output: testPackageManifests}}
+
+	operators, err := List(ocRunner, "my-catalog")
+	assert.NoError(t, err)
+	assert.Len(t, operators, 1)
+	assert.Equal(t, "my-operator", operators[0].Name)
+
+	operators, err = List(ocRunner, "")
+	assert.NoError(t, err)
+	assert.Len(t, operators, 3)
+}
+
+func Test_invalid_package_manifests_are_rejected(t *testing.T) {
+	_, err := parseOperators("not json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+// builtinPresets are the presets shipped with Minishift, keyed by name
+var builtinPresets = map[string]string{
+	"developer": `description: Roomy VM for day-to-day development with cached images and mounted host folders
+config:
+  memory: 8GB
+  cpus: 4
+  disk-size: 40GB
+  image-caching: true
+  hostfolders-automount: true
+addons:
+  - admin-user
+  - anyuid
+`,
+	"ci": `description: Small VM for unattended runs in a CI pipeline
+config:
+  memory: 4GB
+  cpus: 2
+  disk-size: 20GB
+  skip-registration: true
+  warn-check-deprecation: false
+addons:
+  - admin-user
+`,
+	"demo": `description: VM with the service catalog and the xPaaS templates for demos
+config:
+  memory: 8GB
+  cpus: 4
+  service-catalog: true
+addons:
+  - admin-user
+  - anyuid
+  - xpaas
+`,
+}
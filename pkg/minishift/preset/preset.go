@@ -0,0 +1,182 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const fileExtension = ".yaml"
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Preset is a named bundle of configuration properties and add-ons applied by 'minishift start --preset'.
+type Preset struct {
+	Name        string                 `yaml:"-"`
+	Description string                 `yaml:"description,omitempty"`
+	Config      map[string]interface{} `yaml:"config,omitempty"`
+	AddOns      []string               `yaml:"addons,omitempty"`
+	// Source is the file the preset is read from. It is empty for the presets shipped with Minishift.
+	Source string `yaml:"-"`
+}
+
+// Parse reads a preset from its YAML representation.
+func Parse(name string, content []byte) (*Preset, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("Invalid preset name '%s'. Use letters, digits, '.', '_' and '-' only", name)
+	}
+
+	preset := &Preset{}
+	if err := yaml.Unmarshal(content, preset); err != nil {
+		return nil, fmt.Errorf("Invalid preset '%s': %v", name, err)
+	}
+	preset.Name = name
+	return preset, nil
+}
+
+// Load returns the preset with the specified name. Presets in dir take precedence over the shipped presets of the
+// same name, so that a team can adjust them.
+func Load(dir string, name string) (*Preset, error) {
+	path := filepath.Join(dir, name+fileExtension)
+	content, err := ioutil.ReadFile(path)
+	if err == nil {
+		preset, err := Parse(name, content)
+		if err != nil {
+			return nil, err
+		}
+		preset.Source = path
+		return preset, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if content, ok := builtinPresets[name]; ok {
+		return Parse(name, []byte(content))
+	}
+	return nil, fmt.Errorf("Preset '%s' does not exist. Run 'minishift preset list' for the available presets", name)
+}
+
+// List returns the shipped presets and the presets in dir, sorted by name.
+func List(dir string) ([]*Preset, error) {
+	names := make(map[string]bool)
+	for name := range builtinPresets {
+		names[name] = true
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+fileExtension))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		names[strings.TrimSuffix(filepath.Base(file), fileExtension)] = true
+	}
+
+	var presets []*Preset
+	for name := range names {
+		preset, err := Load(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, preset)
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+	return presets, nil
+}
+
+// ReadFile reads the preset file, naming the preset after the file without extension.
+func ReadFile(file string) (*Preset, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), content)
+}
+
+// Add copies the preset file into dir, making it available under the name of the file without extension.
+func Add(dir string, file string) (*Preset, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	preset, err := Parse(name, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, err
+	}
+	preset.Source = filepath.Join(dir, name+fileExtension)
+	if err := ioutil.WriteFile(preset.Source, content, 0644); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// Remove deletes the user-defined preset from dir. The presets shipped with Minishift cannot be removed.
+func Remove(dir string, name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("Invalid preset name '%s'. Use letters, digits, '.', '_' and '-' only", name)
+	}
+
+	path := filepath.Join(dir, name+fileExtension)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if _, ok := builtinPresets[name]; ok {
+			return fmt.Errorf("Preset '%s' is shipped with Minishift and cannot be removed", name)
+		}
+		return fmt.Errorf("Preset '%s' does not exist", name)
+	}
+	return os.Remove(path)
+}
+
+// Values returns the configuration properties of the preset in the format accepted by 'minishift config set'.
+// Lists are joined with commas.
+func (p *Preset) Values() map[string]string {
+	values := make(map[string]string)
+	for name, value := range p.Config {
+		if list, ok := value.([]interface{}); ok {
+			var entries []string
+			for _, entry := range list {
+				entries = append(entries, fmt.Sprintf("%v", entry))
+			}
+			values[name] = strings.Join(entries, ",")
+			continue
+		}
+		values[name] = fmt.Sprintf("%v", value)
+	}
+	return values
+}
+
+// YAML returns the YAML representation of the preset, for example to share it as a file.
+func (p *Preset) YAML() (string, error) {
+	out, err := yaml.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
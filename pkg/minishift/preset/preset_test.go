@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preset
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Builtin_Presets_Are_Valid(t *testing.T) {
+	for name := range builtinPresets {
+		preset, err := Load("", name)
+		assert.NoError(t, err, name)
+		assert.NotEmpty(t, preset.Description, name)
+		assert.NotEmpty(t, preset.Values(), name)
+		assert.Empty(t, preset.Source, name)
+	}
+}
+
+func Test_Presets_In_Dir_Override_Builtin_Presets(t *testing.T) {
+	dir := setupPresetsDir(t)
+	defer os.RemoveAll(dir)
+
+	writePreset(t, dir, "ci", "config:\n  memory: 2GB\n")
+	writePreset(t, dir, "team", "description: Team defaults\nconfig:\n  cpus: 3\n  insecure-registry:\n    - 172.30.0.0/16\n    - registry.local\naddons:\n  - anyuid\n")
+
+	preset, err := Load(dir, "ci")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"memory": "2GB"}, preset.Values())
+	assert.Equal(t, filepath.Join(dir, "ci.yaml"), preset.Source)
+
+	preset, err = Load(dir, "team")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cpus": "3", "insecure-registry": "172.30.0.0/16,registry.local"}, preset.Values())
+	assert.Equal(t, []string{"anyuid"}, preset.AddOns)
+
+	presets, err := List(dir)
+	assert.NoError(t, err)
+	var names []string
+	for _, preset := range presets {
+		names = append(names, preset.Name)
+	}
+	assert.Equal(t, []string{"ci", "demo", "developer", "team"}, names)
+}
+
+func Test_Unknown_Or_Invalid_Preset(t *testing.T) {
+	dir := setupPresetsDir(t)
+	defer os.RemoveAll(dir)
+
+	_, err := Load(dir, "missing")
+	assert.EqualError(t, err, "Preset 'missing' does not exist. Run 'minishift preset list' for the available presets")
+
+	writePreset(t, dir, "broken", "config: [memory]\n")
+	_, err = Load(dir, "broken")
+	assert.Error(t, err)
+
+	_, err = Parse("../evil", []byte{})
+	assert.Error(t, err)
+}
+
+func Test_Added_Preset_Can_Be_Loaded(t *testing.T) {
+	dir := setupPresetsDir(t)
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "shared", "team.yml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(source), 0777))
+	assert.NoError(t, ioutil.WriteFile(source, []byte("config:\n  memory: 6GB\n"), 0644))
+
+	presetsDir := filepath.Join(dir, "presets")
+	added, err := Add(presetsDir, source)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(presetsDir, "team.yaml"), added.Source)
+
+	preset, err := Load(presetsDir, "team")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"memory": "6GB"}, preset.Values())
+}
+
+func Test_Remove_Preset(t *testing.T) {
+	dir := setupPresetsDir(t)
+	defer os.RemoveAll(dir)
+
+	writePreset(t, dir, "ci", "config:\n  memory: 2GB\n")
+	assert.NoError(t, Remove(dir, "ci"))
+
+	preset, err := Load(dir, "ci")
+	assert.NoError(t, err)
+	assert.Empty(t, preset.Source, "The built-in preset is used again")
+
+	assert.EqualError(t, Remove(dir, "ci"), "Preset 'ci' is shipped with Minishift and cannot be removed")
+	assert.EqualError(t, Remove(dir, "missing"), "Preset 'missing' does not exist")
+	assert.EqualError(t, Remove(dir, "../team"), "Invalid preset name '../team'. Use letters, digits, '.', '_' and '-' only")
+}
+
+func setupPresetsDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "minishift-test-presets-")
+	assert.NoError(t, err, "Error creating temp directory")
+	return dir
+}
+
+func writePreset(t *testing.T, dir string, name string, content string) {
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".yaml"), []byte(content), 0644))
+}
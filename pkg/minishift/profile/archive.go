@@ -0,0 +1,211 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/util/archive"
+)
+
+const (
+	// ArchiveManifestFileName is the name of the file describing the content of a profile archive
+	ArchiveManifestFileName = "profile.json"
+
+	configDir   = "config"
+	certsDir    = "certs"
+	addonsDir   = "addons"
+	machinesDir = "machines"
+)
+
+// ArchiveManifest describes the content of a profile archive.
+type ArchiveManifest struct {
+	Profile          string `json:"profile"`
+	MinishiftVersion string `json:"minishift-version"`
+	// HomeDir is the home directory of the exported profile. It is used to relocate the machine configuration on import.
+	HomeDir      string `json:"home-dir"`
+	IncludesDisk bool   `json:"includes-disk"`
+}
+
+// Export writes the configuration, certificates and add-ons of the profile in profileHome to archivePath. If the
+// manifest requests it, the machine directory including the disk image is added as well, in which case the VM must be stopped.
+// A partially written archive is removed again on failure.
+func Export(archivePath string, profileHome string, manifest ArchiveManifest) error {
+	if err := export(archivePath, profileHome, manifest); err != nil {
+		os.Remove(archivePath)
+		return err
+	}
+	return nil
+}
+
+func export(archivePath string, profileHome string, manifest ArchiveManifest) error {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifest.HomeDir = profileHome
+	rawManifest, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := archive.AddTarContent(tarWriter, ArchiveManifestFileName, rawManifest); err != nil {
+		return err
+	}
+
+	for _, entry := range exportedEntries(manifest) {
+		if err := addTree(tarWriter, profileHome, entry); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// ReadArchiveManifest returns the manifest of the profile archive at archivePath.
+func ReadArchiveManifest(archivePath string) (*ArchiveManifest, error) {
+	var manifest *ArchiveManifest
+	err := archive.WalkTarGz(archivePath, func(header *tar.Header, r io.Reader) (bool, error) {
+		if header.Name != ArchiveManifestFileName {
+			return true, nil
+		}
+		manifest = &ArchiveManifest{}
+		return false, json.NewDecoder(r).Decode(manifest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("'%s' is not a Minishift profile archive", archivePath)
+	}
+	return manifest, nil
+}
+
+// Import extracts the profile archive at archivePath into profileHome, which must not exist yet, as profile name.
+// Archives containing a disk image can only be imported under the name they were exported with, since the VM
+// is registered under this name.
+func Import(archivePath string, profileHome string, name string) (*ArchiveManifest, error) {
+	manifest, err := ReadArchiveManifest(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.IncludesDisk && name != manifest.Profile {
+		return nil, fmt.Errorf("The archive contains the disk image of profile '%s' and cannot be imported under a different name", manifest.Profile)
+	}
+	if _, err := os.Stat(profileHome); err == nil {
+		return nil, fmt.Errorf("The directory '%s' already exists", profileHome)
+	}
+
+	instanceConfig := path.Join(configDir, manifest.Profile+".json")
+	err = archive.WalkTarGz(archivePath, func(header *tar.Header, r io.Reader) (bool, error) {
+		// backslashes and drive letters are path separators and volumes on Windows, where they could escape profileHome
+		entry := path.Clean(header.Name)
+		if strings.HasPrefix(entry, "..") || path.IsAbs(entry) || strings.ContainsAny(entry, "\\:") {
+			return false, fmt.Errorf("Invalid profile archive entry '%s'", header.Name)
+		}
+		if entry == ArchiveManifestFileName {
+			return true, nil
+		}
+		if entry == instanceConfig {
+			entry = path.Join(configDir, name+".json")
+		}
+		return true, archive.ExtractFile(r, filepath.Join(profileHome, filepath.FromSlash(entry)), header.FileInfo().Mode())
+	})
+	if err != nil {
+		os.RemoveAll(profileHome)
+		return nil, err
+	}
+
+	if manifest.IncludesDisk {
+		machineConfig := filepath.Join(profileHome, machinesDir, manifest.Profile, "config.json")
+		if err := relocate(machineConfig, manifest.HomeDir, profileHome); err != nil {
+			os.RemoveAll(profileHome)
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+// exportedEntries returns the slash separated paths, relative to the profile home, which make up a profile archive
+func exportedEntries(manifest ArchiveManifest) []string {
+	entries := []string{
+		path.Join(configDir, "config.json"),
+		path.Join(configDir, manifest.Profile+".json"),
+		certsDir,
+		addonsDir,
+	}
+	if manifest.IncludesDisk {
+		entries = append(entries, path.Join(machinesDir, manifest.Profile), path.Join(machinesDir, manifest.Profile+"-state.json"))
+	}
+	return entries
+}
+
+// relocate replaces the old profile home with the new one in the paths of the libmachine host configuration
+func relocate(machineConfig string, oldHome string, newHome string) error {
+	raw, err := ioutil.ReadFile(machineConfig)
+	if os.IsNotExist(err) || oldHome == newHome {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// paths are JSON encoded in the configuration, which matters for the backslashes of Windows paths
+	oldPath, _ := json.Marshal(oldHome)
+	newPath, _ := json.Marshal(newHome)
+	raw = bytes.Replace(raw, bytes.Trim(oldPath, "\""), bytes.Trim(newPath, "\""), -1)
+	return ioutil.WriteFile(machineConfig, raw, 0600)
+}
+
+// addTree adds the file or directory entry of the profile home to the archive. Missing entries are skipped.
+func addTree(w *tar.Writer, profileHome string, entry string) error {
+	root := filepath.Join(profileHome, filepath.FromSlash(entry))
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		relative, err := filepath.Rel(profileHome, file)
+		if err != nil {
+			return err
+		}
+		return archive.AddTarFile(w, filepath.ToSlash(relative), file, info.Mode())
+	})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/util/archive"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Exported_Profile_Can_Be_Imported_Under_New_Name(t *testing.T) {
+	testDir, sourceHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	archive := filepath.Join(testDir, "profile.tar.gz")
+	err := Export(archive, sourceHome, ArchiveManifest{Profile: "foo", MinishiftVersion: "1.0.0"})
+	assert.NoError(t, err)
+
+	targetHome := filepath.Join(testDir, "bar")
+	manifest, err := Import(archive, targetHome, "bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", manifest.Profile)
+	assert.False(t, manifest.IncludesDisk)
+
+	assertFileContent(t, filepath.Join(targetHome, "config", "config.json"), "{\"memory\": \"4GB\"}")
+	assertFileContent(t, filepath.Join(targetHome, "config", "bar.json"), "{}")
+	assertFileContent(t, filepath.Join(targetHome, "certs", "ca.pem"), "ca")
+	assertFileContent(t, filepath.Join(targetHome, "addons", "admin-user", "admin-user.addon"), "# Name: admin-user")
+	assert.False(t, exists(filepath.Join(targetHome, "machines")), "The machine directory should not be exported")
+	assert.False(t, exists(filepath.Join(targetHome, "config", "global.json")), "The global configuration should not be exported")
+}
+
+func Test_Exported_Disk_Is_Relocated_On_Import(t *testing.T) {
+	testDir, sourceHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	archive := filepath.Join(testDir, "profile.tar.gz")
+	err := Export(archive, sourceHome, ArchiveManifest{Profile: "foo", IncludesDisk: true})
+	assert.NoError(t, err)
+
+	_, err = Import(archive, filepath.Join(testDir, "bar"), "bar")
+	assert.Error(t, err, "Archives with disk image should not be importable under a different name")
+
+	targetHome := filepath.Join(testDir, "imported")
+	_, err = Import(archive, targetHome, "foo")
+	assert.NoError(t, err)
+	assertFileContent(t, filepath.Join(targetHome, "machines", "foo", "disk.img"), "disk")
+	assertFileContent(t, filepath.Join(targetHome, "machines", "foo", "config.json"), "{\"StorePath\": \""+jsonPath(targetHome)+"\"}")
+}
+
+func Test_Import_Into_Existing_Directory_Fails(t *testing.T) {
+	testDir, sourceHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	archive := filepath.Join(testDir, "profile.tar.gz")
+	assert.NoError(t, Export(archive, sourceHome, ArchiveManifest{Profile: "foo"}))
+
+	_, err := Import(archive, sourceHome, "foo")
+	assert.Error(t, err)
+}
+
+func Test_Failed_Export_Leaves_No_Archive(t *testing.T) {
+	testDir, sourceHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	archive := filepath.Join(testDir, "profile.tar.gz")
+	err := Export(archive, filepath.Join(sourceHome, "certs", "ca.pem", "home"), ArchiveManifest{Profile: "foo"})
+	assert.Error(t, err)
+	assert.False(t, exists(archive))
+}
+
+func Test_Import_Rejects_Entries_With_Backslashes(t *testing.T) {
+	testDir, _ := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	archivePath := filepath.Join(testDir, "profile.tar.gz")
+	file, err := os.Create(archivePath)
+	assert.NoError(t, err)
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	assert.NoError(t, archive.AddTarContent(tarWriter, ArchiveManifestFileName, []byte("{\"Profile\": \"foo\"}")))
+	assert.NoError(t, archive.AddTarContent(tarWriter, "config\\..\\..\\evil.json", []byte("{}")))
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+	assert.NoError(t, file.Close())
+
+	home := filepath.Join(testDir, "imported")
+	_, err = Import(archivePath, home, "foo")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid profile archive entry")
+	assert.False(t, exists(home))
+}
+
+func Test_Reading_Manifest_Of_Invalid_Archive_Fails(t *testing.T) {
+	testDir, sourceHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	_, err := ReadArchiveManifest(filepath.Join(sourceHome, "certs", "ca.pem"))
+	assert.Error(t, err)
+}
+
+func setupProfileHome(t *testing.T) (string, string) {
+	testDir, err := ioutil.TempDir("", "minishift-test-profile-archive-")
+	assert.NoError(t, err, "Error creating temp directory")
+
+	home := filepath.Join(testDir, "foo")
+	files := map[string]string{
+		filepath.Join("config", "config.json"):                    "{\"memory\": \"4GB\"}",
+		filepath.Join("config", "foo.json"):                       "{}",
+		filepath.Join("config", "global.json"):                    "{}",
+		filepath.Join("certs", "ca.pem"):                          "ca",
+		filepath.Join("addons", "admin-user", "admin-user.addon"): "# Name: admin-user",
+		filepath.Join("machines", "foo", "disk.img"):              "disk",
+		filepath.Join("machines", "foo", "config.json"):           "{\"StorePath\": \"" + jsonPath(home) + "\"}",
+	}
+	for name, content := range files {
+		path := filepath.Join(home, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	return testDir, home
+}
+
+// jsonPath returns the path as it appears within a JSON string
+func jsonPath(path string) string {
+	return strings.Replace(path, "\\", "\\\\", -1)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func assertFileContent(t *testing.T, path string, expected string) {
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
@@ -0,0 +1,247 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/filehelper"
+	"github.com/minishift/minishift/pkg/util/logging"
+)
+
+var logger = logging.For("profile")
+
+// linkedCloner creates a linked clone of a stopped VM, sharing the disk image of the source VM.
+type linkedCloner interface {
+	clone(src string, dst string, machineDir string) error
+	// discard removes the clone dst of src and whatever clone created for it, also if clone failed half-way
+	discard(src string, dst string)
+	// isDisk returns true for the files of the machine directory which make up the disk image of the VM
+	isDisk(file string) bool
+}
+
+var linkedCloners = map[string]linkedCloner{
+	"virtualbox": &virtualBoxCloner{},
+}
+
+// SupportsLinkedClone returns true if the VM of the driver with the specified name can be cloned.
+func SupportsLinkedClone(driverName string) bool {
+	_, ok := linkedCloners[driverName]
+	return ok
+}
+
+// Clone copies the configuration, add-on state, certificates and add-ons of profile src in srcHome to profile dst
+// in dstHome. The VM is not cloned, see CloneMachine.
+func Clone(srcHome string, src string, dstHome string, dst string) error {
+	if err := os.MkdirAll(filepath.Join(dstHome, configDir), 0777); err != nil {
+		return err
+	}
+
+	configFiles := map[string]string{
+		"config.json": "config.json",
+		src + ".json": dst + ".json",
+	}
+	for srcFile, dstFile := range configFiles {
+		srcPath := filepath.Join(srcHome, configDir, srcFile)
+		if !filehelper.Exists(srcPath) {
+			continue
+		}
+		if err := filehelper.CopyFile(srcPath, filepath.Join(dstHome, configDir, dstFile)); err != nil {
+			return err
+		}
+	}
+
+	for _, dir := range []string{certsDir, addonsDir} {
+		if !filehelper.IsDirectory(filepath.Join(srcHome, dir)) {
+			continue
+		}
+		if err := filehelper.CopyDir(filepath.Join(srcHome, dir), filepath.Join(dstHome, dir)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CloneMachine creates a linked clone of the stopped VM of profile src for profile dst. The VM must have been
+// created by the driver with the specified name, which must support linked clones.
+func CloneMachine(srcHome string, src string, dstHome string, dst string, driverName string) error {
+	cloner, ok := linkedCloners[driverName]
+	if !ok {
+		return fmt.Errorf("The driver '%s' does not support cloning the VM", driverName)
+	}
+
+	dstMachineDir := filepath.Join(dstHome, machinesDir, dst)
+	if err := os.MkdirAll(dstMachineDir, 0777); err != nil {
+		return err
+	}
+
+	if err := cloner.clone(src, dst, dstMachineDir); err != nil {
+		cloner.discard(src, dst)
+		return err
+	}
+
+	if err := copyMachineFiles(srcHome, src, dstHome, dst, cloner); err != nil {
+		cloner.discard(src, dst)
+		return err
+	}
+	return nil
+}
+
+// copyMachineFiles copies the files of the machine directory of src besides its disk image to the one of dst,
+// rewriting the host configuration and the instance state for dst
+func copyMachineFiles(srcHome string, src string, dstHome string, dst string, cloner linkedCloner) error {
+	srcMachineDir := filepath.Join(srcHome, machinesDir, src)
+	dstMachineDir := filepath.Join(dstHome, machinesDir, dst)
+	files, err := ioutil.ReadDir(srcMachineDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !file.Mode().IsRegular() || file.Name() == "config.json" || cloner.isDisk(file.Name()) {
+			continue
+		}
+		if err := filehelper.CopyFile(filepath.Join(srcMachineDir, file.Name()), filepath.Join(dstMachineDir, file.Name())); err != nil {
+			return err
+		}
+	}
+
+	replacer := strings.NewReplacer(srcMachineDir, dstMachineDir, srcHome, dstHome)
+	if err := rewriteHostConfig(filepath.Join(srcMachineDir, "config.json"), filepath.Join(dstMachineDir, "config.json"), dst, replacer); err != nil {
+		return err
+	}
+
+	return cloneInstanceState(filepath.Join(srcHome, machinesDir, src+"-state.json"), filepath.Join(dstHome, machinesDir, dst+"-state.json"))
+}
+
+// rewriteHostConfig writes the libmachine host configuration in srcConfig to dstConfig, changing the machine name
+// and replacing the paths using the specified replacer
+func rewriteHostConfig(srcConfig string, dstConfig string, name string, replacer *strings.Replacer) error {
+	raw, err := ioutil.ReadFile(srcConfig)
+	if err != nil {
+		return err
+	}
+
+	var hostConfig map[string]interface{}
+	if err := json.Unmarshal(raw, &hostConfig); err != nil {
+		return err
+	}
+
+	hostConfig = replacePaths(hostConfig, replacer).(map[string]interface{})
+	hostConfig["Name"] = name
+	if driver, ok := hostConfig["Driver"].(map[string]interface{}); ok {
+		driver["MachineName"] = name
+	}
+
+	raw, err = json.MarshalIndent(hostConfig, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dstConfig, raw, 0600)
+}
+
+func replacePaths(value interface{}, replacer *strings.Replacer) interface{} {
+	switch v := value.(type) {
+	case string:
+		return replacer.Replace(v)
+	case map[string]interface{}:
+		for key, element := range v {
+			v[key] = replacePaths(element, replacer)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = replacePaths(element, replacer)
+		}
+	}
+	return value
+}
+
+// cloneInstanceState copies the instance state of the source VM, dropping the processes which belong to the source
+// profile. The last known IP is kept, so that the first start of the clone reconciles the IP change.
+func cloneInstanceState(srcPath string, dstPath string) error {
+	if !filehelper.Exists(srcPath) {
+		return nil
+	}
+
+	instanceState, err := config.NewInstanceStateConfig(srcPath)
+	if err != nil {
+		return err
+	}
+
+	instanceState.FilePath = dstPath
+	instanceState.PreviousKubeContext = ""
+	instanceState.SftpTunnelPID = 0
+	instanceState.HostFolderSyncPIDs = nil
+	instanceState.DockerForwardPID = 0
+	instanceState.DockerForwardAddress = ""
+	instanceState.IPWatchPID = 0
+	instanceState.AgentPID = 0
+	return instanceState.Write()
+}
+
+// virtualBoxCloner clones VirtualBox VMs using a snapshot of the source VM as base of the linked clone.
+type virtualBoxCloner struct{}
+
+func (c *virtualBoxCloner) clone(src string, dst string, machineDir string) error {
+	snapshot := "minishift-clone-" + dst
+	if err := vboxManage("snapshot", src, "take", snapshot); err != nil {
+		return err
+	}
+	return vboxManage("clonevm", src, "--snapshot", snapshot, "--options", "link", "--name", dst, "--basefolder", machineDir, "--register")
+}
+
+// discard unregisters and deletes the cloned VM, if it got registered, and deletes the snapshot it was based on
+func (c *virtualBoxCloner) discard(src string, dst string) {
+	if err := vboxManage("unregistervm", dst, "--delete"); err != nil {
+		logger.Debugf("Unable to delete the cloned VM: %v", err)
+	}
+	if err := vboxManage("snapshot", src, "delete", "minishift-clone-"+dst); err != nil {
+		logger.Debugf("Unable to delete the snapshot of the cloned VM: %v", err)
+	}
+}
+
+func (c *virtualBoxCloner) isDisk(file string) bool {
+	return filepath.Ext(file) == ".vmdk"
+}
+
+func vboxManage(args ...string) error {
+	out, err := exec.Command(VBoxManageCommand(), args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Error running 'VBoxManage %s': %v %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// VBoxManageCommand returns the path of the VBoxManage executable, which on Windows is usually not on the PATH.
+func VBoxManageCommand() string {
+	if runtime.GOOS != "windows" {
+		return "VBoxManage"
+	}
+	for _, env := range []string{"VBOX_INSTALL_PATH", "VBOX_MSI_INSTALL_PATH"} {
+		if dir := os.Getenv(env); dir != "" && filehelper.Exists(filepath.Join(dir, "VBoxManage.exe")) {
+			return filepath.Join(dir, "VBoxManage.exe")
+		}
+	}
+	return "VBoxManage.exe"
+}
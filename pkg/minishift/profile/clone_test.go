@@ -0,0 +1,140 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCloner struct {
+	src, dst, machineDir string
+	err                  error
+	discarded            bool
+}
+
+func (c *fakeCloner) clone(src string, dst string, machineDir string) error {
+	c.src, c.dst, c.machineDir = src, dst, machineDir
+	return c.err
+}
+
+func (c *fakeCloner) discard(src string, dst string) {
+	c.discarded = true
+}
+
+func (c *fakeCloner) isDisk(file string) bool {
+	return file == "disk.img"
+}
+
+func Test_Clone_Copies_Configuration(t *testing.T) {
+	testDir, srcHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	dstHome := filepath.Join(testDir, "bar")
+	err := Clone(srcHome, "foo", dstHome, "bar")
+	assert.NoError(t, err)
+
+	assertFileContent(t, filepath.Join(dstHome, "config", "config.json"), "{\"memory\": \"4GB\"}")
+	assertFileContent(t, filepath.Join(dstHome, "config", "bar.json"), "{}")
+	assertFileContent(t, filepath.Join(dstHome, "certs", "ca.pem"), "ca")
+	assertFileContent(t, filepath.Join(dstHome, "addons", "admin-user", "admin-user.addon"), "# Name: admin-user")
+	assert.False(t, exists(filepath.Join(dstHome, "machines")), "The VM should not be cloned")
+}
+
+func Test_Clone_Machine_Points_Host_Config_To_Clone(t *testing.T) {
+	testDir, srcHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	cloner := &fakeCloner{}
+	linkedCloners["fake"] = cloner
+	defer delete(linkedCloners, "fake")
+
+	hostConfig := map[string]interface{}{
+		"Name": "foo",
+		"Driver": map[string]interface{}{
+			"MachineName": "foo",
+			"StorePath":   srcHome,
+			"SSHKeyPath":  filepath.Join(srcHome, "machines", "foo", "id_rsa"),
+		},
+	}
+	raw, _ := json.Marshal(hostConfig)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(srcHome, "machines", "foo", "config.json"), raw, 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(srcHome, "machines", "foo", "id_rsa"), []byte("key"), 0600))
+	srcState := &config.InstanceStateConfigType{FilePath: filepath.Join(srcHome, "machines", "foo-state.json"), LastKnownIP: "192.168.99.100", AgentPID: 42}
+	assert.NoError(t, srcState.Write())
+
+	dstHome := filepath.Join(testDir, "bar")
+	err := CloneMachine(srcHome, "foo", dstHome, "bar", "fake")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", cloner.src)
+	assert.Equal(t, "bar", cloner.dst)
+	assert.Equal(t, filepath.Join(dstHome, "machines", "bar"), cloner.machineDir)
+
+	assertFileContent(t, filepath.Join(dstHome, "machines", "bar", "id_rsa"), "key")
+	assert.False(t, exists(filepath.Join(dstHome, "machines", "bar", "disk.img")), "The disk image should not be copied")
+
+	raw, err = ioutil.ReadFile(filepath.Join(dstHome, "machines", "bar", "config.json"))
+	assert.NoError(t, err)
+	var clonedConfig map[string]interface{}
+	assert.NoError(t, json.Unmarshal(raw, &clonedConfig))
+	driver := clonedConfig["Driver"].(map[string]interface{})
+	assert.Equal(t, "bar", clonedConfig["Name"])
+	assert.Equal(t, "bar", driver["MachineName"])
+	assert.Equal(t, dstHome, driver["StorePath"])
+	assert.Equal(t, filepath.Join(dstHome, "machines", "bar", "id_rsa"), driver["SSHKeyPath"])
+
+	dstState, err := config.NewInstanceStateConfig(filepath.Join(dstHome, "machines", "bar-state.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.99.100", dstState.LastKnownIP)
+	assert.Equal(t, 0, dstState.AgentPID)
+}
+
+func Test_Clone_Machine_Discards_Failed_Clone(t *testing.T) {
+	testDir, srcHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	cloner := &fakeCloner{err: errors.New("clonevm failed")}
+	linkedCloners["fake"] = cloner
+	defer delete(linkedCloners, "fake")
+
+	err := CloneMachine(srcHome, "foo", filepath.Join(testDir, "bar"), "bar", "fake")
+	assert.Error(t, err)
+	assert.True(t, cloner.discarded)
+
+	// without host configuration in the source machine directory, copying the machine files fails
+	assert.NoError(t, os.Remove(filepath.Join(srcHome, "machines", "foo", "config.json")))
+	cloner = &fakeCloner{}
+	linkedCloners["fake"] = cloner
+	err = CloneMachine(srcHome, "foo", filepath.Join(testDir, "baz"), "baz", "fake")
+	assert.Error(t, err)
+	assert.True(t, cloner.discarded)
+}
+
+func Test_Clone_Machine_Fails_For_Unsupported_Driver(t *testing.T) {
+	assert.False(t, SupportsLinkedClone("xhyve"))
+	assert.True(t, SupportsLinkedClone("virtualbox"))
+
+	err := CloneMachine("src", "foo", "dst", "bar", "xhyve")
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/minishift/config"
+)
+
+var labelKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_./]*[A-Za-z0-9])?$`)
+
+// ParseLabels parses labels in the form key=value. With allowEmptyValue, a label without value is accepted as well,
+// for example to specify the keys of the labels to remove.
+func ParseLabels(specs []string, allowEmptyValue bool) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, spec := range specs {
+		for _, label := range strings.Split(spec, ",") {
+			key, value := label, ""
+			if i := strings.Index(label, "="); i >= 0 {
+				key, value = label[:i], label[i+1:]
+			} else if !allowEmptyValue {
+				return nil, fmt.Errorf("Invalid label '%s'. Labels must be specified as key=value", label)
+			}
+			if !labelKeyRegexp.MatchString(key) {
+				return nil, fmt.Errorf("Invalid label key '%s'. Keys must consist of alphanumeric characters, '-', '_', '.' or '/'", key)
+			}
+			labels[key] = value
+		}
+	}
+	return labels, nil
+}
+
+// FormatLabels returns the labels as sorted, comma separated list of key=value pairs
+func FormatLabels(labels map[string]string) string {
+	var pairs []string
+	for key, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// MatchesSelector returns true if the labels contain all labels of the selector
+func MatchesSelector(labels map[string]string, selector map[string]string) bool {
+	for key, value := range selector {
+		if actual, ok := labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// GetLabels returns the labels of the profile
+func GetLabels(name string) map[string]string {
+	if config.AllInstancesConfig == nil {
+		return nil
+	}
+	return config.AllInstancesConfig.ProfileLabels[name]
+}
+
+// SetLabels adds the labels to the profile, replacing the values of existing labels with the same key
+func SetLabels(name string, labels map[string]string) error {
+	current := map[string]string{}
+	for key, value := range GetLabels(name) {
+		current[key] = value
+	}
+	for key, value := range labels {
+		current[key] = value
+	}
+	return writeLabels(name, current)
+}
+
+// RemoveLabels removes the labels with the specified keys from the profile
+func RemoveLabels(name string, keys ...string) error {
+	current := map[string]string{}
+	for key, value := range GetLabels(name) {
+		current[key] = value
+	}
+	for _, key := range keys {
+		delete(current, key)
+	}
+	return writeLabels(name, current)
+}
+
+// ClearLabels removes all labels of the profile
+func ClearLabels(name string) error {
+	if len(GetLabels(name)) == 0 {
+		return nil
+	}
+	return writeLabels(name, nil)
+}
+
+// RenameLabels moves the labels of the profile to the new name of the profile
+func RenameLabels(oldName string, newName string) error {
+	labels := GetLabels(oldName)
+	if len(labels) == 0 {
+		return nil
+	}
+	delete(config.AllInstancesConfig.ProfileLabels, oldName)
+	return writeLabels(newName, labels)
+}
+
+func writeLabels(name string, labels map[string]string) error {
+	if config.AllInstancesConfig.ProfileLabels == nil {
+		config.AllInstancesConfig.ProfileLabels = map[string]map[string]string{}
+	}
+	if len(labels) == 0 {
+		delete(config.AllInstancesConfig.ProfileLabels, name)
+	} else {
+		config.AllInstancesConfig.ProfileLabels[name] = labels
+	}
+	if err := config.AllInstancesConfig.Write(); err != nil {
+		return fmt.Errorf("Error updating the labels of profile '%s' in config. %s", name, err)
+	}
+	return nil
+}
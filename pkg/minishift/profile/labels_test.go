@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"testing"
+
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels([]string{"team=payments,env=ci", "owner="}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "env": "ci", "owner": ""}, labels)
+	assert.Equal(t, "env=ci,owner=,team=payments", FormatLabels(labels))
+
+	_, err = ParseLabels([]string{"team"}, false)
+	assert.EqualError(t, err, "Invalid label 'team'. Labels must be specified as key=value")
+	_, err = ParseLabels([]string{"te am=payments"}, false)
+	assert.Error(t, err)
+
+	keys, err := ParseLabels([]string{"team", "env"}, true)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+}
+
+func TestMatchesSelector(t *testing.T) {
+	labels := map[string]string{"team": "payments", "env": "ci"}
+	assert.True(t, MatchesSelector(labels, nil))
+	assert.True(t, MatchesSelector(labels, map[string]string{"team": "payments"}))
+	assert.False(t, MatchesSelector(labels, map[string]string{"team": "payments", "env": "demo"}))
+	assert.False(t, MatchesSelector(nil, map[string]string{"owner": ""}))
+}
+
+func TestSettingLabels(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	assert.NoError(t, SetLabels("demo", map[string]string{"team": "payments", "env": "ci"}))
+	assert.NoError(t, SetLabels("demo", map[string]string{"env": "demo"}))
+	assert.Equal(t, map[string]string{"team": "payments", "env": "demo"}, GetLabels("demo"))
+
+	assert.NoError(t, RenameLabels("demo", "payments"))
+	assert.Nil(t, GetLabels("demo"))
+
+	reloaded, err := minishiftConfig.NewAllInstancesConfig(configFilePath)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "env": "demo"}, reloaded.ProfileLabels["payments"])
+
+	assert.NoError(t, RemoveLabels("payments", "team", "env"))
+	assert.Empty(t, minishiftConfig.AllInstancesConfig.ProfileLabels)
+}
@@ -80,6 +80,41 @@ func GetActiveProfile() string {
 	return ""
 }
 
+// IsProtected returns true if the profile was protected with 'minishift profile lock'
+func IsProtected(name string) bool {
+	if config.AllInstancesConfig == nil {
+		return false
+	}
+	for _, protected := range config.AllInstancesConfig.ProtectedProfiles {
+		if protected == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetProtected marks the profile as protected or removes the protection
+func SetProtected(name string, protected bool) error {
+	if IsProtected(name) == protected {
+		return nil
+	}
+
+	var profiles []string
+	for _, profile := range config.AllInstancesConfig.ProtectedProfiles {
+		if profile != name {
+			profiles = append(profiles, profile)
+		}
+	}
+	if protected {
+		profiles = append(profiles, name)
+	}
+	config.AllInstancesConfig.ProtectedProfiles = profiles
+	if err := config.AllInstancesConfig.Write(); err != nil {
+		return fmt.Errorf("Error updating the protection of profile '%s' in config. %s", name, err)
+	}
+	return nil
+}
+
 func SetDefaultProfileActive() error {
 	err := SetActiveProfile(constants.DefaultProfileName)
 	if err != nil {
@@ -64,6 +64,26 @@ func TestSetingGetProfileList(t *testing.T) {
 	assert.EqualValues(t, profileList, actualProfileList, "Profile lists do not match")
 }
 
+func TestSettingProtectedProfile(t *testing.T) {
+	setup(t)
+	defer teardown()
+
+	assert.False(t, IsProtected("demo"))
+	assert.NoError(t, SetProtected("demo", true))
+	assert.NoError(t, SetProtected("demo", true))
+	assert.NoError(t, SetProtected("other", true))
+	assert.True(t, IsProtected("demo"))
+	assert.Equal(t, []string{"demo", "other"}, minishiftConfig.AllInstancesConfig.ProtectedProfiles)
+
+	reloaded, err := minishiftConfig.NewAllInstancesConfig(configFilePath)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"demo", "other"}, reloaded.ProtectedProfiles)
+
+	assert.NoError(t, SetProtected("demo", false))
+	assert.False(t, IsProtected("demo"))
+	assert.True(t, IsProtected("other"))
+}
+
 func setup(t *testing.T) {
 	var err error
 	testDir, err = ioutil.TempDir("", "minishift-test-profile-")
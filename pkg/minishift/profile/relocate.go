@@ -0,0 +1,225 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/util/filehelper"
+)
+
+// Machine identifies the VM of a profile by the home directory of the profile, the machine name and the driver
+// which created it.
+type Machine struct {
+	ProfileHome string
+	Name        string
+	DriverName  string
+}
+
+// relocation tracks a VM whose registration with the hypervisor is moved along with the Minishift home directory
+type relocation struct {
+	machine Machine
+	renamer machineRenamer
+}
+
+// RelocateHome moves the Minishift home directory oldHome, including the cache and all profiles, to newHome, which
+// must not exist or be empty. The stopped VMs of the specified machines are registered with their hypervisor from
+// the new location, and the absolute paths stored in the host configurations and state files are rewritten.
+func RelocateHome(oldHome string, newHome string, machines []Machine) error {
+	if err := ensureRelocationTarget(oldHome, newHome); err != nil {
+		return err
+	}
+
+	var relocations []relocation
+	for _, machine := range machines {
+		newRenamer, ok := machineRenamers[machine.DriverName]
+		if !ok {
+			return fmt.Errorf("The driver '%s' does not support relocating the VM", machine.DriverName)
+		}
+		if newRenamer != nil {
+			relocations = append(relocations, relocation{machine: machine, renamer: newRenamer()})
+		}
+	}
+
+	// unregister all VMs before the first file is moved, restoring the registrations if that fails
+	for i, r := range relocations {
+		driverConfig, err := readDriverConfig(filepath.Join(machineDir(r.machine), "config.json"))
+		if err == nil {
+			err = r.renamer.beforeMove(r.machine.Name, machineDir(r.machine), driverConfig)
+		}
+		if err != nil {
+			restoreRegistrations(relocations[:i])
+			return err
+		}
+	}
+
+	if err := moveDir(oldHome, newHome); err != nil {
+		restoreRegistrations(relocations)
+		return err
+	}
+
+	replacer := strings.NewReplacer(oldHome, newHome)
+	if err := relocateMachines(newHome, machines, relocations, replacer); err != nil {
+		if rollbackErr := rollbackRelocation(oldHome, newHome, machines, relocations); rollbackErr != nil {
+			return fmt.Errorf("%v. Moving the home directory back to '%s' failed as well: %v", err, oldHome, rollbackErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// relocateMachines rewrites the paths of the moved home directory and registers the VMs from their new location.
+func relocateMachines(newHome string, machines []Machine, relocations []relocation, replacer *strings.Replacer) error {
+	for _, machine := range machines {
+		machine.ProfileHome = replacer.Replace(machine.ProfileHome)
+		hostConfig := filepath.Join(machineDir(machine), "config.json")
+		if err := rewriteHostConfig(hostConfig, hostConfig, machine.Name, replacer); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range relocations {
+		r.machine.ProfileHome = replacer.Replace(r.machine.ProfileHome)
+		if err := r.renamer.afterMove(r.machine.Name, r.machine.Name, machineDir(r.machine), replacer); err != nil {
+			return err
+		}
+	}
+
+	return rewriteStateFiles(newHome, replacer)
+}
+
+// rollbackRelocation reverts the paths rewritten so far, moves the home directory back to its old location and
+// registers the VMs from there again.
+func rollbackRelocation(oldHome string, newHome string, machines []Machine, relocations []relocation) error {
+	toNewHome := strings.NewReplacer(oldHome, newHome)
+	toOldHome := strings.NewReplacer(newHome, oldHome)
+	if err := rewriteStateFiles(newHome, toOldHome); err != nil {
+		return err
+	}
+	for _, machine := range machines {
+		machine.ProfileHome = toNewHome.Replace(machine.ProfileHome)
+		hostConfig := filepath.Join(machineDir(machine), "config.json")
+		if err := rewriteHostConfig(hostConfig, hostConfig, machine.Name, toOldHome); err != nil {
+			return err
+		}
+	}
+
+	if err := moveDir(newHome, oldHome); err != nil {
+		return err
+	}
+	restoreRegistrations(relocations)
+	return nil
+}
+
+// ensureRelocationTarget makes sure newHome can take the place of oldHome. An empty directory is removed, so that
+// the home directory can be moved in its place.
+func ensureRelocationTarget(oldHome string, newHome string) error {
+	if filepath.Clean(oldHome) == filepath.Clean(newHome) {
+		return fmt.Errorf("'%s' already is the Minishift home directory", newHome)
+	}
+	if strings.HasPrefix(filepath.Clean(newHome), filepath.Clean(oldHome)+string(filepath.Separator)) {
+		return fmt.Errorf("'%s' is located within the Minishift home directory '%s'", newHome, oldHome)
+	}
+	if !filehelper.Exists(newHome) {
+		return os.MkdirAll(filepath.Dir(newHome), 0777)
+	}
+	if !filehelper.IsDirectory(newHome) || !filehelper.IsEmptyDir(newHome) {
+		return fmt.Errorf("'%s' already exists and is not an empty directory", newHome)
+	}
+	return os.Remove(newHome)
+}
+
+// moveDir moves the directory src to dst. If the directory cannot be renamed, for example since dst is located on
+// another file system, the directory is copied and src is removed afterwards.
+func moveDir(src string, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	if err := filehelper.CopyDir(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return fmt.Errorf("Error copying '%s' to '%s': %v", src, dst, err)
+	}
+	return os.RemoveAll(src)
+}
+
+// restoreRegistrations registers the VMs with their hypervisor from their original location again.
+func restoreRegistrations(relocations []relocation) {
+	for _, r := range relocations {
+		if err := r.renamer.restore(r.machine.Name, r.machine.Name, machineDir(r.machine)); err != nil {
+			fmt.Println(fmt.Sprintf("Error restoring the registration of VM '%s': %v", r.machine.Name, err))
+		}
+	}
+}
+
+// rewriteStateFiles rewrites the paths stored in the configuration and state files of all profiles in home.
+func rewriteStateFiles(home string, replacer *strings.Replacer) error {
+	profileHomes := []string{home}
+	profiles, _ := ioutil.ReadDir(filepath.Join(home, "profiles"))
+	for _, profile := range profiles {
+		if profile.IsDir() {
+			profileHomes = append(profileHomes, filepath.Join(home, "profiles", profile.Name()))
+		}
+	}
+
+	for _, profileHome := range profileHomes {
+		for _, dir := range []string{configDir, machinesDir} {
+			files, err := filepath.Glob(filepath.Join(profileHome, dir, "*.json"))
+			if err != nil {
+				return err
+			}
+			for _, file := range files {
+				if err := rewriteJSONFile(file, replacer); err != nil {
+					return fmt.Errorf("Error updating the paths in '%s': %v", file, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func rewriteJSONFile(path string, replacer *strings.Replacer) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var content interface{}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		// the home directory is moved already, files which cannot be parsed are left untouched instead of failing
+		return nil
+	}
+
+	raw, err = json.MarshalIndent(replacePaths(content, replacer), "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, raw, info.Mode())
+}
+
+func machineDir(machine Machine) string {
+	return filepath.Join(machine.ProfileHome, machinesDir, machine.Name)
+}
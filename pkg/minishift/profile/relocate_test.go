@@ -0,0 +1,138 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RelocateHome_Moves_Home_And_Rewrites_Paths(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	renamer := &fakeRenamer{}
+	machineRenamers["fake"] = func() machineRenamer { return renamer }
+	defer delete(machineRenamers, "fake")
+
+	barHome := filepath.Join(oldHome, "profiles", "bar")
+	assert.NoError(t, os.MkdirAll(filepath.Join(barHome, "machines", "bar"), 0777))
+	hostConfig := map[string]interface{}{
+		"Name": "bar",
+		"Driver": map[string]interface{}{
+			"MachineName": "bar",
+			"StorePath":   barHome,
+		},
+	}
+	raw, _ := json.Marshal(hostConfig)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(barHome, "machines", "bar", "config.json"), raw, 0600))
+	raw, _ = json.Marshal(map[string]interface{}{"OcPath": filepath.Join(oldHome, "cache", "oc", "oc")})
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(barHome, "machines", "bar-state.json"), raw, 0644))
+
+	newHome := filepath.Join(testDir, "relocated", "home")
+	machines := []Machine{
+		{ProfileHome: oldHome, Name: "foo", DriverName: "generic"},
+		{ProfileHome: barHome, Name: "bar", DriverName: "fake"},
+	}
+	err := RelocateHome(oldHome, newHome, machines)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"before:bar:bar", "after:bar:bar:bar"}, renamer.calls)
+
+	assert.False(t, exists(oldHome))
+	assertFileContent(t, filepath.Join(newHome, "machines", "foo", "disk.img"), "disk")
+
+	var fooConfig map[string]interface{}
+	raw, _ = ioutil.ReadFile(filepath.Join(newHome, "machines", "foo", "config.json"))
+	assert.NoError(t, json.Unmarshal(raw, &fooConfig))
+	assert.Equal(t, newHome, fooConfig["StorePath"])
+
+	newBarHome := filepath.Join(newHome, "profiles", "bar")
+	var barConfig map[string]interface{}
+	raw, _ = ioutil.ReadFile(filepath.Join(newBarHome, "machines", "bar", "config.json"))
+	assert.NoError(t, json.Unmarshal(raw, &barConfig))
+	assert.Equal(t, newBarHome, barConfig["Driver"].(map[string]interface{})["StorePath"])
+
+	var barState map[string]interface{}
+	raw, _ = ioutil.ReadFile(filepath.Join(newBarHome, "machines", "bar-state.json"))
+	assert.NoError(t, json.Unmarshal(raw, &barState))
+	assert.Equal(t, filepath.Join(newHome, "cache", "oc", "oc"), barState["OcPath"])
+}
+
+func Test_RelocateHome_Uses_Empty_Target_Directory(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	newHome := filepath.Join(testDir, "empty")
+	assert.NoError(t, os.Mkdir(newHome, 0777))
+
+	assert.NoError(t, RelocateHome(oldHome, newHome, nil))
+	assertFileContent(t, filepath.Join(newHome, "config", "foo.json"), "{}")
+}
+
+func Test_RelocateHome_Rejects_Invalid_Targets(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	nonEmpty := filepath.Join(testDir, "non-empty")
+	assert.NoError(t, os.MkdirAll(filepath.Join(nonEmpty, "content"), 0777))
+
+	for _, newHome := range []string{oldHome, filepath.Join(oldHome, "nested"), nonEmpty} {
+		assert.Error(t, RelocateHome(oldHome, newHome, nil), "Relocating to '%s' should fail", newHome)
+	}
+	assert.True(t, exists(filepath.Join(oldHome, "config", "foo.json")))
+}
+
+func Test_RelocateHome_Fails_For_Unsupported_Driver(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	newHome := filepath.Join(testDir, "relocated")
+	err := RelocateHome(oldHome, newHome, []Machine{{ProfileHome: oldHome, Name: "foo", DriverName: "hyperv"}})
+	assert.Error(t, err)
+	assert.True(t, exists(oldHome))
+	assert.False(t, exists(newHome))
+}
+
+func Test_RelocateHome_Moves_Home_Back_If_Registering_A_VM_Fails(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	renamer := &fakeRenamer{afterMoveErr: errors.New("registervm failed")}
+	machineRenamers["fake"] = func() machineRenamer { return renamer }
+	defer delete(machineRenamers, "fake")
+	originalHostConfig := setupMachine(t, oldHome)
+
+	newHome := filepath.Join(testDir, "relocated")
+	err := RelocateHome(oldHome, newHome, []Machine{{ProfileHome: oldHome, Name: "foo", DriverName: "fake"}})
+	assert.EqualError(t, err, "registervm failed")
+	assert.Equal(t, []string{"before:foo:foo", "after:foo:foo:foo", "restore:foo:foo:foo"}, renamer.calls)
+
+	assert.False(t, exists(newHome))
+	assertFileContent(t, filepath.Join(oldHome, "machines", "foo", "foo.rawdisk"), "disk")
+
+	var restored, original map[string]interface{}
+	raw, _ := ioutil.ReadFile(filepath.Join(oldHome, "machines", "foo", "config.json"))
+	assert.NoError(t, json.Unmarshal(raw, &restored))
+	assert.NoError(t, json.Unmarshal([]byte(originalHostConfig), &original))
+	assert.Equal(t, original, restored, "The paths of the host configuration should point to the old home again")
+}
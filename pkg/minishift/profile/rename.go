@@ -0,0 +1,337 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/minishift/minishift/pkg/util/filehelper"
+)
+
+// kvmDomainBackupFile is the file in the machine directory keeping the libvirt domain XML while the VM gets renamed
+const kvmDomainBackupFile = ".domain.xml"
+
+// machineRenamer renames a stopped VM registered with the hypervisor. beforeMove is called while the machine directory
+// is still in its old location, afterMove once it has been moved and the libmachine host configuration is rewritten.
+// If renaming the profile fails after beforeMove succeeded, restore registers the VM again from the machine directory,
+// which is back in its old location by then.
+type machineRenamer interface {
+	beforeMove(old string, machineDir string, driverConfig map[string]interface{}) error
+	afterMove(old string, new string, machineDir string, replacer *strings.Replacer) error
+	restore(old string, new string, machineDir string) error
+}
+
+// machineRenamers creates the renamer for the driver with the given name. Drivers without registration outside of
+// the machine directory need no renamer.
+var machineRenamers = map[string]func() machineRenamer{
+	"virtualbox": func() machineRenamer { return &virtualBoxRenamer{} },
+	"kvm":        func() machineRenamer { return &kvmRenamer{} },
+	"hyperkit":   nil,
+	"xhyve":      nil,
+	"generic":    nil,
+}
+
+// SupportsRename returns true if the VM of the driver with the specified name can be renamed.
+func SupportsRename(driverName string) bool {
+	_, ok := machineRenamers[driverName]
+	return ok
+}
+
+// Rename renames profile old with home oldHome to profile new with home newHome. If driverName is not empty, the
+// stopped VM created by this driver is renamed as well. If any step fails, the steps done so far are rolled back and
+// the VM is registered again under its old name.
+func Rename(oldHome string, old string, newHome string, new string, driverName string) (err error) {
+	var renamer machineRenamer
+	oldMachineDir := filepath.Join(oldHome, machinesDir, old)
+	newMachineDir := filepath.Join(newHome, machinesDir, new)
+	if driverName != "" {
+		newRenamer, ok := machineRenamers[driverName]
+		if !ok {
+			return fmt.Errorf("The driver '%s' does not support renaming the VM", driverName)
+		}
+		if newRenamer != nil {
+			renamer = newRenamer()
+		}
+
+		driverConfig, err := readDriverConfig(filepath.Join(oldMachineDir, "config.json"))
+		if err != nil {
+			return err
+		}
+		if renamer != nil {
+			if err := renamer.beforeMove(old, oldMachineDir, driverConfig); err != nil {
+				return err
+			}
+		}
+	}
+
+	// undo holds the steps reverting what has been done so far, in the order they were done
+	var undo []func() error
+	defer func() {
+		if err == nil {
+			return
+		}
+		if rollbackErr := rollbackRename(undo, renamer, old, new, oldMachineDir); rollbackErr != nil {
+			err = fmt.Errorf("%v. Rolling back the rename failed as well: %v", err, rollbackErr)
+		}
+	}()
+	move := func(from string, to string) error {
+		if _, err := os.Stat(from); os.IsNotExist(err) {
+			return nil
+		}
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+		undo = append(undo, func() error { return os.Rename(to, from) })
+		return nil
+	}
+
+	if err := move(oldHome, newHome); err != nil {
+		return err
+	}
+
+	renames := map[string]string{
+		filepath.Join(configDir, old+".json"):         filepath.Join(configDir, new+".json"),
+		filepath.Join(machinesDir, old):               filepath.Join(machinesDir, new),
+		filepath.Join(machinesDir, old+".json"):       filepath.Join(machinesDir, new+".json"),
+		filepath.Join(machinesDir, old+"-state.json"): filepath.Join(machinesDir, new+"-state.json"),
+		filepath.Join(machinesDir, old+"-agent.json"): filepath.Join(machinesDir, new+"-agent.json"),
+		filepath.Join(machinesDir, old+"_kubeconfig"): filepath.Join(machinesDir, new+"_kubeconfig"),
+	}
+	for from, to := range renames {
+		if err := move(filepath.Join(newHome, from), filepath.Join(newHome, to)); err != nil {
+			return err
+		}
+	}
+
+	if driverName == "" {
+		return nil
+	}
+
+	// disk images and the like are named after the machine
+	files, err := ioutil.ReadDir(newMachineDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), old+".") && file.Mode().IsRegular() {
+			renamed := new + strings.TrimPrefix(file.Name(), old)
+			if err := move(filepath.Join(newMachineDir, file.Name()), filepath.Join(newMachineDir, renamed)); err != nil {
+				return err
+			}
+		}
+	}
+
+	replacer := strings.NewReplacer(
+		filepath.Join(oldMachineDir, old+"."), filepath.Join(newMachineDir, new+"."),
+		oldMachineDir, newMachineDir,
+		oldHome, newHome)
+	hostConfig := filepath.Join(newMachineDir, "config.json")
+	originalHostConfig, err := ioutil.ReadFile(hostConfig)
+	if err != nil {
+		return err
+	}
+	if err := rewriteHostConfig(hostConfig, hostConfig, new, replacer); err != nil {
+		return err
+	}
+	undo = append(undo, func() error { return ioutil.WriteFile(hostConfig, originalHostConfig, 0600) })
+
+	if renamer != nil {
+		return renamer.afterMove(old, new, newMachineDir, replacer)
+	}
+	return nil
+}
+
+// rollbackRename reverts the steps of a failed rename in reverse order. Only once all files are back in place, the VM
+// is registered again, since its registration refers to the files in the old location.
+func rollbackRename(undo []func() error, renamer machineRenamer, old string, new string, oldMachineDir string) error {
+	for i := len(undo) - 1; i >= 0; i-- {
+		if err := undo[i](); err != nil {
+			return err
+		}
+	}
+
+	if renamer != nil {
+		return renamer.restore(old, new, oldMachineDir)
+	}
+	return nil
+}
+
+func readDriverConfig(hostConfig string) (map[string]interface{}, error) {
+	raw, err := ioutil.ReadFile(hostConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		Driver map[string]interface{}
+	}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, err
+	}
+	return config.Driver, nil
+}
+
+// virtualBoxRenamer re-registers the VM from its new location, since VirtualBox keeps track of the absolute path
+// of the VM settings and of the attached media.
+type virtualBoxRenamer struct {
+	// registered is the name the VM got registered under by afterMove, if any
+	registered string
+}
+
+func (r *virtualBoxRenamer) beforeMove(old string, machineDir string, driverConfig map[string]interface{}) error {
+	return vboxManage("unregistervm", old)
+}
+
+func (r *virtualBoxRenamer) afterMove(old string, new string, machineDir string, replacer *strings.Replacer) error {
+	return r.register(old, new, machineDir)
+}
+
+func (r *virtualBoxRenamer) restore(old string, new string, machineDir string) error {
+	if r.registered != "" {
+		if err := vboxManage("unregistervm", r.registered); err != nil {
+			return err
+		}
+	}
+
+	// renaming the VM renames its settings file as well
+	settingsName := old
+	if !filehelper.Exists(vboxSettingsFile(machineDir, old)) && filehelper.Exists(vboxSettingsFile(machineDir, new)) {
+		settingsName = new
+	}
+	return r.register(settingsName, old, machineDir)
+}
+
+// register registers the VM from the settings file of the given name and renames the VM to name. The media are
+// attached from the machine directory.
+func (r *virtualBoxRenamer) register(settingsName string, name string, machineDir string) error {
+	// the VM folder is created below the machine directory by the driver
+	if err := vboxManage("registervm", vboxSettingsFile(machineDir, settingsName)); err != nil {
+		return err
+	}
+	r.registered = settingsName
+
+	if settingsName != name {
+		if err := vboxManage("modifyvm", settingsName, "--name", name); err != nil {
+			return err
+		}
+		r.registered = name
+	}
+	if err := vboxManage("storageattach", name, "--storagectl", "SATA", "--port", "0", "--device", "0", "--type", "dvddrive", "--medium", filepath.Join(machineDir, "boot2docker.iso")); err != nil {
+		return err
+	}
+	return vboxManage("storageattach", name, "--storagectl", "SATA", "--port", "1", "--device", "0", "--type", "hdd", "--medium", filepath.Join(machineDir, "disk.vmdk"))
+}
+
+func vboxSettingsFile(machineDir string, name string) string {
+	return filepath.Join(machineDir, name, name+".vbox")
+}
+
+// kvmRenamer redefines the libvirt domain under the new name, pointing it to the moved disk image and ISO. The domain
+// XML is written to the machine directory before the domain is undefined, so that the domain can be defined again if
+// renaming fails, even if Minishift itself gets interrupted.
+type kvmRenamer struct {
+	connectionURI string
+	domain        string
+	// defined is true once the domain got defined under the new name
+	defined bool
+}
+
+func (r *kvmRenamer) beforeMove(old string, machineDir string, driverConfig map[string]interface{}) error {
+	r.connectionURI = "qemu:///system"
+	if uri, ok := driverConfig["ConnectionURI"].(string); ok && uri != "" {
+		r.connectionURI = uri
+	}
+
+	domain, err := r.virsh("dumpxml", old)
+	if err != nil {
+		return err
+	}
+	r.domain = domain
+
+	backup := filepath.Join(machineDir, kvmDomainBackupFile)
+	if err := ioutil.WriteFile(backup, []byte(domain), 0600); err != nil {
+		return fmt.Errorf("Error saving the definition of domain '%s': %v", old, err)
+	}
+
+	if _, err = r.virsh("undefine", old); err != nil {
+		os.Remove(backup)
+		return err
+	}
+	return nil
+}
+
+func (r *kvmRenamer) afterMove(old string, new string, machineDir string, replacer *strings.Replacer) error {
+	domain := replacer.Replace(r.domain)
+	domain = strings.Replace(domain, "<name>"+old+"</name>", "<name>"+new+"</name>", 1)
+
+	domainFile, err := ioutil.TempFile("", "minishift-domain-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(domainFile.Name())
+
+	_, err = domainFile.WriteString(domain)
+	domainFile.Close()
+	if err != nil {
+		return err
+	}
+
+	if _, err = r.virsh("define", domainFile.Name()); err != nil {
+		return err
+	}
+	r.defined = true
+
+	os.Remove(filepath.Join(machineDir, kvmDomainBackupFile))
+	return nil
+}
+
+func (r *kvmRenamer) restore(old string, new string, machineDir string) error {
+	if r.defined {
+		if _, err := r.virsh("undefine", new); err != nil {
+			return err
+		}
+		r.defined = false
+	}
+
+	// the backup is removed once the domain got defined under the new name
+	backup := filepath.Join(machineDir, kvmDomainBackupFile)
+	if !filehelper.Exists(backup) && r.domain != "" {
+		if err := ioutil.WriteFile(backup, []byte(r.domain), 0600); err != nil {
+			return err
+		}
+	}
+	if _, err := r.virsh("define", backup); err != nil {
+		return fmt.Errorf("%v. The definition of domain '%s' is kept in '%s'", err, old, backup)
+	}
+	os.Remove(backup)
+	return nil
+}
+
+func (r *kvmRenamer) virsh(args ...string) (string, error) {
+	args = append([]string{"-c", r.connectionURI}, args...)
+	out, err := exec.Command("virsh", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("Error running 'virsh %s': %v", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRenamer struct {
+	calls        []string
+	afterMoveErr error
+}
+
+func (r *fakeRenamer) beforeMove(old string, machineDir string, driverConfig map[string]interface{}) error {
+	r.calls = append(r.calls, "before:"+old+":"+driverConfig["MachineName"].(string))
+	return nil
+}
+
+func (r *fakeRenamer) afterMove(old string, new string, machineDir string, replacer *strings.Replacer) error {
+	r.calls = append(r.calls, "after:"+old+":"+new+":"+filepath.Base(machineDir))
+	return r.afterMoveErr
+}
+
+func (r *fakeRenamer) restore(old string, new string, machineDir string) error {
+	r.calls = append(r.calls, "restore:"+old+":"+new+":"+filepath.Base(machineDir))
+	if !exists(filepath.Join(machineDir, "config.json")) {
+		return errors.New("machine directory not restored")
+	}
+	return nil
+}
+
+func Test_Rename_Moves_Profile_And_Machine(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	renamer := &fakeRenamer{}
+	machineRenamers["fake"] = func() machineRenamer { return renamer }
+	defer delete(machineRenamers, "fake")
+	setupMachine(t, oldHome)
+
+	newHome := filepath.Join(testDir, "bar")
+	err := Rename(oldHome, "foo", newHome, "bar", "fake")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"before:foo:foo", "after:foo:bar:bar"}, renamer.calls)
+
+	assert.False(t, exists(oldHome))
+	assertFileContent(t, filepath.Join(newHome, "config", "bar.json"), "{}")
+	assertFileContent(t, filepath.Join(newHome, "machines", "bar-state.json"), "{}")
+	assertFileContent(t, filepath.Join(newHome, "machines", "bar", "bar.rawdisk"), "disk")
+
+	raw, err := ioutil.ReadFile(filepath.Join(newHome, "machines", "bar", "config.json"))
+	assert.NoError(t, err)
+	var renamedConfig map[string]interface{}
+	assert.NoError(t, json.Unmarshal(raw, &renamedConfig))
+	driver := renamedConfig["Driver"].(map[string]interface{})
+	assert.Equal(t, "bar", renamedConfig["Name"])
+	assert.Equal(t, "bar", driver["MachineName"])
+	assert.Equal(t, newHome, driver["StorePath"])
+	assert.Equal(t, filepath.Join(newHome, "machines", "bar", "bar.rawdisk"), driver["DiskPath"])
+}
+
+func Test_Rename_Without_VM(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	newHome := filepath.Join(testDir, "bar")
+	err := Rename(oldHome, "foo", newHome, "bar", "")
+	assert.NoError(t, err)
+	assertFileContent(t, filepath.Join(newHome, "config", "bar.json"), "{}")
+	assertFileContent(t, filepath.Join(newHome, "machines", "bar", "disk.img"), "disk")
+}
+
+func Test_Rename_Fails_For_Unsupported_Driver(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	assert.False(t, SupportsRename("hyperv"))
+	assert.True(t, SupportsRename("hyperkit"))
+
+	err := Rename(oldHome, "foo", filepath.Join(testDir, "bar"), "bar", "hyperv")
+	assert.Error(t, err)
+	assert.True(t, exists(oldHome), "The profile should be left untouched")
+}
+
+func Test_Rename_Rolls_Back_If_Registering_The_VM_Fails(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	renamer := &fakeRenamer{afterMoveErr: errors.New("define failed")}
+	machineRenamers["fake"] = func() machineRenamer { return renamer }
+	defer delete(machineRenamers, "fake")
+	originalHostConfig := setupMachine(t, oldHome)
+
+	newHome := filepath.Join(testDir, "bar")
+	err := Rename(oldHome, "foo", newHome, "bar", "fake")
+	assert.EqualError(t, err, "define failed")
+	assert.Equal(t, []string{"before:foo:foo", "after:foo:bar:bar", "restore:foo:bar:foo"}, renamer.calls)
+
+	assert.False(t, exists(newHome))
+	assertFileContent(t, filepath.Join(oldHome, "config", "foo.json"), "{}")
+	assertFileContent(t, filepath.Join(oldHome, "machines", "foo-state.json"), "{}")
+	assertFileContent(t, filepath.Join(oldHome, "machines", "foo", "foo.rawdisk"), "disk")
+	assertFileContent(t, filepath.Join(oldHome, "machines", "foo", "config.json"), originalHostConfig)
+}
+
+func Test_Rename_Rolls_Back_If_Moving_The_Profile_Fails(t *testing.T) {
+	testDir, oldHome := setupProfileHome(t)
+	defer os.RemoveAll(testDir)
+
+	renamer := &fakeRenamer{}
+	machineRenamers["fake"] = func() machineRenamer { return renamer }
+	defer delete(machineRenamers, "fake")
+	setupMachine(t, oldHome)
+
+	// the parent directory of the new home does not exist
+	newHome := filepath.Join(testDir, "missing", "bar")
+	err := Rename(oldHome, "foo", newHome, "bar", "fake")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"before:foo:foo", "restore:foo:bar:foo"}, renamer.calls, "The VM should be registered again")
+	assert.True(t, exists(filepath.Join(oldHome, "machines", "foo", "foo.rawdisk")))
+}
+
+// setupMachine creates the machine directory of profile foo and returns the content of its host configuration.
+func setupMachine(t *testing.T, home string) string {
+	machineDir := filepath.Join(home, "machines", "foo")
+	hostConfig := map[string]interface{}{
+		"Name": "foo",
+		"Driver": map[string]interface{}{
+			"MachineName": "foo",
+			"StorePath":   home,
+			"DiskPath":    filepath.Join(machineDir, "foo.rawdisk"),
+		},
+	}
+	raw, _ := json.Marshal(hostConfig)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(machineDir, "config.json"), raw, 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(machineDir, "foo.rawdisk"), []byte("disk"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(home, "machines", "foo-state.json"), []byte("{}"), 0644))
+	return string(raw)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+// memoryKeys are the names under which the drivers store the memory of the VM in MB
+var memoryKeys = []string{"Memory", "MemSize"}
+
+// MachineResources returns the memory in MB and the number of CPUs allocated to the VM, as recorded in the
+// libmachine host configuration. Zero is returned for resources the driver does not record, as for the generic driver.
+func MachineResources(hostConfig string) (int, int, error) {
+	driverConfig, err := readDriverConfig(hostConfig)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	memory := 0
+	for _, key := range memoryKeys {
+		if value, ok := driverConfig[key].(float64); ok {
+			memory = int(value)
+			break
+		}
+	}
+	cpus := 0
+	if value, ok := driverConfig["CPU"].(float64); ok {
+		cpus = int(value)
+	}
+	return memory, cpus, nil
+}
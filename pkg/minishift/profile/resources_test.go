@@ -0,0 +1,54 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MachineResources(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-resources-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	var testCases = []struct {
+		driverConfig   string
+		expectedMemory int
+		expectedCPUs   int
+	}{
+		{`{"Memory": 4096, "CPU": 2}`, 4096, 2},
+		{`{"MemSize": 2048, "CPU": 4}`, 2048, 4},
+		{`{"IPAddress": "192.168.1.10"}`, 0, 0},
+	}
+
+	hostConfig := filepath.Join(testDir, "config.json")
+	for _, testCase := range testCases {
+		assert.NoError(t, ioutil.WriteFile(hostConfig, []byte(`{"Driver": `+testCase.driverConfig+`}`), 0600))
+		memory, cpus, err := MachineResources(hostConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expectedMemory, memory, testCase.driverConfig)
+		assert.Equal(t, testCase.expectedCPUs, cpus, testCase.driverConfig)
+	}
+
+	_, _, err = MachineResources(filepath.Join(testDir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -83,18 +83,33 @@ func (p *BuildrootProvisioner) Provision(swarmOptions swarm.Options, authOptions
 	p.AuthOptions = authOptions
 	p.EngineOptions = engineOptions
 
-	log.Info("\n   Setting hostname ... ")
-	if err := p.SetHostname(p.Driver.GetMachineName()); err != nil {
-		log.Info("FAIL")
-		return err
-	} else {
-		log.Info("OK")
-	}
+	return runSteps(p, p.Steps())
+}
 
-	if err := makeDockerOptionsDir(p); err != nil {
-		return err
+// Steps returns the built-in provisioning steps in the order they run
+func (p *BuildrootProvisioner) Steps() []Step {
+	return []Step{
+		{Name: StepSetHostname, Description: "Setting hostname", Run: func(provision.Provisioner) error {
+			return p.SetHostname(p.Driver.GetMachineName())
+		}},
+		{Name: StepDockerOptionsDir, Run: func(provision.Provisioner) error {
+			return makeDockerOptionsDir(p)
+		}},
+		{Name: StepDockerService, Run: func(provision.Provisioner) error {
+			return p.configureDockerService()
+		}},
+		{Name: StepConfigureAuth, Run: func(provision.Provisioner) error {
+			p.AuthOptions = setRemoteAuthOptions(p)
+			return provision.ConfigureAuth(p)
+		}},
+		{Name: StepFeatureDetection, Run: func(provision.Provisioner) error {
+			doFeatureDetection(p)
+			return nil
+		}},
 	}
+}
 
+func (p *BuildrootProvisioner) configureDockerService() error {
 	dockerCfg, err := p.GenerateDockerOptions(engine.DefaultPort)
 	if err != nil {
 		return err
@@ -112,16 +127,6 @@ func (p *BuildrootProvisioner) Provision(swarmOptions swarm.Options, authOptions
 	}
 	// This is required because minikube ISO doesn't symlink the resolve.conf and OpenShift v3.9 have a check for this.
 	// This is something we should do as part of ISO but atm not able to find a pointer [PK]
-	if _, err = p.SSHCommand("sudo ln -sfn /run/systemd/resolve/resolv.conf /etc/resolv.conf"); err != nil {
-		return err
-	}
-	p.AuthOptions = setRemoteAuthOptions(p)
-
-	if err := provision.ConfigureAuth(p); err != nil {
-		return err
-	}
-
-	doFeatureDetection(p)
-
-	return nil
+	_, err = p.SSHCommand("sudo ln -sfn /run/systemd/resolve/resolv.conf /etc/resolv.conf")
+	return err
 }
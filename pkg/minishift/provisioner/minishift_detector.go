@@ -22,6 +22,8 @@ import (
 	"github.com/docker/machine/libmachine/drivers"
 	"github.com/docker/machine/libmachine/log"
 	"github.com/docker/machine/libmachine/provision"
+	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/iso"
 )
 
 type MinishiftProvisionerDetector struct {
@@ -57,6 +59,10 @@ func (detector *MinishiftProvisionerDetector) DetectProvisioner(driver drivers.D
 		provisioner := NewBuildrootProvisioner("buildroot", driver)
 		provisioner.SetOsReleaseInfo(osReleaseInfo)
 		return provisioner, nil
+	} else if detector.isSystemdFlavor() {
+		provisioner := NewMinishiftProvisioner("minishift", driver)
+		provisioner.SetOsReleaseInfo(osReleaseInfo)
+		return provisioner, nil
 	} else {
 		return detector.Delegate.DetectProvisioner(driver)
 	}
@@ -82,3 +88,12 @@ func (detector *MinishiftProvisionerDetector) isBuildrootIso(osReleaseInfo *prov
 	}
 	return false
 }
+
+// isSystemdFlavor reports whether the ISO flavor of the instance declares systemd as its init system, in which case an
+// otherwise unknown distribution is provisioned like the Minishift ISOs.
+func (detector *MinishiftProvisionerDetector) isSystemdFlavor() bool {
+	if minishiftConfig.InstanceStateConfig == nil {
+		return false
+	}
+	return minishiftConfig.InstanceStateConfig.InitSystem == iso.InitSystemSystemd
+}
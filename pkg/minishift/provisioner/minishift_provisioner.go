@@ -31,6 +31,8 @@ import (
 	"github.com/docker/machine/libmachine/provision/pkgaction"
 	"github.com/docker/machine/libmachine/swarm"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/minishift/minishift/pkg/minishift/iso"
 )
 
 func NewMinishiftProvisioner(osReleaseID string, d drivers.Driver) *MinishiftProvisioner {
@@ -83,9 +85,34 @@ func (provisioner *MinishiftProvisioner) GetRedhatRelease() bool {
 	return false
 }
 
+// Package runs the package manager of the ISO flavor. The root file system of the live ISO images is not persistent,
+// so packages installed this way are lost when the VM restarts. Anything which needs to survive a restart has to be
+// installed again on every start, the way docker.Engine installs the selected Docker version from its cache.
 func (provisioner *MinishiftProvisioner) Package(name string, action pkgaction.PackageAction) error {
-	// Since required packages is already installed, it does not support package function.
-	return errors.New("Custom provisioner does not support package")
+	packageManager := minishiftConfig.InstanceStateConfig.PackageManager
+	if packageManager == "" || packageManager == iso.PackageManagerNone {
+		// Since required packages is already installed, it does not support package function.
+		return errors.New("Custom provisioner does not support package")
+	}
+
+	var command string
+	switch action {
+	case pkgaction.Install:
+		command = "install"
+	case pkgaction.Remove, pkgaction.Purge:
+		// yum and dnf have no notion of purging, removing a package also removes its unmodified configuration
+		command = "remove"
+	case pkgaction.Upgrade:
+		command = "upgrade"
+	default:
+		return fmt.Errorf("Package action '%s' is not supported by %s", action, packageManager)
+	}
+
+	if out, err := provisioner.SSHCommand(fmt.Sprintf("sudo %s %s -y %s", packageManager, command, name)); err != nil {
+		log.Debugf("'%s %s %s' output:\n%s", packageManager, command, name, out)
+		return fmt.Errorf("Error running '%s %s %s': %v", packageManager, command, name, err)
+	}
+	return nil
 }
 
 func (provisioner *MinishiftProvisioner) dockerDaemonResponding() bool {
@@ -114,44 +141,53 @@ func (provisioner *MinishiftProvisioner) Provision(swarmOptions swarm.Options, a
 	provisioner.EngineOptions = engineOptions
 	swarmOptions.Env = engineOptions.Env
 
-	// set default storage driver for minishift
-	storageDriver, err := decideStorageDriver(provisioner, "overlay2", engineOptions.StorageDriver)
-	if err != nil {
-		return err
-	}
-	provisioner.EngineOptions.StorageDriver = storageDriver
-
-	log.Info("\n   Setting hostname ... ")
-	if err := provisioner.SetHostname(provisioner.Driver.GetMachineName()); err != nil {
-		log.Info("FAIL")
-		return err
-	} else {
-		log.Info("OK")
-	}
-
-	if err := mcnutils.WaitFor(provisioner.dockerDaemonResponding); err != nil {
-		return err
-	}
+	return runSteps(provisioner, provisioner.Steps())
+}
 
-	if err := makeDockerOptionsDir(provisioner); err != nil {
-		return err
+// Steps returns the built-in provisioning steps in the order they run
+func (provisioner *MinishiftProvisioner) Steps() []Step {
+	// the Docker engine version selected on start, installed if it differs from the one of the ISO
+	dockerEngine := docker.Engine{
+		Version:        minishiftConfig.InstanceStateConfig.DockerVersion,
+		PackageManager: minishiftConfig.InstanceStateConfig.PackageManager,
+		ProxyEnv:       provisioner.EngineOptions.Env,
 	}
-
-	provisioner.AuthOptions = setRemoteAuthOptions(provisioner)
-
-	if err := provision.ConfigureAuth(provisioner); err != nil {
-		return err
+	var dockerEngineDescription string
+	if dockerEngine.Version != "" {
+		dockerEngineDescription = fmt.Sprintf("Installing Docker %s", dockerEngine.Version)
 	}
 
-	log.Info("\n   Feature detection ... ")
-	if err := doFeatureDetection(provisioner); err != nil {
-		log.Info("FAIL")
-		return err
-	} else {
-		log.Info("OK")
+	return []Step{
+		{Name: StepStorageDriver, Run: func(provision.Provisioner) error {
+			// set default storage driver for minishift
+			storageDriver, err := decideStorageDriver(provisioner, "overlay2", provisioner.EngineOptions.StorageDriver)
+			if err != nil {
+				return err
+			}
+			provisioner.EngineOptions.StorageDriver = storageDriver
+			return nil
+		}},
+		{Name: StepSetHostname, Description: "Setting hostname", Run: func(provision.Provisioner) error {
+			return provisioner.SetHostname(provisioner.Driver.GetMachineName())
+		}},
+		{Name: StepDockerVersion, Description: dockerEngineDescription, Run: func(provision.Provisioner) error {
+			_, err := dockerEngine.Install(provisioner)
+			return err
+		}},
+		{Name: StepWaitForDocker, Run: func(provision.Provisioner) error {
+			return mcnutils.WaitFor(provisioner.dockerDaemonResponding)
+		}},
+		{Name: StepDockerOptionsDir, Run: func(provision.Provisioner) error {
+			return makeDockerOptionsDir(provisioner)
+		}},
+		{Name: StepConfigureAuth, Run: func(provision.Provisioner) error {
+			provisioner.AuthOptions = setRemoteAuthOptions(provisioner)
+			return provision.ConfigureAuth(provisioner)
+		}},
+		{Name: StepFeatureDetection, Description: "Feature detection", Run: func(provision.Provisioner) error {
+			return doFeatureDetection(provisioner)
+		}},
 	}
-
-	return nil
 }
 
 func (provisioner *MinishiftProvisioner) GenerateDockerOptions(dockerPort int) (*provision.DockerOptions, error) {
@@ -171,6 +207,11 @@ func (provisioner *MinishiftProvisioner) GenerateDockerOptions(dockerPort int) (
 	} else if provisioner.OsReleaseInfo.ID == "fedora" {
 		engineConfigTemplate = engineConfigTemplateFedora
 	}
+	// Docker CE, installed when selecting the Docker version, ships neither the distribution's dockerd-current nor
+	// its runtime and proxy binaries
+	if version, err := docker.InstalledVersion(provisioner); err == nil && docker.IsCommunityEdition(version) {
+		engineConfigTemplate = engineConfigTemplateCE
+	}
 
 	t, err := template.New("engineConfig").Parse(engineConfigTemplate)
 	if err != nil {
@@ -126,6 +126,26 @@ func TestMinishiftProvisionerGenerateDockerOptionsForCentOS(t *testing.T) {
 	assert.Equal(t, engineCfg.String(), dockerOptions.EngineOptions)
 }
 
+func TestMinishiftProvisionerGenerateDockerOptionsForDockerCE(t *testing.T) {
+	p := NewMinishiftProvisioner("", &fakedriver.Driver{})
+	p.SSHCommander = &provisiontest.FakeSSHCommander{Responses: map[string]string{
+		"docker --version": "Docker version 18.09.9, build 039a7df9ba\n",
+	}}
+	info := &provision.OsRelease{
+		Name:      "CentOS Linux",
+		VersionID: "7.3",
+		ID:        "centos",
+	}
+	p.SetOsReleaseInfo(info)
+
+	dockerOptions, engineCfg := parseTemplate(t, p, engineConfigTemplateCE)
+
+	assert.Equal(t, engineCfg.String(), dockerOptions.EngineOptions)
+	assert.Contains(t, dockerOptions.EngineOptions, "ExecStart=/usr/bin/dockerd -H")
+	assert.NotContains(t, dockerOptions.EngineOptions, "-current")
+	assert.NotContains(t, dockerOptions.EngineOptions, "--signature-verification")
+}
+
 func setup(t *testing.T) string {
 	// Make sure we create the required directories.
 	testDir, err := ioutil.TempDir("", "minishift-provision")
@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/provision"
+)
+
+// windowsPluginExtensions are the file extensions of the plugins which can be run on Windows
+var windowsPluginExtensions = []string{".exe", ".bat", ".cmd", ".ps1"}
+
+// loadPlugins returns the provisioner plugins contained in dir as hooks. A plugin is an executable in a
+// '<position>-<step>' sub-directory of dir, for example 'after-configure-auth'. Plugins anchored to the same step run
+// in the lexical order of their file names. Files which are not executable are skipped with a warning.
+func loadPlugins(dir string) ([]hook, error) {
+	hookDirs, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var hooks []hook
+	for _, hookDir := range hookDirs {
+		if !hookDir.IsDir() {
+			continue
+		}
+		parts := strings.SplitN(hookDir.Name(), "-", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid provisioner plugin directory '%s', expected '<position>-<step>'", hookDir.Name())
+		}
+
+		files, err := ioutil.ReadDir(filepath.Join(dir, hookDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+				continue
+			}
+			if !isExecutable(file) {
+				log.Warnf("Skipping provisioner plugin '%s', the file is not executable", filepath.Join(hookDir.Name(), file.Name()))
+				continue
+			}
+			h, err := newHook(parts[0], parts[1], pluginStep(filepath.Join(dir, hookDir.Name(), file.Name())))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid provisioner plugin '%s': %v", filepath.Join(hookDir.Name(), file.Name()), err)
+			}
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks, nil
+}
+
+// pluginStep returns the step running the plugin at path on the host. The plugin connects to the VM using the SSH
+// details passed in its environment.
+func pluginStep(path string) Step {
+	name := filepath.Base(path)
+	return Step{
+		Name:        name,
+		Description: fmt.Sprintf("Running provisioner plugin '%s'", name),
+		Run: func(p provision.Provisioner) error {
+			env, err := pluginEnv(p)
+			if err != nil {
+				return err
+			}
+
+			cmd := pluginCommand(path)
+			cmd.Env = append(os.Environ(), env...)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("Provisioner plugin '%s' failed: %v\n%s", path, err, out)
+			}
+			return nil
+		},
+	}
+}
+
+// isExecutable returns true if the file can be run as plugin. On Windows, this is decided by the file extension.
+func isExecutable(file os.FileInfo) bool {
+	if runtime.GOOS != "windows" {
+		return file.Mode()&0111 != 0
+	}
+
+	extension := strings.ToLower(filepath.Ext(file.Name()))
+	for _, e := range windowsPluginExtensions {
+		if extension == e {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginCommand returns the command running the plugin at path. PowerShell scripts are run through PowerShell, since
+// Windows cannot execute them directly.
+func pluginCommand(path string) *exec.Cmd {
+	if runtime.GOOS == "windows" && strings.ToLower(filepath.Ext(path)) == ".ps1" {
+		return exec.Command("powershell.exe", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", path)
+	}
+	return exec.Command(path)
+}
+
+// pluginEnv returns the environment variables passed to a provisioner plugin
+func pluginEnv(p provision.Provisioner) ([]string, error) {
+	driver := p.GetDriver()
+	hostname, err := driver.GetSSHHostname()
+	if err != nil {
+		return nil, err
+	}
+	port, err := driver.GetSSHPort()
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{
+		"MINISHIFT_MACHINE_NAME=" + driver.GetMachineName(),
+		"MINISHIFT_SSH_HOST=" + hostname,
+		"MINISHIFT_SSH_PORT=" + strconv.Itoa(port),
+		"MINISHIFT_SSH_USER=" + driver.GetSSHUsername(),
+		"MINISHIFT_SSH_KEY=" + driver.GetSSHKeyPath(),
+	}, nil
+}
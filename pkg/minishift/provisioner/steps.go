@@ -0,0 +1,160 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/provision"
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+)
+
+// Names of the built-in provisioning steps, in the order they run. Not every provisioner runs every step.
+const (
+	StepStorageDriver    = "storage-driver"
+	StepSetHostname      = "set-hostname"
+	StepDockerVersion    = "docker-version"
+	StepWaitForDocker    = "wait-for-docker"
+	StepDockerOptionsDir = "docker-options-dir"
+	StepDockerService    = "docker-service"
+	StepConfigureAuth    = "configure-auth"
+	StepFeatureDetection = "feature-detection"
+)
+
+// Positions of an additional step relative to the built-in step it is anchored to
+const (
+	Before = "before"
+	After  = "after"
+)
+
+var (
+	// StepNames lists the built-in provisioning steps additional steps can be anchored to
+	StepNames = []string{StepStorageDriver, StepSetHostname, StepDockerVersion, StepWaitForDocker, StepDockerOptionsDir, StepDockerService,
+		StepConfigureAuth, StepFeatureDetection}
+
+	registeredHooks []hook
+
+	// pluginsDir returns the directory the provisioner plugins are loaded from
+	pluginsDir = minishiftConstants.GetProvisionerPluginsDir
+)
+
+// Step is a named step of the provisioning of the VM
+type Step struct {
+	Name        string
+	Description string // printed while the step runs, the step runs silently if empty
+	Run         func(p provision.Provisioner) error
+}
+
+// hook is an additional step anchored before or after a built-in step
+type hook struct {
+	position string
+	anchor   string
+	step     Step
+}
+
+// RegisterStep registers a step which runs before or after the specified built-in step. Steps registered for the
+// same position run in the order of their registration.
+func RegisterStep(position string, anchor string, step Step) error {
+	h, err := newHook(position, anchor, step)
+	if err != nil {
+		return err
+	}
+	registeredHooks = append(registeredHooks, h)
+	return nil
+}
+
+func newHook(position string, anchor string, step Step) (hook, error) {
+	if position != Before && position != After {
+		return hook{}, fmt.Errorf("Position '%s' is not supported. Possible values: %v", position, []string{Before, After})
+	}
+	if !isStepName(anchor) {
+		return hook{}, fmt.Errorf("Provisioning step '%s' does not exist. Possible values: %v", anchor, StepNames)
+	}
+	if step.Name == "" || step.Run == nil {
+		return hook{}, fmt.Errorf("A provisioning step needs a name and a function to run")
+	}
+	return hook{position: position, anchor: anchor, step: step}, nil
+}
+
+func isStepName(name string) bool {
+	for _, stepName := range StepNames {
+		if stepName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// withHooks returns the built-in steps with the hooks inserted at their positions. Hooks anchored to a step the
+// provisioner does not run are skipped with a warning.
+func withHooks(steps []Step, hooks []hook) []Step {
+	var result []Step
+	anchored := make(map[string]bool)
+	for _, step := range steps {
+		anchored[step.Name] = true
+		result = append(result, hookedSteps(hooks, Before, step.Name)...)
+		result = append(result, step)
+		result = append(result, hookedSteps(hooks, After, step.Name)...)
+	}
+
+	for _, h := range hooks {
+		if !anchored[h.anchor] {
+			log.Warnf("Skipping provisioning step '%s', the provisioner does not run the step '%s'", h.step.Name, h.anchor)
+		}
+	}
+	return result
+}
+
+func hookedSteps(hooks []hook, position string, anchor string) []Step {
+	var steps []Step
+	for _, h := range hooks {
+		if h.position == position && h.anchor == anchor {
+			steps = append(steps, h.step)
+		}
+	}
+	return steps
+}
+
+// runSteps runs the built-in steps together with the registered steps and the provisioner plugins
+func runSteps(p provision.Provisioner, steps []Step) error {
+	plugins, err := loadPlugins(pluginsDir())
+	if err != nil {
+		return err
+	}
+
+	// the registered hooks are copied, so that appending the plugins never writes to the backing array of the global slice
+	hooks := append(append([]hook{}, registeredHooks...), plugins...)
+	for _, step := range withHooks(steps, hooks) {
+		log.Debugf("Running provisioning step '%s'", step.Name)
+		if step.Description != "" {
+			log.Info(fmt.Sprintf("\n   %s ... ", step.Description))
+		}
+
+		if err := step.Run(p); err != nil {
+			if step.Description != "" {
+				log.Info("FAIL")
+			}
+			return err
+		}
+
+		if step.Description != "" {
+			log.Info("OK")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/machine/drivers/fakedriver"
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/docker/machine/libmachine/provision/provisiontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func recordingStep(name string, ran *[]string) Step {
+	return Step{Name: name, Run: func(provision.Provisioner) error {
+		*ran = append(*ran, name)
+		return nil
+	}}
+}
+
+func TestWithHooksInsertsStepsAtTheirPositions(t *testing.T) {
+	var ran []string
+	steps := []Step{recordingStep(StepSetHostname, &ran), recordingStep(StepConfigureAuth, &ran)}
+	var hooks []hook
+	for _, h := range []struct{ position, anchor, name string }{
+		{After, StepConfigureAuth, "install-ca"},
+		{Before, StepSetHostname, "prepare"},
+		{After, StepConfigureAuth, "install-agent"},
+		{Before, StepWaitForDocker, "skipped"},
+	} {
+		newHook, err := newHook(h.position, h.anchor, recordingStep(h.name, &ran))
+		assert.NoError(t, err)
+		hooks = append(hooks, newHook)
+	}
+
+	for _, step := range withHooks(steps, hooks) {
+		assert.NoError(t, step.Run(nil))
+	}
+	assert.Equal(t, []string{"prepare", StepSetHostname, StepConfigureAuth, "install-ca", "install-agent"}, ran)
+}
+
+func TestRegisterStepValidatesPosition(t *testing.T) {
+	defer func() { registeredHooks = nil }()
+	var ran []string
+
+	err := RegisterStep("during", StepConfigureAuth, recordingStep("install-ca", &ran))
+	assert.EqualError(t, err, "Position 'during' is not supported. Possible values: [before after]")
+
+	err = RegisterStep(After, "install-packages", recordingStep("install-ca", &ran))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Provisioning step 'install-packages' does not exist")
+
+	assert.NoError(t, RegisterStep(After, StepConfigureAuth, recordingStep("install-ca", &ran)))
+	assert.Len(t, registeredHooks, 1)
+}
+
+func TestLoadPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test plugins are shell scripts")
+	}
+	testDir, err := ioutil.TempDir("", "minishift-provisioners-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	hooks, err := loadPlugins(filepath.Join(testDir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, hooks)
+
+	for _, plugin := range []string{"after-configure-auth/20-agent", "after-configure-auth/10-ca", "before-set-hostname/prepare"} {
+		path := filepath.Join(testDir, plugin)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"), 0755))
+	}
+
+	hooks, err = loadPlugins(testDir)
+	assert.NoError(t, err)
+	var loaded []string
+	for _, h := range hooks {
+		loaded = append(loaded, h.position+" "+h.anchor+" "+h.step.Name)
+	}
+	assert.Equal(t, []string{"after configure-auth 10-ca", "after configure-auth 20-agent", "before set-hostname prepare"}, loaded)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(testDir, "before-set-hostname", "README"), []byte("docs\n"), 0644))
+	hooks, err = loadPlugins(testDir)
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 3, "Files which are not executable should be skipped")
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(testDir, "after-install-packages"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(testDir, "after-install-packages", "agent"), []byte("#!/bin/sh\n"), 0755))
+	_, err = loadPlugins(testDir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid provisioner plugin")
+}
+
+func TestRunStepsRunsPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test plugin is a shell script")
+	}
+
+	testDir, err := ioutil.TempDir("", "minishift-provisioners-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+	defer func(dir func() string) { pluginsDir = dir }(pluginsDir)
+	pluginsDir = func() string { return filepath.Join(testDir, "provisioners") }
+
+	output := filepath.Join(testDir, "output")
+	plugin := filepath.Join(testDir, "provisioners", "after-set-hostname", "ca")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(plugin), 0755))
+	assert.NoError(t, ioutil.WriteFile(plugin, []byte("#!/bin/sh\necho \"$MINISHIFT_MACHINE_NAME\" > "+output+"\n"), 0755))
+
+	p := NewMinishiftProvisioner("", &fakedriver.Driver{MockName: "minishift"})
+	p.SSHCommander = provisiontest.NewFakeSSHCommander(provisiontest.FakeSSHCommanderOptions{})
+	var ran []string
+	steps := []Step{recordingStep(StepSetHostname, &ran)}
+
+	assert.NoError(t, runSteps(p, steps))
+	content, err := ioutil.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Equal(t, "minishift\n", string(content))
+}
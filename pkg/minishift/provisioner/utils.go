@@ -28,6 +28,9 @@ import (
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
 )
 
+// The engine configuration templates only hold the options decided during provisioning. The environment and the
+// flags from the profile configuration are passed via the MINISHIFT_DOCKER_OPTS variable of the drop-in which is
+// rendered on every start, see docker.DaemonConfig.
 var (
 	engineConfigTemplateRHEL = `[Service]
 ExecStart=
@@ -42,8 +45,7 @@ ExecStart=/usr/bin/dockerd-current -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///v
            --add-registry registry.access.redhat.com \
            --storage-driver {{.EngineOptions.StorageDriver}} --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} \
            --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} \
-           {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}
-Environment={{range .EngineOptions.Env}}{{ printf "%q" . }} {{end}}
+           {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}$MINISHIFT_DOCKER_OPTS
 `
 	engineConfigTemplateCentOS = `[Service]
 ExecStart=
@@ -57,8 +59,18 @@ ExecStart=/usr/bin/dockerd-current -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///v
            --userland-proxy-path=/usr/libexec/docker/docker-proxy-current \
            --storage-driver {{.EngineOptions.StorageDriver}} --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} \
            --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} \
-           {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}
-Environment={{range .EngineOptions.Env}}{{ printf "%q" . }} {{end}}
+           {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}$MINISHIFT_DOCKER_OPTS
+`
+
+	engineConfigTemplateCE = `[Service]
+ExecStart=
+ExecStart=/usr/bin/dockerd -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///var/run/docker.sock \
+           --selinux-enabled \
+           --log-driver=journald \
+           --exec-opt native.cgroupdriver=systemd \
+           --storage-driver {{.EngineOptions.StorageDriver}} --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} \
+           --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} \
+           {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}$MINISHIFT_DOCKER_OPTS
 `
 
 	engineConfigTemplateFedora = `[Service]
@@ -74,8 +86,7 @@ ExecStart=/usr/bin/dockerd-current -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///v
           --seccomp-profile=/etc/docker/seccomp.json \
 		  --storage-driver {{.EngineOptions.StorageDriver}} --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} \
 		  --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} \
-           {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}
-Environment={{range .EngineOptions.Env}}{{ printf "%q" . }} {{end}}
+           {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}$MINISHIFT_DOCKER_OPTS
 `
 
 	engineConfigTemplateBuildRoot = `[Unit]
@@ -89,8 +100,6 @@ Type=notify
 
 # DOCKER_RAMDISK disables pivot_root in Docker, using MS_MOVE instead.
 Environment=DOCKER_RAMDISK=yes
-{{range .EngineOptions.Env}}Environment={{.}}
-{{end}}
 
 # This file is a systemd drop-in unit that inherits from the base dockerd configuration.
 # The base configuration already specifies an 'ExecStart=...' command. The first directive
@@ -100,7 +109,7 @@ Environment=DOCKER_RAMDISK=yes
 # will catch this invalid input and refuse to start the service with an error like:
 #  Service has more than one ExecStart= setting, which is only allowed for Type=oneshot services.
 ExecStart=
-ExecStart=/usr/bin/dockerd -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///var/run/docker.sock --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}{{ range .EngineOptions.InsecureRegistry }}--insecure-registry {{.}} {{ end }}{{ range .EngineOptions.RegistryMirror }}--registry-mirror {{.}} {{ end }}{{ range .EngineOptions.ArbitraryFlags }}--{{.}} {{ end }}
+ExecStart=/usr/bin/dockerd -H tcp://0.0.0.0:{{.DockerPort}} -H unix:///var/run/docker.sock --tlsverify --tlscacert {{.AuthOptions.CaCertRemotePath}} --tlscert {{.AuthOptions.ServerCertRemotePath}} --tlskey {{.AuthOptions.ServerKeyRemotePath}} {{ range .EngineOptions.Labels }}--label {{.}} {{ end }}$MINISHIFT_DOCKER_OPTS
 ExecReload=/bin/kill -s HUP $MAINPID
 
 # Having non-zero Limit*s causes performance problems due to accounting overhead
@@ -0,0 +1,320 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/provision"
+	"github.com/minishift/minishift/pkg/minishift/oc"
+)
+
+const (
+	// DirInsideInstance is the directory inside the VM holding the directories of the persistent volumes
+	DirInsideInstance = "/var/lib/minishift/openshift.local.pv"
+
+	// DefaultCount, DefaultSize and DefaultReclaimPolicy describe the persistent volumes created by 'oc cluster up'
+	DefaultCount         = 100
+	DefaultSize          = "100Gi"
+	DefaultReclaimPolicy = "Recycle"
+
+	// MaxCount is the number of volumes the four digit volume names allow for
+	MaxCount = 9999
+
+	ReleasedPhase = "Released"
+)
+
+var (
+	// SupportedReclaimPolicies lists the reclaim policies supported for hostPath persistent volumes
+	SupportedReclaimPolicies = []string{"Recycle", "Retain"}
+
+	volumeName   = regexp.MustCompile(`^pv([0-9]{4})$`)
+	quantityExpr = regexp.MustCompile(`^[1-9][0-9]*(Ki|Mi|Gi|Ti|K|M|G|T)?$`)
+)
+
+// Pool describes the pre-created hostPath persistent volumes pv0001 up to pv<Count>.
+type Pool struct {
+	Count         int
+	Size          string
+	ReclaimPolicy string
+}
+
+// Volume describes a persistent volume of the pool as reported by the cluster.
+type Volume struct {
+	Name          string
+	Capacity      string
+	ReclaimPolicy string
+	Phase         string
+	Claim         string
+	Path          string
+}
+
+// ValidateCount returns an error if the specified number of volumes is not between 1 and MaxCount.
+func ValidateCount(count int) error {
+	if count <= 0 || count > MaxCount {
+		return fmt.Errorf("The number of persistent volumes must be between 1 and %d", MaxCount)
+	}
+	return nil
+}
+
+// ValidateSize returns an error if the specified size is not a valid Kubernetes quantity.
+func ValidateSize(size string) error {
+	if !quantityExpr.MatchString(size) {
+		return fmt.Errorf("'%s' is not a valid persistent volume size. Expected a quantity like '5Gi' or '500Mi'", size)
+	}
+	return nil
+}
+
+// ValidateReclaimPolicy returns an error if the specified reclaim policy is not supported for hostPath volumes.
+func ValidateReclaimPolicy(policy string) error {
+	for _, supported := range SupportedReclaimPolicies {
+		if policy == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("Reclaim policy '%s' is not supported. Possible values: %v", policy, SupportedReclaimPolicies)
+}
+
+// NewPool creates a pool using the defaults of 'oc cluster up' for each unspecified value.
+func NewPool(count int, size string, reclaimPolicy string) *Pool {
+	pool := &Pool{Count: count, Size: size, ReclaimPolicy: reclaimPolicy}
+	if pool.Count <= 0 {
+		pool.Count = DefaultCount
+	}
+	if pool.Size == "" {
+		pool.Size = DefaultSize
+	}
+	if pool.ReclaimPolicy == "" {
+		pool.ReclaimPolicy = DefaultReclaimPolicy
+	}
+	return pool
+}
+
+// IsDefault returns true if the pool matches the persistent volumes created by 'oc cluster up'.
+func (p *Pool) IsDefault() bool {
+	return p.Count == DefaultCount && p.Size == DefaultSize && p.ReclaimPolicy == DefaultReclaimPolicy
+}
+
+// VolumeName returns the name of the i-th persistent volume of the pool, starting at 1.
+func VolumeName(i int) string {
+	return fmt.Sprintf("pv%04d", i)
+}
+
+// Manifest returns the list of the persistent volumes of the pool as JSON.
+func (p *Pool) Manifest() (string, error) {
+	if err := ValidateCount(p.Count); err != nil {
+		return "", err
+	}
+	if err := ValidateSize(p.Size); err != nil {
+		return "", err
+	}
+	if err := ValidateReclaimPolicy(p.ReclaimPolicy); err != nil {
+		return "", err
+	}
+
+	var items []interface{}
+	for i := 1; i <= p.Count; i++ {
+		name := VolumeName(i)
+		items = append(items, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolume",
+			"metadata": map[string]interface{}{
+				"name":   name,
+				"labels": map[string]string{"volume": name},
+			},
+			"spec": map[string]interface{}{
+				"capacity":                      map[string]string{"storage": p.Size},
+				"accessModes":                   []string{"ReadWriteOnce", "ReadWriteMany", "ReadOnlyMany"},
+				"hostPath":                      map[string]string{"path": path.Join(DirInsideInstance, name)},
+				"persistentVolumeReclaimPolicy": p.ReclaimPolicy,
+			},
+		})
+	}
+
+	content, err := json.MarshalIndent(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Reconcile creates the directories and persistent volumes of the pool and updates existing ones. Volumes of a
+// former, larger pool are deleted unless they are bound to a claim.
+func (p *Pool) Reconcile(sshCommander provision.SSHCommander, ocRunner *oc.OcRunner) error {
+	manifest, err := p.Manifest()
+	if err != nil {
+		return err
+	}
+
+	dirs := fmt.Sprintf("%s/pv{%04d..%04d}", DirInsideInstance, 1, p.Count)
+	cmd := fmt.Sprintf("sudo mkdir -p %s && sudo chmod 777 %s && (sudo chcon -t svirt_sandbox_file_t %s || true)", dirs, dirs, dirs)
+	if _, err := sshCommander.SSHCommand(cmd); err != nil {
+		return fmt.Errorf("Error creating the persistent volume directories: %v", err)
+	}
+
+	manifestFile, err := ioutil.TempFile("", "minishift-pv-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(manifestFile.Name())
+	_, err = manifestFile.WriteString(manifest)
+	manifestFile.Close()
+	if err != nil {
+		return err
+	}
+
+	if _, err := ocRunner.RunAsAdmin("apply", "-f", manifestFile.Name()); err != nil {
+		return fmt.Errorf("Error creating the persistent volumes: %v", err)
+	}
+
+	return p.RemoveExtraVolumes(ocRunner)
+}
+
+// RemoveExtraVolumes deletes the volumes of a former, larger pool unless they are bound to a claim.
+func (p *Pool) RemoveExtraVolumes(ocRunner *oc.OcRunner) error {
+	volumes, err := List(ocRunner)
+	if err != nil {
+		return err
+	}
+	for _, volume := range volumes {
+		if index(volume.Name) > p.Count && volume.Claim == "" {
+			if _, err := ocRunner.RunAsAdmin("delete", "pv", volume.Name); err != nil {
+				return fmt.Errorf("Error deleting persistent volume '%s': %v", volume.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// List returns the persistent volumes of the pool ordered by name.
+func List(ocRunner *oc.OcRunner) ([]Volume, error) {
+	out, err := ocRunner.RunAsAdmin("get", "pv", "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("Error listing the persistent volumes: %v", err)
+	}
+	return parseVolumes(out)
+}
+
+// Recycle wipes the data of the specified released volumes and makes them available again. If no names are
+// specified, all released volumes of the pool are recycled. The names of the recycled volumes are returned.
+func Recycle(names []string, sshCommander provision.SSHCommander, ocRunner *oc.OcRunner) ([]string, error) {
+	volumes, err := List(ocRunner)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]Volume)
+	for _, volume := range volumes {
+		byName[volume.Name] = volume
+	}
+
+	if len(names) == 0 {
+		for _, volume := range volumes {
+			if volume.Phase == ReleasedPhase {
+				names = append(names, volume.Name)
+			}
+		}
+	}
+
+	var recycled []string
+	for _, name := range names {
+		volume, ok := byName[name]
+		if !ok {
+			return recycled, fmt.Errorf("There is no persistent volume '%s' in the pool", name)
+		}
+		if volume.Phase != ReleasedPhase {
+			return recycled, fmt.Errorf("Persistent volume '%s' is '%s'. Only released volumes can be recycled", name, volume.Phase)
+		}
+
+		if _, err := sshCommander.SSHCommand(fmt.Sprintf("sudo find %s -mindepth 1 -delete", volume.Path)); err != nil {
+			return recycled, fmt.Errorf("Error wiping the data of persistent volume '%s': %v", name, err)
+		}
+		if _, err := ocRunner.RunAsAdmin("patch", "pv", name, "--type=json", `-p=[{"op": "remove", "path": "/spec/claimRef"}]`); err != nil {
+			return recycled, fmt.Errorf("Error releasing the claim of persistent volume '%s': %v", name, err)
+		}
+		recycled = append(recycled, name)
+	}
+	return recycled, nil
+}
+
+type volumeList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			Capacity      map[string]string `json:"capacity"`
+			ReclaimPolicy string            `json:"persistentVolumeReclaimPolicy"`
+			HostPath      struct {
+				Path string `json:"path"`
+			} `json:"hostPath"`
+			ClaimRef *struct {
+				Namespace string `json:"namespace"`
+				Name      string `json:"name"`
+			} `json:"claimRef"`
+		} `json:"spec"`
+		Status struct {
+			Phase string `json:"phase"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// parseVolumes parses the output of 'oc get pv -o json', ignoring volumes which are not part of the pool.
+func parseVolumes(content string) ([]Volume, error) {
+	list := volumeList{}
+	if err := json.Unmarshal([]byte(content), &list); err != nil {
+		return nil, fmt.Errorf("Error parsing the persistent volumes: %v", err)
+	}
+
+	var volumes []Volume
+	for _, item := range list.Items {
+		if index(item.Metadata.Name) == 0 || !strings.HasPrefix(item.Spec.HostPath.Path, DirInsideInstance) {
+			continue
+		}
+		volume := Volume{
+			Name:          item.Metadata.Name,
+			Capacity:      item.Spec.Capacity["storage"],
+			ReclaimPolicy: item.Spec.ReclaimPolicy,
+			Phase:         item.Status.Phase,
+			Path:          item.Spec.HostPath.Path,
+		}
+		if item.Spec.ClaimRef != nil {
+			volume.Claim = fmt.Sprintf("%s/%s", item.Spec.ClaimRef.Namespace, item.Spec.ClaimRef.Name)
+		}
+		volumes = append(volumes, volume)
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+	return volumes, nil
+}
+
+// index returns the number of the pool volume with the specified name or 0 if it is not a pool volume.
+func index(name string) int {
+	match := volumeName.FindStringSubmatch(name)
+	if match == nil {
+		return 0
+	}
+	i, _ := strconv.Atoi(match[1])
+	return i
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testVolumes = `{"items": [
+  {"metadata": {"name": "pv0002"}, "spec": {"capacity": {"storage": "5Gi"}, "persistentVolumeReclaimPolicy": "Retain",
+    "hostPath": {"path": "/var/lib/minishift/openshift.local.pv/pv0002"}, "claimRef": {"namespace": "myproject", "name": "data"}},
+    "status": {"phase": "Released"}},
+  {"metadata": {"name": "pv0001"}, "spec": {"capacity": {"storage": "5Gi"}, "persistentVolumeReclaimPolicy": "Retain",
+    "hostPath": {"path": "/var/lib/minishift/openshift.local.pv/pv0001"}}, "status": {"phase": "Available"}},
+  {"metadata": {"name": "nfs-volume"}, "spec": {"capacity": {"storage": "1Gi"}}, "status": {"phase": "Available"}}
+]}`
+
+func Test_pool_defaults_match_cluster_up(t *testing.T) {
+	assert.True(t, NewPool(0, "", "").IsDefault())
+	assert.False(t, NewPool(20, "", "").IsDefault())
+	assert.False(t, NewPool(0, "5Gi", "").IsDefault())
+}
+
+func Test_manifest_contains_all_volumes(t *testing.T) {
+	pool := NewPool(3, "5Gi", "Retain")
+	manifest, err := pool.Manifest()
+	assert.NoError(t, err)
+
+	list := volumeList{}
+	assert.NoError(t, json.Unmarshal([]byte(manifest), &list))
+	assert.Len(t, list.Items, 3)
+	assert.Equal(t, "pv0003", list.Items[2].Metadata.Name)
+	assert.Equal(t, "5Gi", list.Items[2].Spec.Capacity["storage"])
+	assert.Equal(t, "Retain", list.Items[2].Spec.ReclaimPolicy)
+	assert.Equal(t, "/var/lib/minishift/openshift.local.pv/pv0003", list.Items[2].Spec.HostPath.Path)
+}
+
+func Test_invalid_pool_settings_are_rejected(t *testing.T) {
+	_, err := NewPool(3, "5 gigabytes", "").Manifest()
+	assert.Error(t, err)
+
+	_, err = NewPool(3, "", "Delete").Manifest()
+	assert.Error(t, err)
+
+	_, err = NewPool(MaxCount+1, "", "").Manifest()
+	assert.Error(t, err)
+}
+
+func Test_only_pool_volumes_are_parsed(t *testing.T) {
+	volumes, err := parseVolumes(testVolumes)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Volume{
+		{Name: "pv0001", Capacity: "5Gi", ReclaimPolicy: "Retain", Phase: "Available", Path: "/var/lib/minishift/openshift.local.pv/pv0001"},
+		{Name: "pv0002", Capacity: "5Gi", ReclaimPolicy: "Retain", Phase: "Released", Claim: "myproject/data", Path: "/var/lib/minishift/openshift.local.pv/pv0002"},
+	}, volumes)
+}
@@ -24,12 +24,14 @@ import (
 	"time"
 
 	"github.com/docker/machine/libmachine/provision"
-	"github.com/golang/glog"
 	"github.com/minishift/minishift/pkg/util"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"github.com/minishift/minishift/pkg/util/progressdots"
 	minishiftStrings "github.com/minishift/minishift/pkg/util/strings"
 )
 
+var logger = logging.For("registration")
+
 const (
 	RHSMName       = "   Red Hat Developers or Red Hat Subscription Management (RHSM)"
 	RedHatRegistry = "registry.redhat.io"
@@ -98,9 +100,7 @@ func (registrator *RedHatRegistrator) Register(param *RegistrationParameters) er
 					} else {
 						fmt.Println(" OK ")
 					}
-					if glog.V(3) {
-						fmt.Println(err)
-					}
+					logger.Debugf("%s", err)
 				}
 			}
 
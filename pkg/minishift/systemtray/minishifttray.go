@@ -25,12 +25,14 @@ import (
 	"runtime"
 	"syscall"
 
-	"github.com/golang/glog"
 	minishiftConfig "github.com/minishift/minishift/pkg/minishift/config"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"github.com/minishift/minishift/pkg/util/os"
 	"github.com/minishift/minishift/pkg/util/os/process"
 )
 
+var logger = logging.For("systemtray")
+
 type MinishiftTray struct {
 	globalConfig *minishiftConfig.GlobalConfigType
 }
@@ -62,9 +64,7 @@ func minishiftTrayCommand() (*exec.Cmd, error) {
 func (s *MinishiftTray) EnsureRunning() error {
 	running := s.isRunning()
 	if running {
-		if glog.V(2) {
-			fmt.Println(fmt.Sprintf("systemtray running with pid %d", s.globalConfig.SystrayPID))
-		}
+		logger.Debugf("systemtray running with pid %d", s.globalConfig.SystrayPID)
 		return nil
 	}
 
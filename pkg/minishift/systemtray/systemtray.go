@@ -32,7 +32,6 @@ import (
 
 	"github.com/anjannath/systray"
 	"github.com/docker/machine/libmachine"
-	"github.com/golang/glog"
 	cmdUtil "github.com/minishift/minishift/cmd/minishift/cmd/util"
 	"github.com/minishift/minishift/cmd/minishift/state"
 	"github.com/minishift/minishift/pkg/minikube/constants"
@@ -201,8 +200,8 @@ func stopProfile(profileName string) error {
 		args := []string{"-F", "-a", "Terminal.app", stopFilePath}
 		cmd, err := exec.LookPath("open")
 		if err != nil {
-			if glog.V(3) {
-				fmt.Println("Could not find open in path")
+			if logger.IsDebug() {
+				logger.Debugf("Could not find open in path")
 				return fmt.Errorf("%v", err)
 			}
 		}
@@ -254,8 +253,8 @@ func startProfile(profileName string) error {
 		args := []string{"-F", "-a", "Terminal.app", startFilePath}
 		cmd, err := exec.LookPath("open")
 		if err != nil {
-			if glog.V(3) {
-				fmt.Println("Could not find open in path")
+			if logger.IsDebug() {
+				logger.Debugf("Could not find open in path")
 				return fmt.Errorf("%v", err)
 			}
 		}
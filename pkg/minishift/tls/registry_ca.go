@@ -0,0 +1,138 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tls
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+)
+
+const (
+	// DockerCertsDir is the directory of the per registry certificates of the Docker daemon inside the VM
+	DockerCertsDir = "/etc/docker/certs.d"
+	// TrustAnchorsDir is the directory of the additional trust anchors of the system trust store inside the VM
+	TrustAnchorsDir = "/etc/pki/ca-trust/source/anchors"
+
+	registryCAPrefix = "minishift-registry-ca-"
+	// registryCAFile is the name of the certificates in the certs.d directories. The Docker daemon uses all *.crt
+	// files of these directories as CA, the name only allows Minishift to tell its own certificates apart.
+	registryCAFile = "minishift-registry-ca.crt"
+)
+
+// RegistryCA is a CA certificate on the host, optionally bound to the registry it is used for.
+type RegistryCA struct {
+	Registry string
+	Path     string
+}
+
+// ParseRegistryCA parses a registry CA setting of the form [<registry>=]<path> and verifies that the file
+// contains a PEM encoded certificate.
+func ParseRegistryCA(entry string) (*RegistryCA, error) {
+	ca := &RegistryCA{Path: strings.TrimSpace(entry)}
+	if i := strings.Index(ca.Path, "="); i >= 0 {
+		ca.Registry = strings.TrimSpace(ca.Path[:i])
+		ca.Path = strings.TrimSpace(ca.Path[i+1:])
+		if ca.Registry == "" {
+			return nil, fmt.Errorf("Missing registry in registry CA '%s'", entry)
+		}
+	}
+
+	if _, err := ca.certificate(); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// SetRegistryCACertificates adds the CA certificates to the system trust store of the VM. Certificates bound to a
+// registry are additionally placed into the certs.d directory of the Docker daemon. The certificates of entries which
+// got removed from the configuration are removed from the VM, also if there are no entries left.
+func SetRegistryCACertificates(driver drivers.Driver, entries []string) error {
+	// certificates of entries which got removed from the configuration must not be trusted any longer
+	out, err := drivers.RunSSHCommandFromDriver(driver, fmt.Sprintf("ls %s 2> /dev/null; true", registryCAFiles()))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(out) != "" {
+		if _, err := drivers.RunSSHCommandFromDriver(driver, fmt.Sprintf("sudo rm -f %s", registryCAFiles())); err != nil {
+			return err
+		}
+	} else if len(entries) == 0 {
+		return nil
+	}
+
+	for i, entry := range entries {
+		ca, err := ParseRegistryCA(entry)
+		if err != nil {
+			return err
+		}
+		certificate, err := ca.certificate()
+		if err != nil {
+			return err
+		}
+
+		targets := []string{path.Join(TrustAnchorsDir, fmt.Sprintf("%s%d.crt", registryCAPrefix, i))}
+		if ca.Registry != "" {
+			targets = append(targets, path.Join(DockerCertsDir, ca.Registry, registryCAFile))
+		}
+		for _, target := range targets {
+			if err := writeCertificate(driver, certificate, target); err != nil {
+				return fmt.Errorf("Error copying '%s' to '%s': %v", ca.Path, target, err)
+			}
+		}
+	}
+
+	if _, err := drivers.RunSSHCommandFromDriver(driver, "sudo update-ca-trust extract"); err != nil {
+		return fmt.Errorf("Error updating the system trust store: %v", err)
+	}
+	return nil
+}
+
+// registryCAFiles returns the patterns matching the certificates SetRegistryCACertificates places into the VM
+func registryCAFiles() string {
+	return fmt.Sprintf("%s %s", path.Join(TrustAnchorsDir, registryCAPrefix+"*.crt"), path.Join(DockerCertsDir, "*", registryCAFile))
+}
+
+func (ca *RegistryCA) certificate() ([]byte, error) {
+	content, err := ioutil.ReadFile(ca.Path)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read registry CA '%s': %v", ca.Path, err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("'%s' does not contain a PEM encoded certificate", ca.Path)
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return nil, fmt.Errorf("'%s' does not contain a valid certificate: %v", ca.Path, err)
+	}
+	return content, nil
+}
+
+func writeCertificate(driver drivers.Driver, certificate []byte, target string) error {
+	cmd := fmt.Sprintf(
+		"sudo mkdir -p %s && echo %s | base64 --decode | sudo tee %s > /dev/null",
+		path.Dir(target), base64.StdEncoding.EncodeToString(certificate), target)
+	_, err := drivers.RunSSHCommandFromDriver(driver, cmd)
+	return err
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tls
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_Registry_CA(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-registry-ca-")
+	assert.NoError(t, err, "Error creating temp directory")
+	defer os.RemoveAll(testDir)
+
+	caPath := filepath.Join(testDir, "ca.crt")
+	assert.NoError(t, ioutil.WriteFile(caPath, CACert, 0644))
+	keyPath := filepath.Join(testDir, "ca.key")
+	assert.NoError(t, ioutil.WriteFile(keyPath, CAKey, 0644))
+
+	ca, err := ParseRegistryCA(caPath)
+	assert.NoError(t, err)
+	assert.Equal(t, &RegistryCA{Path: caPath}, ca)
+
+	ca, err = ParseRegistryCA(" registry.acme.com:5000 = " + caPath)
+	assert.NoError(t, err)
+	assert.Equal(t, &RegistryCA{Registry: "registry.acme.com:5000", Path: caPath}, ca)
+
+	_, err = ParseRegistryCA("=" + caPath)
+	assert.EqualError(t, err, "Missing registry in registry CA '="+caPath+"'")
+
+	_, err = ParseRegistryCA(keyPath)
+	assert.EqualError(t, err, "'"+keyPath+"' does not contain a PEM encoded certificate")
+
+	_, err = ParseRegistryCA(filepath.Join(testDir, "missing.crt"))
+	assert.Error(t, err)
+}
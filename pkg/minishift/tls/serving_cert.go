@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tls
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path"
+	"regexp"
+	"strings"
+
+	minishiftConstants "github.com/minishift/minishift/pkg/minishift/constants"
+	"github.com/minishift/minishift/pkg/minishift/docker"
+)
+
+const (
+	servingCertFile = "master.server.crt"
+	servingKeyFile  = "master.server.key"
+)
+
+var (
+	dnsName = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?)*$`)
+
+	// both API servers serve the API on the same port, so both need to present the extra names
+	servingCertDirs = []string{
+		path.Join(minishiftConstants.BaseDirInsideInstance, "kube-apiserver"),
+		path.Join(minishiftConstants.BaseDirInsideInstance, "openshift-apiserver"),
+	}
+)
+
+// ValidateSAN checks that the subject alternative name is either an IP address or a DNS name.
+func ValidateSAN(san string) error {
+	if net.ParseIP(san) != nil || dnsName.MatchString(san) {
+		return nil
+	}
+	return fmt.Errorf("'%s' is neither a valid IP address nor a valid DNS name", san)
+}
+
+// MissingSANs returns the subject alternative names out of sans which the certificate does not contain.
+func MissingSANs(cert *x509.Certificate, sans []string) []string {
+	var missing []string
+	for _, san := range sans {
+		if !containsString(certificateSANs(cert), san) {
+			missing = append(missing, san)
+		}
+	}
+	return missing
+}
+
+// EnsureServingCertSANs makes sure the serving certificates of the API servers contain the specified subject
+// alternative names in addition to the ones generated by 'cluster up'. Certificates lacking any of the names are
+// re-issued by the cluster CA. The method returns true if a certificate was re-issued, in which case OpenShift needs
+// to be restarted.
+func EnsureServingCertSANs(sans []string, commander docker.DockerCommander) (bool, error) {
+	for _, san := range sans {
+		if err := ValidateSAN(san); err != nil {
+			return false, err
+		}
+	}
+
+	reissued := false
+	for _, dir := range servingCertDirs {
+		certPath := path.Join(dir, servingCertFile)
+		content, err := commander.LocalExec(fmt.Sprintf("sudo cat %s", certPath))
+		if err != nil {
+			return false, fmt.Errorf("Error reading the serving certificate '%s': %v", certPath, err)
+		}
+		cert, err := parseCertificate([]byte(content))
+		if err != nil {
+			return false, fmt.Errorf("Error parsing the serving certificate '%s': %v", certPath, err)
+		}
+
+		missing := MissingSANs(cert, sans)
+		if len(missing) == 0 {
+			continue
+		}
+
+		hostnames := append(certificateSANs(cert), missing...)
+		cmd := fmt.Sprintf("sudo %s/oc adm ca create-server-cert --signer-cert=%s --signer-key=%s --signer-serial=%s --hostnames=%s --cert=%s --key=%s --overwrite=true",
+			minishiftConstants.OcPathInsideVM,
+			path.Join(dir, "ca.crt"), path.Join(dir, "ca.key"), path.Join(dir, "ca.serial.txt"),
+			strings.Join(hostnames, ","),
+			certPath, path.Join(dir, servingKeyFile))
+		if _, err := commander.LocalExec(cmd); err != nil {
+			return false, fmt.Errorf("Error re-issuing the serving certificate '%s': %v", certPath, err)
+		}
+		reissued = true
+	}
+	return reissued, nil
+}
+
+// ServingCert returns the serving certificate of the API server.
+func ServingCert(commander docker.DockerCommander) (*x509.Certificate, error) {
+	certPath := path.Join(servingCertDirs[0], servingCertFile)
+	content, err := commander.LocalExec(fmt.Sprintf("sudo cat %s", certPath))
+	if err != nil {
+		return nil, fmt.Errorf("Error reading the serving certificate '%s': %v", certPath, err)
+	}
+	return parseCertificate([]byte(content))
+}
+
+// ReadCertificateFile reads the PEM encoded certificate from a file on the host.
+func ReadCertificateFile(certPath string) (*x509.Certificate, error) {
+	content, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseCertificate(content)
+}
+
+// certificateSANs returns the DNS names and IP addresses of the certificate in the format of the '--hostnames' flag.
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+func parseCertificate(content []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("No PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+		if ip := net.ParseIP(value); ip != nil && ip.Equal(net.ParseIP(v)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minishift/minishift/pkg/minishift/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCertCommander struct {
+	docker.DockerCommander
+	cert     string
+	commands []string
+}
+
+func (f *fakeCertCommander) LocalExec(cmd string) (string, error) {
+	f.commands = append(f.commands, cmd)
+	if strings.HasPrefix(cmd, "sudo cat ") {
+		return f.cert, nil
+	}
+	return "", nil
+}
+
+func Test_SANs_Are_Validated(t *testing.T) {
+	for _, san := range []string{"my-laptop.corp", "localhost", "192.168.1.50", "fe80::1"} {
+		assert.NoError(t, ValidateSAN(san))
+	}
+	for _, san := range []string{"", "my laptop", "-foo.corp", "foo..corp", "http://foo"} {
+		assert.Error(t, ValidateSAN(san), "Expected '%s' to be invalid", san)
+	}
+}
+
+func Test_Missing_SANs_Are_Detected(t *testing.T) {
+	cert := createServingCert(t)
+	assert.Empty(t, MissingSANs(cert, []string{"LOCALHOST", "127.0.0.1", "::1"}))
+	assert.Equal(t, []string{"my-laptop.corp", "192.168.1.50"}, MissingSANs(cert, []string{"localhost", "my-laptop.corp", "192.168.1.50"}))
+}
+
+func Test_Serving_Cert_Is_Only_Reissued_If_SANs_Are_Missing(t *testing.T) {
+	cert := createServingCert(t)
+	commander := &fakeCertCommander{cert: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))}
+
+	reissued, err := EnsureServingCertSANs([]string{"localhost"}, commander)
+	assert.NoError(t, err)
+	assert.False(t, reissued)
+	assert.Len(t, commander.commands, 2)
+
+	commander.commands = nil
+	reissued, err = EnsureServingCertSANs([]string{"my-laptop.corp"}, commander)
+	assert.NoError(t, err)
+	assert.True(t, reissued)
+	assert.Len(t, commander.commands, 4)
+	assert.Contains(t, commander.commands[1], "--hostnames=localhost,127.0.0.1,::1,my-laptop.corp ")
+
+	_, err = EnsureServingCertSANs([]string{"my laptop"}, commander)
+	assert.Error(t, err)
+}
+
+func Test_Serving_Cert_Is_Read_From_The_VM(t *testing.T) {
+	cert := createServingCert(t)
+	commander := &fakeCertCommander{cert: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))}
+
+	servingCert, err := ServingCert(commander)
+	assert.NoError(t, err)
+	assert.Equal(t, cert.NotAfter, servingCert.NotAfter)
+	assert.Equal(t, []string{"sudo cat /var/lib/minishift/base/kube-apiserver/master.server.crt"}, commander.commands)
+
+	commander.cert = "garbage"
+	_, err = ServingCert(commander)
+	assert.Error(t, err)
+}
+
+func createServingCert(t *testing.T) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "172.30.0.1"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert
+}
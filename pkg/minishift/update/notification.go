@@ -0,0 +1,186 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/google/go-github/github"
+	"github.com/minishift/minishift/pkg/minikube/constants"
+	openshiftVersion "github.com/minishift/minishift/pkg/minishift/openshift/version"
+	"github.com/minishift/minishift/pkg/util/os/lock"
+)
+
+const (
+	// CheckInterval is the minimum time between two update checks and between two update notices
+	CheckInterval = 24 * time.Hour
+
+	// checkTimeout limits the time spent on an update check, so that a slow network never delays a command noticeably
+	checkTimeout = 10 * time.Second
+
+	// stateLockTimeout is the time to wait for another Minishift process updating the state of the update checks
+	stateLockTimeout = 5 * time.Second
+
+	centOsIsoRepo = "minishift-centos-iso"
+	originOwner   = "openshift"
+	originRepo    = "origin"
+)
+
+// CheckState records the latest versions found by the last update check and when the user was last notified
+type CheckState struct {
+	LastCheck  time.Time
+	LastNotice time.Time
+	Minishift  string `json:",omitempty"`
+	CentOsIso  string `json:",omitempty"`
+	OpenShift  string `json:",omitempty"`
+}
+
+// InstalledVersions are the versions the running Minishift binary uses
+type InstalledVersions struct {
+	Minishift string
+	CentOsIso string
+	OpenShift string
+}
+
+// ReadCheckState reads the state of the update checks. A missing or unreadable state file results in an empty state.
+func ReadCheckState(path string) CheckState {
+	state := CheckState{}
+	if raw, err := ioutil.ReadFile(path); err == nil {
+		json.Unmarshal(raw, &state)
+	}
+	return state
+}
+
+// Write writes the state of the update checks
+func (state CheckState) Write(path string) error {
+	raw, err := json.MarshalIndent(state, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, raw, 0644)
+}
+
+// UpdateCheckState reads the state of the update checks, passes it to modify and writes it back if modify returns true.
+// The state file is locked meanwhile, so that Minishift processes running in parallel do not overwrite each other's changes.
+func UpdateCheckState(path string, modify func(state *CheckState) bool) error {
+	fileLock, err := lock.Acquire(path+".lock", stateLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer fileLock.Release()
+
+	state := ReadCheckState(path)
+	if !modify(&state) {
+		return nil
+	}
+	return state.Write(path)
+}
+
+// IsCheckDue returns true if the last update check is older than CheckInterval
+func (state CheckState) IsCheckDue(now time.Time) bool {
+	return now.Sub(state.LastCheck) >= CheckInterval
+}
+
+// IsNoticeDue returns true if the user was last notified about available updates longer than CheckInterval ago
+func (state CheckState) IsNoticeDue(now time.Time) bool {
+	return now.Sub(state.LastNotice) >= CheckInterval
+}
+
+// CheckLatestVersions looks up the latest Minishift release on the specified channel and the latest stable CentOS ISO
+// and OpenShift releases. Only OpenShift releases which Minishift can provision are considered. The check gives up
+// after checkTimeout.
+func CheckLatestVersions(channel string) (CheckState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	state := CheckState{}
+	for _, check := range []struct {
+		owner   string
+		repo    string
+		channel string
+		latest  *string
+	}{
+		{githubOwner, githubRepo, channel, &state.Minishift},
+		{githubOwner, centOsIsoRepo, StableChannel, &state.CentOsIso},
+		{originOwner, originRepo, StableChannel, &state.OpenShift},
+	} {
+		releases, err := getReleasesFromGitHub(ctx, check.owner, check.repo)
+		if err != nil {
+			return CheckState{}, err
+		}
+		if check.latest == &state.OpenShift {
+			releases = compatibleOpenShiftReleases(releases)
+		}
+		latest, err := latestVersionOnChannel(releases, check.channel)
+		if err != nil {
+			return CheckState{}, err
+		}
+		*check.latest = latest.String()
+	}
+	return state, nil
+}
+
+// compatibleOpenShiftReleases drops the OpenShift releases which cannot be provisioned by Minishift
+func compatibleOpenShiftReleases(releases []*github.RepositoryRelease) []*github.RepositoryRelease {
+	var compatible []*github.RepositoryRelease
+	for _, release := range releases {
+		if openshiftVersion.IsCompatibleVersion(release.GetTagName(), constants.MinimumSupportedOpenShiftVersion) {
+			compatible = append(compatible, release)
+		}
+	}
+	return compatible
+}
+
+// Notice returns the one-line notice about the newer versions recorded in the state, empty if the installed
+// versions are up to date.
+func (state CheckState) Notice(installed InstalledVersions) string {
+	var newer []string
+	hint := ""
+	if isNewer(installed.Minishift, state.Minishift) {
+		newer = append(newer, fmt.Sprintf("Minishift %s", state.Minishift))
+		hint = " Run 'minishift update' to update Minishift."
+	}
+	if isNewer(installed.CentOsIso, state.CentOsIso) {
+		newer = append(newer, fmt.Sprintf("CentOS ISO v%s", state.CentOsIso))
+	}
+	if isNewer(installed.OpenShift, state.OpenShift) {
+		newer = append(newer, fmt.Sprintf("OpenShift v%s", state.OpenShift))
+	}
+	if len(newer) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Updates are available: %s.%s", strings.Join(newer, ", "), hint)
+}
+
+// isNewer returns true if both versions are valid and latest is newer than installed
+func isNewer(installed string, latest string) bool {
+	installedVersion, err := semver.Make(strings.TrimPrefix(installed, "v"))
+	if err != nil {
+		return false
+	}
+	latestVersion, err := semver.Make(strings.TrimPrefix(latest, "v"))
+	if err != nil {
+		return false
+	}
+	return IsNewerVersion(installedVersion, latestVersion)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckStateIsRateLimited(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-update-check-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	path := filepath.Join(testDir, "update-check.json")
+	now := time.Date(2019, 10, 15, 9, 0, 0, 0, time.UTC)
+
+	state := ReadCheckState(path)
+	assert.True(t, state.IsCheckDue(now))
+	assert.True(t, state.IsNoticeDue(now))
+
+	state.LastCheck = now
+	state.LastNotice = now
+	assert.NoError(t, state.Write(path))
+
+	state = ReadCheckState(path)
+	assert.False(t, state.IsCheckDue(now.Add(23*time.Hour)))
+	assert.False(t, state.IsNoticeDue(now.Add(23*time.Hour)))
+	assert.True(t, state.IsCheckDue(now.Add(24*time.Hour)))
+}
+
+func TestNotice(t *testing.T) {
+	installed := InstalledVersions{Minishift: "1.34.3", CentOsIso: "v1.17.0", OpenShift: "v3.11.0"}
+
+	state := CheckState{Minishift: "1.34.3", CentOsIso: "1.17.0", OpenShift: "3.11.0"}
+	assert.Empty(t, state.Notice(installed))
+
+	state = CheckState{Minishift: "1.35.0", CentOsIso: "1.18.0", OpenShift: "3.11.0"}
+	assert.Equal(t, "Updates are available: Minishift 1.35.0, CentOS ISO v1.18.0. Run 'minishift update' to update Minishift.", state.Notice(installed))
+
+	state = CheckState{OpenShift: "3.11.1"}
+	assert.Equal(t, "Updates are available: OpenShift v3.11.1.", state.Notice(installed))
+}
+
+func TestUpdateCheckStateKeepsOtherFields(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-update-check-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	path := filepath.Join(testDir, "update-check.json")
+	now := time.Date(2019, 10, 15, 9, 0, 0, 0, time.UTC)
+	assert.NoError(t, CheckState{LastNotice: now}.Write(path))
+
+	err = UpdateCheckState(path, func(state *CheckState) bool {
+		state.Minishift = "1.35.0"
+		return true
+	})
+	assert.NoError(t, err)
+
+	err = UpdateCheckState(path, func(state *CheckState) bool {
+		state.CentOsIso = "1.18.0"
+		return false
+	})
+	assert.NoError(t, err)
+
+	state := ReadCheckState(path)
+	assert.True(t, now.Equal(state.LastNotice))
+	assert.Equal(t, "1.35.0", state.Minishift)
+	assert.Empty(t, state.CentOsIso)
+}
+
+func TestOnlyCompatibleOpenShiftReleasesAreConsidered(t *testing.T) {
+	var releases []*github.RepositoryRelease
+	for _, tag := range []string{"v3.9.0", "v3.11.0", "v4.1.0"} {
+		releases = append(releases, &github.RepositoryRelease{TagName: github.String(tag)})
+	}
+
+	latest, err := latestVersionOnChannel(compatibleOpenShiftReleases(releases), StableChannel)
+	assert.NoError(t, err)
+	assert.Equal(t, "3.11.0", latest.String())
+}
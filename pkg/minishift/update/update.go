@@ -46,8 +46,15 @@ import (
 const (
 	githubOwner = "minishift"
 	githubRepo  = "minishift"
+
+	// StableChannel is the release channel of the final releases
+	StableChannel = "stable"
+	// BetaChannel is the release channel of the final releases and the pre-releases
+	BetaChannel = "beta"
 )
 
+var ValidChannels = []string{StableChannel, BetaChannel}
+
 // CurrentVersion returns the current version of minishift binary installed on the system
 func CurrentVersion() (semver.Version, error) {
 	localVersion, err := version.GetSemverVersion()
@@ -57,13 +64,24 @@ func CurrentVersion() (semver.Version, error) {
 	return localVersion, nil
 }
 
-// LatestVersion returns the latest version of minishift binary available from upstream
-func LatestVersion() (semver.Version, error) {
-	latestVersion, err := getLatestVersionFromGitHub(githubOwner, githubRepo)
+// LatestVersion returns the latest version of minishift binary available from upstream on the specified release
+// channel. The stable channel only considers final releases, the beta channel pre-releases as well.
+func LatestVersion(channel string) (semver.Version, error) {
+	releases, err := getReleasesFromGitHub(context.Background(), githubOwner, githubRepo)
 	if err != nil {
 		return semver.Version{}, err
 	}
-	return latestVersion, nil
+	return latestVersionOnChannel(releases, channel)
+}
+
+// IsReleasedOnChannel returns an error unless the specified version of minishift is released on the release channel,
+// so that a version requested explicitly cannot bypass the channel.
+func IsReleasedOnChannel(requestedVersion semver.Version, channel string) error {
+	releases, err := getReleasesFromGitHub(context.Background(), githubOwner, githubRepo)
+	if err != nil {
+		return err
+	}
+	return releasedOnChannel(releases, requestedVersion, channel)
 }
 
 // IsNewerVersion compares the local and latest versions and returns a boolean
@@ -123,30 +141,71 @@ func Update(latestVersion semver.Version) error {
 	return nil
 }
 
-// getLatestVersionFromGitHub gets the latest version of minishift available on GitHub.
-// It returns the version and error.
-func getLatestVersionFromGitHub(githubOwner, githubRepo string) (semver.Version, error) {
-	var (
-		release *github.RepositoryRelease
-		resp    *github.Response
-		err     error
-	)
-
+// getReleasesFromGitHub gets all releases of minishift available on GitHub, following the pagination of the API.
+// It returns the releases and error.
+func getReleasesFromGitHub(ctx context.Context, githubOwner, githubRepo string) ([]*github.RepositoryRelease, error) {
 	client := githubutils.Client()
-	ctx := context.Background()
-	release, resp, err = client.Repositories.GetLatestRelease(ctx, githubOwner, githubRepo)
-	if err != nil {
-		return semver.Version{}, err
+	var releases []*github.RepositoryRelease
+	listOptions := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.Repositories.ListReleases(ctx, githubOwner, githubRepo, listOptions)
+		if err != nil {
+			return nil, err
+		}
+		_ = resp.Body.Close()
+
+		releases = append(releases, page...)
+		if resp.NextPage == 0 {
+			return releases, nil
+		}
+		listOptions.Page = resp.NextPage
 	}
-	defer func() { _ = resp.Body.Close() }()
+}
 
-	latestVersionString := release.TagName
-	if latestVersionString != nil {
-		return semver.Make(strings.TrimPrefix(*latestVersionString, "v"))
+// latestVersionOnChannel returns the highest version among the releases published on the specified channel.
+// Drafts and releases without a semantic version as tag are ignored.
+func latestVersionOnChannel(releases []*github.RepositoryRelease, channel string) (semver.Version, error) {
+	var latest *semver.Version
+	for _, release := range releases {
+		releaseVersion, ok := versionOnChannel(release, channel)
+		if !ok {
+			continue
+		}
+		if latest == nil || releaseVersion.GT(*latest) {
+			latest = &releaseVersion
+		}
+	}
 
+	if latest == nil {
+		return semver.Version{}, fmt.Errorf("Cannot find a release on the '%s' channel.", channel)
 	}
+	return *latest, nil
+}
 
-	return semver.Version{}, errors.New("Cannot get release name.")
+// releasedOnChannel returns an error unless one of the releases published on the specified channel has the version
+func releasedOnChannel(releases []*github.RepositoryRelease, requestedVersion semver.Version, channel string) error {
+	for _, release := range releases {
+		if releaseVersion, ok := versionOnChannel(release, channel); ok && releaseVersion.Equals(requestedVersion) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Version %s is not released on the '%s' channel.", requestedVersion, channel)
+}
+
+// versionOnChannel returns the version of the release, unless the release is a draft, has no semantic version as tag
+// or is not published on the specified channel.
+func versionOnChannel(release *github.RepositoryRelease, channel string) (semver.Version, bool) {
+	if release.GetDraft() {
+		return semver.Version{}, false
+	}
+	releaseVersion, err := semver.Make(strings.TrimPrefix(release.GetTagName(), "v"))
+	if err != nil {
+		return semver.Version{}, false
+	}
+	if channel == StableChannel && (release.GetPrerelease() || len(releaseVersion.Pre) > 0) {
+		return semver.Version{}, false
+	}
+	return releaseVersion, true
 }
 
 // downloadAndVerifyArchive downloads the archive of latest minishift version from GitHub
@@ -192,7 +251,7 @@ func downloadAndVerifyArchive(url, tmpDir string) (string, error) {
 	}
 
 	// Download checksum file from GitHub
-	checksumURL := fmt.Sprintf(url + ".sha256")
+	checksumURL := url + ".sha256"
 	checksumResp, err := http.Get(checksumURL)
 	if err != nil {
 		return "", err
@@ -25,6 +25,8 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/blang/semver"
+	"github.com/google/go-github/github"
 	minitesting "github.com/minishift/minishift/pkg/testing"
 	minishiftos "github.com/minishift/minishift/pkg/util/os"
 	"github.com/stretchr/testify/assert"
@@ -151,6 +153,48 @@ func TestExtractBinaryWithoutHavingMiniShift(t *testing.T) {
 
 }
 
+func TestLatestVersionOnChannel(t *testing.T) {
+	release := func(tag string, prerelease bool, draft bool) *github.RepositoryRelease {
+		return &github.RepositoryRelease{TagName: &tag, Prerelease: &prerelease, Draft: &draft}
+	}
+	releases := []*github.RepositoryRelease{
+		release("v1.35.0", false, true),
+		release("v1.35.0-beta.1", true, false),
+		release("v1.34.3", false, false),
+		release("v1.34.10-rc.1", false, false),
+		release("nightly", true, false),
+		release("v1.34.2", false, false),
+	}
+
+	latest, err := latestVersionOnChannel(releases, StableChannel)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.34.3", latest.String())
+
+	latest, err = latestVersionOnChannel(releases, BetaChannel)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.35.0-beta.1", latest.String())
+
+	_, err = latestVersionOnChannel(releases[:2], StableChannel)
+	assert.EqualError(t, err, "Cannot find a release on the 'stable' channel.")
+}
+
+func TestReleasedOnChannel(t *testing.T) {
+	release := func(tag string, prerelease bool, draft bool) *github.RepositoryRelease {
+		return &github.RepositoryRelease{TagName: &tag, Prerelease: &prerelease, Draft: &draft}
+	}
+	releases := []*github.RepositoryRelease{
+		release("v1.35.0", false, true),
+		release("v1.35.0-beta.1", true, false),
+		release("v1.34.3", false, false),
+	}
+
+	assert.NoError(t, releasedOnChannel(releases, semver.MustParse("1.34.3"), StableChannel))
+	assert.NoError(t, releasedOnChannel(releases, semver.MustParse("1.35.0-beta.1"), BetaChannel))
+	assert.EqualError(t, releasedOnChannel(releases, semver.MustParse("1.35.0-beta.1"), StableChannel),
+		"Version 1.35.0-beta.1 is not released on the 'stable' channel.")
+	assert.Error(t, releasedOnChannel(releases, semver.MustParse("1.35.0"), BetaChannel), "Drafts are not released")
+}
+
 func setUp(t *testing.T) {
 	testDir, err = ioutil.TempDir("", "minishift-test-")
 	assert.NoError(t, err)
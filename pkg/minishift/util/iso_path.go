@@ -32,6 +32,8 @@ func GetIsoPath(isoURL string) string {
 	switch true {
 	case strings.Contains(filepath.Base(isoURL), minishiftConstants.CentOsIsoAlias):
 		return filepath.Join(minishiftConstants.CentOsIsoAlias, getIsoVersion(uri.Path))
+	case strings.Contains(filepath.Base(isoURL), minishiftConstants.B2dIsoAlias):
+		return filepath.Join(minishiftConstants.B2dIsoAlias, getIsoVersion(uri.Path))
 	default:
 		// This handle any random URI
 		return filepath.Join("unnamed")
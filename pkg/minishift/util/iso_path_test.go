@@ -30,6 +30,7 @@ func TestGetIsoPath(t *testing.T) {
 	}{
 		{"https://github.com/minishift/minishift-centos-iso/releases/download/v1.1.0/minishift-centos7.iso", filepath.Join("centos", "v1.1.0")},
 		{"https://github.com/minishift/minishift-centos-iso/releases/download/v1.3.0/minishift-centos7.iso", filepath.Join("centos", "v1.3.0")},
+		{"https://github.com/minishift/minishift-b2d-iso/releases/download/v1.3.0/minishift-b2d.iso", filepath.Join("b2d", "v1.3.0")},
 		{"https://foo/v1.2.0/minishift-foo.iso", "unnamed"},
 	}
 
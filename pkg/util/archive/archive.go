@@ -20,7 +20,9 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 )
@@ -142,3 +144,92 @@ func Unzip(archive, target string) error {
 
 	return nil
 }
+
+// WalkTarGz calls fn for each regular file of the gzip compressed tarball until fn returns false or an error.
+func WalkTarGz(tarball string, fn func(header *tar.Header, r io.Reader) (bool, error)) error {
+	file, err := os.Open(tarball)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a gzip compressed tarball: %v", tarball, err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		proceed, err := fn(header, tarReader)
+		if err != nil || !proceed {
+			return err
+		}
+	}
+}
+
+// AddTarContent adds content as regular file name to the tarball.
+func AddTarContent(w *tar.Writer, name string, content []byte) error {
+	if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err := w.Write(content)
+	return err
+}
+
+// AddTarFile adds the file source as regular file name with the given mode to the tarball.
+func AddTarFile(w *tar.Writer, name string, source string, mode os.FileMode) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{Name: name, Mode: int64(mode.Perm()), Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg}
+	if err := w.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// ExtractFile writes the content of the reader to target, creating its parent directories as needed. The content
+// is written to a temporary file first, so that a failed extraction never leaves a partial file behind.
+func ExtractFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(target), ".extract-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = io.Copy(tmpFile, r)
+	tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpFile.Name(), mode.Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), target)
+}
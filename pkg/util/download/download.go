@@ -0,0 +1,164 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minishift/minishift/pkg/util/logging"
+	"gopkg.in/cheggaaa/pb.v1"
+)
+
+// PartialFileExtension is the extension of the file holding the content downloaded so far
+const PartialFileExtension = ".part"
+
+var (
+	logger = logging.For("download")
+
+	// mirrors are the base URLs of the mirrors tried in order after the original location of a download failed
+	mirrors []string
+)
+
+// SetMirrors sets the base URLs of the mirrors tried in order after the original location of a download failed.
+// A mirror serves the content under the same path as the original location, for example
+// https://mirror.example.com/github/minishift/minishift-centos-iso/releases/... for the mirror https://mirror.example.com/github.
+func SetMirrors(baseURLs []string) {
+	mirrors = baseURLs
+}
+
+// Locations returns the original location followed by the corresponding locations on the configured mirrors
+func Locations(location string) []string {
+	locations := []string{location}
+	uri, err := url.Parse(location)
+	if err != nil || uri.Host == "" {
+		return locations
+	}
+
+	for _, mirror := range mirrors {
+		mirrored := strings.TrimSuffix(strings.TrimSpace(mirror), "/") + uri.RequestURI()
+		if mirrored != location {
+			locations = append(locations, mirrored)
+		}
+	}
+	return locations
+}
+
+// FromMirrors downloads the resource at location into the file at path, trying the configured mirrors in order
+// if the original location fails. It returns the location the resource was downloaded from.
+func FromMirrors(location string, path string) (string, error) {
+	var errs []string
+	for _, candidate := range Locations(location) {
+		err := ToFile(candidate, path)
+		if err == nil {
+			return candidate, nil
+		}
+		logger.Warnf("Download from '%s' failed: %v", candidate, err)
+		errs = append(errs, err.Error())
+	}
+	return "", fmt.Errorf("Download of '%s' failed: %s", location, strings.Join(errs, "; "))
+}
+
+// ToFile downloads the resource at location into the file at path. The content is written to a partial file next
+// to path first. The partial file of an interrupted download is resumed via an HTTP range request, or downloaded
+// again if the server does not support ranges. Once complete, the partial file is renamed to path.
+func ToFile(location string, path string) error {
+	partialPath := path + PartialFileExtension
+	var offset int64
+	if info, err := os.Stat(partialPath); err == nil {
+		offset = info.Size()
+	}
+
+	request, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+		if start := rangeStart(response.Header.Get("Content-Range")); start != offset {
+			return fmt.Errorf("Received content starting at byte %d instead of %d from %s", start, offset, location)
+		}
+		fmt.Println(fmt.Sprintf("   Resuming the download at %d bytes", offset))
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		offset = 0
+		flags |= os.O_TRUNC
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file is as large as or larger than the resource, it cannot be resumed
+		if err := os.Remove(partialPath); err != nil {
+			return err
+		}
+		return ToFile(location, path)
+	default:
+		return fmt.Errorf("Received %d response from %s", response.StatusCode, location)
+	}
+
+	out, err := os.OpenFile(partialPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var content io.Reader = response.Body
+	if response.ContentLength > 0 {
+		bar := pb.New64(offset + response.ContentLength).SetUnits(pb.U_BYTES)
+		bar.Set64(offset)
+		bar.Start()
+		content = bar.NewProxyReader(content)
+		defer func() {
+			<-time.After(bar.RefreshRate)
+			fmt.Println()
+		}()
+	}
+
+	if _, err := io.Copy(out, content); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+
+	// File descriptor need to be closed otherwise the rename fails on Windows
+	out.Close()
+	return os.Rename(partialPath, path)
+}
+
+// rangeStart returns the first byte of a 'bytes <start>-<end>/<size>' Content-Range header, -1 if it cannot be parsed
+func rangeStart(contentRange string) int64 {
+	var start, end int64
+	var size string
+	if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/%s", &start, &end, &size); err != nil {
+		return -1
+	}
+	return start
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var content = []byte(strings.Repeat("minishift", 1000))
+
+func TestToFileResumesPartialDownload(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-download-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "minishift.iso", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(testDir, "minishift.iso")
+	assert.NoError(t, ioutil.WriteFile(path+PartialFileExtension, content[:1000], 0644))
+
+	assert.NoError(t, ToFile(server.URL+"/minishift.iso", path))
+	assert.Equal(t, []string{"bytes=1000-"}, ranges)
+
+	downloaded, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+	_, err = os.Stat(path + PartialFileExtension)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestToFileRestartsWithoutRangeSupport(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-download-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(testDir, "minishift.iso")
+	assert.NoError(t, ioutil.WriteFile(path+PartialFileExtension, []byte("stale"), 0644))
+
+	assert.NoError(t, ToFile(server.URL+"/minishift.iso", path))
+	downloaded, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+}
+
+func TestFromMirrorsFallsBackInOrder(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-download-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	origin := httptest.NewServer(http.NotFoundHandler())
+	defer origin.Close()
+
+	var requested []string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if !strings.HasPrefix(r.URL.Path, "/second/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(content)
+	}))
+	defer mirror.Close()
+
+	SetMirrors([]string{mirror.URL + "/first", mirror.URL + "/second/"})
+	defer SetMirrors(nil)
+
+	location, err := FromMirrors(origin.URL+"/releases/v1.0.0/minishift.iso", filepath.Join(testDir, "minishift.iso"))
+	assert.NoError(t, err)
+	assert.Equal(t, mirror.URL+"/second/releases/v1.0.0/minishift.iso", location)
+	assert.Equal(t, []string{"/first/releases/v1.0.0/minishift.iso", "/second/releases/v1.0.0/minishift.iso"}, requested)
+
+	SetMirrors(nil)
+	_, err = FromMirrors(origin.URL+"/missing.iso", filepath.Join(testDir, "missing.iso"))
+	assert.Error(t, err)
+}
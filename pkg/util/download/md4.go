@@ -0,0 +1,80 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// md4Shifts and md4Order are the per-round rotations and message word orders of MD4 as specified in RFC 1320
+var (
+	md4Shifts = [3][4]uint{{3, 7, 11, 19}, {3, 5, 9, 13}, {3, 9, 11, 15}}
+	md4Order  = [3][16]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+		{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15},
+		{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15},
+	}
+)
+
+// md4Sum returns the MD4 digest of data. zsync uses MD4 as strong block checksum, MD4 must not be used for
+// anything requiring cryptographic strength.
+// golang.org/x/crypto/md4 is not used since the vendored golang.org/x/crypto is pruned to the packages in use by dep,
+// and pulling in a deprecated hash for this single non-cryptographic use is not worth a dependency update. The RFC 1320
+// test suite in md4_test.go verifies this implementation.
+func md4Sum(data []byte) [16]byte {
+	padded := make([]byte, (len(data)+8)/64*64+64)
+	copy(padded, data)
+	padded[len(data)] = 0x80
+	binary.LittleEndian.PutUint64(padded[len(padded)-8:], uint64(len(data))*8)
+
+	state := [4]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+	var x [16]uint32
+	for chunk := padded; len(chunk) > 0; chunk = chunk[64:] {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(chunk[i*4:])
+		}
+
+		r := state
+		for round := 0; round < 3; round++ {
+			for step := 0; step < 16; step++ {
+				// the register updated in this step, followed by the other three in order
+				a, b, c, d := &r[(16-step)%4], r[(17-step)%4], r[(18-step)%4], r[(19-step)%4]
+				var f uint32
+				switch round {
+				case 0:
+					f = (b & c) | (^b & d)
+				case 1:
+					f = ((b & c) | (b & d) | (c & d)) + 0x5a827999
+				case 2:
+					f = (b ^ c ^ d) + 0x6ed9eba1
+				}
+				*a = bits.RotateLeft32(*a+f+x[md4Order[round][step]], int(md4Shifts[round][step%4]))
+			}
+		}
+
+		for i := range state {
+			state[i] += r[i]
+		}
+	}
+
+	var digest [16]byte
+	for i, word := range state {
+		binary.LittleEndian.PutUint32(digest[i*4:], word)
+	}
+	return digest
+}
@@ -0,0 +1,385 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ZsyncFileExtension is the extension of the zsync control file published next to a resource
+	ZsyncFileExtension = ".zsync"
+
+	// maxBlockGap is the number of already matched blocks re-downloaded to merge two ranges into a single request
+	maxBlockGap = 8
+)
+
+// zsyncControl is the content of a zsync control file, see http://zsync.moria.org.uk/
+type zsyncControl struct {
+	blockSize   int
+	length      int64
+	rsumLen     int
+	checksumLen int
+	url         string
+	sha1        string
+	blocks      []zsyncBlock
+}
+
+// zsyncBlock holds the truncated weak rolling checksum and strong MD4 checksum of a block of the target file
+type zsyncBlock struct {
+	rsum     uint32
+	checksum []byte
+}
+
+// Delta downloads the resource at location into the file at path, reusing the blocks it has in common with the file
+// at seedPath, typically a previous version of the resource. The blocks are identified via the zsync control file
+// published at location plus ZsyncFileExtension and only the remaining blocks are requested via HTTP range requests.
+// An error is returned if no control file is published, the server does not support range requests or the result
+// does not match the SHA-1 of the control file. The caller is expected to fall back to a full download in this case.
+func Delta(location string, seedPath string, path string) error {
+	controlLocation := location + ZsyncFileExtension
+	control, err := fetchZsyncControl(controlLocation)
+	if err != nil {
+		return err
+	}
+	contentLocation, err := resolveLocation(controlLocation, control.url)
+	if err != nil {
+		return err
+	}
+
+	seed, err := os.Open(seedPath)
+	if err != nil {
+		return err
+	}
+	defer seed.Close()
+
+	partialPath := path + PartialFileExtension
+	out, err := os.OpenFile(partialPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := control.patch(seed, out, contentLocation); err != nil {
+		out.Close()
+		os.Remove(partialPath)
+		return err
+	}
+
+	// File descriptor need to be closed otherwise the rename fails on Windows
+	out.Close()
+	return os.Rename(partialPath, path)
+}
+
+// DeltaFromMirrors runs a delta download of the resource at location, trying the configured mirrors in order if the
+// original location fails. It returns the location the resource was downloaded from.
+func DeltaFromMirrors(location string, seedPath string, path string) (string, error) {
+	var errs []string
+	for _, candidate := range Locations(location) {
+		err := Delta(candidate, seedPath, path)
+		if err == nil {
+			return candidate, nil
+		}
+		logger.Debugf("Delta download from '%s' failed: %v", candidate, err)
+		errs = append(errs, err.Error())
+	}
+	return "", fmt.Errorf("Delta download of '%s' failed: %s", location, strings.Join(errs, "; "))
+}
+
+// patch writes the target file into out, copying the matching blocks from seed and downloading the others from
+// contentLocation
+func (c *zsyncControl) patch(seed io.Reader, out *os.File, contentLocation string) error {
+	matched := make([]bool, len(c.blocks))
+	reused, err := c.matchSeed(seed, out, matched)
+	if err != nil {
+		return err
+	}
+
+	ranges := c.missingRanges(matched)
+	var missing int64
+	for _, r := range ranges {
+		missing += r[1] - r[0] + 1
+	}
+	fmt.Println(fmt.Sprintf("   Reusing %d of %d blocks, downloading the remaining %d bytes", reused, len(c.blocks), missing))
+
+	for _, r := range ranges {
+		if err := fetchRange(contentLocation, r[0], r[1], out); err != nil {
+			return err
+		}
+	}
+
+	if err := out.Truncate(c.length); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	hash := sha1.New()
+	if _, err := io.Copy(hash, out); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); c.sha1 != "" && sum != c.sha1 {
+		return fmt.Errorf("Delta download has wrong SHA-1. Expected: %s, got: %s", c.sha1, sum)
+	}
+	return nil
+}
+
+// matchSeed slides a block sized window over seed and writes every window matching a block of the target file at
+// the position of the block in out. It returns the number of matched blocks.
+func (c *zsyncControl) matchSeed(seed io.Reader, out io.WriterAt, matched []bool) (int, error) {
+	mask := uint32(uint64(1)<<(8*uint(c.rsumLen)) - 1)
+	index := make(map[uint32][]int)
+	for i, block := range c.blocks {
+		index[block.rsum] = append(index[block.rsum], i)
+	}
+
+	reader := bufio.NewReaderSize(seed, 1<<20)
+	bs := c.blockSize
+	window := make([]byte, bs)
+	ordered := make([]byte, bs)
+	reused := 0
+	for {
+		// (re)fill the window with a full block, after a match the window continues behind the matched block
+		if _, err := io.ReadFull(reader, window); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return reused, nil
+			}
+			return reused, err
+		}
+		a, b := rsum(window)
+		start := 0
+
+		for {
+			candidates := index[(uint32(a)<<16|uint32(b))&mask]
+			if len(candidates) > 0 && c.matchWindow(window, start, ordered, candidates, out, matched, &reused) {
+				break
+			}
+
+			next, err := reader.ReadByte()
+			if err == io.EOF {
+				return reused, nil
+			}
+			if err != nil {
+				return reused, err
+			}
+			old := window[start]
+			window[start] = next
+			start = (start + 1) % bs
+			a += uint16(next) - uint16(old)
+			b += a - uint16(bs)*uint16(old)
+		}
+	}
+}
+
+// matchWindow compares the strong checksum of the window starting at start with the candidate blocks and writes it
+// to out for every unmatched block it matches. It returns true if the window matched at least one block.
+func (c *zsyncControl) matchWindow(window []byte, start int, ordered []byte, candidates []int, out io.WriterAt, matched []bool, reused *int) bool {
+	n := copy(ordered, window[start:])
+	copy(ordered[n:], window[:start])
+	checksum := md4Sum(ordered)
+
+	found := false
+	for _, i := range candidates {
+		if !bytes.Equal(checksum[:c.checksumLen], c.blocks[i].checksum) {
+			continue
+		}
+		found = true
+		if matched[i] {
+			continue
+		}
+		if _, err := out.WriteAt(ordered, int64(i)*int64(c.blockSize)); err != nil {
+			logger.Warnf("Unable to write block %d: %v", i, err)
+			continue
+		}
+		matched[i] = true
+		*reused++
+	}
+	return found
+}
+
+// missingRanges returns the inclusive byte ranges covering the unmatched blocks. Ranges separated by at most
+// maxBlockGap matched blocks are merged to keep the number of requests low.
+func (c *zsyncControl) missingRanges(matched []bool) [][2]int64 {
+	var ranges [][2]int64
+	bs := int64(c.blockSize)
+	for i := 0; i < len(matched); i++ {
+		if matched[i] {
+			continue
+		}
+		start, end := int64(i)*bs, int64(i+1)*bs-1
+		if end >= c.length {
+			end = c.length - 1
+		}
+		if last := len(ranges) - 1; last >= 0 && start-ranges[last][1]-1 <= maxBlockGap*bs {
+			ranges[last][1] = end
+		} else {
+			ranges = append(ranges, [2]int64{start, end})
+		}
+	}
+	return ranges
+}
+
+// rsum returns the weak rolling checksum of block as used by rsync and zsync
+func rsum(block []byte) (uint16, uint16) {
+	var a, b uint16
+	for _, c := range block {
+		a += uint16(c)
+		b += a
+	}
+	return a, b
+}
+
+// fetchRange downloads the inclusive byte range start-end of the resource at location into out at offset start
+func fetchRange(location string, start int64, end int64, out io.WriteSeeker) error {
+	request, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("Received %d response instead of partial content from %s", response.StatusCode, location)
+	}
+	if got := rangeStart(response.Header.Get("Content-Range")); got != start {
+		return fmt.Errorf("Received content starting at byte %d instead of %d from %s", got, start, location)
+	}
+
+	if _, err := out.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.CopyN(out, response.Body, end-start+1)
+	return err
+}
+
+// fetchZsyncControl downloads and parses the zsync control file at location
+func fetchZsyncControl(location string) (*zsyncControl, error) {
+	response, err := http.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Received %d response from %s", response.StatusCode, location)
+	}
+	return parseZsyncControl(response.Body)
+}
+
+// parseZsyncControl parses the header lines and the block checksums of a zsync control file
+func parseZsyncControl(in io.Reader) (*zsyncControl, error) {
+	reader := bufio.NewReader(in)
+	control := &zsyncControl{rsumLen: 4, checksumLen: 16}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("Invalid zsync control file: %v", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid zsync header line '%s'", line)
+		}
+		key, value := parts[0], strings.TrimSpace(parts[1])
+		switch key {
+		case "Blocksize":
+			control.blockSize, err = strconv.Atoi(value)
+		case "Length":
+			control.length, err = strconv.ParseInt(value, 10, 64)
+		case "Hash-Lengths":
+			var seqMatches int
+			_, err = fmt.Sscanf(value, "%d,%d,%d", &seqMatches, &control.rsumLen, &control.checksumLen)
+		case "URL":
+			control.url = value
+		case "SHA-1":
+			control.sha1 = strings.ToLower(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid zsync header line '%s': %v", line, err)
+		}
+	}
+
+	if control.blockSize <= 0 || control.length < 0 {
+		return nil, fmt.Errorf("Invalid zsync control file: block size %d, length %d", control.blockSize, control.length)
+	}
+	if control.rsumLen < 1 || control.rsumLen > 4 || control.checksumLen < 1 || control.checksumLen > 16 {
+		return nil, fmt.Errorf("Invalid zsync hash lengths %d,%d", control.rsumLen, control.checksumLen)
+	}
+
+	count := int((control.length + int64(control.blockSize) - 1) / int64(control.blockSize))
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	entryLen := control.rsumLen + control.checksumLen
+	if len(data) < count*entryLen {
+		return nil, fmt.Errorf("Invalid zsync control file: expected %d block checksums", count)
+	}
+
+	control.blocks = make([]zsyncBlock, count)
+	rsumBytes := make([]byte, 4)
+	for i := range control.blocks {
+		entry := data[i*entryLen : (i+1)*entryLen]
+		// the rsum is stored as the last rsumLen bytes of its big endian representation
+		copy(rsumBytes[4-control.rsumLen:], entry[:control.rsumLen])
+		control.blocks[i] = zsyncBlock{
+			rsum:     binary.BigEndian.Uint32(rsumBytes),
+			checksum: entry[control.rsumLen:],
+		}
+	}
+	return control, nil
+}
+
+// resolveLocation resolves the possibly relative reference of a zsync control file against its location
+func resolveLocation(controlLocation string, reference string) (string, error) {
+	if reference == "" {
+		return strings.TrimSuffix(controlLocation, ZsyncFileExtension), nil
+	}
+	base, err := url.Parse(controlLocation)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(reference)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBlockSize = 256
+
+func TestDeltaReusesSeedBlocks(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-zsync-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	seed := make([]byte, 64*testBlockSize)
+	rand.New(rand.NewSource(42)).Read(seed)
+	// the new version shifts the content by an inserted prefix and changes a few bytes in the middle
+	target := append([]byte("new version"), seed...)
+	copy(target[20*testBlockSize:], []byte("changed content"))
+
+	var requested int64
+	server := newZsyncServer(target, "", &requested)
+	defer server.Close()
+
+	seedPath := filepath.Join(testDir, "seed.iso")
+	assert.NoError(t, ioutil.WriteFile(seedPath, seed, 0644))
+	path := filepath.Join(testDir, "minishift.iso")
+
+	assert.NoError(t, Delta(server.URL+"/minishift.iso", seedPath, path))
+
+	downloaded, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, target, downloaded)
+	assert.True(t, requested < int64(len(target)/4), "Expected only the changed blocks to be downloaded, got %d bytes", requested)
+	_, err = os.Stat(path + PartialFileExtension)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDeltaFailsWithoutControlFile(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-zsync-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	seedPath := filepath.Join(testDir, "seed.iso")
+	assert.NoError(t, ioutil.WriteFile(seedPath, content, 0644))
+	path := filepath.Join(testDir, "minishift.iso")
+
+	err = Delta(server.URL+"/minishift.iso", seedPath, path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDeltaFallsBackToMirrors(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-zsync-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	origin := httptest.NewServer(http.NotFoundHandler())
+	defer origin.Close()
+	var requested int64
+	mirror := newZsyncServer(content, "", &requested)
+	defer mirror.Close()
+	SetMirrors([]string{mirror.URL})
+	defer SetMirrors(nil)
+
+	seedPath := filepath.Join(testDir, "seed.iso")
+	assert.NoError(t, ioutil.WriteFile(seedPath, content, 0644))
+	path := filepath.Join(testDir, "minishift.iso")
+
+	location, err := DeltaFromMirrors(origin.URL+"/minishift.iso", seedPath, path)
+	assert.NoError(t, err)
+	assert.Equal(t, mirror.URL+"/minishift.iso", location)
+	downloaded, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+}
+
+func TestDeltaFailsOnChecksumMismatch(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-zsync-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	var requested int64
+	server := newZsyncServer(content, strings.Repeat("0", 40), &requested)
+	defer server.Close()
+
+	seedPath := filepath.Join(testDir, "seed.iso")
+	assert.NoError(t, ioutil.WriteFile(seedPath, content, 0644))
+	path := filepath.Join(testDir, "minishift.iso")
+
+	err = Delta(server.URL+"/minishift.iso", seedPath, path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "wrong SHA-1")
+	for _, p := range []string{path, path + PartialFileExtension} {
+		_, err = os.Stat(p)
+		assert.True(t, os.IsNotExist(err))
+	}
+}
+
+func TestMissingRangesMergesSmallGaps(t *testing.T) {
+	control := &zsyncControl{blockSize: 10, length: 195}
+	matched := make([]bool, 20)
+	for i := 2; i < 19; i++ {
+		matched[i] = true
+	}
+	matched[5] = false
+
+	assert.Equal(t, [][2]int64{{0, 59}, {190, 194}}, control.missingRanges(matched))
+}
+
+// newZsyncServer serves target at /minishift.iso and its zsync control file next to it. The bytes served for the
+// target are added up in requested.
+func newZsyncServer(target []byte, sha1Sum string, requested *int64) *httptest.Server {
+	control := zsyncControlFile(target, sha1Sum)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/minishift.iso" + ZsyncFileExtension:
+			w.Write(control)
+		case "/minishift.iso":
+			var start, end int64
+			if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err == nil {
+				*requested += end - start + 1
+			}
+			http.ServeContent(w, r, "minishift.iso", time.Time{}, bytes.NewReader(target))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// zsyncControlFile creates a zsync control file for target as zsyncmake does
+func zsyncControlFile(target []byte, sha1Sum string) []byte {
+	if sha1Sum == "" {
+		sha1Sum = fmt.Sprintf("%x", sha1.Sum(target))
+	}
+
+	var control bytes.Buffer
+	fmt.Fprintf(&control, "zsync: 0.6.2\nFilename: minishift.iso\nBlocksize: %d\nLength: %d\nHash-Lengths: 1,3,8\nURL: minishift.iso\nSHA-1: %s\n\n",
+		testBlockSize, len(target), sha1Sum)
+
+	for offset := 0; offset < len(target); offset += testBlockSize {
+		block := make([]byte, testBlockSize)
+		copy(block, target[offset:])
+		a, b := rsum(block)
+		rsumBytes := make([]byte, 4)
+		binary.BigEndian.PutUint32(rsumBytes, uint32(a)<<16|uint32(b))
+		checksum := md4Sum(block)
+		control.Write(rsumBytes[1:])
+		control.Write(checksum[:8])
+	}
+	return control.Bytes()
+}
@@ -25,13 +25,11 @@ import (
 	"os"
 	"strings"
 	"sync"
-	"time"
 
-	"github.com/golang/glog"
 	"github.com/google/go-github/github"
+	"github.com/minishift/minishift/pkg/util/logging"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
-	"gopkg.in/cheggaaa/pb.v1"
 
 	"crypto/sha256"
 	"fmt"
@@ -40,9 +38,12 @@ import (
 	"regexp"
 
 	"github.com/minishift/minishift/pkg/util/archive"
+	"github.com/minishift/minishift/pkg/util/download"
 	minishiftos "github.com/minishift/minishift/pkg/util/os"
 )
 
+var logger = logging.For("github")
+
 type OpenShiftBinaryType string
 
 const (
@@ -133,62 +134,25 @@ func DownloadOpenShiftReleaseBinary(binaryType OpenShiftBinaryType, osType minis
 			binaryType.String(), version, strings.Title(osType.String())))
 	}
 
-	// Download the asset
-	var asset io.Reader
-	asset, url, err := client.Repositories.DownloadReleaseAsset(ctx, "openshift", "origin", assetID)
-	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("Cannot download OpenShift release asset %d", assetID))
-	}
-	if len(url) > 0 {
-		fmt.Println(fmt.Sprintf("-- Downloading OpenShift binary '%s' version '%s'", binaryType.String(), *release.TagName))
-		httpResp, err := http.Get(url)
-		if err != nil {
-			return errors.Wrap(err, "Cannot download OpenShift release asset.")
-		}
-		defer func() { _ = httpResp.Body.Close() }()
-
-		asset = httpResp.Body
-		if httpResp.ContentLength > 0 {
-			bar := pb.New64(httpResp.ContentLength).SetUnits(pb.U_BYTES)
-			bar.Start()
-			asset = bar.NewProxyReader(asset)
-			defer func() {
-				<-time.After(bar.RefreshRate)
-				fmt.Println()
-			}()
-		}
-	}
-
-	hasher := sha256.New()
-	asset = io.TeeReader(asset, hasher)
-
-	// Create target directory and file
-	tmpDir, err := ioutil.TempDir("", "minishift-asset-download-")
-	if err != nil {
-		return errors.Wrap(err, "Cannot create temporary download directory.")
+	// Create target directory
+	err = os.MkdirAll(outputPath, 0755)
+	if err != nil && !os.IsExist(err) {
+		return errors.Wrap(err, "Cannot create the target directory.")
 	}
-	defer os.RemoveAll(tmpDir)
 
-	// Create a tmp directory for the asset
-	assetTmpFile := filepath.Join(tmpDir, assetFilename)
-	out, err := os.Create(assetTmpFile)
-	defer out.Close()
-	if err != nil {
-		return errors.Wrapf(err, "Cannot create file '%s'", assetTmpFile)
+	// Download the asset into the target directory, so that the next attempt resumes an interrupted download
+	assetFile := filepath.Join(outputPath, assetFilename)
+	fmt.Println(fmt.Sprintf("-- Downloading OpenShift binary '%s' version '%s'", binaryType.String(), *release.TagName))
+	if _, err := download.FromMirrors(getAssetDownloadURL(assetID, release), assetFile); err != nil {
+		return errors.Wrap(err, "Cannot download OpenShift release asset.")
 	}
+	defer os.Remove(assetFile)
 
-	// Copy the asset and verify its hash
-	_, err = io.Copy(out, asset)
-	if err != nil {
-		return errors.Wrapf(err, "Unexpected error occurred while copying '%s' to '%s'", assetTmpFile, tmpDir)
-	}
-	err = out.Sync()
+	// Hash verification for download oc binary
+	hash, err := sha256Sum(assetFile)
 	if err != nil {
-		return errors.Wrapf(err, "Unexpected error occurred while copying '%s' to '%s'", assetTmpFile, tmpDir)
+		return errors.Wrapf(err, "Cannot compute the checksum of '%s'", assetFile)
 	}
-
-	// Hash verification for download oc binary
-	hash := hex.EncodeToString(hasher.Sum(nil))
 	downloadedHash, err := downloadHash(ctx, release, assetFilename)
 	if err != nil {
 		return errors.Wrap(err, "Failed to download hash")
@@ -201,15 +165,21 @@ func DownloadOpenShiftReleaseBinary(binaryType OpenShiftBinaryType, osType minis
 		return errors.Errorf("Failed to validate hash - expected: %s, actual: %s", hash, downloadedHash)
 	}
 
+	tmpDir, err := ioutil.TempDir("", "minishift-asset-download-")
+	if err != nil {
+		return errors.Wrap(err, "Cannot create temporary download directory.")
+	}
+	defer os.RemoveAll(tmpDir)
+
 	// Unpack the asset
 	binaryPath := ""
 	switch {
-	case strings.HasSuffix(assetTmpFile, TAR):
+	case strings.HasSuffix(assetFilename, TAR):
 		// unzip
-		tarFile := assetTmpFile[:len(assetTmpFile)-3]
-		err = archive.Ungzip(assetTmpFile, tarFile)
+		tarFile := filepath.Join(tmpDir, assetFilename[:len(assetFilename)-3])
+		err = archive.Ungzip(assetFile, tarFile)
 		if err != nil {
-			return errors.Wrapf(err, "Cannot ungzip '%s'", assetTmpFile)
+			return errors.Wrapf(err, "Cannot ungzip '%s'", assetFile)
 		}
 
 		// untar
@@ -227,11 +197,11 @@ func DownloadOpenShiftReleaseBinary(binaryType OpenShiftBinaryType, osType minis
 		}
 
 		binaryPath = filepath.Join(tmpDir, content[0])
-	case strings.HasSuffix(assetTmpFile, ZIP):
-		contentDir := assetTmpFile[:len(assetTmpFile)-4]
-		err = archive.Unzip(assetTmpFile, contentDir)
+	case strings.HasSuffix(assetFilename, ZIP):
+		contentDir := filepath.Join(tmpDir, assetFilename[:len(assetFilename)-4])
+		err = archive.Unzip(assetFile, contentDir)
 		if err != nil {
-			return errors.Wrapf(err, "Cannot unzip '%s'", assetTmpFile)
+			return errors.Wrapf(err, "Cannot unzip '%s'", assetFile)
 		}
 		binaryPath = contentDir
 	}
@@ -243,11 +213,6 @@ func DownloadOpenShiftReleaseBinary(binaryType OpenShiftBinaryType, osType minis
 	binaryPath = filepath.Join(binaryPath, binaryName)
 
 	// Copy the requested asset into its final destination
-	err = os.MkdirAll(outputPath, 0755)
-	if err != nil && !os.IsExist(err) {
-		return errors.Wrap(err, "Cannot create the target directory.")
-	}
-
 	finalBinaryPath := filepath.Join(outputPath, binaryName)
 	copy(binaryPath, finalBinaryPath)
 	if err != nil {
@@ -346,6 +311,30 @@ func downloadHash(ctx context.Context, release *github.RepositoryRelease, filena
 	return "", nil
 }
 
+// getAssetDownloadURL returns the public download URL of the release asset with the specified id
+func getAssetDownloadURL(assetID int64, release *github.RepositoryRelease) string {
+	for _, asset := range release.Assets {
+		if asset.GetID() == assetID {
+			return asset.GetBrowserDownloadURL()
+		}
+	}
+	return ""
+}
+
+func sha256Sum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
 func getOpenShiftChecksumAssetID(release *github.RepositoryRelease) int64 {
 	for _, asset := range release.Assets {
 		if *asset.Name == "CHECKSUM" {
@@ -356,7 +345,7 @@ func getOpenShiftChecksumAssetID(release *github.RepositoryRelease) int64 {
 }
 
 func copy(src, dest string) error {
-	glog.V(2).Infof("Copying '%s' to '%s'\n", src, dest)
+	logger.Debugf("Copying '%s' to '%s'", src, dest)
 	srcFile, err := os.Open(src)
 	defer srcFile.Close()
 	if err != nil {
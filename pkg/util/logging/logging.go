@@ -0,0 +1,319 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/minishift/minishift/pkg/util/os/atexit"
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+const (
+	// TextFormat writes one human readable line per entry
+	TextFormat = "text"
+	// JSONFormat writes one JSON document per line and entry
+	JSONFormat = "json"
+
+	// DefaultLevel is the level used for subsystems without explicitly configured level
+	DefaultLevel = WarnLevel
+
+	// recentCapacity is the number of entries kept in memory, independent of the configured levels
+	recentCapacity = 1000
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+}
+
+// Fields are key value pairs attached to a log entry.
+type Fields map[string]interface{}
+
+// Entry is a single log entry.
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Level     Level     `json:"level"`
+	Subsystem string    `json:"subsystem"`
+	Message   string    `json:"msg"`
+	Fields    Fields    `json:"fields,omitempty"`
+}
+
+// Logger writes the log entries of a subsystem.
+type Logger struct {
+	subsystem string
+	fields    Fields
+}
+
+var (
+	lock      sync.Mutex
+	out       io.Writer = os.Stderr
+	format              = TextFormat
+	defaultLv           = DefaultLevel
+	levels              = map[string]Level{}
+	recent              = make([]Entry, 0, recentCapacity)
+	next      int
+)
+
+// For returns the logger of the specified subsystem, for example 'hostfolder' or 'agent'.
+func For(subsystem string) *Logger {
+	return &Logger{subsystem: subsystem}
+}
+
+// WithFields returns a logger adding the specified fields to each of its entries.
+func (l *Logger) WithFields(fields Fields) *Logger {
+	merged := Fields{}
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return &Logger{subsystem: l.subsystem, fields: merged}
+}
+
+// IsDebug returns true if debug entries of the subsystem are written. It allows to skip expensive preparation of
+// debug output.
+func (l *Logger) IsDebug() bool {
+	return l.isEnabled(DebugLevel)
+}
+
+func (l *Logger) Debugf(msg string, args ...interface{}) {
+	l.log(DebugLevel, msg, args...)
+}
+
+func (l *Logger) Infof(msg string, args ...interface{}) {
+	l.log(InfoLevel, msg, args...)
+}
+
+func (l *Logger) Warnf(msg string, args ...interface{}) {
+	l.log(WarnLevel, msg, args...)
+}
+
+func (l *Logger) Errorf(msg string, args ...interface{}) {
+	l.log(ErrorLevel, msg, args...)
+}
+
+// Fatalf writes an error entry and exits the program via atexit, so that the exit handlers run. Like glog.Fatal it
+// is meant for background processes which cannot report errors otherwise.
+func (l *Logger) Fatalf(msg string, args ...interface{}) {
+	l.log(ErrorLevel, msg, args...)
+	atexit.Exit(255)
+}
+
+func (l *Logger) isEnabled(level Level) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return level >= levelOf(l.subsystem)
+}
+
+func (l *Logger) log(level Level, msg string, args ...interface{}) {
+	entry := Entry{
+		Time:      time.Now(),
+		Level:     level,
+		Subsystem: l.subsystem,
+		Message:   strings.TrimSuffix(fmt.Sprintf(msg, args...), "\n"),
+		Fields:    l.fields,
+	}
+
+	lock.Lock()
+	remember(entry)
+	enabled := level >= levelOf(l.subsystem)
+	if enabled {
+		write(out, entry, format)
+	}
+	lock.Unlock()
+
+	// the entries also go into the glog files of the logs directory, like glog.Info did, which are attached to bug
+	// reports. Debug entries only if enabled, like glog.V did.
+	if enabled || level >= InfoLevel {
+		glog.InfoDepth(2, formatText(entry))
+	}
+}
+
+// Configure sets the levels and the format of the log output. The level specification is a comma separated list of
+// a default level and levels per subsystem, for example 'warn,hostfolder=debug'.
+func Configure(levelSpec string, logFormat string) error {
+	newDefault, newLevels, err := ParseLevels(levelSpec)
+	if err != nil {
+		return err
+	}
+	if logFormat == "" {
+		logFormat = TextFormat
+	}
+	if logFormat != TextFormat && logFormat != JSONFormat {
+		return fmt.Errorf("Unsupported log format '%s'. Supported formats are '%s' and '%s'", logFormat, TextFormat, JSONFormat)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	defaultLv = newDefault
+	levels = newLevels
+	format = logFormat
+	return nil
+}
+
+// SetOutput sets the writer the log entries are written to. It defaults to stderr.
+func SetOutput(w io.Writer) {
+	lock.Lock()
+	defer lock.Unlock()
+	out = w
+}
+
+// ParseLevels parses a level specification as accepted by Configure.
+func ParseLevels(levelSpec string) (Level, map[string]Level, error) {
+	defaultLevel := DefaultLevel
+	subsystemLevels := map[string]Level{}
+	for _, part := range strings.Split(levelSpec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		subsystem := ""
+		if i := strings.Index(part, "="); i >= 0 {
+			subsystem, part = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+			if subsystem == "" {
+				return defaultLevel, nil, fmt.Errorf("Missing subsystem in log level '%s'", levelSpec)
+			}
+		}
+		level, err := ParseLevel(part)
+		if err != nil {
+			return defaultLevel, nil, err
+		}
+		if subsystem == "" {
+			defaultLevel = level
+		} else {
+			subsystemLevels[subsystem] = level
+		}
+	}
+	return defaultLevel, subsystemLevels, nil
+}
+
+// ParseLevel parses the name of a level.
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	if strings.EqualFold(name, "warning") {
+		return WarnLevel, nil
+	}
+	return DefaultLevel, fmt.Errorf("Unknown log level '%s'. Supported levels are 'debug', 'info', 'warn' and 'error'", name)
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level%d", int(l))
+}
+
+func (l Level) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// Recent returns the most recent log entries of all levels, oldest first.
+func Recent() []Entry {
+	lock.Lock()
+	defer lock.Unlock()
+	entries := make([]Entry, 0, len(recent))
+	entries = append(entries, recent[next:]...)
+	entries = append(entries, recent[:next]...)
+	return entries
+}
+
+// WriteRecent writes the most recent log entries of all levels in the specified format, for example to capture them
+// for a bug report.
+func WriteRecent(w io.Writer, logFormat string) error {
+	for _, entry := range Recent() {
+		if err := writeEntry(w, entry, logFormat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func levelOf(subsystem string) Level {
+	if level, ok := levels[subsystem]; ok {
+		return level
+	}
+	return defaultLv
+}
+
+// remember adds the entry to the ring buffer of recent entries.
+func remember(entry Entry) {
+	if len(recent) < recentCapacity {
+		recent = append(recent, entry)
+		return
+	}
+	recent[next] = entry
+	next = (next + 1) % recentCapacity
+}
+
+func write(w io.Writer, entry Entry, logFormat string) {
+	if err := writeEntry(w, entry, logFormat); err != nil {
+		fmt.Fprintln(os.Stderr, entry.Message)
+	}
+}
+
+func writeEntry(w io.Writer, entry Entry, logFormat string) error {
+	if logFormat == JSONFormat {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(line))
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%s %s\n", entry.Time.Format("2006-01-02 15:04:05"), formatText(entry))
+	return err
+}
+
+// formatText formats the entry as text without its time
+func formatText(entry Entry) string {
+	line := fmt.Sprintf("%-5s %s: %s", strings.ToUpper(entry.Level.String()), entry.Subsystem, entry.Message)
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		line += fmt.Sprintf(" %s=%v", key, entry.Fields[key])
+	}
+	return line
+}
@@ -0,0 +1,139 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Level_Spec_Sets_Default_And_Subsystem_Levels(t *testing.T) {
+	defaultLevel, subsystemLevels, err := ParseLevels("warn, hostfolder=debug,agent=ERROR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, WarnLevel, defaultLevel)
+	assert.Equal(t, map[string]Level{"hostfolder": DebugLevel, "agent": ErrorLevel}, subsystemLevels)
+}
+
+func Test_Invalid_Level_Spec_Returns_Error(t *testing.T) {
+	_, _, err := ParseLevels("verbose")
+	assert.EqualError(t, err, "Unknown log level 'verbose'. Supported levels are 'debug', 'info', 'warn' and 'error'")
+
+	_, _, err = ParseLevels("=debug")
+	assert.Error(t, err)
+
+	assert.Error(t, Configure("info", "xml"))
+}
+
+func Test_Entries_Are_Filtered_By_Subsystem_Level(t *testing.T) {
+	buf := setup(t, "warn,hostfolder=debug", TextFormat)
+
+	For("hostfolder").Debugf("mounting %s", "/Users")
+	For("agent").Infof("idle")
+	For("agent").Warnf("tunnel down")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "DEBUG hostfolder: mounting /Users")
+	assert.Contains(t, lines[1], "WARN  agent: tunnel down")
+	assert.True(t, For("hostfolder").IsDebug())
+	assert.False(t, For("agent").IsDebug())
+}
+
+func Test_JSON_Format_Writes_Structured_Entries(t *testing.T) {
+	buf := setup(t, "info", JSONFormat)
+
+	For("ipwatch").WithFields(Fields{"ip": "192.168.99.101"}).Infof("IP changed")
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "ipwatch", entry["subsystem"])
+	assert.Equal(t, "IP changed", entry["msg"])
+	assert.Equal(t, map[string]interface{}{"ip": "192.168.99.101"}, entry["fields"])
+}
+
+func Test_Recent_Entries_Include_Filtered_Entries(t *testing.T) {
+	buf := setup(t, "error", TextFormat)
+
+	for i := 0; i < recentCapacity+5; i++ {
+		For("test").Debugf("entry %d", i)
+	}
+
+	assert.Empty(t, buf.String())
+	entries := Recent()
+	assert.Len(t, entries, recentCapacity)
+	assert.Equal(t, "entry 5", entries[0].Message)
+	assert.Equal(t, fmt.Sprintf("entry %d", recentCapacity+4), entries[recentCapacity-1].Message)
+}
+
+func Test_Default_Level_Shows_Warnings(t *testing.T) {
+	buf := setup(t, "", TextFormat)
+
+	For("config").Infof("reading config")
+	For("config").Warnf("config file not found")
+
+	assert.NotContains(t, buf.String(), "reading config")
+	assert.Contains(t, buf.String(), "WARN  config: config file not found")
+}
+
+func Test_Support_Bundle_Contains_Log_Files_And_Recent_Entries(t *testing.T) {
+	setup(t, "error", TextFormat)
+	logDir, err := ioutil.TempDir("", "minishift-test-logging-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(logDir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(logDir, "agent-minishift.log"), []byte("agent started"), 0644))
+
+	For("test").Debugf("captured entry")
+
+	var bundle bytes.Buffer
+	assert.NoError(t, WriteSupportBundle(&bundle, logDir))
+
+	files := map[string]string{}
+	gzipReader, err := gzip.NewReader(&bundle)
+	assert.NoError(t, err)
+	archive := tar.NewReader(gzipReader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		content, _ := ioutil.ReadAll(archive)
+		files[header.Name] = string(content)
+	}
+	assert.Equal(t, "agent started", files["agent-minishift.log"])
+	assert.Contains(t, files[RecentEntriesFile], "DEBUG test: captured entry")
+}
+
+func setup(t *testing.T, levelSpec string, logFormat string) *bytes.Buffer {
+	assert.NoError(t, Configure(levelSpec, logFormat))
+	buf := new(bytes.Buffer)
+	SetOutput(buf)
+	return buf
+}
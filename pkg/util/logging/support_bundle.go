@@ -0,0 +1,86 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// RecentEntriesFile is the name of the file of a support bundle holding the recent entries of the current process
+const RecentEntriesFile = "recent.log"
+
+// WriteSupportBundle writes a gzipped tar archive with the files of the logs directory, that is the glog files, the
+// logs of the background processes and the recent entries of the last failed command, as well as the recent entries
+// of the current process. The archive can be attached to bug reports.
+func WriteSupportBundle(w io.Writer, logDir string) error {
+	glog.Flush()
+
+	gzipWriter := gzip.NewWriter(w)
+	archive := tar.NewWriter(gzipWriter)
+
+	var recentEntries bytes.Buffer
+	if err := WriteRecent(&recentEntries, TextFormat); err != nil {
+		return err
+	}
+	if err := addToBundle(archive, RecentEntriesFile, recentEntries.Bytes(), time.Now()); err != nil {
+		return err
+	}
+
+	err := filepath.Walk(logDir, func(file string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(logDir, file)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		return addToBundle(archive, filepath.ToSlash(rel), content, info.ModTime())
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+func addToBundle(archive *tar.Writer, name string, content []byte, modTime time.Time) error {
+	header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), ModTime: modTime}
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := archive.Write(content)
+	return err
+}
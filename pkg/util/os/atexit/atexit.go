@@ -43,7 +43,7 @@ func ExitWithMessage(code int, msg string) {
 	if code == 0 {
 		fmt.Fprintln(os.Stdout, msg)
 	} else {
-		fmt.Fprintln(os.Stderr, msg)
+		report(ReasonForExitCode(code), code, msg)
 	}
 	Exit(code)
 }
@@ -0,0 +1,129 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package atexit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Reason classifies the error the program exits with, allowing callers to react to distinct classes of failures.
+type Reason string
+
+const (
+	// GenericError is the reason for all errors which are not classified otherwise
+	GenericError Reason = "GenericError"
+	// DriverError indicates a failure of the hypervisor or the VM driver
+	DriverError Reason = "DriverError"
+	// NetworkError indicates a failure to reach the network from the host or the VM
+	NetworkError Reason = "NetworkError"
+	// DownloadError indicates a failure to download an artifact like the ISO, the oc binary or a container image
+	DownloadError Reason = "DownloadError"
+	// ClusterUpFailure indicates a failure to provision OpenShift in the VM
+	ClusterUpFailure Reason = "ClusterUpFailure"
+	// ConfigurationError indicates an invalid configuration or invalid command line options
+	ConfigurationError Reason = "ConfigurationError"
+)
+
+var exitCodes = map[Reason]int{
+	GenericError:       1,
+	DriverError:        10,
+	NetworkError:       11,
+	DownloadError:      12,
+	ClusterUpFailure:   13,
+	ConfigurationError: 14,
+}
+
+// jsonErrors determines whether errors are reported as JSON document on stdout
+var jsonErrors bool
+
+// lastMessage is the error message the program exits with, as reported by report
+var lastMessage string
+
+// ErrorReport is the JSON representation of the error the program exits with.
+type ErrorReport struct {
+	Message   string `json:"message"`
+	Reason    Reason `json:"reason"`
+	ExitCode  int    `json:"exitCode"`
+	Retryable bool   `json:"retryable"`
+}
+
+// ExitCode returns the process exit code for this reason.
+func (r Reason) ExitCode() int {
+	if code, ok := exitCodes[r]; ok {
+		return code
+	}
+	return exitCodes[GenericError]
+}
+
+// IsRetryable returns true for reasons which are typically caused by transient conditions, so that the failed
+// command can be retried as is.
+func (r Reason) IsRetryable() bool {
+	return r == NetworkError || r == DownloadError
+}
+
+// ReasonForExitCode returns the reason for the specified exit code. Unknown non zero exit codes map to GenericError.
+func ReasonForExitCode(code int) Reason {
+	for reason, reasonCode := range exitCodes {
+		if reasonCode == code {
+			return reason
+		}
+	}
+	return GenericError
+}
+
+// ReportErrorsAsJSON makes ExitWithMessage and ExitWithReason report errors as JSON document on stdout.
+// It is meant for commands producing JSON output.
+func ReportErrorsAsJSON() {
+	jsonErrors = true
+}
+
+// LastMessage returns the error message the program is exiting with. It allows exit handlers to record the cause
+// of a failure.
+func LastMessage() string {
+	return lastMessage
+}
+
+// ExitWithReason runs all registered exit handlers, prints the specified message and then exits the program with the
+// exit code of the specified reason.
+func ExitWithReason(reason Reason, msg string) {
+	report(reason, reason.ExitCode(), msg)
+	Exit(reason.ExitCode())
+}
+
+// report prints the message to stderr or, if enabled, the JSON error report to stdout.
+func report(reason Reason, code int, msg string) {
+	lastMessage = msg
+	if !jsonErrors {
+		fmt.Fprintln(os.Stderr, msg)
+		return
+	}
+
+	errorReport := ErrorReport{
+		Message:   msg,
+		Reason:    reason,
+		ExitCode:  code,
+		Retryable: reason.IsRetryable(),
+	}
+	out, err := json.MarshalIndent(errorReport, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg)
+		return
+	}
+	fmt.Fprintln(os.Stdout, string(out))
+}
@@ -0,0 +1,48 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package atexit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Reasons_Have_Distinct_Exit_Codes(t *testing.T) {
+	codes := make(map[int]Reason)
+	for reason := range exitCodes {
+		code := reason.ExitCode()
+		_, exists := codes[code]
+		assert.False(t, exists, "Exit code %d is used more than once", code)
+		codes[code] = reason
+		assert.Equal(t, reason, ReasonForExitCode(code))
+	}
+}
+
+func Test_Unknown_Exit_Code_Maps_To_Generic_Error(t *testing.T) {
+	assert.Equal(t, GenericError, ReasonForExitCode(42))
+	assert.Equal(t, 1, Reason("foo").ExitCode())
+}
+
+func Test_Only_Transient_Reasons_Are_Retryable(t *testing.T) {
+	assert.True(t, NetworkError.IsRetryable())
+	assert.True(t, DownloadError.IsRetryable())
+	assert.False(t, DriverError.IsRetryable())
+	assert.False(t, ClusterUpFailure.IsRetryable())
+	assert.False(t, ConfigurationError.IsRetryable())
+	assert.False(t, GenericError.IsRetryable())
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// retryInterval is the interval in which a held lock is polled
+const retryInterval = 200 * time.Millisecond
+
+// ErrLocked is returned if the lock is held by another process
+var ErrLocked = errors.New("The lock is held by another process")
+
+// FileLock is an advisory lock shared between processes, backed by a lock file. The operating system releases
+// the lock once the holding process exits, so that a crashed process never leaves a stale lock behind.
+type FileLock struct {
+	file *os.File
+}
+
+// TryAcquire acquires the lock backed by the lock file at path without waiting. ErrLocked is returned if the lock
+// is held by another process. The directory of the lock file is created if needed.
+func TryAcquire(path string) (*FileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &FileLock{file: file}, nil
+}
+
+// Acquire acquires the lock backed by the lock file at path, waiting at most for the specified timeout.
+// ErrLocked is returned if the lock is still held by another process once the timeout passed.
+func Acquire(path string, timeout time.Duration) (*FileLock, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		lock, err := TryAcquire(path)
+		if err != ErrLocked || !time.Now().Before(deadline) {
+			return lock, err
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+// Wait acquires the lock like Acquire does. If the lock is held by another process, the message is printed once
+// before waiting for the other process to release it.
+func Wait(path string, timeout time.Duration, message string) (*FileLock, error) {
+	lock, err := TryAcquire(path)
+	if err != ErrLocked {
+		return lock, err
+	}
+
+	fmt.Println(message)
+	return Acquire(path, timeout)
+}
+
+// Release releases the lock. Releasing a nil lock is a no-op, which simplifies deferred releases.
+func (l *FileLock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
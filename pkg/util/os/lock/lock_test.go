@@ -0,0 +1,72 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Lock_Is_Exclusive(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-lock-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	path := filepath.Join(testDir, "locks", "profile.lock")
+	lock, err := TryAcquire(path)
+	assert.NoError(t, err)
+
+	_, err = TryAcquire(path)
+	assert.Equal(t, ErrLocked, err)
+
+	_, err = Acquire(path, 500*time.Millisecond)
+	assert.Equal(t, ErrLocked, err, "The lock should not be acquired before the timeout")
+
+	assert.NoError(t, lock.Release())
+	other, err := TryAcquire(path)
+	assert.NoError(t, err, "The lock should be available once released")
+	assert.NoError(t, other.Release())
+}
+
+func Test_Acquire_Waits_For_Release(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "minishift-test-lock-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	path := filepath.Join(testDir, "cache.lock")
+	lock, err := TryAcquire(path)
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		lock.Release()
+	}()
+
+	other, err := Acquire(path, 5*time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, other.Release())
+}
+
+func Test_Releasing_Nil_Lock(t *testing.T) {
+	var lock *FileLock
+	assert.NoError(t, lock.Release())
+}
@@ -0,0 +1,81 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+
+	minishiftos "github.com/minishift/minishift/pkg/util/os"
+)
+
+// StartMinishiftDaemon starts the Minishift binary with the specified arguments as background process, which does not
+// inherit any file handles, and returns its pid.
+func StartMinishiftDaemon(args ...string) (int, error) {
+	executable, err := minishiftos.CurrentExecutable()
+	if err != nil {
+		return 0, err
+	}
+
+	daemonCmd := exec.Command(executable, args...)
+	// don't inherit any file handles
+	daemonCmd.Stderr = nil
+	daemonCmd.Stdin = nil
+	daemonCmd.Stdout = nil
+	daemonCmd.SysProcAttr = SysProcForBackgroundProcess()
+	daemonCmd.Env = EnvForBackgroundProcess()
+
+	if err := daemonCmd.Start(); err != nil {
+		return 0, err
+	}
+	return daemonCmd.Process.Pid, nil
+}
+
+// IsRunning returns true if the process with the specified pid is running.
+func IsRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	// for Windows FindProcess is enough
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	// for non Windows we need to send a signal to get more information
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// Kill kills the process with the specified pid, if it is running.
+func Kill(pid int) error {
+	if !IsRunning(pid) {
+		return nil
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Kill()
+}
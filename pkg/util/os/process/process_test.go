@@ -0,0 +1,43 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRunning(t *testing.T) {
+	assert.True(t, IsRunning(os.Getpid()))
+	assert.False(t, IsRunning(0))
+	assert.False(t, IsRunning(-1))
+}
+
+func TestKill(t *testing.T) {
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Unable to start a process: %v", err)
+	}
+
+	assert.NoError(t, Kill(cmd.Process.Pid))
+	cmd.Wait()
+	assert.False(t, IsRunning(cmd.Process.Pid))
+	assert.NoError(t, Kill(cmd.Process.Pid), "Killing a terminated process should be a no-op")
+}
@@ -0,0 +1,190 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secret encrypts configuration values such as passwords before they are written to the configuration files.
+// The encryption key is either a random key kept in the keychain of the OS or a key derived from the passphrase in
+// the MINISHIFT_SECRETS_PASSPHRASE environment variable.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// PassphraseEnv is the environment variable holding the passphrase to derive the encryption key from
+	PassphraseEnv = "MINISHIFT_SECRETS_PASSPHRASE"
+
+	// encryptedPrefix marks encrypted values. It is followed by the key source and the encrypted value.
+	encryptedPrefix  = "encrypted:"
+	keychainSource   = "keychain"
+	passphraseSource = "passphrase"
+
+	keychainService = "minishift-secrets"
+	keychainUser    = "config-encryption-key"
+
+	keyLength            = 32
+	saltLength           = 16
+	kdfIterations        = 100000
+	redactedValue        = "<redacted>"
+	encryptedDisplay     = "<encrypted>"
+	undecryptableDisplay = "<undecryptable>"
+)
+
+// IsEncrypted returns true if the value has been encrypted by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// Encrypt encrypts the value using AES-GCM. If MINISHIFT_SECRETS_PASSPHRASE is set, the key is derived from the
+// passphrase. Otherwise a random key is created on first use and kept in the keychain of the OS.
+func Encrypt(value string) (string, error) {
+	var key []byte
+	var source string
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		salt := make([]byte, saltLength)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return "", err
+		}
+		key = deriveKey(passphrase, salt)
+		source = passphraseSource + ":" + base64.RawURLEncoding.EncodeToString(salt)
+	} else {
+		var err error
+		if key, err = keychainKey(true); err != nil {
+			return "", err
+		}
+		source = keychainSource
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ciphertext := gcm.Seal(nonce, nonce, []byte(value), nil)
+
+	return fmt.Sprintf("%s%s:%s", encryptedPrefix, source, base64.RawURLEncoding.EncodeToString(ciphertext)), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt. Values which are not encrypted are returned unchanged.
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, encryptedPrefix), ":")
+	var key []byte
+	switch {
+	case len(parts) == 2 && parts[0] == keychainSource:
+		var err error
+		if key, err = keychainKey(false); err != nil {
+			return "", err
+		}
+	case len(parts) == 3 && parts[0] == passphraseSource:
+		passphrase, ok := os.LookupEnv(PassphraseEnv)
+		if !ok {
+			return "", fmt.Errorf("The value is encrypted using a passphrase. Set %s to decrypt it", PassphraseEnv)
+		}
+		salt, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return "", errors.New("Invalid salt of encrypted value")
+		}
+		key = deriveKey(passphrase, salt)
+	default:
+		return "", errors.New("Unknown format of encrypted value")
+	}
+
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[len(parts)-1])
+	if err != nil {
+		return "", errors.New("Invalid encoding of encrypted value")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return "", errors.New("Encrypted value is too short")
+	}
+	plaintext, err := gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("Unable to decrypt the value. The encryption key or passphrase does not match")
+	}
+	return string(plaintext), nil
+}
+
+// Redact returns the representation of a secret value for display, which never contains the secret itself.
+// Encrypted values which cannot be decrypted, for example because the passphrase is not set, are marked as such.
+func Redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	if IsEncrypted(value) {
+		if _, err := Decrypt(value); err != nil {
+			return undecryptableDisplay
+		}
+		return encryptedDisplay
+	}
+	return redactedValue
+}
+
+// keychainKey returns the encryption key kept in the keychain of the OS. If create is true, a missing key is created.
+func keychainKey(create bool) ([]byte, error) {
+	encoded, err := keyring.Get(keychainService, keychainUser)
+	if err == keyring.ErrNotFound && create {
+		key := make([]byte, keyLength)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, err
+		}
+		encoded = base64.StdEncoding.EncodeToString(key)
+		err = keyring.Set(keychainService, keychainUser, encoded)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to access the encryption key in the keychain of the OS: %v. Set %s to use a passphrase instead", err, PassphraseEnv)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != keyLength {
+		return nil, errors.New("Invalid encryption key in the keychain of the OS")
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives the encryption key from the passphrase using PBKDF2 with HMAC-SHA256
+func deriveKey(passphrase string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, kdfIterations, keyLength, sha256.New)
+}
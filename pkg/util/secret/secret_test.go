@@ -0,0 +1,84 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+)
+
+func TestEncryptUsingKeychain(t *testing.T) {
+	keyring.MockInit()
+	os.Unsetenv(PassphraseEnv)
+
+	encrypted, err := Encrypt("s3cr3t")
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "s3cr3t")
+
+	otherEncrypted, err := Encrypt("s3cr3t")
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, otherEncrypted, "Each encryption should use a new nonce")
+
+	decrypted, err := Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", decrypted)
+}
+
+func TestEncryptUsingPassphrase(t *testing.T) {
+	os.Setenv(PassphraseEnv, "correct horse")
+	defer os.Unsetenv(PassphraseEnv)
+
+	encrypted, err := Encrypt("s3cr3t")
+	assert.NoError(t, err)
+
+	decrypted, err := Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", decrypted)
+
+	os.Setenv(PassphraseEnv, "wrong horse")
+	_, err = Decrypt(encrypted)
+	assert.Error(t, err)
+
+	os.Unsetenv(PassphraseEnv)
+	_, err = Decrypt(encrypted)
+	assert.Error(t, err)
+}
+
+func TestDecryptPlainValue(t *testing.T) {
+	decrypted, err := Decrypt("plain")
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", decrypted)
+
+	_, err = Decrypt("encrypted:unknown:abc")
+	assert.Error(t, err)
+}
+
+func TestRedact(t *testing.T) {
+	keyring.MockInit()
+	os.Unsetenv(PassphraseEnv)
+	encrypted, err := Encrypt("s3cr3t")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", Redact(""))
+	assert.Equal(t, "<redacted>", Redact("plain"))
+	assert.Equal(t, "<encrypted>", Redact(encrypted))
+	assert.Equal(t, "<undecryptable>", Redact("encrypted:keychain:abc"))
+}
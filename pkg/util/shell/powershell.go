@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+const (
+	powerShellModuleTmpl = `# Minishift PowerShell helpers. Load them into the current session with:
+#   & {{ .Executable }} oc-env --ps-module | Out-String | Invoke-Expression
+# or add them to your profile with:
+#   & {{ .Executable }} oc-env --ps-module | Out-File -Append -Encoding utf8 $PROFILE
+
+<#
+.SYNOPSIS
+Sets up the current PowerShell session for the Minishift VM.
+
+.DESCRIPTION
+Evaluates the output of the Minishift env commands, so that oc, docker and podman use the cluster and the container
+runtime of the Minishift VM. Use -Unset to restore the previous environment.
+
+.EXAMPLE
+Invoke-MinishiftEnv
+
+.EXAMPLE
+Invoke-MinishiftEnv -Tool docker -ProfileName dev
+
+.EXAMPLE
+Invoke-MinishiftEnv -Unset
+#>
+function Invoke-MinishiftEnv {
+    [CmdletBinding()]
+    param(
+        [ValidateSet('oc', 'docker', 'podman')]
+        [string[]] $Tool = @('oc', 'docker'),
+        [string] $ProfileName,
+        [switch] $NoProxy,
+        [switch] $Unset
+    )
+
+    foreach ($name in $Tool) {
+        $arguments = @("$name-env", '--shell', 'powershell')
+        if ($ProfileName) { $arguments += @('--profile', $ProfileName) }
+        if ($NoProxy -and $name -ne 'podman') { $arguments += '--no-proxy' }
+        if ($Unset) { $arguments += '--unset' }
+
+        $output = & {{ .Executable }} @arguments
+        if ($LASTEXITCODE -ne 0) {
+            throw "minishift $($arguments -join ' ') failed with exit code $LASTEXITCODE"
+        }
+        $output | Out-String | Invoke-Expression
+    }
+}
+`
+)
+
+// PowerShellModule returns PowerShell functions wrapping the env commands of the specified Minishift executable.
+func PowerShellModule(executable string) string {
+	tmpl := template.Must(template.New("powerShellModule").Parse(powerShellModuleTmpl))
+	var module bytes.Buffer
+	tmpl.Execute(&module, struct{ Executable string }{PowerShellQuote(executable)})
+	return module.String()
+}
+
+// PowerShellQuote quotes the string as single quoted PowerShell string, in which only single quotes need escaping.
+func PowerShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
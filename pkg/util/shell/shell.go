@@ -19,8 +19,8 @@ package shell
 import (
 	"errors"
 	"fmt"
-	"github.com/docker/machine/libmachine/shell"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 )
@@ -43,7 +43,37 @@ func GetShell(userShell string) (string, error) {
 		}
 		return userShell, nil
 	}
-	return shell.Detect()
+
+	if callingShell := detectCallingShell(); callingShell != "" {
+		return callingShell, nil
+	}
+	return detectDefaultShell()
+}
+
+// shellFromProcessName returns the supported shell the executable of a process belongs to, or an empty string if the
+// process is no supported shell.
+func shellFromProcessName(name string) string {
+	// login shells are reported with a leading dash
+	name = strings.TrimPrefix(filepath.Base(strings.TrimSpace(name)), "-")
+	name = strings.TrimSuffix(strings.ToLower(name), ".exe")
+	if name == "pwsh" {
+		name = "powershell"
+	}
+	if !isSupportedShell(name) {
+		return ""
+	}
+	return name
+}
+
+// firstShell returns the first supported shell out of the names of the ancestor processes, the parent process first.
+// Wrappers like winpty or make in between Minishift and the calling shell are skipped this way.
+func firstShell(ancestors []string) string {
+	for _, name := range ancestors {
+		if shell := shellFromProcessName(name); shell != "" {
+			return shell
+		}
+	}
+	return ""
 }
 
 func isSupportedShell(userShell string) bool {
@@ -82,7 +112,7 @@ func FindNoProxyFromEnv() (string, string) {
 
 func GenerateUsageHint(userShell, cmdLine string) string {
 	cmd := ""
-	comment := "#"
+	comment := CommentPrefix(userShell)
 
 	switch userShell {
 	case "fish":
@@ -91,10 +121,10 @@ func GenerateUsageHint(userShell, cmdLine string) string {
 		cmd = fmt.Sprintf("& %s | Invoke-Expression", cmdLine)
 	case "cmd":
 		cmd = fmt.Sprintf("\t@FOR /f \"tokens=*\" %%i IN ('%s') DO @call %%i", cmdLine)
-		comment = "REM"
 	case "emacs":
 		cmd = fmt.Sprintf("(with-temp-buffer (shell-command \"%s\" (current-buffer)) (eval-buffer))", cmdLine)
-		comment = ";;"
+	case "tcsh":
+		cmd = fmt.Sprintf("eval `%s`", cmdLine)
 	default:
 		cmd = fmt.Sprintf("eval $(%s)", cmdLine)
 	}
@@ -102,6 +132,18 @@ func GenerateUsageHint(userShell, cmdLine string) string {
 	return fmt.Sprintf("%s Run this command to configure your shell:\n%s %s\n", comment, comment, cmd)
 }
 
+// CommentPrefix returns the prefix starting a comment line in the specified shell.
+func CommentPrefix(userShell string) string {
+	switch userShell {
+	case "cmd":
+		return "REM"
+	case "emacs":
+		return ";;"
+	default:
+		return "#"
+	}
+}
+
 func GetPrefixSuffixDelimiterForSet(userShell string) (prefix, delimiter, suffix, pathSuffix string) {
 	switch userShell {
 	case "fish":
@@ -123,6 +165,13 @@ func GetPrefixSuffixDelimiterForSet(userShell string) (prefix, delimiter, suffix
 		prefix = "(setenv \""
 		delimiter = "\" \""
 		suffix = "\")\n"
+		// emacs cannot reference the current value in setenv, the value is expanded instead
+		pathSuffix = string(os.PathListSeparator) + os.Getenv("PATH") + suffix
+	case "tcsh":
+		prefix = "setenv "
+		delimiter = " \""
+		suffix = "\";\n"
+		pathSuffix = ":\"$PATH;\n"
 	default:
 		prefix = "export "
 		delimiter = "=\""
@@ -140,8 +189,8 @@ func GetPrefixSuffixDelimiterForUnSet(userShell string) (prefix, suffix, delimit
 		suffix = ";\n"
 		delimiter = ""
 	case "powershell":
-		prefix = `Remove-Item Env:\\`
-		suffix = "\n"
+		prefix = `Remove-Item Env:\`
+		suffix = " -ErrorAction SilentlyContinue\n"
 		delimiter = ""
 	case "cmd":
 		prefix = "SET "
@@ -151,6 +200,10 @@ func GetPrefixSuffixDelimiterForUnSet(userShell string) (prefix, suffix, delimit
 		prefix = "(setenv \""
 		suffix = ")\n"
 		delimiter = "\" nil"
+	case "tcsh":
+		prefix = "unsetenv "
+		suffix = ";\n"
+		delimiter = ""
 	default:
 		prefix = "unset "
 		suffix = "\n"
@@ -159,3 +212,20 @@ func GetPrefixSuffixDelimiterForUnSet(userShell string) (prefix, suffix, delimit
 
 	return
 }
+
+// RemoveFromPath returns the PATH environment variable value without the occurrences of the specified directory.
+// The entries are formatted for the specified shell, fish expects the entries of PATH as a list. An empty directory
+// removes nothing, in particular not the current directory '.'.
+func RemoveFromPath(userShell string, pathValue string, dir string) string {
+	var entries []string
+	for _, entry := range filepath.SplitList(pathValue) {
+		if dir == "" || filepath.Clean(entry) != filepath.Clean(dir) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if userShell == "fish" {
+		return strings.Join(entries, "\" \"")
+	}
+	return strings.Join(entries, string(os.PathListSeparator))
+}
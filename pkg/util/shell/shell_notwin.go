@@ -0,0 +1,46 @@
+// +build !windows
+
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/docker/machine/libmachine/shell"
+)
+
+// detectCallingShell returns the shell Minishift is called from, provided it is a supported shell.
+// This way the output matches the shell it gets evaluated in, even if that is not the login shell set in SHELL.
+func detectCallingShell() string {
+	out, err := exec.Command("ps", "-o", "comm=", "-p", strconv.Itoa(os.Getppid())).Output()
+	if err != nil {
+		return ""
+	}
+
+	name := shellFromProcessName(string(out))
+	if name == "cmd" {
+		return ""
+	}
+	return name
+}
+
+func detectDefaultShell() (string, error) {
+	return shell.Detect()
+}
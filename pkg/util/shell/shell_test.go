@@ -18,6 +18,7 @@ package shell
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -103,6 +104,9 @@ REM 	@FOR /f "tokens=*" %i IN ('foo') DO @call %i
 ;; (with-temp-buffer (shell-command "foo" (current-buffer)) (eval-buffer))
 `,
 		},
+		{
+			name: "tcsh", cmdLine: "foo", expectedHint: "# Run this command to configure your shell:\n# eval `foo`\n",
+		},
 	}
 
 	for _, tt := range testData {
@@ -111,3 +115,84 @@ REM 	@FOR /f "tokens=*" %i IN ('foo') DO @call %i
 
 	}
 }
+
+func TestPrefixSuffixDelimiterForSet(t *testing.T) {
+	testData := []prefixSuffixDelimiterTestCase{
+		{shellName: "bash", prefix: "export ", delimiter: "=\"", suffix: "\"\n"},
+		{shellName: "fish", prefix: "set -gx ", delimiter: " \"", suffix: "\";\n"},
+		{shellName: "powershell", prefix: "$Env:", delimiter: " = \"", suffix: "\"\n"},
+		{shellName: "tcsh", prefix: "setenv ", delimiter: " \"", suffix: "\";\n"},
+	}
+
+	for _, tt := range testData {
+		prefix, delimiter, suffix, _ := GetPrefixSuffixDelimiterForSet(tt.shellName)
+		assert.Equal(t, tt.prefix, prefix, tt.shellName)
+		assert.Equal(t, tt.delimiter, delimiter, tt.shellName)
+		assert.Equal(t, tt.suffix, suffix, tt.shellName)
+	}
+}
+
+func TestPrefixSuffixDelimiterForUnSet(t *testing.T) {
+	testData := []prefixSuffixDelimiterTestCase{
+		{shellName: "bash", prefix: "unset ", suffix: "\n"},
+		{shellName: "fish", prefix: "set -e ", suffix: ";\n"},
+		{shellName: "powershell", prefix: `Remove-Item Env:\`, suffix: " -ErrorAction SilentlyContinue\n"},
+		{shellName: "tcsh", prefix: "unsetenv ", suffix: ";\n"},
+		{shellName: "cmd", prefix: "SET ", suffix: "\n", delimiter: "="},
+	}
+
+	for _, tt := range testData {
+		prefix, suffix, delimiter := GetPrefixSuffixDelimiterForUnSet(tt.shellName)
+		assert.Equal(t, tt.prefix, prefix, tt.shellName)
+		assert.Equal(t, tt.suffix, suffix, tt.shellName)
+		assert.Equal(t, tt.delimiter, delimiter, tt.shellName)
+	}
+}
+
+func TestRemoveFromPath(t *testing.T) {
+	separator := string(os.PathListSeparator)
+	pathValue := strings.Join([]string{"/home/john/.minishift/cache/oc/v3.11.0/linux", "/usr/bin", "/home/john/.minishift/cache/oc/v3.11.0/linux/", "/bin"}, separator)
+
+	assert.Equal(t, "/usr/bin"+separator+"/bin", RemoveFromPath("bash", pathValue, "/home/john/.minishift/cache/oc/v3.11.0/linux"))
+	assert.Equal(t, `/usr/bin" "/bin`, RemoveFromPath("fish", pathValue, "/home/john/.minishift/cache/oc/v3.11.0/linux"))
+	assert.Equal(t, pathValue, RemoveFromPath("bash", pathValue, "/opt/bin"))
+
+	pathValue = strings.Join([]string{".", "/usr/bin"}, separator)
+	assert.Equal(t, pathValue, RemoveFromPath("bash", pathValue, ""))
+}
+
+func TestShellFromProcessName(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		expectedShell string
+	}{
+		{"powershell.exe", "powershell"},
+		{"pwsh.exe", "powershell"},
+		{"pwsh", "powershell"},
+		{"cmd.exe", "cmd"},
+		{"CMD.EXE", "cmd"},
+		{"bash.exe", "bash"},
+		{"-zsh\n", "zsh"},
+		{"/usr/bin/fish", "fish"},
+		{"winpty-agent.exe", ""},
+		{"minishift.exe", ""},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedShell, shellFromProcessName(testCase.name), "Unexpected shell for '%s'", testCase.name)
+	}
+}
+
+func TestFirstShellSkipsWrappers(t *testing.T) {
+	assert.Equal(t, "bash", firstShell([]string{"winpty.exe", "bash.exe", "mintty.exe"}))
+	assert.Equal(t, "powershell", firstShell([]string{"powershell.exe", "explorer.exe"}))
+	assert.Equal(t, "", firstShell([]string{"make.exe", "services.exe"}))
+}
+
+func TestPowerShellModule(t *testing.T) {
+	module := PowerShellModule(`C:\Program Files\minishift\minishift.exe`)
+
+	assert.Contains(t, module, "function Invoke-MinishiftEnv {")
+	assert.Contains(t, module, `$output = & 'C:\Program Files\minishift\minishift.exe' @arguments`)
+	assert.Equal(t, `'C:\Users\O''Brien\minishift.exe'`, PowerShellQuote(`C:\Users\O'Brien\minishift.exe`))
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// enough to get past wrappers like winpty or make, without picking up the shell which started the terminal
+	maxAncestors = 4
+)
+
+// detectCallingShell returns the shell Minishift is called from, provided it is a supported shell. The process
+// ancestry is inspected before SHELL, since SHELL is often set globally by Git for Windows or Cygwin and then
+// leaks into PowerShell and cmd sessions.
+func detectCallingShell() string {
+	return firstShell(ancestorNames(os.Getpid(), maxAncestors))
+}
+
+// detectDefaultShell falls back to SHELL, as set in Git Bash or Cygwin, and to cmd otherwise.
+func detectDefaultShell() (string, error) {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return strings.TrimSuffix(filepath.Base(shell), ".exe"), nil
+	}
+	return "cmd", nil
+}
+
+// ancestorNames returns the executable names of the ancestors of the process, the parent process first.
+func ancestorNames(pid int, max int) []string {
+	snapshot, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	type processEntry struct {
+		name string
+		ppid int
+	}
+	processes := make(map[int]processEntry)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = syscall.Process32First(snapshot, &entry); err == nil; err = syscall.Process32Next(snapshot, &entry) {
+		processes[int(entry.ProcessID)] = processEntry{
+			name: syscall.UTF16ToString(entry.ExeFile[:]),
+			ppid: int(entry.ParentProcessID),
+		}
+	}
+
+	var names []string
+	// process IDs are reused on Windows, so the parent ID of an orphaned process can point to one of its children
+	visited := map[int]bool{pid: true}
+	current, ok := processes[pid]
+	for ok && len(names) < max && !visited[current.ppid] {
+		visited[current.ppid] = true
+		current, ok = processes[current.ppid]
+		if ok {
+			names = append(names, current.name)
+		}
+	}
+	return names
+}
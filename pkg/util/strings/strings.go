@@ -131,3 +131,76 @@ func ParseLines(stdout string) []string {
 
 	return resp
 }
+
+// ClosestMatch returns the candidate which is most likely meant by value, ignoring case. A candidate matches if
+// value is a prefix of it or if the edit distance is small relative to the length of the candidate. If no candidate
+// matches, an empty string is returned.
+func ClosestMatch(value string, candidates []string) string {
+	value = strings.ToLower(value)
+	if value == "" {
+		return ""
+	}
+
+	closest := ""
+	closestDistance := -1
+	for _, candidate := range candidates {
+		lowerCandidate := strings.ToLower(candidate)
+		distance := editDistance(value, lowerCandidate)
+		if strings.HasPrefix(lowerCandidate, value) {
+			distance = 0
+		}
+		if distance > maxTypoDistance(lowerCandidate) {
+			continue
+		}
+		if closestDistance == -1 || distance < closestDistance {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+	return closest
+}
+
+func maxTypoDistance(s string) int {
+	if len(s) < 6 {
+		return 1
+	}
+	return len(s) / 3
+}
+
+// editDistance returns the Levenshtein distance of a and b, counting the transposition of two adjacent characters
+// as a single edit.
+func editDistance(a string, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = minOf(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = minOf(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func minOf(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
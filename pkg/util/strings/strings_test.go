@@ -144,3 +144,25 @@ func TestSplitAndTrim(t *testing.T) {
 		assert.EqualValues(t, testCase.expectedSlice, actualSlice)
 	}
 }
+
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"virtualbox", "kvm", "generic", "xhyve"}
+	var testCases = []struct {
+		value    string
+		expected string
+	}{
+		{"virtualbx", "virtualbox"},
+		{"VirtualBox", "virtualbox"},
+		{"virtual", "virtualbox"},
+		{"kmv", "kvm"},
+		{"kvn", "kvm"},
+		{"genric", "generic"},
+		{"hyperv", ""},
+		{"foo", ""},
+		{"", ""},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, ClosestMatch(testCase.value, candidates), "Unexpected match for '%s'", testCase.value)
+	}
+}
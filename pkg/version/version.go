@@ -35,6 +35,9 @@ var (
 	// The default version of the CentOS ISO version
 	centOsIsoVersion = "0.0.0-unset"
 
+	// The default version of the Boot2Docker ISO version
+	b2dIsoVersion = "0.0.0-unset"
+
 	// The SHA-1 of the commit this binary is build off
 	commitSha = "sha-unset"
 )
@@ -43,6 +46,11 @@ func GetMinishiftVersion() string {
 	return minishiftVersion
 }
 
+// IsDevelopmentBuild returns true if the binary was built without a release version
+func IsDevelopmentBuild() bool {
+	return minishiftVersion == "0.0.0-unset"
+}
+
 func GetSemverVersion() (semver.Version, error) {
 	return semver.Make(strings.TrimPrefix(GetMinishiftVersion(), VersionPrefix))
 }
@@ -55,6 +63,10 @@ func GetCentOsIsoVersion() string {
 	return centOsIsoVersion
 }
 
+func GetB2dIsoVersion() string {
+	return b2dIsoVersion
+}
+
 func GetCommitSha() string {
 	return commitSha
 }
@@ -13,6 +13,12 @@ Feature: Addons command and its subcommands
       And stdout should match "htpasswd-identity-provider\s*: disabled\s*P\(0\)"
       And stdout should match "admissions-webhook\s*: disabled\s*P\(0\)"
       And stdout should match "redhat-registry-login\s*: disabled\s*P\(0\)"
+      And stdout should match "hostpath-provisioner\s*: disabled\s*P\(0\)"
+      And stdout should match "monitoring\s*: disabled\s*P\(0\)"
+      And stdout should match "logging\s*: disabled\s*P\(0\)"
+      And stdout should match "service-mesh\s*: disabled\s*P\(0\)"
+      And stdout should match "olm\s*: disabled\s*P\(0\)"
+      And stdout should match "jenkins\s*: disabled\s*P\(0\)"
 
   @minishift-only @quick
   Scenario: Verbose listing of add-ons installed by default
@@ -73,7 +79,7 @@ Feature: Addons command and its subcommands
      When executing "minishift addons remove anyuid" succeeds
      Then stdout should contain
       """
-      Unable to remove addon 'anyuid'. No anyuid.addon.remove file is found.
+      Unable to remove addon 'anyuid'. No Remove section or anyuid.addon.remove file is found.
       """
 
   @quick
@@ -81,7 +87,7 @@ Feature: Addons command and its subcommands
      When executing "minishift addons install --defaults" succeeds
      Then stdout should contain
       """
-      Default add-ons 'anyuid, admin-user, xpaas, registry-route, che, htpasswd-identity-provider, admissions-webhook, redhat-registry-login' installed
+      Default add-ons 'anyuid, admin-user, xpaas, registry-route, che, htpasswd-identity-provider, admissions-webhook, redhat-registry-login, hostpath-provisioner, monitoring, logging, service-mesh, olm, jenkins' installed
       """
      When executing "minishift addons list" succeeds
      Then stdout should contain "admin-user"
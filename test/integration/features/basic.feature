@@ -6,7 +6,7 @@ Feature: Basic
      When executing "minishift addons install --defaults" succeeds
      Then stdout should contain
       """
-      Default add-ons 'anyuid, admin-user, xpaas, registry-route, che, htpasswd-identity-provider, admissions-webhook, redhat-registry-login' installed
+      Default add-ons 'anyuid, admin-user, xpaas, registry-route, che, htpasswd-identity-provider, admissions-webhook, redhat-registry-login, hostpath-provisioner, monitoring, logging, service-mesh, olm, jenkins' installed
       """
 
   Scenario: User can enable the anyuid add-on
@@ -101,21 +101,21 @@ Feature: Basic
     Given Minishift has state "Running"
      Then executing "oc get clusterrolebindings" fails
 
-  Scenario: A 'minishift' context is created for 'oc' usage
-    After a successful Minishift start the user's current context is 'minishift'
+  Scenario: A 'minishift/minishift' context is created for 'oc' usage
+    After a successful Minishift start the user's current context is 'minishift/minishift'
     Given Minishift has state "Running"
      When executing "oc config current-context" succeeds
      Then stdout should contain
       """
-      minishift
+      minishift/minishift
       """
 
-  Scenario: User can switch the current 'oc' context and return to 'minishift' context
+  Scenario: User can switch the current 'oc' context and return to 'minishift/minishift' context
     Given executing "oc config set-context dummy" succeeds
       And executing "oc config use-context dummy" succeeds
      When executing "oc project -q"
      Then exitcode should equal "1"
-     When executing "oc config use-context minishift" succeeds
+     When executing "oc config use-context minishift/minishift" succeeds
       And executing "oc config current-context" succeeds
      Then stdout should contain
       """